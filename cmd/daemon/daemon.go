@@ -23,4 +23,5 @@ func init() {
 	DaemonCmd.AddCommand(subcommands.StopCmd)
 	DaemonCmd.AddCommand(subcommands.StatusCmd)
 	DaemonCmd.AddCommand(subcommands.RebuildCmd)
+	DaemonCmd.AddCommand(subcommands.PruneCmd)
 }
@@ -0,0 +1,73 @@
+package subcommands
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/leefowlercu/agentic-memorizer/internal/config"
+	"github.com/leefowlercu/agentic-memorizer/internal/daemonclient"
+)
+
+var pruneVerbose bool
+
+// PruneCmd deletes orphaned nodes from the knowledge graph.
+var PruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete orphaned nodes from the knowledge graph",
+	Long: "Delete orphaned nodes from the knowledge graph.\n\n" +
+		"This command triggers the daemon to delete chunks that no file links to, " +
+		"and chunk metadata and embeddings that no chunk links to. Such nodes are " +
+		"left behind when writes are interrupted. Chunks updated in the last few " +
+		"minutes are kept, since their file links may still be pending.",
+	Example: `  # Prune orphaned nodes
+  memorizer daemon prune
+
+  # Prune and show counts per node label
+  memorizer daemon prune --verbose`,
+	PreRunE: validatePrune,
+	RunE:    runPrune,
+}
+
+func init() {
+	PruneCmd.Flags().BoolVar(&pruneVerbose, "verbose", false, "Show deleted node counts per label")
+}
+
+func validatePrune(cmd *cobra.Command, args []string) error {
+	// All errors after this are runtime errors
+	cmd.SilenceUsage = true
+	return nil
+}
+
+func runPrune(cmd *cobra.Command, args []string) error {
+	out := cmd.OutOrStdout()
+	quiet := isQuiet(cmd)
+
+	client, err := daemonclient.NewFromConfig(config.Get(),
+		daemonclient.WithTimeout(daemonclient.RebuildTimeout),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to initialize daemon client; %w", err)
+	}
+
+	result, err := client.Prune(context.Background())
+	if err != nil {
+		return fmt.Errorf("prune failed; %w", err)
+	}
+
+	if quiet {
+		return nil
+	}
+	fmt.Fprintf(out, "Prune %s: %d orphaned nodes deleted\n", result.Status, result.Total)
+	if pruneVerbose {
+		for _, label := range slices.Sorted(maps.Keys(result.Deleted)) {
+			fmt.Fprintf(out, "  %s: %d\n", label, result.Deleted[label])
+		}
+		fmt.Fprintf(out, "  Duration: %s\n", result.Duration)
+	}
+
+	return nil
+}
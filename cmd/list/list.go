@@ -193,6 +193,9 @@ func printConfig(out io.Writer, cfg *registry.PathConfig) {
 	if len(cfg.IncludeFiles) > 0 {
 		fmt.Fprintf(out, "      Include Files: %s\n", strings.Join(cfg.IncludeFiles, ", "))
 	}
+	if len(cfg.IncludeHiddenGlobs) > 0 {
+		fmt.Fprintf(out, "      Include Hidden: %s\n", strings.Join(cfg.IncludeHiddenGlobs, ", "))
+	}
 
 	// Vision API
 	if cfg.UseVision != nil {
@@ -128,4 +128,5 @@ func registerAllProviders(registry *providers.Registry) {
 	registry.RegisterEmbeddings(embeddings.NewOpenAIEmbeddingsProvider())
 	registry.RegisterEmbeddings(embeddings.NewVoyageEmbeddingsProvider())
 	registry.RegisterEmbeddings(embeddings.NewGoogleEmbeddingsProvider())
+	registry.RegisterEmbeddings(embeddings.NewOllamaEmbeddingsProvider())
 }
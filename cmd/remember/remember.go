@@ -27,6 +27,7 @@ var (
 	rememberAddIncludeExt  []string
 	rememberAddIncludeDir  []string
 	rememberAddIncludeFile []string
+	rememberIncludeHidden  []string
 	rememberSkipHidden     bool
 	rememberUseVision      *bool
 )
@@ -57,6 +58,9 @@ var RememberCmd = &cobra.Command{
   # Remember with include overrides (include .env even though hidden)
   memorizer remember ~/config --add-include-file=.env,.envrc
 
+  # Remember while keeping CI workflows despite skipping hidden paths
+  memorizer remember ~/projects/myapp --add-include-hidden='.github/workflows/*.yml'
+
   # Remember without vision API processing for images
   memorizer remember ~/large-images --use-vision=false`,
 	Args:    cobra.ExactArgs(1),
@@ -92,6 +96,8 @@ func init() {
 	// Hidden file handling
 	RememberCmd.Flags().BoolVar(&rememberSkipHidden, "skip-hidden", true,
 		"Skip hidden files and directories")
+	RememberCmd.Flags().StringSliceVar(&rememberIncludeHidden, "add-include-hidden", nil,
+		"Add hidden path globs to include (overrides skip-hidden)")
 
 	// Vision API
 	RememberCmd.Flags().StringVar(&useVisionFlag, "use-vision", "",
@@ -224,6 +230,9 @@ func buildConfigPatch(cmd *cobra.Command) *registry.PathConfigPatch {
 	if cmd.Flags().Changed("add-include-file") {
 		patch.AddIncludeFiles = rememberAddIncludeFile
 	}
+	if cmd.Flags().Changed("add-include-hidden") {
+		patch.AddIncludeHiddenGlobs = rememberIncludeHidden
+	}
 
 	if patch.IsEmpty() {
 		return nil
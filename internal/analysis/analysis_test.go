@@ -287,8 +287,8 @@ func TestWorkerAnalyze_DegradationMetadataSkipsSemanticAndEmbeddings(t *testing.
 
 	queue := NewQueue(bus)
 	queue.queueCapacity = 1
-	queue.workChan = make(chan WorkItem, 1)
-	queue.workChan <- WorkItem{}
+	queue.work = newWorkQueue(1)
+	queue.work.push(WorkItem{})
 
 	worker := NewWorker(0, queue)
 	worker.SetRegistry(reg)
@@ -404,26 +404,74 @@ func TestDegradationMode(t *testing.T) {
 	bus := events.NewBus()
 	defer bus.Close()
 
-	queue := NewQueue(bus)
-
-	tests := []struct {
+	type point struct {
 		capacity float64
 		expected DegradationMode
+	}
+
+	tests := []struct {
+		name   string
+		opts   []QueueOption
+		points []point
 	}{
-		{0.0, DegradationFull},
-		{0.5, DegradationFull},
-		{0.79, DegradationFull},
-		{0.80, DegradationNoEmbed},
-		{0.90, DegradationNoEmbed},
-		{0.95, DegradationMetadata},
-		{1.0, DegradationMetadata},
+		{
+			name: "Defaults",
+			points: []point{
+				{0.0, DegradationFull},
+				{0.5, DegradationFull},
+				{0.79, DegradationFull},
+				{0.80, DegradationNoEmbed},
+				{0.90, DegradationNoEmbed},
+				{0.95, DegradationMetadata},
+				{1.0, DegradationMetadata},
+			},
+		},
+		{
+			name: "ShedEarly",
+			opts: []QueueOption{WithDegradationThresholds(0.5, 0.7)},
+			points: []point{
+				{0.49, DegradationFull},
+				{0.5, DegradationNoEmbed},
+				{0.69, DegradationNoEmbed},
+				{0.7, DegradationMetadata},
+			},
+		},
+		{
+			name: "ShedLate",
+			opts: []QueueOption{WithDegradationThresholds(0.97, 1.0)},
+			points: []point{
+				{0.95, DegradationFull},
+				{0.97, DegradationNoEmbed},
+				{0.99, DegradationNoEmbed},
+				{1.0, DegradationMetadata},
+			},
+		},
+		{
+			name: "InvalidKeepsDefaults",
+			opts: []QueueOption{
+				WithDegradationThresholds(0, 0.5),
+				WithDegradationThresholds(0.9, 0.8),
+				WithDegradationThresholds(0.5, 0.5),
+				WithDegradationThresholds(0.5, 1.5),
+			},
+			points: []point{
+				{0.79, DegradationFull},
+				{0.80, DegradationNoEmbed},
+				{0.95, DegradationMetadata},
+			},
+		},
 	}
 
 	for _, tt := range tests {
-		result := queue.getDegradationMode(tt.capacity)
-		if result != tt.expected {
-			t.Errorf("getDegradationMode(%v) = %v, want %v", tt.capacity, result, tt.expected)
-		}
+		t.Run(tt.name, func(t *testing.T) {
+			queue := NewQueue(bus, tt.opts...)
+			for _, p := range tt.points {
+				result := queue.getDegradationMode(p.capacity)
+				if result != p.expected {
+					t.Errorf("getDegradationMode(%v) = %v, want %v", p.capacity, result, p.expected)
+				}
+			}
+		})
 	}
 }
 
@@ -588,12 +636,22 @@ func (m *mockGraph) UpsertChunkWithMetadata(ctx context.Context, chunk *graph.Ch
 	m.chunks = append(m.chunks, chunk)
 	return nil
 }
+func (m *mockGraph) UpsertChunksBatch(ctx context.Context, chunks []*graph.ChunkNode, metas []*chunkers.ChunkMetadata) error {
+	m.chunks = append(m.chunks, chunks...)
+	return nil
+}
+func (m *mockGraph) CommitTransaction(ctx context.Context, tx *graph.Transaction) error {
+	return nil
+}
 func (m *mockGraph) UpsertChunkEmbedding(ctx context.Context, chunkID string, emb *graph.ChunkEmbeddingNode) error {
 	return nil
 }
 func (m *mockGraph) DeleteChunkEmbeddings(ctx context.Context, chunkID string, provider, model string) error {
 	return nil
 }
+func (m *mockGraph) DeleteFileEmbeddings(ctx context.Context, filePath string, provider, model string) error {
+	return nil
+}
 func (m *mockGraph) DeleteChunks(ctx context.Context, path string) error               { return nil }
 func (m *mockGraph) SetFileTags(ctx context.Context, path string, tags []string) error { return nil }
 func (m *mockGraph) SetFileTopics(ctx context.Context, path string, topics []graph.Topic) error {
@@ -602,18 +660,71 @@ func (m *mockGraph) SetFileTopics(ctx context.Context, path string, topics []gra
 func (m *mockGraph) SetFileEntities(ctx context.Context, path string, entities []graph.Entity) error {
 	return nil
 }
+func (m *mockGraph) SetFileRelations(ctx context.Context, path string, relations []graph.EntityRelation) error {
+	return nil
+}
 func (m *mockGraph) SetFileReferences(ctx context.Context, path string, refs []graph.Reference) error {
 	return nil
 }
+func (m *mockGraph) SetChunkSectionReferences(ctx context.Context, chunkID string, targetIDs []string, unmatchedAnchors []string) error {
+	return nil
+}
+
+func (m *mockGraph) LinkChunks(ctx context.Context, path string, chunkIDs []string) error {
+	return nil
+}
+
+func (m *mockGraph) GetAdjacentChunks(ctx context.Context, chunkID string, before, after int) ([]graph.ChunkNode, error) {
+	return nil, nil
+}
+func (m *mockGraph) PruneOrphans(ctx context.Context) (graph.PruneStats, error) {
+	return graph.PruneStats{}, nil
+}
+func (m *mockGraph) GetChunkByID(ctx context.Context, id string) (*graph.ChunkNode, *chunkers.ChunkMetadata, error) {
+	return nil, nil, nil
+}
+func (m *mockGraph) ListChunksForFile(ctx context.Context, filePath string) ([]graph.ChunkNode, error) {
+	return nil, nil
+}
+func (m *mockGraph) GetBacklinks(ctx context.Context, path string) ([]graph.FileNode, error) {
+	return nil, nil
+}
+func (m *mockGraph) GetReferences(ctx context.Context, path string) ([]graph.Reference, error) {
+	return nil, nil
+}
 func (m *mockGraph) Query(ctx context.Context, cypher string) (*graph.QueryResult, error) {
 	return nil, nil
 }
 func (m *mockGraph) HasEmbedding(ctx context.Context, contentHash string, version int) (bool, error) {
 	return false, nil
 }
+func (m *mockGraph) GetEmbedding(ctx context.Context, contentHash, provider, model string, version int) ([]float32, error) {
+	return nil, nil
+}
+func (m *mockGraph) CountFilesByIngestReason(ctx context.Context) (map[string]int, error) {
+	return map[string]int{}, nil
+}
+func (m *mockGraph) GetDirectoryTree(ctx context.Context, rootPath string, maxDepth int) (*graph.DirectoryTree, error) {
+	return nil, nil
+}
+func (m *mockGraph) TopTags(ctx context.Context, n int) ([]graph.TagNode, error) {
+	return nil, nil
+}
+func (m *mockGraph) TopTopics(ctx context.Context, n int) ([]graph.TopicNode, error) {
+	return nil, nil
+}
+func (m *mockGraph) TopEntities(ctx context.Context, n int, entityType string) ([]graph.EntityNode, error) {
+	return nil, nil
+}
+func (m *mockGraph) GetRelatedFiles(ctx context.Context, path string, k int) ([]graph.RelatedFile, error) {
+	return nil, nil
+}
 func (m *mockGraph) ExportSnapshot(ctx context.Context) (*graph.GraphSnapshot, error) {
 	return nil, nil
 }
+func (m *mockGraph) ExportSnapshotSince(ctx context.Context, since time.Time) (*graph.GraphSnapshot, error) {
+	return nil, nil
+}
 func (m *mockGraph) GetFileWithRelations(ctx context.Context, path string) (*graph.FileWithRelations, error) {
 	return nil, nil
 }
@@ -621,6 +732,10 @@ func (m *mockGraph) SearchSimilarChunks(ctx context.Context, embedding []float32
 	return nil, nil
 }
 
+func (m *mockGraph) SearchSimilarChunksFiltered(ctx context.Context, embedding []float32, k int, filter graph.ChunkFilter) ([]graph.ChunkSearchHit, error) {
+	return nil, nil
+}
+
 func TestBuildAnalyzedChunks(t *testing.T) {
 	t.Run("Empty", func(t *testing.T) {
 		result := BuildAnalyzedChunks(nil)
@@ -996,11 +1111,11 @@ func TestWorkerAnalyze_DegradationNoEmbed_ChunksPopulated(t *testing.T) {
 	// Using capacity 10, we need 8-9 items to hit this range.
 	queue := NewQueue(bus)
 	queue.queueCapacity = 10
-	queue.workChan = make(chan WorkItem, 10)
+	queue.work = newWorkQueue(10)
 
 	// Fill queue to 90% capacity (9 items) to trigger DegradationNoEmbed
 	for i := 0; i < 9; i++ {
-		queue.workChan <- WorkItem{FilePath: fmt.Sprintf("/fake/path%d", i)}
+		queue.work.push(WorkItem{FilePath: fmt.Sprintf("/fake/path%d", i)})
 	}
 
 	worker := NewWorker(0, queue)
@@ -1075,7 +1190,7 @@ func TestWorkerAnalyze_EmbeddingsFailure_ChunksStillPopulated(t *testing.T) {
 	// Use larger capacity to stay in DegradationFull mode (capacity < 0.80)
 	queue := NewQueue(bus)
 	queue.queueCapacity = 100
-	queue.workChan = make(chan WorkItem, 100)
+	queue.work = newWorkQueue(100)
 
 	worker := NewWorker(0, queue)
 	worker.SetRegistry(reg)
@@ -1144,7 +1259,7 @@ func TestWorkerAnalyze_NoEmbeddingsProvider_ChunksStillPopulated(t *testing.T) {
 	// Use larger capacity to stay in DegradationFull mode (capacity < 0.80)
 	queue := NewQueue(bus)
 	queue.queueCapacity = 100
-	queue.workChan = make(chan WorkItem, 100)
+	queue.work = newWorkQueue(100)
 
 	worker := NewWorker(0, queue)
 	worker.SetRegistry(reg)
@@ -1259,3 +1374,78 @@ func TestAnalyzedChunkContainsAllFields(t *testing.T) {
 		t.Errorf("Summary = %q, want %q", ac.Summary, "test summary")
 	}
 }
+
+func TestWorkerProcessItem_IngestReason(t *testing.T) {
+	bus := events.NewBus()
+	defer bus.Close()
+
+	ctx := context.Background()
+	reg, err := registry.Open(ctx, filepath.Join(t.TempDir(), "registry.db"))
+	if err != nil {
+		t.Fatalf("failed to open registry: %v", err)
+	}
+	defer reg.Close()
+
+	mockG := &mockGraphForPersistence{connected: true}
+	worker := NewWorker(0, NewQueue(bus))
+	worker.SetRegistry(reg)
+	worker.SetGraph(mockG)
+
+	filePath := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(filePath, []byte("first draft"), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+	if err := worker.processItem(ctx, WorkItem{FilePath: filePath, EventType: WorkItemNew}); err != nil {
+		t.Fatalf("processItem failed: %v", err)
+	}
+	if got := mockG.files[filePath].ChangeReason; got != string(ingest.ChangeNew) {
+		t.Errorf("ChangeReason = %q after first ingest, want %q", got, ingest.ChangeNew)
+	}
+
+	if err := os.WriteFile(filePath, []byte("second draft"), 0644); err != nil {
+		t.Fatalf("failed to rewrite test file: %v", err)
+	}
+	if err := worker.processItem(ctx, WorkItem{FilePath: filePath, EventType: WorkItemChanged}); err != nil {
+		t.Fatalf("processItem failed: %v", err)
+	}
+	if got := mockG.files[filePath].ChangeReason; got != string(ingest.ChangeModified) {
+		t.Errorf("ChangeReason = %q after content change, want %q", got, ingest.ChangeModified)
+	}
+
+	counts, err := mockG.CountFilesByIngestReason(ctx)
+	if err != nil {
+		t.Fatalf("CountFilesByIngestReason failed: %v", err)
+	}
+	if counts[string(ingest.ChangeModified)] != 1 || counts[string(ingest.ChangeNew)] != 0 {
+		t.Errorf("counts = %v, want one modified file", counts)
+	}
+}
+
+func TestChangeReason(t *testing.T) {
+	state := &registry.FileState{ContentHash: "abc", AnalysisVersion: "1.0.0", ChunkerVersion: chunkers.Version}
+
+	tests := []struct {
+		name      string
+		eventType WorkItemType
+		existing  *registry.FileState
+		hash      string
+		version   string
+		want      ingest.ChangeReason
+	}{
+		{"no prior state", WorkItemNew, nil, "abc", "", ingest.ChangeNew},
+		{"content changed", WorkItemChanged, state, "def", "", ingest.ChangeModified},
+		{"reanalyze", WorkItemReanalyze, state, "abc", "", ingest.ChangeForced},
+		{"rediscovered unchanged", WorkItemNew, state, "abc", "", ingest.ChangeForced},
+		{"older analysis version", WorkItemNew, state, "abc", "2.0.0", ingest.ChangeVersionBump},
+		{"older chunker version", WorkItemNew, &registry.FileState{ContentHash: "abc", AnalysisVersion: "1.0.0", ChunkerVersion: "0"}, "abc", "1.0.0", ingest.ChangeVersionBump},
+		{"unrecorded chunker version", WorkItemNew, &registry.FileState{ContentHash: "abc", AnalysisVersion: "1.0.0"}, "abc", "1.0.0", ingest.ChangeVersionBump},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := changeReason(tt.eventType, tt.existing, tt.hash, tt.version); got != tt.want {
+				t.Errorf("changeReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package analysis
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/leefowlercu/agentic-memorizer/internal/fsutil"
+)
+
+// FileCategory is a coarse file grouping used to order analysis.
+type FileCategory string
+
+const (
+	CategoryDocs   FileCategory = "docs"
+	CategoryConfig FileCategory = "config"
+	CategoryCode   FileCategory = "code"
+	CategoryData   FileCategory = "data"
+	CategoryOther  FileCategory = "other"
+)
+
+// categoryExtensions maps extensions to non-code categories. Anything not
+// listed here with a detected programming language is code.
+var categoryExtensions = map[string]FileCategory{
+	".md":         CategoryDocs,
+	".markdown":   CategoryDocs,
+	".rst":        CategoryDocs,
+	".adoc":       CategoryDocs,
+	".asciidoc":   CategoryDocs,
+	".tex":        CategoryDocs,
+	".txt":        CategoryDocs,
+	".org":        CategoryDocs,
+	".pdf":        CategoryDocs,
+	".docx":       CategoryDocs,
+	".odt":        CategoryDocs,
+	".rtf":        CategoryDocs,
+	".html":       CategoryDocs,
+	".htm":        CategoryDocs,
+	".yaml":       CategoryConfig,
+	".yml":        CategoryConfig,
+	".toml":       CategoryConfig,
+	".ini":        CategoryConfig,
+	".cfg":        CategoryConfig,
+	".conf":       CategoryConfig,
+	".env":        CategoryConfig,
+	".properties": CategoryConfig,
+	".hcl":        CategoryConfig,
+	".tf":         CategoryConfig,
+	".json":       CategoryData,
+	".jsonl":      CategoryData,
+	".ndjson":     CategoryData,
+	".csv":        CategoryData,
+	".tsv":        CategoryData,
+	".xml":        CategoryData,
+	".parquet":    CategoryData,
+	".log":        CategoryData,
+	".xlsx":       CategoryData,
+	".ods":        CategoryData,
+}
+
+// CategorizeFile returns the category of a file based on its path.
+func CategorizeFile(path string) FileCategory {
+	ext := strings.ToLower(filepath.Ext(path))
+	if category, ok := categoryExtensions[ext]; ok {
+		return category
+	}
+	if fsutil.DetectLanguage(path) != "" || isSourceMIME(fsutil.MIMEFromExtension(ext)) {
+		return CategoryCode
+	}
+	return CategoryOther
+}
+
+// isSourceMIME reports whether a MIME type identifies program source.
+func isSourceMIME(mimeType string) bool {
+	return strings.HasPrefix(mimeType, "text/x-") ||
+		strings.HasPrefix(mimeType, "text/javascript") ||
+		strings.HasPrefix(mimeType, "text/typescript")
+}
+
+// WithCategoryOrder prefers analyzing files in the given category order, most
+// preferred first (e.g., docs before code). Categories not listed are analyzed
+// last. This is a scheduling preference: it orders queued items but does not
+// hold back work that is already in progress.
+func WithCategoryOrder(order []FileCategory) QueueOption {
+	return func(q *Queue) {
+		if len(order) == 0 {
+			q.categoryPriority = nil
+			return
+		}
+		q.categoryPriority = make(map[FileCategory]int, len(order))
+		for i, category := range order {
+			if _, exists := q.categoryPriority[category]; !exists {
+				q.categoryPriority[category] = len(order) - i
+			}
+		}
+	}
+}
+
+// categoryPriorityFor returns the scheduling priority for a file under the
+// configured category order. Zero when no order is configured.
+func (q *Queue) categoryPriorityFor(path string) int {
+	if len(q.categoryPriority) == 0 {
+		return 0
+	}
+	return q.categoryPriority[CategorizeFile(path)]
+}
@@ -0,0 +1,119 @@
+package analysis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/leefowlercu/agentic-memorizer/internal/events"
+)
+
+func TestCategorizeFile(t *testing.T) {
+	tests := []struct {
+		path string
+		want FileCategory
+	}{
+		{"/repo/README.md", CategoryDocs},
+		{"/repo/docs/guide.rst", CategoryDocs},
+		{"/repo/config.yaml", CategoryConfig},
+		{"/repo/Cargo.toml", CategoryConfig},
+		{"/repo/main.go", CategoryCode},
+		{"/repo/include/util.h", CategoryCode},
+		{"/repo/fixtures/users.json", CategoryData},
+		{"/repo/export.CSV", CategoryData},
+		{"/repo/logo.png", CategoryOther},
+	}
+
+	for _, tt := range tests {
+		if got := CategorizeFile(tt.path); got != tt.want {
+			t.Errorf("CategorizeFile(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestQueueCategoryOrder(t *testing.T) {
+	paths := []string{
+		"/repo/main.go",
+		"/repo/fixtures/users.json",
+		"/repo/README.md",
+		"/repo/logo.png",
+		"/repo/config.yaml",
+		"/repo/lib/util.py",
+		"/repo/docs/guide.rst",
+	}
+
+	dequeueAll := func(t *testing.T, opts ...QueueOption) []string {
+		t.Helper()
+		bus := events.NewBus()
+		defer bus.Close()
+
+		queue := NewQueue(bus, opts...)
+		queue.ctx = context.Background()
+		queue.state = QueueStateRunning
+		queue.work = newWorkQueue(len(paths))
+
+		for _, path := range paths {
+			if err := queue.Enqueue(WorkItem{FilePath: path, EventType: WorkItemNew}); err != nil {
+				t.Fatalf("Enqueue(%q) failed: %v", path, err)
+			}
+		}
+
+		var order []string
+		for range paths {
+			<-queue.work.ready
+			item, ok := queue.work.pop()
+			if !ok {
+				t.Fatal("expected queued item")
+			}
+			order = append(order, item.FilePath)
+		}
+		return order
+	}
+
+	t.Run("DocsFirst", func(t *testing.T) {
+		got := dequeueAll(t, WithCategoryOrder([]FileCategory{CategoryDocs, CategoryConfig, CategoryCode, CategoryData}))
+		want := []string{
+			"/repo/README.md",
+			"/repo/docs/guide.rst",
+			"/repo/config.yaml",
+			"/repo/main.go",
+			"/repo/lib/util.py",
+			"/repo/fixtures/users.json",
+			"/repo/logo.png",
+		}
+		assertOrder(t, got, want)
+	})
+
+	t.Run("NoPreferenceKeepsDiscoveryOrder", func(t *testing.T) {
+		assertOrder(t, dequeueAll(t), paths)
+	})
+
+	t.Run("ExplicitPriorityWins", func(t *testing.T) {
+		bus := events.NewBus()
+		defer bus.Close()
+
+		queue := NewQueue(bus, WithCategoryOrder([]FileCategory{CategoryDocs, CategoryCode}))
+		queue.ctx = context.Background()
+		queue.state = QueueStateRunning
+		queue.work = newWorkQueue(2)
+
+		_ = queue.Enqueue(WorkItem{FilePath: "/repo/README.md"})
+		_ = queue.Enqueue(WorkItem{FilePath: "/repo/main.go", Priority: 10})
+
+		item, _ := queue.work.pop()
+		if item.FilePath != "/repo/main.go" {
+			t.Errorf("first dequeued = %q, want explicitly prioritized /repo/main.go", item.FilePath)
+		}
+	})
+}
+
+func assertOrder(t *testing.T, got, want []string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("dequeued %d items, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("dequeue order[%d] = %q, want %q (full order %v)", i, got[i], want[i], got)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/leefowlercu/agentic-memorizer/internal/registry"
+)
+
+// errNoRegistry is returned by dead-letter operations when the queue has no registry.
+var errNoRegistry = errors.New("no registry configured")
+
+// ListDeadLetters returns the work items that failed permanently, most
+// recently failed first.
+func (q *Queue) ListDeadLetters(ctx context.Context) ([]registry.DeadLetter, error) {
+	reg := q.deadLetterStore()
+	if reg == nil {
+		return nil, errNoRegistry
+	}
+
+	dls, err := reg.ListDeadLetters(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead letters; %w", err)
+	}
+	return dls, nil
+}
+
+// RequeueDeadLetter enqueues a dead-lettered path for a fresh round of
+// attempts and removes its dead letter. Returns registry.ErrPathNotFound if
+// the path is not dead-lettered.
+func (q *Queue) RequeueDeadLetter(ctx context.Context, path string) error {
+	reg := q.deadLetterStore()
+	if reg == nil {
+		return errNoRegistry
+	}
+
+	dl, err := reg.GetDeadLetter(ctx, path)
+	if err != nil {
+		return fmt.Errorf("failed to get dead letter; %w", err)
+	}
+
+	if err := q.Enqueue(WorkItem{
+		FilePath:  dl.FilePath,
+		FileSize:  dl.FileSize,
+		ModTime:   dl.ModTime,
+		EventType: WorkItemType(dl.EventType),
+	}); err != nil {
+		return fmt.Errorf("failed to requeue dead letter; %w", err)
+	}
+
+	if err := reg.DeleteDeadLetter(ctx, path); err != nil {
+		return fmt.Errorf("failed to delete requeued dead letter; %w", err)
+	}
+
+	q.logger.Info("requeued dead-lettered work item", "path", path, "previous_retries", dl.Retries)
+	return nil
+}
+
+// recordDeadLetter records a work item that failed permanently. Failures are
+// logged; the AnalysisFailed event is still published either way.
+func (q *Queue) recordDeadLetter(item WorkItem, cause error) {
+	reg := q.deadLetterStore()
+	if reg == nil {
+		return
+	}
+
+	dl := &registry.DeadLetter{
+		FilePath:  item.FilePath,
+		FileSize:  item.FileSize,
+		ModTime:   item.ModTime,
+		EventType: int(item.EventType),
+		Retries:   item.Retries,
+		LastError: cause.Error(),
+	}
+
+	// Not the worker context: the record must still be written while the queue stops
+	if err := reg.RecordDeadLetter(context.Background(), dl); err != nil {
+		q.logger.Warn("failed to record dead letter", "path", item.FilePath, "error", err)
+	}
+}
+
+// clearDeadLetter removes any dead letter for a path that has since been
+// analyzed successfully.
+func (q *Queue) clearDeadLetter(path string) {
+	reg := q.deadLetterStore()
+	if reg == nil {
+		return
+	}
+
+	if err := reg.DeleteDeadLetter(context.Background(), path); err != nil {
+		q.logger.Warn("failed to clear dead letter", "path", path, "error", err)
+	}
+}
+
+// deadLetterStore returns the registry dead letters are kept in, if any.
+func (q *Queue) deadLetterStore() registry.Registry {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return q.registry
+}
@@ -0,0 +1,120 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/leefowlercu/agentic-memorizer/internal/events"
+	"github.com/leefowlercu/agentic-memorizer/internal/registry"
+)
+
+func TestQueueDeadLettersPermanentFailures(t *testing.T) {
+	bus := events.NewBus()
+	defer bus.Close()
+
+	ctx := context.Background()
+	reg, err := registry.Open(ctx, filepath.Join(t.TempDir(), "registry.db"))
+	if err != nil {
+		t.Fatalf("failed to open registry: %v", err)
+	}
+	defer reg.Close()
+
+	queue := NewQueue(bus, WithWorkerCount(1), WithMaxRetries(2), WithRegistry(reg))
+	queue.retryDelay = time.Millisecond
+	queue.SetProviders(&mockSemanticProvider{available: true}, &mockEmbeddingsProvider{available: true, embedding: []float32{0.1, 0.2}})
+	if err := queue.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer queue.Stop(context.Background())
+
+	// The file does not exist yet, so every analysis attempt fails
+	filePath := filepath.Join(t.TempDir(), "missing.txt")
+
+	failed := make(chan struct{}, 1)
+	completed := make(chan struct{}, 1)
+	unsubFailed := bus.Subscribe(events.AnalysisFailed, func(e events.Event) {
+		if ae, ok := e.Payload.(*events.AnalysisEvent); ok && ae.Path == filePath {
+			failed <- struct{}{}
+		}
+	})
+	defer unsubFailed()
+	unsubComplete := bus.Subscribe(events.AnalysisComplete, func(e events.Event) {
+		if ae, ok := e.Payload.(*events.AnalysisEvent); ok && ae.Path == filePath {
+			completed <- struct{}{}
+		}
+	})
+	defer unsubComplete()
+
+	if err := queue.Enqueue(WorkItem{FilePath: filePath, FileSize: 42, EventType: WorkItemChanged}); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	select {
+	case <-failed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for permanent failure")
+	}
+
+	dls, err := queue.ListDeadLetters(ctx)
+	if err != nil {
+		t.Fatalf("ListDeadLetters failed: %v", err)
+	}
+	if len(dls) != 1 {
+		t.Fatalf("expected 1 dead letter, got %d", len(dls))
+	}
+	dl := dls[0]
+	if dl.FilePath != filePath || dl.FileSize != 42 || dl.EventType != int(WorkItemChanged) {
+		t.Errorf("unexpected dead letter %+v", dl)
+	}
+	if dl.Retries != 2 {
+		t.Errorf("Retries = %d, want 2", dl.Retries)
+	}
+	if dl.LastError == "" || dl.FailedAt.IsZero() {
+		t.Errorf("expected last error and failure time, got %+v", dl)
+	}
+
+	// Once the cause is fixed the path can be requeued and succeeds
+	if err := os.WriteFile(filePath, []byte("now present"), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+	if err := queue.RequeueDeadLetter(ctx, filePath); err != nil {
+		t.Fatalf("RequeueDeadLetter failed: %v", err)
+	}
+
+	select {
+	case <-completed:
+	case <-failed:
+		t.Fatal("requeued item failed again")
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for requeued item")
+	}
+
+	dls, err = queue.ListDeadLetters(ctx)
+	if err != nil {
+		t.Fatalf("ListDeadLetters failed: %v", err)
+	}
+	if len(dls) != 0 {
+		t.Errorf("expected no dead letters after requeue, got %d", len(dls))
+	}
+
+	if err := queue.RequeueDeadLetter(ctx, filePath); !errors.Is(err, registry.ErrPathNotFound) {
+		t.Errorf("RequeueDeadLetter(unknown) error = %v, want ErrPathNotFound", err)
+	}
+}
+
+func TestQueueDeadLettersRequireRegistry(t *testing.T) {
+	bus := events.NewBus()
+	defer bus.Close()
+
+	queue := NewQueue(bus)
+	if _, err := queue.ListDeadLetters(context.Background()); err == nil {
+		t.Error("expected ListDeadLetters to fail without a registry")
+	}
+	if err := queue.RequeueDeadLetter(context.Background(), "/repo/main.go"); err == nil {
+		t.Error("expected RequeueDeadLetter to fail without a registry")
+	}
+}
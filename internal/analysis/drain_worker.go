@@ -8,8 +8,10 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/leefowlercu/agentic-memorizer/internal/chunkers"
 	"github.com/leefowlercu/agentic-memorizer/internal/events"
 	"github.com/leefowlercu/agentic-memorizer/internal/graph"
+	"github.com/leefowlercu/agentic-memorizer/internal/registry"
 	"github.com/leefowlercu/agentic-memorizer/internal/storage"
 )
 
@@ -44,11 +46,12 @@ func DefaultDrainConfig() DrainConfig {
 
 // DrainWorker automatically drains the persistence queue when the graph becomes available.
 type DrainWorker struct {
-	queue  storage.DurablePersistenceQueue
-	graph  graph.Graph
-	bus    events.Bus
-	logger *slog.Logger
-	config DrainConfig
+	queue    storage.DurablePersistenceQueue
+	graph    graph.Graph
+	bus      events.Bus
+	registry registry.Registry
+	logger   *slog.Logger
+	config   DrainConfig
 
 	// draining prevents concurrent drain operations
 	draining atomic.Bool
@@ -71,6 +74,14 @@ func WithDrainConfig(cfg DrainConfig) DrainWorkerOption {
 	}
 }
 
+// WithDrainRegistry sets the registry that records the chunker version of
+// files whose queued results are persisted.
+func WithDrainRegistry(reg registry.Registry) DrainWorkerOption {
+	return func(w *DrainWorker) {
+		w.registry = reg
+	}
+}
+
 // WithDrainLogger sets the logger for the drain worker.
 func WithDrainLogger(logger *slog.Logger) DrainWorkerOption {
 	return func(w *DrainWorker) {
@@ -277,6 +288,12 @@ func (w *DrainWorker) persistItem(ctx context.Context, item *storage.QueuedResul
 		return fmt.Errorf("failed to persist to graph; %w", err)
 	}
 
+	if w.registry != nil {
+		if err := w.registry.UpdateChunkerState(ctx, result.FilePath, chunkers.Version); err != nil {
+			w.logger.Warn("failed to update chunker state", "path", result.FilePath, "error", err)
+		}
+	}
+
 	return nil
 }
 
@@ -194,12 +194,21 @@ func (g *drainMockGraph) DeleteDirectoriesUnderPath(ctx context.Context, path st
 func (g *drainMockGraph) UpsertChunkWithMetadata(ctx context.Context, chunk *graph.ChunkNode, meta *chunkers.ChunkMetadata) error {
 	return nil
 }
+func (g *drainMockGraph) UpsertChunksBatch(ctx context.Context, chunks []*graph.ChunkNode, metas []*chunkers.ChunkMetadata) error {
+	return nil
+}
+func (g *drainMockGraph) CommitTransaction(ctx context.Context, tx *graph.Transaction) error {
+	return nil
+}
 func (g *drainMockGraph) UpsertChunkEmbedding(ctx context.Context, chunkID string, emb *graph.ChunkEmbeddingNode) error {
 	return nil
 }
 func (g *drainMockGraph) DeleteChunkEmbeddings(ctx context.Context, chunkID, provider, model string) error {
 	return nil
 }
+func (g *drainMockGraph) DeleteFileEmbeddings(ctx context.Context, filePath string, provider, model string) error {
+	return nil
+}
 func (g *drainMockGraph) DeleteChunks(ctx context.Context, filePath string) error { return nil }
 func (g *drainMockGraph) SetFileTags(ctx context.Context, path string, tags []string) error {
 	return nil
@@ -210,18 +219,71 @@ func (g *drainMockGraph) SetFileTopics(ctx context.Context, path string, topics
 func (g *drainMockGraph) SetFileEntities(ctx context.Context, path string, entities []graph.Entity) error {
 	return nil
 }
+func (g *drainMockGraph) SetFileRelations(ctx context.Context, path string, relations []graph.EntityRelation) error {
+	return nil
+}
 func (g *drainMockGraph) SetFileReferences(ctx context.Context, path string, refs []graph.Reference) error {
 	return nil
 }
+func (g *drainMockGraph) SetChunkSectionReferences(ctx context.Context, chunkID string, targetIDs []string, unmatchedAnchors []string) error {
+	return nil
+}
+
+func (g *drainMockGraph) LinkChunks(ctx context.Context, path string, chunkIDs []string) error {
+	return nil
+}
+
+func (g *drainMockGraph) GetAdjacentChunks(ctx context.Context, chunkID string, before, after int) ([]graph.ChunkNode, error) {
+	return nil, nil
+}
+func (g *drainMockGraph) PruneOrphans(ctx context.Context) (graph.PruneStats, error) {
+	return graph.PruneStats{}, nil
+}
+func (g *drainMockGraph) GetChunkByID(ctx context.Context, id string) (*graph.ChunkNode, *chunkers.ChunkMetadata, error) {
+	return nil, nil, nil
+}
+func (g *drainMockGraph) ListChunksForFile(ctx context.Context, filePath string) ([]graph.ChunkNode, error) {
+	return nil, nil
+}
+func (g *drainMockGraph) GetBacklinks(ctx context.Context, path string) ([]graph.FileNode, error) {
+	return nil, nil
+}
+func (g *drainMockGraph) GetReferences(ctx context.Context, path string) ([]graph.Reference, error) {
+	return nil, nil
+}
 func (g *drainMockGraph) Query(ctx context.Context, cypher string) (*graph.QueryResult, error) {
 	return nil, nil
 }
 func (g *drainMockGraph) HasEmbedding(ctx context.Context, contentHash string, version int) (bool, error) {
 	return false, nil
 }
+func (g *drainMockGraph) GetEmbedding(ctx context.Context, contentHash, provider, model string, version int) ([]float32, error) {
+	return nil, nil
+}
+func (g *drainMockGraph) CountFilesByIngestReason(ctx context.Context) (map[string]int, error) {
+	return map[string]int{}, nil
+}
+func (g *drainMockGraph) GetDirectoryTree(ctx context.Context, rootPath string, maxDepth int) (*graph.DirectoryTree, error) {
+	return nil, nil
+}
+func (g *drainMockGraph) TopTags(ctx context.Context, n int) ([]graph.TagNode, error) {
+	return nil, nil
+}
+func (g *drainMockGraph) TopTopics(ctx context.Context, n int) ([]graph.TopicNode, error) {
+	return nil, nil
+}
+func (g *drainMockGraph) TopEntities(ctx context.Context, n int, entityType string) ([]graph.EntityNode, error) {
+	return nil, nil
+}
+func (g *drainMockGraph) GetRelatedFiles(ctx context.Context, path string, k int) ([]graph.RelatedFile, error) {
+	return nil, nil
+}
 func (g *drainMockGraph) ExportSnapshot(ctx context.Context) (*graph.GraphSnapshot, error) {
 	return nil, nil
 }
+func (g *drainMockGraph) ExportSnapshotSince(ctx context.Context, since time.Time) (*graph.GraphSnapshot, error) {
+	return nil, nil
+}
 func (g *drainMockGraph) GetFileWithRelations(ctx context.Context, path string) (*graph.FileWithRelations, error) {
 	return nil, nil
 }
@@ -229,6 +291,10 @@ func (g *drainMockGraph) SearchSimilarChunks(ctx context.Context, embedding []fl
 	return nil, nil
 }
 
+func (g *drainMockGraph) SearchSimilarChunksFiltered(ctx context.Context, embedding []float32, k int, filter graph.ChunkFilter) ([]graph.ChunkSearchHit, error) {
+	return nil, nil
+}
+
 // drainMockBus implements events.Bus for testing.
 type drainMockBus struct {
 	mu        sync.Mutex
@@ -0,0 +1,105 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/leefowlercu/agentic-memorizer/internal/chunkers"
+	"github.com/leefowlercu/agentic-memorizer/internal/events"
+	"github.com/leefowlercu/agentic-memorizer/internal/ingest"
+)
+
+// DefaultEmbeddingCostPerToken is the projected embeddings API cost per token in USD
+// (OpenAI text-embedding-3-large list price of $0.13 per million tokens).
+const DefaultEmbeddingCostPerToken = 0.13 / 1_000_000
+
+// DryRunSummary contains projected analysis figures for a file without
+// calling providers or writing the graph.
+type DryRunSummary struct {
+	FilePath               string
+	IngestMode             ingest.Mode
+	ChunkerUsed            string
+	ChunkCount             int
+	TokenEstimate          int
+	EstimatedEmbeddingCost float64
+}
+
+// estimateDryRun reads and chunks a file and projects token and embedding cost totals.
+// Only chunkable files produce chunk and token figures.
+func estimateDryRun(ctx context.Context, reader FileReaderStage, chunker ChunkerStageInterface, item WorkItem, mode DegradationMode, costPerToken float64) (*DryRunSummary, error) {
+	fileResult, err := reader.Read(ctx, item, mode)
+	if err != nil {
+		return nil, fmt.Errorf("file read stage failed; %w", err)
+	}
+
+	summary := &DryRunSummary{
+		FilePath:   item.FilePath,
+		IngestMode: fileResult.IngestMode,
+	}
+	if fileResult.IngestMode != ingest.ModeChunk {
+		return summary, nil
+	}
+
+	chunkResult, err := chunker.Chunk(ctx, item.FilePath, fileResult.Content, fileResult.MIMEType, fileResult.Language)
+	if err != nil {
+		return nil, fmt.Errorf("chunking stage failed; %w", err)
+	}
+
+	summary.ChunkerUsed = chunkResult.ChunkerUsed
+	summary.ChunkCount = len(chunkResult.Chunks)
+	for _, chunk := range chunkResult.Chunks {
+		summary.TokenEstimate += chunkers.EstimateTokens(chunk.Content)
+	}
+	summary.EstimatedEmbeddingCost = float64(summary.TokenEstimate) * costPerToken
+
+	return summary, nil
+}
+
+// processDryRun chunks a work item and publishes its projected figures,
+// skipping semantic analysis, embeddings, registry updates, and persistence.
+func (w *Worker) processDryRun(ctx context.Context, item WorkItem) error {
+	start := time.Now()
+
+	reader, chunker := w.dryRunStages()
+	summary, err := estimateDryRun(ctx, reader, chunker, item, w.queue.Stats().DegradationMode, w.queue.embeddingCostPerToken)
+	if err != nil {
+		w.logger.Warn("dry run failed", "path", item.FilePath, "error", err)
+		w.queue.recordAnalysisFailure()
+		w.queue.publishAnalysisFailed(item.FilePath, err)
+		return nil
+	}
+
+	w.queue.recordSuccess(time.Since(start))
+	w.queue.publishAnalysisDryRun(summary)
+	w.logger.Info("dry run complete",
+		"path", item.FilePath,
+		"ingest_mode", summary.IngestMode,
+		"chunks", summary.ChunkCount,
+		"tokens", summary.TokenEstimate,
+		"estimated_cost", summary.EstimatedEmbeddingCost,
+		"duration", time.Since(start))
+
+	return nil
+}
+
+// dryRunStages returns the file reader and chunker stages used for dry runs.
+func (w *Worker) dryRunStages() (FileReaderStage, ChunkerStageInterface) {
+	if w.pipeline != nil {
+		return w.pipeline.fileReader, w.pipeline.chunker
+	}
+	semanticEnabled := w.semanticProvider != nil && w.semanticProvider.Available()
+	return NewFileReader(w.registry, WithSemanticEnabled(semanticEnabled)), NewChunkerStage(w.chunkerRegistry)
+}
+
+// publishAnalysisDryRun publishes the projected figures for a dry-run file.
+func (q *Queue) publishAnalysisDryRun(summary *DryRunSummary) {
+	q.bus.Publish(q.ctx, events.NewAnalysisDryRun(
+		summary.FilePath,
+		string(summary.IngestMode),
+		summary.ChunkerUsed,
+		summary.ChunkCount,
+		summary.TokenEstimate,
+		summary.EstimatedEmbeddingCost,
+	))
+}
@@ -0,0 +1,142 @@
+package analysis
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/leefowlercu/agentic-memorizer/internal/events"
+	"github.com/leefowlercu/agentic-memorizer/internal/providers"
+)
+
+// countingSemanticProvider records how many times Analyze is invoked.
+type countingSemanticProvider struct {
+	mockSemanticProvider
+	calls atomic.Int32
+}
+
+func (m *countingSemanticProvider) Analyze(ctx context.Context, input providers.SemanticInput) (*providers.SemanticResult, error) {
+	m.calls.Add(1)
+	return m.mockSemanticProvider.Analyze(ctx, input)
+}
+
+// countingEmbeddingsProvider records how many times Embed or EmbedBatch is invoked.
+type countingEmbeddingsProvider struct {
+	mockEmbeddingsProvider
+	calls atomic.Int32
+}
+
+func (m *countingEmbeddingsProvider) Embed(ctx context.Context, req providers.EmbeddingsRequest) (*providers.EmbeddingsResult, error) {
+	m.calls.Add(1)
+	return m.mockEmbeddingsProvider.Embed(ctx, req)
+}
+
+func (m *countingEmbeddingsProvider) EmbedBatch(ctx context.Context, texts []string) ([]providers.EmbeddingsBatchResult, error) {
+	m.calls.Add(1)
+	return m.mockEmbeddingsProvider.EmbedBatch(ctx, texts)
+}
+
+func TestWorkerDryRun(t *testing.T) {
+	bus := events.NewBus()
+	defer bus.Close()
+
+	rate := 0.5 / 1_000_000
+	queue := NewQueue(bus, WithEmbeddingCostPerToken(rate))
+	queue.ctx = context.Background()
+
+	semantic := &countingSemanticProvider{mockSemanticProvider: mockSemanticProvider{available: true}}
+	embeddings := &countingEmbeddingsProvider{mockEmbeddingsProvider: mockEmbeddingsProvider{
+		available: true,
+		embedding: []float32{0.1, 0.2},
+	}}
+	mockG := &mockGraph{}
+
+	worker := NewWorker(0, queue)
+	worker.SetSemanticProvider(semantic)
+	worker.SetEmbeddingsProvider(embeddings)
+	worker.SetGraph(mockG)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sample.txt")
+	content := strings.Repeat("dry run content for token estimation. ", 50)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("write file failed: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat file failed: %v", err)
+	}
+
+	received := make(chan *events.DryRunEvent, 1)
+	unsub := bus.Subscribe(events.AnalysisDryRun, func(e events.Event) {
+		if de, ok := e.Payload.(*events.DryRunEvent); ok && de.Path == path {
+			received <- de
+		}
+	})
+	defer unsub()
+
+	err = worker.processItem(context.Background(), WorkItem{
+		FilePath:  path,
+		FileSize:  info.Size(),
+		ModTime:   info.ModTime(),
+		EventType: WorkItemNew,
+		DryRun:    true,
+	})
+	if err != nil {
+		t.Fatalf("processItem failed: %v", err)
+	}
+
+	var summary *events.DryRunEvent
+	select {
+	case summary = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for dry run event")
+	}
+
+	if summary.ChunkCount == 0 {
+		t.Error("expected dry run to report chunks")
+	}
+	if summary.TokenEstimate == 0 {
+		t.Error("expected dry run to report a token estimate")
+	}
+	wantCost := float64(summary.TokenEstimate) * rate
+	if summary.EstimatedEmbeddingCost != wantCost {
+		t.Errorf("EstimatedEmbeddingCost = %g, want %g", summary.EstimatedEmbeddingCost, wantCost)
+	}
+
+	if calls := semantic.calls.Load(); calls != 0 {
+		t.Errorf("semantic provider called %d times, want 0", calls)
+	}
+	if calls := embeddings.calls.Load(); calls != 0 {
+		t.Errorf("embeddings provider called %d times, want 0", calls)
+	}
+	if len(mockG.chunks) != 0 || len(mockG.deleteFileFor) != 0 {
+		t.Errorf("expected no graph writes, got %d chunks and %d deletes", len(mockG.chunks), len(mockG.deleteFileFor))
+	}
+}
+
+func TestQueueDryRunMarksItems(t *testing.T) {
+	bus := events.NewBus()
+	defer bus.Close()
+
+	queue := NewQueue(bus, WithDryRun(true))
+	queue.ctx = context.Background()
+	queue.state = QueueStateRunning
+	queue.work = newWorkQueue(1)
+
+	if err := queue.Enqueue(WorkItem{FilePath: "/tmp/file.txt"}); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	item, ok := queue.work.pop()
+	if !ok {
+		t.Fatal("expected enqueued item")
+	}
+	if !item.DryRun {
+		t.Error("expected enqueued item to be marked as dry run")
+	}
+}
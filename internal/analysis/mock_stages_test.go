@@ -2,6 +2,7 @@ package analysis
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"time"
 
@@ -45,7 +46,7 @@ type mockChunkerStage struct {
 	err    error
 }
 
-func (m *mockChunkerStage) Chunk(ctx context.Context, content []byte, mimeType, language string) (*chunkers.ChunkResult, error) {
+func (m *mockChunkerStage) Chunk(ctx context.Context, path string, content []byte, mimeType, language string) (*chunkers.ChunkResult, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
@@ -92,6 +93,13 @@ func (m *mockSemanticStage) Analyze(ctx context.Context, input providers.Semanti
 	}, nil
 }
 
+func (m *mockSemanticStage) AnalyzeChunk(ctx context.Context, path string, chunks []chunkers.Chunk, index int) (*SemanticResult, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &SemanticResult{Summary: fmt.Sprintf("Mock summary of chunk %d", index)}, nil
+}
+
 // mockEmbeddingsStage is a mock implementation of EmbeddingsStageInterface for testing.
 type mockEmbeddingsStage struct {
 	embedding []float32
@@ -0,0 +1,79 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+)
+
+// Pause stops workers from taking new work items while keeping them and the
+// event subscriptions alive. Items already being analyzed finish, and Enqueue
+// keeps accepting work up to capacity. Use Resume to continue.
+func (q *Queue) Pause() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	switch q.state {
+	case QueueStatePaused:
+		return nil
+	case QueueStateRunning:
+	default:
+		return fmt.Errorf("queue not running")
+	}
+
+	q.state = QueueStatePaused
+	q.resumeChan = make(chan struct{})
+
+	q.logger.Info("analysis queue paused", "pending", q.work.len())
+	return nil
+}
+
+// Resume lets workers take work items again after Pause.
+func (q *Queue) Resume() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	switch q.state {
+	case QueueStateRunning:
+		return nil
+	case QueueStatePaused:
+	default:
+		return fmt.Errorf("queue not paused")
+	}
+
+	q.state = QueueStateRunning
+	close(q.resumeChan)
+	q.resumeChan = nil
+
+	q.logger.Info("analysis queue resumed", "pending", q.work.len())
+	return nil
+}
+
+// pauseGate returns a channel closed when the queue resumes, or nil if the
+// queue is not paused.
+func (q *Queue) pauseGate() <-chan struct{} {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.resumeChan == nil {
+		return nil
+	}
+	return q.resumeChan
+}
+
+// awaitResume blocks while the queue is paused. Returns false if the worker
+// should exit instead.
+func (w *Worker) awaitResume(ctx context.Context) bool {
+	for {
+		gate := w.queue.pauseGate()
+		if gate == nil {
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-w.stopChan:
+			return false
+		case <-gate:
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package analysis
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/leefowlercu/agentic-memorizer/internal/events"
+)
+
+func TestQueuePauseResume(t *testing.T) {
+	bus := events.NewBus()
+	defer bus.Close()
+
+	queue := NewQueue(bus, WithWorkerCount(2))
+	queue.SetProviders(&mockSemanticProvider{available: true}, &mockEmbeddingsProvider{available: true, embedding: []float32{0.1, 0.2}})
+
+	if err := queue.Pause(); err == nil {
+		t.Error("expected Pause to fail before Start")
+	}
+
+	ctx := context.Background()
+	if err := queue.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer queue.Stop(context.Background())
+
+	completed := make(chan string, 4)
+	unsub := bus.Subscribe(events.AnalysisComplete, func(e events.Event) {
+		if ae, ok := e.Payload.(*events.AnalysisEvent); ok {
+			completed <- ae.Path
+		}
+	})
+	defer unsub()
+
+	if err := queue.Pause(); err != nil {
+		t.Fatalf("Pause failed: %v", err)
+	}
+	if got := queue.Stats().State; got != QueueStatePaused {
+		t.Errorf("State = %v, want QueueStatePaused", got)
+	}
+
+	dir := t.TempDir()
+	var paths []string
+	for _, name := range []string{"a.txt", "b.txt", "c.txt"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("paused "+name), 0644); err != nil {
+			t.Fatalf("failed to write test file: %v", err)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("failed to stat test file: %v", err)
+		}
+		if err := queue.Enqueue(WorkItem{FilePath: path, FileSize: info.Size(), ModTime: info.ModTime(), EventType: WorkItemNew}); err != nil {
+			t.Fatalf("Enqueue while paused failed: %v", err)
+		}
+		paths = append(paths, path)
+	}
+
+	select {
+	case path := <-completed:
+		t.Fatalf("item %q processed while paused", path)
+	case <-time.After(200 * time.Millisecond):
+	}
+	stats := queue.Stats()
+	if stats.PendingItems != len(paths) {
+		t.Errorf("PendingItems = %d, want %d", stats.PendingItems, len(paths))
+	}
+	if stats.ActiveWorkers != 2 {
+		t.Errorf("ActiveWorkers = %d, want 2 workers kept alive", stats.ActiveWorkers)
+	}
+
+	if err := queue.Resume(); err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+	if got := queue.Stats().State; got != QueueStateRunning {
+		t.Errorf("State = %v, want QueueStateRunning", got)
+	}
+
+	seen := make(map[string]bool)
+	for range paths {
+		select {
+		case path := <-completed:
+			seen[path] = true
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for resumed work; completed %v", seen)
+		}
+	}
+	for _, path := range paths {
+		if !seen[path] {
+			t.Errorf("expected %q to be processed after resume", path)
+		}
+	}
+}
+
+func TestQueueStopWhilePaused(t *testing.T) {
+	bus := events.NewBus()
+	defer bus.Close()
+
+	queue := NewQueue(bus, WithWorkerCount(1))
+	if err := queue.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := queue.Pause(); err != nil {
+		t.Fatalf("Pause failed: %v", err)
+	}
+	if err := queue.Enqueue(WorkItem{FilePath: "/repo/main.go"}); err != nil {
+		t.Fatalf("Enqueue while paused failed: %v", err)
+	}
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := queue.Stop(stopCtx); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if stopCtx.Err() != nil {
+		t.Fatal("Stop timed out waiting for paused workers")
+	}
+	if got := queue.Stats().State; got != QueueStateStopped {
+		t.Errorf("State = %v, want QueueStateStopped", got)
+	}
+	if err := queue.Resume(); err == nil {
+		t.Error("expected Resume to fail after Stop")
+	}
+}
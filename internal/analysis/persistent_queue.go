@@ -0,0 +1,94 @@
+package analysis
+
+import (
+	"context"
+
+	"github.com/leefowlercu/agentic-memorizer/internal/registry"
+)
+
+// WithPersistentQueue records work items in reg as they are enqueued so that
+// work pending when the process stops is re-enqueued by the next Start. Items
+// are removed from reg once a worker finishes with them, whether analysis
+// succeeded or failed permanently. Dry-run items are not recorded.
+func WithPersistentQueue(reg registry.Registry) QueueOption {
+	return func(q *Queue) {
+		q.pendingStore = reg
+	}
+}
+
+// recordPending durably records a new work item, setting its pendingID. A path
+// has at most one record, so repeat events for a path share it. Items already
+// recorded, and dry-run items, are left alone. Failures are logged and the item
+// stays in memory only.
+func (q *Queue) recordPending(item *WorkItem) {
+	if q.pendingStore == nil || item.pendingID != 0 || item.DryRun {
+		return
+	}
+
+	pending := &registry.PendingWorkItem{
+		FilePath:  item.FilePath,
+		FileSize:  item.FileSize,
+		ModTime:   item.ModTime,
+		EventType: int(item.EventType),
+	}
+	if err := q.pendingStore.EnqueueWorkItem(q.ctx, pending); err != nil {
+		q.logger.Warn("failed to persist work item", "path", item.FilePath, "error", err)
+		return
+	}
+	item.pendingID = pending.ID
+	item.pendingEnqueuedAt = pending.EnqueuedAt
+}
+
+// completePending removes a recorded work item once a worker is done with it,
+// unless the path was enqueued again while the item was processed.
+func (q *Queue) completePending(item WorkItem) {
+	if q.pendingStore == nil || item.pendingID == 0 {
+		return
+	}
+
+	// Not the worker context: completing must still succeed while the queue stops
+	if err := q.pendingStore.CompleteWorkItem(context.Background(), item.pendingID, item.pendingEnqueuedAt); err != nil {
+		q.logger.Warn("failed to complete persisted work item", "path", item.FilePath, "error", err)
+	}
+}
+
+// resumePending re-enqueues the work items recorded before the last stop,
+// skipping paths that are already queued. Callers hold q.mu.
+func (q *Queue) resumePending(ctx context.Context) {
+	if q.pendingStore == nil {
+		return
+	}
+
+	items, err := q.pendingStore.ListPendingWorkItems(ctx)
+	if err != nil {
+		q.logger.Warn("failed to load persisted work items", "error", err)
+		return
+	}
+
+	queued := q.work.paths()
+	resumed := 0
+	for _, pending := range items {
+		if _, ok := queued[pending.FilePath]; ok {
+			continue
+		}
+		item := WorkItem{
+			FilePath:          pending.FilePath,
+			FileSize:          pending.FileSize,
+			ModTime:           pending.ModTime,
+			EventType:         WorkItemType(pending.EventType),
+			pendingID:         pending.ID,
+			pendingEnqueuedAt: pending.EnqueuedAt,
+		}
+		if err := q.enqueue(item); err != nil {
+			// The item stays recorded and is retried on the next start
+			q.logger.Warn("failed to resume persisted work item", "path", item.FilePath, "error", err)
+			continue
+		}
+		queued[item.FilePath] = struct{}{}
+		resumed++
+	}
+
+	if resumed > 0 {
+		q.logger.Info("resumed persisted analysis work", "items", resumed)
+	}
+}
@@ -0,0 +1,235 @@
+package analysis
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/leefowlercu/agentic-memorizer/internal/events"
+	"github.com/leefowlercu/agentic-memorizer/internal/registry"
+)
+
+func TestPersistentQueueResumesAfterRestart(t *testing.T) {
+	bus := events.NewBus()
+	defer bus.Close()
+
+	ctx := context.Background()
+	reg, err := registry.Open(ctx, filepath.Join(t.TempDir(), "registry.db"))
+	if err != nil {
+		t.Fatalf("failed to open registry: %v", err)
+	}
+	defer reg.Close()
+
+	dir := t.TempDir()
+	var paths []string
+	for _, name := range []string{"a.txt", "b.txt"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("pending "+name), 0644); err != nil {
+			t.Fatalf("failed to write test file: %v", err)
+		}
+		paths = append(paths, path)
+	}
+
+	// Enqueue into a queue without workers, then drop it as a crash would
+	first := NewQueue(bus, WithPersistentQueue(reg))
+	first.ctx = ctx
+	first.state = QueueStateRunning
+	first.work = newWorkQueue(10)
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("failed to stat test file: %v", err)
+		}
+		if err := first.Enqueue(WorkItem{
+			FilePath:  path,
+			FileSize:  info.Size(),
+			ModTime:   info.ModTime(),
+			EventType: WorkItemChanged,
+		}); err != nil {
+			t.Fatalf("Enqueue failed: %v", err)
+		}
+	}
+
+	pending, err := reg.ListPendingWorkItems(ctx)
+	if err != nil {
+		t.Fatalf("ListPendingWorkItems failed: %v", err)
+	}
+	if len(pending) != 2 {
+		t.Fatalf("expected 2 persisted items, got %d", len(pending))
+	}
+	if pending[0].FilePath != paths[0] || pending[0].EventType != int(WorkItemChanged) || pending[0].FileSize == 0 {
+		t.Errorf("unexpected persisted item %+v", pending[0])
+	}
+
+	var mu sync.Mutex
+	completed := make(map[string]bool)
+	done := make(chan struct{})
+	unsub := bus.Subscribe(events.AnalysisComplete, func(e events.Event) {
+		ae, ok := e.Payload.(*events.AnalysisEvent)
+		if !ok {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		completed[ae.Path] = true
+		if len(completed) == len(paths) {
+			close(done)
+		}
+	})
+	defer unsub()
+
+	// A new queue over the same registry picks the work back up
+	second := NewQueue(bus, WithWorkerCount(1), WithPersistentQueue(reg))
+	second.SetProviders(&mockSemanticProvider{available: true}, &mockEmbeddingsProvider{available: true, embedding: []float32{0.1, 0.2}})
+	if err := second.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer second.Stop(context.Background())
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for resumed items; completed %v", completed)
+	}
+
+	pending, err = reg.ListPendingWorkItems(ctx)
+	if err != nil {
+		t.Fatalf("ListPendingWorkItems failed: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("expected completed items to be removed, %d remain", len(pending))
+	}
+}
+
+func TestPersistentQueueSkipsDryRunItems(t *testing.T) {
+	bus := events.NewBus()
+	defer bus.Close()
+
+	ctx := context.Background()
+	reg, err := registry.Open(ctx, filepath.Join(t.TempDir(), "registry.db"))
+	if err != nil {
+		t.Fatalf("failed to open registry: %v", err)
+	}
+	defer reg.Close()
+
+	queue := NewQueue(bus, WithPersistentQueue(reg), WithDryRun(true))
+	queue.ctx = ctx
+	queue.state = QueueStateRunning
+	queue.work = newWorkQueue(10)
+
+	if err := queue.Enqueue(WorkItem{FilePath: "/repo/main.go"}); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	pending, err := reg.ListPendingWorkItems(ctx)
+	if err != nil {
+		t.Fatalf("ListPendingWorkItems failed: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("expected dry-run items not to be persisted, got %d", len(pending))
+	}
+}
+
+func TestPersistentQueueRecordsOneItemPerPath(t *testing.T) {
+	bus := events.NewBus()
+	defer bus.Close()
+
+	ctx := context.Background()
+	reg, err := registry.Open(ctx, filepath.Join(t.TempDir(), "registry.db"))
+	if err != nil {
+		t.Fatalf("failed to open registry: %v", err)
+	}
+	defer reg.Close()
+
+	queue := NewQueue(bus, WithPersistentQueue(reg))
+	queue.ctx = ctx
+	queue.state = QueueStateRunning
+	queue.work = newWorkQueue(1)
+
+	if err := queue.Enqueue(WorkItem{FilePath: "/repo/a.go"}); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	// The queue is full; rejected repeats for the same path share one record
+	for i := 0; i < 2; i++ {
+		if err := queue.Enqueue(WorkItem{FilePath: "/repo/b.go", FileSize: int64(i)}); err == nil {
+			t.Fatal("expected queue full error")
+		}
+	}
+
+	pending, err := reg.ListPendingWorkItems(ctx)
+	if err != nil {
+		t.Fatalf("ListPendingWorkItems failed: %v", err)
+	}
+	if len(pending) != 2 {
+		t.Fatalf("expected one persisted item per path, got %+v", pending)
+	}
+	if pending[1].FilePath != "/repo/b.go" || pending[1].FileSize != 1 {
+		t.Errorf("expected the latest event for /repo/b.go, got %+v", pending[1])
+	}
+
+	// Resuming skips the path already in memory and queues the other
+	resumed := NewQueue(bus, WithPersistentQueue(reg))
+	resumed.ctx = ctx
+	resumed.state = QueueStateRunning
+	resumed.work = newWorkQueue(10)
+	if err := resumed.Enqueue(WorkItem{FilePath: "/repo/a.go"}); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	resumed.resumePending(ctx)
+
+	if n := resumed.work.len(); n != 2 {
+		t.Errorf("expected 2 queued items after resume, got %d", n)
+	}
+}
+
+func TestPersistentQueueKeepsRecordReenqueuedDuringProcessing(t *testing.T) {
+	bus := events.NewBus()
+	defer bus.Close()
+
+	ctx := context.Background()
+	reg, err := registry.Open(ctx, filepath.Join(t.TempDir(), "registry.db"))
+	if err != nil {
+		t.Fatalf("failed to open registry: %v", err)
+	}
+	defer reg.Close()
+
+	queue := NewQueue(bus, WithPersistentQueue(reg))
+	queue.ctx = ctx
+	queue.state = QueueStateRunning
+	queue.work = newWorkQueue(10)
+
+	if err := queue.Enqueue(WorkItem{FilePath: "/repo/a.go"}); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	processing, _ := queue.work.pop()
+
+	// A new event for the path arrives while the first item is processed
+	time.Sleep(time.Millisecond)
+	if err := queue.Enqueue(WorkItem{FilePath: "/repo/a.go", FileSize: 2}); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	queue.completePending(processing)
+
+	pending, err := reg.ListPendingWorkItems(ctx)
+	if err != nil {
+		t.Fatalf("ListPendingWorkItems failed: %v", err)
+	}
+	if len(pending) != 1 || pending[0].FileSize != 2 {
+		t.Fatalf("expected the re-enqueued item to stay recorded, got %+v", pending)
+	}
+
+	newer, _ := queue.work.pop()
+	queue.completePending(newer)
+
+	pending, err = reg.ListPendingWorkItems(ctx)
+	if err != nil {
+		t.Fatalf("ListPendingWorkItems failed: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("expected the record to be removed after the newer item, %d remain", len(pending))
+	}
+}
@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"log/slog"
 	"time"
+	"unicode/utf8"
 
 	"github.com/leefowlercu/agentic-memorizer/internal/cache"
 	"github.com/leefowlercu/agentic-memorizer/internal/chunkers"
 	"github.com/leefowlercu/agentic-memorizer/internal/graph"
 	"github.com/leefowlercu/agentic-memorizer/internal/ingest"
+	"github.com/leefowlercu/agentic-memorizer/internal/metrics"
 	"github.com/leefowlercu/agentic-memorizer/internal/providers"
 	"github.com/leefowlercu/agentic-memorizer/internal/registry"
 	"github.com/leefowlercu/agentic-memorizer/internal/storage"
@@ -32,6 +34,13 @@ type Pipeline struct {
 
 	// Analysis version for tracking schema changes
 	analysisVersion string
+
+	// Secret redaction applied to chunk content and summaries (nil disables)
+	redactor               *SecretRedactor
+	skipRedactedEmbeddings bool
+
+	// Summarize each chunk in addition to the file
+	chunkSummaries bool
 }
 
 // PipelineConfig holds all dependencies needed to construct a Pipeline.
@@ -47,6 +56,32 @@ type PipelineConfig struct {
 	PersistenceQueue   storage.DurablePersistenceQueue
 	AnalysisVersion    string
 	Logger             *slog.Logger
+
+	// Redactor replaces secrets in chunk content and summaries; nil disables redaction.
+	Redactor *SecretRedactor
+
+	// SkipRedactedEmbeddings excludes chunks containing redacted secrets from embeddings.
+	SkipRedactedEmbeddings bool
+
+	// SummaryCache shares semantic results across files with identical content; nil disables it.
+	SummaryCache *SummaryCache
+
+	// ChunkSummaries summarizes each chunk, with its neighbors as context,
+	// after the file-level semantic analysis.
+	ChunkSummaries bool
+
+	// SemanticTokenRates and EmbeddingsTokenRates price provider token usage
+	// for the cost metric; zero rates record tokens without cost.
+	SemanticTokenRates   metrics.TokenRates
+	EmbeddingsTokenRates metrics.TokenRates
+
+	// EmbeddingsTruncation controls how chunks over the embeddings model's
+	// token limit are handled; empty sends them unchanged.
+	EmbeddingsTruncation providers.TruncationStrategy
+
+	// RateLimiters shares provider rate limits across pipelines; nil does not
+	// limit provider calls beyond the providers' own limits.
+	RateLimiters *providers.RateLimiterManager
 }
 
 // PipelineOption configures a Pipeline.
@@ -103,7 +138,7 @@ func NewPipeline(cfg PipelineConfig, opts ...PipelineOption) *Pipeline {
 	}
 
 	// Build persistence stage with optional queue for fallback
-	persistenceOpts := []PersistenceStageOption{WithPersistenceLogger(logger)}
+	persistenceOpts := []PersistenceStageOption{WithPersistenceLogger(logger), WithPersistenceRegistry(cfg.Registry)}
 	if cfg.PersistenceQueue != nil {
 		persistenceOpts = append(persistenceOpts, WithPersistenceQueue(cfg.PersistenceQueue))
 	}
@@ -113,13 +148,17 @@ func NewPipeline(cfg PipelineConfig, opts ...PipelineOption) *Pipeline {
 	p := &Pipeline{
 		fileReader:       NewFileReader(cfg.Registry, WithSemanticEnabled(semanticEnabled)),
 		chunker:          NewChunkerStage(cfg.ChunkerRegistry),
-		semantic:         NewSemanticStage(cfg.SemanticProvider, cfg.SemanticCache, cfg.Registry, cfg.AnalysisVersion, logger),
-		embeddings:       NewEmbeddingsStage(cfg.EmbeddingsProvider, cfg.EmbeddingsCache, cfg.Registry, logger),
+		semantic:         NewSemanticStage(cfg.SemanticProvider, cfg.SemanticCache, cfg.Registry, cfg.AnalysisVersion, logger, WithSummaryCache(cfg.SummaryCache), WithSemanticTokenRates(cfg.SemanticTokenRates), WithSemanticRateLimiter(stageLimiter(cfg.RateLimiters, cfg.SemanticProvider))),
+		embeddings:       NewEmbeddingsStage(cfg.EmbeddingsProvider, cfg.EmbeddingsCache, cfg.Registry, logger, WithEmbeddingsTokenRates(cfg.EmbeddingsTokenRates), WithEmbeddingsTruncation(cfg.EmbeddingsTruncation), WithEmbeddingsRateLimiter(stageLimiter(cfg.RateLimiters, cfg.EmbeddingsProvider)), WithEmbeddingsGraph(cfg.Graph)),
 		persistence:      NewPersistenceStage(cfg.Graph, persistenceOpts...),
 		logger:           logger,
 		semanticProvider: cfg.SemanticProvider,
 		registry:         cfg.Registry,
 		analysisVersion:  cfg.AnalysisVersion,
+
+		redactor:               cfg.Redactor,
+		skipRedactedEmbeddings: cfg.SkipRedactedEmbeddings,
+		chunkSummaries:         cfg.ChunkSummaries,
 	}
 
 	for _, opt := range opts {
@@ -151,17 +190,22 @@ func (p *Pipeline) Execute(ctx context.Context, pctx *PipelineContext) error {
 		return nil
 	}
 
+	// Stage errors caused by the context deadline are returned once the partial
+	// result is built; other stage failures are non-fatal.
+	var semanticErr, chunkSummaryErr, embeddingsErr error
+
 	// Semantic-only files skip chunking/embeddings
 	if pctx.IsSemanticOnly() {
 		if p.semantic != nil {
 			semanticStart := time.Now()
-			input, buildErr := BuildSemanticInput(pctx.WorkItem.FilePath, pctx.FileResult, nil, p.semanticProvider)
+			input, buildErr := BuildSemanticInput(pctx.WorkItem.FilePath, p.redactFileResult(pctx), nil, p.semanticProvider)
 			if buildErr != nil {
 				p.logger.Warn("semantic input build failed",
 					"path", pctx.WorkItem.FilePath,
 					"error", buildErr)
 			} else {
-				semanticResult, semanticErr := p.semantic.Analyze(ctx, input, fileResult.ContentHash)
+				var semanticResult *SemanticResult
+				semanticResult, semanticErr = p.semantic.Analyze(ctx, input, fileResult.ContentHash)
 				if semanticErr != nil {
 					p.logger.Warn("semantic analysis failed",
 						"path", pctx.WorkItem.FilePath,
@@ -169,33 +213,44 @@ func (p *Pipeline) Execute(ctx context.Context, pctx *PipelineContext) error {
 						"duration", time.Since(semanticStart))
 				}
 				pctx.SemanticResult = semanticResult
+				p.redactSemanticResult(pctx)
 			}
 		}
 		p.updateRegistryForSemanticOnly(ctx, pctx)
 		pctx.AnalysisResult = pctx.BuildAnalysisResult()
-		return nil
+		return deadlineError(semanticErr)
 	}
 
 	// Stage 2: Chunk content
-	chunkResult, err := p.chunker.Chunk(ctx, fileResult.Content, fileResult.MIMEType, fileResult.Language)
+	chunkResult, err := p.chunker.Chunk(ctx, pctx.WorkItem.FilePath, fileResult.Content, fileResult.MIMEType, fileResult.Language)
 	if err != nil {
 		return fmt.Errorf("chunking stage failed; %w", err)
 	}
 	pctx.ChunkResult = chunkResult
 
+	// Redact secrets before chunk content reaches providers or the graph
+	redacted := p.redactChunks(pctx)
+
 	// Build analyzed chunks structure
 	pctx.AnalyzedChunks = BuildAnalyzedChunks(chunkResult.Chunks)
+	for i := range pctx.AnalyzedChunks {
+		if redacted[i] {
+			pctx.AnalyzedChunks[i].Redacted = true
+			pctx.AnalyzedChunks[i].SkipEmbedding = p.skipRedactedEmbeddings
+		}
+	}
 
 	// Stage 3: Semantic analysis (optional)
 	if p.semantic != nil {
 		semanticStart := time.Now()
-		input, buildErr := BuildSemanticInput(pctx.WorkItem.FilePath, pctx.FileResult, chunkResult, p.semanticProvider)
+		input, buildErr := BuildSemanticInput(pctx.WorkItem.FilePath, p.redactFileResult(pctx), chunkResult, p.semanticProvider)
 		if buildErr != nil {
 			p.logger.Warn("semantic input build failed",
 				"path", pctx.WorkItem.FilePath,
 				"error", buildErr)
 		} else {
-			semanticResult, semanticErr := p.semantic.Analyze(ctx, input, fileResult.ContentHash)
+			var semanticResult *SemanticResult
+			semanticResult, semanticErr = p.semantic.Analyze(ctx, input, fileResult.ContentHash)
 			if semanticErr != nil {
 				p.logger.Warn("semantic analysis failed",
 					"path", pctx.WorkItem.FilePath,
@@ -204,13 +259,20 @@ func (p *Pipeline) Execute(ctx context.Context, pctx *PipelineContext) error {
 				// Continue pipeline - semantic failures are non-fatal
 			}
 			pctx.SemanticResult = semanticResult
+			p.redactSemanticResult(pctx)
 		}
 	}
 
+	// Per-chunk summaries (optional)
+	if p.chunkSummaries && p.semantic != nil {
+		chunkSummaryErr = p.summarizeChunks(ctx, pctx)
+	}
+
 	// Stage 4: Embeddings generation (conditional)
 	if pctx.ShouldGenerateEmbeddings() && p.embeddings != nil {
 		embeddingsStart := time.Now()
-		embeddings, embeddingsErr := p.embeddings.Generate(ctx, pctx.WorkItem.FilePath, pctx.AnalyzedChunks)
+		var embeddings []float32
+		embeddings, embeddingsErr = p.embeddings.Generate(ctx, pctx.WorkItem.FilePath, pctx.AnalyzedChunks)
 		if embeddingsErr != nil {
 			p.logger.Warn("embeddings generation failed",
 				"path", pctx.WorkItem.FilePath,
@@ -225,6 +287,37 @@ func (p *Pipeline) Execute(ctx context.Context, pctx *PipelineContext) error {
 	// Build final analysis result
 	pctx.AnalysisResult = pctx.BuildAnalysisResult()
 
+	return deadlineError(semanticErr, chunkSummaryErr, embeddingsErr)
+}
+
+// summarizeChunks stores a summary on each analyzed chunk, using neighboring
+// chunks and the enclosing section or type as context. Failed chunks are left
+// unsummarized; once the context is done the remaining chunks are skipped and
+// its error returned.
+func (p *Pipeline) summarizeChunks(ctx context.Context, pctx *PipelineContext) error {
+	chunks := pctx.ChunkResult.Chunks
+	for i := range pctx.AnalyzedChunks {
+		result, err := p.semantic.AnalyzeChunk(ctx, pctx.WorkItem.FilePath, chunks, i)
+		if err != nil {
+			p.logger.Warn("chunk semantic analysis failed",
+				"path", pctx.WorkItem.FilePath,
+				"chunk", pctx.AnalyzedChunks[i].Index,
+				"error", err)
+			if ctx.Err() != nil {
+				return err
+			}
+			continue
+		}
+		if result == nil {
+			continue
+		}
+
+		summary := result.Summary
+		if p.redactor != nil {
+			summary, _ = p.redactor.Redact(summary)
+		}
+		pctx.AnalyzedChunks[i].Summary = summary
+	}
 	return nil
 }
 
@@ -237,17 +330,96 @@ func (p *Pipeline) Persist(ctx context.Context, pctx *PipelineContext) error {
 	return p.persistence.Persist(ctx, pctx.AnalysisResult)
 }
 
+// redactChunks replaces secrets in chunk content in place.
+// Returns the set of chunk positions that were redacted.
+func (p *Pipeline) redactChunks(pctx *PipelineContext) map[int]bool {
+	if p.redactor == nil || pctx.ChunkResult == nil {
+		return nil
+	}
+
+	redacted := make(map[int]bool)
+	for i := range pctx.ChunkResult.Chunks {
+		chunk := &pctx.ChunkResult.Chunks[i]
+		content, count := p.redactor.Redact(chunk.Content)
+		if count == 0 {
+			continue
+		}
+
+		chunk.Content = content
+		redacted[i] = true
+		pctx.ChunkResult.Warnings = append(pctx.ChunkResult.Warnings, chunkers.ChunkWarning{
+			Offset:  chunk.StartOffset,
+			Message: fmt.Sprintf("redacted %d secret(s) from chunk %d", count, chunk.Index),
+			Code:    "SECRET_REDACTED",
+		})
+		p.logger.Warn("secrets redacted from chunk",
+			"path", pctx.WorkItem.FilePath,
+			"chunk", chunk.Index,
+			"redactions", count,
+			"skip_embedding", p.skipRedactedEmbeddings)
+	}
+	return redacted
+}
+
+// redactFileResult returns the file result to use for semantic input,
+// with secrets replaced in textual content.
+func (p *Pipeline) redactFileResult(pctx *PipelineContext) *FileReadResult {
+	if p.redactor == nil || pctx.FileResult == nil || !utf8.Valid(pctx.FileResult.Content) {
+		return pctx.FileResult
+	}
+
+	content, count := p.redactor.Redact(string(pctx.FileResult.Content))
+	if count == 0 {
+		return pctx.FileResult
+	}
+
+	p.logger.Warn("secrets redacted from semantic input",
+		"path", pctx.WorkItem.FilePath,
+		"redactions", count)
+
+	redacted := *pctx.FileResult
+	redacted.Content = []byte(content)
+	return &redacted
+}
+
+// redactSemanticResult replaces secrets echoed back in the semantic summary.
+func (p *Pipeline) redactSemanticResult(pctx *PipelineContext) {
+	if p.redactor == nil || pctx.SemanticResult == nil {
+		return
+	}
+
+	summary, count := p.redactor.Redact(pctx.SemanticResult.Summary)
+	if count == 0 {
+		return
+	}
+
+	pctx.SemanticResult.Summary = summary
+	p.logger.Warn("secrets redacted from summary",
+		"path", pctx.WorkItem.FilePath,
+		"redactions", count)
+}
+
 // syncMetadataState updates the registry with current file metadata.
 func (p *Pipeline) syncMetadataState(ctx context.Context, pctx *PipelineContext) {
-	if p.registry == nil || pctx.AnalysisResult == nil {
+	if pctx.AnalysisResult == nil {
 		return
 	}
 
 	result := pctx.AnalysisResult
+	if p.registry == nil {
+		pctx.ChangeReason = changeReason(pctx.WorkItem.EventType, nil, result.ContentHash, p.analysisVersion)
+		result.ChangeReason = pctx.ChangeReason
+		return
+	}
 
 	// Check if content has changed (requires clearing previous analysis state)
 	existingState, err := p.registry.GetFileState(ctx, result.FilePath)
-	if err == nil && existingState.ContentHash != result.ContentHash {
+	if err != nil {
+		existingState = nil
+	}
+	pctx.ChangeReason = changeReason(pctx.WorkItem.EventType, existingState, result.ContentHash, p.analysisVersion)
+	result.ChangeReason = pctx.ChangeReason
+	if existingState != nil && existingState.ContentHash != result.ContentHash {
 		p.logger.Debug("content changed; clearing analysis state",
 			"path", result.FilePath,
 			"old_hash", existingState.ContentHash[:8],
@@ -285,7 +457,7 @@ func (p *Pipeline) updateRegistryForMetadataOnly(ctx context.Context, pctx *Pipe
 	if err := p.registry.UpdateSemanticState(ctx, result.FilePath, version, nil); err != nil {
 		p.logger.Warn("failed to update semantic state", "path", result.FilePath, "error", err)
 	}
-	if err := p.registry.UpdateEmbeddingsState(ctx, result.FilePath, nil); err != nil {
+	if err := p.registry.UpdateEmbeddingsState(ctx, result.FilePath, "", nil); err != nil {
 		p.logger.Warn("failed to update embeddings state", "path", result.FilePath, "error", err)
 	}
 }
@@ -296,7 +468,7 @@ func (p *Pipeline) updateRegistryForSemanticOnly(ctx context.Context, pctx *Pipe
 		return
 	}
 
-	if err := p.registry.UpdateEmbeddingsState(ctx, pctx.AnalysisResult.FilePath, nil); err != nil {
+	if err := p.registry.UpdateEmbeddingsState(ctx, pctx.AnalysisResult.FilePath, "", nil); err != nil {
 		p.logger.Warn("failed to update embeddings state", "path", pctx.AnalysisResult.FilePath, "error", err)
 	}
 }
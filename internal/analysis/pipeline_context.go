@@ -24,6 +24,10 @@ type PipelineContext struct {
 	Embeddings     []float32
 	AnalysisResult *AnalysisResult
 
+	// ChangeReason records why the file is being ingested; set when metadata
+	// state is synced so rebuilt results keep it.
+	ChangeReason ingest.ChangeReason
+
 	// Processing metadata
 	StartTime time.Time
 	Logger    *slog.Logger
@@ -55,6 +59,7 @@ func (p *PipelineContext) BuildAnalysisResult() *AnalysisResult {
 		IngestKind:   p.FileResult.Kind,
 		IngestMode:   p.FileResult.IngestMode,
 		IngestReason: p.FileResult.IngestReason,
+		ChangeReason: p.ChangeReason,
 		ContentHash:  p.FileResult.ContentHash,
 		MetadataHash: p.FileResult.MetadataHash,
 		AnalyzedAt:   time.Now(),
@@ -72,11 +77,15 @@ func (p *PipelineContext) BuildAnalysisResult() *AnalysisResult {
 		result.Tags = p.SemanticResult.Tags
 		result.Topics = p.SemanticResult.Topics
 		result.Entities = p.SemanticResult.Entities
+		result.Relations = p.SemanticResult.Relations
 		result.References = p.SemanticResult.References
 		result.Complexity = p.SemanticResult.Complexity
 		result.Keywords = p.SemanticResult.Keywords
 	}
 
+	// Add file references found while chunking, such as resolved includes
+	result.References = mergeChunkReferences(result.References, p.ChunkResult)
+
 	// Add embeddings (file-level average)
 	result.Embeddings = p.Embeddings
 
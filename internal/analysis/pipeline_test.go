@@ -3,6 +3,7 @@ package analysis
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -381,6 +382,45 @@ func TestPipelineExecute(t *testing.T) {
 			t.Error("expected AnalysisResult to be populated despite embeddings error")
 		}
 	})
+
+	t.Run("ChunkSummaries", func(t *testing.T) {
+		for _, enabled := range []bool{false, true} {
+			chunker := &mockChunkerStage{result: &chunkers.ChunkResult{
+				Chunks: []chunkers.Chunk{
+					{Index: 0, Content: "first chunk"},
+					{Index: 1, Content: "second chunk"},
+				},
+				TotalChunks: 2,
+				ChunkerUsed: "mock-chunker",
+			}}
+			p := NewPipeline(PipelineConfig{ChunkSummaries: enabled},
+				WithFileReader(&mockFileReaderStage{}),
+				WithChunker(chunker),
+				WithSemantic(&mockSemanticStage{}),
+				WithEmbeddings(&mockEmbeddingsStage{}),
+				WithPersistence(&mockPersistenceStage{}),
+			)
+
+			pctx := NewPipelineContext(WorkItem{FilePath: testFile}, DegradationFull, nil)
+			if err := p.Execute(context.Background(), pctx); err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+
+			chunks := pctx.AnalysisResult.Chunks
+			if len(chunks) != 2 {
+				t.Fatalf("chunks = %d, want 2", len(chunks))
+			}
+			for i, chunk := range chunks {
+				want := ""
+				if enabled {
+					want = fmt.Sprintf("Mock summary of chunk %d", i)
+				}
+				if chunk.Summary != want {
+					t.Errorf("ChunkSummaries=%v: chunk %d summary = %q, want %q", enabled, i, chunk.Summary, want)
+				}
+			}
+		}
+	})
 }
 
 func TestPipelinePersist(t *testing.T) {
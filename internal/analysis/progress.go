@@ -0,0 +1,133 @@
+package analysis
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/leefowlercu/agentic-memorizer/internal/events"
+)
+
+// progressTracker counts the current ingestion batch for progress reporting.
+// A batch starts when a file is discovered while idle and completes when every
+// discovered file has been processed or has failed. Counts cover only the
+// current batch, independent of the queue's lifetime statistics.
+type progressTracker struct {
+	mu         sync.Mutex
+	idle       bool
+	batchStart time.Time
+	discovered int64
+	processed  int64
+	failed     int64
+}
+
+// newProgressTracker creates an idle progress tracker.
+func newProgressTracker() *progressTracker {
+	return &progressTracker{idle: true}
+}
+
+// discover counts a file handed to the queue by the walker or watcher,
+// starting a new batch if the tracker is idle.
+func (t *progressTracker) discover(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.idle {
+		t.idle = false
+		t.batchStart = now
+		t.discovered, t.processed, t.failed = 0, 0, 0
+	}
+	t.discovered++
+}
+
+// finish counts a discovered file as processed or failed. Files that could
+// not be queued, or whose retry could not be queued, count as failed.
+func (t *progressTracker) finish(failed bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.idle {
+		return
+	}
+	if failed {
+		t.failed++
+	} else {
+		t.processed++
+	}
+}
+
+// WithProgressInterval sets how often ingestion progress events are published.
+// Zero disables periodic progress events.
+func WithProgressInterval(d time.Duration) QueueOption {
+	return func(q *Queue) {
+		if d >= 0 {
+			q.progressInterval = d
+		}
+	}
+}
+
+// runProgressReporter publishes progress events on the configured interval until ctx is done.
+func (q *Queue) runProgressReporter(ctx context.Context) {
+	ticker := time.NewTicker(q.progressInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			q.reportProgress(now)
+		}
+	}
+}
+
+// reportProgress publishes an IngestProgress event for the active batch, marking the
+// batch complete once nothing is pending. Nothing is published while idle.
+func (q *Queue) reportProgress(now time.Time) {
+	t := q.progress
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.idle {
+		return
+	}
+
+	discovered, processed, failed := t.discovered, t.processed, t.failed
+	done := processed + failed
+	pending := max(discovered-done, 0)
+
+	elapsed := now.Sub(t.batchStart)
+	var throughput float64
+	if elapsed > 0 {
+		throughput = float64(done) / elapsed.Seconds()
+	}
+
+	var eta time.Duration
+	if throughput > 0 {
+		eta = time.Duration(float64(pending) / throughput * float64(time.Second))
+	}
+
+	complete := pending == 0
+	if complete {
+		t.idle = true
+	}
+
+	q.bus.Publish(q.ctx, events.NewIngestProgress(
+		int(discovered),
+		int(processed),
+		int(pending),
+		int(failed),
+		throughput,
+		elapsed,
+		eta,
+		complete,
+	))
+
+	if complete {
+		q.logger.Info("ingestion batch complete",
+			"discovered", discovered,
+			"processed", processed,
+			"failed", failed,
+			"elapsed", elapsed)
+	}
+}
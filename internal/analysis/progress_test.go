@@ -0,0 +1,169 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/leefowlercu/agentic-memorizer/internal/events"
+)
+
+func TestQueueIngestProgress(t *testing.T) {
+	bus := events.NewBus()
+	defer bus.Close()
+
+	queue := NewQueue(bus, WithWorkerCount(1), WithProgressInterval(10*time.Millisecond))
+	if err := queue.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer queue.Stop(context.Background())
+
+	queue.SetProviders(&mockSemanticProvider{available: true}, &mockEmbeddingsProvider{
+		available: true,
+		embedding: []float32{0.1, 0.2},
+	})
+
+	var mu sync.Mutex
+	var progress []*events.IngestProgressEvent
+	complete := make(chan *events.IngestProgressEvent, 1)
+	unsub := bus.Subscribe(events.IngestProgress, func(e events.Event) {
+		pe, ok := e.Payload.(*events.IngestProgressEvent)
+		if !ok {
+			return
+		}
+		mu.Lock()
+		progress = append(progress, pe)
+		mu.Unlock()
+		if pe.Complete {
+			select {
+			case complete <- pe:
+			default:
+			}
+		}
+	})
+	defer unsub()
+
+	dir := t.TempDir()
+
+	// walk publishes FileDiscovered events as the walker does and waits for
+	// the batch's completion event.
+	walk := func(batch string, fileCount int) *events.IngestProgressEvent {
+		for i := range fileCount {
+			path := filepath.Join(dir, fmt.Sprintf("%s%d.txt", batch, i))
+			if err := os.WriteFile(path, []byte(fmt.Sprintf("progress content %s %d", batch, i)), 0644); err != nil {
+				t.Fatalf("write file failed: %v", err)
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatalf("stat file failed: %v", err)
+			}
+			if err := bus.Publish(context.Background(), events.NewEvent(events.FileDiscovered, &events.FileEvent{
+				Path:    path,
+				Size:    info.Size(),
+				ModTime: info.ModTime(),
+				IsNew:   true,
+			})); err != nil {
+				t.Fatalf("Publish failed: %v", err)
+			}
+		}
+
+		select {
+		case final := <-complete:
+			return final
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for %s completion progress event", batch)
+			return nil
+		}
+	}
+
+	const fileCount = 5
+	final := walk("first", fileCount)
+	if final.Discovered != fileCount || final.Processed+final.Failed != fileCount {
+		t.Errorf("final progress = %+v, want %d discovered and completed", final, fileCount)
+	}
+	if final.Pending != 0 {
+		t.Errorf("final Pending = %d, want 0", final.Pending)
+	}
+
+	mu.Lock()
+	last := -1
+	for _, pe := range progress {
+		done := pe.Processed + pe.Failed
+		if done < last {
+			t.Errorf("completed count went backwards: %d after %d", done, last)
+		}
+		if done+pe.Pending != pe.Discovered {
+			t.Errorf("progress counts inconsistent: %+v", pe)
+		}
+		last = done
+	}
+
+	// No further events are published while idle.
+	count := len(progress)
+	mu.Unlock()
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	if len(progress) != count {
+		t.Errorf("expected no progress events while idle, got %d more", len(progress)-count)
+	}
+	mu.Unlock()
+
+	// A later walk reports its own batch, not the queue's lifetime totals
+	const secondCount = 2
+	final = walk("second", secondCount)
+	if final.Discovered != secondCount || final.Processed+final.Failed != secondCount {
+		t.Errorf("second batch progress = %+v, want %d discovered and completed", final, secondCount)
+	}
+}
+
+func TestReportProgressETA(t *testing.T) {
+	bus := events.NewBus()
+	defer bus.Close()
+
+	queue := NewQueue(bus)
+	queue.ctx = context.Background()
+
+	received := make(chan *events.IngestProgressEvent, 1)
+	unsub := bus.Subscribe(events.IngestProgress, func(e events.Event) {
+		if pe, ok := e.Payload.(*events.IngestProgressEvent); ok {
+			received <- pe
+		}
+	})
+	defer unsub()
+
+	start := time.Now()
+	for range 10 {
+		queue.progress.discover(start)
+	}
+	for range 3 {
+		queue.progress.finish(false)
+	}
+	queue.progress.finish(true)
+	// Lifetime counters from earlier batches do not affect the batch's progress
+	queue.processedCount.Store(50)
+	queue.analysisFailedCount.Store(7)
+
+	queue.reportProgress(start.Add(2 * time.Second))
+
+	select {
+	case pe := <-received:
+		if pe.Pending != 6 || pe.Processed != 3 || pe.Failed != 1 {
+			t.Errorf("progress = %+v, want 6 pending, 3 processed, 1 failed", pe)
+		}
+		if pe.Throughput != 2 {
+			t.Errorf("Throughput = %g, want 2", pe.Throughput)
+		}
+		if pe.ETA != 3*time.Second {
+			t.Errorf("ETA = %v, want 3s", pe.ETA)
+		}
+		if pe.Complete {
+			t.Error("expected batch to be incomplete")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for progress event")
+	}
+}
@@ -25,8 +25,14 @@ const (
 	QueueStateRunning
 	QueueStateStopping
 	QueueStateStopped
+	QueueStatePaused
 )
 
+// started reports whether the queue has workers and accepts work.
+func (s QueueState) started() bool {
+	return s == QueueStateRunning || s == QueueStatePaused
+}
+
 // QueueStats contains statistics about queue operation.
 type QueueStats struct {
 	State               QueueState
@@ -50,6 +56,12 @@ const (
 	DegradationMetadata                        // Metadata only
 )
 
+// Default queue capacity fractions at which analysis degrades.
+const (
+	DefaultNoEmbedThreshold      = 0.80
+	DefaultMetadataOnlyThreshold = 0.95
+)
+
 // Queue manages analysis work items and workers.
 type Queue struct {
 	mu            sync.RWMutex
@@ -62,11 +74,36 @@ type Queue struct {
 	queueCapacity int
 	registry      registry.Registry
 
+	// pendingStore durably records enqueued work items; nil keeps them in memory only
+	pendingStore registry.Registry
+
 	// Pipeline configuration for workers
 	pipelineConfig *PipelineConfig
 
+	// Dry-run mode and projected embeddings cost per token
+	dryRun                bool
+	embeddingCostPerToken float64
+
+	// Per-file analysis timeout and whether partial results survive it
+	perFileTimeout          time.Duration
+	persistPartialOnTimeout bool
+
+	// Queue capacity fractions at which embeddings, then semantic analysis, are skipped
+	noEmbedThreshold      float64
+	metadataOnlyThreshold float64
+
+	// Provider rate limits shared by every worker's stages
+	rateLimiters *providers.RateLimiterManager
+
+	// Scheduling priority per file category; nil disables category ordering
+	categoryPriority map[FileCategory]int
+
+	// Ingestion progress reporting
+	progressInterval time.Duration
+	progress         *progressTracker
+
 	state    QueueState
-	workChan chan WorkItem
+	work     *workQueue
 	workers  []*Worker
 	wg       sync.WaitGroup
 	stopChan chan struct{}
@@ -74,6 +111,9 @@ type Queue struct {
 	cancelFn context.CancelFunc
 	unsubFns []func()
 
+	// resumeChan is closed on Resume; nil unless paused
+	resumeChan chan struct{}
+
 	// Stats
 	processedCount         atomic.Int64
 	analysisFailedCount    atomic.Int64
@@ -149,6 +189,35 @@ func WithPipelineConfig(cfg *PipelineConfig) QueueOption {
 	}
 }
 
+// WithDryRun marks every enqueued work item as a dry run.
+func WithDryRun(enabled bool) QueueOption {
+	return func(q *Queue) {
+		q.dryRun = enabled
+	}
+}
+
+// WithEmbeddingCostPerToken sets the per-token rate used to project embeddings cost in dry runs.
+func WithEmbeddingCostPerToken(rate float64) QueueOption {
+	return func(q *Queue) {
+		if rate >= 0 {
+			q.embeddingCostPerToken = rate
+		}
+	}
+}
+
+// WithDegradationThresholds sets the queue capacity fractions at which
+// analysis skips embeddings (noEmbed) and then semantic analysis too
+// (metadataOnly). Thresholds must satisfy 0 < noEmbed < metadataOnly <= 1;
+// invalid pairs are ignored and the defaults kept.
+func WithDegradationThresholds(noEmbed, metadataOnly float64) QueueOption {
+	return func(q *Queue) {
+		if noEmbed > 0 && noEmbed < metadataOnly && metadataOnly <= 1 {
+			q.noEmbedThreshold = noEmbed
+			q.metadataOnlyThreshold = metadataOnly
+		}
+	}
+}
+
 // NewQueue creates a new analysis queue.
 func NewQueue(bus events.Bus, opts ...QueueOption) *Queue {
 	q := &Queue{
@@ -161,6 +230,12 @@ func NewQueue(bus events.Bus, opts ...QueueOption) *Queue {
 		queueCapacity: 1000,
 		state:         QueueStateIdle,
 		errChan:       make(chan error, 1),
+
+		embeddingCostPerToken: DefaultEmbeddingCostPerToken,
+		noEmbedThreshold:      DefaultNoEmbedThreshold,
+		metadataOnlyThreshold: DefaultMetadataOnlyThreshold,
+		progress:              newProgressTracker(),
+		rateLimiters:          providers.NewRateLimiterManager(),
 	}
 
 	for _, opt := range opts {
@@ -170,6 +245,16 @@ func NewQueue(bus events.Bus, opts ...QueueOption) *Queue {
 	return q
 }
 
+// newPipeline builds a worker pipeline from the queue's pipeline config,
+// sharing the queue's provider rate limits.
+func (q *Queue) newPipeline() *Pipeline {
+	cfg := *q.pipelineConfig
+	if cfg.RateLimiters == nil {
+		cfg.RateLimiters = q.rateLimiters
+	}
+	return NewPipeline(cfg)
+}
+
 // Name returns the component name.
 func (q *Queue) Name() string {
 	return "analysis-queue"
@@ -180,7 +265,7 @@ func (q *Queue) Start(ctx context.Context) error {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
-	if q.state == QueueStateRunning || q.state == QueueStateStopping {
+	if q.state.started() || q.state == QueueStateStopping {
 		return fmt.Errorf("queue already running")
 	}
 	if len(q.unsubFns) > 0 {
@@ -189,7 +274,8 @@ func (q *Queue) Start(ctx context.Context) error {
 
 	q.ctx, q.cancelFn = context.WithCancel(ctx)
 	q.stopChan = make(chan struct{})
-	q.workChan = make(chan WorkItem, q.queueCapacity)
+	q.work = newWorkQueue(q.queueCapacity)
+	q.resumeChan = nil
 	q.state = QueueStateRunning
 
 	// Start workers
@@ -198,7 +284,7 @@ func (q *Queue) Start(ctx context.Context) error {
 		worker := NewWorker(i, q)
 		worker.SetRegistry(q.registry)
 		if q.pipelineConfig != nil {
-			worker.SetPipeline(NewPipeline(*q.pipelineConfig))
+			worker.SetPipeline(q.newPipeline())
 		}
 		q.workers[i] = worker
 		q.wg.Add(1)
@@ -208,6 +294,17 @@ func (q *Queue) Start(ctx context.Context) error {
 		}(worker)
 	}
 
+	if q.progressInterval > 0 {
+		q.wg.Add(1)
+		go func() {
+			defer q.wg.Done()
+			q.runProgressReporter(q.ctx)
+		}()
+	}
+
+	// Re-enqueue work persisted before the last stop
+	q.resumePending(q.ctx)
+
 	// Subscribe to file events
 	q.subscribeToEvents()
 
@@ -221,7 +318,7 @@ func (q *Queue) Start(ctx context.Context) error {
 // Stop gracefully shuts down the queue.
 func (q *Queue) Stop(ctx context.Context) error {
 	q.mu.Lock()
-	if q.state != QueueStateRunning {
+	if !q.state.started() {
 		unsubFns := q.unsubFns
 		q.unsubFns = nil
 		q.mu.Unlock()
@@ -262,7 +359,7 @@ func (q *Queue) Stop(ctx context.Context) error {
 	}
 
 	q.mu.Lock()
-	close(q.workChan)
+	q.work.close()
 	q.state = QueueStateStopped
 	q.mu.Unlock()
 
@@ -279,6 +376,7 @@ func (q *Queue) subscribeToEvents() {
 				FileSize:  fe.Size,
 				ModTime:   fe.ModTime,
 				EventType: WorkItemNew,
+				Priority:  fe.Priority,
 			})
 		}
 	}))
@@ -291,6 +389,7 @@ func (q *Queue) subscribeToEvents() {
 				FileSize:  fe.Size,
 				ModTime:   fe.ModTime,
 				EventType: WorkItemChanged,
+				Priority:  fe.Priority,
 			})
 		}
 	}))
@@ -301,17 +400,38 @@ func (q *Queue) Enqueue(item WorkItem) error {
 	q.mu.RLock()
 	defer q.mu.RUnlock()
 
-	if q.state != QueueStateRunning {
+	return q.enqueue(item)
+}
+
+// enqueue adds a work item to the queue. Callers hold q.mu.
+func (q *Queue) enqueue(item WorkItem) error {
+	if !q.state.started() {
 		return fmt.Errorf("queue not running")
 	}
 
-	// Non-blocking send to avoid deadlock while holding lock
-	select {
-	case q.workChan <- item:
-		return nil
-	default:
+	if q.dryRun {
+		item.DryRun = true
+	}
+	if item.Priority == 0 {
+		item.Priority = q.categoryPriorityFor(item.FilePath)
+	}
+
+	// Count new items before sending so progress never sees completions ahead of discoveries
+	isNew := item.Retries == 0
+	if isNew {
+		q.progress.discover(time.Now())
+	}
+
+	// Recorded before pushing so an item rejected by a full queue resumes on the next start
+	q.recordPending(&item)
+
+	if !q.work.push(item) {
+		if isNew {
+			q.progress.finish(true)
+		}
 		return fmt.Errorf("queue full; capacity=%d", q.queueCapacity)
 	}
+	return nil
 }
 
 // Stats returns current queue statistics.
@@ -322,7 +442,7 @@ func (q *Queue) Stats() QueueStats {
 	lastMode := q.lastDegradationMode
 	q.mu.RUnlock()
 
-	pending := len(q.workChan)
+	pending := q.work.len()
 	processed := q.processedCount.Load()
 	analysisFailed := q.analysisFailedCount.Load()
 	persistenceFailed := q.persistenceFailedCount.Load()
@@ -337,7 +457,7 @@ func (q *Queue) Stats() QueueStats {
 	mode := q.getDegradationMode(capacity)
 
 	// Detect degradation mode transitions and publish event
-	if mode != lastMode && state == QueueStateRunning {
+	if mode != lastMode && state.started() {
 		q.mu.Lock()
 		if q.lastDegradationMode != mode {
 			q.lastDegradationMode = mode
@@ -365,9 +485,9 @@ func (q *Queue) Stats() QueueStats {
 // getDegradationMode returns the current mode based on capacity.
 func (q *Queue) getDegradationMode(capacity float64) DegradationMode {
 	switch {
-	case capacity >= 0.95:
+	case capacity >= q.metadataOnlyThreshold:
 		return DegradationMetadata
-	case capacity >= 0.80:
+	case capacity >= q.noEmbedThreshold:
 		return DegradationNoEmbed
 	default:
 		return DegradationFull
@@ -383,7 +503,7 @@ func (q *Queue) SetWorkerCount(n int) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
-	if q.state != QueueStateRunning {
+	if !q.state.started() {
 		q.workerCount = n
 		return
 	}
@@ -399,7 +519,7 @@ func (q *Queue) SetWorkerCount(n int) {
 			worker := NewWorker(i, q)
 			worker.SetRegistry(q.registry)
 			if q.pipelineConfig != nil {
-				worker.SetPipeline(NewPipeline(*q.pipelineConfig))
+				worker.SetPipeline(q.newPipeline())
 			}
 			q.workers = append(q.workers, worker)
 			q.wg.Add(1)
@@ -507,17 +627,26 @@ func (q *Queue) SetCaches(semantic *cache.SemanticCache, embeddings *cache.Embed
 func (q *Queue) recordSuccess(duration time.Duration) {
 	q.processedCount.Add(1)
 	q.totalProcTime.Add(int64(duration))
+	q.progress.finish(false)
 }
 
 // recordAnalysisFailure records a failed analysis (file read or chunking error).
 func (q *Queue) recordAnalysisFailure() {
 	q.analysisFailedCount.Add(1)
+	q.progress.finish(true)
 }
 
 // recordPersistenceFailure records a failed graph persistence.
 func (q *Queue) recordPersistenceFailure() {
 	q.persistenceFailedCount.Add(1)
 	metrics.AnalysisPersistenceFailures.Inc()
+	q.progress.finish(true)
+}
+
+// recordRetryDropped counts a retry that could not be queued as failed in the
+// progress batch, without counting another analysis or persistence failure.
+func (q *Queue) recordRetryDropped() {
+	q.progress.finish(true)
 }
 
 // publishAnalysisComplete publishes a success event.
@@ -0,0 +1,50 @@
+package analysis
+
+import (
+	"github.com/leefowlercu/agentic-memorizer/internal/chunkers"
+	"github.com/leefowlercu/agentic-memorizer/internal/providers"
+)
+
+// stageLimiter returns the usage limiter shared by every stage calling p.
+// Returns nil, which never blocks, when m or p is nil.
+func stageLimiter(m *providers.RateLimiterManager, p providers.Provider) *providers.UsageLimiter {
+	if m == nil || p == nil {
+		return nil
+	}
+	// Semantic and embeddings providers may share a name but not a quota
+	return m.GetOrCreateUsage(string(p.Type())+"/"+p.Name(), p.RateLimit())
+}
+
+// embeddingsTokenBudget estimates the tokens an embeddings request for texts
+// consumes. Texts over the provider's MaxTokens are budgeted at MaxTokens.
+func embeddingsTokenBudget(provider providers.EmbeddingsProvider, texts []string) int {
+	maxTokens := provider.MaxTokens()
+	total := 0
+	for _, text := range texts {
+		total += textTokenBudget(maxTokens, text)
+	}
+	return total
+}
+
+// textTokenBudget estimates the tokens in text, capped at maxTokens when positive.
+func textTokenBudget(maxTokens int, text string) int {
+	tokens := chunkers.EstimateTokens(text)
+	if maxTokens > 0 && tokens > maxTokens {
+		return maxTokens
+	}
+	return tokens
+}
+
+// semanticTokenBudget estimates the input tokens a semantic request consumes,
+// capped at the model's input limit. Non-text inputs are budgeted from their
+// token estimate only.
+func semanticTokenBudget(provider providers.SemanticProvider, input providers.SemanticInput) int {
+	tokens := input.TokenEstimate
+	if tokens == 0 && input.Text != "" {
+		tokens = chunkers.EstimateTokens(input.Text)
+	}
+	if maxTokens := provider.Capabilities().MaxInputTokens; maxTokens > 0 && tokens > maxTokens {
+		tokens = maxTokens
+	}
+	return tokens
+}
@@ -0,0 +1,133 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/leefowlercu/agentic-memorizer/internal/providers"
+)
+
+// rateLimitedEmbeddingsProvider reports a fixed rate limit and records when it is called.
+type rateLimitedEmbeddingsProvider struct {
+	mockEmbeddingsProvider
+	limit     providers.RateLimitConfig
+	maxTokens int
+
+	mu    sync.Mutex
+	calls []time.Time
+}
+
+func (p *rateLimitedEmbeddingsProvider) RateLimit() providers.RateLimitConfig { return p.limit }
+func (p *rateLimitedEmbeddingsProvider) MaxTokens() int                       { return p.maxTokens }
+func (p *rateLimitedEmbeddingsProvider) Embed(ctx context.Context, req providers.EmbeddingsRequest) (*providers.EmbeddingsResult, error) {
+	p.mu.Lock()
+	p.calls = append(p.calls, time.Now())
+	p.mu.Unlock()
+	return p.mockEmbeddingsProvider.Embed(ctx, req)
+}
+
+func TestEmbeddingsStageRateLimitSharedAcrossWorkers(t *testing.T) {
+	// 600 requests/minute with no burst allows one call every 100ms
+	const interval = 100 * time.Millisecond
+	provider := &rateLimitedEmbeddingsProvider{
+		mockEmbeddingsProvider: mockEmbeddingsProvider{available: true, embedding: []float32{0.1}},
+		limit:                  providers.RateLimitConfig{RequestsPerMinute: 600, BurstSize: 1},
+		maxTokens:              8192,
+	}
+	limiters := providers.NewRateLimiterManager()
+
+	const workers = 4
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	start := time.Now()
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Each worker builds its own stage, as workers do per file
+			stage := NewEmbeddingsStage(provider, nil, nil, slog.Default(), WithEmbeddingsRateLimiter(stageLimiter(limiters, provider)))
+			chunks := []AnalyzedChunk{{Index: 0, Content: fmt.Sprintf("chunk %d", i), ContentHash: fmt.Sprintf("h%d", i)}}
+			if _, err := stage.Generate(context.Background(), fmt.Sprintf("/repo/file%d.go", i), chunks); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	if len(provider.calls) != workers {
+		t.Fatalf("provider called %d times, want %d", len(provider.calls), workers)
+	}
+	if elapsed := time.Since(start); elapsed < (workers-1)*interval-10*time.Millisecond {
+		t.Errorf("%d concurrent calls finished in %v, want at least %v", workers, elapsed, (workers-1)*interval)
+	}
+
+	calls := slices.Clone(provider.calls)
+	slices.SortFunc(calls, func(a, b time.Time) int { return a.Compare(b) })
+	for i := 1; i < len(calls); i++ {
+		if gap := calls[i].Sub(calls[i-1]); gap < interval-10*time.Millisecond {
+			t.Errorf("calls %d and %d were %v apart, want about %v", i-1, i, gap, interval)
+		}
+	}
+}
+
+func TestEmbeddingsStageRateLimitRespectsContext(t *testing.T) {
+	provider := &rateLimitedEmbeddingsProvider{
+		mockEmbeddingsProvider: mockEmbeddingsProvider{available: true, embedding: []float32{0.1}},
+		limit:                  providers.RateLimitConfig{RequestsPerMinute: 1, BurstSize: 1},
+		maxTokens:              8192,
+	}
+	stage := NewEmbeddingsStage(provider, nil, nil, slog.Default(), WithEmbeddingsRateLimiter(stageLimiter(providers.NewRateLimiterManager(), provider)))
+
+	if _, err := stage.Generate(context.Background(), "/repo/a.go", []AnalyzedChunk{{Index: 0, Content: "a", ContentHash: "ha"}}); err != nil {
+		t.Fatalf("first Generate failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := stage.Generate(ctx, "/repo/b.go", []AnalyzedChunk{{Index: 0, Content: "b", ContentHash: "hb"}})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Generate error = %v, want context.DeadlineExceeded", err)
+	}
+	if len(provider.calls) != 1 {
+		t.Errorf("provider called %d times, want 1", len(provider.calls))
+	}
+}
+
+func TestEmbeddingsTokenBudgetCapsAtMaxTokens(t *testing.T) {
+	provider := &rateLimitedEmbeddingsProvider{maxTokens: 50}
+	long := strings.Repeat("token ", 1000)
+
+	if got := embeddingsTokenBudget(provider, []string{long, long}); got != 100 {
+		t.Errorf("embeddingsTokenBudget = %d, want 100", got)
+	}
+	if got := embeddingsTokenBudget(provider, []string{"short"}); got < 1 || got > 50 {
+		t.Errorf("embeddingsTokenBudget(short) = %d, want within (0, 50]", got)
+	}
+}
+
+func TestStageLimiterKeyedByProviderType(t *testing.T) {
+	limiters := providers.NewRateLimiterManager()
+	embeddings := &mockEmbeddingsProvider{}
+	semantic := &mockSemanticProvider{}
+
+	if stageLimiter(limiters, embeddings) != stageLimiter(limiters, embeddings) {
+		t.Error("expected the same limiter for the same provider")
+	}
+	if stageLimiter(limiters, embeddings) == stageLimiter(limiters, semantic) {
+		t.Error("expected separate limiters for semantic and embeddings providers")
+	}
+	if stageLimiter(nil, embeddings) != nil {
+		t.Error("expected nil limiter without a manager")
+	}
+}
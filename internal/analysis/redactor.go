@@ -0,0 +1,153 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// RedactedPlaceholder replaces secret values removed by a SecretRedactor.
+const RedactedPlaceholder = "[REDACTED]"
+
+// DefaultEntropyThreshold is the Shannon entropy (bits per character) at or above
+// which a long token is treated as a secret.
+const DefaultEntropyThreshold = 4.5
+
+// DefaultEntropyMinLength is the minimum token length considered for entropy checks.
+// A token of n characters has at most log2(n) bits per character, so 23 is the
+// shortest length that can reach DefaultEntropyThreshold.
+const DefaultEntropyMinLength = 23
+
+// DefaultSecretPatterns are the built-in secret patterns. A named "prefix" group
+// is preserved in the output so assignments keep their key (e.g. "password=[REDACTED]").
+var DefaultSecretPatterns = []string{
+	// AWS access key IDs
+	`\b(?:AKIA|ASIA|AGPA|AIDA|AROA|ANPA|ANVA)[0-9A-Z]{16}\b`,
+	// PEM private key blocks (header alone when the block is split across chunks)
+	`(?s)-----BEGIN [A-Z0-9 ]*PRIVATE KEY-----(?:.*?-----END [A-Z0-9 ]*PRIVATE KEY-----)?`,
+	// Secret assignments: password=..., token: "...", api_key = '...'
+	`(?i)(?P<prefix>\b(?:password|passwd|pwd|secret|token|api[_-]?key|access[_-]?key|auth[_-]?token)\s*[:=]\s*)(?:"[^"\n]*"|'[^'\n]*'|[^\s"',;]+)`,
+}
+
+// entropyCandidate matches tokens eligible for the high-entropy check.
+var entropyCandidate = regexp.MustCompile(`[A-Za-z0-9+/=_\-]+`)
+
+// SecretRedactor replaces secrets in text with RedactedPlaceholder.
+type SecretRedactor struct {
+	patterns         []*regexp.Regexp
+	entropyThreshold float64
+	entropyMinLength int
+}
+
+// RedactorOption configures a SecretRedactor.
+type RedactorOption func(*SecretRedactor)
+
+// WithEntropyThreshold sets the entropy threshold for high-entropy tokens.
+// A threshold of zero disables the entropy check.
+func WithEntropyThreshold(threshold float64) RedactorOption {
+	return func(r *SecretRedactor) {
+		r.entropyThreshold = threshold
+	}
+}
+
+// WithEntropyMinLength sets the minimum token length for the entropy check.
+func WithEntropyMinLength(n int) RedactorOption {
+	return func(r *SecretRedactor) {
+		r.entropyMinLength = n
+	}
+}
+
+// NewSecretRedactor creates a redactor from the given regular expressions.
+// If patterns is empty, DefaultSecretPatterns is used.
+func NewSecretRedactor(patterns []string, opts ...RedactorOption) (*SecretRedactor, error) {
+	if len(patterns) == 0 {
+		patterns = DefaultSecretPatterns
+	}
+
+	r := &SecretRedactor{
+		entropyThreshold: DefaultEntropyThreshold,
+		entropyMinLength: DefaultEntropyMinLength,
+	}
+
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid secret pattern %q; %w", pattern, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r, nil
+}
+
+// Redact replaces secrets in content and returns the result with the number of redactions.
+func (r *SecretRedactor) Redact(content string) (string, int) {
+	if r == nil || content == "" {
+		return content, 0
+	}
+
+	count := 0
+	for _, re := range r.patterns {
+		matches := re.FindAllStringIndex(content, -1)
+		if len(matches) == 0 {
+			continue
+		}
+		count += len(matches)
+		content = re.ReplaceAllString(content, "${prefix}"+RedactedPlaceholder)
+	}
+
+	if r.entropyThreshold > 0 {
+		content = entropyCandidate.ReplaceAllStringFunc(content, func(token string) string {
+			if !r.isHighEntropy(token) {
+				return token
+			}
+			count++
+			return RedactedPlaceholder
+		})
+	}
+
+	return content, count
+}
+
+// isHighEntropy reports whether token looks like a random secret.
+func (r *SecretRedactor) isHighEntropy(token string) bool {
+	if len(token) < r.entropyMinLength {
+		return false
+	}
+
+	// Require mixed letters and digits to avoid flagging long identifiers
+	hasLetter := strings.IndexFunc(token, unicode.IsLetter) >= 0
+	hasDigit := strings.IndexFunc(token, unicode.IsDigit) >= 0
+	if !hasLetter || !hasDigit {
+		return false
+	}
+
+	return shannonEntropy(token) >= r.entropyThreshold
+}
+
+// shannonEntropy returns the Shannon entropy of s in bits per character.
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+
+	freq := make(map[rune]int)
+	total := 0
+	for _, c := range s {
+		freq[c]++
+		total++
+	}
+
+	var entropy float64
+	for _, n := range freq {
+		p := float64(n) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
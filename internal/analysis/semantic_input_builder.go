@@ -12,6 +12,10 @@ import (
 
 const defaultReservedOutputTokens = 4096
 
+// defaultNeighborContextTokens bounds each neighboring chunk excerpt passed
+// alongside a chunk-level input.
+const defaultNeighborContextTokens = 256
+
 // BuildSemanticInput constructs a provider-ready semantic input from the file and chunk context.
 func BuildSemanticInput(path string, fileResult *FileReadResult, chunkResult *chunkers.ChunkResult, provider providers.SemanticProvider) (providers.SemanticInput, error) {
 	if fileResult == nil {
@@ -43,6 +47,71 @@ func BuildSemanticInput(path string, fileResult *FileReadResult, chunkResult *ch
 	return buildTextSemanticInput(input, fileResult, chunkResult, caps)
 }
 
+// BuildChunkSemanticInput constructs a text input for the chunk at index,
+// including truncated neighboring chunks and the enclosing section or class.
+func BuildChunkSemanticInput(path string, chunks []chunkers.Chunk, index int, provider providers.SemanticProvider) (providers.SemanticInput, error) {
+	if index < 0 || index >= len(chunks) {
+		return providers.SemanticInput{}, fmt.Errorf("chunk index %d out of range", index)
+	}
+
+	caps := defaultSemanticCapabilities(provider)
+	chunk := chunks[index]
+
+	neighbors := &providers.SemanticNeighbors{}
+	if index > 0 {
+		neighbors.Previous = tailToBudget(chunks[index-1].Content, defaultNeighborContextTokens)
+	}
+	if index+1 < len(chunks) {
+		neighbors.Next = headToBudget(chunks[index+1].Content, defaultNeighborContextTokens)
+	}
+	if doc := chunk.Metadata.Document; doc != nil {
+		neighbors.SectionPath = doc.SectionPath
+	}
+	if code := chunk.Metadata.Code; code != nil {
+		neighbors.ClassName = code.ClassName
+	}
+
+	input := providers.SemanticInput{
+		Path:          path,
+		MIMEType:      "text/plain",
+		Type:          providers.SemanticInputText,
+		TokenEstimate: chunkers.EstimateTokens(chunk.Content),
+		Neighbors:     neighbors,
+		Meta: map[string]any{
+			"chunk_index": chunk.Index,
+		},
+	}
+
+	budget := caps.MaxInputTokens - defaultReservedOutputTokens - 2*defaultNeighborContextTokens
+	if caps.MaxInputTokens <= 0 || budget <= 0 {
+		budget = input.TokenEstimate
+	}
+
+	input.Text, input.Truncated = condenseTextToBudget(chunk.Content, budget)
+	input.Meta["token_estimate"] = input.TokenEstimate
+	input.Meta["token_budget"] = budget
+
+	return input, nil
+}
+
+// headToBudget returns the start of text within maxTokens.
+func headToBudget(text string, maxTokens int) string {
+	maxChars := maxTokens * 4
+	if len(text) <= maxChars {
+		return text
+	}
+	return strings.ToValidUTF8(text[:maxChars], "") + "\n[...truncated...]"
+}
+
+// tailToBudget returns the end of text within maxTokens.
+func tailToBudget(text string, maxTokens int) string {
+	maxChars := maxTokens * 4
+	if len(text) <= maxChars {
+		return text
+	}
+	return "[...truncated...]\n" + strings.ToValidUTF8(text[len(text)-maxChars:], "")
+}
+
 func buildPDFSemanticInput(input providers.SemanticInput, fileResult *FileReadResult, chunkResult *chunkers.ChunkResult, caps providers.SemanticCapabilities) (providers.SemanticInput, error) {
 	pageCount := extractPDFPageCount(chunkResult)
 	input.Meta["page_count"] = pageCount
@@ -16,13 +16,16 @@ type FileReaderStage interface {
 // ChunkerStageInterface defines the interface for the chunking stage.
 // It splits file content into semantic chunks using format-specific chunkers.
 type ChunkerStageInterface interface {
-	Chunk(ctx context.Context, content []byte, mimeType, language string) (*chunkers.ChunkResult, error)
+	Chunk(ctx context.Context, path string, content []byte, mimeType, language string) (*chunkers.ChunkResult, error)
 }
 
 // SemanticStageInterface defines the interface for the semantic analysis stage.
 // It analyzes file-level inputs using AI providers to extract summaries, topics, entities, etc.
 type SemanticStageInterface interface {
 	Analyze(ctx context.Context, input providers.SemanticInput, contentHash string) (*SemanticResult, error)
+
+	// AnalyzeChunk analyzes the chunk at index with its neighbors as context.
+	AnalyzeChunk(ctx context.Context, path string, chunks []chunkers.Chunk, index int) (*SemanticResult, error)
 }
 
 // EmbeddingsStageInterface defines the interface for the embeddings generation stage.
@@ -3,6 +3,7 @@ package analysis
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/leefowlercu/agentic-memorizer/internal/chunkers"
 )
@@ -17,15 +18,28 @@ func NewChunkerStage(registry *chunkers.Registry) *ChunkerStage {
 	return &ChunkerStage{registry: registry}
 }
 
-// Chunk splits content using the configured chunker registry.
-func (s *ChunkerStage) Chunk(ctx context.Context, content []byte, mimeType, language string) (*chunkers.ChunkResult, error) {
+// Chunk splits the content of the file at path using the configured chunker
+// registry. File references, such as AsciiDoc includes, resolve relative to
+// the file's directory.
+func (s *ChunkerStage) Chunk(ctx context.Context, path string, content []byte, mimeType, language string) (*chunkers.ChunkResult, error) {
 	if s.registry == nil {
 		return nil, fmt.Errorf("chunker registry not configured")
 	}
 
-	opts := chunkers.DefaultChunkOptions()
-	opts.MIMEType = mimeType
-	opts.Language = language
+	return s.registry.ChunkFile(ctx, path, content, mimeType, language)
+}
 
-	return s.registry.Chunk(ctx, content, opts)
+// mergeChunkReferences appends references found while chunking, such as
+// resolved includes, to refs, skipping duplicates.
+func mergeChunkReferences(refs []Reference, chunkResult *chunkers.ChunkResult) []Reference {
+	if chunkResult == nil {
+		return refs
+	}
+	for _, ref := range chunkResult.References {
+		merged := Reference{Type: ref.Type, Target: ref.Target}
+		if !slices.Contains(refs, merged) {
+			refs = append(refs, merged)
+		}
+	}
+	return refs
 }
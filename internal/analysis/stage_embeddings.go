@@ -6,6 +6,9 @@ import (
 	"log/slog"
 
 	"github.com/leefowlercu/agentic-memorizer/internal/cache"
+	"github.com/leefowlercu/agentic-memorizer/internal/chunkers"
+	"github.com/leefowlercu/agentic-memorizer/internal/graph"
+	"github.com/leefowlercu/agentic-memorizer/internal/metrics"
 	"github.com/leefowlercu/agentic-memorizer/internal/providers"
 	"github.com/leefowlercu/agentic-memorizer/internal/registry"
 )
@@ -16,16 +19,69 @@ type EmbeddingsStage struct {
 	cache    *cache.EmbeddingsCache
 	registry registry.Registry
 	logger   *slog.Logger
+
+	// Per-1k-token prices used to estimate provider cost
+	tokenRates metrics.TokenRates
+
+	// How chunks over the provider's token limit are handled
+	truncation providers.TruncationStrategy
+
+	// Provider rate limits shared across workers; nil does not limit
+	limiter *providers.UsageLimiter
+
+	// Graph consulted for embeddings already stored for a chunk's content
+	stored graph.Graph
+}
+
+// EmbeddingsStageOption configures an EmbeddingsStage.
+type EmbeddingsStageOption func(*EmbeddingsStage)
+
+// WithEmbeddingsTokenRates sets the per-1k-token prices used to estimate
+// embeddings provider cost. Only the input rate applies.
+func WithEmbeddingsTokenRates(rates metrics.TokenRates) EmbeddingsStageOption {
+	return func(s *EmbeddingsStage) {
+		s.tokenRates = rates
+	}
+}
+
+// WithEmbeddingsTruncation sets how chunks over the provider's token limit
+// are handled. The default sends chunks unchanged.
+func WithEmbeddingsTruncation(strategy providers.TruncationStrategy) EmbeddingsStageOption {
+	return func(s *EmbeddingsStage) {
+		s.truncation = strategy
+	}
+}
+
+// WithEmbeddingsRateLimiter makes provider calls wait for capacity under the
+// provider's request and token rate limits.
+func WithEmbeddingsRateLimiter(l *providers.UsageLimiter) EmbeddingsStageOption {
+	return func(s *EmbeddingsStage) {
+		s.limiter = l
+	}
+}
+
+// WithEmbeddingsGraph reuses embeddings already stored in the graph for
+// chunks with the same content hash instead of calling the provider.
+func WithEmbeddingsGraph(g graph.Graph) EmbeddingsStageOption {
+	return func(s *EmbeddingsStage) {
+		s.stored = g
+	}
 }
 
 // NewEmbeddingsStage creates an embeddings stage.
-func NewEmbeddingsStage(provider providers.EmbeddingsProvider, cache *cache.EmbeddingsCache, reg registry.Registry, logger *slog.Logger) *EmbeddingsStage {
-	return &EmbeddingsStage{
+func NewEmbeddingsStage(provider providers.EmbeddingsProvider, cache *cache.EmbeddingsCache, reg registry.Registry, logger *slog.Logger, opts ...EmbeddingsStageOption) *EmbeddingsStage {
+	s := &EmbeddingsStage{
 		provider: provider,
 		cache:    cache,
 		registry: reg,
 		logger:   logger,
 	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
 // Generate runs embeddings generation and updates registry state.
@@ -36,10 +92,11 @@ func (s *EmbeddingsStage) Generate(ctx context.Context, path string, analyzedChu
 	}
 
 	logger := loggerOrDefault(s.logger)
-	fileEmbedding, embeddingsErr := generateEmbeddings(ctx, s.provider, s.cache, logger, analyzedChunks)
+	fileEmbedding, tokens, embeddingsErr := generateEmbeddings(ctx, s.provider, s.cache, s.stored, logger, s.truncation, s.limiter, analyzedChunks)
+	metrics.RecordTokenUsage("embeddings", s.provider.Name(), s.provider.ModelName(), tokens, 0, s.tokenRates)
 
 	if s.registry != nil {
-		if err := s.registry.UpdateEmbeddingsState(ctx, path, embeddingsErr); err != nil {
+		if err := s.registry.UpdateEmbeddingsState(ctx, path, s.provider.ModelName(), embeddingsErr); err != nil {
 			logger.Warn("failed to update embeddings state", "path", path, "error", err)
 		}
 	}
@@ -49,28 +106,43 @@ func (s *EmbeddingsStage) Generate(ctx context.Context, path string, analyzedChu
 
 // generateEmbeddings generates embeddings for pre-built analyzed chunks.
 // It modifies analyzedChunks in place to add embeddings to each chunk.
-// Returns the file-level average embedding and any error.
-func generateEmbeddings(ctx context.Context, provider providers.EmbeddingsProvider, embCache *cache.EmbeddingsCache, logger *slog.Logger, analyzedChunks []AnalyzedChunk) ([]float32, error) {
+// Chunks found in embCache or stored in the graph are not sent to the provider.
+// Returns the file-level average embedding, the input tokens sent to the
+// provider (reported, else estimated), and any error. Oversize chunks are
+// handled per truncation before they are sent, and provider calls wait on
+// limiter.
+func generateEmbeddings(ctx context.Context, provider providers.EmbeddingsProvider, embCache *cache.EmbeddingsCache, stored graph.Graph, logger *slog.Logger, truncation providers.TruncationStrategy, limiter *providers.UsageLimiter, analyzedChunks []AnalyzedChunk) ([]float32, int, error) {
 	if len(analyzedChunks) == 0 {
-		return nil, nil
+		return nil, 0, nil
 	}
 
 	logger = loggerOrDefault(logger)
 	var needsEmbedding []int
+	tokens := 0
 
 	// Check cache for existing embeddings
+	skipped := 0
 	for i := range analyzedChunks {
+		if analyzedChunks[i].SkipEmbedding {
+			skipped++
+			continue
+		}
 		if embCache != nil {
 			cached, err := embCache.Get(analyzedChunks[i].ContentHash, analyzedChunks[i].Index)
 			if err == nil {
-				analyzedChunks[i].Embedding = cached.Embedding
+				setChunkEmbedding(&analyzedChunks[i], provider, cached.Embedding)
 				continue
 			}
 		}
+		if embedding := storedEmbedding(ctx, stored, provider, logger, analyzedChunks[i].ContentHash); embedding != nil {
+			setChunkEmbedding(&analyzedChunks[i], provider, embedding)
+			cacheEmbedding(embCache, logger, analyzedChunks[i], embedding)
+			continue
+		}
 		needsEmbedding = append(needsEmbedding, i)
 	}
 
-	cacheHits := len(analyzedChunks) - len(needsEmbedding)
+	cacheHits := len(analyzedChunks) - len(needsEmbedding) - skipped
 	if cacheHits > 0 {
 		logger.Debug("embeddings cache hits",
 			"hits", cacheHits,
@@ -84,44 +156,46 @@ func generateEmbeddings(ctx context.Context, provider providers.EmbeddingsProvid
 
 		texts := make([]string, len(needsEmbedding))
 		for j, idx := range needsEmbedding {
-			texts[j] = analyzedChunks[idx].Content
+			text, _, err := providers.TruncateEmbeddingsInput(provider, providers.EmbeddingsRequest{
+				Content:    analyzedChunks[idx].Content,
+				Truncation: truncation,
+			})
+			if err != nil {
+				return nil, 0, fmt.Errorf("chunk %d; %w", analyzedChunks[idx].Index, err)
+			}
+			texts[j] = text
 		}
 
-		var embeddings []providers.EmbeddingsBatchResult
-		var err error
+		byIndex := make(map[int][]float32, len(texts))
+		for _, batch := range embeddingsBatches(provider, texts) {
+			embeddings, err := embedBatch(ctx, provider, limiter, texts[batch.start:batch.end])
+			if err != nil {
+				return nil, 0, err
+			}
 
-		if len(texts) == 1 {
-			req := providers.EmbeddingsRequest{Content: texts[0]}
-			result, e := provider.Embed(ctx, req)
-			if e != nil {
-				return nil, fmt.Errorf("embedding failed; %w", e)
+			for _, e := range embeddings {
+				tokens += e.TokensUsed
 			}
-			embeddings = []providers.EmbeddingsBatchResult{{
-				Index:     0,
-				Embedding: result.Embedding,
-			}}
-		} else {
-			embeddings, err = provider.EmbedBatch(ctx, texts)
-			if err != nil {
-				return nil, fmt.Errorf("batch embeddings failed; %w", err)
+			for k, embedding := range alignBatchResults(logger, embeddings, batch.end-batch.start) {
+				byIndex[batch.start+k] = embedding
+			}
+		}
+		if tokens == 0 {
+			for _, text := range texts {
+				tokens += chunkers.EstimateTokens(text)
 			}
 		}
 
-		for j, emb := range embeddings {
-			idx := needsEmbedding[j]
-			analyzedChunks[idx].Embedding = emb.Embedding
-
-			if embCache != nil {
-				cacheResult := &providers.EmbeddingsResult{
-					Embedding:  emb.Embedding,
-					Dimensions: len(emb.Embedding),
-				}
-				if err := embCache.Set(analyzedChunks[idx].ContentHash, analyzedChunks[idx].Index, cacheResult); err != nil {
-					logger.Warn("embeddings cache write error",
-						"chunk", idx,
-						"error", err)
+		for j, idx := range needsEmbedding {
+			embedding, ok := byIndex[j]
+			if !ok {
+				embedding = retryMissingEmbedding(ctx, provider, limiter, logger, texts[j], analyzedChunks[idx].Index)
+				if embedding == nil {
+					continue
 				}
 			}
+			setChunkEmbedding(&analyzedChunks[idx], provider, embedding)
+			cacheEmbedding(embCache, logger, analyzedChunks[idx], embedding)
 		}
 	}
 
@@ -136,5 +210,166 @@ func generateEmbeddings(ctx context.Context, provider providers.EmbeddingsProvid
 	}
 
 	fileEmbedding := averageEmbeddings(allEmbeddings)
-	return fileEmbedding, nil
+	return fileEmbedding, tokens, nil
+}
+
+// storedEmbedding returns the embedding stored in the graph for contentHash by
+// the provider's model at the current embeddings version, or nil if there is
+// none or it does not match the provider's dimensions. Lookup errors are
+// logged and treated as misses.
+func storedEmbedding(ctx context.Context, stored graph.Graph, provider providers.EmbeddingsProvider, logger *slog.Logger, contentHash string) []float32 {
+	if stored == nil || contentHash == "" {
+		return nil
+	}
+
+	embedding, err := stored.GetEmbedding(ctx, contentHash, provider.Name(), provider.ModelName(), cache.EmbeddingsCacheVersion)
+	if err != nil {
+		logger.Debug("stored embedding lookup failed", "content_hash", contentHash, "error", err)
+		return nil
+	}
+	if len(embedding) == 0 {
+		return nil
+	}
+
+	// Vectors stored under a model name whose dimensions changed are not reused
+	if dims := provider.Dimensions(); dims > 0 && len(embedding) != dims {
+		return nil
+	}
+	return embedding
+}
+
+// setChunkEmbedding sets a chunk's embedding and records the provider and
+// model that produced it.
+func setChunkEmbedding(chunk *AnalyzedChunk, provider providers.EmbeddingsProvider, embedding []float32) {
+	chunk.Embedding = embedding
+	chunk.EmbeddingProvider = provider.Name()
+	chunk.EmbeddingModel = provider.ModelName()
+}
+
+// cacheEmbedding writes a chunk's embedding to embCache, if set.
+func cacheEmbedding(embCache *cache.EmbeddingsCache, logger *slog.Logger, chunk AnalyzedChunk, embedding []float32) {
+	if embCache == nil {
+		return
+	}
+	cacheResult := &providers.EmbeddingsResult{
+		Embedding:  embedding,
+		Dimensions: len(embedding),
+	}
+	if err := embCache.Set(chunk.ContentHash, chunk.Index, cacheResult); err != nil {
+		logger.Warn("embeddings cache write error",
+			"chunk", chunk.Index,
+			"error", err)
+	}
+}
+
+// defaultEmbeddingsBatchInputs caps the texts per EmbedBatch call for
+// providers that do not declare batch limits.
+const defaultEmbeddingsBatchInputs = 100
+
+// textRange is a half-open range of indices into a slice of texts.
+type textRange struct {
+	start, end int
+}
+
+// embeddingsBatches splits texts into consecutive batches within the
+// provider's batch limits. Token totals are estimated, with each text counted
+// at no more than the provider's MaxTokens. A text over the batch token limit
+// on its own gets a batch of its own.
+func embeddingsBatches(provider providers.EmbeddingsProvider, texts []string) []textRange {
+	limits := providers.EmbeddingsBatchLimits{MaxInputs: defaultEmbeddingsBatchInputs}
+	if limited, ok := provider.(providers.BatchLimitedEmbeddingsProvider); ok {
+		limits = limited.BatchLimits()
+	}
+
+	var batches []textRange
+	start, batchTokens := 0, 0
+	for i, text := range texts {
+		tokens := textTokenBudget(provider.MaxTokens(), text)
+		full := limits.MaxInputs > 0 && i-start >= limits.MaxInputs
+		overBudget := limits.MaxTokens > 0 && batchTokens+tokens > limits.MaxTokens
+		if i > start && (full || overBudget) {
+			batches = append(batches, textRange{start: start, end: i})
+			start, batchTokens = i, 0
+		}
+		batchTokens += tokens
+	}
+	if start < len(texts) {
+		batches = append(batches, textRange{start: start, end: len(texts)})
+	}
+	return batches
+}
+
+// embedBatch embeds one batch of texts once the rate limiter has capacity,
+// using Embed for a single text and EmbedBatch otherwise.
+func embedBatch(ctx context.Context, provider providers.EmbeddingsProvider, limiter *providers.UsageLimiter, texts []string) ([]providers.EmbeddingsBatchResult, error) {
+	if err := limiter.Wait(ctx, embeddingsTokenBudget(provider, texts)); err != nil {
+		return nil, fmt.Errorf("embeddings rate limit wait failed; %w", err)
+	}
+
+	if len(texts) == 1 {
+		result, err := provider.Embed(ctx, providers.EmbeddingsRequest{Content: texts[0]})
+		if err != nil {
+			return nil, fmt.Errorf("embedding failed; %w", err)
+		}
+		return []providers.EmbeddingsBatchResult{{
+			Index:      0,
+			Embedding:  result.Embedding,
+			TokensUsed: result.TokensUsed,
+		}}, nil
+	}
+
+	embeddings, err := provider.EmbedBatch(ctx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("batch embeddings failed; %w", err)
+	}
+	return embeddings, nil
+}
+
+// alignBatchResults maps batch results to input positions by their Index rather than
+// by position, so out-of-order results stay aligned with their texts.
+// Results with out-of-range or duplicate indices, or empty embeddings, are dropped.
+func alignBatchResults(logger *slog.Logger, results []providers.EmbeddingsBatchResult, count int) map[int][]float32 {
+	byIndex := make(map[int][]float32, len(results))
+	for _, r := range results {
+		if r.Index < 0 || r.Index >= count {
+			logger.Warn("ignoring batch embedding with out-of-range index",
+				"index", r.Index,
+				"inputs", count)
+			continue
+		}
+		if _, dup := byIndex[r.Index]; dup {
+			logger.Warn("ignoring duplicate batch embedding", "index", r.Index)
+			continue
+		}
+		if len(r.Embedding) == 0 {
+			continue
+		}
+		byIndex[r.Index] = r.Embedding
+	}
+
+	if len(byIndex) < count {
+		logger.Warn("batch embeddings incomplete",
+			"expected", count,
+			"received", len(byIndex))
+	}
+	return byIndex
+}
+
+// retryMissingEmbedding embeds a single text that was missing from a batch result.
+// Returns nil if the retry fails, leaving the chunk without an embedding.
+func retryMissingEmbedding(ctx context.Context, provider providers.EmbeddingsProvider, limiter *providers.UsageLimiter, logger *slog.Logger, text string, chunkIndex int) []float32 {
+	if err := limiter.Wait(ctx, embeddingsTokenBudget(provider, []string{text})); err != nil {
+		logger.Warn("embedding missing from batch result; chunk left without embedding",
+			"chunk", chunkIndex,
+			"error", err)
+		return nil
+	}
+	result, err := provider.Embed(ctx, providers.EmbeddingsRequest{Content: text})
+	if err != nil || result == nil || len(result.Embedding) == 0 {
+		logger.Warn("embedding missing from batch result; chunk left without embedding",
+			"chunk", chunkIndex,
+			"error", err)
+		return nil
+	}
+	return result.Embedding
 }
@@ -0,0 +1,461 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/leefowlercu/agentic-memorizer/internal/cache"
+	"github.com/leefowlercu/agentic-memorizer/internal/chunkers"
+	"github.com/leefowlercu/agentic-memorizer/internal/graph"
+	"github.com/leefowlercu/agentic-memorizer/internal/ingest"
+	"github.com/leefowlercu/agentic-memorizer/internal/providers"
+)
+
+// unorderedBatchProvider returns batch results in reverse order and drops one index.
+// Each embedding encodes the length of its input text so alignment can be checked.
+type unorderedBatchProvider struct {
+	mockEmbeddingsProvider
+	dropIndex   int
+	embedErr    error
+	embedCalled []string
+}
+
+func (p *unorderedBatchProvider) Embed(ctx context.Context, req providers.EmbeddingsRequest) (*providers.EmbeddingsResult, error) {
+	p.embedCalled = append(p.embedCalled, req.Content)
+	if p.embedErr != nil {
+		return nil, p.embedErr
+	}
+	return &providers.EmbeddingsResult{Embedding: []float32{float32(len(req.Content))}, Dimensions: 1}, nil
+}
+
+func (p *unorderedBatchProvider) EmbedBatch(ctx context.Context, texts []string) ([]providers.EmbeddingsBatchResult, error) {
+	var results []providers.EmbeddingsBatchResult
+	for i := len(texts) - 1; i >= 0; i-- {
+		if i == p.dropIndex {
+			continue
+		}
+		results = append(results, providers.EmbeddingsBatchResult{
+			Index:     i,
+			Embedding: []float32{float32(len(texts[i]))},
+		})
+	}
+	return results, nil
+}
+
+func TestGenerateEmbeddingsBatchAlignment(t *testing.T) {
+	newChunks := func() []AnalyzedChunk {
+		return []AnalyzedChunk{
+			{Index: 0, Content: "a", ContentHash: "h0"},
+			{Index: 1, Content: "bb", ContentHash: "h1"},
+			{Index: 2, Content: "ccc", ContentHash: "h2"},
+			{Index: 3, Content: "dddd", ContentHash: "h3"},
+		}
+	}
+
+	t.Run("ReordersAndRetriesMissing", func(t *testing.T) {
+		provider := &unorderedBatchProvider{mockEmbeddingsProvider: mockEmbeddingsProvider{available: true}, dropIndex: 2}
+		chunks := newChunks()
+
+		if _, _, err := generateEmbeddings(context.Background(), provider, nil, nil, slog.Default(), "", nil, chunks); err != nil {
+			t.Fatalf("generateEmbeddings failed: %v", err)
+		}
+
+		for i, chunk := range chunks {
+			if len(chunk.Embedding) != 1 || chunk.Embedding[0] != float32(len(chunk.Content)) {
+				t.Errorf("chunk %d embedding = %v, want [%d]", i, chunk.Embedding, len(chunk.Content))
+			}
+		}
+		if len(provider.embedCalled) != 1 || provider.embedCalled[0] != "ccc" {
+			t.Errorf("individual retries = %v, want [ccc]", provider.embedCalled)
+		}
+	})
+
+	t.Run("LeavesGapWhenRetryFails", func(t *testing.T) {
+		provider := &unorderedBatchProvider{
+			mockEmbeddingsProvider: mockEmbeddingsProvider{available: true},
+			dropIndex:              1,
+			embedErr:               errors.New("retry failed"),
+		}
+		chunks := newChunks()
+
+		fileEmbedding, _, err := generateEmbeddings(context.Background(), provider, nil, nil, slog.Default(), "", nil, chunks)
+		if err != nil {
+			t.Fatalf("generateEmbeddings failed: %v", err)
+		}
+		if fileEmbedding == nil {
+			t.Error("expected file embedding from remaining chunks")
+		}
+
+		if chunks[1].Embedding != nil {
+			t.Errorf("chunk 1 embedding = %v, want nil", chunks[1].Embedding)
+		}
+		for _, i := range []int{0, 2, 3} {
+			if len(chunks[i].Embedding) != 1 || chunks[i].Embedding[0] != float32(len(chunks[i].Content)) {
+				t.Errorf("chunk %d embedding = %v, want [%d]", i, chunks[i].Embedding, len(chunks[i].Content))
+			}
+		}
+	})
+}
+
+// limitedEmbeddingsProvider has a small token limit and records the text it is sent.
+type limitedEmbeddingsProvider struct {
+	mockEmbeddingsProvider
+	maxTokens int
+	sent      []string
+}
+
+func (p *limitedEmbeddingsProvider) MaxTokens() int { return p.maxTokens }
+
+func (p *limitedEmbeddingsProvider) Embed(ctx context.Context, req providers.EmbeddingsRequest) (*providers.EmbeddingsResult, error) {
+	p.sent = append(p.sent, req.Content)
+	return &providers.EmbeddingsResult{Embedding: []float32{1}, Dimensions: 1}, nil
+}
+
+func TestGenerateEmbeddingsTruncation(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("BEGIN ")
+	for i := range 200 {
+		fmt.Fprintf(&b, "word%d ", i)
+	}
+	b.WriteString("END")
+	content := b.String()
+	const maxTokens = 50
+
+	tests := []struct {
+		strategy  providers.TruncationStrategy
+		wantErr   bool
+		wantStart bool
+		wantEnd   bool
+	}{
+		{strategy: providers.TruncationError, wantErr: true},
+		{strategy: providers.TruncationEnd, wantStart: true},
+		{strategy: providers.TruncationMiddle, wantStart: true, wantEnd: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			provider := &limitedEmbeddingsProvider{
+				mockEmbeddingsProvider: mockEmbeddingsProvider{available: true},
+				maxTokens:              maxTokens,
+			}
+			chunks := []AnalyzedChunk{{Index: 0, Content: content, ContentHash: "h0"}}
+
+			_, _, err := generateEmbeddings(context.Background(), provider, nil, nil, slog.Default(), tt.strategy, nil, chunks)
+			if tt.wantErr {
+				if !errors.Is(err, providers.ErrInputTooLong) {
+					t.Fatalf("error = %v, want ErrInputTooLong", err)
+				}
+				if len(provider.sent) != 0 {
+					t.Errorf("sent %d inputs, want none", len(provider.sent))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("generateEmbeddings failed: %v", err)
+			}
+			if len(provider.sent) != 1 {
+				t.Fatalf("sent %d inputs, want 1", len(provider.sent))
+			}
+
+			sent := provider.sent[0]
+			if tokens := chunkers.CountTokens(sent); tokens > maxTokens {
+				t.Errorf("sent %d tokens, want at most %d", tokens, maxTokens)
+			}
+			if len(sent) >= len(content) {
+				t.Errorf("sent %d bytes, want fewer than %d", len(sent), len(content))
+			}
+			if got := strings.HasPrefix(sent, "BEGIN "); got != tt.wantStart {
+				t.Errorf("keeps start = %v, want %v", got, tt.wantStart)
+			}
+			if got := strings.HasSuffix(sent, " END"); got != tt.wantEnd {
+				t.Errorf("keeps end = %v, want %v", got, tt.wantEnd)
+			}
+			if got := strings.Contains(sent, "\n...\n"); got != (tt.strategy == providers.TruncationMiddle) {
+				t.Errorf("contains middle marker = %v", got)
+			}
+		})
+	}
+}
+
+// batchRecordingProvider records each batch it is sent. Each embedding encodes
+// the length of its input text so alignment can be checked.
+type batchRecordingProvider struct {
+	mockEmbeddingsProvider
+	batches    [][]string
+	embedCalls int
+}
+
+func (p *batchRecordingProvider) Embed(ctx context.Context, req providers.EmbeddingsRequest) (*providers.EmbeddingsResult, error) {
+	p.embedCalls++
+	return &providers.EmbeddingsResult{Embedding: []float32{float32(len(req.Content))}, Dimensions: 1}, nil
+}
+
+func (p *batchRecordingProvider) EmbedBatch(ctx context.Context, texts []string) ([]providers.EmbeddingsBatchResult, error) {
+	p.batches = append(p.batches, texts)
+	results := make([]providers.EmbeddingsBatchResult, len(texts))
+	for i, text := range texts {
+		results[i] = providers.EmbeddingsBatchResult{Index: i, Embedding: []float32{float32(len(text))}}
+	}
+	return results, nil
+}
+
+// batchLimitedProvider is a batchRecordingProvider that declares batch limits.
+type batchLimitedProvider struct {
+	batchRecordingProvider
+	limits providers.EmbeddingsBatchLimits
+}
+
+func (p *batchLimitedProvider) BatchLimits() providers.EmbeddingsBatchLimits { return p.limits }
+
+// batchTestChunks returns n chunks whose contents have distinct lengths.
+func batchTestChunks(n int) []AnalyzedChunk {
+	chunks := make([]AnalyzedChunk, n)
+	for i := range chunks {
+		chunks[i] = AnalyzedChunk{Index: i, Content: strings.Repeat("x", i+1), ContentHash: fmt.Sprintf("h%d", i)}
+	}
+	return chunks
+}
+
+func assertChunkEmbeddingsAligned(t *testing.T, chunks []AnalyzedChunk) {
+	t.Helper()
+	for i, chunk := range chunks {
+		if len(chunk.Embedding) != 1 || chunk.Embedding[0] != float32(len(chunk.Content)) {
+			t.Errorf("chunk %d embedding = %v, want [%d]", i, chunk.Embedding, len(chunk.Content))
+		}
+	}
+}
+
+func TestEmbeddingsStageGenerateBatchesChunks(t *testing.T) {
+	provider := &batchRecordingProvider{mockEmbeddingsProvider: mockEmbeddingsProvider{available: true}}
+	stage := NewEmbeddingsStage(provider, nil, nil, slog.Default())
+	chunks := batchTestChunks(5)
+
+	fileEmbedding, err := stage.Generate(context.Background(), "/repo/file.go", chunks)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	if len(provider.batches) != 1 || len(provider.batches[0]) != len(chunks) {
+		t.Fatalf("EmbedBatch batches = %v, want one batch of %d", provider.batches, len(chunks))
+	}
+	if provider.embedCalls != 0 {
+		t.Errorf("Embed called %d times, want 0", provider.embedCalls)
+	}
+	assertChunkEmbeddingsAligned(t, chunks)
+	if len(fileEmbedding) != 1 {
+		t.Errorf("file embedding = %v, want one dimension", fileEmbedding)
+	}
+}
+
+func TestGenerateEmbeddingsRespectsBatchLimits(t *testing.T) {
+	t.Run("MaxInputs", func(t *testing.T) {
+		provider := &batchLimitedProvider{
+			batchRecordingProvider: batchRecordingProvider{mockEmbeddingsProvider: mockEmbeddingsProvider{available: true}},
+			limits:                 providers.EmbeddingsBatchLimits{MaxInputs: 3},
+		}
+		chunks := batchTestChunks(7)
+
+		if _, _, err := generateEmbeddings(context.Background(), provider, nil, nil, slog.Default(), "", nil, chunks); err != nil {
+			t.Fatalf("generateEmbeddings failed: %v", err)
+		}
+
+		// 7 texts in batches of at most 3; the last single text goes through Embed
+		if len(provider.batches) != 2 || len(provider.batches[0]) != 3 || len(provider.batches[1]) != 3 {
+			t.Errorf("batch sizes = %v, want [3 3]", batchSizes(provider.batches))
+		}
+		if provider.embedCalls != 1 {
+			t.Errorf("Embed called %d times, want 1", provider.embedCalls)
+		}
+		assertChunkEmbeddingsAligned(t, chunks)
+	})
+
+	t.Run("DefaultMaxInputs", func(t *testing.T) {
+		provider := &batchRecordingProvider{mockEmbeddingsProvider: mockEmbeddingsProvider{available: true}}
+		chunks := batchTestChunks(defaultEmbeddingsBatchInputs + 2)
+
+		if _, _, err := generateEmbeddings(context.Background(), provider, nil, nil, slog.Default(), "", nil, chunks); err != nil {
+			t.Fatalf("generateEmbeddings failed: %v", err)
+		}
+
+		if got := batchSizes(provider.batches); len(got) != 2 || got[0] != defaultEmbeddingsBatchInputs || got[1] != 2 {
+			t.Errorf("batch sizes = %v, want [%d 2]", got, defaultEmbeddingsBatchInputs)
+		}
+		assertChunkEmbeddingsAligned(t, chunks)
+	})
+}
+
+func TestEmbeddingsBatches(t *testing.T) {
+	word := "word "
+	texts := []string{
+		strings.Repeat(word, 10),
+		strings.Repeat(word, 10),
+		strings.Repeat(word, 10),
+		strings.Repeat(word, 200),
+		strings.Repeat(word, 10),
+	}
+	tokens := make([]int, len(texts))
+	for i, text := range texts {
+		tokens[i] = chunkers.EstimateTokens(text)
+	}
+
+	provider := &batchLimitedProvider{
+		batchRecordingProvider: batchRecordingProvider{mockEmbeddingsProvider: mockEmbeddingsProvider{available: true}},
+		// Room for the first two short texts but not a third
+		limits: providers.EmbeddingsBatchLimits{MaxTokens: tokens[0] + tokens[1] + tokens[2] - 1},
+	}
+
+	got := embeddingsBatches(provider, texts)
+	want := []textRange{{0, 2}, {2, 3}, {3, 4}, {4, 5}}
+	if len(got) != len(want) {
+		t.Fatalf("embeddingsBatches = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("batch %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	if got := embeddingsBatches(provider, nil); len(got) != 0 {
+		t.Errorf("embeddingsBatches(nil) = %v, want none", got)
+	}
+}
+
+func batchSizes(batches [][]string) []int {
+	sizes := make([]int, len(batches))
+	for i, batch := range batches {
+		sizes[i] = len(batch)
+	}
+	return sizes
+}
+
+func TestEmbeddingsStageGenerateReusesStoredEmbeddings(t *testing.T) {
+	ctx := context.Background()
+	mockGraph := &mockGraphForPersistence{connected: true}
+	provider := &batchRecordingProvider{mockEmbeddingsProvider: mockEmbeddingsProvider{available: true}}
+	stage := NewEmbeddingsStage(provider, nil, nil, slog.Default(), WithEmbeddingsGraph(mockGraph))
+
+	chunks := batchTestChunks(3)
+	if _, err := stage.Generate(ctx, "/repo/file.go", chunks); err != nil {
+		t.Fatalf("first Generate failed: %v", err)
+	}
+	if len(provider.batches) != 1 {
+		t.Fatalf("first Generate made %d batch calls, want 1", len(provider.batches))
+	}
+
+	result := &AnalysisResult{
+		FilePath:    "/repo/file.go",
+		ContentHash: "file-hash",
+		IngestMode:  ingest.ModeChunk,
+		Chunks:      chunks,
+	}
+	if err := NewPersistenceStage(mockGraph).Persist(ctx, result); err != nil {
+		t.Fatalf("Persist failed: %v", err)
+	}
+
+	// Re-ingest the same content, e.g. after only the mtime changed
+	provider.batches, provider.embedCalls = nil, 0
+	again := batchTestChunks(3)
+	fileEmbedding, err := stage.Generate(ctx, "/repo/file.go", again)
+	if err != nil {
+		t.Fatalf("second Generate failed: %v", err)
+	}
+
+	if len(provider.batches) != 0 || provider.embedCalls != 0 {
+		t.Errorf("second Generate made %d batch and %d single calls, want none", len(provider.batches), provider.embedCalls)
+	}
+	assertChunkEmbeddingsAligned(t, again)
+	if len(fileEmbedding) != 1 {
+		t.Errorf("file embedding = %v, want one dimension", fileEmbedding)
+	}
+}
+
+func TestEmbeddingsStageGenerateEmbedsStoredMisses(t *testing.T) {
+	mockGraph := &mockGraphForPersistence{
+		connected:   true,
+		chunkHashes: map[string]string{"h0": "h0", "h1": "h1"},
+		embeddings: map[string][]*graph.ChunkEmbeddingNode{
+			// Current version, reused
+			"h0": {{Provider: "mock-embeddings", Model: "mock-model", Embedding: []float32{1}, Dimensions: 1, Version: cache.EmbeddingsCacheVersion}},
+			// Older version, re-embedded
+			"h1": {{Provider: "mock-embeddings", Model: "mock-model", Embedding: []float32{9}, Dimensions: 1, Version: cache.EmbeddingsCacheVersion - 1}},
+		},
+	}
+	provider := &batchRecordingProvider{mockEmbeddingsProvider: mockEmbeddingsProvider{available: true}}
+	stage := NewEmbeddingsStage(provider, nil, nil, slog.Default(), WithEmbeddingsGraph(mockGraph))
+
+	chunks := batchTestChunks(3)
+	if _, err := stage.Generate(context.Background(), "/repo/file.go", chunks); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	if len(provider.batches) != 1 || strings.Join(provider.batches[0], ",") != "xx,xxx" {
+		t.Errorf("batches = %v, want only the two misses", provider.batches)
+	}
+	assertChunkEmbeddingsAligned(t, chunks)
+}
+
+func TestStoredEmbeddingIgnoresOtherDimensions(t *testing.T) {
+	mockGraph := &mockGraphForPersistence{
+		connected:   true,
+		chunkHashes: map[string]string{"h0": "h0"},
+		embeddings: map[string][]*graph.ChunkEmbeddingNode{
+			"h0": {{Provider: "mock-embeddings", Model: "mock-model", Embedding: []float32{1}, Dimensions: 1, Version: cache.EmbeddingsCacheVersion}},
+		},
+	}
+
+	provider := &mockEmbeddingsProvider{available: true, embedding: []float32{0.5, 0.5}}
+	if got := storedEmbedding(context.Background(), mockGraph, provider, slog.Default(), "h0"); got != nil {
+		t.Errorf("storedEmbedding = %v, want nil for a 1-dimension vector with a 2-dimension provider", got)
+	}
+
+	provider = &mockEmbeddingsProvider{available: true, embedding: []float32{0.5}}
+	if got := storedEmbedding(context.Background(), mockGraph, provider, slog.Default(), "h0"); len(got) != 1 {
+		t.Errorf("storedEmbedding = %v, want the stored vector", got)
+	}
+}
+
+// renamedEmbeddingsProvider reports a different model than mockEmbeddingsProvider.
+type renamedEmbeddingsProvider struct {
+	mockEmbeddingsProvider
+}
+
+func (p *renamedEmbeddingsProvider) ModelName() string { return "other-model" }
+
+func TestStoredEmbeddingIgnoresOtherModels(t *testing.T) {
+	ctx := context.Background()
+	mockGraph := &mockGraphForPersistence{connected: true}
+	provider := &mockEmbeddingsProvider{available: true, embedding: []float32{0.5}}
+
+	chunks := batchTestChunks(1)
+	if _, err := NewEmbeddingsStage(provider, nil, nil, slog.Default()).Generate(ctx, "/repo/file.go", chunks); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	result := &AnalysisResult{
+		FilePath:    "/repo/file.go",
+		ContentHash: "file-hash",
+		IngestMode:  ingest.ModeChunk,
+		Chunks:      chunks,
+	}
+	if err := NewPersistenceStage(mockGraph).Persist(ctx, result); err != nil {
+		t.Fatalf("Persist failed: %v", err)
+	}
+
+	stored := mockGraph.embeddings[chunks[0].ContentHash]
+	if len(stored) != 1 || stored[0].Provider != "mock-embeddings" || stored[0].Model != "mock-model" {
+		t.Fatalf("stored embeddings = %+v, want one from mock-embeddings/mock-model", stored)
+	}
+
+	if got := storedEmbedding(ctx, mockGraph, provider, slog.Default(), chunks[0].ContentHash); len(got) != 1 {
+		t.Errorf("storedEmbedding = %v, want the vector stored by the same model", got)
+	}
+
+	// Same dimensions, different model
+	other := &renamedEmbeddingsProvider{mockEmbeddingsProvider{available: true, embedding: []float32{0.5}}}
+	if got := storedEmbedding(ctx, mockGraph, other, slog.Default(), chunks[0].ContentHash); got != nil {
+		t.Errorf("storedEmbedding = %v, want nil for a vector from another model", got)
+	}
+}
@@ -2,20 +2,37 @@ package analysis
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"path/filepath"
+	"time"
 
+	"github.com/leefowlercu/agentic-memorizer/internal/cache"
+	"github.com/leefowlercu/agentic-memorizer/internal/chunkers"
 	"github.com/leefowlercu/agentic-memorizer/internal/graph"
 	"github.com/leefowlercu/agentic-memorizer/internal/ingest"
+	"github.com/leefowlercu/agentic-memorizer/internal/registry"
 	"github.com/leefowlercu/agentic-memorizer/internal/storage"
 )
 
+// asyncWriteTimeout bounds how long persistence waits for a file's queued graph
+// writes to execute before treating them as failed.
+const asyncWriteTimeout = 30 * time.Second
+
+// DefaultChunkBatchThreshold is the chunk count above which a file's chunks
+// are written with a single batched upsert instead of one upsert per chunk.
+const DefaultChunkBatchThreshold = 16
+
 // PersistenceStage writes analysis results to the graph.
 type PersistenceStage struct {
-	graph  graph.Graph
-	queue  storage.DurablePersistenceQueue
-	logger *slog.Logger
+	graph    graph.Graph
+	queue    storage.DurablePersistenceQueue
+	registry registry.Registry
+	logger   *slog.Logger
+
+	// chunkBatchThreshold is the chunk count above which chunks are batched.
+	chunkBatchThreshold int
 }
 
 // PersistenceStageOption configures a PersistenceStage.
@@ -28,6 +45,22 @@ func WithPersistenceQueue(q storage.DurablePersistenceQueue) PersistenceStageOpt
 	}
 }
 
+// WithPersistenceRegistry sets the registry whose file state is cleared when
+// persistence fails, so the file is analyzed again instead of appearing done.
+func WithPersistenceRegistry(reg registry.Registry) PersistenceStageOption {
+	return func(s *PersistenceStage) {
+		s.registry = reg
+	}
+}
+
+// WithChunkBatchThreshold sets the chunk count above which a file's chunks are
+// written with UpsertChunksBatch. Zero or less batches every file.
+func WithChunkBatchThreshold(n int) PersistenceStageOption {
+	return func(s *PersistenceStage) {
+		s.chunkBatchThreshold = n
+	}
+}
+
 // WithPersistenceLogger sets the logger for the persistence stage.
 func WithPersistenceLogger(logger *slog.Logger) PersistenceStageOption {
 	return func(s *PersistenceStage) {
@@ -38,8 +71,9 @@ func WithPersistenceLogger(logger *slog.Logger) PersistenceStageOption {
 // NewPersistenceStage creates a persistence stage.
 func NewPersistenceStage(g graph.Graph, opts ...PersistenceStageOption) *PersistenceStage {
 	s := &PersistenceStage{
-		graph:  g,
-		logger: slog.Default(),
+		graph:               g,
+		logger:              slog.Default(),
+		chunkBatchThreshold: DefaultChunkBatchThreshold,
 	}
 
 	for _, opt := range opts {
@@ -56,6 +90,7 @@ func (s *PersistenceStage) Persist(ctx context.Context, result *AnalysisResult)
 
 	// If no graph configured and no queue, nothing to do
 	if s.graph == nil && s.queue == nil {
+		s.recordChunkerVersion(ctx, result.FilePath)
 		return nil
 	}
 
@@ -86,12 +121,50 @@ func (s *PersistenceStage) Persist(ctx context.Context, result *AnalysisResult)
 			}
 			return nil // Queued successfully, don't return the persistence error
 		}
+		s.clearFileState(ctx, result.FilePath)
 		return err
 	}
 
+	s.recordChunkerVersion(ctx, result.FilePath)
 	return nil
 }
 
+// recordChunkerVersion stores the chunker version under which the file's
+// results were persisted, so the walker re-chunks it after a version change.
+func (s *PersistenceStage) recordChunkerVersion(ctx context.Context, path string) {
+	if s.registry == nil {
+		return
+	}
+	if err := s.registry.UpdateChunkerState(ctx, path, chunkers.Version); err != nil {
+		loggerOrDefault(s.logger).Warn("failed to update chunker state",
+			"path", path,
+			"error", err)
+	}
+}
+
+// removePartialFile deletes the file's nodes after a transaction that was
+// only partly applied, so the graph does not show a file with missing chunks.
+func (s *PersistenceStage) removePartialFile(ctx context.Context, path string) {
+	if err := s.graph.DeleteFile(ctx, path); err != nil {
+		loggerOrDefault(s.logger).Warn("failed to remove partially persisted file",
+			"path", path,
+			"error", err)
+	}
+}
+
+// clearFileState resets the registry analysis state of a file whose results
+// did not reach the graph.
+func (s *PersistenceStage) clearFileState(ctx context.Context, path string) {
+	if s.registry == nil {
+		return
+	}
+	if err := s.registry.ClearAnalysisState(ctx, path); err != nil {
+		loggerOrDefault(s.logger).Warn("failed to clear analysis state after persistence failure",
+			"path", path,
+			"error", err)
+	}
+}
+
 // enqueueResult serializes and enqueues an analysis result.
 func (s *PersistenceStage) enqueueResult(ctx context.Context, result *AnalysisResult) error {
 	resultJSON, err := storage.MarshalAnalysisResult(result)
@@ -106,8 +179,35 @@ func (s *PersistenceStage) enqueueResult(ctx context.Context, result *AnalysisRe
 	return nil
 }
 
-// persistToGraph performs the actual persistence to the graph.
+// persistToGraph performs the actual persistence to the graph. The file's
+// writes are committed as one transaction, and any asynchronous writes are
+// awaited, so a late write failure fails the whole file.
 func (s *PersistenceStage) persistToGraph(ctx context.Context, result *AnalysisResult) error {
+	batchCtx, batch := graph.WithWriteBatch(ctx)
+	txCtx, tx := graph.WithTransaction(batchCtx)
+	if err := s.writeToGraph(txCtx, result); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := s.graph.CommitTransaction(batchCtx, tx); err != nil {
+		if errors.Is(err, graph.ErrTransactionFailed) {
+			s.removePartialFile(ctx, result.FilePath)
+		}
+		return fmt.Errorf("failed to commit graph writes; %w", err)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, asyncWriteTimeout)
+	defer cancel()
+	if err := batch.Wait(waitCtx); err != nil {
+		return fmt.Errorf("async graph writes failed; %w", err)
+	}
+
+	return nil
+}
+
+// writeToGraph issues the graph writes for an analysis result.
+func (s *PersistenceStage) writeToGraph(ctx context.Context, result *AnalysisResult) error {
 	logger := loggerOrDefault(s.logger)
 
 	if result.IngestMode == ingest.ModeSkip {
@@ -133,6 +233,7 @@ func (s *PersistenceStage) persistToGraph(ctx context.Context, result *AnalysisR
 		IngestKind:   string(result.IngestKind),
 		IngestMode:   string(result.IngestMode),
 		IngestReason: result.IngestReason,
+		ChangeReason: string(result.ChangeReason),
 	}
 
 	if err := s.graph.UpsertFile(ctx, fileNode); err != nil {
@@ -143,34 +244,45 @@ func (s *PersistenceStage) persistToGraph(ctx context.Context, result *AnalysisR
 		return fmt.Errorf("failed to delete existing chunks; %w", err)
 	}
 
-	for _, chunk := range result.Chunks {
-		chunkNode := &graph.ChunkNode{
-			ID:          chunk.ContentHash,
-			FilePath:    result.FilePath,
-			Index:       chunk.Index,
-			Content:     chunk.Content,
-			ContentHash: chunk.ContentHash,
-			StartOffset: chunk.StartOffset,
-			EndOffset:   chunk.EndOffset,
-			ChunkType:   chunk.ChunkType,
-			Summary:     chunk.Summary,
-			TokenCount:  chunk.TokenCount,
-		}
-
-		if err := s.graph.UpsertChunkWithMetadata(ctx, chunkNode, chunk.Metadata); err != nil {
-			logger.Warn("failed to upsert chunk with metadata",
-				"path", result.FilePath,
-				"chunk", chunk.Index,
-				"error", err)
-			continue
+	chunkNodes := make([]*graph.ChunkNode, len(result.Chunks))
+	for i, chunk := range result.Chunks {
+		chunkNodes[i] = chunkNodeFor(result.FilePath, chunk)
+	}
+
+	var linked []string
+	batched := len(result.Chunks) > s.chunkBatchThreshold
+	if batched {
+		metas := make([]*chunkers.ChunkMetadata, len(result.Chunks))
+		for i, chunk := range result.Chunks {
+			metas[i] = chunk.Metadata
+		}
+		if err := s.graph.UpsertChunksBatch(ctx, chunkNodes, metas); err != nil {
+			return fmt.Errorf("failed to upsert chunks; %w", err)
+		}
+		for _, node := range chunkNodes {
+			linked = append(linked, node.ID)
+		}
+	}
+
+	for i, chunk := range result.Chunks {
+		if !batched {
+			if err := s.graph.UpsertChunkWithMetadata(ctx, chunkNodes[i], chunk.Metadata); err != nil {
+				logger.Warn("failed to upsert chunk with metadata",
+					"path", result.FilePath,
+					"chunk", chunk.Index,
+					"error", err)
+				continue
+			}
+			linked = append(linked, chunkNodes[i].ID)
 		}
 
 		if len(chunk.Embedding) > 0 {
 			embNode := &graph.ChunkEmbeddingNode{
-				Provider:   "default",
-				Model:      "default",
+				Provider:   chunk.EmbeddingProvider,
+				Model:      chunk.EmbeddingModel,
 				Dimensions: len(chunk.Embedding),
 				Embedding:  chunk.Embedding,
+				Version:    cache.EmbeddingsCacheVersion,
 			}
 			if err := s.graph.UpsertChunkEmbedding(ctx, chunk.ContentHash, embNode); err != nil {
 				logger.Warn("failed to upsert embedding",
@@ -181,6 +293,16 @@ func (s *PersistenceStage) persistToGraph(ctx context.Context, result *AnalysisR
 		}
 	}
 
+	if len(linked) > 1 {
+		if err := s.graph.LinkChunks(ctx, result.FilePath, linked); err != nil {
+			logger.Warn("failed to link chunks",
+				"path", result.FilePath,
+				"error", err)
+		}
+	}
+
+	s.persistSectionReferences(ctx, logger, result)
+
 	if len(result.Tags) > 0 {
 		if err := s.graph.SetFileTags(ctx, result.FilePath, result.Tags); err != nil {
 			return fmt.Errorf("failed to set tags; %w", err)
@@ -207,6 +329,15 @@ func (s *PersistenceStage) persistToGraph(ctx context.Context, result *AnalysisR
 		}
 	}
 
+	// Always set relations so an empty set clears edges from earlier analyses
+	relations := make([]graph.EntityRelation, len(result.Relations))
+	for i, r := range result.Relations {
+		relations[i] = graph.EntityRelation{Subject: r.Subject, Predicate: r.Predicate, Object: r.Object}
+	}
+	if err := s.graph.SetFileRelations(ctx, result.FilePath, relations); err != nil {
+		return fmt.Errorf("failed to set relations; %w", err)
+	}
+
 	if len(result.References) > 0 {
 		refs := make([]graph.Reference, len(result.References))
 		for i, r := range result.References {
@@ -219,3 +350,68 @@ func (s *PersistenceStage) persistToGraph(ctx context.Context, result *AnalysisR
 
 	return nil
 }
+
+// chunkNodeFor builds the graph node for an analyzed chunk of the file at path.
+func chunkNodeFor(path string, chunk AnalyzedChunk) *graph.ChunkNode {
+	node := &graph.ChunkNode{
+		ID:          chunk.ContentHash,
+		FilePath:    path,
+		Index:       chunk.Index,
+		Content:     chunk.Content,
+		ContentHash: chunk.ContentHash,
+		StartOffset: chunk.StartOffset,
+		EndOffset:   chunk.EndOffset,
+		ChunkType:   chunk.ChunkType,
+		Summary:     chunk.Summary,
+		TokenCount:  chunk.TokenCount,
+	}
+	if chunk.Metadata != nil {
+		node.BoundaryConfidence = chunk.Metadata.BoundaryConfidence
+		node.LineStart = chunk.Metadata.LineStart
+		node.LineEnd = chunk.Metadata.LineEnd
+		if node.LineStart == 0 && chunk.Metadata.Code != nil {
+			node.LineStart = chunk.Metadata.Code.LineStart
+			node.LineEnd = chunk.Metadata.Code.LineEnd
+		}
+	}
+	return node
+}
+
+// persistSectionReferences links chunks containing in-document anchor links to the
+// chunks whose heading slug matches the anchor within the same file.
+func (s *PersistenceStage) persistSectionReferences(ctx context.Context, logger *slog.Logger, result *AnalysisResult) {
+	sections := make(map[string]string)
+	for _, chunk := range result.Chunks {
+		if chunk.Metadata == nil || chunk.Metadata.Document == nil || chunk.Metadata.Document.Heading == "" {
+			continue
+		}
+		slug := chunkers.AnchorSlug(chunk.Metadata.Document.Heading)
+		if _, exists := sections[slug]; !exists {
+			sections[slug] = chunk.ContentHash
+		}
+	}
+
+	for _, chunk := range result.Chunks {
+		if chunk.Metadata == nil || chunk.Metadata.Document == nil || len(chunk.Metadata.Document.Anchors) == 0 {
+			continue
+		}
+
+		var targets, unmatched []string
+		for _, anchor := range chunk.Metadata.Document.Anchors {
+			target, ok := sections[chunkers.AnchorSlug(anchor)]
+			switch {
+			case !ok:
+				unmatched = append(unmatched, anchor)
+			case target != chunk.ContentHash:
+				targets = append(targets, target)
+			}
+		}
+
+		if err := s.graph.SetChunkSectionReferences(ctx, chunk.ContentHash, targets, unmatched); err != nil {
+			logger.Warn("failed to set section references",
+				"path", result.FilePath,
+				"chunk", chunk.Index,
+				"error", err)
+		}
+	}
+}
@@ -3,12 +3,18 @@ package analysis
 import (
 	"context"
 	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/leefowlercu/agentic-memorizer/internal/cache"
 	"github.com/leefowlercu/agentic-memorizer/internal/chunkers"
 	"github.com/leefowlercu/agentic-memorizer/internal/graph"
 	"github.com/leefowlercu/agentic-memorizer/internal/ingest"
+	"github.com/leefowlercu/agentic-memorizer/internal/providers"
+	"github.com/leefowlercu/agentic-memorizer/internal/registry"
 	"github.com/leefowlercu/agentic-memorizer/internal/storage"
 )
 
@@ -16,9 +22,23 @@ import (
 type mockGraphForPersistence struct {
 	connected    bool
 	upsertErr    error
+	asyncErr     error
 	deleteErr    error
+	linkErr      error
 	upsertCalled int
 	deleteCalled int
+	batchCalled  int
+	commitCalled int
+	commitErr    error
+	references   map[string][]graph.Reference
+	chunkHashes  map[string]string
+	embeddings   map[string][]*graph.ChunkEmbeddingNode
+	sections     map[string][]string
+	links        map[string][]string
+	unmatched    map[string][]string
+	entities     map[string]graph.Entity
+	relations    []graph.EntityRelation
+	files        map[string]*graph.FileNode
 }
 
 func (m *mockGraphForPersistence) Name() string                    { return "mock-graph" }
@@ -28,6 +48,15 @@ func (m *mockGraphForPersistence) Stop(ctx context.Context) error  { return nil
 func (m *mockGraphForPersistence) IsConnected() bool               { return m.connected }
 func (m *mockGraphForPersistence) UpsertFile(ctx context.Context, file *graph.FileNode) error {
 	m.upsertCalled++
+	if m.files == nil {
+		m.files = make(map[string]*graph.FileNode)
+	}
+	m.files[file.Path] = file
+	if m.asyncErr != nil {
+		// Accept the write, then fail it later like a queued graph write
+		done := graph.WriteBatchFromContext(ctx).Track()
+		go done(m.asyncErr)
+	}
 	return m.upsertErr
 }
 func (m *mockGraphForPersistence) DeleteFile(ctx context.Context, path string) error {
@@ -48,14 +77,41 @@ func (m *mockGraphForPersistence) DeleteDirectoriesUnderPath(ctx context.Context
 	return nil
 }
 func (m *mockGraphForPersistence) UpsertChunkWithMetadata(ctx context.Context, chunk *graph.ChunkNode, meta *chunkers.ChunkMetadata) error {
+	if m.chunkHashes == nil {
+		m.chunkHashes = make(map[string]string)
+	}
+	m.chunkHashes[chunk.ID] = chunk.ContentHash
+	return nil
+}
+func (m *mockGraphForPersistence) UpsertChunksBatch(ctx context.Context, chunks []*graph.ChunkNode, metas []*chunkers.ChunkMetadata) error {
+	m.batchCalled++
+	for _, chunk := range chunks {
+		if err := m.UpsertChunkWithMetadata(ctx, chunk, nil); err != nil {
+			return err
+		}
+	}
 	return nil
 }
+func (m *mockGraphForPersistence) CommitTransaction(ctx context.Context, tx *graph.Transaction) error {
+	m.commitCalled++
+	return m.commitErr
+}
 func (m *mockGraphForPersistence) UpsertChunkEmbedding(ctx context.Context, chunkID string, emb *graph.ChunkEmbeddingNode) error {
+	if _, ok := m.chunkHashes[chunkID]; !ok {
+		return nil
+	}
+	if m.embeddings == nil {
+		m.embeddings = make(map[string][]*graph.ChunkEmbeddingNode)
+	}
+	m.embeddings[chunkID] = append(m.embeddings[chunkID], emb)
 	return nil
 }
 func (m *mockGraphForPersistence) DeleteChunkEmbeddings(ctx context.Context, chunkID string, provider, model string) error {
 	return nil
 }
+func (m *mockGraphForPersistence) DeleteFileEmbeddings(ctx context.Context, filePath string, provider, model string) error {
+	return nil
+}
 func (m *mockGraphForPersistence) DeleteChunks(ctx context.Context, path string) error { return nil }
 func (m *mockGraphForPersistence) SetFileTags(ctx context.Context, path string, tags []string) error {
 	return nil
@@ -64,20 +120,125 @@ func (m *mockGraphForPersistence) SetFileTopics(ctx context.Context, path string
 	return nil
 }
 func (m *mockGraphForPersistence) SetFileEntities(ctx context.Context, path string, entities []graph.Entity) error {
+	if m.entities == nil {
+		m.entities = make(map[string]graph.Entity)
+	}
+	for _, e := range entities {
+		m.entities[e.Name] = e
+	}
+	return nil
+}
+func (m *mockGraphForPersistence) SetFileRelations(ctx context.Context, path string, relations []graph.EntityRelation) error {
+	// Mirror the graph: earlier relations are replaced, and relations only
+	// link entities that already exist
+	m.relations = nil
+	for _, rel := range relations {
+		_, hasSubject := m.entities[rel.Subject]
+		_, hasObject := m.entities[rel.Object]
+		if hasSubject && hasObject {
+			m.relations = append(m.relations, rel)
+		}
+	}
 	return nil
 }
 func (m *mockGraphForPersistence) SetFileReferences(ctx context.Context, path string, refs []graph.Reference) error {
+	if m.references == nil {
+		m.references = make(map[string][]graph.Reference)
+	}
+	m.references[path] = refs
+	return nil
+}
+func (m *mockGraphForPersistence) SetChunkSectionReferences(ctx context.Context, chunkID string, targetIDs []string, unmatchedAnchors []string) error {
+	if m.sections == nil {
+		m.sections = make(map[string][]string)
+		m.unmatched = make(map[string][]string)
+	}
+	m.sections[chunkID] = targetIDs
+	m.unmatched[chunkID] = unmatchedAnchors
 	return nil
 }
+func (m *mockGraphForPersistence) LinkChunks(ctx context.Context, path string, chunkIDs []string) error {
+	if m.links == nil {
+		m.links = make(map[string][]string)
+	}
+	m.links[path] = chunkIDs
+	return m.linkErr
+}
+func (m *mockGraphForPersistence) GetAdjacentChunks(ctx context.Context, chunkID string, before, after int) ([]graph.ChunkNode, error) {
+	return nil, nil
+}
+func (m *mockGraphForPersistence) PruneOrphans(ctx context.Context) (graph.PruneStats, error) {
+	return graph.PruneStats{}, nil
+}
+func (m *mockGraphForPersistence) GetChunkByID(ctx context.Context, id string) (*graph.ChunkNode, *chunkers.ChunkMetadata, error) {
+	return nil, nil, nil
+}
+func (m *mockGraphForPersistence) ListChunksForFile(ctx context.Context, filePath string) ([]graph.ChunkNode, error) {
+	return nil, nil
+}
+func (m *mockGraphForPersistence) GetBacklinks(ctx context.Context, path string) ([]graph.FileNode, error) {
+	return nil, nil
+}
+func (m *mockGraphForPersistence) GetReferences(ctx context.Context, path string) ([]graph.Reference, error) {
+	return nil, nil
+}
 func (m *mockGraphForPersistence) Query(ctx context.Context, cypher string) (*graph.QueryResult, error) {
 	return nil, nil
 }
 func (m *mockGraphForPersistence) HasEmbedding(ctx context.Context, contentHash string, version int) (bool, error) {
+	for chunkID, hash := range m.chunkHashes {
+		if hash != contentHash {
+			continue
+		}
+		for _, emb := range m.embeddings[chunkID] {
+			if emb.Version == version && len(emb.Embedding) > 0 {
+				return true, nil
+			}
+		}
+	}
 	return false, nil
 }
+func (m *mockGraphForPersistence) GetEmbedding(ctx context.Context, contentHash, provider, model string, version int) ([]float32, error) {
+	for chunkID, hash := range m.chunkHashes {
+		if hash != contentHash {
+			continue
+		}
+		for _, emb := range m.embeddings[chunkID] {
+			if emb.Provider == provider && emb.Model == model && emb.Version == version && len(emb.Embedding) > 0 {
+				return emb.Embedding, nil
+			}
+		}
+	}
+	return nil, nil
+}
+func (m *mockGraphForPersistence) CountFilesByIngestReason(ctx context.Context) (map[string]int, error) {
+	counts := make(map[string]int)
+	for _, file := range m.files {
+		counts[file.ChangeReason]++
+	}
+	return counts, nil
+}
+func (m *mockGraphForPersistence) GetDirectoryTree(ctx context.Context, rootPath string, maxDepth int) (*graph.DirectoryTree, error) {
+	return nil, nil
+}
+func (m *mockGraphForPersistence) TopTags(ctx context.Context, n int) ([]graph.TagNode, error) {
+	return nil, nil
+}
+func (m *mockGraphForPersistence) TopTopics(ctx context.Context, n int) ([]graph.TopicNode, error) {
+	return nil, nil
+}
+func (m *mockGraphForPersistence) TopEntities(ctx context.Context, n int, entityType string) ([]graph.EntityNode, error) {
+	return nil, nil
+}
+func (m *mockGraphForPersistence) GetRelatedFiles(ctx context.Context, path string, k int) ([]graph.RelatedFile, error) {
+	return nil, nil
+}
 func (m *mockGraphForPersistence) ExportSnapshot(ctx context.Context) (*graph.GraphSnapshot, error) {
 	return nil, nil
 }
+func (m *mockGraphForPersistence) ExportSnapshotSince(ctx context.Context, since time.Time) (*graph.GraphSnapshot, error) {
+	return nil, nil
+}
 func (m *mockGraphForPersistence) GetFileWithRelations(ctx context.Context, path string) (*graph.FileWithRelations, error) {
 	return nil, nil
 }
@@ -85,6 +246,10 @@ func (m *mockGraphForPersistence) SearchSimilarChunks(ctx context.Context, embed
 	return nil, nil
 }
 
+func (m *mockGraphForPersistence) SearchSimilarChunksFiltered(ctx context.Context, embedding []float32, k int, filter graph.ChunkFilter) ([]graph.ChunkSearchHit, error) {
+	return nil, nil
+}
+
 // mockPersistenceQueue implements storage.DurablePersistenceQueue for testing.
 type mockPersistenceQueue struct {
 	enqueued    []mockQueuedItem
@@ -251,6 +416,134 @@ func TestPersistenceStage_Persist(t *testing.T) {
 	}
 }
 
+func TestPersistenceStage_AsyncWriteFailureClearsFileState(t *testing.T) {
+	ctx := context.Background()
+	reg, err := registry.Open(ctx, filepath.Join(t.TempDir(), "registry.db"))
+	if err != nil {
+		t.Fatalf("failed to open registry: %v", err)
+	}
+	defer reg.Close()
+
+	path := "/test/file.go"
+	if err := reg.UpdateMetadataState(ctx, path, "abc123", "meta123", 100, time.Now()); err != nil {
+		t.Fatalf("UpdateMetadataState failed: %v", err)
+	}
+
+	mockGraph := &mockGraphForPersistence{connected: true, asyncErr: errors.New("write failed")}
+	stage := NewPersistenceStage(mockGraph, WithPersistenceRegistry(reg))
+
+	result := &AnalysisResult{
+		FilePath:    path,
+		ContentHash: "abc123",
+		IngestMode:  ingest.ModeChunk,
+	}
+	err = stage.Persist(ctx, result)
+	if err == nil || !strings.Contains(err.Error(), "write failed") {
+		t.Fatalf("Persist() error = %v, want async write failure", err)
+	}
+
+	state, err := reg.GetFileState(ctx, path)
+	if err != nil {
+		t.Fatalf("GetFileState failed: %v", err)
+	}
+	if state.MetadataAnalyzedAt != nil {
+		t.Error("expected file not to be marked analyzed after persistence failure")
+	}
+}
+
+func TestPersistenceStage_RecordsChunkerVersionAfterPersist(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		graph       *mockGraphForPersistence
+		wantVersion string
+	}{
+		{"persisted", &mockGraphForPersistence{connected: true}, chunkers.Version},
+		{"write failed", &mockGraphForPersistence{connected: true, asyncErr: errors.New("write failed")}, ""},
+		{"graph disconnected", &mockGraphForPersistence{connected: false}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg, err := registry.Open(ctx, filepath.Join(t.TempDir(), "registry.db"))
+			if err != nil {
+				t.Fatalf("failed to open registry: %v", err)
+			}
+			defer reg.Close()
+
+			path := "/test/file.go"
+			if err := reg.UpdateMetadataState(ctx, path, "abc123", "meta123", 100, time.Now()); err != nil {
+				t.Fatalf("UpdateMetadataState failed: %v", err)
+			}
+
+			stage := NewPersistenceStage(tt.graph, WithPersistenceRegistry(reg))
+			_ = stage.Persist(ctx, &AnalysisResult{
+				FilePath:    path,
+				ContentHash: "abc123",
+				IngestMode:  ingest.ModeChunk,
+			})
+
+			state, err := reg.GetFileState(ctx, path)
+			if err != nil {
+				t.Fatalf("GetFileState failed: %v", err)
+			}
+			if state.ChunkerVersion != tt.wantVersion {
+				t.Errorf("ChunkerVersion = %q, want %q", state.ChunkerVersion, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestPersistenceStage_CommitsFileWritesTogether(t *testing.T) {
+	result := &AnalysisResult{
+		FilePath:    "/test/file.go",
+		ContentHash: "abc123",
+		IngestMode:  ingest.ModeChunk,
+		Tags:        []string{"go"},
+	}
+
+	t.Run("commits once per file", func(t *testing.T) {
+		mockGraph := &mockGraphForPersistence{connected: true}
+		stage := NewPersistenceStage(mockGraph)
+
+		if err := stage.Persist(context.Background(), result); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if mockGraph.commitCalled != 1 {
+			t.Errorf("CommitTransaction calls = %d, want 1", mockGraph.commitCalled)
+		}
+	})
+
+	t.Run("failed commit removes partial state", func(t *testing.T) {
+		mockGraph := &mockGraphForPersistence{
+			connected: true,
+			commitErr: fmt.Errorf("%w; query 3 of 4: boom", graph.ErrTransactionFailed),
+		}
+		stage := NewPersistenceStage(mockGraph)
+
+		err := stage.Persist(context.Background(), result)
+		if !errors.Is(err, graph.ErrTransactionFailed) {
+			t.Fatalf("Persist() error = %v, want %v", err, graph.ErrTransactionFailed)
+		}
+		if mockGraph.deleteCalled != 1 {
+			t.Errorf("DeleteFile calls = %d, want 1 to remove the partial file", mockGraph.deleteCalled)
+		}
+	})
+
+	t.Run("commit that never reached the graph leaves it alone", func(t *testing.T) {
+		mockGraph := &mockGraphForPersistence{connected: true, commitErr: errors.New("write queue full")}
+		stage := NewPersistenceStage(mockGraph)
+
+		if err := stage.Persist(context.Background(), result); err == nil {
+			t.Fatal("expected Persist to return the commit error")
+		}
+		if mockGraph.deleteCalled != 0 {
+			t.Errorf("DeleteFile calls = %d, want 0", mockGraph.deleteCalled)
+		}
+	})
+}
+
 func TestPersistenceStage_NilGraphAndQueue(t *testing.T) {
 	stage := NewPersistenceStage(nil)
 
@@ -314,6 +607,276 @@ func TestPersistenceStage_SkipModeQueuesOnDeleteError(t *testing.T) {
 	}
 }
 
+func TestPersistenceStage_PersistsFileReferences(t *testing.T) {
+	mockGraph := &mockGraphForPersistence{connected: true}
+	stage := NewPersistenceStage(mockGraph)
+
+	result := &AnalysisResult{
+		FilePath:    "/test/a.md",
+		ContentHash: "abc123",
+		IngestMode:  ingest.ModeChunk,
+		References:  []Reference{{Type: "file", Target: "/test/b.md"}},
+	}
+
+	if err := stage.Persist(context.Background(), result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	refs := mockGraph.references["/test/a.md"]
+	if len(refs) != 1 || refs[0].Type != "file" || refs[0].Target != "/test/b.md" {
+		t.Errorf("references = %+v, want [/test/b.md]", refs)
+	}
+}
+
+// relationSemanticProvider returns two entities linked by a single relation.
+type relationSemanticProvider struct {
+	mockSemanticProvider
+}
+
+func (p *relationSemanticProvider) Analyze(ctx context.Context, input providers.SemanticInput) (*providers.SemanticResult, error) {
+	return &providers.SemanticResult{
+		Summary: "Team roster",
+		Entities: []providers.Entity{
+			{Name: "Alice", Type: "person"},
+			{Name: "Acme", Type: "organization"},
+		},
+		Relations: []providers.EntityRelation{
+			{Subject: "Alice", Predicate: "WORKS_AT", Object: "Acme"},
+		},
+	}, nil
+}
+
+func TestPersistenceStage_EntityRelations(t *testing.T) {
+	provider := &relationSemanticProvider{mockSemanticProvider{available: true}}
+	semanticStage := NewSemanticStage(provider, nil, nil, "", nil)
+
+	semantic, err := semanticStage.Analyze(context.Background(), providers.SemanticInput{Path: "/test/team.md"}, "abc123")
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	mockGraph := &mockGraphForPersistence{connected: true}
+	stage := NewPersistenceStage(mockGraph)
+
+	result := &AnalysisResult{
+		FilePath:    "/test/team.md",
+		ContentHash: "abc123",
+		IngestMode:  ingest.ModeChunk,
+		Entities:    semantic.Entities,
+		Relations:   semantic.Relations,
+	}
+	if err := stage.Persist(context.Background(), result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := graph.EntityRelation{Subject: "Alice", Predicate: "WORKS_AT", Object: "Acme"}
+	if len(mockGraph.relations) != 1 || mockGraph.relations[0] != want {
+		t.Errorf("relations = %+v, want [%+v]", mockGraph.relations, want)
+	}
+
+	// Re-analysis without relations clears the stale edges
+	result.Relations = nil
+	if err := stage.Persist(context.Background(), result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mockGraph.relations) != 0 {
+		t.Errorf("relations after re-analysis = %+v, want none", mockGraph.relations)
+	}
+}
+
+func TestPersistenceStage_SectionReferences(t *testing.T) {
+	content := "# Guide\n\nSee [installation](#installation) and [missing](#missing).\n\n## Installation\n\nRun the installer.\n"
+	chunkResult, err := chunkers.NewMarkdownChunker().Chunk(context.Background(), []byte(content), chunkers.DefaultChunkOptions())
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+
+	chunks := BuildAnalyzedChunks(chunkResult.Chunks)
+	if len(chunks) != 2 {
+		t.Fatalf("got %d chunks, want 2", len(chunks))
+	}
+
+	mockGraph := &mockGraphForPersistence{connected: true}
+	stage := NewPersistenceStage(mockGraph)
+
+	result := &AnalysisResult{
+		FilePath:    "/docs/guide.md",
+		ContentHash: "guide-hash",
+		IngestMode:  ingest.ModeChunk,
+		Chunks:      chunks,
+	}
+	if err := stage.Persist(context.Background(), result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	guide, installation := chunks[0].ContentHash, chunks[1].ContentHash
+	if got := mockGraph.sections[guide]; len(got) != 1 || got[0] != installation {
+		t.Errorf("section references = %v, want [%s]", got, installation)
+	}
+	if got := mockGraph.unmatched[guide]; len(got) != 1 || got[0] != "missing" {
+		t.Errorf("unmatched anchors = %v, want [missing]", got)
+	}
+	if _, ok := mockGraph.sections[installation]; ok {
+		t.Error("expected no section references from chunk without anchors")
+	}
+}
+
+func TestPersistenceStage_BatchesLargeFiles(t *testing.T) {
+	chunksOf := func(n int) []AnalyzedChunk {
+		chunks := make([]AnalyzedChunk, n)
+		for i := range chunks {
+			chunks[i] = AnalyzedChunk{Index: i, Content: "chunk", ContentHash: fmt.Sprintf("hash-%d", i)}
+		}
+		return chunks
+	}
+
+	tests := []struct {
+		name      string
+		chunks    int
+		threshold int
+		wantBatch int
+	}{
+		{"at threshold writes each chunk", DefaultChunkBatchThreshold, DefaultChunkBatchThreshold, 0},
+		{"above threshold batches", DefaultChunkBatchThreshold + 1, DefaultChunkBatchThreshold, 1},
+		{"custom threshold", 3, 2, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockGraph := &mockGraphForPersistence{connected: true}
+			stage := NewPersistenceStage(mockGraph, WithChunkBatchThreshold(tt.threshold))
+
+			result := &AnalysisResult{
+				FilePath:    "/docs/big.md",
+				ContentHash: "big-hash",
+				IngestMode:  ingest.ModeChunk,
+				Chunks:      chunksOf(tt.chunks),
+			}
+			if err := stage.Persist(context.Background(), result); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if mockGraph.batchCalled != tt.wantBatch {
+				t.Errorf("UpsertChunksBatch calls = %d, want %d", mockGraph.batchCalled, tt.wantBatch)
+			}
+			if len(mockGraph.chunkHashes) != tt.chunks {
+				t.Errorf("persisted %d chunks, want %d", len(mockGraph.chunkHashes), tt.chunks)
+			}
+		})
+	}
+}
+
+func TestPersistenceStage_LinksChunksInReadingOrder(t *testing.T) {
+	for _, threshold := range []int{DefaultChunkBatchThreshold, 1} {
+		t.Run(fmt.Sprintf("threshold %d", threshold), func(t *testing.T) {
+			mockGraph := &mockGraphForPersistence{connected: true}
+			stage := NewPersistenceStage(mockGraph, WithChunkBatchThreshold(threshold))
+
+			result := &AnalysisResult{
+				FilePath:    "/docs/guide.md",
+				ContentHash: "guide-hash",
+				IngestMode:  ingest.ModeChunk,
+				Chunks: []AnalyzedChunk{
+					{Index: 0, Content: "intro", ContentHash: "c0"},
+					{Index: 1, Content: "setup", ContentHash: "c1"},
+					{Index: 2, Content: "usage", ContentHash: "c2"},
+				},
+			}
+			if err := stage.Persist(context.Background(), result); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := mockGraph.links["/docs/guide.md"]
+			if strings.Join(got, ",") != "c0,c1,c2" {
+				t.Errorf("linked chunks = %v, want c0,c1,c2", got)
+			}
+		})
+	}
+}
+
+func TestPersistenceStage_KeepsIngestAndChangeReasons(t *testing.T) {
+	mockGraph := &mockGraphForPersistence{connected: true}
+	stage := NewPersistenceStage(mockGraph)
+
+	result := &AnalysisResult{
+		FilePath:     "/docs/guide.md",
+		ContentHash:  "guide-hash",
+		IngestMode:   ingest.ModeMetadataOnly,
+		IngestReason: ingest.ReasonSemanticDisabled,
+		ChangeReason: ingest.ChangeModified,
+	}
+	if err := stage.Persist(context.Background(), result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	file := mockGraph.files["/docs/guide.md"]
+	if file == nil {
+		t.Fatal("expected file to be upserted")
+	}
+	if file.IngestReason != ingest.ReasonSemanticDisabled {
+		t.Errorf("IngestReason = %q, want %q", file.IngestReason, ingest.ReasonSemanticDisabled)
+	}
+	if file.ChangeReason != string(ingest.ChangeModified) {
+		t.Errorf("ChangeReason = %q, want %q", file.ChangeReason, ingest.ChangeModified)
+	}
+}
+
+func TestPersistenceStage_LinkFailureDoesNotFailFile(t *testing.T) {
+	mockGraph := &mockGraphForPersistence{connected: true, linkErr: errors.New("link failed")}
+	stage := NewPersistenceStage(mockGraph)
+
+	result := &AnalysisResult{
+		FilePath:    "/docs/guide.md",
+		ContentHash: "guide-hash",
+		IngestMode:  ingest.ModeChunk,
+		Tags:        []string{"guide"},
+		Chunks: []AnalyzedChunk{
+			{Index: 0, Content: "intro", ContentHash: "c0"},
+			{Index: 1, Content: "setup", ContentHash: "c1"},
+		},
+	}
+	if err := stage.Persist(context.Background(), result); err != nil {
+		t.Fatalf("link failure should be logged, got error: %v", err)
+	}
+	if len(mockGraph.chunkHashes) != 2 {
+		t.Errorf("persisted %d chunks, want 2", len(mockGraph.chunkHashes))
+	}
+}
+
+func TestPersistenceStage_EmbeddingsAreVersioned(t *testing.T) {
+	mockGraph := &mockGraphForPersistence{connected: true}
+	stage := NewPersistenceStage(mockGraph)
+
+	result := &AnalysisResult{
+		FilePath:    "/test/a.go",
+		ContentHash: "file-hash",
+		IngestMode:  ingest.ModeChunk,
+		Chunks: []AnalyzedChunk{{
+			Index:       0,
+			Content:     "package a",
+			ContentHash: "chunk-hash",
+			Embedding:   []float32{0.1, 0.2},
+		}},
+	}
+
+	if err := stage.Persist(context.Background(), result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	has, err := mockGraph.HasEmbedding(context.Background(), "chunk-hash", cache.EmbeddingsCacheVersion)
+	if err != nil {
+		t.Fatalf("HasEmbedding failed: %v", err)
+	}
+	if !has {
+		t.Error("expected HasEmbedding to report the persisted embedding")
+	}
+
+	has, _ = mockGraph.HasEmbedding(context.Background(), "chunk-hash", cache.EmbeddingsCacheVersion+1)
+	if has {
+		t.Error("expected HasEmbedding to reject a different version")
+	}
+}
+
 func TestPersistenceStage_WithOptions(t *testing.T) {
 	mockGraph := &mockGraphForPersistence{connected: true}
 	mockQueue := &mockPersistenceQueue{}
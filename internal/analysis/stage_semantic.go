@@ -3,10 +3,14 @@ package analysis
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"strings"
 
 	"github.com/leefowlercu/agentic-memorizer/internal/cache"
+	"github.com/leefowlercu/agentic-memorizer/internal/chunkers"
 	"github.com/leefowlercu/agentic-memorizer/internal/fsutil"
+	"github.com/leefowlercu/agentic-memorizer/internal/metrics"
 	"github.com/leefowlercu/agentic-memorizer/internal/providers"
 	"github.com/leefowlercu/agentic-memorizer/internal/registry"
 )
@@ -18,17 +22,59 @@ type SemanticStage struct {
 	registry        registry.Registry
 	analysisVersion string
 	logger          *slog.Logger
+
+	// In-memory results shared across files with identical content (nil disables)
+	summaries *SummaryCache
+
+	// Per-1k-token prices used to estimate provider cost
+	tokenRates metrics.TokenRates
+
+	// Provider rate limits shared across workers; nil does not limit
+	limiter *providers.UsageLimiter
+}
+
+// SemanticStageOption configures a SemanticStage.
+type SemanticStageOption func(*SemanticStage)
+
+// WithSummaryCache consults and populates an in-memory summary cache before
+// calling the provider.
+func WithSummaryCache(c *SummaryCache) SemanticStageOption {
+	return func(s *SemanticStage) {
+		s.summaries = c
+	}
+}
+
+// WithSemanticTokenRates sets the per-1k-token prices used to estimate
+// semantic provider cost.
+func WithSemanticTokenRates(rates metrics.TokenRates) SemanticStageOption {
+	return func(s *SemanticStage) {
+		s.tokenRates = rates
+	}
+}
+
+// WithSemanticRateLimiter makes provider calls wait for capacity under the
+// provider's request and token rate limits.
+func WithSemanticRateLimiter(l *providers.UsageLimiter) SemanticStageOption {
+	return func(s *SemanticStage) {
+		s.limiter = l
+	}
 }
 
 // NewSemanticStage creates a semantic stage.
-func NewSemanticStage(provider providers.SemanticProvider, cache *cache.SemanticCache, reg registry.Registry, analysisVersion string, logger *slog.Logger) *SemanticStage {
-	return &SemanticStage{
+func NewSemanticStage(provider providers.SemanticProvider, cache *cache.SemanticCache, reg registry.Registry, analysisVersion string, logger *slog.Logger, opts ...SemanticStageOption) *SemanticStage {
+	s := &SemanticStage{
 		provider:        provider,
 		cache:           cache,
 		registry:        reg,
 		analysisVersion: analysisVersion,
 		logger:          logger,
 	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
 // Analyze runs semantic analysis and updates registry state.
@@ -44,7 +90,13 @@ func (s *SemanticStage) Analyze(ctx context.Context, input providers.SemanticInp
 
 	cacheKey := semanticCacheKey(contentHash, input.Type, s.provider.ModelName())
 
-	if s.cache != nil {
+	if cached, ok := s.summaries.Get(contentHash, s.analysisVersion); ok {
+		semanticResult = cached
+		cacheHit = true
+		logger.Debug("summary cache hit", "path", input.Path)
+	}
+
+	if !cacheHit && s.cache != nil {
 		cachedResult, err := s.cache.Get(cacheKey)
 		if err == nil {
 			semanticResult = convertCachedSemantic(cachedResult)
@@ -60,10 +112,11 @@ func (s *SemanticStage) Analyze(ctx context.Context, input providers.SemanticInp
 	}
 
 	if !cacheHit {
-		providerResult, err := s.provider.Analyze(ctx, input)
+		providerResult, err := s.analyzeLimited(ctx, input)
 		if err != nil {
 			semanticErr = err
 		} else if providerResult != nil {
+			s.recordTokenUsage(input, providerResult)
 			semanticResult = convertProviderSemantic(providerResult)
 		}
 
@@ -74,6 +127,10 @@ func (s *SemanticStage) Analyze(ctx context.Context, input providers.SemanticInp
 		}
 	}
 
+	if semanticErr == nil {
+		s.summaries.Put(contentHash, s.analysisVersion, semanticResult)
+	}
+
 	if s.registry != nil {
 		version := analysisVersionOrDefault(s.analysisVersion)
 		if err := s.registry.UpdateSemanticState(ctx, input.Path, version, semanticErr); err != nil {
@@ -84,6 +141,74 @@ func (s *SemanticStage) Analyze(ctx context.Context, input providers.SemanticInp
 	return semanticResult, semanticErr
 }
 
+// AnalyzeChunk runs semantic analysis on a single chunk, passing its
+// neighbors and enclosing section or class as context. Chunk results are not
+// recorded in the registry, which tracks semantic state per file.
+func (s *SemanticStage) AnalyzeChunk(ctx context.Context, path string, chunks []chunkers.Chunk, index int) (*SemanticResult, error) {
+	if s.provider == nil || !s.provider.Available() {
+		return nil, nil
+	}
+
+	input, err := BuildChunkSemanticInput(path, chunks, index, s.provider)
+	if err != nil {
+		return nil, err
+	}
+
+	// Context changes the summary, so identical chunks only share results
+	// when their surroundings match too
+	n := input.Neighbors
+	contextHash := fsutil.HashBytes([]byte(strings.Join([]string{input.Text, n.Previous, n.Next, n.SectionPath, n.ClassName}, "\x00")))
+	if cached, ok := s.summaries.Get(contextHash, s.analysisVersion); ok {
+		loggerOrDefault(s.logger).Debug("chunk summary cache hit", "path", path, "chunk_index", index)
+		return cached, nil
+	}
+
+	providerResult, err := s.analyzeLimited(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if providerResult == nil {
+		return nil, nil
+	}
+	s.recordTokenUsage(input, providerResult)
+
+	result := convertProviderSemantic(providerResult)
+	s.summaries.Put(contextHash, s.analysisVersion, result)
+	return result, nil
+}
+
+// analyzeLimited calls the provider once the rate limiter has capacity.
+func (s *SemanticStage) analyzeLimited(ctx context.Context, input providers.SemanticInput) (*providers.SemanticResult, error) {
+	if err := s.limiter.Wait(ctx, semanticTokenBudget(s.provider, input)); err != nil {
+		return nil, fmt.Errorf("semantic rate limit wait failed; %w", err)
+	}
+	return s.provider.Analyze(ctx, input)
+}
+
+// recordTokenUsage records provider-reported token counts, estimating any the
+// provider did not report.
+func (s *SemanticStage) recordTokenUsage(input providers.SemanticInput, result *providers.SemanticResult) {
+	inputTokens := result.InputTokens
+	if inputTokens == 0 {
+		inputTokens = chunkers.EstimateTokens(input.Text)
+	}
+
+	outputTokens := result.OutputTokens
+	if outputTokens == 0 {
+		if result.TokensUsed > inputTokens {
+			outputTokens = result.TokensUsed - inputTokens
+		} else {
+			outputTokens = chunkers.EstimateTokens(result.Summary)
+		}
+	}
+
+	model := result.ModelName
+	if model == "" {
+		model = s.provider.ModelName()
+	}
+	metrics.RecordTokenUsage("semantic", s.provider.Name(), model, inputTokens, outputTokens, s.tokenRates)
+}
+
 func semanticCacheKey(contentHash string, inputType providers.SemanticInputType, model string) string {
 	key := contentHash + ":" + string(inputType)
 	if model != "" {
@@ -98,6 +223,11 @@ func convertProviderSemantic(result *providers.SemanticResult) *SemanticResult {
 		entities = append(entities, Entity{Name: e.Name, Type: e.Type})
 	}
 
+	relations := make([]EntityRelation, 0, len(result.Relations))
+	for _, r := range result.Relations {
+		relations = append(relations, EntityRelation{Subject: r.Subject, Predicate: r.Predicate, Object: r.Object})
+	}
+
 	refs := make([]Reference, 0, len(result.References))
 	for _, r := range result.References {
 		refs = append(refs, Reference{Type: r.Type, Target: r.Target})
@@ -113,6 +243,7 @@ func convertProviderSemantic(result *providers.SemanticResult) *SemanticResult {
 		Tags:       result.Tags,
 		Topics:     topics,
 		Entities:   entities,
+		Relations:  relations,
 		References: refs,
 		Complexity: result.Complexity,
 		Keywords:   result.Keywords,
@@ -125,6 +256,11 @@ func convertCachedSemantic(cached *providers.SemanticResult) *SemanticResult {
 		entities = append(entities, Entity{Name: e.Name, Type: e.Type})
 	}
 
+	relations := make([]EntityRelation, 0, len(cached.Relations))
+	for _, r := range cached.Relations {
+		relations = append(relations, EntityRelation{Subject: r.Subject, Predicate: r.Predicate, Object: r.Object})
+	}
+
 	refs := make([]Reference, 0, len(cached.References))
 	for _, r := range cached.References {
 		refs = append(refs, Reference{Type: r.Type, Target: r.Target})
@@ -140,6 +276,7 @@ func convertCachedSemantic(cached *providers.SemanticResult) *SemanticResult {
 		Tags:       cached.Tags,
 		Topics:     topics,
 		Entities:   entities,
+		Relations:  relations,
 		References: refs,
 		Complexity: cached.Complexity,
 		Keywords:   cached.Keywords,
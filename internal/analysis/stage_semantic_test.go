@@ -0,0 +1,123 @@
+package analysis
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/leefowlercu/agentic-memorizer/internal/chunkers"
+	"github.com/leefowlercu/agentic-memorizer/internal/metrics"
+	"github.com/leefowlercu/agentic-memorizer/internal/providers"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// capturingSemanticProvider records the inputs passed to Analyze.
+type capturingSemanticProvider struct {
+	mockSemanticProvider
+	inputs []providers.SemanticInput
+}
+
+func (m *capturingSemanticProvider) Analyze(ctx context.Context, input providers.SemanticInput) (*providers.SemanticResult, error) {
+	m.inputs = append(m.inputs, input)
+	return m.mockSemanticProvider.Analyze(ctx, input)
+}
+
+func TestSemanticStage_AnalyzeChunkPassesNeighborContext(t *testing.T) {
+	provider := &capturingSemanticProvider{mockSemanticProvider: mockSemanticProvider{available: true}}
+	stage := NewSemanticStage(provider, nil, nil, "1.0.0", nil)
+
+	chunks := []chunkers.Chunk{
+		{Index: 0, Content: "type Server struct {\n\taddr string\n}"},
+		{
+			Index:   1,
+			Content: "func (s *Server) Start() error {\n\treturn nil\n}",
+			Metadata: chunkers.ChunkMetadata{
+				Code: &chunkers.CodeMetadata{FunctionName: "Start", ClassName: "Server"},
+			},
+		},
+		{Index: 2, Content: strings.Repeat("// trailing comment\n", 200)},
+	}
+
+	result, err := stage.AnalyzeChunk(context.Background(), "/src/server.go", chunks, 1)
+	if err != nil {
+		t.Fatalf("AnalyzeChunk failed: %v", err)
+	}
+	if result == nil {
+		t.Fatal("expected a semantic result")
+	}
+
+	if len(provider.inputs) != 1 {
+		t.Fatalf("provider calls = %d, want 1", len(provider.inputs))
+	}
+	input := provider.inputs[0]
+	if input.Text != chunks[1].Content {
+		t.Errorf("Text = %q, want the method chunk", input.Text)
+	}
+	if input.Neighbors == nil {
+		t.Fatal("expected neighbor context")
+	}
+	if input.Neighbors.ClassName != "Server" {
+		t.Errorf("ClassName = %q, want Server", input.Neighbors.ClassName)
+	}
+	if input.Neighbors.Previous != chunks[0].Content {
+		t.Errorf("Previous = %q, want the preceding chunk", input.Neighbors.Previous)
+	}
+	if len(input.Neighbors.Next) > defaultNeighborContextTokens*4+len("\n[...truncated...]") {
+		t.Errorf("Next length = %d, want truncated to the neighbor budget", len(input.Neighbors.Next))
+	}
+
+	if _, err := stage.AnalyzeChunk(context.Background(), "/src/server.go", chunks, 3); err == nil {
+		t.Error("expected error for out-of-range chunk index")
+	}
+}
+
+// usageSemanticProvider reports fixed token usage for every call.
+type usageSemanticProvider struct {
+	mockSemanticProvider
+	inputTokens  int
+	outputTokens int
+}
+
+func (m *usageSemanticProvider) Analyze(ctx context.Context, input providers.SemanticInput) (*providers.SemanticResult, error) {
+	result, err := m.mockSemanticProvider.Analyze(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	result.ModelName = "usage-model"
+	result.InputTokens = m.inputTokens
+	result.OutputTokens = m.outputTokens
+	result.TokensUsed = m.inputTokens + m.outputTokens
+	return result, nil
+}
+
+func TestSemanticStage_RecordsTokenUsage(t *testing.T) {
+	provider := &usageSemanticProvider{
+		mockSemanticProvider: mockSemanticProvider{available: true},
+		inputTokens:          1200,
+		outputTokens:         300,
+	}
+	rates := metrics.TokenRates{InputPer1K: 0.01, OutputPer1K: 0.1}
+	stage := NewSemanticStage(provider, nil, nil, "1.0.0", nil, WithSemanticTokenRates(rates))
+
+	inputCounter := metrics.ProviderUsageTokensTotal.WithLabelValues("semantic", "mock-semantic", "usage-model", "input")
+	outputCounter := metrics.ProviderUsageTokensTotal.WithLabelValues("semantic", "mock-semantic", "usage-model", "output")
+	costCounter := metrics.ProviderCostDollarsTotal.WithLabelValues("semantic", "mock-semantic", "usage-model")
+	inputBefore := testutil.ToFloat64(inputCounter)
+	outputBefore := testutil.ToFloat64(outputCounter)
+	costBefore := testutil.ToFloat64(costCounter)
+
+	input := providers.SemanticInput{Path: "/a.md", Type: providers.SemanticInputText, Text: "hello"}
+	if _, err := stage.Analyze(context.Background(), input, "usagehash"); err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	if got := testutil.ToFloat64(inputCounter) - inputBefore; got != 1200 {
+		t.Errorf("input tokens delta = %v, want 1200", got)
+	}
+	if got := testutil.ToFloat64(outputCounter) - outputBefore; got != 300 {
+		t.Errorf("output tokens delta = %v, want 300", got)
+	}
+	if got := testutil.ToFloat64(costCounter) - costBefore; got < 0.0419 || got > 0.0421 {
+		t.Errorf("cost delta = %v, want 0.042", got)
+	}
+}
@@ -30,6 +30,7 @@ type SemanticResult struct {
 	Tags       []string
 	Topics     []string
 	Entities   []Entity
+	Relations  []EntityRelation
 	References []Reference
 	Complexity int
 	Keywords   []string
@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
 	"github.com/leefowlercu/agentic-memorizer/internal/chunkers"
@@ -135,7 +136,7 @@ func TestChunkerStageUsesRegistry(t *testing.T) {
 	registry.Register(chunker)
 
 	stage := NewChunkerStage(registry)
-	result, err := stage.Chunk(context.Background(), []byte("sample"), "text/plain", "")
+	result, err := stage.Chunk(context.Background(), "/docs/sample.txt", []byte("sample"), "text/plain", "")
 	if err != nil {
 		t.Fatalf("Chunk failed: %v", err)
 	}
@@ -150,3 +151,46 @@ func TestChunkerStageUsesRegistry(t *testing.T) {
 		t.Fatalf("TotalChunks = %d, want 1", result.TotalChunks)
 	}
 }
+
+func TestChunkerStageResolvesIncludes(t *testing.T) {
+	dir := t.TempDir()
+	guide := filepath.Join(dir, "guide.adoc")
+	chapter := filepath.Join(dir, "install.adoc")
+	content := []byte("= Guide\n\ninclude::install.adoc[]\n")
+	if err := os.WriteFile(guide, content, 0644); err != nil {
+		t.Fatalf("write guide failed: %v", err)
+	}
+	if err := os.WriteFile(chapter, []byte("== Installation\n\nRun the installer.\n"), 0644); err != nil {
+		t.Fatalf("write chapter failed: %v", err)
+	}
+
+	registry := chunkers.NewRegistry()
+	registry.Register(chunkers.NewAsciiDocChunker())
+
+	stage := NewChunkerStage(registry)
+	chunkResult, err := stage.Chunk(context.Background(), guide, content, "text/asciidoc", "")
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if len(chunkResult.References) != 1 || chunkResult.References[0].Target != chapter {
+		t.Fatalf("References = %+v, want include of %s", chunkResult.References, chapter)
+	}
+
+	info, err := os.Stat(guide)
+	if err != nil {
+		t.Fatalf("stat guide failed: %v", err)
+	}
+	pctx := NewPipelineContext(WorkItem{FilePath: guide}, DegradationFull, nil)
+	pctx.FileResult = &FileReadResult{Info: info}
+	pctx.ChunkResult = chunkResult
+	pctx.SemanticResult = &SemanticResult{References: []Reference{
+		{Type: "url", Target: "https://example.com"},
+		{Type: "file", Target: chapter},
+	}}
+
+	result := pctx.BuildAnalysisResult()
+	want := []Reference{{Type: "url", Target: "https://example.com"}, {Type: "file", Target: chapter}}
+	if !slices.Equal(result.References, want) {
+		t.Errorf("References = %+v, want %+v", result.References, want)
+	}
+}
@@ -0,0 +1,93 @@
+package analysis
+
+import (
+	"container/list"
+	"sync"
+)
+
+// SummaryCache is an in-memory LRU of semantic results keyed by content hash and
+// analysis version. It lets files with identical content share one provider call.
+// A nil *SummaryCache is valid and never hits.
+type SummaryCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+type summaryCacheEntry struct {
+	key    string
+	result *SemanticResult
+}
+
+// NewSummaryCache creates a summary cache holding up to capacity results.
+// A non-positive capacity returns nil, which disables caching.
+func NewSummaryCache(capacity int) *SummaryCache {
+	if capacity <= 0 {
+		return nil
+	}
+	return &SummaryCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Get returns a copy of the cached result for the content hash and analysis
+// version, so callers may adjust fields such as a redacted summary.
+func (c *SummaryCache) Get(contentHash, analysisVersion string) (*SemanticResult, bool) {
+	if c == nil || contentHash == "" {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[summaryCacheKey(contentHash, analysisVersion)]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	result := *elem.Value.(*summaryCacheEntry).result
+	return &result, true
+}
+
+// Put stores a result, evicting the least recently used entry when full.
+func (c *SummaryCache) Put(contentHash, analysisVersion string, result *SemanticResult) {
+	if c == nil || contentHash == "" || result == nil {
+		return
+	}
+
+	stored := *result
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := summaryCacheKey(contentHash, analysisVersion)
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*summaryCacheEntry).result = &stored
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&summaryCacheEntry{key: key, result: &stored})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*summaryCacheEntry).key)
+	}
+}
+
+// Len returns the number of cached results.
+func (c *SummaryCache) Len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func summaryCacheKey(contentHash, analysisVersion string) string {
+	return contentHash + ":" + analysisVersionOrDefault(analysisVersion)
+}
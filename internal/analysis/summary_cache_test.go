@@ -0,0 +1,93 @@
+package analysis
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/leefowlercu/agentic-memorizer/internal/providers"
+)
+
+func TestSemanticStage_SummaryCacheReusesIdenticalContent(t *testing.T) {
+	provider := &countingSemanticProvider{mockSemanticProvider: mockSemanticProvider{available: true}}
+	stage := NewSemanticStage(provider, nil, nil, "1.0.0", nil, WithSummaryCache(NewSummaryCache(10)))
+
+	first, err := stage.Analyze(context.Background(), providers.SemanticInput{Path: "/a/README.md"}, "samehash")
+	if err != nil {
+		t.Fatalf("first Analyze failed: %v", err)
+	}
+	second, err := stage.Analyze(context.Background(), providers.SemanticInput{Path: "/vendor/b/README.md"}, "samehash")
+	if err != nil {
+		t.Fatalf("second Analyze failed: %v", err)
+	}
+
+	if provider.calls.Load() != 1 {
+		t.Errorf("provider calls = %d, want 1", provider.calls.Load())
+	}
+	if second.Summary != first.Summary || !reflect.DeepEqual(second.Tags, first.Tags) {
+		t.Errorf("second result = %+v, want cached %+v", second, first)
+	}
+
+	// Different content still reaches the provider
+	if _, err := stage.Analyze(context.Background(), providers.SemanticInput{Path: "/c.md"}, "otherhash"); err != nil {
+		t.Fatalf("third Analyze failed: %v", err)
+	}
+	if provider.calls.Load() != 2 {
+		t.Errorf("provider calls = %d, want 2", provider.calls.Load())
+	}
+}
+
+func TestSummaryCache(t *testing.T) {
+	t.Run("KeyedByAnalysisVersion", func(t *testing.T) {
+		c := NewSummaryCache(10)
+		c.Put("hash", "1.0.0", &SemanticResult{Summary: "v1"})
+
+		if _, ok := c.Get("hash", "2.0.0"); ok {
+			t.Error("expected miss for a different analysis version")
+		}
+		if got, ok := c.Get("hash", "1.0.0"); !ok || got.Summary != "v1" {
+			t.Errorf("Get = %+v, %v; want v1 hit", got, ok)
+		}
+	})
+
+	t.Run("EvictsLeastRecentlyUsed", func(t *testing.T) {
+		c := NewSummaryCache(2)
+		c.Put("a", "", &SemanticResult{Summary: "a"})
+		c.Put("b", "", &SemanticResult{Summary: "b"})
+		c.Get("a", "")
+		c.Put("c", "", &SemanticResult{Summary: "c"})
+
+		if _, ok := c.Get("b", ""); ok {
+			t.Error("expected least recently used entry to be evicted")
+		}
+		if _, ok := c.Get("a", ""); !ok {
+			t.Error("expected recently used entry to be retained")
+		}
+		if c.Len() != 2 {
+			t.Errorf("Len = %d, want 2", c.Len())
+		}
+	})
+
+	t.Run("ReturnsCopies", func(t *testing.T) {
+		c := NewSummaryCache(10)
+		c.Put("hash", "", &SemanticResult{Summary: "secret"})
+
+		got, _ := c.Get("hash", "")
+		got.Summary = "[REDACTED]"
+
+		if again, _ := c.Get("hash", ""); again.Summary != "secret" {
+			t.Errorf("cached summary = %q, want it unchanged by callers", again.Summary)
+		}
+	})
+
+	t.Run("DisabledWhenNonPositive", func(t *testing.T) {
+		c := NewSummaryCache(0)
+		if c != nil {
+			t.Fatal("expected nil cache for zero capacity")
+		}
+		c.Put("hash", "", &SemanticResult{Summary: "x"})
+		if _, ok := c.Get("hash", ""); ok {
+			t.Error("nil cache should never hit")
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrAnalysisTimeout indicates a file exceeded the per-file analysis timeout.
+var ErrAnalysisTimeout = errors.New("TIMEOUT")
+
+// WithPerFileTimeout bounds the time a worker spends analyzing a single file.
+// Zero disables the timeout.
+func WithPerFileTimeout(d time.Duration) QueueOption {
+	return func(q *Queue) {
+		if d >= 0 {
+			q.perFileTimeout = d
+		}
+	}
+}
+
+// WithPersistPartialOnTimeout persists chunks analyzed before a per-file timeout,
+// without embeddings, instead of discarding them.
+func WithPersistPartialOnTimeout(enabled bool) QueueOption {
+	return func(q *Queue) {
+		q.persistPartialOnTimeout = enabled
+	}
+}
+
+// fileContext returns the context used to analyze a single file, bounded by
+// the per-file timeout when one is configured.
+func (w *Worker) fileContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if w.queue.perFileTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, w.queue.perFileTimeout)
+}
+
+// deadlineError returns the first stage error caused by a context deadline.
+// Stage failures are otherwise non-fatal, so this is what lets the worker tell
+// a timed-out analysis from one that finished at the deadline.
+func deadlineError(errs ...error) error {
+	for _, err := range errs {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return err
+		}
+	}
+	return nil
+}
+
+// timeoutPersistence returns the stage that persists partial results: the
+// pipeline's, which falls back to the configured persistence queue, or a
+// graph-only stage when the worker has no pipeline.
+func (w *Worker) timeoutPersistence() PersistenceStageInterface {
+	if w.pipeline != nil && w.pipeline.persistence != nil {
+		return w.pipeline.persistence
+	}
+	return NewPersistenceStage(w.graph, WithPersistenceLogger(w.logger), WithPersistenceRegistry(w.registry))
+}
+
+// handleTimeout records a per-file timeout as an analysis failure without
+// retrying, so the worker moves on to the next item. When configured, chunks
+// produced before the deadline are persisted even if embeddings are missing.
+func (w *Worker) handleTimeout(ctx context.Context, item WorkItem, result *AnalysisResult) {
+	timeoutErr := fmt.Errorf("%w; analysis exceeded per-file timeout of %s", ErrAnalysisTimeout, w.queue.perFileTimeout)
+
+	w.logger.Warn("analysis timed out",
+		"path", item.FilePath,
+		"timeout", w.queue.perFileTimeout)
+
+	if w.registry != nil {
+		if result == nil || result.Summary == "" {
+			if err := w.registry.UpdateSemanticState(ctx, item.FilePath, w.analysisVersion, timeoutErr); err != nil {
+				w.logger.Warn("failed to record semantic timeout", "path", item.FilePath, "error", err)
+			}
+		}
+		if err := w.registry.UpdateEmbeddingsState(ctx, item.FilePath, "", timeoutErr); err != nil {
+			w.logger.Warn("failed to record embeddings timeout", "path", item.FilePath, "error", err)
+		}
+	}
+
+	if w.queue.persistPartialOnTimeout && result != nil && len(result.Chunks) > 0 {
+		if err := w.timeoutPersistence().Persist(ctx, result); err != nil {
+			w.logger.Warn("failed to persist partial results after timeout",
+				"path", item.FilePath,
+				"error", err)
+		}
+	}
+
+	w.queue.recordAnalysisFailure()
+	w.queue.publishAnalysisFailed(item.FilePath, timeoutErr)
+}
@@ -0,0 +1,245 @@
+package analysis
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/leefowlercu/agentic-memorizer/internal/events"
+	"github.com/leefowlercu/agentic-memorizer/internal/providers"
+	"github.com/leefowlercu/agentic-memorizer/internal/registry"
+)
+
+// slowSemanticProvider blocks its first Analyze call until the context is done.
+type slowSemanticProvider struct {
+	mockSemanticProvider
+	calls atomic.Int32
+}
+
+func (m *slowSemanticProvider) Analyze(ctx context.Context, input providers.SemanticInput) (*providers.SemanticResult, error) {
+	if m.calls.Add(1) == 1 {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return m.mockSemanticProvider.Analyze(ctx, input)
+}
+
+func TestWorkerPerFileTimeout(t *testing.T) {
+	for _, persistPartial := range []bool{false, true} {
+		name := "DiscardPartial"
+		if persistPartial {
+			name = "PersistPartial"
+		}
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			bus := events.NewBus()
+			defer bus.Close()
+
+			reg, err := registry.Open(ctx, filepath.Join(t.TempDir(), "registry.db"))
+			if err != nil {
+				t.Fatalf("failed to open registry: %v", err)
+			}
+			defer reg.Close()
+
+			queue := NewQueue(bus,
+				WithPerFileTimeout(50*time.Millisecond),
+				WithPersistPartialOnTimeout(persistPartial))
+			queue.ctx = ctx
+
+			mockG := &mockGraph{}
+			worker := NewWorker(0, queue)
+			worker.SetRegistry(reg)
+			worker.SetSemanticProvider(&slowSemanticProvider{mockSemanticProvider: mockSemanticProvider{available: true}})
+			worker.SetGraph(mockG)
+
+			failed := make(chan *events.AnalysisEvent, 2)
+			unsub := bus.Subscribe(events.AnalysisFailed, func(e events.Event) {
+				if ae, ok := e.Payload.(*events.AnalysisEvent); ok {
+					failed <- ae
+				}
+			})
+			defer unsub()
+
+			dir := t.TempDir()
+			newItem := func(name string) WorkItem {
+				path := filepath.Join(dir, name)
+				if err := os.WriteFile(path, []byte("per-file timeout content"), 0644); err != nil {
+					t.Fatalf("write file failed: %v", err)
+				}
+				info, err := os.Stat(path)
+				if err != nil {
+					t.Fatalf("stat file failed: %v", err)
+				}
+				return WorkItem{FilePath: path, FileSize: info.Size(), ModTime: info.ModTime(), EventType: WorkItemNew}
+			}
+			slow := newItem("slow.txt")
+			fast := newItem("fast.txt")
+
+			start := time.Now()
+			if err := worker.processItem(ctx, slow); err != nil {
+				t.Fatalf("processItem returned error for timed out item: %v", err)
+			}
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Fatalf("processItem blocked for %v; expected per-file timeout", elapsed)
+			}
+
+			select {
+			case ae := <-failed:
+				if ae.Path != slow.FilePath {
+					t.Errorf("failed path = %q, want %q", ae.Path, slow.FilePath)
+				}
+				if !strings.Contains(ae.Error, "TIMEOUT") {
+					t.Errorf("failed error = %q, want TIMEOUT", ae.Error)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("expected AnalysisFailed event for timed out item")
+			}
+
+			state, err := reg.GetFileState(ctx, slow.FilePath)
+			if err != nil {
+				t.Fatalf("failed to get file state: %v", err)
+			}
+			if state.SemanticError == nil || !strings.Contains(*state.SemanticError, "TIMEOUT") {
+				t.Errorf("SemanticError = %v, want TIMEOUT", state.SemanticError)
+			}
+			if got := queue.analysisFailedCount.Load(); got != 1 {
+				t.Errorf("analysisFailedCount = %d, want 1", got)
+			}
+
+			persisted := len(mockG.chunks) > 0
+			if persisted != persistPartial {
+				t.Errorf("partial chunks persisted = %v, want %v", persisted, persistPartial)
+			}
+
+			// The worker moves on and completes the next item
+			if err := worker.processItem(ctx, fast); err != nil {
+				t.Fatalf("processItem failed for next item: %v", err)
+			}
+			if got := queue.processedCount.Load(); got != 1 {
+				t.Errorf("processedCount = %d, want 1", got)
+			}
+			select {
+			case ae := <-failed:
+				t.Errorf("unexpected AnalysisFailed for %q: %s", ae.Path, ae.Error)
+			default:
+			}
+		})
+	}
+}
+
+// lateSemanticProvider ignores cancellation and succeeds after the deadline.
+type lateSemanticProvider struct {
+	mockSemanticProvider
+	delay time.Duration
+}
+
+func (m *lateSemanticProvider) Analyze(ctx context.Context, input providers.SemanticInput) (*providers.SemanticResult, error) {
+	time.Sleep(m.delay)
+	return m.mockSemanticProvider.Analyze(ctx, input)
+}
+
+// blockingEmbeddingsStage blocks until the context is done.
+type blockingEmbeddingsStage struct{}
+
+func (blockingEmbeddingsStage) Generate(ctx context.Context, path string, analyzedChunks []AnalyzedChunk) ([]float32, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestWorkerPerFileTimeoutCompletedAtDeadline(t *testing.T) {
+	ctx := context.Background()
+	bus := events.NewBus()
+	defer bus.Close()
+
+	queue := NewQueue(bus, WithPerFileTimeout(20*time.Millisecond))
+	queue.ctx = ctx
+
+	worker := NewWorker(0, queue)
+	worker.SetSemanticProvider(&lateSemanticProvider{
+		mockSemanticProvider: mockSemanticProvider{available: true},
+		delay:                50 * time.Millisecond,
+	})
+	worker.SetGraph(&mockGraph{})
+
+	failed := make(chan *events.AnalysisEvent, 1)
+	unsub := bus.Subscribe(events.AnalysisFailed, func(e events.Event) {
+		if ae, ok := e.Payload.(*events.AnalysisEvent); ok {
+			failed <- ae
+		}
+	})
+	defer unsub()
+
+	path := filepath.Join(t.TempDir(), "late.txt")
+	if err := os.WriteFile(path, []byte("finished at the deadline"), 0644); err != nil {
+		t.Fatalf("write file failed: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat file failed: %v", err)
+	}
+	item := WorkItem{FilePath: path, FileSize: info.Size(), ModTime: info.ModTime(), EventType: WorkItemNew}
+
+	if err := worker.processItem(ctx, item); err != nil {
+		t.Fatalf("processItem failed: %v", err)
+	}
+	if got := queue.processedCount.Load(); got != 1 {
+		t.Errorf("processedCount = %d, want 1", got)
+	}
+	if got := queue.analysisFailedCount.Load(); got != 0 {
+		t.Errorf("analysisFailedCount = %d, want 0", got)
+	}
+	select {
+	case ae := <-failed:
+		t.Errorf("unexpected AnalysisFailed for %q: %s", ae.Path, ae.Error)
+	default:
+	}
+}
+
+func TestWorkerPerFileTimeoutPersistsThroughPipeline(t *testing.T) {
+	ctx := context.Background()
+	bus := events.NewBus()
+	defer bus.Close()
+
+	queue := NewQueue(bus,
+		WithPerFileTimeout(50*time.Millisecond),
+		WithPersistPartialOnTimeout(true))
+	queue.ctx = ctx
+
+	mockG := &mockGraph{}
+	persist := &mockPersistenceStage{}
+	worker := NewWorker(0, queue)
+	worker.SetGraph(mockG)
+	worker.SetPipeline(NewPipeline(PipelineConfig{},
+		WithFileReader(&mockFileReaderStage{}),
+		WithChunker(&mockChunkerStage{}),
+		WithSemantic(nil),
+		WithEmbeddings(blockingEmbeddingsStage{}),
+		WithPersistence(persist)))
+
+	path := filepath.Join(t.TempDir(), "slow.txt")
+	if err := os.WriteFile(path, []byte("pipeline timeout content"), 0644); err != nil {
+		t.Fatalf("write file failed: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat file failed: %v", err)
+	}
+	item := WorkItem{FilePath: path, FileSize: info.Size(), ModTime: info.ModTime(), EventType: WorkItemNew}
+
+	if err := worker.processItem(ctx, item); err != nil {
+		t.Fatalf("processItem returned error for timed out item: %v", err)
+	}
+	if got := queue.analysisFailedCount.Load(); got != 1 {
+		t.Errorf("analysisFailedCount = %d, want 1", got)
+	}
+	if len(persist.persisted) != 1 || len(persist.persisted[0].Chunks) == 0 {
+		t.Fatalf("pipeline persisted %d results, want 1 with chunks", len(persist.persisted))
+	}
+	if len(mockG.chunks) != 0 {
+		t.Errorf("partial chunks written directly to graph = %d, want 0", len(mockG.chunks))
+	}
+}
@@ -0,0 +1,132 @@
+package analysis
+
+import (
+	"container/heap"
+	"sync"
+)
+
+// workQueue is a bounded buffer of work items ordered by priority. Items with a
+// higher Priority are dequeued first; items of equal priority are dequeued in
+// the order they were pushed.
+type workQueue struct {
+	mu       sync.Mutex
+	items    workHeap
+	seq      uint64
+	capacity int
+	closed   bool
+
+	// ready holds one token per queued item so workers can select on it.
+	ready chan struct{}
+}
+
+// newWorkQueue creates a work queue holding at most capacity items.
+func newWorkQueue(capacity int) *workQueue {
+	return &workQueue{
+		capacity: capacity,
+		ready:    make(chan struct{}, capacity),
+	}
+}
+
+// push adds an item. Returns false if the queue is full or closed.
+func (wq *workQueue) push(item WorkItem) bool {
+	wq.mu.Lock()
+	defer wq.mu.Unlock()
+
+	if wq.closed || len(wq.items) >= wq.capacity {
+		return false
+	}
+
+	wq.seq++
+	heap.Push(&wq.items, queuedItem{item: item, seq: wq.seq})
+
+	// Never blocks: tokens never outnumber queued items, which are bounded by capacity
+	wq.ready <- struct{}{}
+	return true
+}
+
+// pop removes the highest priority item. Callers receive a token from ready first.
+func (wq *workQueue) pop() (WorkItem, bool) {
+	wq.mu.Lock()
+	defer wq.mu.Unlock()
+
+	if len(wq.items) == 0 {
+		return WorkItem{}, false
+	}
+	return heap.Pop(&wq.items).(queuedItem).item, true
+}
+
+// release returns a token received from ready without popping an item.
+func (wq *workQueue) release() {
+	wq.mu.Lock()
+	defer wq.mu.Unlock()
+
+	if wq.closed {
+		return
+	}
+	// Never blocks: the token's item is still queued
+	wq.ready <- struct{}{}
+}
+
+// len returns the number of queued items.
+func (wq *workQueue) len() int {
+	if wq == nil {
+		return 0
+	}
+	wq.mu.Lock()
+	defer wq.mu.Unlock()
+	return len(wq.items)
+}
+
+// paths returns the set of file paths of the queued items.
+func (wq *workQueue) paths() map[string]struct{} {
+	wq.mu.Lock()
+	defer wq.mu.Unlock()
+
+	paths := make(map[string]struct{}, len(wq.items))
+	for _, qi := range wq.items {
+		paths[qi.item.FilePath] = struct{}{}
+	}
+	return paths
+}
+
+// close rejects further pushes and closes ready so idle workers exit.
+func (wq *workQueue) close() {
+	wq.mu.Lock()
+	defer wq.mu.Unlock()
+
+	if wq.closed {
+		return
+	}
+	wq.closed = true
+	close(wq.ready)
+}
+
+// queuedItem is a work item with its push sequence for FIFO tie-breaking.
+type queuedItem struct {
+	item WorkItem
+	seq  uint64
+}
+
+// workHeap implements heap.Interface ordered by priority, then push order.
+type workHeap []queuedItem
+
+func (h workHeap) Len() int { return len(h) }
+
+func (h workHeap) Less(i, j int) bool {
+	if h[i].item.Priority != h[j].item.Priority {
+		return h[i].item.Priority > h[j].item.Priority
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h workHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *workHeap) Push(x any) { *h = append(*h, x.(queuedItem)) }
+
+func (h *workHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
@@ -0,0 +1,61 @@
+package analysis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/leefowlercu/agentic-memorizer/internal/events"
+)
+
+func TestWorkQueueHighPriorityFirst(t *testing.T) {
+	bus := events.NewBus()
+	defer bus.Close()
+
+	queue := NewQueue(bus)
+	queue.ctx = context.Background()
+	queue.state = QueueStateRunning
+	queue.work = newWorkQueue(2)
+
+	if err := queue.Enqueue(WorkItem{FilePath: "/repo/bulk.go"}); err != nil {
+		t.Fatalf("Enqueue low priority failed: %v", err)
+	}
+	if err := queue.Enqueue(WorkItem{FilePath: "/repo/remembered.go", Priority: events.FilePriorityExplicit}); err != nil {
+		t.Fatalf("Enqueue high priority failed: %v", err)
+	}
+
+	// Capacity counts items of every priority
+	if got := queue.Stats().PendingItems; got != 2 {
+		t.Errorf("PendingItems = %d, want 2", got)
+	}
+	if err := queue.Enqueue(WorkItem{FilePath: "/repo/overflow.go"}); err == nil {
+		t.Error("expected enqueue beyond capacity to fail")
+	}
+
+	var order []string
+	for range 2 {
+		<-queue.work.ready
+		item, ok := queue.work.pop()
+		if !ok {
+			t.Fatal("expected queued item")
+		}
+		order = append(order, item.FilePath)
+	}
+	assertOrder(t, order, []string{"/repo/remembered.go", "/repo/bulk.go"})
+}
+
+func TestWorkQueueEqualPriorityIsFIFO(t *testing.T) {
+	wq := newWorkQueue(3)
+	for _, path := range []string{"/a", "/b", "/c"} {
+		if !wq.push(WorkItem{FilePath: path, Priority: 1}) {
+			t.Fatalf("push(%q) rejected", path)
+		}
+	}
+
+	var order []string
+	for range 3 {
+		<-wq.ready
+		item, _ := wq.pop()
+		order = append(order, item.FilePath)
+	}
+	assertOrder(t, order, []string{"/a", "/b", "/c"})
+}
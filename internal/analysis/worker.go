@@ -2,6 +2,7 @@ package analysis
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -36,6 +37,20 @@ type WorkItem struct {
 	ModTime   time.Time
 	EventType WorkItemType
 	Retries   int
+
+	// Priority orders queued items; higher values are analyzed first.
+	Priority int
+
+	// DryRun chunks the file and reports projected token and embedding cost
+	// without calling providers, updating the registry, or writing the graph.
+	DryRun bool
+
+	// pendingID identifies the item's record in a persistent queue, if any.
+	pendingID int64
+
+	// pendingEnqueuedAt is when the item's record was written. A record
+	// rewritten by a later event for the same path is left for that event.
+	pendingEnqueuedAt time.Time
 }
 
 // AnalysisResult contains the complete analysis of a file.
@@ -51,12 +66,14 @@ type AnalysisResult struct {
 	IngestKind   ingest.Kind
 	IngestMode   ingest.Mode
 	IngestReason string
+	ChangeReason ingest.ChangeReason
 
 	// Semantic analysis
 	Summary    string
 	Tags       []string
 	Topics     []string
 	Entities   []Entity
+	Relations  []EntityRelation
 	References []Reference
 	Complexity int
 	Keywords   []string
@@ -84,14 +101,24 @@ type AnalyzedChunk struct {
 	ChunkType   string
 	Embedding   []float32
 
+	// EmbeddingProvider and EmbeddingModel identify what produced Embedding.
+	EmbeddingProvider string
+	EmbeddingModel    string
+
 	// Metadata contains typed metadata from chunking (Code, Document, etc.)
 	Metadata *chunkers.ChunkMetadata
 
 	// TokenCount is the estimated token count.
 	TokenCount int
 
-	// Summary is an optional per-chunk summary (unused in per-file semantics).
+	// Summary is the chunk's own summary, set when per-chunk summaries are enabled.
 	Summary string
+
+	// Redacted indicates secrets were replaced in Content.
+	Redacted bool
+
+	// SkipEmbedding excludes the chunk from embeddings generation.
+	SkipEmbedding bool
 }
 
 // Entity represents an extracted entity.
@@ -100,6 +127,13 @@ type Entity struct {
 	Type string
 }
 
+// EntityRelation represents an extracted relation between two entities.
+type EntityRelation struct {
+	Subject   string
+	Predicate string
+	Object    string
+}
+
 // Reference represents an extracted reference.
 type Reference struct {
 	Type   string // "file", "url", "symbol"
@@ -134,6 +168,7 @@ type Worker struct {
 	// Caches for avoiding redundant API calls
 	semanticCache   *cache.SemanticCache
 	embeddingsCache *cache.EmbeddingsCache
+	summaryCache    *SummaryCache
 }
 
 // NewWorker creates a new analysis worker.
@@ -166,11 +201,21 @@ func (w *Worker) Run(ctx context.Context) {
 		case <-w.stopChan:
 			w.logger.Debug("worker stopping due to stop signal")
 			return
-		case item, ok := <-w.queue.workChan:
+		case _, ok := <-w.queue.work.ready:
 			if !ok {
-				w.logger.Debug("worker stopping due to closed channel")
+				w.logger.Debug("worker stopping due to closed queue")
 				return
 			}
+			if !w.awaitResume(ctx) {
+				// Hand the token back so the item stays available to other workers
+				w.queue.work.release()
+				w.logger.Debug("worker stopping while paused")
+				return
+			}
+			item, ok := w.queue.work.pop()
+			if !ok {
+				continue
+			}
 			if err := w.processItem(ctx, item); err != nil {
 				select {
 				case w.queue.errChan <- err:
@@ -190,9 +235,22 @@ func (w *Worker) Stop() {
 
 // processItem handles a single work item with retry logic.
 func (w *Worker) processItem(ctx context.Context, item WorkItem) error {
+	if item.DryRun {
+		return w.processDryRun(ctx, item)
+	}
+
 	start := time.Now()
 
-	result, err := w.analyze(ctx, item)
+	fileCtx, cancel := w.fileContext(ctx)
+	result, err := w.analyze(fileCtx, item)
+	// An analysis that completed right at the deadline is not a timeout
+	timedOut := err != nil && errors.Is(fileCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil
+	cancel()
+	if timedOut {
+		w.handleTimeout(ctx, item, result)
+		w.queue.completePending(item)
+		return nil
+	}
 	if err != nil {
 		if item.Retries < w.queue.maxRetries {
 			item.Retries++
@@ -205,6 +263,7 @@ func (w *Worker) processItem(ctx context.Context, item WorkItem) error {
 			time.AfterFunc(delay, func() {
 				if err := w.queue.Enqueue(item); err != nil {
 					w.logger.Error("failed to re-queue item", "path", item.FilePath, "error", err)
+					w.queue.recordRetryDropped()
 				}
 			})
 			return nil
@@ -215,6 +274,8 @@ func (w *Worker) processItem(ctx context.Context, item WorkItem) error {
 			"error", err,
 			"retries", item.Retries)
 		w.queue.recordAnalysisFailure()
+		w.queue.recordDeadLetter(item, err)
+		w.queue.completePending(item)
 		w.queue.publishAnalysisFailed(item.FilePath, err)
 		return fmt.Errorf("analysis failed permanently; %w", err)
 	}
@@ -222,7 +283,7 @@ func (w *Worker) processItem(ctx context.Context, item WorkItem) error {
 	duration := time.Since(start)
 	result.ProcessingTime = duration
 
-	persistenceStage := NewPersistenceStage(w.graph, WithPersistenceLogger(w.logger))
+	persistenceStage := NewPersistenceStage(w.graph, WithPersistenceLogger(w.logger), WithPersistenceRegistry(w.registry))
 	if err := persistenceStage.Persist(ctx, result); err != nil {
 		if item.Retries < w.queue.maxRetries {
 			item.Retries++
@@ -235,6 +296,7 @@ func (w *Worker) processItem(ctx context.Context, item WorkItem) error {
 			time.AfterFunc(delay, func() {
 				if err := w.queue.Enqueue(item); err != nil {
 					w.logger.Error("failed to re-queue item", "path", item.FilePath, "error", err)
+					w.queue.recordRetryDropped()
 				}
 			})
 			return nil
@@ -245,11 +307,15 @@ func (w *Worker) processItem(ctx context.Context, item WorkItem) error {
 			"error", err,
 			"retries", item.Retries)
 		w.queue.recordPersistenceFailure()
+		w.queue.recordDeadLetter(item, err)
+		w.queue.completePending(item)
 		w.queue.publishGraphPersistenceFailed(item.FilePath, err, item.Retries)
 		return fmt.Errorf("graph persistence failed permanently; %w", err)
 	}
 
 	w.queue.recordSuccess(duration)
+	w.queue.clearDeadLetter(item.FilePath)
+	w.queue.completePending(item)
 	w.queue.publishAnalysisComplete(item.FilePath, result)
 	w.logger.Info("analysis complete",
 		"path", item.FilePath,
@@ -276,6 +342,10 @@ func (w *Worker) analyze(ctx context.Context, item WorkItem) (*AnalysisResult, e
 		return w.analyzeWithPipeline(ctx, item, mode)
 	}
 
+	// Stage errors caused by the per-file deadline are returned alongside the
+	// partial result; other stage failures only degrade the result.
+	var semanticErr, embeddingsErr error
+
 	semanticEnabled := w.semanticProvider != nil && w.semanticProvider.Available()
 	fileReader := NewFileReader(w.registry, WithSemanticEnabled(semanticEnabled))
 	fileResult, err := fileReader.Read(ctx, item, mode)
@@ -297,7 +367,7 @@ func (w *Worker) analyze(ctx context.Context, item WorkItem) (*AnalysisResult, e
 		MetadataHash: fileResult.MetadataHash,
 	}
 
-	w.syncMetadataState(ctx, result)
+	w.syncMetadataState(ctx, item, result)
 
 	if fileResult.IngestMode == ingest.ModeMetadataOnly || fileResult.IngestMode == ingest.ModeSkip {
 		w.updateRegistryForMetadataOnly(ctx, result, fileResult.DegradedMetadata, fileResult.IngestReason)
@@ -313,12 +383,13 @@ func (w *Worker) analyze(ctx context.Context, item WorkItem) (*AnalysisResult, e
 	if fileResult.IngestMode == ingest.ModeSemanticOnly {
 		if w.semanticProvider != nil && w.semanticProvider.Available() {
 			semanticStart := time.Now()
-			semanticStage := NewSemanticStage(w.semanticProvider, w.semanticCache, w.registry, w.analysisVersion, w.logger)
+			semanticStage := NewSemanticStage(w.semanticProvider, w.semanticCache, w.registry, w.analysisVersion, w.logger, WithSummaryCache(w.summaryCache), WithSemanticRateLimiter(stageLimiter(w.queue.rateLimiters, w.semanticProvider)))
 			input, buildErr := BuildSemanticInput(item.FilePath, fileResult, nil, w.semanticProvider)
 			if buildErr != nil {
 				w.logger.Warn("semantic input build failed", "path", item.FilePath, "error", buildErr)
 			} else {
-				semanticResult, semanticErr := semanticStage.Analyze(ctx, input, result.ContentHash)
+				var semanticResult *SemanticResult
+				semanticResult, semanticErr = semanticStage.Analyze(ctx, input, result.ContentHash)
 				semanticDuration := time.Since(semanticStart)
 				if semanticErr != nil {
 					w.logger.Warn("semantic analysis failed",
@@ -332,6 +403,7 @@ func (w *Worker) analyze(ctx context.Context, item WorkItem) (*AnalysisResult, e
 					result.Tags = semanticResult.Tags
 					result.Topics = semanticResult.Topics
 					result.Entities = semanticResult.Entities
+					result.Relations = semanticResult.Relations
 					result.References = semanticResult.References
 					result.Complexity = semanticResult.Complexity
 					result.Keywords = semanticResult.Keywords
@@ -341,19 +413,18 @@ func (w *Worker) analyze(ctx context.Context, item WorkItem) (*AnalysisResult, e
 			}
 		}
 		if w.registry != nil {
-			if err := w.registry.UpdateEmbeddingsState(ctx, result.FilePath, nil); err != nil {
+			if err := w.registry.UpdateEmbeddingsState(ctx, result.FilePath, "", nil); err != nil {
 				w.logger.Warn("failed to update embeddings state", "path", result.FilePath, "error", err)
 			}
 		}
-		return result, nil
+		return result, deadlineError(semanticErr)
 	}
 
 	chunkerStage := NewChunkerStage(w.chunkerRegistry)
-	chunkResult, err := chunkerStage.Chunk(ctx, fileResult.Content, fileResult.MIMEType, fileResult.Language)
+	chunkResult, err := chunkerStage.Chunk(ctx, item.FilePath, fileResult.Content, fileResult.MIMEType, fileResult.Language)
 	if err != nil {
 		return nil, fmt.Errorf("chunking failed; %w", err)
 	}
-
 	result.ChunkerUsed = chunkResult.ChunkerUsed
 	result.ChunksProcessed = chunkResult.TotalChunks
 
@@ -363,12 +434,13 @@ func (w *Worker) analyze(ctx context.Context, item WorkItem) (*AnalysisResult, e
 
 	if w.semanticProvider != nil && w.semanticProvider.Available() {
 		semanticStart := time.Now()
-		semanticStage := NewSemanticStage(w.semanticProvider, w.semanticCache, w.registry, w.analysisVersion, w.logger)
+		semanticStage := NewSemanticStage(w.semanticProvider, w.semanticCache, w.registry, w.analysisVersion, w.logger, WithSummaryCache(w.summaryCache), WithSemanticRateLimiter(stageLimiter(w.queue.rateLimiters, w.semanticProvider)))
 		input, buildErr := BuildSemanticInput(item.FilePath, fileResult, chunkResult, w.semanticProvider)
 		if buildErr != nil {
 			w.logger.Warn("semantic input build failed", "path", item.FilePath, "error", buildErr)
 		} else {
-			semanticResult, semanticErr := semanticStage.Analyze(ctx, input, result.ContentHash)
+			var semanticResult *SemanticResult
+			semanticResult, semanticErr = semanticStage.Analyze(ctx, input, result.ContentHash)
 			semanticDuration := time.Since(semanticStart)
 			if semanticErr != nil {
 				w.logger.Warn("semantic analysis failed",
@@ -382,6 +454,7 @@ func (w *Worker) analyze(ctx context.Context, item WorkItem) (*AnalysisResult, e
 				result.Tags = semanticResult.Tags
 				result.Topics = semanticResult.Topics
 				result.Entities = semanticResult.Entities
+				result.Relations = semanticResult.Relations
 				result.References = semanticResult.References
 				result.Complexity = semanticResult.Complexity
 				result.Keywords = semanticResult.Keywords
@@ -391,18 +464,21 @@ func (w *Worker) analyze(ctx context.Context, item WorkItem) (*AnalysisResult, e
 		}
 	}
 
+	result.References = mergeChunkReferences(result.References, chunkResult)
+
 	// Always populate result.Chunks regardless of embeddings mode.
 	// This ensures chunks are persisted even when embeddings are skipped.
 	result.Chunks = analyzedChunks
 
 	if mode == DegradationNoEmbed {
-		return result, nil
+		return result, deadlineError(semanticErr)
 	}
 
 	if w.embeddingsProvider != nil && w.embeddingsProvider.Available() {
 		embeddingsStart := time.Now()
-		embeddingsStage := NewEmbeddingsStage(w.embeddingsProvider, w.embeddingsCache, w.registry, w.logger)
-		embeddings, embeddingsErr := embeddingsStage.Generate(ctx, item.FilePath, result.Chunks)
+		embeddingsStage := NewEmbeddingsStage(w.embeddingsProvider, w.embeddingsCache, w.registry, w.logger, WithEmbeddingsRateLimiter(stageLimiter(w.queue.rateLimiters, w.embeddingsProvider)), WithEmbeddingsGraph(w.graph))
+		var embeddings []float32
+		embeddings, embeddingsErr = embeddingsStage.Generate(ctx, item.FilePath, result.Chunks)
 		embeddingsDuration := time.Since(embeddingsStart)
 		if embeddingsErr != nil {
 			w.logger.Warn("embeddings generation failed",
@@ -416,11 +492,12 @@ func (w *Worker) analyze(ctx context.Context, item WorkItem) (*AnalysisResult, e
 		}
 	}
 
-	return result, nil
+	return result, deadlineError(semanticErr, embeddingsErr)
 }
 
-func (w *Worker) syncMetadataState(ctx context.Context, result *AnalysisResult) {
+func (w *Worker) syncMetadataState(ctx context.Context, item WorkItem, result *AnalysisResult) {
 	if w.registry == nil {
+		result.ChangeReason = changeReason(item.EventType, nil, result.ContentHash, w.analysisVersion)
 		return
 	}
 
@@ -429,7 +506,11 @@ func (w *Worker) syncMetadataState(ctx context.Context, result *AnalysisResult)
 	// process the same file concurrently. This is rare in practice but possible
 	// during full rebuilds. The registry should ideally support atomic compare-and-swap.
 	existingState, err := w.registry.GetFileState(ctx, result.FilePath)
-	if err == nil && existingState.ContentHash != result.ContentHash {
+	if err != nil {
+		existingState = nil
+	}
+	result.ChangeReason = changeReason(item.EventType, existingState, result.ContentHash, w.analysisVersion)
+	if existingState != nil && existingState.ContentHash != result.ContentHash {
 		w.logger.Debug("content changed; clearing analysis state",
 			"path", result.FilePath,
 			"old_hash", existingState.ContentHash[:8],
@@ -444,6 +525,33 @@ func (w *Worker) syncMetadataState(ctx context.Context, result *AnalysisResult)
 	}
 }
 
+// changeReason decides why a file is being ingested from its work item type
+// and the registry state recorded before this analysis.
+func changeReason(eventType WorkItemType, existing *registry.FileState, contentHash, analysisVersion string) ingest.ChangeReason {
+	if eventType == WorkItemReanalyze {
+		return ingest.ChangeForced
+	}
+	if existing == nil {
+		if eventType == WorkItemChanged {
+			return ingest.ChangeModified
+		}
+		return ingest.ChangeNew
+	}
+	if existing.ContentHash != contentHash {
+		return ingest.ChangeModified
+	}
+	if existing.AnalysisVersion != "" && existing.AnalysisVersion != analysisVersionOrDefault(analysisVersion) {
+		return ingest.ChangeVersionBump
+	}
+	if existing.NeedsRechunk(chunkers.Version) {
+		return ingest.ChangeVersionBump
+	}
+	if eventType == WorkItemChanged {
+		return ingest.ChangeModified
+	}
+	return ingest.ChangeForced
+}
+
 func (w *Worker) updateRegistryForMetadataOnly(ctx context.Context, result *AnalysisResult, degradedMetadata bool, ingestReason string) {
 	if w.registry == nil || degradedMetadata {
 		return
@@ -456,7 +564,7 @@ func (w *Worker) updateRegistryForMetadataOnly(ctx context.Context, result *Anal
 	if err := w.registry.UpdateSemanticState(ctx, result.FilePath, version, nil); err != nil {
 		w.logger.Warn("failed to update semantic state", "path", result.FilePath, "error", err)
 	}
-	if err := w.registry.UpdateEmbeddingsState(ctx, result.FilePath, nil); err != nil {
+	if err := w.registry.UpdateEmbeddingsState(ctx, result.FilePath, "", nil); err != nil {
 		w.logger.Warn("failed to update embeddings state", "path", result.FilePath, "error", err)
 	}
 }
@@ -466,7 +574,8 @@ func (w *Worker) analyzeWithPipeline(ctx context.Context, item WorkItem, mode De
 	pctx := NewPipelineContext(item, mode, w.logger)
 
 	if err := w.pipeline.Execute(ctx, pctx); err != nil {
-		return nil, err
+		// A deadline error still carries the partial result for timeout handling
+		return pctx.AnalysisResult, err
 	}
 
 	// Publish stage-specific events for observability
@@ -561,6 +670,11 @@ func (w *Worker) SetCaches(semantic *cache.SemanticCache, embeddings *cache.Embe
 	w.embeddingsCache = embeddings
 }
 
+// SetSummaryCache sets the in-memory cache shared by files with identical content.
+func (w *Worker) SetSummaryCache(c *SummaryCache) {
+	w.summaryCache = c
+}
+
 // SetPipeline sets the analysis pipeline.
 // When a pipeline is set, the worker delegates analysis to it instead of
 // using individual stages directly.
@@ -0,0 +1,64 @@
+package chunkers
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Matches markdown links to in-document anchors: [text](#anchor)
+var markdownAnchorLinkRegex = regexp.MustCompile(`\]\(#([^)\s]+)\)`)
+
+// Matches AsciiDoc cross references: <<anchor>>, <<anchor,text>>, xref:anchor[] and xref:#anchor[]
+var asciidocAnchorLinkRegex = regexp.MustCompile(`<<([^,>\s]+)(?:,[^>]*)?>>|xref:#?([^\[\s/.]+)\[`)
+
+// extractMarkdownAnchors returns the anchors referenced by markdown in-document links.
+func extractMarkdownAnchors(text string) []string {
+	return collectAnchors(markdownAnchorLinkRegex.FindAllStringSubmatch(text, -1))
+}
+
+// extractAsciiDocAnchors returns the anchors referenced by AsciiDoc cross references.
+func extractAsciiDocAnchors(text string) []string {
+	return collectAnchors(asciidocAnchorLinkRegex.FindAllStringSubmatch(text, -1))
+}
+
+// collectAnchors returns the first non-empty capture group of each match, deduplicated.
+func collectAnchors(matches [][]string) []string {
+	var anchors []string
+	seen := make(map[string]bool)
+	for _, match := range matches {
+		for _, group := range match[1:] {
+			if group == "" {
+				continue
+			}
+			if !seen[group] {
+				seen[group] = true
+				anchors = append(anchors, group)
+			}
+			break
+		}
+	}
+	return anchors
+}
+
+// AnchorSlug normalizes a heading or anchor for matching in-document links.
+// Letters and digits are lowercased, runs of other characters collapse to a
+// single hyphen, and leading or trailing hyphens are trimmed, so "## Getting
+// Started", "#getting-started", and AsciiDoc's "_getting_started" all match.
+func AnchorSlug(s string) string {
+	var b strings.Builder
+	pendingSep := false
+	for _, r := range strings.TrimPrefix(s, "#") {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if pendingSep && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			pendingSep = false
+			b.WriteRune(unicode.ToLower(r))
+		case r == ' ' || r == '-' || r == '_' || r == '\t':
+			pendingSep = true
+		}
+	}
+	return b.String()
+}
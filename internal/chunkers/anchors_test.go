@@ -0,0 +1,65 @@
+package chunkers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestAnchorSlug(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Installation", "installation"},
+		{"#installation", "installation"},
+		{"Getting Started", "getting-started"},
+		{"getting-started", "getting-started"},
+		{"_getting_started", "getting-started"},
+		{"What's New in v1.2?", "whats-new-in-v12"},
+		{"  API  Reference ", "api-reference"},
+	}
+
+	for _, tt := range tests {
+		if got := AnchorSlug(tt.input); got != tt.want {
+			t.Errorf("AnchorSlug(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestExtractAnchors(t *testing.T) {
+	t.Run("Markdown", func(t *testing.T) {
+		text := "See [install](#installation) and [usage](#usage), [again](#installation), [site](https://example.com#x)."
+		got := extractMarkdownAnchors(text)
+		want := []string{"installation", "usage"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("extractMarkdownAnchors() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("AsciiDoc", func(t *testing.T) {
+		text := "See <<_installation>>, <<usage,Usage guide>> and xref:#config[Config]."
+		got := extractAsciiDocAnchors(text)
+		want := []string{"_installation", "usage", "config"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("extractAsciiDocAnchors() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("MarkdownChunkMetadata", func(t *testing.T) {
+		content := "# Intro\n\nSee [installation](#installation).\n\n## Installation\n\nRun the installer.\n"
+		result, err := NewMarkdownChunker().Chunk(context.Background(), []byte(content), DefaultChunkOptions())
+		if err != nil {
+			t.Fatalf("Chunk returned error: %v", err)
+		}
+		if len(result.Chunks) != 2 {
+			t.Fatalf("got %d chunks, want 2", len(result.Chunks))
+		}
+		if got := result.Chunks[0].Metadata.Document.Anchors; !reflect.DeepEqual(got, []string{"installation"}) {
+			t.Errorf("intro anchors = %v, want [installation]", got)
+		}
+		if got := result.Chunks[1].Metadata.Document.Anchors; len(got) != 0 {
+			t.Errorf("installation anchors = %v, want none", got)
+		}
+	})
+}
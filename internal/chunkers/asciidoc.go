@@ -18,11 +18,27 @@ var asciidocHeadingRegex = regexp.MustCompile(`^(={1,6})\s+(.+)$`)
 var asciidocAnchorRegex = regexp.MustCompile(`^\[\[([^\]]+)\]\]$`)
 
 // AsciiDocChunker splits AsciiDoc content by section boundaries.
-type AsciiDocChunker struct{}
+type AsciiDocChunker struct {
+	maxIncludeDepth int
+}
+
+// AsciiDocOption configures an AsciiDocChunker.
+type AsciiDocOption func(*AsciiDocChunker)
+
+// WithMaxIncludeDepth caps how deeply include:: directives nest.
+func WithMaxIncludeDepth(depth int) AsciiDocOption {
+	return func(c *AsciiDocChunker) {
+		c.maxIncludeDepth = depth
+	}
+}
 
 // NewAsciiDocChunker creates a new AsciiDoc chunker.
-func NewAsciiDocChunker() *AsciiDocChunker {
-	return &AsciiDocChunker{}
+func NewAsciiDocChunker(opts ...AsciiDocOption) *AsciiDocChunker {
+	c := &AsciiDocChunker{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // Name returns the chunker's identifier.
@@ -32,12 +48,9 @@ func (c *AsciiDocChunker) Name() string {
 
 // CanHandle returns true for AsciiDoc content.
 func (c *AsciiDocChunker) CanHandle(mimeType string, language string) bool {
-	lang := strings.ToLower(language)
 	return mimeType == "text/asciidoc" ||
 		mimeType == "text/x-asciidoc" ||
-		strings.HasSuffix(lang, ".adoc") ||
-		strings.HasSuffix(lang, ".asciidoc") ||
-		strings.HasSuffix(lang, ".asc")
+		MatchesExtension(language, ".adoc", ".asciidoc", ".asc")
 }
 
 // Priority returns the chunker's priority.
@@ -45,7 +58,15 @@ func (c *AsciiDocChunker) Priority() int {
 	return asciidocChunkerPriority
 }
 
-// Chunk splits AsciiDoc content by section headings.
+// ChunkType returns the type of content this chunker produces.
+func (c *AsciiDocChunker) ChunkType() ChunkType {
+	return ChunkTypeProse
+}
+
+// Chunk splits AsciiDoc content by section headings, or purely by size when
+// opts.Flatten is set. When opts.BaseDir is set, include:: directives are
+// resolved relative to the including file and must stay within BaseDir;
+// offsets of chunks built from included content point at the directive.
 func (c *AsciiDocChunker) Chunk(ctx context.Context, content []byte, opts ChunkOptions) (*ChunkResult, error) {
 	if len(content) == 0 {
 		return &ChunkResult{
@@ -57,9 +78,34 @@ func (c *AsciiDocChunker) Chunk(ctx context.Context, content []byte, opts ChunkO
 		}, nil
 	}
 
+	originalSize := len(content)
+	source := content
+	var includer *asciidocIncluder
+	if opts.BaseDir != "" {
+		includer = newAsciiDocIncluder(c, opts.BaseDir)
+		content = []byte(includer.expand(string(source)))
+	}
+
+	if opts.Flatten {
+		chunks, err := chunkBySize(ctx, content, opts, flatDocumentMetadata(ChunkTypeProse))
+		if err != nil {
+			return nil, err
+		}
+		warnings, references := includer.mapToSource(chunks)
+		setLineRanges(source, chunks)
+		return &ChunkResult{
+			Chunks:       chunks,
+			Warnings:     warnings,
+			References:   references,
+			TotalChunks:  len(chunks),
+			ChunkerUsed:  asciidocChunkerName,
+			OriginalSize: originalSize,
+		}, nil
+	}
+
 	maxSize := opts.MaxChunkSize
 	if maxSize <= 0 {
-		maxSize = DefaultChunkOptions().MaxChunkSize
+		maxSize = DefaultChunkOptionsForType(c.ChunkType()).MaxChunkSize
 	}
 
 	text := string(content)
@@ -89,12 +135,14 @@ func (c *AsciiDocChunker) Chunk(ctx context.Context, content []byte, opts ChunkO
 				StartOffset: offset,
 				EndOffset:   offset + len(section.content),
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeProse,
-					TokenEstimate: EstimateTokens(section.content),
+					Type:               ChunkTypeProse,
+					BoundaryConfidence: BoundaryConfidenceStructural,
+					TokenEstimate:      EstimateTokens(section.content),
 					Document: &DocumentMetadata{
 						Heading:      section.heading,
 						HeadingLevel: section.level,
 						SectionPath:  section.sectionPath,
+						Anchors:      extractAsciiDocAnchors(section.content),
 					},
 				},
 			})
@@ -103,12 +151,16 @@ func (c *AsciiDocChunker) Chunk(ctx context.Context, content []byte, opts ChunkO
 		offset += len(section.content)
 	}
 
+	warnings, references := includer.mapToSource(chunks)
+	setLineRanges(source, chunks)
+
 	return &ChunkResult{
 		Chunks:       chunks,
-		Warnings:     nil,
+		Warnings:     warnings,
+		References:   references,
 		TotalChunks:  len(chunks),
 		ChunkerUsed:  asciidocChunkerName,
-		OriginalSize: len(content),
+		OriginalSize: originalSize,
 	}, nil
 }
 
@@ -248,34 +300,40 @@ func (c *AsciiDocChunker) splitLargeSection(ctx context.Context, section asciido
 	// Split by blank lines (paragraphs)
 	paragraphs := strings.Split(section.content, "\n\n")
 	var current strings.Builder
-	offset := baseOffset
+	pos := baseOffset
+	start, end := baseOffset, baseOffset
 
-	for _, para := range paragraphs {
+	for _, raw := range paragraphs {
 		select {
 		case <-ctx.Done():
 			return chunks
 		default:
 		}
 
-		para = strings.TrimSpace(para)
+		rawStart := pos
+		pos += len(raw) + 2
+		para := strings.TrimSpace(raw)
 		if para == "" {
 			continue
 		}
+		paraStart := rawStart + strings.Index(raw, para)
 
 		// If adding this paragraph exceeds max, finalize current chunk
 		if current.Len()+len(para)+2 > maxSize && current.Len() > 0 {
 			content := current.String()
 			chunks = append(chunks, Chunk{
 				Content:     content,
-				StartOffset: offset - len(content),
-				EndOffset:   offset,
+				StartOffset: start,
+				EndOffset:   end,
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeProse,
-					TokenEstimate: EstimateTokens(content),
+					Type:               ChunkTypeProse,
+					TokenEstimate:      EstimateTokens(content),
+					BoundaryConfidence: BoundaryConfidenceHeuristic,
 					Document: &DocumentMetadata{
 						Heading:      section.heading,
 						HeadingLevel: section.level,
 						SectionPath:  section.sectionPath,
+						Anchors:      extractAsciiDocAnchors(content),
 					},
 				},
 			})
@@ -284,9 +342,11 @@ func (c *AsciiDocChunker) splitLargeSection(ctx context.Context, section asciido
 
 		if current.Len() > 0 {
 			current.WriteString("\n\n")
+		} else {
+			start = paraStart
 		}
 		current.WriteString(para)
-		offset += len(para) + 2
+		end = paraStart + len(para)
 	}
 
 	// Finalize last chunk
@@ -294,15 +354,17 @@ func (c *AsciiDocChunker) splitLargeSection(ctx context.Context, section asciido
 		content := current.String()
 		chunks = append(chunks, Chunk{
 			Content:     content,
-			StartOffset: offset - len(content),
-			EndOffset:   offset,
+			StartOffset: start,
+			EndOffset:   end,
 			Metadata: ChunkMetadata{
-				Type:          ChunkTypeProse,
-				TokenEstimate: EstimateTokens(content),
+				Type:               ChunkTypeProse,
+				TokenEstimate:      EstimateTokens(content),
+				BoundaryConfidence: BoundaryConfidenceHeuristic,
 				Document: &DocumentMetadata{
 					Heading:      section.heading,
 					HeadingLevel: section.level,
 					SectionPath:  section.sectionPath,
+					Anchors:      extractAsciiDocAnchors(content),
 				},
 			},
 		})
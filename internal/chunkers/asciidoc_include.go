@@ -0,0 +1,257 @@
+package chunkers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultAsciiDocIncludeDepth matches Asciidoctor's default max-include-depth.
+const defaultAsciiDocIncludeDepth = 64
+
+// Matches AsciiDoc include directives: include::target[attributes].
+var asciidocIncludeRegex = regexp.MustCompile(`^include::([^\[\s]+)\[(.*)\]\s*$`)
+
+// Matches the leveloffset attribute of an include directive (e.g. leveloffset=+1).
+var asciidocLevelOffsetRegex = regexp.MustCompile(`(?:^|,)\s*leveloffset=([+-]?\d+)\s*(?:,|$)`)
+
+// asciidocIncluder expands include directives relative to a base directory.
+type asciidocIncluder struct {
+	chunker    *AsciiDocChunker
+	baseDir    string
+	maxDepth   int
+	active     map[string]bool
+	seen       map[string]bool
+	references []Reference
+	warnings   []ChunkWarning
+
+	// spans maps each top-level source line to its range in the expanded text.
+	spans []asciidocSpan
+}
+
+// asciidocSpan pairs a range of the expanded text with the source range it
+// came from. Verbatim spans map offsets one to one; expanded include
+// directives map their whole range onto the directive line.
+type asciidocSpan struct {
+	out, outLen int
+	src, srcLen int
+	verbatim    bool
+}
+
+func newAsciiDocIncluder(c *AsciiDocChunker, baseDir string) *asciidocIncluder {
+	maxDepth := c.maxIncludeDepth
+	if maxDepth <= 0 {
+		maxDepth = defaultAsciiDocIncludeDepth
+	}
+	return &asciidocIncluder{
+		chunker:  c,
+		baseDir:  filepath.Clean(baseDir),
+		maxDepth: maxDepth,
+		active:   make(map[string]bool),
+		seen:     make(map[string]bool),
+	}
+}
+
+// expand returns text with include directives replaced by the content of
+// their targets. Directives that cannot be resolved are kept as literal lines
+// and reported as warnings; warning offsets refer to the expanded text until
+// mapToSource is called.
+func (inc *asciidocIncluder) expand(text string) string {
+	var out strings.Builder
+	inc.expandInto(&out, text, inc.baseDir, 0, 0)
+	return out.String()
+}
+
+func (inc *asciidocIncluder) expandInto(out *strings.Builder, text, dir string, levelOffset, depth int) {
+	var block string
+	src := 0
+
+	for i, line := range strings.Split(text, "\n") {
+		// Each top-level line's span covers the newline before it
+		span := asciidocSpan{out: out.Len(), src: max(src-1, 0), srcLen: len(line) + min(i, 1)}
+		src += len(line) + 1
+
+		if i > 0 {
+			out.WriteByte('\n')
+		}
+		inc.expandLine(out, line, dir, &block, levelOffset, depth)
+
+		if depth == 0 {
+			span.outLen = out.Len() - span.out
+			span.verbatim = out.String()[span.out:] == text[span.src:span.src+span.srcLen]
+			inc.spans = append(inc.spans, span)
+		}
+	}
+}
+
+// expandLine writes one line, or the expanded content of the include directive
+// on it, tracking the delimited block the line opens or closes.
+func (inc *asciidocIncluder) expandLine(out *strings.Builder, line, dir string, block *string, levelOffset, depth int) {
+	trimmed := strings.TrimSpace(line)
+	if delimiter := inc.chunker.getBlockDelimiter(trimmed); delimiter != "" {
+		switch *block {
+		case "":
+			*block = delimiter
+		case delimiter:
+			*block = ""
+		}
+		out.WriteString(line)
+		return
+	}
+	if *block == "////" || strings.HasPrefix(trimmed, "//") {
+		out.WriteString(line)
+		return
+	}
+
+	matches := asciidocIncludeRegex.FindStringSubmatch(trimmed)
+	if matches == nil {
+		if *block == "" {
+			line = shiftAsciiDocHeading(line, levelOffset)
+		}
+		out.WriteString(line)
+		return
+	}
+
+	target := matches[1]
+	if strings.Contains(target, "://") || strings.Contains(target, "{") {
+		// Remote targets and attribute references are left for the renderer
+		out.WriteString(line)
+		return
+	}
+
+	path, err := inc.resolve(dir, target)
+	if err == nil && inc.active[path] {
+		err = fmt.Errorf("include cycle at %s", target)
+	}
+	if err == nil && depth >= inc.maxDepth {
+		err = fmt.Errorf("include depth limit %d exceeded at %s", inc.maxDepth, target)
+	}
+	var content []byte
+	if err == nil {
+		content, err = os.ReadFile(path)
+	}
+	if err != nil {
+		inc.warnings = append(inc.warnings, ChunkWarning{
+			Offset:  out.Len(),
+			Message: fmt.Sprintf("unresolved include; %v", err),
+			Code:    "ASCIIDOC_INCLUDE_UNRESOLVED",
+		})
+		out.WriteString(line)
+		return
+	}
+
+	if !inc.seen[path] {
+		inc.seen[path] = true
+		inc.references = append(inc.references, Reference{Type: "file", Target: path})
+	}
+
+	inc.active[path] = true
+	included := strings.TrimSuffix(string(content), "\n")
+	inc.expandInto(out, included, filepath.Dir(path), includeLevelOffset(matches[2], levelOffset), depth+1)
+	delete(inc.active, path)
+}
+
+// mapToSource rewrites chunk and warning offsets from the expanded text to the
+// source text, and returns the warnings and resolved references. A nil
+// includer leaves the chunks unchanged.
+func (inc *asciidocIncluder) mapToSource(chunks []Chunk) ([]ChunkWarning, []Reference) {
+	if inc == nil {
+		return nil, nil
+	}
+	for i := range chunks {
+		chunks[i].StartOffset = inc.sourceOffset(chunks[i].StartOffset, false)
+		chunks[i].EndOffset = inc.sourceOffset(chunks[i].EndOffset, true)
+	}
+	for i := range inc.warnings {
+		inc.warnings[i].Offset = inc.sourceOffset(inc.warnings[i].Offset, false)
+	}
+	return inc.warnings, inc.references
+}
+
+// sourceOffset maps an offset in the expanded text to the source text. Offsets
+// inside expanded content map to the start of the include directive, or to its
+// end when end is set, so chunk ranges cover the directive.
+func (inc *asciidocIncluder) sourceOffset(offset int, end bool) int {
+	i := sort.Search(len(inc.spans), func(i int) bool {
+		return inc.spans[i].out+inc.spans[i].outLen > offset
+	})
+	if i == len(inc.spans) {
+		if i == 0 {
+			return offset
+		}
+		last := inc.spans[i-1]
+		return last.src + last.srcLen
+	}
+
+	span := inc.spans[i]
+	switch {
+	case span.verbatim:
+		return span.src + offset - span.out
+	case end && offset > span.out:
+		return span.src + span.srcLen
+	default:
+		return span.src
+	}
+}
+
+// resolve maps an include target to a clean absolute path under the base
+// directory, rejecting targets that escape it.
+func (inc *asciidocIncluder) resolve(dir, target string) (string, error) {
+	path := target
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	base, err := filepath.Abs(inc.baseDir)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(base, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("include target %s is outside the base directory", target)
+	}
+
+	return path, nil
+}
+
+// includeLevelOffset applies the leveloffset attribute of an include
+// directive to the current offset. Signed values are relative; unsigned
+// values replace the offset.
+func includeLevelOffset(attrs string, current int) int {
+	matches := asciidocLevelOffsetRegex.FindStringSubmatch(attrs)
+	if matches == nil {
+		return current
+	}
+
+	value, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return current
+	}
+	if strings.HasPrefix(matches[1], "+") || strings.HasPrefix(matches[1], "-") {
+		return current + value
+	}
+	return value
+}
+
+// shiftAsciiDocHeading adjusts a heading line's level by offset, clamped to
+// the 1-6 range AsciiDoc supports.
+func shiftAsciiDocHeading(line string, offset int) string {
+	if offset == 0 {
+		return line
+	}
+	matches := asciidocHeadingRegex.FindStringSubmatch(line)
+	if matches == nil {
+		return line
+	}
+
+	level := min(max(len(matches[1])+offset, 1), 6)
+	return strings.Repeat("=", level) + " " + matches[2]
+}
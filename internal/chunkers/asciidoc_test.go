@@ -998,3 +998,116 @@ Content.
 		}
 	}
 }
+
+func TestAsciiDocChunker_ResolvesIncludes(t *testing.T) {
+	dir := t.TempDir()
+	chapter := filepath.Join(dir, "chapters", "install.adoc")
+	if err := os.MkdirAll(filepath.Dir(chapter), 0755); err != nil {
+		t.Fatalf("failed to create chapters dir: %v", err)
+	}
+	if err := os.WriteFile(chapter, []byte("= Installation\n\nRun the installer.\n"), 0644); err != nil {
+		t.Fatalf("failed to write include: %v", err)
+	}
+
+	content := "= Guide\n\nIntro text.\n\ninclude::chapters/install.adoc[leveloffset=+1]\n"
+
+	opts := DefaultChunkOptions()
+	opts.BaseDir = dir
+	result, err := NewAsciiDocChunker().Chunk(context.Background(), []byte(content), opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+
+	var found *Chunk
+	for i := range result.Chunks {
+		if strings.Contains(result.Chunks[i].Content, "Run the installer.") {
+			found = &result.Chunks[i]
+		}
+		if strings.Contains(result.Chunks[i].Content, "include::") {
+			t.Errorf("chunk %d still contains the include directive", i)
+		}
+	}
+	if found == nil {
+		t.Fatal("included content was not inlined")
+	}
+	doc := found.Metadata.Document
+	if doc.Heading != "Installation" || doc.HeadingLevel != 2 {
+		t.Errorf("heading = %q level %d, want %q level 2", doc.Heading, doc.HeadingLevel, "Installation")
+	}
+	if doc.SectionPath != "Guide > Installation" {
+		t.Errorf("SectionPath = %q, want %q", doc.SectionPath, "Guide > Installation")
+	}
+
+	// Offsets of included content point at the directive in the source
+	directive := strings.Index(content, "include::")
+	if found.StartOffset > directive || found.EndOffset < directive+len("include::chapters/install.adoc[leveloffset=+1]") || found.EndOffset > len(content) {
+		t.Errorf("offsets = [%d, %d), want a source range covering the directive at %d", found.StartOffset, found.EndOffset, directive)
+	}
+	if found.Metadata.LineStart != 5 || found.Metadata.LineEnd != 5 {
+		t.Errorf("lines = %d-%d, want 5-5", found.Metadata.LineStart, found.Metadata.LineEnd)
+	}
+	if intro := result.Chunks[0]; intro.StartOffset != 0 || content[intro.StartOffset:intro.EndOffset] != intro.Content {
+		t.Errorf("source chunk offsets [%d, %d) do not match its content", intro.StartOffset, intro.EndOffset)
+	}
+
+	if len(result.References) != 1 {
+		t.Fatalf("expected 1 reference, got %d", len(result.References))
+	}
+	if ref := result.References[0]; ref.Type != "file" || ref.Target != chapter {
+		t.Errorf("reference = %+v, want file %s", ref, chapter)
+	}
+	if len(result.Warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", result.Warnings)
+	}
+}
+
+func TestAsciiDocChunker_CyclicInclude(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.adoc")
+	b := filepath.Join(dir, "b.adoc")
+	if err := os.WriteFile(a, []byte("Alpha content.\n\ninclude::b.adoc[]\n"), 0644); err != nil {
+		t.Fatalf("failed to write a.adoc: %v", err)
+	}
+	if err := os.WriteFile(b, []byte("Beta content.\n\ninclude::a.adoc[]\n"), 0644); err != nil {
+		t.Fatalf("failed to write b.adoc: %v", err)
+	}
+
+	opts := DefaultChunkOptions()
+	opts.BaseDir = dir
+	result, err := NewAsciiDocChunker().Chunk(context.Background(), []byte("= Root\n\ninclude::a.adoc[]\n"), opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+
+	var all strings.Builder
+	for _, chunk := range result.Chunks {
+		all.WriteString(chunk.Content)
+	}
+	if got := strings.Count(all.String(), "Alpha content."); got != 1 {
+		t.Errorf("Alpha content inlined %d times, want 1", got)
+	}
+	if got := strings.Count(all.String(), "Beta content."); got != 1 {
+		t.Errorf("Beta content inlined %d times, want 1", got)
+	}
+	if len(result.References) != 2 {
+		t.Errorf("expected 2 references, got %d", len(result.References))
+	}
+	if len(result.Warnings) != 1 || result.Warnings[0].Code != "ASCIIDOC_INCLUDE_UNRESOLVED" {
+		t.Errorf("expected one unresolved include warning, got %v", result.Warnings)
+	}
+}
+
+func TestAsciiDocChunker_IncludesDisabledByDefault(t *testing.T) {
+	content := "= Guide\n\ninclude::missing.adoc[]\n"
+
+	result, err := NewAsciiDocChunker().Chunk(context.Background(), []byte(content), DefaultChunkOptions())
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if len(result.References) != 0 || len(result.Warnings) != 0 {
+		t.Errorf("expected includes to be left alone, got refs %v warnings %v", result.References, result.Warnings)
+	}
+	if !strings.Contains(result.Chunks[0].Content, "include::missing.adoc[]") {
+		t.Error("expected include directive to remain literal")
+	}
+}
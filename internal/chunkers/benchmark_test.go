@@ -0,0 +1,146 @@
+package chunkers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leefowlercu/agentic-memorizer/internal/chunkers"
+	"github.com/leefowlercu/agentic-memorizer/internal/chunkers/code"
+	"github.com/leefowlercu/agentic-memorizer/internal/chunkers/code/languages"
+)
+
+// benchScales are the fixture repetition counts; the largest input stays around 100KB.
+var benchScales = []int{1, 10, 100}
+
+// loadBenchFixture reads a file from the repository testdata directory.
+func loadBenchFixture(b *testing.B, parts ...string) []byte {
+	b.Helper()
+	content, err := os.ReadFile(filepath.Join(append([]string{"..", "..", "testdata"}, parts...)...))
+	if err != nil {
+		b.Fatalf("failed to read fixture: %v", err)
+	}
+	return content
+}
+
+// benchJSONFixture builds a deterministic JSON array of n records.
+func benchJSONFixture(b *testing.B, n int) []byte {
+	b.Helper()
+	records := make([]map[string]any, n)
+	for i := range records {
+		records[i] = map[string]any{
+			"id":      i,
+			"name":    fmt.Sprintf("record-%d", i),
+			"enabled": i%2 == 0,
+			"tags":    []string{"alpha", "beta", "gamma"},
+			"config":  map[string]any{"retries": 3, "timeout": "30s"},
+		}
+	}
+	content, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		b.Fatalf("failed to marshal fixture: %v", err)
+	}
+	return content
+}
+
+// contentChunker is satisfied by both individual chunkers and the Registry.
+type contentChunker interface {
+	Chunk(ctx context.Context, content []byte, opts chunkers.ChunkOptions) (*chunkers.ChunkResult, error)
+}
+
+// runChunkerBenchmark chunks content repeatedly, reporting throughput, chunks/s, and allocations.
+func runChunkerBenchmark(b *testing.B, c contentChunker, content []byte, opts chunkers.ChunkOptions) {
+	b.Helper()
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(content)))
+	b.ResetTimer()
+
+	chunks := 0
+	for i := 0; i < b.N; i++ {
+		result, err := c.Chunk(ctx, content, opts)
+		if err != nil {
+			b.Fatalf("Chunk failed: %v", err)
+		}
+		chunks += len(result.Chunks)
+	}
+
+	b.ReportMetric(float64(chunks)/b.Elapsed().Seconds(), "chunks/s")
+}
+
+// benchmarkScaled runs the chunker over the fixture repeated at each bench scale.
+func benchmarkScaled(b *testing.B, c chunkers.Chunker, fixture []byte, opts chunkers.ChunkOptions) {
+	for _, scale := range benchScales {
+		content := bytes.Repeat(append(fixture, '\n'), scale)
+		b.Run(fmt.Sprintf("x%d", scale), func(b *testing.B) {
+			runChunkerBenchmark(b, c, content, opts)
+		})
+	}
+}
+
+func BenchmarkMarkdownChunker(b *testing.B) {
+	fixture := loadBenchFixture(b, "markup", "sample.md")
+	opts := chunkers.DefaultChunkOptionsForType(chunkers.ChunkTypeMarkdown)
+	benchmarkScaled(b, chunkers.NewMarkdownChunker(), fixture, opts)
+}
+
+func BenchmarkRecursiveChunker(b *testing.B) {
+	fixture := loadBenchFixture(b, "markup", "sample.rst")
+	opts := chunkers.DefaultChunkOptionsForType(chunkers.ChunkTypeProse)
+	benchmarkScaled(b, chunkers.NewRecursiveChunker(), fixture, opts)
+}
+
+func BenchmarkStructuredChunker(b *testing.B) {
+	c := chunkers.NewStructuredChunker()
+	opts := chunkers.DefaultChunkOptionsForType(chunkers.ChunkTypeStructured)
+	opts.MIMEType = "application/json"
+
+	for _, scale := range benchScales {
+		content := benchJSONFixture(b, 5*scale)
+		b.Run(fmt.Sprintf("x%d", scale), func(b *testing.B) {
+			runChunkerBenchmark(b, c, content, opts)
+		})
+	}
+}
+
+func BenchmarkTreeSitterChunker(b *testing.B) {
+	c := code.NewTreeSitterChunker()
+	c.RegisterStrategy(languages.NewGoStrategy())
+	c.RegisterStrategy(languages.NewPythonStrategy())
+
+	for _, lang := range []struct {
+		name    string
+		fixture string
+	}{
+		{"go", "sample.go"},
+		{"python", "sample.py"},
+	} {
+		fixture := loadBenchFixture(b, "code", lang.fixture)
+		opts := chunkers.DefaultChunkOptionsForType(chunkers.ChunkTypeCode)
+		opts.Language = lang.name
+		b.Run(lang.name, func(b *testing.B) {
+			benchmarkScaled(b, c, fixture, opts)
+		})
+	}
+}
+
+// BenchmarkRegistryDispatch compares Registry.Chunk with calling the selected
+// chunker directly, isolating the cost of chunker selection.
+func BenchmarkRegistryDispatch(b *testing.B) {
+	fixture := loadBenchFixture(b, "markup", "sample.md")
+	registry := chunkers.DefaultRegistry()
+	opts := chunkers.DefaultChunkOptionsForType(chunkers.ChunkTypeMarkdown)
+	opts.MIMEType = "text/markdown"
+
+	b.Run("Registry", func(b *testing.B) {
+		runChunkerBenchmark(b, registry, fixture, opts)
+	})
+	b.Run("Direct", func(b *testing.B) {
+		runChunkerBenchmark(b, chunkers.NewMarkdownChunker(), fixture, opts)
+	})
+}
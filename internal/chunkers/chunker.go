@@ -2,8 +2,13 @@ package chunkers
 
 import (
 	"context"
+	"io"
 )
 
+// Version identifies the chunking behavior. Bump it whenever chunk boundaries or
+// content change so previously chunked files are re-chunked and re-embedded.
+const Version = "3"
+
 // ChunkType represents the type of content being chunked.
 type ChunkType string
 
@@ -45,6 +50,15 @@ type ChunkWarning struct {
 	Code string
 }
 
+// Reference identifies an external resource the chunked content depends on.
+type Reference struct {
+	// Type is the kind of resource (e.g. "file").
+	Type string
+
+	// Target is the resource location, such as an absolute file path.
+	Target string
+}
+
 // ChunkOptions configures chunking behavior.
 type ChunkOptions struct {
 	// MaxChunkSize is the maximum size in bytes for a chunk.
@@ -53,6 +67,11 @@ type ChunkOptions struct {
 	// MaxTokens is the target maximum tokens per chunk.
 	MaxTokens int
 
+	// Tokenizer counts tokens for MaxTokens sizing and chunk token estimates.
+	// When nil, the package-level EstimateTokens is used. The Registry applies
+	// it to every chunk's TokenEstimate.
+	Tokenizer Tokenizer
+
 	// Overlap is the number of bytes to overlap between chunks.
 	Overlap int
 
@@ -62,8 +81,28 @@ type ChunkOptions struct {
 	// MIMEType is the content MIME type.
 	MIMEType string
 
+	// BaseDir is the directory of the file being chunked. AsciiDoc include
+	// directives are resolved within it; when empty they are left literal.
+	BaseDir string
+
 	// PreserveStructure attempts to keep logical units together.
 	PreserveStructure bool
+
+	// Flatten makes document and structured chunkers split purely by size,
+	// ignoring headings, sections and records.
+	Flatten bool
+
+	// RecordsPerChunk groups a fixed number of JSON array elements or NDJSON
+	// lines into each structured chunk. Zero groups records to fit MaxChunkSize.
+	RecordsPerChunk int
+
+	// ExportedOnly emits code chunks only for exported/public declarations and
+	// the file header. Private declarations are parsed but not emitted.
+	ExportedOnly bool
+
+	// SplitOnThematicBreak treats markdown thematic breaks (***, ---, ___) as
+	// chunk boundaries in addition to headings.
+	SplitOnThematicBreak bool
 }
 
 // DefaultChunkOptions returns sensible default chunking options.
@@ -76,6 +115,29 @@ func DefaultChunkOptions() ChunkOptions {
 	}
 }
 
+// DefaultChunkOptionsForType returns default chunking options tuned for a
+// content type. Code chunks are allowed to grow so functions stay whole, while
+// prose and markdown target windows of roughly 1000 tokens.
+func DefaultChunkOptionsForType(chunkType ChunkType) ChunkOptions {
+	opts := DefaultChunkOptions()
+	switch chunkType {
+	case ChunkTypeCode:
+		opts.MaxChunkSize = 12000 // ~3000 tokens
+		opts.MaxTokens = 3000
+	case ChunkTypeMarkdown, ChunkTypeProse:
+		opts.MaxChunkSize = 4000 // ~1000 tokens
+		opts.MaxTokens = 1000
+	}
+	return opts
+}
+
+// TypedChunker is implemented by chunkers that declare the type of content
+// they produce. The registry uses it to pick size defaults.
+type TypedChunker interface {
+	// ChunkType returns the type of content this chunker produces.
+	ChunkType() ChunkType
+}
+
 // Chunker splits content into smaller pieces for analysis.
 type Chunker interface {
 	// Name returns the chunker's identifier.
@@ -93,6 +155,19 @@ type Chunker interface {
 	Priority() int
 }
 
+// StreamChunker is implemented by chunkers that can split content as it is
+// read, so very large inputs never have to be held in memory whole.
+type StreamChunker interface {
+	Chunker
+
+	// ChunkStream reads r and sends chunks on the returned channel as they
+	// are produced, waiting for each to be received before producing more.
+	// The chunk channel is closed when chunking ends; the error channel then
+	// delivers at most one error (a read failure or context cancellation)
+	// and is closed.
+	ChunkStream(ctx context.Context, r io.Reader, opts ChunkOptions) (<-chan Chunk, <-chan error)
+}
+
 // ChunkResult contains the result of chunking an entire file.
 type ChunkResult struct {
 	// Chunks is the list of content chunks.
@@ -102,6 +177,10 @@ type ChunkResult struct {
 	// The caller decides how to handle/log these warnings.
 	Warnings []ChunkWarning
 
+	// References lists external resources pulled into the content, such as
+	// resolved AsciiDoc includes.
+	References []Reference
+
 	// TotalChunks is the total number of chunks.
 	TotalChunks int
 
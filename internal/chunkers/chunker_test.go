@@ -3,8 +3,13 @@ package chunkers
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 	"sync"
 	"testing"
+	"time"
+	"unicode/utf8"
 )
 
 func TestEstimateTokens(t *testing.T) {
@@ -46,6 +51,35 @@ func TestDefaultChunkOptions(t *testing.T) {
 	}
 }
 
+func TestDefaultChunkOptionsForType(t *testing.T) {
+	tests := []struct {
+		chunkType ChunkType
+		maxSize   int
+		maxTokens int
+	}{
+		{ChunkTypeCode, 12000, 3000},
+		{ChunkTypeMarkdown, 4000, 1000},
+		{ChunkTypeProse, 4000, 1000},
+		{ChunkTypeStructured, 8000, 2000},
+		{ChunkTypeUnknown, 8000, 2000},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.chunkType), func(t *testing.T) {
+			opts := DefaultChunkOptionsForType(tt.chunkType)
+			if opts.MaxChunkSize != tt.maxSize {
+				t.Errorf("MaxChunkSize = %d, want %d", opts.MaxChunkSize, tt.maxSize)
+			}
+			if opts.MaxTokens != tt.maxTokens {
+				t.Errorf("MaxTokens = %d, want %d", opts.MaxTokens, tt.maxTokens)
+			}
+			if !opts.PreserveStructure {
+				t.Error("PreserveStructure should be true by default")
+			}
+		})
+	}
+}
+
 func TestFallbackChunker(t *testing.T) {
 	chunker := NewFallbackChunker()
 
@@ -67,6 +101,20 @@ func TestFallbackChunker(t *testing.T) {
 		}
 	})
 
+	t.Run("BoundaryConfidence", func(t *testing.T) {
+		content := []byte(strings.Repeat("word ", 400))
+		result, err := chunker.Chunk(context.Background(), content, ChunkOptions{MaxChunkSize: 500})
+		if err != nil {
+			t.Fatalf("Chunk returned error: %v", err)
+		}
+		for _, chunk := range result.Chunks {
+			if chunk.Metadata.BoundaryConfidence != BoundaryConfidenceFixedWindow {
+				t.Errorf("chunk %d BoundaryConfidence = %v, want %v",
+					chunk.Index, chunk.Metadata.BoundaryConfidence, BoundaryConfidenceFixedWindow)
+			}
+		}
+	})
+
 	t.Run("EmptyContent", func(t *testing.T) {
 		result, err := chunker.Chunk(context.Background(), []byte{}, DefaultChunkOptions())
 		if err != nil {
@@ -219,6 +267,35 @@ Content under heading 3.
 			}
 		}
 	})
+
+	t.Run("LineRanges", func(t *testing.T) {
+		source := "# Title\n\nIntro text.\n\n## Install\n\nRun the installer.\nThen restart.\n\n## Usage\n\nRun it.\n"
+		for _, tt := range []struct {
+			name    string
+			content string
+		}{
+			{"LF", source},
+			{"CRLF", strings.ReplaceAll(source, "\n", "\r\n")},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				result, err := chunker.Chunk(context.Background(), []byte(tt.content), DefaultChunkOptions())
+				if err != nil {
+					t.Fatalf("Chunk returned error: %v", err)
+				}
+
+				want := [][2]int{{1, 3}, {5, 8}, {10, 12}}
+				if len(result.Chunks) != len(want) {
+					t.Fatalf("got %d chunks, want %d", len(result.Chunks), len(want))
+				}
+				for i, chunk := range result.Chunks {
+					got := [2]int{chunk.Metadata.LineStart, chunk.Metadata.LineEnd}
+					if got != want[i] {
+						t.Errorf("chunk %d lines = %v, want %v", i, got, want[i])
+					}
+				}
+			})
+		}
+	})
 }
 
 func TestStructuredChunker(t *testing.T) {
@@ -245,6 +322,7 @@ func TestStructuredChunker(t *testing.T) {
 			{"text/json", true},
 			{"text/csv", true},
 			{"text/yaml", true},
+			{"application/x-ndjson", true},
 			{"text/plain", false},
 		}
 
@@ -270,6 +348,101 @@ func TestStructuredChunker(t *testing.T) {
 			t.Error("Expected at least one chunk")
 		}
 	})
+
+	t.Run("RecordsPerChunk", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			mimeType string
+			content  string
+		}{
+			{"JSONArray", "application/json", `[{"id":1},{"id":2},{"id":3},{"id":4},{"id":5},{"id":6},{"id":7}]`},
+			{"NDJSON", "application/x-ndjson", "{\"id\":1}\n{\"id\":2}\n{\"id\":3}\n{\"id\":4}\n{\"id\":5}\n{\"id\":6}\n{\"id\":7}\n"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				opts := ChunkOptions{
+					MIMEType:        tt.mimeType,
+					MaxChunkSize:    1000,
+					RecordsPerChunk: 3,
+				}
+				result, err := chunker.Chunk(context.Background(), []byte(tt.content), opts)
+				if err != nil {
+					t.Fatalf("Chunk returned error: %v", err)
+				}
+
+				wantIndex := []int{0, 3, 6}
+				wantCount := []int{3, 3, 1}
+				if len(result.Chunks) != len(wantCount) {
+					t.Fatalf("got %d chunks, want %d", len(result.Chunks), len(wantCount))
+				}
+				for i, chunk := range result.Chunks {
+					meta := chunk.Metadata.Structured
+					if meta.RecordIndex != wantIndex[i] || meta.RecordCount != wantCount[i] {
+						t.Errorf("chunk %d records = [%d, +%d], want [%d, +%d]",
+							i, meta.RecordIndex, meta.RecordCount, wantIndex[i], wantCount[i])
+					}
+					if !strings.Contains(chunk.Content, fmt.Sprintf(`"id":%d`, wantIndex[i]+1)) {
+						t.Errorf("chunk %d content %q missing first record", i, chunk.Content)
+					}
+				}
+			})
+		}
+	})
+
+	t.Run("NDJSONLineRanges", func(t *testing.T) {
+		content := "{\"id\":1}\n\n{\"id\":2}\r\n{\"id\":3}\n{\"id\":4}\n"
+		opts := ChunkOptions{
+			MIMEType:        "application/x-ndjson",
+			MaxChunkSize:    1000,
+			RecordsPerChunk: 2,
+		}
+		result, err := chunker.Chunk(context.Background(), []byte(content), opts)
+		if err != nil {
+			t.Fatalf("Chunk returned error: %v", err)
+		}
+
+		want := [][2]int{{1, 3}, {4, 5}}
+		if len(result.Chunks) != len(want) {
+			t.Fatalf("got %d chunks, want %d", len(result.Chunks), len(want))
+		}
+		for i, chunk := range result.Chunks {
+			got := [2]int{chunk.Metadata.LineStart, chunk.Metadata.LineEnd}
+			if got != want[i] {
+				t.Errorf("chunk %d lines = %v, want %v", i, got, want[i])
+			}
+		}
+	})
+
+	t.Run("RecordsPerChunkOversizedElement", func(t *testing.T) {
+		large := strings.Repeat("x", 200)
+		content := fmt.Sprintf(`[{"id":1},{"data":"%s"},{"id":3},{"id":4}]`, large)
+		opts := ChunkOptions{
+			MIMEType:        "application/json",
+			MaxChunkSize:    100,
+			RecordsPerChunk: 3,
+		}
+		result, err := chunker.Chunk(context.Background(), []byte(content), opts)
+		if err != nil {
+			t.Fatalf("Chunk returned error: %v", err)
+		}
+
+		wantIndex := []int{0, 1, 2}
+		wantCount := []int{1, 1, 2}
+		if len(result.Chunks) != len(wantCount) {
+			t.Fatalf("got %d chunks, want %d", len(result.Chunks), len(wantCount))
+		}
+		for i, chunk := range result.Chunks {
+			meta := chunk.Metadata.Structured
+			if meta.RecordIndex != wantIndex[i] || meta.RecordCount != wantCount[i] {
+				t.Errorf("chunk %d records = [%d, +%d], want [%d, +%d]",
+					i, meta.RecordIndex, meta.RecordCount, wantIndex[i], wantCount[i])
+			}
+		}
+		if !strings.Contains(result.Chunks[1].Content, large) {
+			t.Error("expected oversized element in its own chunk")
+		}
+	})
 }
 
 func TestRegistry(t *testing.T) {
@@ -340,6 +513,30 @@ func TestRegistry(t *testing.T) {
 		}
 	})
 
+	t.Run("GetAll", func(t *testing.T) {
+		registry := NewRegistry()
+		registry.Register(NewStructuredChunker())
+		// Stand-in for a manifest chunker that outranks the structured chunker for YAML
+		registry.Register(&selectiveChunker{accepts: "text/yaml", priority: 60})
+		registry.Register(NewMarkdownChunker())
+		registry.SetFallback(NewFallbackChunker())
+
+		matches := registry.GetAll("text/yaml", "")
+		if len(matches) != 2 {
+			t.Fatalf("GetAll returned %d chunkers, want 2", len(matches))
+		}
+		if matches[0].Name() != "selective" || matches[1].Name() != "structured" {
+			t.Errorf("GetAll order = [%q, %q], want [selective, structured]", matches[0].Name(), matches[1].Name())
+		}
+		if got := registry.Get("text/yaml", ""); got != matches[0] {
+			t.Errorf("Get = %q, want highest priority match %q", got.Name(), matches[0].Name())
+		}
+
+		if matches := registry.GetAll("unknown/type", ""); len(matches) != 0 {
+			t.Errorf("GetAll for unknown type returned %d chunkers, want 0", len(matches))
+		}
+	})
+
 	t.Run("DefaultRegistry", func(t *testing.T) {
 		registry := DefaultRegistry()
 		if registry == nil {
@@ -406,6 +603,55 @@ func TestRegistry(t *testing.T) {
 		}
 	})
 
+	t.Run("RecoversFromPanic", func(t *testing.T) {
+		registry := NewRegistry()
+		registry.Register(&panickingChunker{priority: 100})
+		registry.SetFallback(NewRecursiveChunker())
+
+		opts := ChunkOptions{
+			MIMEType:     "text/plain",
+			MaxChunkSize: 8000,
+		}
+
+		result, err := registry.Chunk(context.Background(), []byte("Hello, world!"), opts)
+		if err != nil {
+			t.Fatalf("Chunk returned error: %v", err)
+		}
+		if result.ChunkerUsed != "recursive" {
+			t.Errorf("Expected recursive fallback, got %q", result.ChunkerUsed)
+		}
+
+		foundWarning := false
+		for _, w := range result.Warnings {
+			if w.Code == "CHUNKER_PANIC" && strings.Contains(w.Message, "intentional panic") {
+				foundWarning = true
+				break
+			}
+		}
+		if !foundWarning {
+			t.Errorf("Expected CHUNKER_PANIC warning, got %+v", result.Warnings)
+		}
+
+		// A panicking fallback becomes an error instead of crashing the caller
+		registry = NewRegistry()
+		registry.SetFallback(&panickingChunker{})
+		if _, err := registry.Chunk(context.Background(), []byte("Hello"), opts); err == nil {
+			t.Error("Expected error from panicking fallback")
+		}
+	})
+
+	t.Run("RecoverDisabled", func(t *testing.T) {
+		registry := NewRegistry(WithRecover(false))
+		registry.Register(&panickingChunker{priority: 100})
+
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected panic to propagate with recovery disabled")
+			}
+		}()
+		_, _ = registry.Chunk(context.Background(), []byte("Hello"), ChunkOptions{MIMEType: "text/plain"})
+	})
+
 	t.Run("WarningAggregation", func(t *testing.T) {
 		registry := NewRegistry()
 		// Register a failing chunker
@@ -507,6 +753,19 @@ func (f *failingChunker) Chunk(ctx context.Context, content []byte, opts ChunkOp
 	return nil, fmt.Errorf("intentional failure for testing")
 }
 
+type panickingChunker struct {
+	priority int
+}
+
+func (p *panickingChunker) Name() string { return "panicking" }
+func (p *panickingChunker) CanHandle(mimeType string, language string) bool {
+	return mimeType == "text/plain" || mimeType == ""
+}
+func (p *panickingChunker) Priority() int { return p.priority }
+func (p *panickingChunker) Chunk(ctx context.Context, content []byte, opts ChunkOptions) (*ChunkResult, error) {
+	panic("intentional panic for testing")
+}
+
 func TestChunkWarningConstruction(t *testing.T) {
 	t.Run("basic construction", func(t *testing.T) {
 		warning := ChunkWarning{
@@ -802,6 +1061,340 @@ func TestChunkTypeValues(t *testing.T) {
 func TestMarkdownChunkerEdgeCases(t *testing.T) {
 	chunker := NewMarkdownChunker()
 
+	t.Run("setext headings", func(t *testing.T) {
+		content := []byte("Project Title\n=============\n\nIntro text.\n\nInstallation\n------------\n\nRun the installer.\n")
+		result, err := chunker.Chunk(context.Background(), content, DefaultChunkOptions())
+		if err != nil {
+			t.Fatalf("Chunk returned error: %v", err)
+		}
+		if len(result.Chunks) != 2 {
+			t.Fatalf("expected 2 chunks, got %d", len(result.Chunks))
+		}
+
+		tests := []struct {
+			heading string
+			level   int
+		}{
+			{"Project Title", 1},
+			{"Installation", 2},
+		}
+		for i, tt := range tests {
+			doc := result.Chunks[i].Metadata.Document
+			if doc.Heading != tt.heading || doc.HeadingLevel != tt.level {
+				t.Errorf("chunk %d heading = %q (level %d), want %q (level %d)",
+					i, doc.Heading, doc.HeadingLevel, tt.heading, tt.level)
+			}
+		}
+	})
+
+	t.Run("mixed ATX and setext headings", func(t *testing.T) {
+		content := []byte("User Guide\n==========\n\nOverview.\n\n## Setup\n\nInstall it.\n\nConfiguration\n-------------\n\nEdit the file.\n\n### Options\n\nFlags.\n")
+		result, err := chunker.Chunk(context.Background(), content, DefaultChunkOptions())
+		if err != nil {
+			t.Fatalf("Chunk returned error: %v", err)
+		}
+		if len(result.Chunks) != 4 {
+			t.Fatalf("expected 4 chunks, got %d", len(result.Chunks))
+		}
+
+		tests := []struct {
+			heading string
+			level   int
+			path    string
+		}{
+			{"User Guide", 1, "User Guide"},
+			{"Setup", 2, "User Guide > Setup"},
+			{"Configuration", 2, "User Guide > Configuration"},
+			{"Options", 3, "User Guide > Configuration > Options"},
+		}
+		for i, tt := range tests {
+			doc := result.Chunks[i].Metadata.Document
+			if doc.Heading != tt.heading || doc.HeadingLevel != tt.level || doc.SectionPath != tt.path {
+				t.Errorf("chunk %d heading = %q (level %d, path %q), want %q (level %d, path %q)",
+					i, doc.Heading, doc.HeadingLevel, doc.SectionPath, tt.heading, tt.level, tt.path)
+			}
+		}
+	})
+
+	t.Run("overlap carried within an oversized section", func(t *testing.T) {
+		var sb strings.Builder
+		sb.WriteString("# Long\n\n")
+		for i := range 12 {
+			fmt.Fprintf(&sb, "Paragraph %02d talks about the long section at some length.\n\n", i)
+		}
+		sb.WriteString("# Short\n\nA short section.\n")
+		content := sb.String()
+
+		opts := DefaultChunkOptions()
+		opts.MaxChunkSize = 200
+		opts.Overlap = 30
+		result, err := chunker.Chunk(context.Background(), []byte(content), opts)
+		if err != nil {
+			t.Fatalf("Chunk returned error: %v", err)
+		}
+
+		var long []Chunk
+		for _, chunk := range result.Chunks {
+			if chunk.Metadata.Document.Heading == "Long" {
+				long = append(long, chunk)
+			}
+		}
+		if len(long) < 3 {
+			t.Fatalf("expected the long section to split, got %d chunks", len(long))
+		}
+		for i := 1; i < len(long); i++ {
+			prev, next := long[i-1], long[i]
+			if next.StartOffset != prev.EndOffset-opts.Overlap {
+				t.Errorf("chunk %d starts at %d, want %d", next.Index, next.StartOffset, prev.EndOffset-opts.Overlap)
+			}
+			if shared := prev.Content[len(prev.Content)-opts.Overlap:]; !strings.HasPrefix(next.Content, shared) {
+				t.Errorf("chunk %d does not start with the overlap %q", next.Index, shared)
+			}
+			if content[next.StartOffset:next.EndOffset] != next.Content {
+				t.Errorf("chunk %d offsets do not match content", next.Index)
+			}
+		}
+
+		// No overlap across headings
+		last := result.Chunks[len(result.Chunks)-1]
+		if last.Metadata.Document.Heading != "Short" || !strings.HasPrefix(last.Content, "# Short") {
+			t.Errorf("last chunk = %q, want the Short section without overlap", last.Content)
+		}
+	})
+
+	t.Run("table sections are marked", func(t *testing.T) {
+		content := []byte("# Limits\n\nCurrent quotas:\n\n| Name | Value |\n|:-----|------:|\n| cpu | 4 |\n| memory | 8Gi |\n\n# Notes\n\nPlain text | with a pipe.\n")
+		result, err := chunker.Chunk(context.Background(), content, DefaultChunkOptions())
+		if err != nil {
+			t.Fatalf("Chunk returned error: %v", err)
+		}
+		if len(result.Chunks) != 2 {
+			t.Fatalf("expected 2 chunks, got %d", len(result.Chunks))
+		}
+		if !result.Chunks[0].Metadata.Document.IsTable {
+			t.Error("expected table section to be marked IsTable")
+		}
+		if result.Chunks[1].Metadata.Document.IsTable {
+			t.Error("expected prose section not to be marked IsTable")
+		}
+	})
+
+	t.Run("table slightly over max size stays whole", func(t *testing.T) {
+		var sb strings.Builder
+		sb.WriteString("# Data\n\n| Key | Value |\n| --- | --- |\n")
+		for i := 0; sb.Len() < 220; i++ {
+			fmt.Fprintf(&sb, "| key%02d | value%02d |\n", i, i)
+		}
+		opts := DefaultChunkOptions()
+		opts.MaxChunkSize = 200
+		result, err := chunker.Chunk(context.Background(), []byte(sb.String()), opts)
+		if err != nil {
+			t.Fatalf("Chunk returned error: %v", err)
+		}
+		if len(result.Chunks) != 1 {
+			t.Fatalf("expected 1 chunk, got %d", len(result.Chunks))
+		}
+		if !result.Chunks[0].Metadata.Document.IsTable {
+			t.Error("expected chunk to be marked IsTable")
+		}
+	})
+
+	t.Run("enormous table splits on row boundaries", func(t *testing.T) {
+		var sb strings.Builder
+		sb.WriteString("# Data\n\n| Key | Value |\n| --- | --- |\n")
+		rows := 60
+		for i := range rows {
+			fmt.Fprintf(&sb, "| key%02d | value%02d |\n", i, i)
+		}
+		content := sb.String()
+		opts := DefaultChunkOptions()
+		opts.MaxChunkSize = 200
+		result, err := chunker.Chunk(context.Background(), []byte(content), opts)
+		if err != nil {
+			t.Fatalf("Chunk returned error: %v", err)
+		}
+		if len(result.Chunks) < 3 {
+			t.Fatalf("expected table to be split, got %d chunks", len(result.Chunks))
+		}
+
+		seen := 0
+		for _, chunk := range result.Chunks {
+			if !strings.Contains(chunk.Content, "| key") {
+				continue
+			}
+			if !chunk.Metadata.Document.IsTable {
+				t.Errorf("chunk %d not marked IsTable", chunk.Index)
+			}
+			if len(chunk.Content) > opts.MaxChunkSize {
+				t.Errorf("chunk %d has %d bytes, want at most %d", chunk.Index, len(chunk.Content), opts.MaxChunkSize)
+			}
+			for _, line := range strings.Split(chunk.Content, "\n") {
+				if !strings.HasPrefix(line, "|") || !strings.HasSuffix(line, "|") {
+					t.Errorf("chunk %d has partial row %q", chunk.Index, line)
+				}
+				if strings.HasPrefix(line, "| key") {
+					seen++
+				}
+			}
+			if content[chunk.StartOffset:chunk.EndOffset] != chunk.Content {
+				t.Errorf("chunk %d offsets do not match content", chunk.Index)
+			}
+		}
+		if seen != rows {
+			t.Errorf("saw %d rows across chunks, want %d", seen, rows)
+		}
+	})
+
+	t.Run("thematic break is not a heading", func(t *testing.T) {
+		content := []byte("# Title\n\nFirst part.\n\n---\n\nSecond part.\n")
+		result, err := chunker.Chunk(context.Background(), content, DefaultChunkOptions())
+		if err != nil {
+			t.Fatalf("Chunk returned error: %v", err)
+		}
+		if len(result.Chunks) != 1 {
+			t.Fatalf("expected 1 chunk, got %d", len(result.Chunks))
+		}
+		if doc := result.Chunks[0].Metadata.Document; doc.Heading != "Title" || doc.HeadingLevel != 1 {
+			t.Errorf("heading = %q (level %d), want %q (level 1)", doc.Heading, doc.HeadingLevel, "Title")
+		}
+	})
+
+	t.Run("front matter delimiter is not a heading", func(t *testing.T) {
+		content := []byte("---\ntitle: Notes\n---\n\nBody text.\n")
+		result, err := chunker.Chunk(context.Background(), content, DefaultChunkOptions())
+		if err != nil {
+			t.Fatalf("Chunk returned error: %v", err)
+		}
+		if len(result.Chunks) != 1 {
+			t.Fatalf("expected 1 chunk, got %d", len(result.Chunks))
+		}
+		if doc := result.Chunks[0].Metadata.Document; doc.Heading != "" {
+			t.Errorf("heading = %q, want none", doc.Heading)
+		}
+	})
+
+	t.Run("thematic break splits when enabled", func(t *testing.T) {
+		content := []byte("# Guide\n\n## Notes\n\nFirst block.\n\n***\n\nSecond block.\n")
+		opts := DefaultChunkOptions()
+		opts.SplitOnThematicBreak = true
+		result, err := chunker.Chunk(context.Background(), content, opts)
+		if err != nil {
+			t.Fatalf("Chunk returned error: %v", err)
+		}
+		if len(result.Chunks) != 3 {
+			t.Fatalf("expected 3 chunks, got %d", len(result.Chunks))
+		}
+
+		first, second := result.Chunks[1], result.Chunks[2]
+		if !strings.Contains(first.Content, "First block.") || strings.Contains(first.Content, "Second block.") {
+			t.Errorf("first block chunk = %q", first.Content)
+		}
+		if !strings.Contains(second.Content, "Second block.") {
+			t.Errorf("second block chunk = %q", second.Content)
+		}
+		for _, chunk := range []Chunk{first, second} {
+			doc := chunk.Metadata.Document
+			if doc.Heading != "Notes" || doc.HeadingLevel != 2 || doc.SectionPath != "Guide > Notes" {
+				t.Errorf("chunk %d heading = %q (level %d, path %q), want %q (level 2, path %q)",
+					chunk.Index, doc.Heading, doc.HeadingLevel, doc.SectionPath, "Notes", "Guide > Notes")
+			}
+		}
+		if second.StartOffset != first.EndOffset {
+			t.Errorf("second chunk starts at %d, want %d", second.StartOffset, first.EndOffset)
+		}
+	})
+
+	t.Run("thematic break split ignores setext underlines and front matter", func(t *testing.T) {
+		content := []byte("---\ntitle: Notes\n---\n\nIntro\n---\n\nBody text.\n")
+		opts := DefaultChunkOptions()
+		opts.SplitOnThematicBreak = true
+		result, err := chunker.Chunk(context.Background(), content, opts)
+		if err != nil {
+			t.Fatalf("Chunk returned error: %v", err)
+		}
+		// The front matter is lifted into metadata rather than chunked
+		if len(result.Chunks) != 1 {
+			t.Fatalf("expected 1 chunk, got %d", len(result.Chunks))
+		}
+		if doc := result.Chunks[0].Metadata.Document; doc.Heading != "Intro" || doc.HeadingLevel != 2 {
+			t.Errorf("heading = %q (level %d), want %q (level 2)", doc.Heading, doc.HeadingLevel, "Intro")
+		}
+	})
+
+	t.Run("front matter populates first chunk metadata", func(t *testing.T) {
+		content := []byte("---\ntitle: Field Notes\nauthor: Ada Lovelace\ndate: 2024-03-15\n---\n# Intro\n\nFirst.\n\n# Next\n\nSecond.\n")
+		for _, flatten := range []bool{false, true} {
+			opts := DefaultChunkOptions()
+			opts.Flatten = flatten
+			result, err := chunker.Chunk(context.Background(), content, opts)
+			if err != nil {
+				t.Fatalf("Chunk returned error: %v", err)
+			}
+			if len(result.Warnings) != 0 {
+				t.Errorf("unexpected warnings: %+v", result.Warnings)
+			}
+			if len(result.Chunks) == 0 {
+				t.Fatal("expected chunks")
+			}
+
+			first := result.Chunks[0]
+			if strings.Contains(first.Content, "author:") {
+				t.Errorf("flatten=%v: front matter left in content: %q", flatten, first.Content)
+			}
+			if first.StartOffset != strings.Index(string(content), "# Intro") {
+				t.Errorf("flatten=%v: first chunk starts at %d", flatten, first.StartOffset)
+			}
+			if first.Metadata.LineStart != 6 {
+				t.Errorf("flatten=%v: first chunk line = %d, want 6", flatten, first.Metadata.LineStart)
+			}
+			doc := first.Metadata.Document
+			if doc.Title != "Field Notes" || doc.Author != "Ada Lovelace" {
+				t.Errorf("flatten=%v: title = %q, author = %q", flatten, doc.Title, doc.Author)
+			}
+			if want := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC); !doc.CreatedDate.Equal(want) {
+				t.Errorf("flatten=%v: created = %v, want %v", flatten, doc.CreatedDate, want)
+			}
+			for _, chunk := range result.Chunks[1:] {
+				if chunk.Metadata.Document.Title != "" {
+					t.Errorf("flatten=%v: chunk %d has title %q", flatten, chunk.Index, chunk.Metadata.Document.Title)
+				}
+			}
+		}
+	})
+
+	t.Run("malformed front matter is kept as text", func(t *testing.T) {
+		content := []byte("---\ntitle: [unclosed\n---\n\nBody text.\n")
+		result, err := chunker.Chunk(context.Background(), content, DefaultChunkOptions())
+		if err != nil {
+			t.Fatalf("Chunk returned error: %v", err)
+		}
+		if len(result.Warnings) != 1 || result.Warnings[0].Code != "FRONTMATTER_PARSE_ERROR" {
+			t.Fatalf("warnings = %+v, want one FRONTMATTER_PARSE_ERROR", result.Warnings)
+		}
+		if len(result.Chunks) != 1 || !strings.HasPrefix(result.Chunks[0].Content, "---\ntitle:") {
+			t.Fatalf("expected front matter kept in a single chunk, got %+v", result.Chunks)
+		}
+		if doc := result.Chunks[0].Metadata.Document; doc.Title != "" {
+			t.Errorf("title = %q, want none", doc.Title)
+		}
+	})
+
+	t.Run("front matter only at first line", func(t *testing.T) {
+		content := []byte("Intro text.\n\n---\ntitle: Not front matter\n---\n")
+		result, err := chunker.Chunk(context.Background(), content, DefaultChunkOptions())
+		if err != nil {
+			t.Fatalf("Chunk returned error: %v", err)
+		}
+		if len(result.Warnings) != 0 {
+			t.Errorf("unexpected warnings: %+v", result.Warnings)
+		}
+		for _, chunk := range result.Chunks {
+			if chunk.Metadata.Document.Title != "" {
+				t.Errorf("chunk %d has title %q", chunk.Index, chunk.Metadata.Document.Title)
+			}
+		}
+	})
+
 	t.Run("headings inside code blocks not split", func(t *testing.T) {
 		content := []byte("# Real Heading\n\nSome text.\n\n```markdown\n# This is not a heading\n## Neither is this\n```\n\nMore text after code block.")
 		result, err := chunker.Chunk(context.Background(), content, DefaultChunkOptions())
@@ -815,6 +1408,34 @@ func TestMarkdownChunkerEdgeCases(t *testing.T) {
 		}
 	})
 
+	t.Run("fenced code block language", func(t *testing.T) {
+		content := []byte("# Usage\n\n```go\n# not a heading\nfmt.Println()\n```\n\n```bash\nmake\n```\n\n# Plain\n\n~~~\nraw\n~~~\n\n# Prose\n\nNo code here.\n")
+		result, err := chunker.Chunk(context.Background(), content, DefaultChunkOptions())
+		if err != nil {
+			t.Fatalf("Chunk returned error: %v", err)
+		}
+		if len(result.Chunks) != 3 {
+			t.Fatalf("expected 3 chunks, got %d", len(result.Chunks))
+		}
+
+		tests := []struct {
+			heading  string
+			hasCode  bool
+			language string
+		}{
+			{"Usage", true, "go"},
+			{"Plain", true, ""},
+			{"Prose", false, ""},
+		}
+		for i, tt := range tests {
+			doc := result.Chunks[i].Metadata.Document
+			if doc.Heading != tt.heading || doc.HasCodeBlock != tt.hasCode || doc.CodeLanguage != tt.language {
+				t.Errorf("chunk %d = %q (code %v, language %q), want %q (code %v, language %q)",
+					i, doc.Heading, doc.HasCodeBlock, doc.CodeLanguage, tt.heading, tt.hasCode, tt.language)
+			}
+		}
+	})
+
 	t.Run("consecutive headings without content", func(t *testing.T) {
 		content := []byte("# Heading 1\n## Heading 2\n### Heading 3\n\nActual content here.")
 		result, err := chunker.Chunk(context.Background(), content, DefaultChunkOptions())
@@ -965,6 +1586,142 @@ func TestMarkdownChunkerEdgeCases(t *testing.T) {
 	})
 }
 
+func TestStructuredChunkerYAML(t *testing.T) {
+	chunker := NewStructuredChunker()
+
+	chunkYAML := func(t *testing.T, content string, maxSize int) []Chunk {
+		t.Helper()
+		result, err := chunker.Chunk(context.Background(), []byte(content), ChunkOptions{
+			MIMEType:     "text/yaml",
+			MaxChunkSize: maxSize,
+		})
+		if err != nil {
+			t.Fatalf("Chunk returned error: %v", err)
+		}
+		for i, chunk := range result.Chunks {
+			if chunk.Index != i {
+				t.Errorf("Chunk %d has Index %d", i, chunk.Index)
+			}
+			if !strings.HasSuffix(chunk.Content, content[chunk.StartOffset:chunk.EndOffset]) {
+				t.Errorf("Chunk %d offsets [%d, %d) do not match its content %q", i, chunk.StartOffset, chunk.EndOffset, chunk.Content)
+			}
+		}
+		return result.Chunks
+	}
+
+	t.Run("nested maps and sequences keep block scalars whole", func(t *testing.T) {
+		description := "description: |\n  First line of the description.\n\n  Last line after a blank line.\n"
+		script := "  script: >\n    make build &&\n    make test\n"
+		content := "# Service definition\nname: api\n" + description +
+			"build:\n" + script + "  env:\n    - GOOS=linux\n    - GOARCH=amd64\n" +
+			"ports:\n  - name: http\n    port: 80\n  - name: https\n    port: 443\n"
+
+		chunks := chunkYAML(t, content, 90)
+		if len(chunks) < 3 {
+			t.Fatalf("Expected the document to be split by key, got %d chunks", len(chunks))
+		}
+
+		for _, block := range []string{description, script} {
+			found := 0
+			for _, chunk := range chunks {
+				if strings.Contains(chunk.Content, block) {
+					found++
+				} else if strings.Contains(chunk.Content, strings.SplitN(block, "\n", 3)[1]) {
+					t.Errorf("Block scalar split across chunks: %q", chunk.Content)
+				}
+			}
+			if found != 1 {
+				t.Errorf("Expected block scalar in exactly one chunk, found in %d", found)
+			}
+		}
+
+		paths := make(map[string]*StructuredMetadata)
+		for _, chunk := range chunks {
+			meta := chunk.Metadata.Structured
+			if meta == nil {
+				t.Fatalf("Chunk %d has no structured metadata", chunk.Index)
+			}
+			paths[meta.ElementPath] = meta
+		}
+		if meta, ok := paths["/description"]; !ok || meta.ElementName != "description" || !slices.Equal(meta.KeyNames, []string{"description"}) {
+			t.Errorf("Expected a /description chunk, got %v", slices.Sorted(maps.Keys(paths)))
+		}
+		if _, ok := paths["/ports"]; !ok {
+			t.Errorf("Expected a /ports chunk, got %v", slices.Sorted(maps.Keys(paths)))
+		}
+	})
+
+	t.Run("oversized top-level key is split by child keys", func(t *testing.T) {
+		var b strings.Builder
+		b.WriteString("version: 2\nservers:\n")
+		for i := 0; i < 10; i++ {
+			fmt.Fprintf(&b, "  server%d:\n    host: host%d.example.com\n    notes: |\n      Rack %d\n      Row %d\n", i, i, i, i)
+		}
+
+		chunks := chunkYAML(t, b.String(), 150)
+
+		servers := 0
+		for _, chunk := range chunks {
+			meta := chunk.Metadata.Structured
+			if !strings.HasPrefix(meta.ElementPath, "/servers") {
+				continue
+			}
+			servers++
+			if !strings.HasPrefix(chunk.Content, "servers:\n") {
+				t.Errorf("Sub-chunk does not repeat its key line: %q", chunk.Content)
+			}
+			for _, key := range meta.KeyNames {
+				if !strings.HasPrefix(key, "server") {
+					t.Errorf("Unexpected key %q in %s chunk", key, meta.ElementPath)
+				}
+			}
+			if strings.Count(chunk.Content, "notes: |") != strings.Count(chunk.Content, "Row ") {
+				t.Errorf("Block scalar split across chunks: %q", chunk.Content)
+			}
+		}
+		if servers < 2 {
+			t.Errorf("Expected servers to be split into several chunks, got %d", servers)
+		}
+	})
+
+	t.Run("multi-document stream", func(t *testing.T) {
+		content := "# Deployment\n---\napiVersion: v1\nkind: Service\nmetadata:\n  name: web\n" +
+			"---\napiVersion: apps/v1\nkind: Deployment\nspec:\n  replicas: 2\n"
+
+		chunks := chunkYAML(t, content, 1000)
+		if len(chunks) != 2 {
+			t.Fatalf("Expected one chunk per document, got %d", len(chunks))
+		}
+
+		wantKeys := [][]string{
+			{"apiVersion", "kind", "metadata"},
+			{"apiVersion", "kind", "spec"},
+		}
+		for i, chunk := range chunks {
+			meta := chunk.Metadata.Structured
+			if meta.RecordIndex != i {
+				t.Errorf("Chunk %d RecordIndex = %d, want document %d", i, meta.RecordIndex, i)
+			}
+			if !slices.Equal(meta.KeyNames, wantKeys[i]) {
+				t.Errorf("Chunk %d KeyNames = %v, want %v", i, meta.KeyNames, wantKeys[i])
+			}
+			if meta.ElementPath != "/" {
+				t.Errorf("Chunk %d ElementPath = %q, want /", i, meta.ElementPath)
+			}
+		}
+		if !strings.Contains(chunks[0].Content, "# Deployment") || strings.Contains(chunks[0].Content, "kind: Deployment") {
+			t.Errorf("Unexpected first document chunk %q", chunks[0].Content)
+		}
+	})
+
+	t.Run("invalid YAML falls back to lines", func(t *testing.T) {
+		chunks := chunkYAML(t, "key: [unclosed\nother: value\n", 1000)
+		if len(chunks) != 1 {
+			t.Errorf("Expected 1 chunk, got %d", len(chunks))
+		}
+	})
+}
+
 // ============================================================================
 // Phase 1 Edge Case Tests - Structured Chunker
 // ============================================================================
@@ -1145,7 +1902,48 @@ value5,value6`)
 		}
 	})
 
-	t.Run("YAML content fallback to lines", func(t *testing.T) {
+	t.Run("CSV metadata records header columns and record indices", func(t *testing.T) {
+		var b strings.Builder
+		b.WriteString("id,\"full name\",notes\n")
+		for i := 0; i < 20; i++ {
+			// Quoted fields with commas and newlines are single records
+			fmt.Fprintf(&b, "%d,\"Doe, John %d\",\"line one\nline two\"\n", i, i)
+		}
+		opts := ChunkOptions{
+			MIMEType:     "text/csv",
+			MaxChunkSize: 200,
+		}
+		result, err := chunker.Chunk(context.Background(), []byte(b.String()), opts)
+		if err != nil {
+			t.Fatalf("Chunk returned error: %v", err)
+		}
+		if len(result.Chunks) < 2 {
+			t.Fatalf("Expected multiple chunks, got %d", len(result.Chunks))
+		}
+
+		next := 0
+		for _, chunk := range result.Chunks {
+			meta := chunk.Metadata.Structured
+			if meta == nil {
+				t.Fatalf("Chunk %d has no structured metadata", chunk.Index)
+			}
+			if !slices.Equal(meta.KeyNames, []string{"id", "full name", "notes"}) {
+				t.Errorf("Chunk %d KeyNames = %v", chunk.Index, meta.KeyNames)
+			}
+			if meta.RecordIndex != next {
+				t.Errorf("Chunk %d RecordIndex = %d, want %d", chunk.Index, meta.RecordIndex, next)
+			}
+			if meta.RecordCount != 20 {
+				t.Errorf("Chunk %d RecordCount = %d, want 20", chunk.Index, meta.RecordCount)
+			}
+			next += strings.Count(chunk.Content, "line two")
+		}
+		if next != 20 {
+			t.Errorf("Expected 20 records across chunks, got %d", next)
+		}
+	})
+
+	t.Run("YAML content by top-level keys", func(t *testing.T) {
 		content := []byte(`key1: value1
 key2: value2
 nested:
@@ -1365,6 +2163,42 @@ func TestRecursiveChunkerEdgeCases(t *testing.T) {
 func TestFallbackChunkerEdgeCases(t *testing.T) {
 	chunker := NewFallbackChunker()
 
+	t.Run("splits on rune boundaries", func(t *testing.T) {
+		// 3-byte CJK and 4-byte emoji runes with no whitespace to break on
+		content := []byte(strings.Repeat("日本語😀", 40))
+		for _, opts := range []ChunkOptions{
+			{MaxChunkSize: 10},
+			{MaxChunkSize: 10, Overlap: 3},
+			{MaxChunkSize: 2},
+			{MaxChunkSize: 150, Overlap: 20},
+		} {
+			result, err := chunker.Chunk(context.Background(), content, opts)
+			if err != nil {
+				t.Fatalf("Chunk returned error: %v", err)
+			}
+			if len(result.Chunks) == 0 {
+				t.Fatalf("opts %+v: expected chunks", opts)
+			}
+
+			prevEnd := 0
+			for _, chunk := range result.Chunks {
+				if !utf8.ValidString(chunk.Content) {
+					t.Errorf("opts %+v: chunk %d is not valid UTF-8: %q", opts, chunk.Index, chunk.Content)
+				}
+				if string(content[chunk.StartOffset:chunk.EndOffset]) != chunk.Content {
+					t.Errorf("opts %+v: chunk %d offsets [%d, %d) do not match content", opts, chunk.Index, chunk.StartOffset, chunk.EndOffset)
+				}
+				if chunk.StartOffset > prevEnd {
+					t.Errorf("opts %+v: gap before chunk %d at %d", opts, chunk.Index, prevEnd)
+				}
+				prevEnd = chunk.EndOffset
+			}
+			if prevEnd != len(content) {
+				t.Errorf("opts %+v: chunks end at %d, want %d", opts, prevEnd, len(content))
+			}
+		}
+	})
+
 	t.Run("overlap equals maxSize", func(t *testing.T) {
 		content := []byte("Hello world this is a test of overlap handling.")
 		opts := ChunkOptions{
@@ -1528,6 +2362,59 @@ func TestFallbackChunkerEdgeCases(t *testing.T) {
 // Phase 1 Edge Case Tests - Registry
 // ============================================================================
 
+func TestMaxTokensSizing(t *testing.T) {
+	// A single ASCII paragraph well under the byte limit
+	content := []byte(strings.Repeat("The quick brown fox jumps over the lazy dog. ", 40))
+
+	for _, chunker := range []Chunker{NewRecursiveChunker(), NewFallbackChunker()} {
+		t.Run(chunker.Name(), func(t *testing.T) {
+			bytesOnly := ChunkOptions{MaxChunkSize: 4000}
+			result, err := chunker.Chunk(context.Background(), content, bytesOnly)
+			if err != nil {
+				t.Fatalf("Chunk returned error: %v", err)
+			}
+			if len(result.Chunks) != 1 {
+				t.Fatalf("expected 1 chunk without MaxTokens, got %d", len(result.Chunks))
+			}
+
+			opts := ChunkOptions{MaxChunkSize: 4000, MaxTokens: 30}
+			result, err = chunker.Chunk(context.Background(), content, opts)
+			if err != nil {
+				t.Fatalf("Chunk returned error: %v", err)
+			}
+			if len(result.Chunks) < 2 {
+				t.Fatalf("expected token limit to split content, got %d chunks", len(result.Chunks))
+			}
+			for _, chunk := range result.Chunks {
+				if tokens := EstimateTokens(chunk.Content); tokens > opts.MaxTokens {
+					t.Errorf("chunk %d has %d tokens, want at most %d", chunk.Index, tokens, opts.MaxTokens)
+				}
+				if len(chunk.Content) >= opts.MaxChunkSize {
+					t.Errorf("chunk %d has %d bytes, want under the byte limit", chunk.Index, len(chunk.Content))
+				}
+			}
+		})
+	}
+
+	t.Run("fallback overlap leaves no gaps", func(t *testing.T) {
+		opts := ChunkOptions{MaxChunkSize: 4000, MaxTokens: 30, Overlap: 40}
+		result, err := NewFallbackChunker().Chunk(context.Background(), content, opts)
+		if err != nil {
+			t.Fatalf("Chunk returned error: %v", err)
+		}
+		prevEnd := 0
+		for _, chunk := range result.Chunks {
+			if chunk.StartOffset > prevEnd {
+				t.Errorf("gap before chunk %d at %d", chunk.Index, prevEnd)
+			}
+			prevEnd = chunk.EndOffset
+		}
+		if prevEnd != len(content) {
+			t.Errorf("chunks end at %d, want %d", prevEnd, len(content))
+		}
+	})
+}
+
 func TestRegistryEdgeCases(t *testing.T) {
 	t.Run("empty content through registry", func(t *testing.T) {
 		registry := DefaultRegistry()
@@ -1639,6 +2526,22 @@ func TestRegistryEdgeCases(t *testing.T) {
 		}
 	})
 
+	t.Run("unset boundary confidence defaults to heuristic", func(t *testing.T) {
+		registry := NewRegistry()
+		registry.Register(&selectiveChunker{
+			accepts:  "text/special",
+			priority: 50,
+		})
+
+		result, err := registry.Chunk(context.Background(), []byte("test content"), ChunkOptions{MIMEType: "text/special"})
+		if err != nil {
+			t.Fatalf("Chunk returned error: %v", err)
+		}
+		if got := result.Chunks[0].Metadata.BoundaryConfidence; got != BoundaryConfidenceHeuristic {
+			t.Errorf("BoundaryConfidence = %v, want %v", got, BoundaryConfidenceHeuristic)
+		}
+	})
+
 	t.Run("concurrent registry access", func(t *testing.T) {
 		registry := DefaultRegistry()
 		content := []byte("test content for concurrent access")
@@ -1690,3 +2593,92 @@ func (s *selectiveChunker) Chunk(ctx context.Context, content []byte, opts Chunk
 		OriginalSize: len(content),
 	}, nil
 }
+
+func TestFlatten(t *testing.T) {
+	var b strings.Builder
+	for i := range 40 {
+		fmt.Fprintf(&b, "## Section %d\n\nShort paragraph number %d with a few words.\n\n", i, i)
+	}
+	content := []byte(b.String())
+
+	opts := DefaultChunkOptions()
+	opts.MaxChunkSize = 500
+	opts.Flatten = true
+
+	t.Run("markdown is size-uniform", func(t *testing.T) {
+		result, err := NewMarkdownChunker().Chunk(context.Background(), content, opts)
+		if err != nil {
+			t.Fatalf("Chunk returned error: %v", err)
+		}
+
+		if len(result.Chunks) >= 40 {
+			t.Fatalf("expected size-based chunks, got %d heading-split chunks", len(result.Chunks))
+		}
+
+		var rebuilt strings.Builder
+		for i, chunk := range result.Chunks {
+			rebuilt.WriteString(chunk.Content)
+			if len(chunk.Content) > opts.MaxChunkSize {
+				t.Errorf("chunk %d size %d exceeds max %d", i, len(chunk.Content), opts.MaxChunkSize)
+			}
+			if i < len(result.Chunks)-1 && len(chunk.Content) < opts.MaxChunkSize-100 {
+				t.Errorf("chunk %d size %d is not near max %d", i, len(chunk.Content), opts.MaxChunkSize)
+			}
+			if chunk.Metadata.Type != ChunkTypeMarkdown {
+				t.Errorf("chunk %d type = %q, want %q", i, chunk.Metadata.Type, ChunkTypeMarkdown)
+			}
+			if chunk.Metadata.Document == nil || chunk.Metadata.Document.WordCount == 0 {
+				t.Errorf("chunk %d missing document metadata", i)
+			}
+		}
+		if rebuilt.String() != string(content) {
+			t.Error("chunks do not cover the original content")
+		}
+	})
+
+	t.Run("MaxTokens bounds window with the configured tokenizer", func(t *testing.T) {
+		tokenOpts := opts
+		tokenOpts.MaxTokens = 50
+		// One token per word, so windows hold far fewer bytes than 4 per token
+		tokenOpts.Tokenizer = TokenizerFunc(func(text string) int { return len(strings.Fields(text)) })
+		result, err := NewMarkdownChunker().Chunk(context.Background(), content, tokenOpts)
+		if err != nil {
+			t.Fatalf("Chunk returned error: %v", err)
+		}
+		var rebuilt strings.Builder
+		for i, chunk := range result.Chunks {
+			rebuilt.WriteString(chunk.Content)
+			if words := len(strings.Fields(chunk.Content)); words > 50 {
+				t.Errorf("chunk %d has %d tokens, exceeds budget of 50", i, words)
+			}
+		}
+		if rebuilt.String() != string(content) {
+			t.Error("chunks do not cover the original content")
+		}
+	})
+
+	t.Run("off by default", func(t *testing.T) {
+		result, err := NewMarkdownChunker().Chunk(context.Background(), content, ChunkOptions{MaxChunkSize: 500})
+		if err != nil {
+			t.Fatalf("Chunk returned error: %v", err)
+		}
+		if len(result.Chunks) < 40 {
+			t.Errorf("expected heading-split chunks with zero-value options, got %d", len(result.Chunks))
+		}
+	})
+
+	t.Run("structured keeps type", func(t *testing.T) {
+		jsonOpts := opts
+		jsonOpts.MIMEType = "application/json"
+		result, err := NewStructuredChunker().Chunk(context.Background(), []byte(`[{"id": 1}, {"id": 2}]`), jsonOpts)
+		if err != nil {
+			t.Fatalf("Chunk returned error: %v", err)
+		}
+		if len(result.Chunks) != 1 {
+			t.Fatalf("expected 1 chunk, got %d", len(result.Chunks))
+		}
+		if result.Chunks[0].Metadata.Type != ChunkTypeStructured || result.Chunks[0].Metadata.Structured == nil {
+			t.Errorf("unexpected metadata: %+v", result.Chunks[0].Metadata)
+		}
+	})
+}
@@ -48,6 +48,11 @@ func (c *TreeSitterChunker) Priority() int {
 	return treeSitterChunkerPriority
 }
 
+// ChunkType returns the type of content this chunker produces.
+func (c *TreeSitterChunker) ChunkType() chunkers.ChunkType {
+	return chunkers.ChunkTypeCode
+}
+
 // Chunk parses source code and splits it by AST structure.
 func (c *TreeSitterChunker) Chunk(ctx context.Context, content []byte, opts chunkers.ChunkOptions) (*chunkers.ChunkResult, error) {
 	if len(content) == 0 {
@@ -60,8 +65,13 @@ func (c *TreeSitterChunker) Chunk(ctx context.Context, content []byte, opts chun
 		}, nil
 	}
 
-	// Find strategy for this content
+	// Find strategy for this content, falling back to the script's shebang
 	strategy := c.registry.Resolve(opts.MIMEType, opts.Language)
+	if strategy == nil && opts.MIMEType == "" && opts.Language == "" {
+		if interpreter := chunkers.ShebangInterpreter(content); interpreter != "" {
+			strategy = c.registry.Resolve("", interpreter)
+		}
+	}
 	if strategy == nil {
 		return nil, fmt.Errorf("no tree-sitter strategy for mime=%s lang=%s", opts.MIMEType, opts.Language)
 	}
@@ -106,8 +116,9 @@ func (c *TreeSitterChunker) Chunk(ctx context.Context, content []byte, opts chun
 			StartOffset: 0,
 			EndOffset:   len(content),
 			Metadata: chunkers.ChunkMetadata{
-				Type:          chunkers.ChunkTypeCode,
-				TokenEstimate: chunkers.EstimateTokens(contentStr),
+				Type:               chunkers.ChunkTypeCode,
+				TokenEstimate:      chunkers.EstimateTokens(contentStr),
+				BoundaryConfidence: chunkers.BoundaryConfidenceStructural,
 				Code: &chunkers.CodeMetadata{
 					Language: strategy.Language(),
 				},
@@ -135,7 +146,7 @@ func (c *TreeSitterChunker) extractChunks(
 	var chunks []chunkers.Chunk
 	maxSize := opts.MaxChunkSize
 	if maxSize <= 0 {
-		maxSize = chunkers.DefaultChunkOptions().MaxChunkSize
+		maxSize = chunkers.DefaultChunkOptionsForType(c.ChunkType()).MaxChunkSize
 	}
 
 	// First, extract package/import header if present
@@ -149,8 +160,9 @@ func (c *TreeSitterChunker) extractChunks(
 				StartOffset: 0,
 				EndOffset:   headerEnd,
 				Metadata: chunkers.ChunkMetadata{
-					Type:          chunkers.ChunkTypeCode,
-					TokenEstimate: chunkers.EstimateTokens(headerContent),
+					Type:               chunkers.ChunkTypeCode,
+					TokenEstimate:      chunkers.EstimateTokens(headerContent),
+					BoundaryConfidence: chunkers.BoundaryConfidenceStructural,
 					Code: &chunkers.CodeMetadata{
 						Language: strategy.Language(),
 					},
@@ -200,9 +212,14 @@ func (c *TreeSitterChunker) extractChunks(
 			}
 			metadata.Language = strategy.Language()
 
+			// Skip private declarations when only the exported surface is wanted
+			if opts.ExportedOnly && !metadata.IsExported {
+				goto next
+			}
+
 			// Split if too large
 			if len(content) > maxSize {
-				subChunks := c.splitLargeNode(content, metadata, maxSize, start)
+				subChunks := c.splitLargeNode(content, metadata, maxSize, opts.Overlap, start)
 				for _, sc := range subChunks {
 					sc.Index = len(chunks)
 					chunks = append(chunks, sc)
@@ -214,9 +231,10 @@ func (c *TreeSitterChunker) extractChunks(
 					StartOffset: start,
 					EndOffset:   end,
 					Metadata: chunkers.ChunkMetadata{
-						Type:          chunkers.ChunkTypeCode,
-						TokenEstimate: chunkers.EstimateTokens(content),
-						Code:          metadata,
+						Type:               chunkers.ChunkTypeCode,
+						TokenEstimate:      chunkers.EstimateTokens(content),
+						BoundaryConfidence: chunkers.BoundaryConfidenceStructural,
+						Code:               metadata,
 					},
 				})
 			}
@@ -250,13 +268,17 @@ func (c *TreeSitterChunker) extractChunks(
 	return chunks, nil
 }
 
-// findHeaderEnd finds the end position of package/import declarations.
+// findHeaderEnd finds the end position of package/import declarations, or of
+// the header nodes defined by a HeaderStrategy.
 func (c *TreeSitterChunker) findHeaderEnd(root *sitter.Node, source []byte, strategy LanguageStrategy) int {
 	// Look for common header patterns at the start
 	headerEnd := 0
 	cursor := sitter.NewTreeCursor(root)
 	defer cursor.Close()
 
+	// Strategy-defined headers must start the file
+	hs, custom := strategy.(HeaderStrategy)
+
 	if cursor.GoToFirstChild() {
 		for {
 			node := cursor.CurrentNode()
@@ -264,13 +286,26 @@ func (c *TreeSitterChunker) findHeaderEnd(root *sitter.Node, source []byte, stra
 
 			// Common header node types across languages
 			isHeader := false
-			switch nodeType {
-			case "package_clause", "package_declaration", // Go, Java
-				"import_declaration", "import_statement", "import_spec_list", // Various
-				"preproc_include", "preproc_define", // C/C++
-				"use_declaration", "extern_crate_declaration", // Rust
-				"module_declaration": // Various
-				isHeader = true
+			if custom {
+				isHeader = hs.IsHeaderNode(node, source)
+			} else {
+				switch nodeType {
+				case "package_declaration", // Java
+					"import_declaration", "import_statement", "import_spec_list", // Various
+					"preproc_include", "preproc_define", // C/C++
+					"use_declaration", "extern_crate_declaration", // Rust
+					"groovy_package", "groovy_import", // Groovy
+					"package_header", "import_list", // Kotlin
+					"php_tag", "namespace_use_declaration", // PHP
+					"using_directive", "file_scoped_namespace_declaration", // C#
+					"module_declaration": // Various
+					isHeader = true
+				case "package_clause", "namespace_definition":
+					// Go and Scala package clauses and PHP "namespace X;"
+					// statements; braced Scala packages and PHP namespaces
+					// hold code
+					isHeader = node.ChildByFieldName("body") == nil
+				}
 			}
 
 			if isHeader {
@@ -278,7 +313,7 @@ func (c *TreeSitterChunker) findHeaderEnd(root *sitter.Node, source []byte, stra
 				if end > headerEnd {
 					headerEnd = end
 				}
-			} else if headerEnd > 0 {
+			} else if headerEnd > 0 || custom {
 				// Stop at first non-header node after finding headers
 				break
 			}
@@ -292,18 +327,22 @@ func (c *TreeSitterChunker) findHeaderEnd(root *sitter.Node, source []byte, stra
 	return headerEnd
 }
 
-// splitLargeNode splits a large AST node into smaller chunks.
-func (c *TreeSitterChunker) splitLargeNode(content string, baseMeta *chunkers.CodeMetadata, maxSize, baseOffset int) []chunkers.Chunk {
+// splitLargeNode splits a large AST node into smaller chunks on line
+// boundaries. Each chunk after the first starts with the last overlap bytes of
+// the chunk before it.
+func (c *TreeSitterChunker) splitLargeNode(content string, baseMeta *chunkers.CodeMetadata, maxSize, overlap, baseOffset int) []chunkers.Chunk {
 	var chunks []chunkers.Chunk
 	lines := strings.Split(content, "\n")
 
 	var current strings.Builder
 	offset := baseOffset
+	carried := 0
 
 	for _, line := range lines {
 		lineLen := len(line) + 1
 
-		if current.Len()+lineLen > maxSize && current.Len() > 0 {
+		// Never emit a chunk holding nothing but the carried overlap
+		if current.Len()+lineLen > maxSize && current.Len() > carried {
 			chunkContent := current.String()
 			meta := *baseMeta // Copy metadata
 
@@ -312,12 +351,16 @@ func (c *TreeSitterChunker) splitLargeNode(content string, baseMeta *chunkers.Co
 				StartOffset: offset - current.Len(),
 				EndOffset:   offset,
 				Metadata: chunkers.ChunkMetadata{
-					Type:          chunkers.ChunkTypeCode,
-					TokenEstimate: chunkers.EstimateTokens(chunkContent),
-					Code:          &meta,
+					Type:               chunkers.ChunkTypeCode,
+					TokenEstimate:      chunkers.EstimateTokens(chunkContent),
+					BoundaryConfidence: chunkers.BoundaryConfidenceHeuristic,
+					Code:               &meta,
 				},
 			})
 			current.Reset()
+			carry := chunkContent[chunkers.OverlapStart(chunkContent, overlap):]
+			current.WriteString(carry)
+			carried = len(carry)
 		}
 
 		current.WriteString(line)
@@ -334,9 +377,10 @@ func (c *TreeSitterChunker) splitLargeNode(content string, baseMeta *chunkers.Co
 			StartOffset: offset - current.Len(),
 			EndOffset:   offset,
 			Metadata: chunkers.ChunkMetadata{
-				Type:          chunkers.ChunkTypeCode,
-				TokenEstimate: chunkers.EstimateTokens(chunkContent),
-				Code:          &meta,
+				Type:               chunkers.ChunkTypeCode,
+				TokenEstimate:      chunkers.EstimateTokens(chunkContent),
+				BoundaryConfidence: chunkers.BoundaryConfidenceHeuristic,
+				Code:               &meta,
 			},
 		})
 	}
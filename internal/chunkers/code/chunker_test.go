@@ -5,6 +5,8 @@ import (
 	"strings"
 	"testing"
 
+	sitter "github.com/smacker/go-tree-sitter"
+
 	"github.com/leefowlercu/agentic-memorizer/internal/chunkers"
 	"github.com/leefowlercu/agentic-memorizer/internal/chunkers/code"
 	"github.com/leefowlercu/agentic-memorizer/internal/chunkers/code/languages"
@@ -712,6 +714,1129 @@ public:
 	})
 }
 
+func TestGroovyStrategy(t *testing.T) {
+	strategy := languages.NewGroovyStrategy()
+	c := code.NewTreeSitterChunker()
+	c.RegisterStrategy(strategy)
+
+	t.Run("GradleDependenciesBlock", func(t *testing.T) {
+		code := `plugins {
+    id 'java'
+}
+
+dependencies {
+    implementation 'org.slf4j:slf4j-api:2.0.9'
+    testImplementation 'junit:junit:4.13.2'
+}
+`
+		result, err := c.Chunk(context.Background(), []byte(code), chunkers.ChunkOptions{
+			MIMEType: "text/x-groovy",
+		})
+		if err != nil {
+			t.Fatalf("Chunk failed: %v", err)
+		}
+
+		var deps *chunkers.Chunk
+		for i := range result.Chunks {
+			if meta := result.Chunks[i].Metadata.Code; meta != nil && meta.FunctionName == "dependencies" {
+				deps = &result.Chunks[i]
+				break
+			}
+		}
+		if deps == nil {
+			t.Fatal("expected dependencies block chunk")
+		}
+
+		meta := deps.Metadata.Code
+		if meta.Language != "groovy" {
+			t.Errorf("expected language 'groovy', got %q", meta.Language)
+		}
+		if meta.Signature != "dependencies {}" {
+			t.Errorf("expected block signature 'dependencies {}', got %q", meta.Signature)
+		}
+		if !strings.Contains(deps.Content, "slf4j-api") {
+			t.Errorf("dependencies chunk missing block content: %q", deps.Content)
+		}
+		if strings.Contains(deps.Content, "plugins") {
+			t.Error("dependencies chunk should not include the plugins block")
+		}
+	})
+
+	t.Run("ClassWithMethod", func(t *testing.T) {
+		source := []byte(`class Greeter {
+    private String prefix
+
+    String greet(String name) {
+        return prefix + name
+    }
+}
+`)
+		result, err := c.Chunk(context.Background(), source, chunkers.ChunkOptions{
+			Language: "groovy",
+		})
+		if err != nil {
+			t.Fatalf("Chunk failed: %v", err)
+		}
+
+		var classMeta *chunkers.CodeMetadata
+		for i := range result.Chunks {
+			if meta := result.Chunks[i].Metadata.Code; meta != nil && meta.ClassName == "Greeter" && meta.FunctionName == "" {
+				classMeta = meta
+				break
+			}
+		}
+		if classMeta == nil {
+			t.Fatal("expected Greeter class chunk")
+		}
+		if classMeta.Visibility != "public" {
+			t.Errorf("expected class visibility 'public', got %q", classMeta.Visibility)
+		}
+
+		// Methods are chunked with their class, so extract the method metadata directly
+		meta := strategy.ExtractMetadata(parseFirstNode(t, strategy, source, "function_definition"), source)
+		if meta.FunctionName != "greet" {
+			t.Errorf("expected function name 'greet', got %q", meta.FunctionName)
+		}
+		if meta.ClassName != "Greeter" {
+			t.Errorf("expected class name 'Greeter', got %q", meta.ClassName)
+		}
+		if meta.Visibility != "public" {
+			t.Errorf("expected method visibility 'public', got %q", meta.Visibility)
+		}
+		if meta.ReturnType != "String" {
+			t.Errorf("expected return type 'String', got %q", meta.ReturnType)
+		}
+		if len(meta.Parameters) != 1 || meta.Parameters[0] != "name" {
+			t.Errorf("expected parameters [name], got %v", meta.Parameters)
+		}
+	})
+}
+
+func TestKotlinStrategy(t *testing.T) {
+	strategy := languages.NewKotlinStrategy()
+	c := code.NewTreeSitterChunker()
+	c.RegisterStrategy(strategy)
+
+	for _, tt := range []struct{ mime, lang string }{
+		{"text/x-kotlin", ""},
+		{"", "kotlin"},
+		{"", ".kt"},
+		{"", "kts"},
+	} {
+		if !c.CanHandle(tt.mime, tt.lang) {
+			t.Errorf("CanHandle(%q, %q) = false, want true", tt.mime, tt.lang)
+		}
+	}
+
+	t.Run("DataClassWithCompanion", func(t *testing.T) {
+		source := []byte(`package com.example
+
+import kotlin.math.max
+
+/** A registered user. */
+data class User(val name: String, private val age: Int) : Base(), Named {
+    companion object {
+        fun create(name: String): User = User(name, 0)
+    }
+}
+
+object Registry {
+    val users = mutableListOf<User>()
+}
+
+private const val LIMIT = 10
+`)
+		result, err := c.Chunk(context.Background(), source, chunkers.ChunkOptions{
+			Language: "kotlin",
+		})
+		if err != nil {
+			t.Fatalf("Chunk failed: %v", err)
+		}
+		for _, w := range result.Warnings {
+			if w.Code == "PARSE_ERROR" {
+				t.Errorf("unexpected parse error warning: %s", w.Message)
+			}
+		}
+
+		byClass := make(map[string]*chunkers.CodeMetadata)
+		var property *chunkers.CodeMetadata
+		for i := range result.Chunks {
+			meta := result.Chunks[i].Metadata.Code
+			if meta == nil {
+				continue
+			}
+			if meta.ClassName != "" && meta.FunctionName == "" {
+				byClass[meta.ClassName] = meta
+			}
+			if strings.Contains(meta.Signature, "LIMIT") {
+				property = meta
+			}
+		}
+
+		user := byClass["User"]
+		if user == nil {
+			t.Fatal("expected User class chunk")
+		}
+		if user.ParentClass != "Base" {
+			t.Errorf("expected parent class 'Base', got %q", user.ParentClass)
+		}
+		if len(user.Implements) != 1 || user.Implements[0] != "Named" {
+			t.Errorf("expected implements [Named], got %v", user.Implements)
+		}
+		if user.Visibility != "public" || !user.IsExported {
+			t.Errorf("expected exported public class, got visibility %q exported %v", user.Visibility, user.IsExported)
+		}
+		if user.Docstring != "A registered user." {
+			t.Errorf("expected KDoc docstring, got %q", user.Docstring)
+		}
+		if byClass["Registry"] == nil {
+			t.Error("expected Registry object chunk")
+		}
+		if property == nil {
+			t.Fatal("expected LIMIT property chunk")
+		}
+		if property.Visibility != "private" || property.IsExported {
+			t.Errorf("expected private unexported property, got visibility %q exported %v", property.Visibility, property.IsExported)
+		}
+
+		// The companion's function belongs to the outer class
+		meta := strategy.ExtractMetadata(parseFirstNode(t, strategy, source, "function_declaration"), source)
+		if meta.FunctionName != "create" || meta.ClassName != "User" {
+			t.Errorf("expected User.create, got %s.%s", meta.ClassName, meta.FunctionName)
+		}
+	})
+
+	t.Run("FunctionMetadata", func(t *testing.T) {
+		source := []byte(`class Greeter {
+    @Deprecated("use greetAll")
+    internal suspend fun greet(prefix: String, times: Int = 1): String? {
+        return prefix
+    }
+}
+`)
+		meta := strategy.ExtractMetadata(parseFirstNode(t, strategy, source, "function_declaration"), source)
+		if meta.FunctionName != "greet" {
+			t.Errorf("expected function name 'greet', got %q", meta.FunctionName)
+		}
+		if meta.ClassName != "Greeter" {
+			t.Errorf("expected class name 'Greeter', got %q", meta.ClassName)
+		}
+		if meta.Visibility != "internal" || meta.IsExported {
+			t.Errorf("expected unexported internal function, got visibility %q exported %v", meta.Visibility, meta.IsExported)
+		}
+		if !meta.IsAsync {
+			t.Error("expected suspend function to be async")
+		}
+		if meta.ReturnType != "String?" {
+			t.Errorf("expected return type 'String?', got %q", meta.ReturnType)
+		}
+		if len(meta.Parameters) != 2 || meta.Parameters[0] != "prefix: String" || meta.Parameters[1] != "times: Int" {
+			t.Errorf("expected parameters [prefix: String times: Int], got %v", meta.Parameters)
+		}
+		if len(meta.Decorators) != 1 || meta.Decorators[0] != "Deprecated" {
+			t.Errorf("expected decorators [Deprecated], got %v", meta.Decorators)
+		}
+		if meta.Signature != "internal suspend fun greet(prefix: String, times: Int): String?" {
+			t.Errorf("unexpected signature %q", meta.Signature)
+		}
+	})
+
+	t.Run("ExtensionFunction", func(t *testing.T) {
+		source := []byte("fun String.shout(): String = uppercase()\n")
+		meta := strategy.ExtractMetadata(parseFirstNode(t, strategy, source, "function_declaration"), source)
+		if meta.FunctionName != "shout" {
+			t.Errorf("expected function name 'shout', got %q", meta.FunctionName)
+		}
+		if meta.Signature != "fun String.shout(): String" {
+			t.Errorf("unexpected signature %q", meta.Signature)
+		}
+	})
+}
+
+func TestRubyStrategy(t *testing.T) {
+	strategy := languages.NewRubyStrategy()
+	c := code.NewTreeSitterChunker()
+	c.RegisterStrategy(strategy)
+
+	for _, tt := range []struct{ mime, lang string }{
+		{"text/x-ruby", ""},
+		{"", "ruby"},
+		{"", ".rb"},
+		{"", "rake"},
+		{"", "Gemfile"},
+	} {
+		if !c.CanHandle(tt.mime, tt.lang) {
+			t.Errorf("CanHandle(%q, %q) = false, want true", tt.mime, tt.lang)
+		}
+	}
+
+	source := []byte(`require "json"
+
+module Billing
+  # Computes invoice totals.
+  class Invoice < Base::Record
+    include Comparable
+
+    def initialize(amount, *items, currency:, rate: 1, **opts, &block)
+      @amount = amount
+    end
+
+    def self.build(attrs = {})
+      new(**attrs)
+    end
+
+    private
+
+    def secret
+      42
+    end
+  end
+end
+
+module Helpers
+  def self.slug(text)
+    text.downcase
+  end
+end
+`)
+
+	t.Run("ClassWithInstanceMethods", func(t *testing.T) {
+		result, err := c.Chunk(context.Background(), source, chunkers.ChunkOptions{
+			Language: "ruby",
+		})
+		if err != nil {
+			t.Fatalf("Chunk failed: %v", err)
+		}
+		for _, w := range result.Warnings {
+			if w.Code == "PARSE_ERROR" {
+				t.Errorf("unexpected parse error warning: %s", w.Message)
+			}
+		}
+
+		var invoice *chunkers.CodeMetadata
+		for i := range result.Chunks {
+			if meta := result.Chunks[i].Metadata.Code; meta != nil && meta.ClassName == "Invoice" {
+				invoice = meta
+			}
+		}
+		if invoice == nil {
+			t.Fatal("expected Invoice class chunk")
+		}
+		if invoice.Namespace != "Billing" {
+			t.Errorf("expected namespace 'Billing', got %q", invoice.Namespace)
+		}
+		if invoice.ParentClass != "Base::Record" {
+			t.Errorf("expected parent class 'Base::Record', got %q", invoice.ParentClass)
+		}
+		if len(invoice.Implements) != 1 || invoice.Implements[0] != "Comparable" {
+			t.Errorf("expected implements [Comparable], got %v", invoice.Implements)
+		}
+		if invoice.Docstring != "Computes invoice totals." {
+			t.Errorf("expected comment docstring, got %q", invoice.Docstring)
+		}
+
+		meta := strategy.ExtractMetadata(parseFirstNode(t, strategy, source, "method"), source)
+		if meta.FunctionName != "initialize" || meta.ClassName != "Invoice" || !meta.IsConstructor {
+			t.Errorf("expected Invoice#initialize constructor, got %s#%s", meta.ClassName, meta.FunctionName)
+		}
+		want := []string{"amount", "*items", "currency:", "rate:", "**opts", "&block"}
+		if strings.Join(meta.Parameters, ",") != strings.Join(want, ",") {
+			t.Errorf("expected parameters %v, got %v", want, meta.Parameters)
+		}
+		if meta.Visibility != "public" || !meta.IsExported {
+			t.Errorf("expected public method, got visibility %q exported %v", meta.Visibility, meta.IsExported)
+		}
+
+		build := strategy.ExtractMetadata(parseFirstNode(t, strategy, source, "singleton_method"), source)
+		if build.FunctionName != "build" || !build.IsStatic {
+			t.Errorf("expected static build method, got %q static %v", build.FunctionName, build.IsStatic)
+		}
+		if build.Signature != "def self.build(attrs)" {
+			t.Errorf("unexpected signature %q", build.Signature)
+		}
+
+		body := parseFirstNode(t, strategy, source, "class").ChildByFieldName("body")
+		secret := body.NamedChild(int(body.NamedChildCount()) - 1)
+		meta = strategy.ExtractMetadata(secret, source)
+		if meta.FunctionName != "secret" {
+			t.Fatalf("expected secret method, got %q", meta.FunctionName)
+		}
+		if meta.Visibility != "private" || meta.IsExported {
+			t.Errorf("expected private unexported method, got visibility %q exported %v", meta.Visibility, meta.IsExported)
+		}
+		if meta.Signature != "private def secret" {
+			t.Errorf("unexpected signature %q", meta.Signature)
+		}
+	})
+
+	t.Run("TopLevelModule", func(t *testing.T) {
+		result, err := c.Chunk(context.Background(), source, chunkers.ChunkOptions{
+			Language: "ruby",
+		})
+		if err != nil {
+			t.Fatalf("Chunk failed: %v", err)
+		}
+
+		var helpers *chunkers.CodeMetadata
+		for i := range result.Chunks {
+			meta := result.Chunks[i].Metadata.Code
+			if meta == nil {
+				continue
+			}
+			if meta.Namespace == "Billing" && meta.ClassName == "" {
+				t.Error("expected Billing to be split into its nested types")
+			}
+			if meta.Namespace == "Helpers" {
+				helpers = meta
+				if !strings.Contains(result.Chunks[i].Content, "def self.slug") {
+					t.Error("expected Helpers chunk to contain its methods")
+				}
+			}
+		}
+		if helpers == nil {
+			t.Fatal("expected Helpers module chunk")
+		}
+		if helpers.ClassName != "" || helpers.FunctionName != "" {
+			t.Errorf("expected module-only metadata, got class %q function %q", helpers.ClassName, helpers.FunctionName)
+		}
+	})
+}
+
+func TestPHPStrategy(t *testing.T) {
+	strategy := languages.NewPHPStrategy()
+	c := code.NewTreeSitterChunker()
+	c.RegisterStrategy(strategy)
+
+	for _, tt := range []struct{ mime, lang string }{
+		{"application/x-php", ""},
+		{"", "php"},
+		{"", ".php"},
+		{"", "phtml"},
+	} {
+		if !c.CanHandle(tt.mime, tt.lang) {
+			t.Errorf("CanHandle(%q, %q) = false, want true", tt.mime, tt.lang)
+		}
+	}
+
+	source := []byte(`<?php
+
+namespace App\Models;
+
+use Illuminate\Database\Eloquent\Model;
+
+/**
+ * A registered user.
+ */
+final class User extends Model implements Billable, Named
+{
+    use HasFactory;
+
+    protected static function find(?int $id = null, array &$opts = []): ?User
+    {
+        return null;
+    }
+}
+
+interface Greeter extends Base
+{
+    public function greet(string $who, string ...$rest): string;
+}
+
+trait HasFactory
+{
+    function make() {}
+}
+
+function helper($x, $y = 2) {
+    return $x;
+}
+`)
+
+	t.Run("NamespacedClasses", func(t *testing.T) {
+		result, err := c.Chunk(context.Background(), source, chunkers.ChunkOptions{
+			Language: "php",
+		})
+		if err != nil {
+			t.Fatalf("Chunk failed: %v", err)
+		}
+		for _, w := range result.Warnings {
+			if w.Code == "PARSE_ERROR" {
+				t.Errorf("unexpected parse error warning: %s", w.Message)
+			}
+		}
+
+		if len(result.Chunks) == 0 || result.Chunks[0].Metadata.Code == nil || result.Chunks[0].Metadata.Code.ClassName != "" {
+			t.Fatal("expected header chunk first")
+		}
+		header := result.Chunks[0].Content
+		if !strings.HasPrefix(header, "<?php") || !strings.Contains(header, "use Illuminate") {
+			t.Errorf("expected header with php tag and use statements, got %q", header)
+		}
+
+		byName := make(map[string]*chunkers.CodeMetadata)
+		for i := range result.Chunks {
+			meta := result.Chunks[i].Metadata.Code
+			if meta == nil {
+				continue
+			}
+			if meta.FunctionName != "" {
+				byName[meta.FunctionName] = meta
+			} else if meta.ClassName != "" {
+				byName[meta.ClassName] = meta
+			}
+		}
+
+		user := byName["User"]
+		if user == nil {
+			t.Fatal("expected User class chunk")
+		}
+		if user.Namespace != `App\Models` {
+			t.Errorf("expected namespace 'App\\Models', got %q", user.Namespace)
+		}
+		if user.ParentClass != "Model" {
+			t.Errorf("expected parent class 'Model', got %q", user.ParentClass)
+		}
+		if strings.Join(user.Implements, ",") != "Billable,Named" {
+			t.Errorf("expected implements [Billable Named], got %v", user.Implements)
+		}
+		if user.Docstring != "A registered user." {
+			t.Errorf("expected PHPDoc docstring, got %q", user.Docstring)
+		}
+
+		greeter := byName["Greeter"]
+		if greeter == nil {
+			t.Fatal("expected Greeter interface chunk")
+		}
+		if greeter.ParentClass != "" || len(greeter.Implements) != 1 || greeter.Implements[0] != "Base" {
+			t.Errorf("expected interface to extend [Base], got parent %q implements %v", greeter.ParentClass, greeter.Implements)
+		}
+		if byName["HasFactory"] == nil {
+			t.Error("expected HasFactory trait chunk")
+		}
+		helper := byName["helper"]
+		if helper == nil {
+			t.Fatal("expected helper function chunk")
+		}
+		if helper.Namespace != `App\Models` || helper.Signature != "function helper($x, $y)" {
+			t.Errorf("unexpected helper metadata: namespace %q signature %q", helper.Namespace, helper.Signature)
+		}
+	})
+
+	t.Run("MethodMetadata", func(t *testing.T) {
+		meta := strategy.ExtractMetadata(parseFirstNode(t, strategy, source, "method_declaration"), source)
+		if meta.FunctionName != "find" || meta.ClassName != "User" {
+			t.Errorf("expected User::find, got %s::%s", meta.ClassName, meta.FunctionName)
+		}
+		if meta.Visibility != "protected" || meta.IsExported {
+			t.Errorf("expected unexported protected method, got visibility %q exported %v", meta.Visibility, meta.IsExported)
+		}
+		if !meta.IsStatic {
+			t.Error("expected static method")
+		}
+		if strings.Join(meta.Parameters, ", ") != "?int $id, array &$opts" {
+			t.Errorf("unexpected parameters %v", meta.Parameters)
+		}
+		if meta.Signature != "protected static function find(?int $id, array &$opts): ?User" {
+			t.Errorf("unexpected signature %q", meta.Signature)
+		}
+
+		body := parseFirstNode(t, strategy, source, "interface_declaration").ChildByFieldName("body")
+		greet := strategy.ExtractMetadata(body.NamedChild(0), source)
+		if strings.Join(greet.Parameters, ", ") != "string $who, string ...$rest" {
+			t.Errorf("unexpected variadic parameters %v", greet.Parameters)
+		}
+	})
+}
+
+func TestCSharpStrategy(t *testing.T) {
+	strategy := languages.NewCSharpStrategy()
+	c := code.NewTreeSitterChunker()
+	c.RegisterStrategy(strategy)
+
+	for _, tt := range []struct{ mime, lang string }{
+		{"text/x-csharp", ""},
+		{"", "csharp"},
+		{"", ".cs"},
+	} {
+		if !c.CanHandle(tt.mime, tt.lang) {
+			t.Errorf("CanHandle(%q, %q) = false, want true", tt.mime, tt.lang)
+		}
+	}
+
+	source := []byte(`using System;
+using System.Threading.Tasks;
+
+namespace App.Controllers
+{
+    /// <summary>
+    /// Handles users.
+    /// </summary>
+    [ApiController]
+    public sealed class UsersController : ControllerBase, IDisposable
+    {
+        public string Name { get; set; }
+
+        [HttpGet("{id}")]
+        public async Task<User?> GetAsync(int id, CancellationToken ct = default)
+        {
+            return null;
+        }
+
+        static void Helper(ref int x, params string[] rest) {}
+    }
+
+    interface IRepo<T>
+    {
+        T Find(int id);
+    }
+
+    public struct Point { public int X; }
+
+    public enum Color { Red, Green }
+}
+`)
+
+	t.Run("TypesInNamespace", func(t *testing.T) {
+		result, err := c.Chunk(context.Background(), source, chunkers.ChunkOptions{
+			Language: "csharp",
+		})
+		if err != nil {
+			t.Fatalf("Chunk failed: %v", err)
+		}
+		for _, w := range result.Warnings {
+			if w.Code == "PARSE_ERROR" {
+				t.Errorf("unexpected parse error warning: %s", w.Message)
+			}
+		}
+
+		byClass := make(map[string]*chunkers.CodeMetadata)
+		for i := range result.Chunks {
+			if meta := result.Chunks[i].Metadata.Code; meta != nil && meta.ClassName != "" {
+				byClass[meta.ClassName] = meta
+			}
+		}
+		for _, name := range []string{"UsersController", "IRepo", "Point", "Color"} {
+			if byClass[name] == nil {
+				t.Errorf("expected %s chunk", name)
+			}
+		}
+
+		controller := byClass["UsersController"]
+		if controller == nil {
+			t.Fatal("expected UsersController class chunk")
+		}
+		if controller.Namespace != "App.Controllers" {
+			t.Errorf("expected namespace 'App.Controllers', got %q", controller.Namespace)
+		}
+		if controller.ParentClass != "ControllerBase" {
+			t.Errorf("expected parent class 'ControllerBase', got %q", controller.ParentClass)
+		}
+		if len(controller.Implements) != 1 || controller.Implements[0] != "IDisposable" {
+			t.Errorf("expected implements [IDisposable], got %v", controller.Implements)
+		}
+		if len(controller.Decorators) != 1 || controller.Decorators[0] != "ApiController" {
+			t.Errorf("expected decorators [ApiController], got %v", controller.Decorators)
+		}
+		if controller.Docstring != "Handles users." {
+			t.Errorf("expected XML doc docstring, got %q", controller.Docstring)
+		}
+		if repo := byClass["IRepo"]; repo != nil && (repo.Visibility != "internal" || repo.IsExported) {
+			t.Errorf("expected internal interface, got visibility %q exported %v", repo.Visibility, repo.IsExported)
+		}
+	})
+
+	t.Run("MethodMetadata", func(t *testing.T) {
+		meta := strategy.ExtractMetadata(parseFirstNode(t, strategy, source, "method_declaration"), source)
+		if meta.FunctionName != "GetAsync" || meta.ClassName != "UsersController" {
+			t.Errorf("expected UsersController.GetAsync, got %s.%s", meta.ClassName, meta.FunctionName)
+		}
+		if meta.ReturnType != "Task<User?>" {
+			t.Errorf("expected return type 'Task<User?>', got %q", meta.ReturnType)
+		}
+		if !meta.IsAsync || meta.IsStatic {
+			t.Errorf("expected async instance method, got async %v static %v", meta.IsAsync, meta.IsStatic)
+		}
+		if meta.Visibility != "public" || !meta.IsExported {
+			t.Errorf("expected public method, got visibility %q exported %v", meta.Visibility, meta.IsExported)
+		}
+		if len(meta.Decorators) != 1 || meta.Decorators[0] != "HttpGet" {
+			t.Errorf("expected decorators [HttpGet], got %v", meta.Decorators)
+		}
+		if meta.Namespace != "App.Controllers" {
+			t.Errorf("expected namespace 'App.Controllers', got %q", meta.Namespace)
+		}
+		if meta.Signature != "public async Task<User?> GetAsync(int id, CancellationToken ct)" {
+			t.Errorf("unexpected signature %q", meta.Signature)
+		}
+
+		helper := strategy.ExtractMetadata(parseFirstNode(t, strategy, source, "method_declaration").NextNamedSibling(), source)
+		if helper.FunctionName != "Helper" || !helper.IsStatic {
+			t.Errorf("expected static Helper, got %q static %v", helper.FunctionName, helper.IsStatic)
+		}
+		if helper.Visibility != "private" || helper.IsExported {
+			t.Errorf("expected private default visibility, got %q", helper.Visibility)
+		}
+		if strings.Join(helper.Parameters, ", ") != "ref int x, params string[] rest" {
+			t.Errorf("unexpected parameters %v", helper.Parameters)
+		}
+
+		property := strategy.ExtractMetadata(parseFirstNode(t, strategy, source, "property_declaration"), source)
+		if property.Signature != "public string Name { get; set; }" || !property.IsGetter || !property.IsSetter {
+			t.Errorf("unexpected property metadata: signature %q getter %v setter %v", property.Signature, property.IsGetter, property.IsSetter)
+		}
+	})
+}
+
+func TestBashStrategy(t *testing.T) {
+	strategy := languages.NewBashStrategy()
+	c := code.NewTreeSitterChunker()
+	c.RegisterStrategy(strategy)
+
+	for _, tt := range []struct{ mime, lang string }{
+		{"text/x-shellscript", ""},
+		{"", "bash"},
+		{"", ".sh"},
+		{"", "sh"},
+	} {
+		if !c.CanHandle(tt.mime, tt.lang) {
+			t.Errorf("CanHandle(%q, %q) = false, want true", tt.mime, tt.lang)
+		}
+	}
+
+	source := []byte(`#!/usr/bin/env bash
+# Deploy helper.
+set -euo pipefail
+
+readonly ROOT="$(pwd)"
+COUNT=3
+
+# Builds the thing.
+build() {
+  echo "$ROOT"
+}
+
+function _deploy {
+  build
+}
+
+_deploy "$@"
+`)
+
+	t.Run("HeaderAndFunctions", func(t *testing.T) {
+		result, err := c.Chunk(context.Background(), source, chunkers.ChunkOptions{
+			Language: "bash",
+		})
+		if err != nil {
+			t.Fatalf("Chunk failed: %v", err)
+		}
+		if len(result.Chunks) != 3 {
+			t.Fatalf("expected header and two function chunks, got %d", len(result.Chunks))
+		}
+
+		header := result.Chunks[0].Content
+		if !strings.HasPrefix(header, "#!/usr/bin/env bash") || !strings.HasSuffix(header, "COUNT=3") {
+			t.Errorf("expected header to span shebang through assignments, got %q", header)
+		}
+		if strings.Contains(header, "Builds the thing") {
+			t.Error("expected function comment to stay out of the header")
+		}
+
+		build := result.Chunks[1].Metadata.Code
+		if build.FunctionName != "build" || build.Signature != "build()" {
+			t.Errorf("expected build(), got %q signature %q", build.FunctionName, build.Signature)
+		}
+		if build.Docstring != "Builds the thing." || !build.IsExported {
+			t.Errorf("unexpected build metadata: docstring %q exported %v", build.Docstring, build.IsExported)
+		}
+		deploy := result.Chunks[2].Metadata.Code
+		if deploy.FunctionName != "_deploy" || deploy.IsExported {
+			t.Errorf("expected unexported _deploy, got %q exported %v", deploy.FunctionName, deploy.IsExported)
+		}
+	})
+
+	t.Run("ShebangDetection", func(t *testing.T) {
+		result, err := c.Chunk(context.Background(), source, chunkers.ChunkOptions{})
+		if err != nil {
+			t.Fatalf("Chunk without language or MIME failed: %v", err)
+		}
+		if result.Chunks[0].Metadata.Code.Language != "bash" {
+			t.Errorf("expected bash from shebang, got %q", result.Chunks[0].Metadata.Code.Language)
+		}
+
+		for _, tt := range []struct{ line, want string }{
+			{"#!/bin/bash\n", "bash"},
+			{"#!/bin/sh -e\n", "sh"},
+			{"#!/usr/bin/env bash\n", "bash"},
+			{"#!/usr/bin/env -S bash -x\n", "bash"},
+			{"#!/usr/bin/python3\n", "python"},
+			{"echo hi\n", ""},
+		} {
+			if got := chunkers.ShebangInterpreter([]byte(tt.line)); got != tt.want {
+				t.Errorf("ShebangInterpreter(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+		}
+	})
+
+	t.Run("LargeFunctionSplit", func(t *testing.T) {
+		source := []byte("big() {\n" + strings.Repeat("  echo \"some line of output\"\n", 50) + "}\n")
+		result, err := c.Chunk(context.Background(), source, chunkers.ChunkOptions{
+			Language:     "bash",
+			MaxChunkSize: 200,
+		})
+		if err != nil {
+			t.Fatalf("Chunk failed: %v", err)
+		}
+		if len(result.Chunks) < 2 {
+			t.Fatalf("expected large function to be split, got %d chunks", len(result.Chunks))
+		}
+		for _, chunk := range result.Chunks {
+			if chunk.Metadata.Code.FunctionName != "big" {
+				t.Errorf("expected split chunks to keep function name, got %q", chunk.Metadata.Code.FunctionName)
+			}
+		}
+	})
+}
+
+func TestSwiftStrategy(t *testing.T) {
+	strategy := languages.NewSwiftStrategy()
+	c := code.NewTreeSitterChunker()
+	c.RegisterStrategy(strategy)
+
+	for _, tt := range []struct{ mime, lang string }{
+		{"text/x-swift", ""},
+		{"", "swift"},
+		{"", ".swift"},
+	} {
+		if !c.CanHandle(tt.mime, tt.lang) {
+			t.Errorf("CanHandle(%q, %q) = false, want true", tt.mime, tt.lang)
+		}
+	}
+
+	source := []byte(`import Foundation
+
+/// A registered user.
+public final class User: Base, Codable {
+    /// Loads a user.
+    @discardableResult
+    public static func load(id: Int, from store: Store = .shared) async throws -> User? {
+        return nil
+    }
+}
+
+struct Point { let x: Int }
+
+protocol Named { func name() -> String }
+
+extension User: Named {
+    fileprivate func name() -> String { "x" }
+}
+
+func helper(_ x: Int, values: String...) -> Int { x }
+`)
+
+	t.Run("TypesAndExtensions", func(t *testing.T) {
+		result, err := c.Chunk(context.Background(), source, chunkers.ChunkOptions{
+			Language: "swift",
+		})
+		if err != nil {
+			t.Fatalf("Chunk failed: %v", err)
+		}
+		for _, w := range result.Warnings {
+			if w.Code == "PARSE_ERROR" {
+				t.Errorf("unexpected parse error warning: %s", w.Message)
+			}
+		}
+
+		bySignature := make(map[string]*chunkers.CodeMetadata)
+		for i := range result.Chunks {
+			if meta := result.Chunks[i].Metadata.Code; meta != nil && meta.Signature != "" {
+				bySignature[meta.Signature] = meta
+			}
+		}
+		for _, sig := range []string{"class User", "struct Point", "protocol Named", "extension User", "func helper(_ x: Int, values: String...) -> Int"} {
+			if bySignature[sig] == nil {
+				t.Errorf("expected chunk with signature %q", sig)
+			}
+		}
+
+		user := bySignature["class User"]
+		if user == nil {
+			t.Fatal("expected User class chunk")
+		}
+		if user.ClassName != "User" || user.ParentClass != "Base" {
+			t.Errorf("expected User extending Base, got %q extending %q", user.ClassName, user.ParentClass)
+		}
+		if len(user.Implements) != 1 || user.Implements[0] != "Codable" {
+			t.Errorf("expected implements [Codable], got %v", user.Implements)
+		}
+		if user.Visibility != "public" || !user.IsExported {
+			t.Errorf("expected exported public class, got visibility %q exported %v", user.Visibility, user.IsExported)
+		}
+		if user.Docstring != "A registered user." {
+			t.Errorf("expected doc comment, got %q", user.Docstring)
+		}
+
+		extension := bySignature["extension User"]
+		if extension != nil && (extension.ClassName != "User" || extension.ParentClass != "" || len(extension.Implements) != 1) {
+			t.Errorf("expected extension of User conforming to Named, got %+v", extension)
+		}
+		if point := bySignature["struct Point"]; point != nil && (point.Visibility != "internal" || point.IsExported) {
+			t.Errorf("expected internal struct, got visibility %q", point.Visibility)
+		}
+	})
+
+	t.Run("FunctionMetadata", func(t *testing.T) {
+		meta := strategy.ExtractMetadata(parseFirstNode(t, strategy, source, "function_declaration"), source)
+		if meta.FunctionName != "load" || meta.ClassName != "User" {
+			t.Errorf("expected User.load, got %s.%s", meta.ClassName, meta.FunctionName)
+		}
+		if meta.ReturnType != "User?" {
+			t.Errorf("expected return type 'User?', got %q", meta.ReturnType)
+		}
+		if !meta.IsAsync || !meta.IsStatic {
+			t.Errorf("expected async static function, got async %v static %v", meta.IsAsync, meta.IsStatic)
+		}
+		if len(meta.Decorators) != 1 || meta.Decorators[0] != "discardableResult" {
+			t.Errorf("expected decorators [discardableResult], got %v", meta.Decorators)
+		}
+		if meta.Docstring != "Loads a user." {
+			t.Errorf("expected doc comment, got %q", meta.Docstring)
+		}
+		if meta.Signature != "public static func load(id: Int, from store: Store) async throws -> User?" {
+			t.Errorf("unexpected signature %q", meta.Signature)
+		}
+
+		body := parseFirstNode(t, strategy, source, "class_declaration").NextNamedSibling().NextNamedSibling().NextNamedSibling().ChildByFieldName("body")
+		name := strategy.ExtractMetadata(body.NamedChild(0), source)
+		if name.FunctionName != "name" || name.ClassName != "User" {
+			t.Errorf("expected extension method User.name, got %s.%s", name.ClassName, name.FunctionName)
+		}
+		if name.Visibility != "fileprivate" || name.IsExported {
+			t.Errorf("expected fileprivate method, got visibility %q exported %v", name.Visibility, name.IsExported)
+		}
+	})
+}
+
+func TestScalaStrategy(t *testing.T) {
+	strategy := languages.NewScalaStrategy()
+	c := code.NewTreeSitterChunker()
+	c.RegisterStrategy(strategy)
+
+	for _, tt := range []struct{ mime, lang string }{
+		{"text/x-scala", ""},
+		{"", "scala"},
+		{"", ".scala"},
+		{"", "sc"},
+	} {
+		if !c.CanHandle(tt.mime, tt.lang) {
+			t.Errorf("CanHandle(%q, %q) = false, want true", tt.mime, tt.lang)
+		}
+	}
+
+	source := []byte(`package com.example.users
+
+import scala.concurrent.Future
+
+/** A registered user. */
+case class User(name: String, age: Int = 0) extends Base with Named
+
+object User {
+  def apply(name: String): User = new User(name)
+}
+
+sealed trait Shape {
+  def area: Double
+}
+
+private class Repo[T](db: Db) extends Store[T] {
+  protected def find(id: Int, opts: String*)(implicit ec: ExecutionContext): Future[Option[T]] = ???
+}
+`)
+
+	t.Run("CaseClassAndCompanion", func(t *testing.T) {
+		result, err := c.Chunk(context.Background(), source, chunkers.ChunkOptions{
+			Language: "scala",
+		})
+		if err != nil {
+			t.Fatalf("Chunk failed: %v", err)
+		}
+		for _, w := range result.Warnings {
+			if w.Code == "PARSE_ERROR" {
+				t.Errorf("unexpected parse error warning: %s", w.Message)
+			}
+		}
+
+		bySignature := make(map[string]*chunkers.CodeMetadata)
+		for i := range result.Chunks {
+			if meta := result.Chunks[i].Metadata.Code; meta != nil && meta.Signature != "" {
+				bySignature[meta.Signature] = meta
+			}
+		}
+
+		user := bySignature["case class User"]
+		if user == nil {
+			t.Fatal("expected case class User chunk")
+		}
+		if user.ParentClass != "Base" || len(user.Implements) != 1 || user.Implements[0] != "Named" {
+			t.Errorf("expected User extends Base with Named, got parent %q implements %v", user.ParentClass, user.Implements)
+		}
+		if user.Docstring != "A registered user." {
+			t.Errorf("expected Scaladoc docstring, got %q", user.Docstring)
+		}
+
+		companion := bySignature["object User"]
+		if companion == nil {
+			t.Fatal("expected companion object chunk")
+		}
+		if companion.ClassName != "User" || companion.Namespace != "com.example.users" {
+			t.Errorf("expected companion User in com.example.users, got %q in %q", companion.ClassName, companion.Namespace)
+		}
+		if bySignature["trait Shape"] == nil {
+			t.Error("expected trait Shape chunk")
+		}
+		repo := bySignature["class Repo"]
+		if repo == nil {
+			t.Fatal("expected class Repo chunk")
+		}
+		if repo.Visibility != "private" || repo.IsExported || repo.ParentClass != "Store[T]" {
+			t.Errorf("unexpected Repo metadata: visibility %q parent %q", repo.Visibility, repo.ParentClass)
+		}
+	})
+
+	t.Run("FunctionMetadata", func(t *testing.T) {
+		meta := strategy.ExtractMetadata(parseFirstNode(t, strategy, source, "function_definition"), source)
+		if meta.FunctionName != "apply" || meta.ClassName != "User" || meta.ReturnType != "User" {
+			t.Errorf("expected User.apply returning User, got %s.%s: %q", meta.ClassName, meta.FunctionName, meta.ReturnType)
+		}
+
+		body := parseFirstNode(t, strategy, source, "trait_definition").NextNamedSibling().ChildByFieldName("body")
+		find := strategy.ExtractMetadata(body.NamedChild(0), source)
+		if strings.Join(find.Parameters, ", ") != "id: Int, opts: String*, ec: ExecutionContext" {
+			t.Errorf("unexpected parameters %v", find.Parameters)
+		}
+		if find.Visibility != "protected" || find.IsExported {
+			t.Errorf("expected protected function, got visibility %q exported %v", find.Visibility, find.IsExported)
+		}
+		if find.Signature != "protected def find(id: Int, opts: String*)(implicit ec: ExecutionContext): Future[Option[T]]" {
+			t.Errorf("unexpected signature %q", find.Signature)
+		}
+	})
+
+	t.Run("PackageBlocks", func(t *testing.T) {
+		source := []byte("package com\npackage example {\n  object Tools {\n    def run(): Unit = ()\n  }\n}\n")
+		result, err := c.Chunk(context.Background(), source, chunkers.ChunkOptions{
+			Language: "scala",
+		})
+		if err != nil {
+			t.Fatalf("Chunk failed: %v", err)
+		}
+		var tools *chunkers.CodeMetadata
+		for i := range result.Chunks {
+			if meta := result.Chunks[i].Metadata.Code; meta != nil && meta.ClassName == "Tools" {
+				tools = meta
+			}
+		}
+		if tools == nil {
+			t.Fatal("expected Tools object chunk inside the package block")
+		}
+		if tools.Namespace != "com.example" {
+			t.Errorf("expected namespace 'com.example', got %q", tools.Namespace)
+		}
+	})
+}
+
+func TestLuaStrategy(t *testing.T) {
+	strategy := languages.NewLuaStrategy()
+	c := code.NewTreeSitterChunker()
+	c.RegisterStrategy(strategy)
+
+	for _, tt := range []struct{ mime, lang string }{
+		{"text/x-lua", ""},
+		{"", "lua"},
+		{"", ".lua"},
+	} {
+		if !c.CanHandle(tt.mime, tt.lang) {
+			t.Errorf("CanHandle(%q, %q) = false, want true", tt.mime, tt.lang)
+		}
+	}
+
+	source := []byte(`local json = require("json")
+local util = require "util"
+
+local M = {}
+
+--- Adds numbers.
+-- Returns the sum.
+function M.add(a, b, ...)
+  return a + b
+end
+
+function Account:deposit(v)
+  self.balance = self.balance + v
+end
+
+local function helper(x)
+  return x
+end
+
+M.handler = function(evt) end
+
+return M
+`)
+
+	result, err := c.Chunk(context.Background(), source, chunkers.ChunkOptions{
+		Language: "lua",
+	})
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	for _, w := range result.Warnings {
+		if w.Code == "PARSE_ERROR" {
+			t.Errorf("unexpected parse error warning: %s", w.Message)
+		}
+	}
+	if len(result.Chunks) != 5 {
+		t.Fatalf("expected header and four function chunks, got %d", len(result.Chunks))
+	}
+
+	header := result.Chunks[0].Content
+	if !strings.Contains(header, `require("json")`) || !strings.HasSuffix(header, `require "util"`) {
+		t.Errorf("expected header with require calls, got %q", header)
+	}
+
+	add := result.Chunks[1].Metadata.Code
+	if add.FunctionName != "add" || add.Namespace != "M" || add.ClassName != "" {
+		t.Errorf("expected M.add, got namespace %q class %q function %q", add.Namespace, add.ClassName, add.FunctionName)
+	}
+	if strings.Join(add.Parameters, ", ") != "a, b, ..." || add.Signature != "function M.add(a, b, ...)" {
+		t.Errorf("unexpected add parameters %v signature %q", add.Parameters, add.Signature)
+	}
+	if add.Docstring != "Adds numbers. Returns the sum." {
+		t.Errorf("expected documentation comments, got %q", add.Docstring)
+	}
+
+	deposit := result.Chunks[2].Metadata.Code
+	if deposit.FunctionName != "deposit" || deposit.ClassName != "Account" {
+		t.Errorf("expected Account:deposit, got class %q function %q", deposit.ClassName, deposit.FunctionName)
+	}
+
+	helper := result.Chunks[3].Metadata.Code
+	if helper.FunctionName != "helper" || helper.Visibility != "file" || helper.IsExported {
+		t.Errorf("expected file-local helper, got %q visibility %q exported %v", helper.FunctionName, helper.Visibility, helper.IsExported)
+	}
+	if helper.Signature != "local function helper(x)" {
+		t.Errorf("unexpected helper signature %q", helper.Signature)
+	}
+
+	handler := result.Chunks[4].Metadata.Code
+	if handler.FunctionName != "handler" || handler.Namespace != "M" || !handler.IsExported {
+		t.Errorf("expected exported M.handler, got namespace %q function %q", handler.Namespace, handler.FunctionName)
+	}
+	if !strings.HasPrefix(result.Chunks[4].Content, "M.handler = function") {
+		t.Errorf("expected assigned function chunk to include its assignment, got %q", result.Chunks[4].Content)
+	}
+}
+
 func TestChunkSplitting(t *testing.T) {
 	strategy := languages.NewGoStrategy()
 	c := code.NewTreeSitterChunker()
@@ -739,13 +1864,92 @@ func TestChunkSplitting(t *testing.T) {
 			t.Fatalf("Chunk failed: %v", err)
 		}
 
-		// Should have multiple chunks due to size limit
-		if len(result.Chunks) < 2 {
-			t.Errorf("expected multiple chunks due to large function, got %d", len(result.Chunks))
+		// Should have multiple chunks due to size limit
+		if len(result.Chunks) < 2 {
+			t.Errorf("expected multiple chunks due to large function, got %d", len(result.Chunks))
+		}
+	})
+
+	t.Run("OverlapWithinSplitNode", func(t *testing.T) {
+		var builder strings.Builder
+		builder.WriteString("package main\n\n")
+		builder.WriteString("func bigFunction() {\n")
+		for i := 0; i < 40; i++ {
+			builder.WriteString("\tvalue := compute(value) // step\n")
+		}
+		builder.WriteString("}\n\nfunc small() {}\n")
+		source := builder.String()
+
+		overlap := 25
+		result, err := c.Chunk(context.Background(), []byte(source), chunkers.ChunkOptions{
+			Language:     "go",
+			MaxChunkSize: 300,
+			Overlap:      overlap,
+		})
+		if err != nil {
+			t.Fatalf("Chunk failed: %v", err)
+		}
+
+		var parts []chunkers.Chunk
+		for _, chunk := range result.Chunks {
+			if chunk.Metadata.Code != nil && chunk.Metadata.Code.FunctionName == "bigFunction" {
+				parts = append(parts, chunk)
+			}
+		}
+		if len(parts) < 3 {
+			t.Fatalf("expected bigFunction to split, got %d parts", len(parts))
+		}
+		for i := 1; i < len(parts); i++ {
+			prev, next := parts[i-1], parts[i]
+			shared := prev.Content[len(prev.Content)-overlap:]
+			if !strings.HasPrefix(next.Content, shared) {
+				t.Errorf("part %d does not start with the overlap %q", i, shared)
+			}
+			if next.StartOffset != prev.EndOffset-overlap {
+				t.Errorf("part %d starts at %d, want %d", i, next.StartOffset, prev.EndOffset-overlap)
+			}
+		}
+
+		// No overlap across functions
+		last := result.Chunks[len(result.Chunks)-1]
+		if last.Content != "func small() {}" {
+			t.Errorf("last chunk = %q, want the small function alone", last.Content)
 		}
 	})
 }
 
+func TestBoundaryConfidence(t *testing.T) {
+	c := code.NewTreeSitterChunker()
+	c.RegisterStrategy(languages.NewGoStrategy())
+
+	source := "package main\n\nimport \"fmt\"\n\nfunc hello() {\n\tfmt.Println(\"hi\")\n}\n\ntype Greeter struct{}\n"
+	result, err := c.Chunk(context.Background(), []byte(source), chunkers.ChunkOptions{Language: "go"})
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if len(result.Chunks) == 0 {
+		t.Fatal("expected chunks")
+	}
+
+	fallback, err := chunkers.NewFallbackChunker().Chunk(context.Background(), []byte(source), chunkers.DefaultChunkOptions())
+	if err != nil {
+		t.Fatalf("fallback Chunk failed: %v", err)
+	}
+
+	for _, chunk := range result.Chunks {
+		if chunk.Metadata.BoundaryConfidence != chunkers.BoundaryConfidenceStructural {
+			t.Errorf("chunk %d BoundaryConfidence = %v, want %v",
+				chunk.Index, chunk.Metadata.BoundaryConfidence, chunkers.BoundaryConfidenceStructural)
+		}
+		for _, fb := range fallback.Chunks {
+			if fb.Metadata.BoundaryConfidence >= chunk.Metadata.BoundaryConfidence {
+				t.Errorf("fallback confidence %v should be below tree-sitter confidence %v",
+					fb.Metadata.BoundaryConfidence, chunk.Metadata.BoundaryConfidence)
+			}
+		}
+	}
+}
+
 func TestEmptyContent(t *testing.T) {
 	strategy := languages.NewGoStrategy()
 	c := code.NewTreeSitterChunker()
@@ -1117,6 +2321,20 @@ func TestNodeTypeConfig(t *testing.T) {
 	})
 }
 
+func TestStrategyRegistryExtensionForms(t *testing.T) {
+	c := code.NewTreeSitterChunker()
+	c.RegisterStrategy(languages.NewGoStrategy())
+
+	for _, lang := range []string{".GO", "go", ".go", "GO", "cmd/main.go"} {
+		if !c.CanHandle("", lang) {
+			t.Errorf("CanHandle(%q) = false, want true", lang)
+		}
+	}
+	if c.CanHandle("", ".rs") {
+		t.Error("CanHandle(\".rs\") = true, want false")
+	}
+}
+
 func TestConcurrentRegistryAccess(t *testing.T) {
 	c := code.NewTreeSitterChunker()
 
@@ -2015,3 +3233,504 @@ func Greet(name string) string {
 		t.Error("expected Unicode content to be preserved")
 	}
 }
+
+func TestTestFunctionDetection(t *testing.T) {
+	// findByName returns the code metadata for the chunk with the given function name.
+	findByName := func(t *testing.T, result *chunkers.ChunkResult, name string) *chunkers.CodeMetadata {
+		t.Helper()
+		for i := range result.Chunks {
+			if meta := result.Chunks[i].Metadata.Code; meta != nil && meta.FunctionName == name {
+				return meta
+			}
+		}
+		t.Fatalf("chunk for %q not found", name)
+		return nil
+	}
+
+	t.Run("GoTestFunctions", func(t *testing.T) {
+		c := code.NewTreeSitterChunker()
+		c.RegisterStrategy(languages.NewGoStrategy())
+
+		source := `package calc
+
+import "testing"
+
+func Add(a, b int) int {
+	return a + b
+}
+
+func TestFoo(t *testing.T) {
+	if Add(1, 2) != 3 {
+		t.Fail()
+	}
+}
+
+func BenchmarkAdd(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Add(1, 2)
+	}
+}
+
+func ExampleAdd() {
+	Add(1, 2)
+}
+
+func Testimony(t *testing.T) {}
+
+func TestHelper(x int) {}
+`
+		result, err := c.Chunk(context.Background(), []byte(source), chunkers.ChunkOptions{Language: "go"})
+		if err != nil {
+			t.Fatalf("Chunk failed: %v", err)
+		}
+
+		tests := []struct {
+			name   string
+			isTest bool
+		}{
+			{"Add", false},
+			{"TestFoo", true},
+			{"BenchmarkAdd", true},
+			{"ExampleAdd", true},
+			{"Testimony", false},
+			{"TestHelper", false},
+		}
+		for _, tt := range tests {
+			if got := findByName(t, result, tt.name).IsTest; got != tt.isTest {
+				t.Errorf("%s IsTest = %v, want %v", tt.name, got, tt.isTest)
+			}
+		}
+	})
+
+	t.Run("PythonTestFunctions", func(t *testing.T) {
+		c := code.NewTreeSitterChunker()
+		c.RegisterStrategy(languages.NewPythonStrategy())
+
+		source := `import unittest
+
+def bar():
+    return 1
+
+def test_bar():
+    assert bar() == 1
+
+class CalculatorTest(unittest.TestCase):
+    def test_add(self):
+        self.assertEqual(bar(), 1)
+`
+		result, err := c.Chunk(context.Background(), []byte(source), chunkers.ChunkOptions{Language: "python"})
+		if err != nil {
+			t.Fatalf("Chunk failed: %v", err)
+		}
+
+		if findByName(t, result, "bar").IsTest {
+			t.Error("bar should not be flagged as a test")
+		}
+		if !findByName(t, result, "test_bar").IsTest {
+			t.Error("test_bar should be flagged as a test")
+		}
+
+		var testCase *chunkers.CodeMetadata
+		for i := range result.Chunks {
+			if meta := result.Chunks[i].Metadata.Code; meta != nil && meta.ClassName == "CalculatorTest" {
+				testCase = meta
+				break
+			}
+		}
+		if testCase == nil {
+			t.Fatal("CalculatorTest class chunk not found")
+		}
+		if !testCase.IsTest {
+			t.Error("unittest.TestCase subclass should be flagged as a test")
+		}
+	})
+
+	t.Run("JavaScriptTestBlocks", func(t *testing.T) {
+		c := code.NewTreeSitterChunker()
+		c.RegisterStrategy(languages.NewJavaScriptStrategy())
+
+		source := `function sum(a, b) {
+    return a + b;
+}
+
+describe("sum", () => {
+    it("adds numbers", () => {
+        expect(sum(1, 2)).toBe(3);
+    });
+});
+`
+		result, err := c.Chunk(context.Background(), []byte(source), chunkers.ChunkOptions{Language: "javascript"})
+		if err != nil {
+			t.Fatalf("Chunk failed: %v", err)
+		}
+
+		if findByName(t, result, "sum").IsTest {
+			t.Error("sum should not be flagged as a test")
+		}
+		describe := findByName(t, result, "describe")
+		if !describe.IsTest {
+			t.Error("describe block should be flagged as a test")
+		}
+		if describe.Signature != `describe("sum")` {
+			t.Errorf("Signature = %q, want %q", describe.Signature, `describe("sum")`)
+		}
+	})
+}
+
+func TestConstructorDetection(t *testing.T) {
+	t.Run("GoFactoryFunctions", func(t *testing.T) {
+		c := code.NewTreeSitterChunker()
+		c.RegisterStrategy(languages.NewGoStrategy())
+
+		source := `package server
+
+import "net/http"
+
+type Server struct{}
+
+func NewServer(addr string) (*Server, error) {
+	return &Server{}, nil
+}
+
+func NewHandler() http.Handler {
+	return nil
+}
+
+func NewID() string {
+	return ""
+}
+
+func Newline() *Server {
+	return nil
+}
+
+func (s *Server) NewSession() *Server {
+	return s
+}
+`
+		result, err := c.Chunk(context.Background(), []byte(source), chunkers.ChunkOptions{Language: "go"})
+		if err != nil {
+			t.Fatalf("Chunk failed: %v", err)
+		}
+
+		tests := []struct {
+			name          string
+			isConstructor bool
+		}{
+			{"NewServer", true},
+			{"NewHandler", false},
+			{"NewID", false},
+			{"Newline", false},
+			{"NewSession", false},
+		}
+		for _, tt := range tests {
+			var meta *chunkers.CodeMetadata
+			for i := range result.Chunks {
+				if m := result.Chunks[i].Metadata.Code; m != nil && m.FunctionName == tt.name {
+					meta = m
+					break
+				}
+			}
+			if meta == nil {
+				t.Fatalf("chunk for %q not found", tt.name)
+			}
+			if meta.IsConstructor != tt.isConstructor {
+				t.Errorf("%s IsConstructor = %v, want %v", tt.name, meta.IsConstructor, tt.isConstructor)
+			}
+		}
+	})
+
+	t.Run("RustAssociatedNew", func(t *testing.T) {
+		strategy := languages.NewRustStrategy()
+		source := []byte(`impl Foo {
+    fn new() -> Self {
+        Foo {}
+    }
+}
+`)
+		meta := strategy.ExtractMetadata(parseFirstNode(t, strategy, source, "function_item"), source)
+		if meta.FunctionName != "new" {
+			t.Fatalf("FunctionName = %q, want %q", meta.FunctionName, "new")
+		}
+		if !meta.IsConstructor {
+			t.Error("fn new in impl block should be flagged as a constructor")
+		}
+
+		free := []byte("fn new() -> Foo { Foo {} }\n")
+		if strategy.ExtractMetadata(parseFirstNode(t, strategy, free, "function_item"), free).IsConstructor {
+			t.Error("free fn new should not be flagged as a constructor")
+		}
+	})
+
+	t.Run("PythonInit", func(t *testing.T) {
+		strategy := languages.NewPythonStrategy()
+		source := []byte(`class Foo:
+    def __init__(self, x):
+        self.x = x
+`)
+		meta := strategy.ExtractMetadata(parseFirstNode(t, strategy, source, "function_definition"), source)
+		if meta.FunctionName != "__init__" {
+			t.Fatalf("FunctionName = %q, want %q", meta.FunctionName, "__init__")
+		}
+		if !meta.IsConstructor {
+			t.Error("__init__ should be flagged as a constructor")
+		}
+	})
+}
+
+func TestExportedOnly(t *testing.T) {
+	c := code.NewTreeSitterChunker()
+	c.RegisterStrategy(languages.NewGoStrategy())
+
+	source := `package api
+
+import "strings"
+
+// Normalize trims and lowercases input.
+func Normalize(s string) string {
+	return strings.ToLower(trim(s))
+}
+
+func trim(s string) string {
+	return strings.TrimSpace(s)
+}
+
+type Client struct{}
+
+type config struct{}
+
+func (c *Client) Do() error {
+	return nil
+}
+
+func (c *Client) retry() {}
+`
+	result, err := c.Chunk(context.Background(), []byte(source), chunkers.ChunkOptions{
+		Language:     "go",
+		ExportedOnly: true,
+	})
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+
+	var names []string
+	for i, chunk := range result.Chunks {
+		if chunk.Index != i {
+			t.Errorf("chunk %d has index %d; indexes should stay contiguous", i, chunk.Index)
+		}
+		if !strings.Contains(source[chunk.StartOffset:chunk.EndOffset], strings.TrimSpace(chunk.Content)) {
+			t.Errorf("chunk %d offsets [%d:%d] do not cover its content", i, chunk.StartOffset, chunk.EndOffset)
+		}
+		meta := chunk.Metadata.Code
+		if meta.FunctionName == "" && meta.ClassName == "" {
+			continue // header
+		}
+		if !meta.IsExported {
+			t.Errorf("unexported declaration %q/%q emitted", meta.FunctionName, meta.ClassName)
+		}
+		names = append(names, meta.FunctionName+meta.ClassName)
+	}
+
+	want := []string{"Normalize", "Client", "DoClient"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("exported chunks = %v, want %v", names, want)
+	}
+	if !strings.Contains(result.Chunks[0].Content, "package api") {
+		t.Error("expected header chunk to be kept")
+	}
+
+	all, err := c.Chunk(context.Background(), []byte(source), chunkers.ChunkOptions{Language: "go"})
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if len(all.Chunks) <= len(result.Chunks) {
+		t.Errorf("expected more chunks without ExportedOnly, got %d vs %d", len(all.Chunks), len(result.Chunks))
+	}
+}
+
+func TestRaisesExtraction(t *testing.T) {
+	// findByName returns the code metadata for the chunk with the given function name.
+	findByName := func(t *testing.T, result *chunkers.ChunkResult, name string) *chunkers.CodeMetadata {
+		t.Helper()
+		for i := range result.Chunks {
+			if meta := result.Chunks[i].Metadata.Code; meta != nil && meta.FunctionName == name {
+				return meta
+			}
+		}
+		t.Fatalf("chunk for %q not found", name)
+		return nil
+	}
+
+	// assertRaises checks raises against want, ignoring order.
+	assertRaises := func(t *testing.T, name string, raises []string, want ...string) {
+		t.Helper()
+		if len(raises) != len(want) {
+			t.Fatalf("%s Raises = %v, want %v", name, raises, want)
+		}
+		for _, w := range want {
+			found := false
+			for _, r := range raises {
+				if r == w {
+					found = true
+					break
+				}
+			}
+			if !found {
+				t.Errorf("%s Raises = %v, missing %q", name, raises, w)
+			}
+		}
+	}
+
+	t.Run("PythonRaise", func(t *testing.T) {
+		c := code.NewTreeSitterChunker()
+		c.RegisterStrategy(languages.NewPythonStrategy())
+
+		source := `def parse(value):
+    """Parse a value.
+
+    Raises:
+        ValueError: if the value is empty.
+        KeyError: if the value is unknown.
+            Continuation: not an entry.
+    """
+    if not value:
+        raise ValueError("empty value")
+    try:
+        return lookup(value)
+    except LookupError:
+        raise
+
+def inner_only():
+    def helper():
+        raise TypeError("nested")
+    return helper
+`
+		result, err := c.Chunk(context.Background(), []byte(source), chunkers.ChunkOptions{Language: "python"})
+		if err != nil {
+			t.Fatalf("Chunk failed: %v", err)
+		}
+
+		assertRaises(t, "parse", findByName(t, result, "parse").Raises, "ValueError", "KeyError")
+		assertRaises(t, "inner_only", findByName(t, result, "inner_only").Raises)
+	})
+
+	t.Run("JavaThrows", func(t *testing.T) {
+		strategy := languages.NewJavaStrategy()
+
+		source := []byte(`public class Loader {
+    public String read(String path) throws IOException {
+        return Files.readString(Path.of(path));
+    }
+}
+`)
+		// Methods are chunked with their class, so extract the method metadata directly
+		parser := sitter.NewParser()
+		defer parser.Close()
+		parser.SetLanguage(strategy.GetLanguage())
+		tree, err := parser.ParseCtx(context.Background(), nil, source)
+		if err != nil {
+			t.Fatalf("parse failed: %v", err)
+		}
+		defer tree.Close()
+
+		var method *sitter.Node
+		var find func(n *sitter.Node)
+		find = func(n *sitter.Node) {
+			if method != nil {
+				return
+			}
+			if n.Type() == "method_declaration" {
+				method = n
+				return
+			}
+			for i := 0; i < int(n.NamedChildCount()); i++ {
+				find(n.NamedChild(i))
+			}
+		}
+		find(tree.RootNode())
+		if method == nil {
+			t.Fatal("method_declaration not found")
+		}
+
+		meta := strategy.ExtractMetadata(method, source)
+		if meta.FunctionName != "read" {
+			t.Fatalf("FunctionName = %q, want %q", meta.FunctionName, "read")
+		}
+		assertRaises(t, "read", meta.Raises, "IOException")
+	})
+
+	t.Run("GoErrorsAndPanics", func(t *testing.T) {
+		c := code.NewTreeSitterChunker()
+		c.RegisterStrategy(languages.NewGoStrategy())
+
+		source := `package config
+
+func Load(path string) (*Config, error) {
+	if path == "" {
+		panic("empty path")
+	}
+	return nil, nil
+}
+`
+		result, err := c.Chunk(context.Background(), []byte(source), chunkers.ChunkOptions{Language: "go"})
+		if err != nil {
+			t.Fatalf("Chunk failed: %v", err)
+		}
+
+		assertRaises(t, "Load", findByName(t, result, "Load").Raises, "error", "panic")
+	})
+
+	t.Run("JavaScriptThrow", func(t *testing.T) {
+		c := code.NewTreeSitterChunker()
+		c.RegisterStrategy(languages.NewJavaScriptStrategy())
+
+		source := `function validate(input) {
+    if (!input) {
+        throw new ValidationError("missing input");
+    }
+    return input;
+}
+`
+		result, err := c.Chunk(context.Background(), []byte(source), chunkers.ChunkOptions{Language: "javascript"})
+		if err != nil {
+			t.Fatalf("Chunk failed: %v", err)
+		}
+
+		assertRaises(t, "validate", findByName(t, result, "validate").Raises, "ValidationError")
+	})
+}
+
+// parseFirstNode parses source with the strategy's grammar and returns the first
+// node of the given type in depth-first order.
+func parseFirstNode(t *testing.T, strategy code.LanguageStrategy, source []byte, nodeType string) *sitter.Node {
+	t.Helper()
+
+	parser := sitter.NewParser()
+	t.Cleanup(parser.Close)
+	parser.SetLanguage(strategy.GetLanguage())
+	tree, err := parser.ParseCtx(context.Background(), nil, source)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	t.Cleanup(tree.Close)
+
+	var found *sitter.Node
+	var find func(n *sitter.Node)
+	find = func(n *sitter.Node) {
+		if found != nil {
+			return
+		}
+		if n.Type() == nodeType {
+			found = n
+			return
+		}
+		for i := 0; i < int(n.NamedChildCount()); i++ {
+			find(n.NamedChild(i))
+		}
+	}
+	find(tree.RootNode())
+	if found == nil {
+		t.Fatalf("%s not found", nodeType)
+	}
+	return found
+}
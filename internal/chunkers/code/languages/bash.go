@@ -0,0 +1,152 @@
+package languages
+
+import (
+	"slices"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/bash"
+
+	"github.com/leefowlercu/agentic-memorizer/internal/chunkers"
+	"github.com/leefowlercu/agentic-memorizer/internal/chunkers/code"
+)
+
+// bashSetupCommands are top-level commands that configure the shell and are
+// kept in the header alongside variable assignments.
+var bashSetupCommands = []string{"set", "shopt", "source", ".", "trap"}
+
+// BashStrategy implements tree-sitter parsing for Bash and POSIX shell
+// scripts.
+type BashStrategy struct{}
+
+// NewBashStrategy creates a new Bash language strategy.
+func NewBashStrategy() *BashStrategy {
+	return &BashStrategy{}
+}
+
+// Language returns the language identifier.
+func (s *BashStrategy) Language() string {
+	return "bash"
+}
+
+// Extensions returns file extensions this strategy handles.
+func (s *BashStrategy) Extensions() []string {
+	return []string{".sh", ".bash"}
+}
+
+// MIMETypes returns MIME types this strategy handles.
+func (s *BashStrategy) MIMETypes() []string {
+	return []string{
+		"text/x-shellscript",
+		"application/x-sh",
+	}
+}
+
+// GetLanguage returns the tree-sitter Language for Bash.
+func (s *BashStrategy) GetLanguage() *sitter.Language {
+	return bash.GetLanguage()
+}
+
+// NodeTypes returns Bash-specific node type configuration.
+func (s *BashStrategy) NodeTypes() code.NodeTypeConfig {
+	return code.NodeTypeConfig{
+		Functions: []string{
+			"function_definition",
+		},
+		Methods:      []string{},
+		Classes:      []string{},
+		Declarations: []string{},
+		TopLevel:     []string{},
+	}
+}
+
+// ShouldChunk determines if a node should be its own chunk. Functions nested
+// in other functions are never reached, so every function found is chunked.
+func (s *BashStrategy) ShouldChunk(node *sitter.Node) bool {
+	return node.Type() == "function_definition"
+}
+
+// IsHeaderNode reports whether a top-level node belongs to the script header:
+// the shebang and leading comments, variable assignments, and shell setup
+// commands such as set -euo pipefail. A comment documenting the function that
+// follows it is left out of the header.
+func (s *BashStrategy) IsHeaderNode(node *sitter.Node, source []byte) bool {
+	switch node.Type() {
+	case "comment":
+		text := string(source[node.StartByte():node.EndByte()])
+		return strings.HasPrefix(text, "#!") || !s.documentsFunction(node)
+	case "variable_assignment", "declaration_command":
+		return true
+	case "command":
+		if name := node.ChildByFieldName("name"); name != nil {
+			return slices.Contains(bashSetupCommands, string(source[name.StartByte():name.EndByte()]))
+		}
+	}
+	return false
+}
+
+// ExtractMetadata extracts Bash-specific metadata from an AST node.
+func (s *BashStrategy) ExtractMetadata(node *sitter.Node, source []byte) *chunkers.CodeMetadata {
+	meta := &chunkers.CodeMetadata{
+		Language:  "bash",
+		LineStart: int(node.StartPoint().Row) + 1,
+		LineEnd:   int(node.EndPoint().Row) + 1,
+	}
+
+	if node.Type() != "function_definition" {
+		return meta
+	}
+
+	if name := node.ChildByFieldName("name"); name != nil {
+		meta.FunctionName = string(source[name.StartByte():name.EndByte()])
+	}
+	meta.Signature = meta.FunctionName + "()"
+	// Functions prefixed with an underscore are private by convention
+	meta.IsExported = !strings.HasPrefix(meta.FunctionName, "_")
+	meta.Docstring = s.extractComments(node, source)
+
+	return meta
+}
+
+// extractComments extracts the run of comments directly above a function.
+func (s *BashStrategy) extractComments(node *sitter.Node, source []byte) string {
+	var lines []string
+	row := node.StartPoint().Row
+	for prev := node.PrevSibling(); prev != nil && prev.Type() == "comment" && prev.EndPoint().Row+1 == row; prev = prev.PrevSibling() {
+		text := string(source[prev.StartByte():prev.EndByte()])
+		if strings.HasPrefix(text, "#!") {
+			break
+		}
+		if line := strings.TrimSpace(strings.TrimLeft(text, "#")); line != "" {
+			lines = append([]string{line}, lines...)
+		}
+		row = prev.StartPoint().Row
+	}
+	return strings.Join(lines, " ")
+}
+
+// documentsFunction reports whether a comment is part of an unbroken run of
+// comment lines ending directly above a function definition.
+func (s *BashStrategy) documentsFunction(comment *sitter.Node) bool {
+	current := comment
+	for next := current.NextSibling(); next != nil; next = current.NextSibling() {
+		if next.StartPoint().Row != current.EndPoint().Row+1 {
+			return false
+		}
+		switch next.Type() {
+		case "function_definition":
+			return true
+		case "comment":
+			current = next
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// Ensure BashStrategy implements LanguageStrategy and HeaderStrategy.
+var (
+	_ code.LanguageStrategy = (*BashStrategy)(nil)
+	_ code.HeaderStrategy   = (*BashStrategy)(nil)
+)
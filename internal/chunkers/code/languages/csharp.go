@@ -0,0 +1,425 @@
+package languages
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/csharp"
+
+	"github.com/leefowlercu/agentic-memorizer/internal/chunkers"
+	"github.com/leefowlercu/agentic-memorizer/internal/chunkers/code"
+)
+
+// xmlDocTagPattern matches the XML tags used in C# documentation comments.
+var xmlDocTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// csharpTestAttributes are the attributes that mark test methods in NUnit,
+// xUnit and MSTest.
+var csharpTestAttributes = []string{"Test", "TestCase", "Fact", "Theory", "TestMethod"}
+
+// CSharpStrategy implements tree-sitter parsing for C# code.
+type CSharpStrategy struct{}
+
+// NewCSharpStrategy creates a new C# language strategy.
+func NewCSharpStrategy() *CSharpStrategy {
+	return &CSharpStrategy{}
+}
+
+// Language returns the language identifier.
+func (s *CSharpStrategy) Language() string {
+	return "csharp"
+}
+
+// Extensions returns file extensions this strategy handles.
+func (s *CSharpStrategy) Extensions() []string {
+	return []string{".cs"}
+}
+
+// MIMETypes returns MIME types this strategy handles.
+func (s *CSharpStrategy) MIMETypes() []string {
+	return []string{
+		"text/x-csharp",
+	}
+}
+
+// GetLanguage returns the tree-sitter Language for C#.
+func (s *CSharpStrategy) GetLanguage() *sitter.Language {
+	return csharp.GetLanguage()
+}
+
+// NodeTypes returns C#-specific node type configuration.
+func (s *CSharpStrategy) NodeTypes() code.NodeTypeConfig {
+	return code.NodeTypeConfig{
+		Functions: []string{},
+		Methods: []string{
+			"method_declaration",
+			"constructor_declaration",
+		},
+		Classes: []string{
+			"class_declaration",
+			"interface_declaration",
+			"struct_declaration",
+			"enum_declaration",
+		},
+		Declarations: []string{
+			"property_declaration",
+		},
+		TopLevel: []string{},
+	}
+}
+
+// ShouldChunk determines if a node should be its own chunk.
+func (s *CSharpStrategy) ShouldChunk(node *sitter.Node) bool {
+	switch node.Type() {
+	case "class_declaration", "interface_declaration", "struct_declaration", "enum_declaration":
+		return true
+	case "method_declaration", "constructor_declaration", "property_declaration":
+		// Only chunk members of a type body
+		return s.enclosingType(node) != nil
+	}
+	return false
+}
+
+// ExtractMetadata extracts C#-specific metadata from an AST node.
+func (s *CSharpStrategy) ExtractMetadata(node *sitter.Node, source []byte) *chunkers.CodeMetadata {
+	meta := &chunkers.CodeMetadata{
+		Language:  "csharp",
+		LineStart: int(node.StartPoint().Row) + 1,
+		LineEnd:   int(node.EndPoint().Row) + 1,
+	}
+
+	switch node.Type() {
+	case "class_declaration", "interface_declaration", "struct_declaration", "enum_declaration":
+		s.extractTypeMetadata(node, source, meta)
+	case "method_declaration", "constructor_declaration":
+		s.extractMethodMetadata(node, source, meta)
+	case "property_declaration":
+		s.extractPropertyMetadata(node, source, meta)
+	}
+
+	meta.Namespace = s.namespace(node, source)
+	meta.Docstring = s.extractXMLDoc(node, source)
+	meta.Decorators = s.extractAttributes(node, source)
+
+	return meta
+}
+
+// extractTypeMetadata extracts metadata from a class, interface, struct or
+// enum declaration.
+func (s *CSharpStrategy) extractTypeMetadata(node *sitter.Node, source []byte, meta *chunkers.CodeMetadata) {
+	if name := node.ChildByFieldName("name"); name != nil {
+		meta.ClassName = string(source[name.StartByte():name.EndByte()])
+	}
+
+	// The base list cannot distinguish a base class from interfaces, so a
+	// class's first entry is its parent unless it follows the IName
+	// interface convention
+	if bases := s.findChild(node, "base_list"); bases != nil {
+		for i := 0; i < int(bases.NamedChildCount()); i++ {
+			base := bases.NamedChild(i)
+			text := string(source[base.StartByte():base.EndByte()])
+			if i == 0 && node.Type() == "class_declaration" && !isInterfaceName(text) {
+				meta.ParentClass = text
+				continue
+			}
+			meta.Implements = append(meta.Implements, text)
+		}
+	}
+
+	s.extractModifiers(node, source, meta)
+}
+
+// extractMethodMetadata extracts metadata from a method or constructor
+// declaration.
+func (s *CSharpStrategy) extractMethodMetadata(node *sitter.Node, source []byte, meta *chunkers.CodeMetadata) {
+	if class := s.enclosingType(node); class != nil {
+		if name := class.ChildByFieldName("name"); name != nil {
+			meta.ClassName = string(source[name.StartByte():name.EndByte()])
+		}
+	}
+	if name := node.ChildByFieldName("name"); name != nil {
+		meta.FunctionName = string(source[name.StartByte():name.EndByte()])
+	}
+	if returns := node.ChildByFieldName("returns"); returns != nil {
+		meta.ReturnType = string(source[returns.StartByte():returns.EndByte()])
+	}
+	if params := node.ChildByFieldName("parameters"); params != nil {
+		meta.Parameters = s.extractParameters(params, source)
+	}
+
+	meta.IsConstructor = node.Type() == "constructor_declaration"
+	s.extractModifiers(node, source, meta)
+	meta.IsTest = slices.ContainsFunc(s.extractAttributes(node, source), func(name string) bool {
+		return slices.Contains(csharpTestAttributes, name)
+	})
+	meta.Signature = s.buildMethodSignature(meta)
+}
+
+// extractPropertyMetadata extracts metadata from a property declaration.
+func (s *CSharpStrategy) extractPropertyMetadata(node *sitter.Node, source []byte, meta *chunkers.CodeMetadata) {
+	if class := s.enclosingType(node); class != nil {
+		if name := class.ChildByFieldName("name"); name != nil {
+			meta.ClassName = string(source[name.StartByte():name.EndByte()])
+		}
+	}
+	s.extractModifiers(node, source, meta)
+
+	var name string
+	if n := node.ChildByFieldName("name"); n != nil {
+		name = string(source[n.StartByte():n.EndByte()])
+	}
+	if propertyType := node.ChildByFieldName("type"); propertyType != nil {
+		meta.ReturnType = string(source[propertyType.StartByte():propertyType.EndByte()])
+	}
+
+	var accessors []string
+	if list := node.ChildByFieldName("accessors"); list != nil {
+		for i := 0; i < int(list.NamedChildCount()); i++ {
+			accessor := list.NamedChild(i)
+			if accessor.Type() != "accessor_declaration" {
+				continue
+			}
+			if kind := accessor.ChildByFieldName("name"); kind != nil {
+				text := string(source[kind.StartByte():kind.EndByte()])
+				accessors = append(accessors, text+";")
+				switch text {
+				case "get":
+					meta.IsGetter = true
+				case "set", "init":
+					meta.IsSetter = true
+				}
+			}
+		}
+	} else {
+		// Expression-bodied properties are read-only
+		meta.IsGetter = true
+		accessors = append(accessors, "get;")
+	}
+
+	var sig strings.Builder
+	sig.WriteString(meta.Visibility)
+	sig.WriteString(" ")
+	if meta.IsStatic {
+		sig.WriteString("static ")
+	}
+	if meta.ReturnType != "" {
+		sig.WriteString(meta.ReturnType)
+		sig.WriteString(" ")
+	}
+	sig.WriteString(name)
+	sig.WriteString(" { ")
+	sig.WriteString(strings.Join(accessors, " "))
+	sig.WriteString(" }")
+	meta.Signature = sig.String()
+}
+
+// extractModifiers extracts visibility, static and async modifiers. Without
+// an explicit access modifier, interface members are public, other members
+// private, and top-level types internal.
+func (s *CSharpStrategy) extractModifiers(node *sitter.Node, source []byte, meta *chunkers.CodeMetadata) {
+	var access []string
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		if child.Type() != "modifier" {
+			continue
+		}
+		switch text := string(source[child.StartByte():child.EndByte()]); text {
+		case "public", "private", "protected", "internal":
+			access = append(access, text)
+		case "static":
+			meta.IsStatic = true
+		case "async":
+			meta.IsAsync = true
+		}
+	}
+
+	switch {
+	case len(access) > 0:
+		// Combined modifiers such as "protected internal" are kept whole
+		meta.Visibility = strings.Join(access, " ")
+	case s.enclosingType(node) == nil:
+		meta.Visibility = "internal"
+	case s.enclosingType(node).Type() == "interface_declaration":
+		meta.Visibility = "public"
+	default:
+		meta.Visibility = "private"
+	}
+	meta.IsExported = meta.Visibility == "public"
+}
+
+// extractParameters extracts parameters as "modifiers Type name", dropping
+// default values. The grammar flattens a params array into the parameter
+// list itself, so its type and name are read from the list's fields.
+func (s *CSharpStrategy) extractParameters(params *sitter.Node, source []byte) []string {
+	var result []string
+
+	var variadic []string
+	for i := 0; i < int(params.ChildCount()); i++ {
+		child := params.Child(i)
+		switch {
+		case child.Type() == "parameter":
+			var parts []string
+			for j := 0; j < int(child.NamedChildCount()); j++ {
+				if modifier := child.NamedChild(j); modifier.Type() == "modifier" {
+					parts = append(parts, string(source[modifier.StartByte():modifier.EndByte()]))
+				}
+			}
+			if paramType := child.ChildByFieldName("type"); paramType != nil {
+				parts = append(parts, string(source[paramType.StartByte():paramType.EndByte()]))
+			}
+			if name := child.ChildByFieldName("name"); name != nil {
+				parts = append(parts, string(source[name.StartByte():name.EndByte()]))
+			}
+			result = append(result, strings.Join(parts, " "))
+		case child.Type() == "params":
+			variadic = []string{"params"}
+		case variadic != nil && (params.FieldNameForChild(i) == "type" || params.FieldNameForChild(i) == "name"):
+			variadic = append(variadic, string(source[child.StartByte():child.EndByte()]))
+			if params.FieldNameForChild(i) == "name" {
+				result = append(result, strings.Join(variadic, " "))
+				variadic = nil
+			}
+		}
+	}
+
+	return result
+}
+
+// extractAttributes extracts attribute names such as HttpGet from the
+// attribute lists on a declaration.
+func (s *CSharpStrategy) extractAttributes(node *sitter.Node, source []byte) []string {
+	var attributes []string
+
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		list := node.NamedChild(i)
+		if list.Type() != "attribute_list" {
+			continue
+		}
+		for j := 0; j < int(list.NamedChildCount()); j++ {
+			attribute := list.NamedChild(j)
+			if attribute.Type() != "attribute" {
+				continue
+			}
+			if name := attribute.ChildByFieldName("name"); name != nil {
+				attributes = append(attributes, string(source[name.StartByte():name.EndByte()]))
+			}
+		}
+	}
+
+	return attributes
+}
+
+// extractXMLDoc extracts the /// documentation comments preceding a node,
+// with XML tags removed.
+func (s *CSharpStrategy) extractXMLDoc(node *sitter.Node, source []byte) string {
+	var lines []string
+	for prev := node.PrevSibling(); prev != nil && prev.Type() == "comment"; prev = prev.PrevSibling() {
+		comment := string(source[prev.StartByte():prev.EndByte()])
+		if !strings.HasPrefix(comment, "///") {
+			break
+		}
+		line := xmlDocTagPattern.ReplaceAllString(strings.TrimPrefix(comment, "///"), "")
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append([]string{line}, lines...)
+		}
+	}
+	return strings.Join(lines, " ")
+}
+
+// buildMethodSignature builds a method or constructor signature string.
+func (s *CSharpStrategy) buildMethodSignature(meta *chunkers.CodeMetadata) string {
+	var sig strings.Builder
+
+	sig.WriteString(meta.Visibility)
+	sig.WriteString(" ")
+	if meta.IsStatic {
+		sig.WriteString("static ")
+	}
+	if meta.IsAsync {
+		sig.WriteString("async ")
+	}
+	if meta.ReturnType != "" {
+		sig.WriteString(meta.ReturnType)
+		sig.WriteString(" ")
+	}
+	sig.WriteString(meta.FunctionName)
+	sig.WriteString("(")
+	sig.WriteString(strings.Join(meta.Parameters, ", "))
+	sig.WriteString(")")
+
+	return sig.String()
+}
+
+// namespace returns the namespace a node is declared in, joining nested
+// namespace blocks with "." or falling back to a file-scoped namespace.
+func (s *CSharpStrategy) namespace(node *sitter.Node, source []byte) string {
+	var names []string
+	top := node
+	for parent := node.Parent(); parent != nil; parent = parent.Parent() {
+		if parent.Type() == "namespace_declaration" {
+			if name := parent.ChildByFieldName("name"); name != nil {
+				names = append([]string{string(source[name.StartByte():name.EndByte()])}, names...)
+			}
+		}
+		if parent.Type() != "compilation_unit" {
+			top = parent
+		}
+	}
+	if len(names) > 0 {
+		return strings.Join(names, ".")
+	}
+
+	for prev := top.PrevNamedSibling(); prev != nil; prev = prev.PrevNamedSibling() {
+		if prev.Type() != "file_scoped_namespace_declaration" {
+			continue
+		}
+		if name := prev.ChildByFieldName("name"); name != nil {
+			return string(source[name.StartByte():name.EndByte()])
+		}
+	}
+	return ""
+}
+
+// enclosingType returns the type declaration whose body directly contains
+// node, or nil.
+func (s *CSharpStrategy) enclosingType(node *sitter.Node) *sitter.Node {
+	body := node.Parent()
+	if body == nil || body.Type() != "declaration_list" {
+		return nil
+	}
+	parent := body.Parent()
+	if parent == nil {
+		return nil
+	}
+	switch parent.Type() {
+	case "class_declaration", "interface_declaration", "struct_declaration", "record_declaration":
+		return parent
+	}
+	return nil
+}
+
+// findChild finds the first child with the given type.
+func (s *CSharpStrategy) findChild(node *sitter.Node, nodeType string) *sitter.Node {
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		if child.Type() == nodeType {
+			return child
+		}
+	}
+	return nil
+}
+
+// isInterfaceName reports whether a type name follows the .NET IName
+// convention for interfaces, ignoring namespace qualifiers and type arguments.
+func isInterfaceName(name string) bool {
+	if i := strings.Index(name, "<"); i >= 0 {
+		name = name[:i]
+	}
+	name = name[strings.LastIndex(name, ".")+1:]
+	return len(name) > 1 && name[0] == 'I' && name[1] >= 'A' && name[1] <= 'Z'
+}
+
+// Ensure CSharpStrategy implements LanguageStrategy.
+var _ code.LanguageStrategy = (*CSharpStrategy)(nil)
@@ -26,6 +26,15 @@ func NewDefaultChunker() *code.TreeSitterChunker {
 	c.RegisterStrategy(NewRustStrategy())
 	c.RegisterStrategy(NewCStrategy())
 	c.RegisterStrategy(NewCPPStrategy())
+	c.RegisterStrategy(NewGroovyStrategy())
+	c.RegisterStrategy(NewKotlinStrategy())
+	c.RegisterStrategy(NewRubyStrategy())
+	c.RegisterStrategy(NewPHPStrategy())
+	c.RegisterStrategy(NewCSharpStrategy())
+	c.RegisterStrategy(NewBashStrategy())
+	c.RegisterStrategy(NewSwiftStrategy())
+	c.RegisterStrategy(NewScalaStrategy())
+	c.RegisterStrategy(NewLuaStrategy())
 
 	return c
 }
@@ -96,10 +96,10 @@ func (s *GoStrategy) ExtractMetadata(node *sitter.Node, source []byte) *chunkers
 
 // extractFunctionMetadata extracts metadata from a function declaration.
 func (s *GoStrategy) extractFunctionMetadata(node *sitter.Node, source []byte, meta *chunkers.CodeMetadata) {
-	// Find function name
+	// Find function name (method names are field identifiers)
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
-		if child.Type() == "identifier" {
+		if child.Type() == "identifier" || child.Type() == "field_identifier" {
 			meta.FunctionName = string(source[child.StartByte():child.EndByte()])
 			meta.IsExported = isExported(meta.FunctionName)
 			meta.Visibility = "package"
@@ -127,6 +127,15 @@ func (s *GoStrategy) extractFunctionMetadata(node *sitter.Node, source []byte, m
 
 	// Check for preceding doc comment
 	meta.Docstring = s.extractDocComment(node, source)
+
+	// Record returned errors and panics
+	meta.Raises = s.extractRaises(node, source)
+
+	// Detect go test functions (only plain functions, never methods)
+	if node.Type() == "function_declaration" {
+		meta.IsTest = s.isTestFunction(meta.FunctionName, params, source)
+		meta.IsConstructor = s.isConstructor(meta.FunctionName, node.ChildByFieldName("result"), source)
+	}
 }
 
 // extractMethodMetadata extracts metadata from a method declaration.
@@ -192,6 +201,10 @@ func (s *GoStrategy) extractSignature(node *sitter.Node, source []byte) string {
 		switch child.Type() {
 		case "identifier":
 			sig.WriteString(string(source[child.StartByte():child.EndByte()]))
+		case "field_identifier":
+			// Method name follows the receiver
+			sig.WriteString(" ")
+			sig.WriteString(string(source[child.StartByte():child.EndByte()]))
 		case "parameter_list":
 			sig.WriteString(string(source[child.StartByte():child.EndByte()]))
 		case "result":
@@ -231,6 +244,95 @@ func (s *GoStrategy) extractParameters(params *sitter.Node, source []byte) []str
 	return result
 }
 
+// goTestPrefixes maps go test function name prefixes to the parameter type they
+// require. Example functions take no parameters.
+var goTestPrefixes = []struct {
+	prefix    string
+	paramType string
+}{
+	{"Test", "*testing.T"},
+	{"Benchmark", "*testing.B"},
+	{"Fuzz", "*testing.F"},
+	{"Example", ""},
+}
+
+// isTestFunction reports whether a function follows the go test naming and
+// signature conventions (TestXxx(*testing.T), BenchmarkXxx(*testing.B),
+// FuzzXxx(*testing.F), ExampleXxx()).
+func (s *GoStrategy) isTestFunction(name string, params *sitter.Node, source []byte) bool {
+	var paramTypes []string
+	if params != nil {
+		for i := 0; i < int(params.NamedChildCount()); i++ {
+			child := params.NamedChild(i)
+			if child.Type() != "parameter_declaration" {
+				continue
+			}
+			if typeNode := child.ChildByFieldName("type"); typeNode != nil {
+				paramTypes = append(paramTypes, string(source[typeNode.StartByte():typeNode.EndByte()]))
+			}
+		}
+	}
+
+	// TestMain(m *testing.M) is test scaffolding as well
+	if name == "TestMain" {
+		return len(paramTypes) == 1 && paramTypes[0] == "*testing.M"
+	}
+
+	for _, tp := range goTestPrefixes {
+		if !strings.HasPrefix(name, tp.prefix) {
+			continue
+		}
+		// The prefix must be followed by end of name or a non-lowercase rune
+		rest := name[len(tp.prefix):]
+		if rest != "" && unicode.IsLower([]rune(rest)[0]) {
+			return false
+		}
+		if tp.paramType == "" {
+			return len(paramTypes) == 0
+		}
+		return len(paramTypes) == 1 && paramTypes[0] == tp.paramType
+	}
+
+	return false
+}
+
+// extractRaises reports "error" when a function returns an error-typed result
+// and "panic" when its body calls panic. Function literals are not descended into.
+func (s *GoStrategy) extractRaises(node *sitter.Node, source []byte) []string {
+	var raises []string
+
+	if result := node.ChildByFieldName("result"); result != nil {
+		switch result.Type() {
+		case "type_identifier":
+			if string(source[result.StartByte():result.EndByte()]) == "error" {
+				raises = append(raises, "error")
+			}
+		case "parameter_list":
+			for i := 0; i < int(result.NamedChildCount()); i++ {
+				typ := result.NamedChild(i).ChildByFieldName("type")
+				if typ != nil && string(source[typ.StartByte():typ.EndByte()]) == "error" {
+					raises = append(raises, "error")
+					break
+				}
+			}
+		}
+	}
+
+	if body := node.ChildByFieldName("body"); body != nil {
+		walkBody(body, map[string]bool{"func_literal": true}, func(n *sitter.Node) {
+			if n.Type() != "call_expression" {
+				return
+			}
+			fn := n.ChildByFieldName("function")
+			if fn != nil && fn.Type() == "identifier" && string(source[fn.StartByte():fn.EndByte()]) == "panic" {
+				raises = appendUnique(raises, "panic")
+			}
+		})
+	}
+
+	return raises
+}
+
 // extractDocComment extracts the doc comment preceding a node.
 func (s *GoStrategy) extractDocComment(node *sitter.Node, source []byte) string {
 	// Look for comment nodes immediately before this node
@@ -261,6 +363,60 @@ func (s *GoStrategy) findChild(node *sitter.Node, nodeType string) *sitter.Node
 	return nil
 }
 
+// goPredeclaredTypes lists builtin types that never make a New* function a constructor.
+var goPredeclaredTypes = map[string]bool{
+	"any": true, "bool": true, "byte": true, "comparable": true, "complex64": true,
+	"complex128": true, "error": true, "float32": true, "float64": true, "int": true,
+	"int8": true, "int16": true, "int32": true, "int64": true, "rune": true,
+	"string": true, "uint": true, "uint8": true, "uint16": true, "uint32": true,
+	"uint64": true, "uintptr": true,
+}
+
+// isConstructor reports whether a New* factory function returns a type declared
+// in the current package, optionally behind a pointer or paired with an error.
+func (s *GoStrategy) isConstructor(name string, result *sitter.Node, source []byte) bool {
+	if !strings.HasPrefix(name, "New") || result == nil {
+		return false
+	}
+	// The prefix must be followed by end of name or a non-lowercase rune
+	rest := name[len("New"):]
+	if rest != "" && unicode.IsLower([]rune(rest)[0]) {
+		return false
+	}
+
+	// Multiple results: the constructed type comes first
+	typeNode := result
+	if typeNode.Type() == "parameter_list" {
+		first := typeNode.NamedChild(0)
+		if first == nil || first.Type() != "parameter_declaration" {
+			return false
+		}
+		typeNode = first.ChildByFieldName("type")
+		if typeNode == nil {
+			return false
+		}
+	}
+
+	if typeNode.Type() == "pointer_type" {
+		typeNode = typeNode.NamedChild(0)
+		if typeNode == nil {
+			return false
+		}
+	}
+	if typeNode.Type() == "generic_type" {
+		typeNode = typeNode.ChildByFieldName("type")
+		if typeNode == nil {
+			return false
+		}
+	}
+
+	// Qualified types (pkg.Type) belong to another package
+	if typeNode.Type() != "type_identifier" {
+		return false
+	}
+	return !goPredeclaredTypes[string(source[typeNode.StartByte():typeNode.EndByte()])]
+}
+
 // isExported returns true if the identifier is exported (starts with uppercase).
 func isExported(name string) bool {
 	if name == "" {
@@ -0,0 +1,325 @@
+package languages
+
+import (
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/groovy"
+
+	"github.com/leefowlercu/agentic-memorizer/internal/chunkers"
+	"github.com/leefowlercu/agentic-memorizer/internal/chunkers/code"
+)
+
+// GroovyStrategy implements tree-sitter parsing for Groovy code, including
+// Gradle build scripts and Jenkins pipelines.
+type GroovyStrategy struct{}
+
+// NewGroovyStrategy creates a new Groovy language strategy.
+func NewGroovyStrategy() *GroovyStrategy {
+	return &GroovyStrategy{}
+}
+
+// Language returns the language identifier.
+func (s *GroovyStrategy) Language() string {
+	return "groovy"
+}
+
+// Extensions returns file extensions this strategy handles.
+func (s *GroovyStrategy) Extensions() []string {
+	return []string{".groovy", ".gradle"}
+}
+
+// MIMETypes returns MIME types this strategy handles.
+func (s *GroovyStrategy) MIMETypes() []string {
+	return []string{
+		"text/x-groovy",
+	}
+}
+
+// GetLanguage returns the tree-sitter Language for Groovy.
+func (s *GroovyStrategy) GetLanguage() *sitter.Language {
+	return groovy.GetLanguage()
+}
+
+// NodeTypes returns Groovy-specific node type configuration.
+func (s *GroovyStrategy) NodeTypes() code.NodeTypeConfig {
+	return code.NodeTypeConfig{
+		Functions: []string{
+			"function_definition",
+		},
+		Methods: []string{},
+		Classes: []string{
+			"class_definition",
+		},
+		Declarations: []string{
+			"declaration",
+		},
+		TopLevel: []string{
+			"juxt_function_call",
+			"pipeline",
+		},
+	}
+}
+
+// ShouldChunk determines if a node should be its own chunk.
+func (s *GroovyStrategy) ShouldChunk(node *sitter.Node) bool {
+	parent := node.Parent()
+	topLevel := parent != nil && parent.Type() == "source_file"
+
+	switch node.Type() {
+	case "class_definition":
+		return true
+	case "function_definition":
+		// Chunk script-level functions and class methods
+		return topLevel || s.enclosingClass(node) != nil
+	case "declaration":
+		// Only chunk top-level closure definitions: def name = { ... }
+		if !topLevel {
+			return false
+		}
+		value := node.ChildByFieldName("value")
+		return value != nil && value.Type() == "closure"
+	case "juxt_function_call":
+		// Gradle DSL configuration blocks: dependencies { ... }
+		return topLevel && s.blockClosure(node) != nil
+	case "pipeline":
+		return topLevel
+	}
+	return false
+}
+
+// ExtractMetadata extracts Groovy-specific metadata from an AST node.
+func (s *GroovyStrategy) ExtractMetadata(node *sitter.Node, source []byte) *chunkers.CodeMetadata {
+	meta := &chunkers.CodeMetadata{
+		Language:  "groovy",
+		LineStart: int(node.StartPoint().Row) + 1,
+		LineEnd:   int(node.EndPoint().Row) + 1,
+	}
+
+	switch node.Type() {
+	case "class_definition":
+		s.extractClassMetadata(node, source, meta)
+	case "function_definition":
+		s.extractMethodMetadata(node, source, meta)
+	case "declaration":
+		s.extractClosureMetadata(node, source, meta)
+	case "juxt_function_call", "pipeline":
+		s.extractBlockMetadata(node, source, meta)
+	}
+
+	return meta
+}
+
+// extractClassMetadata extracts metadata from a class or interface definition.
+func (s *GroovyStrategy) extractClassMetadata(node *sitter.Node, source []byte, meta *chunkers.CodeMetadata) {
+	if name := node.ChildByFieldName("name"); name != nil {
+		meta.ClassName = string(source[name.StartByte():name.EndByte()])
+	}
+
+	if superclass := node.ChildByFieldName("superclass"); superclass != nil {
+		meta.ParentClass = string(source[superclass.StartByte():superclass.EndByte()])
+	}
+
+	s.extractModifiers(node, source, meta)
+	meta.Docstring = s.extractGroovyDoc(node, source)
+	meta.Decorators = s.extractAnnotations(node, source)
+}
+
+// extractMethodMetadata extracts metadata from a method or script function definition.
+func (s *GroovyStrategy) extractMethodMetadata(node *sitter.Node, source []byte, meta *chunkers.CodeMetadata) {
+	if class := s.enclosingClass(node); class != nil {
+		if name := class.ChildByFieldName("name"); name != nil {
+			meta.ClassName = string(source[name.StartByte():name.EndByte()])
+		}
+	}
+
+	if name := node.ChildByFieldName("function"); name != nil {
+		meta.FunctionName = string(source[name.StartByte():name.EndByte()])
+	}
+
+	if returnType := node.ChildByFieldName("type"); returnType != nil {
+		meta.ReturnType = string(source[returnType.StartByte():returnType.EndByte()])
+	}
+
+	if params := node.ChildByFieldName("parameters"); params != nil {
+		meta.Parameters = s.extractParameters(params, source)
+	}
+
+	// Constructors share the class name
+	if meta.ClassName != "" && meta.FunctionName == meta.ClassName {
+		meta.IsConstructor = true
+	}
+
+	s.extractModifiers(node, source, meta)
+	meta.Signature = s.buildMethodSignature(meta)
+	meta.Docstring = s.extractGroovyDoc(node, source)
+	meta.Decorators = s.extractAnnotations(node, source)
+}
+
+// extractClosureMetadata extracts metadata from a closure assigned to a variable.
+func (s *GroovyStrategy) extractClosureMetadata(node *sitter.Node, source []byte, meta *chunkers.CodeMetadata) {
+	if name := node.ChildByFieldName("name"); name != nil {
+		meta.FunctionName = string(source[name.StartByte():name.EndByte()])
+	}
+
+	if closure := node.ChildByFieldName("value"); closure != nil {
+		if params := s.findChild(closure, "parameter_list"); params != nil {
+			meta.Parameters = s.extractParameters(params, source)
+		}
+	}
+
+	meta.Visibility = "public"
+	meta.IsExported = true
+	meta.Signature = meta.FunctionName + " = { " + strings.Join(meta.Parameters, ", ") + " -> }"
+	meta.Docstring = s.extractGroovyDoc(node, source)
+}
+
+// extractBlockMetadata extracts metadata from a top-level DSL block such as a
+// Gradle dependencies {} block or a Jenkins pipeline {}. The block name is
+// recorded as the function name and the signature marks it as a block.
+func (s *GroovyStrategy) extractBlockMetadata(node *sitter.Node, source []byte, meta *chunkers.CodeMetadata) {
+	if node.Type() == "pipeline" {
+		meta.FunctionName = "pipeline"
+	} else if fn := node.ChildByFieldName("function"); fn != nil {
+		meta.FunctionName = string(source[fn.StartByte():fn.EndByte()])
+	}
+
+	meta.Signature = meta.FunctionName + " {}"
+	meta.Docstring = s.extractGroovyDoc(node, source)
+}
+
+// extractModifiers extracts visibility and static modifiers. Groovy members are
+// public unless declared otherwise.
+func (s *GroovyStrategy) extractModifiers(node *sitter.Node, source []byte, meta *chunkers.CodeMetadata) {
+	meta.Visibility = "public"
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		switch child.Type() {
+		case "access_modifier":
+			meta.Visibility = string(source[child.StartByte():child.EndByte()])
+		case "modifier":
+			if string(source[child.StartByte():child.EndByte()]) == "static" {
+				meta.IsStatic = true
+			}
+		}
+	}
+	meta.IsExported = meta.Visibility == "public"
+}
+
+// extractParameters extracts parameter names from a parameter list.
+func (s *GroovyStrategy) extractParameters(params *sitter.Node, source []byte) []string {
+	var result []string
+
+	for i := 0; i < int(params.NamedChildCount()); i++ {
+		param := params.NamedChild(i)
+		if param.Type() != "parameter" {
+			continue
+		}
+		if name := param.ChildByFieldName("name"); name != nil {
+			result = append(result, string(source[name.StartByte():name.EndByte()]))
+		}
+	}
+
+	return result
+}
+
+// extractAnnotations extracts annotation names on a declaration.
+func (s *GroovyStrategy) extractAnnotations(node *sitter.Node, source []byte) []string {
+	var annotations []string
+
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		if child.Type() != "annotation" {
+			continue
+		}
+		if name := s.findChild(child, "identifier"); name != nil {
+			annotations = append(annotations, string(source[name.StartByte():name.EndByte()]))
+		}
+	}
+
+	return annotations
+}
+
+// extractGroovyDoc extracts the GroovyDoc comment preceding a node.
+func (s *GroovyStrategy) extractGroovyDoc(node *sitter.Node, source []byte) string {
+	prev := node.PrevSibling()
+	if prev == nil || prev.Type() != "groovy_doc" {
+		return ""
+	}
+
+	comment := string(source[prev.StartByte():prev.EndByte()])
+	comment = strings.TrimPrefix(comment, "/**")
+	comment = strings.TrimSuffix(comment, "*/")
+	var cleaned []string
+	for _, line := range strings.Split(comment, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimPrefix(line, "*")
+		line = strings.TrimSpace(line)
+		// Skip @param, @return tags for summary
+		if !strings.HasPrefix(line, "@") && line != "" {
+			cleaned = append(cleaned, line)
+		}
+	}
+	return strings.Join(cleaned, " ")
+}
+
+// buildMethodSignature builds a method signature string.
+func (s *GroovyStrategy) buildMethodSignature(meta *chunkers.CodeMetadata) string {
+	var sig strings.Builder
+
+	if meta.Visibility != "" && meta.Visibility != "public" {
+		sig.WriteString(meta.Visibility)
+		sig.WriteString(" ")
+	}
+	if meta.IsStatic {
+		sig.WriteString("static ")
+	}
+	if meta.ReturnType != "" {
+		sig.WriteString(meta.ReturnType)
+	} else {
+		sig.WriteString("def")
+	}
+	sig.WriteString(" ")
+	sig.WriteString(meta.FunctionName)
+	sig.WriteString("(")
+	sig.WriteString(strings.Join(meta.Parameters, ", "))
+	sig.WriteString(")")
+
+	return sig.String()
+}
+
+// enclosingClass returns the class definition whose body directly contains node, or nil.
+func (s *GroovyStrategy) enclosingClass(node *sitter.Node) *sitter.Node {
+	body := node.Parent()
+	if body == nil || body.Type() != "closure" {
+		return nil
+	}
+	class := body.Parent()
+	if class == nil || class.Type() != "class_definition" {
+		return nil
+	}
+	return class
+}
+
+// blockClosure returns the closure argument of a DSL block call, or nil.
+func (s *GroovyStrategy) blockClosure(node *sitter.Node) *sitter.Node {
+	args := node.ChildByFieldName("args")
+	if args == nil {
+		return nil
+	}
+	return s.findChild(args, "closure")
+}
+
+// findChild finds the first child with the given type.
+func (s *GroovyStrategy) findChild(node *sitter.Node, nodeType string) *sitter.Node {
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		if child.Type() == nodeType {
+			return child
+		}
+	}
+	return nil
+}
+
+// Ensure GroovyStrategy implements LanguageStrategy.
+var _ code.LanguageStrategy = (*GroovyStrategy)(nil)
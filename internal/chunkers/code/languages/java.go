@@ -287,6 +287,9 @@ func (s *JavaStrategy) extractMethodMetadata(node *sitter.Node, source []byte, m
 		meta.Parameters = s.extractParameters(params, source)
 	}
 
+	// Extract throws clause
+	meta.Raises = s.extractThrows(node, source)
+
 	// Build signature
 	meta.Signature = s.buildMethodSignature(meta)
 
@@ -329,6 +332,9 @@ func (s *JavaStrategy) extractConstructorMetadata(node *sitter.Node, source []by
 		meta.Parameters = s.extractParameters(params, source)
 	}
 
+	// Extract throws clause
+	meta.Raises = s.extractThrows(node, source)
+
 	// Build signature
 	meta.Signature = s.buildConstructorSignature(meta)
 
@@ -415,6 +421,21 @@ func (s *JavaStrategy) extractParameters(params *sitter.Node, source []byte) []s
 	return result
 }
 
+// extractThrows extracts the exception types declared in a throws clause.
+func (s *JavaStrategy) extractThrows(node *sitter.Node, source []byte) []string {
+	throws := s.findChild(node, "throws")
+	if throws == nil {
+		return nil
+	}
+
+	var raises []string
+	for i := 0; i < int(throws.NamedChildCount()); i++ {
+		child := throws.NamedChild(i)
+		raises = appendUnique(raises, string(source[child.StartByte():child.EndByte()]))
+	}
+	return raises
+}
+
 // extractAnnotations extracts annotation names preceding a declaration.
 func (s *JavaStrategy) extractAnnotations(node *sitter.Node, source []byte) []string {
 	var annotations []string
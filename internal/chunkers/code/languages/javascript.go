@@ -63,6 +63,7 @@ func (s *JavaScriptStrategy) NodeTypes() code.NodeTypeConfig {
 		},
 		TopLevel: []string{
 			"export_statement",
+			"expression_statement",
 		},
 	}
 }
@@ -103,6 +104,13 @@ func (s *JavaScriptStrategy) ShouldChunk(node *sitter.Node) bool {
 			}
 		}
 		return false
+	case "expression_statement":
+		// Only chunk top-level calls that take a callback (describe/it/test blocks, route handlers)
+		parent := node.Parent()
+		if parent == nil || parent.Type() != "program" {
+			return false
+		}
+		return jsCallbackCall(node) != nil
 	case "export_statement":
 		// Only chunk export statements that contain declarations
 		for i := 0; i < int(node.ChildCount()); i++ {
@@ -137,6 +145,8 @@ func (s *JavaScriptStrategy) ExtractMetadata(node *sitter.Node, source []byte) *
 		s.extractMethodMetadata(node, source, meta)
 	case "export_statement":
 		s.extractExportMetadata(node, source, meta)
+	case "expression_statement":
+		extractJSCallMetadata(node, source, meta)
 	}
 
 	return meta
@@ -180,6 +190,9 @@ func (s *JavaScriptStrategy) extractFunctionMetadata(node *sitter.Node, source [
 		meta.Parameters = s.extractParameters(params, source)
 	}
 
+	// Collect thrown errors
+	meta.Raises = jsThrows(node, source)
+
 	// Build signature
 	meta.Signature = s.buildFunctionSignature(meta)
 
@@ -226,6 +239,9 @@ func (s *JavaScriptStrategy) extractArrowFunctionMetadata(node *sitter.Node, sou
 		meta.Parameters = s.extractParameters(params, source)
 	}
 
+	// Collect thrown errors
+	meta.Raises = jsThrows(node, source)
+
 	// Build signature
 	meta.Signature = s.buildFunctionSignature(meta)
 }
@@ -319,6 +335,9 @@ func (s *JavaScriptStrategy) extractMethodMetadata(node *sitter.Node, source []b
 		meta.Parameters = s.extractParameters(params, source)
 	}
 
+	// Collect thrown errors
+	meta.Raises = jsThrows(node, source)
+
 	// Build signature
 	meta.Signature = s.buildMethodSignature(meta)
 }
@@ -469,6 +488,71 @@ func (s *JavaScriptStrategy) buildMethodSignature(meta *chunkers.CodeMetadata) s
 	return sig.String()
 }
 
+// jsTestFunctions are the callee names used by Jest, Mocha, Vitest, and Jasmine
+// to declare test suites and test cases.
+var jsTestFunctions = map[string]bool{
+	"describe": true,
+	"context":  true,
+	"suite":    true,
+	"it":       true,
+	"test":     true,
+	"specify":  true,
+}
+
+// jsCallbackCall returns the call expression of an expression statement when
+// the call receives a function argument, or nil otherwise. Shared by the
+// JavaScript and TypeScript strategies.
+func jsCallbackCall(stmt *sitter.Node) *sitter.Node {
+	call := stmt.NamedChild(0)
+	if call == nil || call.Type() != "call_expression" {
+		return nil
+	}
+	args := call.ChildByFieldName("arguments")
+	if args == nil {
+		return nil
+	}
+	for i := 0; i < int(args.NamedChildCount()); i++ {
+		switch args.NamedChild(i).Type() {
+		case "arrow_function", "function_expression", "function":
+			return call
+		}
+	}
+	return nil
+}
+
+// extractJSCallMetadata extracts metadata from a top-level callback call such as
+// describe("suite", () => {}) and flags test suite/case declarations.
+func extractJSCallMetadata(stmt *sitter.Node, source []byte, meta *chunkers.CodeMetadata) {
+	call := jsCallbackCall(stmt)
+	if call == nil {
+		return
+	}
+
+	callee := call.ChildByFieldName("function")
+	if callee == nil {
+		return
+	}
+	calleeText := string(source[callee.StartByte():callee.EndByte()])
+	meta.FunctionName = calleeText
+
+	// Modifiers like describe.only, it.skip, test.each(...) share the base name
+	base := calleeText
+	if idx := strings.IndexAny(base, ".("); idx >= 0 {
+		base = base[:idx]
+	}
+	meta.IsTest = jsTestFunctions[base]
+
+	// Use the suite/case title as the signature when present
+	args := call.ChildByFieldName("arguments")
+	if args != nil && args.NamedChildCount() > 0 {
+		first := args.NamedChild(0)
+		switch first.Type() {
+		case "string", "template_string":
+			meta.Signature = calleeText + "(" + string(source[first.StartByte():first.EndByte()]) + ")"
+		}
+	}
+}
+
 // findChild finds the first child with the given type.
 func (s *JavaScriptStrategy) findChild(node *sitter.Node, nodeType string) *sitter.Node {
 	for i := 0; i < int(node.ChildCount()); i++ {
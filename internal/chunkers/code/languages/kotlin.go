@@ -0,0 +1,359 @@
+package languages
+
+import (
+	"slices"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/kotlin"
+
+	"github.com/leefowlercu/agentic-memorizer/internal/chunkers"
+	"github.com/leefowlercu/agentic-memorizer/internal/chunkers/code"
+)
+
+// KotlinStrategy implements tree-sitter parsing for Kotlin code and Kotlin
+// script files.
+type KotlinStrategy struct{}
+
+// NewKotlinStrategy creates a new Kotlin language strategy.
+func NewKotlinStrategy() *KotlinStrategy {
+	return &KotlinStrategy{}
+}
+
+// Language returns the language identifier.
+func (s *KotlinStrategy) Language() string {
+	return "kotlin"
+}
+
+// Extensions returns file extensions this strategy handles.
+func (s *KotlinStrategy) Extensions() []string {
+	return []string{".kt", ".kts"}
+}
+
+// MIMETypes returns MIME types this strategy handles.
+func (s *KotlinStrategy) MIMETypes() []string {
+	return []string{
+		"text/x-kotlin",
+	}
+}
+
+// GetLanguage returns the tree-sitter Language for Kotlin.
+func (s *KotlinStrategy) GetLanguage() *sitter.Language {
+	return kotlin.GetLanguage()
+}
+
+// NodeTypes returns Kotlin-specific node type configuration.
+func (s *KotlinStrategy) NodeTypes() code.NodeTypeConfig {
+	return code.NodeTypeConfig{
+		Functions: []string{
+			"function_declaration",
+		},
+		Methods: []string{},
+		Classes: []string{
+			"class_declaration",
+			"object_declaration",
+		},
+		Declarations: []string{
+			"property_declaration",
+		},
+		TopLevel: []string{},
+	}
+}
+
+// ShouldChunk determines if a node should be its own chunk.
+func (s *KotlinStrategy) ShouldChunk(node *sitter.Node) bool {
+	parent := node.Parent()
+	topLevel := parent != nil && parent.Type() == "source_file"
+
+	switch node.Type() {
+	case "class_declaration", "object_declaration":
+		return true
+	case "function_declaration":
+		// Chunk top-level functions and class members
+		return topLevel || (parent != nil && parent.Type() == "class_body")
+	case "property_declaration":
+		// Only chunk top-level properties; members stay with their class
+		return topLevel
+	}
+	return false
+}
+
+// ExtractMetadata extracts Kotlin-specific metadata from an AST node.
+func (s *KotlinStrategy) ExtractMetadata(node *sitter.Node, source []byte) *chunkers.CodeMetadata {
+	meta := &chunkers.CodeMetadata{
+		Language:  "kotlin",
+		LineStart: int(node.StartPoint().Row) + 1,
+		LineEnd:   int(node.EndPoint().Row) + 1,
+	}
+
+	switch node.Type() {
+	case "class_declaration", "object_declaration":
+		s.extractClassMetadata(node, source, meta)
+	case "function_declaration":
+		s.extractFunctionMetadata(node, source, meta)
+	case "property_declaration":
+		s.extractPropertyMetadata(node, source, meta)
+	}
+
+	return meta
+}
+
+// extractClassMetadata extracts metadata from a class, interface, enum or
+// object declaration.
+func (s *KotlinStrategy) extractClassMetadata(node *sitter.Node, source []byte, meta *chunkers.CodeMetadata) {
+	if name := s.findChild(node, "type_identifier"); name != nil {
+		meta.ClassName = string(source[name.StartByte():name.EndByte()])
+	}
+
+	// Superclass is the delegation specifier that invokes a constructor;
+	// the remaining specifiers are implemented interfaces.
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		if child.Type() != "delegation_specifier" {
+			continue
+		}
+		if invocation := s.findChild(child, "constructor_invocation"); invocation != nil {
+			if userType := s.findChild(invocation, "user_type"); userType != nil {
+				meta.ParentClass = string(source[userType.StartByte():userType.EndByte()])
+			}
+			continue
+		}
+		meta.Implements = append(meta.Implements, string(source[child.StartByte():child.EndByte()]))
+	}
+
+	s.extractModifiers(node, source, meta)
+	meta.Docstring = s.extractKDoc(node, source)
+	meta.Decorators = s.extractAnnotations(node, source)
+}
+
+// extractFunctionMetadata extracts metadata from a function declaration.
+func (s *KotlinStrategy) extractFunctionMetadata(node *sitter.Node, source []byte, meta *chunkers.CodeMetadata) {
+	if class := s.enclosingClass(node); class != nil {
+		if name := s.findChild(class, "type_identifier"); name != nil {
+			meta.ClassName = string(source[name.StartByte():name.EndByte()])
+		}
+	}
+
+	var receiver string
+	afterParams := false
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		switch child.Type() {
+		case "simple_identifier":
+			if meta.FunctionName == "" {
+				meta.FunctionName = string(source[child.StartByte():child.EndByte()])
+			}
+		case "function_value_parameters":
+			meta.Parameters = s.extractParameters(child, source)
+			afterParams = true
+		case "user_type", "nullable_type", "function_type", "parenthesized_type":
+			text := string(source[child.StartByte():child.EndByte()])
+			if afterParams {
+				meta.ReturnType = text
+			} else if meta.FunctionName == "" {
+				// Extension function receiver: fun String.shout()
+				receiver = text
+			}
+		}
+	}
+
+	s.extractModifiers(node, source, meta)
+	meta.Signature = s.buildFunctionSignature(meta, receiver)
+	meta.Docstring = s.extractKDoc(node, source)
+	meta.Decorators = s.extractAnnotations(node, source)
+	meta.IsTest = slices.Contains(meta.Decorators, "Test")
+}
+
+// extractPropertyMetadata extracts metadata from a top-level property declaration.
+func (s *KotlinStrategy) extractPropertyMetadata(node *sitter.Node, source []byte, meta *chunkers.CodeMetadata) {
+	s.extractModifiers(node, source, meta)
+
+	var sig strings.Builder
+	if meta.Visibility != "public" {
+		sig.WriteString(meta.Visibility)
+		sig.WriteString(" ")
+	}
+	if kind := s.findChild(node, "binding_pattern_kind"); kind != nil {
+		sig.WriteString(string(source[kind.StartByte():kind.EndByte()]))
+		sig.WriteString(" ")
+	}
+	if decl := s.findChild(node, "variable_declaration"); decl != nil {
+		sig.WriteString(string(source[decl.StartByte():decl.EndByte()]))
+	}
+	meta.Signature = sig.String()
+
+	meta.Docstring = s.extractKDoc(node, source)
+	meta.Decorators = s.extractAnnotations(node, source)
+}
+
+// extractModifiers extracts visibility and suspend modifiers. Kotlin
+// declarations are public unless declared otherwise.
+func (s *KotlinStrategy) extractModifiers(node *sitter.Node, source []byte, meta *chunkers.CodeMetadata) {
+	meta.Visibility = "public"
+	if modifiers := s.findChild(node, "modifiers"); modifiers != nil {
+		for i := 0; i < int(modifiers.ChildCount()); i++ {
+			child := modifiers.Child(i)
+			text := string(source[child.StartByte():child.EndByte()])
+			switch child.Type() {
+			case "visibility_modifier":
+				meta.Visibility = text
+			case "function_modifier":
+				if text == "suspend" {
+					meta.IsAsync = true
+				}
+			}
+		}
+	}
+	meta.IsExported = meta.Visibility == "public"
+}
+
+// extractParameters extracts parameters as "name: Type".
+func (s *KotlinStrategy) extractParameters(params *sitter.Node, source []byte) []string {
+	var result []string
+
+	for i := 0; i < int(params.NamedChildCount()); i++ {
+		param := params.NamedChild(i)
+		if param.Type() != "parameter" {
+			continue
+		}
+
+		var name, paramType string
+		for j := 0; j < int(param.ChildCount()); j++ {
+			child := param.Child(j)
+			switch child.Type() {
+			case "simple_identifier":
+				name = string(source[child.StartByte():child.EndByte()])
+			case "user_type", "nullable_type", "function_type", "parenthesized_type":
+				paramType = string(source[child.StartByte():child.EndByte()])
+			}
+		}
+
+		if name == "" {
+			continue
+		}
+		if paramType != "" {
+			result = append(result, name+": "+paramType)
+		} else {
+			result = append(result, name)
+		}
+	}
+
+	return result
+}
+
+// extractAnnotations extracts annotation names on a declaration.
+func (s *KotlinStrategy) extractAnnotations(node *sitter.Node, source []byte) []string {
+	modifiers := s.findChild(node, "modifiers")
+	if modifiers == nil {
+		return nil
+	}
+
+	var annotations []string
+	for i := 0; i < int(modifiers.ChildCount()); i++ {
+		child := modifiers.Child(i)
+		if child.Type() != "annotation" {
+			continue
+		}
+		target := child
+		if invocation := s.findChild(child, "constructor_invocation"); invocation != nil {
+			target = invocation
+		}
+		if userType := s.findChild(target, "user_type"); userType != nil {
+			annotations = append(annotations, string(source[userType.StartByte():userType.EndByte()]))
+		}
+	}
+
+	return annotations
+}
+
+// extractKDoc extracts the KDoc comment preceding a node. The grammar attaches
+// a comment that follows the imports to the last import header, so the
+// trailing descendants of the previous sibling are checked as well.
+func (s *KotlinStrategy) extractKDoc(node *sitter.Node, source []byte) string {
+	prev := node.PrevSibling()
+	for prev != nil && prev.Type() != "multiline_comment" && prev.ChildCount() > 0 {
+		prev = prev.Child(int(prev.ChildCount()) - 1)
+	}
+	if prev == nil || prev.Type() != "multiline_comment" {
+		return ""
+	}
+
+	comment := string(source[prev.StartByte():prev.EndByte()])
+	if !strings.HasPrefix(comment, "/**") {
+		return ""
+	}
+	comment = strings.TrimPrefix(comment, "/**")
+	comment = strings.TrimSuffix(comment, "*/")
+	var cleaned []string
+	for _, line := range strings.Split(comment, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimPrefix(line, "*")
+		line = strings.TrimSpace(line)
+		// Skip @param, @return tags for summary
+		if !strings.HasPrefix(line, "@") && line != "" {
+			cleaned = append(cleaned, line)
+		}
+	}
+	return strings.Join(cleaned, " ")
+}
+
+// buildFunctionSignature builds a function signature string.
+func (s *KotlinStrategy) buildFunctionSignature(meta *chunkers.CodeMetadata, receiver string) string {
+	var sig strings.Builder
+
+	if meta.Visibility != "" && meta.Visibility != "public" {
+		sig.WriteString(meta.Visibility)
+		sig.WriteString(" ")
+	}
+	if meta.IsAsync {
+		sig.WriteString("suspend ")
+	}
+	sig.WriteString("fun ")
+	if receiver != "" {
+		sig.WriteString(receiver)
+		sig.WriteString(".")
+	}
+	sig.WriteString(meta.FunctionName)
+	sig.WriteString("(")
+	sig.WriteString(strings.Join(meta.Parameters, ", "))
+	sig.WriteString(")")
+	if meta.ReturnType != "" {
+		sig.WriteString(": ")
+		sig.WriteString(meta.ReturnType)
+	}
+
+	return sig.String()
+}
+
+// enclosingClass returns the class or object whose body directly contains
+// node, or nil. Members of a companion object belong to the outer class.
+func (s *KotlinStrategy) enclosingClass(node *sitter.Node) *sitter.Node {
+	body := node.Parent()
+	if body == nil || body.Type() != "class_body" {
+		return nil
+	}
+	class := body.Parent()
+	if class != nil && class.Type() == "companion_object" {
+		if outer := class.Parent(); outer != nil && outer.Type() == "class_body" {
+			class = outer.Parent()
+		}
+	}
+	if class == nil || (class.Type() != "class_declaration" && class.Type() != "object_declaration") {
+		return nil
+	}
+	return class
+}
+
+// findChild finds the first child with the given type.
+func (s *KotlinStrategy) findChild(node *sitter.Node, nodeType string) *sitter.Node {
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		if child.Type() == nodeType {
+			return child
+		}
+	}
+	return nil
+}
+
+// Ensure KotlinStrategy implements LanguageStrategy.
+var _ code.LanguageStrategy = (*KotlinStrategy)(nil)
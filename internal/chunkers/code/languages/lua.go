@@ -0,0 +1,215 @@
+package languages
+
+import (
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/lua"
+
+	"github.com/leefowlercu/agentic-memorizer/internal/chunkers"
+	"github.com/leefowlercu/agentic-memorizer/internal/chunkers/code"
+)
+
+// LuaStrategy implements tree-sitter parsing for Lua code. The grammar folds
+// leading whitespace into tokens, so node text is trimmed before use.
+type LuaStrategy struct{}
+
+// NewLuaStrategy creates a new Lua language strategy.
+func NewLuaStrategy() *LuaStrategy {
+	return &LuaStrategy{}
+}
+
+// Language returns the language identifier.
+func (s *LuaStrategy) Language() string {
+	return "lua"
+}
+
+// Extensions returns file extensions this strategy handles.
+func (s *LuaStrategy) Extensions() []string {
+	return []string{".lua"}
+}
+
+// MIMETypes returns MIME types this strategy handles.
+func (s *LuaStrategy) MIMETypes() []string {
+	return []string{
+		"text/x-lua",
+	}
+}
+
+// GetLanguage returns the tree-sitter Language for Lua.
+func (s *LuaStrategy) GetLanguage() *sitter.Language {
+	return lua.GetLanguage()
+}
+
+// NodeTypes returns Lua-specific node type configuration.
+func (s *LuaStrategy) NodeTypes() code.NodeTypeConfig {
+	return code.NodeTypeConfig{
+		Functions: []string{
+			"function_statement",
+		},
+		Methods: []string{},
+		Classes: []string{},
+		Declarations: []string{
+			"variable_declaration",
+		},
+		TopLevel: []string{},
+	}
+}
+
+// ShouldChunk determines if a node should be its own chunk.
+func (s *LuaStrategy) ShouldChunk(node *sitter.Node) bool {
+	switch node.Type() {
+	case "function_statement":
+		return true
+	case "variable_declaration":
+		// Only chunk function expressions assigned at top level, such as
+		// M.handler = function(evt) ... end
+		return s.assignedFunction(node) != nil
+	}
+	return false
+}
+
+// IsHeaderNode reports whether a top-level node belongs to the file header:
+// leading comments and the require calls that load dependencies.
+func (s *LuaStrategy) IsHeaderNode(node *sitter.Node, source []byte) bool {
+	switch node.Type() {
+	case "comment":
+		return true
+	case "function_call":
+		return s.isRequire(node, source)
+	case "variable_declaration":
+		value := node.ChildByFieldName("value")
+		return value != nil && value.Type() == "function_call" && s.isRequire(value, source)
+	}
+	return false
+}
+
+// ExtractMetadata extracts Lua-specific metadata from an AST node.
+func (s *LuaStrategy) ExtractMetadata(node *sitter.Node, source []byte) *chunkers.CodeMetadata {
+	meta := &chunkers.CodeMetadata{
+		Language:  "lua",
+		LineStart: int(node.StartPoint().Row) + 1,
+		LineEnd:   int(node.EndPoint().Row) + 1,
+	}
+
+	function := node
+	if node.Type() == "variable_declaration" {
+		function = s.assignedFunction(node)
+	}
+	if function == nil {
+		return meta
+	}
+
+	var name string
+	if n := node.ChildByFieldName("name"); n != nil {
+		name = strings.TrimSpace(string(source[n.StartByte():n.EndByte()]))
+	}
+	s.splitName(name, meta)
+	if params := s.findChild(function, "parameter_list"); params != nil {
+		for i := 0; i < int(params.NamedChildCount()); i++ {
+			param := params.NamedChild(i)
+			meta.Parameters = append(meta.Parameters, strings.TrimSpace(string(source[param.StartByte():param.EndByte()])))
+		}
+	}
+
+	// Local functions are only visible in their file, like static C functions
+	local := s.findChild(node, "local") != nil
+	if local {
+		meta.Visibility = "file"
+	} else {
+		meta.Visibility = "public"
+		meta.IsExported = true
+	}
+
+	var sig strings.Builder
+	if local {
+		sig.WriteString("local ")
+	}
+	sig.WriteString("function ")
+	sig.WriteString(name)
+	sig.WriteString("(")
+	sig.WriteString(strings.Join(meta.Parameters, ", "))
+	sig.WriteString(")")
+	meta.Signature = sig.String()
+
+	meta.Docstring = s.extractDocumentation(node, source)
+
+	return meta
+}
+
+// splitName fills FunctionName from the last segment of a dotted or colon
+// function name. The table before a colon is the method's class; any other
+// prefix is its namespace, so a.B:m has class B in namespace a and M.add has
+// namespace M.
+func (s *LuaStrategy) splitName(name string, meta *chunkers.CodeMetadata) {
+	prefix := ""
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		prefix, meta.FunctionName = name[:i], name[i+1:]
+		if j := strings.LastIndex(prefix, "."); j >= 0 {
+			meta.Namespace, meta.ClassName = prefix[:j], prefix[j+1:]
+		} else {
+			meta.ClassName = prefix
+		}
+		return
+	}
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		meta.Namespace, meta.FunctionName = name[:i], name[i+1:]
+		return
+	}
+	meta.FunctionName = name
+}
+
+// extractDocumentation extracts the comments the grammar attaches to a
+// function statement, skipping EmmyLua annotations such as ---@param.
+func (s *LuaStrategy) extractDocumentation(node *sitter.Node, source []byte) string {
+	var lines []string
+	for i := 0; i < int(node.ChildCount()); i++ {
+		if node.FieldNameForChild(i) != "documentation" {
+			continue
+		}
+		child := node.Child(i)
+		for _, line := range strings.Split(string(source[child.StartByte():child.EndByte()]), "\n") {
+			line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "-"))
+			if line != "" && !strings.HasPrefix(line, "@") {
+				lines = append(lines, line)
+			}
+		}
+	}
+	return strings.Join(lines, " ")
+}
+
+// assignedFunction returns the function expression assigned by a top-level
+// variable declaration, or nil.
+func (s *LuaStrategy) assignedFunction(declaration *sitter.Node) *sitter.Node {
+	if program := declaration.Parent(); program == nil || program.Type() != "program" {
+		return nil
+	}
+	value := declaration.ChildByFieldName("value")
+	if value == nil || value.Type() != "function" {
+		return nil
+	}
+	return value
+}
+
+// isRequire reports whether a function call invokes require.
+func (s *LuaStrategy) isRequire(call *sitter.Node, source []byte) bool {
+	prefix := call.ChildByFieldName("prefix")
+	return prefix != nil && strings.TrimSpace(string(source[prefix.StartByte():prefix.EndByte()])) == "require"
+}
+
+// findChild finds the first child with the given type.
+func (s *LuaStrategy) findChild(node *sitter.Node, nodeType string) *sitter.Node {
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		if child.Type() == nodeType {
+			return child
+		}
+	}
+	return nil
+}
+
+// Ensure LuaStrategy implements LanguageStrategy and HeaderStrategy.
+var (
+	_ code.LanguageStrategy = (*LuaStrategy)(nil)
+	_ code.HeaderStrategy   = (*LuaStrategy)(nil)
+)
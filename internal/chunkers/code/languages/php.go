@@ -0,0 +1,305 @@
+package languages
+
+import (
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/php"
+
+	"github.com/leefowlercu/agentic-memorizer/internal/chunkers"
+	"github.com/leefowlercu/agentic-memorizer/internal/chunkers/code"
+)
+
+// PHPStrategy implements tree-sitter parsing for PHP code and templates.
+type PHPStrategy struct{}
+
+// NewPHPStrategy creates a new PHP language strategy.
+func NewPHPStrategy() *PHPStrategy {
+	return &PHPStrategy{}
+}
+
+// Language returns the language identifier.
+func (s *PHPStrategy) Language() string {
+	return "php"
+}
+
+// Extensions returns file extensions this strategy handles.
+func (s *PHPStrategy) Extensions() []string {
+	return []string{".php", ".phtml"}
+}
+
+// MIMETypes returns MIME types this strategy handles.
+func (s *PHPStrategy) MIMETypes() []string {
+	return []string{
+		"application/x-php",
+		"text/x-php",
+	}
+}
+
+// GetLanguage returns the tree-sitter Language for PHP.
+func (s *PHPStrategy) GetLanguage() *sitter.Language {
+	return php.GetLanguage()
+}
+
+// NodeTypes returns PHP-specific node type configuration.
+func (s *PHPStrategy) NodeTypes() code.NodeTypeConfig {
+	return code.NodeTypeConfig{
+		Functions: []string{
+			"function_definition",
+		},
+		Methods: []string{
+			"method_declaration",
+		},
+		Classes: []string{
+			"class_declaration",
+			"interface_declaration",
+			"trait_declaration",
+		},
+		Declarations: []string{},
+		TopLevel:     []string{},
+	}
+}
+
+// ShouldChunk determines if a node should be its own chunk.
+func (s *PHPStrategy) ShouldChunk(node *sitter.Node) bool {
+	switch node.Type() {
+	case "class_declaration", "interface_declaration", "trait_declaration":
+		return true
+	case "function_definition":
+		// Functions are reached at top level, inside namespace blocks or
+		// inside conditional definitions such as function_exists guards
+		return true
+	case "method_declaration":
+		parent := node.Parent()
+		return parent != nil && parent.Type() == "declaration_list"
+	}
+	return false
+}
+
+// ExtractMetadata extracts PHP-specific metadata from an AST node.
+func (s *PHPStrategy) ExtractMetadata(node *sitter.Node, source []byte) *chunkers.CodeMetadata {
+	meta := &chunkers.CodeMetadata{
+		Language:  "php",
+		LineStart: int(node.StartPoint().Row) + 1,
+		LineEnd:   int(node.EndPoint().Row) + 1,
+	}
+
+	switch node.Type() {
+	case "class_declaration", "interface_declaration", "trait_declaration":
+		s.extractClassMetadata(node, source, meta)
+	case "function_definition", "method_declaration":
+		s.extractFunctionMetadata(node, source, meta)
+	}
+
+	meta.Namespace = s.namespace(node, source)
+	meta.Docstring = s.extractPHPDoc(node, source)
+
+	return meta
+}
+
+// extractClassMetadata extracts metadata from a class, interface or trait
+// declaration.
+func (s *PHPStrategy) extractClassMetadata(node *sitter.Node, source []byte, meta *chunkers.CodeMetadata) {
+	if name := node.ChildByFieldName("name"); name != nil {
+		meta.ClassName = string(source[name.StartByte():name.EndByte()])
+	}
+
+	// A class extends its parent class; an interface extends other
+	// interfaces, which are recorded alongside implemented ones
+	if base := s.findChild(node, "base_clause"); base != nil {
+		names := s.namedTexts(base, source)
+		if node.Type() == "class_declaration" && len(names) > 0 {
+			meta.ParentClass = names[0]
+		} else {
+			meta.Implements = append(meta.Implements, names...)
+		}
+	}
+	if interfaces := s.findChild(node, "class_interface_clause"); interfaces != nil {
+		meta.Implements = append(meta.Implements, s.namedTexts(interfaces, source)...)
+	}
+
+	meta.Visibility = "public"
+	meta.IsExported = true
+}
+
+// extractFunctionMetadata extracts metadata from a function definition or
+// method declaration.
+func (s *PHPStrategy) extractFunctionMetadata(node *sitter.Node, source []byte, meta *chunkers.CodeMetadata) {
+	if name := node.ChildByFieldName("name"); name != nil {
+		meta.FunctionName = string(source[name.StartByte():name.EndByte()])
+	}
+	if params := node.ChildByFieldName("parameters"); params != nil {
+		meta.Parameters = s.extractParameters(params, source)
+	}
+	if returnType := node.ChildByFieldName("return_type"); returnType != nil {
+		meta.ReturnType = string(source[returnType.StartByte():returnType.EndByte()])
+	}
+
+	if class := s.enclosingClass(node); class != nil {
+		if name := class.ChildByFieldName("name"); name != nil {
+			meta.ClassName = string(source[name.StartByte():name.EndByte()])
+		}
+	}
+
+	// Members without a visibility modifier are public
+	meta.Visibility = "public"
+	var modifiers []string
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		if !strings.HasSuffix(child.Type(), "_modifier") {
+			continue
+		}
+		text := string(source[child.StartByte():child.EndByte()])
+		modifiers = append(modifiers, text)
+		switch child.Type() {
+		case "visibility_modifier":
+			meta.Visibility = text
+		case "static_modifier":
+			meta.IsStatic = true
+		}
+	}
+	meta.IsExported = meta.Visibility == "public"
+	meta.IsConstructor = meta.FunctionName == "__construct"
+	meta.IsTest = meta.ClassName != "" && strings.HasPrefix(meta.FunctionName, "test")
+
+	meta.Signature = s.buildFunctionSignature(meta, modifiers)
+}
+
+// extractParameters extracts parameters as "Type $name", keeping by-reference
+// and variadic markers and dropping default values.
+func (s *PHPStrategy) extractParameters(params *sitter.Node, source []byte) []string {
+	var result []string
+
+	for i := 0; i < int(params.NamedChildCount()); i++ {
+		param := params.NamedChild(i)
+		name := param.ChildByFieldName("name")
+		if name == nil {
+			continue
+		}
+
+		var text strings.Builder
+		if paramType := param.ChildByFieldName("type"); paramType != nil {
+			text.WriteString(string(source[paramType.StartByte():paramType.EndByte()]))
+			text.WriteString(" ")
+		}
+		if param.ChildByFieldName("reference_modifier") != nil {
+			text.WriteString("&")
+		}
+		if param.Type() == "variadic_parameter" {
+			text.WriteString("...")
+		}
+		text.WriteString(string(source[name.StartByte():name.EndByte()]))
+		result = append(result, text.String())
+	}
+
+	return result
+}
+
+// extractPHPDoc extracts the PHPDoc comment preceding a node.
+func (s *PHPStrategy) extractPHPDoc(node *sitter.Node, source []byte) string {
+	prev := node.PrevSibling()
+	if prev == nil || prev.Type() != "comment" {
+		return ""
+	}
+
+	comment := string(source[prev.StartByte():prev.EndByte()])
+	if !strings.HasPrefix(comment, "/**") {
+		return ""
+	}
+	comment = strings.TrimPrefix(comment, "/**")
+	comment = strings.TrimSuffix(comment, "*/")
+	var cleaned []string
+	for _, line := range strings.Split(comment, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimPrefix(line, "*")
+		line = strings.TrimSpace(line)
+		// Skip @param, @return tags for summary
+		if !strings.HasPrefix(line, "@") && line != "" {
+			cleaned = append(cleaned, line)
+		}
+	}
+	return strings.Join(cleaned, " ")
+}
+
+// buildFunctionSignature builds a function signature string.
+func (s *PHPStrategy) buildFunctionSignature(meta *chunkers.CodeMetadata, modifiers []string) string {
+	var sig strings.Builder
+
+	for _, modifier := range modifiers {
+		sig.WriteString(modifier)
+		sig.WriteString(" ")
+	}
+	sig.WriteString("function ")
+	sig.WriteString(meta.FunctionName)
+	sig.WriteString("(")
+	sig.WriteString(strings.Join(meta.Parameters, ", "))
+	sig.WriteString(")")
+	if meta.ReturnType != "" {
+		sig.WriteString(": ")
+		sig.WriteString(meta.ReturnType)
+	}
+
+	return sig.String()
+}
+
+// namespace returns the namespace a node is declared in, either from an
+// enclosing braced namespace block or from the closest preceding
+// "namespace X;" statement.
+func (s *PHPStrategy) namespace(node *sitter.Node, source []byte) string {
+	for current := node; current != nil; current = current.Parent() {
+		if current.Type() == "namespace_definition" {
+			if name := current.ChildByFieldName("name"); name != nil {
+				return string(source[name.StartByte():name.EndByte()])
+			}
+			return ""
+		}
+		if parent := current.Parent(); parent == nil || parent.Type() != "program" {
+			continue
+		}
+		for prev := current.PrevNamedSibling(); prev != nil; prev = prev.PrevNamedSibling() {
+			if prev.Type() != "namespace_definition" {
+				continue
+			}
+			if name := prev.ChildByFieldName("name"); name != nil {
+				return string(source[name.StartByte():name.EndByte()])
+			}
+			return ""
+		}
+		return ""
+	}
+	return ""
+}
+
+// enclosingClass returns the class, interface or trait whose body directly
+// contains node, or nil.
+func (s *PHPStrategy) enclosingClass(node *sitter.Node) *sitter.Node {
+	body := node.Parent()
+	if body == nil || body.Type() != "declaration_list" {
+		return nil
+	}
+	return body.Parent()
+}
+
+// namedTexts returns the source text of each named child of node.
+func (s *PHPStrategy) namedTexts(node *sitter.Node, source []byte) []string {
+	var texts []string
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		texts = append(texts, string(source[child.StartByte():child.EndByte()]))
+	}
+	return texts
+}
+
+// findChild finds the first child with the given type.
+func (s *PHPStrategy) findChild(node *sitter.Node, nodeType string) *sitter.Node {
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		if child.Type() == nodeType {
+			return child
+		}
+	}
+	return nil
+}
+
+// Ensure PHPStrategy implements LanguageStrategy.
+var _ code.LanguageStrategy = (*PHPStrategy)(nil)
@@ -1,6 +1,7 @@
 package languages
 
 import (
+	"regexp"
 	"strings"
 
 	sitter "github.com/smacker/go-tree-sitter"
@@ -100,6 +101,7 @@ func (s *PythonStrategy) ExtractMetadata(node *sitter.Node, source []byte) *chun
 // extractFunctionMetadata extracts metadata from a function definition.
 func (s *PythonStrategy) extractFunctionMetadata(node *sitter.Node, source []byte, meta *chunkers.CodeMetadata) {
 	// Check if this is a method (inside a class)
+	inTestCase := false
 	parent := node.Parent()
 	if parent != nil && parent.Type() == "block" {
 		grandparent := parent.Parent()
@@ -112,6 +114,9 @@ func (s *PythonStrategy) extractFunctionMetadata(node *sitter.Node, source []byt
 					break
 				}
 			}
+			if argList := s.findChild(grandparent, "argument_list"); argList != nil {
+				inTestCase = isTestCaseClass(s.extractBaseClasses(argList, source))
+			}
 		}
 	}
 
@@ -131,6 +136,14 @@ func (s *PythonStrategy) extractFunctionMetadata(node *sitter.Node, source []byt
 		}
 	}
 
+	// __new__ allocates and __init__ initializes class instances
+	meta.IsConstructor = meta.ClassName != "" &&
+		(meta.FunctionName == "__init__" || meta.FunctionName == "__new__")
+
+	// pytest collects test_* functions; unittest collects test* methods on TestCase subclasses
+	meta.IsTest = strings.HasPrefix(meta.FunctionName, "test_") ||
+		(inTestCase && strings.HasPrefix(meta.FunctionName, "test"))
+
 	// Check for async
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
@@ -162,10 +175,12 @@ func (s *PythonStrategy) extractFunctionMetadata(node *sitter.Node, source []byt
 		meta.ReturnType = string(source[returnType.StartByte():returnType.EndByte()])
 	}
 
-	// Extract docstring
+	// Extract docstring and raised exceptions
 	body := s.findChild(node, "block")
 	if body != nil {
 		meta.Docstring = s.extractDocstring(body, source)
+		meta.Raises = s.extractRaises(body, source)
+		meta.Raises = appendUnique(meta.Raises, docstringRaises(meta.Docstring)...)
 	}
 
 	// Build signature
@@ -198,6 +213,7 @@ func (s *PythonStrategy) extractClassMetadata(node *sitter.Node, source []byte,
 		if len(meta.Implements) > 0 {
 			meta.ParentClass = meta.Implements[0]
 		}
+		meta.IsTest = isTestCaseClass(meta.Implements)
 	}
 
 	// Extract docstring
@@ -292,6 +308,16 @@ func (s *PythonStrategy) extractBaseClasses(argList *sitter.Node, source []byte)
 	return bases
 }
 
+// isTestCaseClass returns true if any base class is a unittest TestCase.
+func isTestCaseClass(bases []string) bool {
+	for _, base := range bases {
+		if base == "TestCase" || strings.HasSuffix(base, ".TestCase") {
+			return true
+		}
+	}
+	return false
+}
+
 // extractDocstring extracts the docstring from a block.
 func (s *PythonStrategy) extractDocstring(block *sitter.Node, source []byte) string {
 	// First statement in block might be a string (docstring)
@@ -320,6 +346,79 @@ func (s *PythonStrategy) extractDocstring(block *sitter.Node, source []byte) str
 	return ""
 }
 
+// pythonNestedScopes are Python nodes whose raises belong to their own chunk.
+var pythonNestedScopes = map[string]bool{
+	"function_definition": true,
+	"class_definition":    true,
+	"lambda":              true,
+}
+
+// extractRaises extracts the exception types raised in a function body.
+// Bare re-raises are ignored; for raise X(...) the callee name is used.
+func (s *PythonStrategy) extractRaises(body *sitter.Node, source []byte) []string {
+	var raises []string
+	walkBody(body, pythonNestedScopes, func(n *sitter.Node) {
+		if n.Type() != "raise_statement" {
+			return
+		}
+		expr := n.NamedChild(0)
+		if expr == nil {
+			return
+		}
+		if expr.Type() == "call" {
+			expr = expr.ChildByFieldName("function")
+		}
+		if expr == nil {
+			return
+		}
+		switch expr.Type() {
+		case "identifier", "attribute":
+			raises = appendUnique(raises, string(source[expr.StartByte():expr.EndByte()]))
+		}
+	})
+	return raises
+}
+
+// Matches an entry in a docstring Raises section: "ValueError: if the value is empty"
+var docstringRaisesEntryRegex = regexp.MustCompile(`^([A-Za-z_][\w.]*)\s*:`)
+
+// docstringRaises extracts exception names from a Google-style "Raises:" docstring section.
+func docstringRaises(docstring string) []string {
+	var raises []string
+	inSection := false
+	sectionIndent, entryIndent := 0, -1
+
+	for _, line := range strings.Split(docstring, "\n") {
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+
+		if !inSection {
+			if trimmed == "Raises:" {
+				inSection = true
+				sectionIndent = indent
+				entryIndent = -1
+			}
+			continue
+		}
+
+		if trimmed == "" || indent <= sectionIndent {
+			inSection = trimmed == "Raises:"
+			continue
+		}
+		if entryIndent == -1 {
+			entryIndent = indent
+		}
+		if indent != entryIndent {
+			// Continuation line of the previous entry
+			continue
+		}
+		if match := docstringRaisesEntryRegex.FindStringSubmatch(trimmed); match != nil {
+			raises = appendUnique(raises, match[1])
+		}
+	}
+	return raises
+}
+
 // buildSignature builds a signature string from metadata.
 func (s *PythonStrategy) buildSignature(meta *chunkers.CodeMetadata) string {
 	var sig strings.Builder
@@ -0,0 +1,74 @@
+package languages
+
+import (
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// jsNestedScopes are JavaScript/TypeScript nodes whose throws belong to their own chunk.
+var jsNestedScopes = map[string]bool{
+	"function_declaration":           true,
+	"generator_function_declaration": true,
+	"function_expression":            true,
+	"function":                       true,
+	"generator_function":             true,
+	"arrow_function":                 true,
+	"method_definition":              true,
+	"class_declaration":              true,
+	"class":                          true,
+}
+
+// walkBody calls visit for each named descendant of node. Nodes whose type is
+// in skip are neither visited nor descended into, so nested functions and
+// classes do not contribute to their enclosing function's metadata.
+func walkBody(node *sitter.Node, skip map[string]bool, visit func(*sitter.Node)) {
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		if skip[child.Type()] {
+			continue
+		}
+		visit(child)
+		walkBody(child, skip, visit)
+	}
+}
+
+// appendUnique appends values that are not already present in list.
+func appendUnique(list []string, values ...string) []string {
+	for _, value := range values {
+		found := false
+		for _, existing := range list {
+			if existing == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+// jsThrows returns the error types constructed by throw statements in a
+// function body (e.g., "TypeError" for throw new TypeError("bad")). Shared by
+// the JavaScript and TypeScript strategies.
+func jsThrows(node *sitter.Node, source []byte) []string {
+	body := node.ChildByFieldName("body")
+	if body == nil {
+		return nil
+	}
+
+	var raises []string
+	walkBody(body, jsNestedScopes, func(n *sitter.Node) {
+		if n.Type() != "throw_statement" {
+			return
+		}
+		expr := n.NamedChild(0)
+		if expr == nil || expr.Type() != "new_expression" {
+			return
+		}
+		if ctor := expr.ChildByFieldName("constructor"); ctor != nil {
+			raises = appendUnique(raises, string(source[ctor.StartByte():ctor.EndByte()]))
+		}
+	})
+	return raises
+}
@@ -0,0 +1,377 @@
+package languages
+
+import (
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/ruby"
+
+	"github.com/leefowlercu/agentic-memorizer/internal/chunkers"
+	"github.com/leefowlercu/agentic-memorizer/internal/chunkers/code"
+)
+
+// RubyStrategy implements tree-sitter parsing for Ruby code, Rake tasks and
+// Gemfiles.
+type RubyStrategy struct{}
+
+// NewRubyStrategy creates a new Ruby language strategy.
+func NewRubyStrategy() *RubyStrategy {
+	return &RubyStrategy{}
+}
+
+// Language returns the language identifier.
+func (s *RubyStrategy) Language() string {
+	return "ruby"
+}
+
+// Extensions returns file extensions this strategy handles. Gemfile and
+// Rakefile have no extension and are matched by file name.
+func (s *RubyStrategy) Extensions() []string {
+	return []string{".rb", ".rake", "Gemfile", "Rakefile"}
+}
+
+// MIMETypes returns MIME types this strategy handles.
+func (s *RubyStrategy) MIMETypes() []string {
+	return []string{
+		"text/x-ruby",
+		"application/x-ruby",
+	}
+}
+
+// GetLanguage returns the tree-sitter Language for Ruby.
+func (s *RubyStrategy) GetLanguage() *sitter.Language {
+	return ruby.GetLanguage()
+}
+
+// NodeTypes returns Ruby-specific node type configuration.
+func (s *RubyStrategy) NodeTypes() code.NodeTypeConfig {
+	return code.NodeTypeConfig{
+		Functions: []string{
+			"method",
+			"singleton_method",
+		},
+		Methods: []string{},
+		Classes: []string{
+			"class",
+			"module",
+		},
+		Declarations: []string{},
+		TopLevel:     []string{},
+	}
+}
+
+// ShouldChunk determines if a node should be its own chunk.
+func (s *RubyStrategy) ShouldChunk(node *sitter.Node) bool {
+	// The class and module keywords share their definition's node type
+	if !node.IsNamed() {
+		return false
+	}
+
+	switch node.Type() {
+	case "class":
+		return true
+	case "module":
+		// Modules that only namespace other types are descended into so
+		// each nested class or module becomes its own chunk
+		return !s.containsTypes(node)
+	case "method", "singleton_method":
+		// Methods are only reached at top level or inside a namespacing
+		// module; class members stay with their class
+		return true
+	}
+	return false
+}
+
+// ExtractMetadata extracts Ruby-specific metadata from an AST node.
+func (s *RubyStrategy) ExtractMetadata(node *sitter.Node, source []byte) *chunkers.CodeMetadata {
+	meta := &chunkers.CodeMetadata{
+		Language:  "ruby",
+		LineStart: int(node.StartPoint().Row) + 1,
+		LineEnd:   int(node.EndPoint().Row) + 1,
+	}
+
+	switch node.Type() {
+	case "class":
+		s.extractClassMetadata(node, source, meta)
+	case "module":
+		s.extractModuleMetadata(node, source, meta)
+	case "method", "singleton_method":
+		s.extractMethodMetadata(node, source, meta)
+	}
+
+	return meta
+}
+
+// extractClassMetadata extracts metadata from a class definition.
+func (s *RubyStrategy) extractClassMetadata(node *sitter.Node, source []byte, meta *chunkers.CodeMetadata) {
+	if name := node.ChildByFieldName("name"); name != nil {
+		meta.ClassName = string(source[name.StartByte():name.EndByte()])
+	}
+	if superclass := node.ChildByFieldName("superclass"); superclass != nil {
+		for i := 0; i < int(superclass.NamedChildCount()); i++ {
+			parent := superclass.NamedChild(i)
+			meta.ParentClass = string(source[parent.StartByte():parent.EndByte()])
+		}
+	}
+
+	meta.Namespace = s.namespace(node, source)
+	meta.Implements = s.extractMixins(node, source)
+	meta.Visibility = "public"
+	meta.IsExported = true
+	meta.Docstring = s.extractComments(node, source)
+}
+
+// extractModuleMetadata extracts metadata from a module definition. The
+// module's own name is the last segment of its namespace.
+func (s *RubyStrategy) extractModuleMetadata(node *sitter.Node, source []byte, meta *chunkers.CodeMetadata) {
+	namespace := s.namespace(node, source)
+	if name := node.ChildByFieldName("name"); name != nil {
+		text := string(source[name.StartByte():name.EndByte()])
+		if namespace != "" {
+			namespace += "::" + text
+		} else {
+			namespace = text
+		}
+	}
+
+	meta.Namespace = namespace
+	meta.Implements = s.extractMixins(node, source)
+	meta.Visibility = "public"
+	meta.IsExported = true
+	meta.Docstring = s.extractComments(node, source)
+}
+
+// extractMethodMetadata extracts metadata from an instance or singleton method.
+func (s *RubyStrategy) extractMethodMetadata(node *sitter.Node, source []byte, meta *chunkers.CodeMetadata) {
+	if name := node.ChildByFieldName("name"); name != nil {
+		meta.FunctionName = string(source[name.StartByte():name.EndByte()])
+	}
+	if params := node.ChildByFieldName("parameters"); params != nil {
+		meta.Parameters = s.extractParameters(params, source)
+	}
+
+	class, inSingletonClass := s.enclosingClass(node)
+	if class != nil {
+		if name := class.ChildByFieldName("name"); name != nil {
+			meta.ClassName = string(source[name.StartByte():name.EndByte()])
+		}
+	}
+	meta.Namespace = s.namespace(node, source)
+	meta.IsStatic = node.Type() == "singleton_method" || inSingletonClass
+
+	meta.Visibility = s.extractVisibility(node, source)
+	meta.IsExported = meta.Visibility == "public"
+	meta.IsConstructor = meta.FunctionName == "initialize"
+	meta.IsTest = strings.HasPrefix(meta.FunctionName, "test_")
+
+	meta.Signature = s.buildMethodSignature(node, source, meta)
+	meta.Docstring = s.extractComments(node, source)
+}
+
+// extractParameters extracts parameters with their Ruby sigils, so keyword
+// arguments read "name:", splats "*args" and "**opts", and blocks "&block".
+// Default values are omitted.
+func (s *RubyStrategy) extractParameters(params *sitter.Node, source []byte) []string {
+	var result []string
+
+	for i := 0; i < int(params.NamedChildCount()); i++ {
+		param := params.NamedChild(i)
+
+		var name string
+		if n := param.ChildByFieldName("name"); n != nil {
+			name = string(source[n.StartByte():n.EndByte()])
+		}
+
+		switch param.Type() {
+		case "identifier":
+			result = append(result, string(source[param.StartByte():param.EndByte()]))
+		case "optional_parameter":
+			result = append(result, name)
+		case "keyword_parameter":
+			result = append(result, name+":")
+		case "splat_parameter":
+			result = append(result, "*"+name)
+		case "hash_splat_parameter":
+			result = append(result, "**"+name)
+		case "block_parameter":
+			result = append(result, "&"+name)
+		case "comment":
+			continue
+		default:
+			// Destructured, forwarding and nil-keyword parameters
+			result = append(result, string(source[param.StartByte():param.EndByte()]))
+		}
+	}
+
+	return result
+}
+
+// extractVisibility determines a method's visibility. A method passed to
+// private, protected or public directly takes that visibility; otherwise the
+// last bare visibility call earlier in the same body applies. Bare calls do
+// not affect singleton methods.
+func (s *RubyStrategy) extractVisibility(node *sitter.Node, source []byte) string {
+	if args := node.Parent(); args != nil && args.Type() == "argument_list" {
+		if call := args.Parent(); call != nil && call.Type() == "call" {
+			if method := call.ChildByFieldName("method"); method != nil {
+				if v := string(source[method.StartByte():method.EndByte()]); isRubyVisibility(v) {
+					return v
+				}
+			}
+		}
+	}
+
+	if node.Type() == "singleton_method" {
+		return "public"
+	}
+
+	for prev := node.PrevNamedSibling(); prev != nil; prev = prev.PrevNamedSibling() {
+		if prev.Type() != "identifier" {
+			continue
+		}
+		if v := string(source[prev.StartByte():prev.EndByte()]); isRubyVisibility(v) {
+			return v
+		}
+	}
+	return "public"
+}
+
+// extractMixins extracts modules mixed in with include, extend or prepend.
+func (s *RubyStrategy) extractMixins(node *sitter.Node, source []byte) []string {
+	body := node.ChildByFieldName("body")
+	if body == nil {
+		return nil
+	}
+
+	var mixins []string
+	for i := 0; i < int(body.NamedChildCount()); i++ {
+		child := body.NamedChild(i)
+		if child.Type() != "call" || child.ChildByFieldName("receiver") != nil {
+			continue
+		}
+		method := child.ChildByFieldName("method")
+		if method == nil {
+			continue
+		}
+		switch string(source[method.StartByte():method.EndByte()]) {
+		case "include", "extend", "prepend":
+		default:
+			continue
+		}
+		if args := child.ChildByFieldName("arguments"); args != nil {
+			for j := 0; j < int(args.NamedChildCount()); j++ {
+				arg := args.NamedChild(j)
+				mixins = appendUnique(mixins, string(source[arg.StartByte():arg.EndByte()]))
+			}
+		}
+	}
+
+	return mixins
+}
+
+// extractComments extracts the run of # comments directly above a node. The
+// grammar attaches comments preceding the first statement of a body to the
+// enclosing class or module, so those are checked as well.
+func (s *RubyStrategy) extractComments(node *sitter.Node, source []byte) string {
+	prev := node.PrevSibling()
+	if prev == nil {
+		if body := node.Parent(); body != nil && body.Type() == "body_statement" {
+			prev = body.PrevSibling()
+		}
+	}
+
+	var lines []string
+	row := node.StartPoint().Row
+	for prev != nil && prev.Type() == "comment" && prev.EndPoint().Row+1 >= row {
+		text := string(source[prev.StartByte():prev.EndByte()])
+		if !strings.HasPrefix(text, "#") {
+			break
+		}
+		if line := strings.TrimSpace(strings.TrimLeft(text, "#")); line != "" {
+			lines = append([]string{line}, lines...)
+		}
+		row = prev.StartPoint().Row
+		prev = prev.PrevSibling()
+	}
+
+	return strings.Join(lines, " ")
+}
+
+// buildMethodSignature builds a method signature string.
+func (s *RubyStrategy) buildMethodSignature(node *sitter.Node, source []byte, meta *chunkers.CodeMetadata) string {
+	var sig strings.Builder
+
+	if meta.Visibility != "public" {
+		sig.WriteString(meta.Visibility)
+		sig.WriteString(" ")
+	}
+	sig.WriteString("def ")
+	if object := node.ChildByFieldName("object"); object != nil {
+		sig.WriteString(string(source[object.StartByte():object.EndByte()]))
+		sig.WriteString(".")
+	}
+	sig.WriteString(meta.FunctionName)
+	if len(meta.Parameters) > 0 {
+		sig.WriteString("(")
+		sig.WriteString(strings.Join(meta.Parameters, ", "))
+		sig.WriteString(")")
+	}
+
+	return sig.String()
+}
+
+// enclosingClass returns the nearest class containing node, or nil when the
+// node belongs to a module or the top level. The second result reports
+// whether the node sits inside a class << self block.
+func (s *RubyStrategy) enclosingClass(node *sitter.Node) (*sitter.Node, bool) {
+	inSingletonClass := false
+	for parent := node.Parent(); parent != nil; parent = parent.Parent() {
+		switch parent.Type() {
+		case "class":
+			return parent, inSingletonClass
+		case "singleton_class":
+			inSingletonClass = true
+		case "module", "method", "singleton_method", "program":
+			return nil, inSingletonClass
+		}
+	}
+	return nil, inSingletonClass
+}
+
+// namespace returns the "::" joined names of the modules enclosing node.
+func (s *RubyStrategy) namespace(node *sitter.Node, source []byte) string {
+	var names []string
+	for parent := node.Parent(); parent != nil; parent = parent.Parent() {
+		if parent.Type() != "module" {
+			continue
+		}
+		if name := parent.ChildByFieldName("name"); name != nil {
+			names = append([]string{string(source[name.StartByte():name.EndByte()])}, names...)
+		}
+	}
+	return strings.Join(names, "::")
+}
+
+// containsTypes reports whether a class or module body directly defines a
+// nested class or module.
+func (s *RubyStrategy) containsTypes(node *sitter.Node) bool {
+	body := node.ChildByFieldName("body")
+	if body == nil {
+		return false
+	}
+	for i := 0; i < int(body.NamedChildCount()); i++ {
+		switch body.NamedChild(i).Type() {
+		case "class", "module":
+			return true
+		}
+	}
+	return false
+}
+
+// isRubyVisibility reports whether name is a Ruby visibility method.
+func isRubyVisibility(name string) bool {
+	return name == "private" || name == "protected" || name == "public"
+}
+
+// Ensure RubyStrategy implements LanguageStrategy.
+var _ code.LanguageStrategy = (*RubyStrategy)(nil)
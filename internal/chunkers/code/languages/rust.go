@@ -165,6 +165,9 @@ func (s *RustStrategy) extractFunctionMetadata(node *sitter.Node, source []byte,
 		}
 	}
 
+	// Associated fn new() inside an impl block is the conventional constructor
+	meta.IsConstructor = meta.ClassName != "" && meta.FunctionName == "new"
+
 	// Check for async
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
@@ -0,0 +1,281 @@
+package languages
+
+import (
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/scala"
+
+	"github.com/leefowlercu/agentic-memorizer/internal/chunkers"
+	"github.com/leefowlercu/agentic-memorizer/internal/chunkers/code"
+)
+
+// ScalaStrategy implements tree-sitter parsing for Scala code and worksheets.
+type ScalaStrategy struct{}
+
+// NewScalaStrategy creates a new Scala language strategy.
+func NewScalaStrategy() *ScalaStrategy {
+	return &ScalaStrategy{}
+}
+
+// Language returns the language identifier.
+func (s *ScalaStrategy) Language() string {
+	return "scala"
+}
+
+// Extensions returns file extensions this strategy handles.
+func (s *ScalaStrategy) Extensions() []string {
+	return []string{".scala", ".sc"}
+}
+
+// MIMETypes returns MIME types this strategy handles.
+func (s *ScalaStrategy) MIMETypes() []string {
+	return []string{
+		"text/x-scala",
+	}
+}
+
+// GetLanguage returns the tree-sitter Language for Scala.
+func (s *ScalaStrategy) GetLanguage() *sitter.Language {
+	return scala.GetLanguage()
+}
+
+// NodeTypes returns Scala-specific node type configuration.
+func (s *ScalaStrategy) NodeTypes() code.NodeTypeConfig {
+	return code.NodeTypeConfig{
+		Functions: []string{
+			"function_definition",
+		},
+		Methods: []string{},
+		Classes: []string{
+			"class_definition",
+			"object_definition",
+			"trait_definition",
+		},
+		Declarations: []string{},
+		TopLevel:     []string{},
+	}
+}
+
+// ShouldChunk determines if a node should be its own chunk.
+func (s *ScalaStrategy) ShouldChunk(node *sitter.Node) bool {
+	switch node.Type() {
+	case "class_definition", "object_definition", "trait_definition":
+		return true
+	case "function_definition":
+		// Chunk top-level functions and template members
+		parent := node.Parent()
+		return parent != nil && (parent.Type() == "compilation_unit" || parent.Type() == "template_body")
+	}
+	return false
+}
+
+// ExtractMetadata extracts Scala-specific metadata from an AST node.
+func (s *ScalaStrategy) ExtractMetadata(node *sitter.Node, source []byte) *chunkers.CodeMetadata {
+	meta := &chunkers.CodeMetadata{
+		Language:  "scala",
+		LineStart: int(node.StartPoint().Row) + 1,
+		LineEnd:   int(node.EndPoint().Row) + 1,
+	}
+
+	switch node.Type() {
+	case "class_definition", "object_definition", "trait_definition":
+		s.extractTypeMetadata(node, source, meta)
+	case "function_definition":
+		s.extractFunctionMetadata(node, source, meta)
+	}
+
+	meta.Namespace = s.packageName(node, source)
+	meta.Docstring = s.extractScaladoc(node, source)
+
+	return meta
+}
+
+// extractTypeMetadata extracts metadata from a class, object or trait
+// definition. The signature names the definition kind, so a companion object
+// reads "object User" next to its "case class User".
+func (s *ScalaStrategy) extractTypeMetadata(node *sitter.Node, source []byte, meta *chunkers.CodeMetadata) {
+	meta.ClassName = s.nameOf(node, source)
+
+	// The first extended type is the parent; types mixed in with "with"
+	// are recorded as implemented traits
+	if extends := node.ChildByFieldName("extend"); extends != nil {
+		for i := 0; i < int(extends.ChildCount()); i++ {
+			parent := extends.Child(i)
+			if !parent.IsNamed() || extends.FieldNameForChild(i) != "type" {
+				continue
+			}
+			text := string(source[parent.StartByte():parent.EndByte()])
+			if meta.ParentClass == "" {
+				meta.ParentClass = text
+			} else {
+				meta.Implements = append(meta.Implements, text)
+			}
+		}
+	}
+
+	s.extractModifiers(node, source, meta)
+
+	var kind []string
+	for i := 0; i < int(node.ChildCount()); i++ {
+		switch child := node.Child(i); child.Type() {
+		case "case", "class", "object", "trait":
+			kind = append(kind, child.Type())
+		}
+	}
+	meta.Signature = strings.Join(kind, " ") + " " + meta.ClassName
+}
+
+// extractFunctionMetadata extracts metadata from a function definition.
+func (s *ScalaStrategy) extractFunctionMetadata(node *sitter.Node, source []byte, meta *chunkers.CodeMetadata) {
+	meta.FunctionName = s.nameOf(node, source)
+	if body := node.Parent(); body != nil && body.Type() == "template_body" {
+		// Package blocks share the template body node with classes
+		if owner := body.Parent(); owner != nil && owner.Type() != "package_clause" {
+			meta.ClassName = s.nameOf(owner, source)
+		}
+	}
+	if returnType := node.ChildByFieldName("return_type"); returnType != nil {
+		meta.ReturnType = string(source[returnType.StartByte():returnType.EndByte()])
+	}
+
+	// Curried and implicit parameter lists are flattened into Parameters and
+	// kept separate in the signature
+	var lists []string
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		params := node.NamedChild(i)
+		if params.Type() != "parameters" {
+			continue
+		}
+		var list []string
+		for j := 0; j < int(params.NamedChildCount()); j++ {
+			param := params.NamedChild(j)
+			if param.Type() != "parameter" {
+				continue
+			}
+			text := s.formatParameter(param, source)
+			list = append(list, text)
+			meta.Parameters = append(meta.Parameters, text)
+		}
+		prefix := ""
+		if s.findChild(params, "implicit") != nil {
+			prefix = "implicit "
+		}
+		lists = append(lists, "("+prefix+strings.Join(list, ", ")+")")
+	}
+
+	s.extractModifiers(node, source, meta)
+
+	var sig strings.Builder
+	if meta.Visibility != "public" {
+		sig.WriteString(meta.Visibility)
+		sig.WriteString(" ")
+	}
+	sig.WriteString("def ")
+	sig.WriteString(meta.FunctionName)
+	sig.WriteString(strings.Join(lists, ""))
+	if meta.ReturnType != "" {
+		sig.WriteString(": ")
+		sig.WriteString(meta.ReturnType)
+	}
+	meta.Signature = sig.String()
+}
+
+// extractModifiers extracts visibility from access modifiers, dropping any
+// qualifier such as private[users]. Scala definitions are public unless
+// declared otherwise.
+func (s *ScalaStrategy) extractModifiers(node *sitter.Node, source []byte, meta *chunkers.CodeMetadata) {
+	meta.Visibility = "public"
+	if modifiers := s.findChild(node, "modifiers"); modifiers != nil {
+		if access := s.findChild(modifiers, "access_modifier"); access != nil {
+			text := string(source[access.StartByte():access.EndByte()])
+			if i := strings.Index(text, "["); i >= 0 {
+				text = text[:i]
+			}
+			meta.Visibility = strings.TrimSpace(text)
+		}
+	}
+	meta.IsExported = meta.Visibility == "public"
+}
+
+// formatParameter formats a parameter as "name: Type".
+func (s *ScalaStrategy) formatParameter(param *sitter.Node, source []byte) string {
+	var name, paramType string
+	if n := param.ChildByFieldName("name"); n != nil {
+		name = string(source[n.StartByte():n.EndByte()])
+	}
+	if t := param.ChildByFieldName("type"); t != nil {
+		paramType = string(source[t.StartByte():t.EndByte()])
+	}
+	if paramType == "" {
+		return name
+	}
+	return name + ": " + paramType
+}
+
+// extractScaladoc extracts the Scaladoc comment preceding a node.
+func (s *ScalaStrategy) extractScaladoc(node *sitter.Node, source []byte) string {
+	prev := node.PrevSibling()
+	if prev == nil || prev.Type() != "block_comment" {
+		return ""
+	}
+
+	comment := string(source[prev.StartByte():prev.EndByte()])
+	if !strings.HasPrefix(comment, "/**") {
+		return ""
+	}
+	comment = strings.TrimPrefix(comment, "/**")
+	comment = strings.TrimSuffix(comment, "*/")
+	var cleaned []string
+	for _, line := range strings.Split(comment, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimPrefix(line, "*")
+		line = strings.TrimSpace(line)
+		// Skip @param, @return tags for summary
+		if !strings.HasPrefix(line, "@") && line != "" {
+			cleaned = append(cleaned, line)
+		}
+	}
+	return strings.Join(cleaned, " ")
+}
+
+// packageName returns the package a node is declared in, joining chained
+// and nested package clauses with ".".
+func (s *ScalaStrategy) packageName(node *sitter.Node, source []byte) string {
+	var names []string
+	for current := node; current != nil; current = current.Parent() {
+		var scoped []string
+		for prev := current.PrevNamedSibling(); prev != nil; prev = prev.PrevNamedSibling() {
+			if prev.Type() == "package_clause" && prev.ChildByFieldName("body") == nil {
+				scoped = append([]string{s.nameOf(prev, source)}, scoped...)
+			}
+		}
+		if current.Type() == "package_clause" && current.ChildByFieldName("body") != nil {
+			scoped = append(scoped, s.nameOf(current, source))
+		}
+		names = append(scoped, names...)
+	}
+	return strings.Join(names, ".")
+}
+
+// nameOf returns the text of a node's name field, or an empty string.
+func (s *ScalaStrategy) nameOf(node *sitter.Node, source []byte) string {
+	if name := node.ChildByFieldName("name"); name != nil {
+		return string(source[name.StartByte():name.EndByte()])
+	}
+	return ""
+}
+
+// findChild finds the first child with the given type.
+func (s *ScalaStrategy) findChild(node *sitter.Node, nodeType string) *sitter.Node {
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		if child.Type() == nodeType {
+			return child
+		}
+	}
+	return nil
+}
+
+// Ensure ScalaStrategy implements LanguageStrategy.
+var _ code.LanguageStrategy = (*ScalaStrategy)(nil)
@@ -0,0 +1,333 @@
+package languages
+
+import (
+	"slices"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/swift"
+
+	"github.com/leefowlercu/agentic-memorizer/internal/chunkers"
+	"github.com/leefowlercu/agentic-memorizer/internal/chunkers/code"
+)
+
+// SwiftStrategy implements tree-sitter parsing for Swift code. The grammar
+// parses classes, structs, enums and extensions as class_declaration nodes
+// told apart by their declaration kind.
+type SwiftStrategy struct{}
+
+// NewSwiftStrategy creates a new Swift language strategy.
+func NewSwiftStrategy() *SwiftStrategy {
+	return &SwiftStrategy{}
+}
+
+// Language returns the language identifier.
+func (s *SwiftStrategy) Language() string {
+	return "swift"
+}
+
+// Extensions returns file extensions this strategy handles.
+func (s *SwiftStrategy) Extensions() []string {
+	return []string{".swift"}
+}
+
+// MIMETypes returns MIME types this strategy handles.
+func (s *SwiftStrategy) MIMETypes() []string {
+	return []string{
+		"text/x-swift",
+	}
+}
+
+// GetLanguage returns the tree-sitter Language for Swift.
+func (s *SwiftStrategy) GetLanguage() *sitter.Language {
+	return swift.GetLanguage()
+}
+
+// NodeTypes returns Swift-specific node type configuration.
+func (s *SwiftStrategy) NodeTypes() code.NodeTypeConfig {
+	return code.NodeTypeConfig{
+		Functions: []string{
+			"function_declaration",
+		},
+		Methods: []string{},
+		Classes: []string{
+			"class_declaration",
+			"protocol_declaration",
+		},
+		Declarations: []string{},
+		TopLevel:     []string{},
+	}
+}
+
+// ShouldChunk determines if a node should be its own chunk.
+func (s *SwiftStrategy) ShouldChunk(node *sitter.Node) bool {
+	switch node.Type() {
+	case "class_declaration", "protocol_declaration":
+		return true
+	case "function_declaration":
+		// Chunk top-level functions and type members
+		parent := node.Parent()
+		return parent != nil && (parent.Type() == "source_file" || s.enclosingType(node) != nil)
+	}
+	return false
+}
+
+// ExtractMetadata extracts Swift-specific metadata from an AST node.
+func (s *SwiftStrategy) ExtractMetadata(node *sitter.Node, source []byte) *chunkers.CodeMetadata {
+	meta := &chunkers.CodeMetadata{
+		Language:  "swift",
+		LineStart: int(node.StartPoint().Row) + 1,
+		LineEnd:   int(node.EndPoint().Row) + 1,
+	}
+
+	switch node.Type() {
+	case "class_declaration", "protocol_declaration":
+		s.extractTypeMetadata(node, source, meta)
+	case "function_declaration":
+		s.extractFunctionMetadata(node, source, meta)
+	}
+
+	s.extractModifiers(node, source, meta)
+	meta.Docstring = s.extractDocComment(node, source)
+
+	return meta
+}
+
+// extractTypeMetadata extracts metadata from a class, struct, enum, protocol
+// or extension declaration. The signature starts with the declaration kind,
+// so an extension reads "extension User" while the type itself reads
+// "class User".
+func (s *SwiftStrategy) extractTypeMetadata(node *sitter.Node, source []byte, meta *chunkers.CodeMetadata) {
+	meta.ClassName = s.typeName(node, source)
+
+	// Swift does not mark superclasses, so a class's first inherited type is
+	// taken as its parent and everything else as a conformance
+	kind := s.declarationKind(node, source)
+	first := true
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		if child.Type() != "inheritance_specifier" {
+			continue
+		}
+		text := string(source[child.StartByte():child.EndByte()])
+		if kind == "class" && first {
+			meta.ParentClass = text
+			first = false
+			continue
+		}
+		first = false
+		meta.Implements = append(meta.Implements, text)
+	}
+
+	meta.Signature = kind + " " + meta.ClassName
+}
+
+// extractFunctionMetadata extracts metadata from a function declaration.
+func (s *SwiftStrategy) extractFunctionMetadata(node *sitter.Node, source []byte, meta *chunkers.CodeMetadata) {
+	if class := s.enclosingType(node); class != nil {
+		meta.ClassName = s.typeName(class, source)
+	}
+
+	var effects []string
+	afterArrow := false
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		switch child.Type() {
+		case "simple_identifier":
+			if meta.FunctionName == "" {
+				meta.FunctionName = string(source[child.StartByte():child.EndByte()])
+			}
+		case "parameter":
+			meta.Parameters = append(meta.Parameters, s.formatParameter(child, source))
+		case "async":
+			meta.IsAsync = true
+			effects = append(effects, "async")
+		case "throws":
+			effects = append(effects, string(source[child.StartByte():child.EndByte()]))
+		case "->":
+			afterArrow = true
+		default:
+			// The return type shares the "name" field with the function name
+			if afterArrow && child.IsNamed() && meta.ReturnType == "" && node.FieldNameForChild(i) == "name" {
+				meta.ReturnType = string(source[child.StartByte():child.EndByte()])
+			}
+		}
+	}
+
+	meta.Decorators = s.extractAttributes(node, source)
+	meta.IsTest = meta.ClassName != "" && strings.HasPrefix(meta.FunctionName, "test")
+	meta.Signature = s.buildFunctionSignature(node, source, meta, effects)
+}
+
+// extractModifiers extracts visibility and static modifiers. Swift
+// declarations are internal unless declared otherwise.
+func (s *SwiftStrategy) extractModifiers(node *sitter.Node, source []byte, meta *chunkers.CodeMetadata) {
+	meta.Visibility = "internal"
+	if modifiers := s.findChild(node, "modifiers"); modifiers != nil {
+		for i := 0; i < int(modifiers.NamedChildCount()); i++ {
+			child := modifiers.NamedChild(i)
+			text := string(source[child.StartByte():child.EndByte()])
+			switch child.Type() {
+			case "visibility_modifier":
+				meta.Visibility = text
+			case "property_modifier":
+				if text == "static" || text == "class" {
+					meta.IsStatic = true
+				}
+			}
+		}
+	}
+	meta.IsExported = meta.Visibility == "public" || meta.Visibility == "open"
+}
+
+// formatParameter formats a parameter as "label name: Type", keeping the
+// variadic marker and dropping default values.
+func (s *SwiftStrategy) formatParameter(param *sitter.Node, source []byte) string {
+	var label, name, paramType string
+	variadic := false
+	for i := 0; i < int(param.ChildCount()); i++ {
+		child := param.Child(i)
+		text := string(source[child.StartByte():child.EndByte()])
+		switch {
+		case param.FieldNameForChild(i) == "external_name":
+			label = text
+		case child.Type() == "simple_identifier" && name == "":
+			name = text
+		case child.Type() == "...":
+			variadic = true
+		case child.IsNamed() && paramType == "":
+			paramType = text
+		}
+	}
+
+	result := name
+	if label != "" {
+		result = label + " " + name
+	}
+	if paramType != "" {
+		result += ": " + paramType
+	}
+	if variadic {
+		result += "..."
+	}
+	return result
+}
+
+// extractAttributes extracts attribute names such as discardableResult.
+func (s *SwiftStrategy) extractAttributes(node *sitter.Node, source []byte) []string {
+	modifiers := s.findChild(node, "modifiers")
+	if modifiers == nil {
+		return nil
+	}
+
+	var attributes []string
+	for i := 0; i < int(modifiers.NamedChildCount()); i++ {
+		child := modifiers.NamedChild(i)
+		if child.Type() != "attribute" {
+			continue
+		}
+		if userType := s.findChild(child, "user_type"); userType != nil {
+			attributes = append(attributes, string(source[userType.StartByte():userType.EndByte()]))
+		}
+	}
+
+	return attributes
+}
+
+// extractDocComment extracts the /// or /** */ documentation preceding a node.
+func (s *SwiftStrategy) extractDocComment(node *sitter.Node, source []byte) string {
+	var lines []string
+	for prev := node.PrevSibling(); prev != nil; prev = prev.PrevSibling() {
+		comment := string(source[prev.StartByte():prev.EndByte()])
+		if prev.Type() == "multiline_comment" && strings.HasPrefix(comment, "/**") && len(lines) == 0 {
+			comment = strings.TrimSuffix(strings.TrimPrefix(comment, "/**"), "*/")
+			for _, line := range strings.Split(comment, "\n") {
+				line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "*"))
+				if line != "" {
+					lines = append(lines, line)
+				}
+			}
+			break
+		}
+		if prev.Type() != "comment" || !strings.HasPrefix(comment, "///") {
+			break
+		}
+		if line := strings.TrimSpace(strings.TrimPrefix(comment, "///")); line != "" {
+			lines = append([]string{line}, lines...)
+		}
+	}
+	return strings.Join(lines, " ")
+}
+
+// buildFunctionSignature builds a function signature string.
+func (s *SwiftStrategy) buildFunctionSignature(node *sitter.Node, source []byte, meta *chunkers.CodeMetadata, effects []string) string {
+	var sig strings.Builder
+
+	if modifiers := s.findChild(node, "modifiers"); modifiers != nil {
+		for i := 0; i < int(modifiers.NamedChildCount()); i++ {
+			child := modifiers.NamedChild(i)
+			if child.Type() == "attribute" {
+				continue
+			}
+			sig.WriteString(string(source[child.StartByte():child.EndByte()]))
+			sig.WriteString(" ")
+		}
+	}
+	sig.WriteString("func ")
+	sig.WriteString(meta.FunctionName)
+	sig.WriteString("(")
+	sig.WriteString(strings.Join(meta.Parameters, ", "))
+	sig.WriteString(")")
+	for _, effect := range effects {
+		sig.WriteString(" ")
+		sig.WriteString(effect)
+	}
+	if meta.ReturnType != "" {
+		sig.WriteString(" -> ")
+		sig.WriteString(meta.ReturnType)
+	}
+
+	return sig.String()
+}
+
+// declarationKind returns the keyword a type was declared with, such as
+// "struct" or "extension".
+func (s *SwiftStrategy) declarationKind(node *sitter.Node, source []byte) string {
+	if kind := node.ChildByFieldName("declaration_kind"); kind != nil {
+		return string(source[kind.StartByte():kind.EndByte()])
+	}
+	return "class"
+}
+
+// typeName returns the declared name of a type, or the extended type's name
+// for an extension.
+func (s *SwiftStrategy) typeName(node *sitter.Node, source []byte) string {
+	if name := node.ChildByFieldName("name"); name != nil {
+		return string(source[name.StartByte():name.EndByte()])
+	}
+	return ""
+}
+
+// enclosingType returns the type or extension whose body directly contains
+// node, or nil.
+func (s *SwiftStrategy) enclosingType(node *sitter.Node) *sitter.Node {
+	body := node.Parent()
+	if body == nil || !slices.Contains([]string{"class_body", "enum_class_body"}, body.Type()) {
+		return nil
+	}
+	return body.Parent()
+}
+
+// findChild finds the first child with the given type.
+func (s *SwiftStrategy) findChild(node *sitter.Node, nodeType string) *sitter.Node {
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		if child.Type() == nodeType {
+			return child
+		}
+	}
+	return nil
+}
+
+// Ensure SwiftStrategy implements LanguageStrategy.
+var _ code.LanguageStrategy = (*SwiftStrategy)(nil)
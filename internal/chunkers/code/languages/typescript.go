@@ -67,6 +67,7 @@ func (s *TypeScriptStrategy) NodeTypes() code.NodeTypeConfig {
 		},
 		TopLevel: []string{
 			"export_statement",
+			"expression_statement",
 		},
 	}
 }
@@ -108,6 +109,13 @@ func (s *TypeScriptStrategy) ShouldChunk(node *sitter.Node) bool {
 			}
 		}
 		return false
+	case "expression_statement":
+		// Only chunk top-level calls that take a callback (describe/it/test blocks, route handlers)
+		parent := node.Parent()
+		if parent == nil || parent.Type() != "program" {
+			return false
+		}
+		return jsCallbackCall(node) != nil
 	case "export_statement":
 		// Only chunk export statements that contain declarations
 		for i := 0; i < int(node.ChildCount()); i++ {
@@ -150,6 +158,8 @@ func (s *TypeScriptStrategy) ExtractMetadata(node *sitter.Node, source []byte) *
 		s.extractMethodMetadata(node, source, meta)
 	case "export_statement":
 		s.extractExportMetadata(node, source, meta)
+	case "expression_statement":
+		extractJSCallMetadata(node, source, meta)
 	}
 
 	return meta
@@ -203,6 +213,9 @@ func (s *TypeScriptStrategy) extractFunctionMetadata(node *sitter.Node, source [
 		meta.ReturnType = strings.TrimSpace(meta.ReturnType)
 	}
 
+	// Collect thrown errors
+	meta.Raises = jsThrows(node, source)
+
 	// Build signature
 	meta.Signature = s.buildFunctionSignature(meta)
 
@@ -259,6 +272,9 @@ func (s *TypeScriptStrategy) extractArrowFunctionMetadata(node *sitter.Node, sou
 		meta.Parameters = s.extractParameters(params, source)
 	}
 
+	// Collect thrown errors
+	meta.Raises = jsThrows(node, source)
+
 	// Build signature
 	meta.Signature = s.buildFunctionSignature(meta)
 }
@@ -465,6 +481,9 @@ func (s *TypeScriptStrategy) extractMethodMetadata(node *sitter.Node, source []b
 		meta.ReturnType = strings.TrimSpace(meta.ReturnType)
 	}
 
+	// Collect thrown errors
+	meta.Raises = jsThrows(node, source)
+
 	// Build signature
 	meta.Signature = s.buildMethodSignature(meta)
 }
@@ -3,13 +3,15 @@ package code
 import (
 	"strings"
 	"sync"
+
+	"github.com/leefowlercu/agentic-memorizer/internal/chunkers"
 )
 
 // StrategyRegistry manages language strategies for the tree-sitter chunker.
 type StrategyRegistry struct {
 	mu           sync.RWMutex
 	strategies   map[string]LanguageStrategy // keyed by language name
-	extensionMap map[string]LanguageStrategy // keyed by extension (e.g., ".go")
+	extensionMap map[string]LanguageStrategy // keyed by normalized extension (e.g., "go")
 	mimeTypeMap  map[string]LanguageStrategy // keyed by MIME type
 }
 
@@ -32,7 +34,7 @@ func (r *StrategyRegistry) Register(strategy LanguageStrategy) {
 
 	// Register by extensions
 	for _, ext := range strategy.Extensions() {
-		r.extensionMap[strings.ToLower(ext)] = strategy
+		r.extensionMap[chunkers.NormalizeExtension(ext)] = strategy
 	}
 
 	// Register by MIME types
@@ -53,11 +55,7 @@ func (r *StrategyRegistry) GetByExtension(ext string) LanguageStrategy {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	ext = strings.ToLower(ext)
-	if !strings.HasPrefix(ext, ".") {
-		ext = "." + ext
-	}
-	return r.extensionMap[ext]
+	return r.extensionMap[chunkers.NormalizeExtension(ext)]
 }
 
 // GetByMIMEType returns a strategy by MIME type.
@@ -88,15 +86,10 @@ func (r *StrategyRegistry) Resolve(mimeType, language string) LanguageStrategy {
 			return s
 		}
 
-		// Try as extension
-		if s := r.extensionMap[lang]; s != nil {
+		// Try as extension, matching the same forms as chunkers.MatchesExtension
+		if s := r.extensionMap[chunkers.NormalizeExtension(lang)]; s != nil {
 			return s
 		}
-		if !strings.HasPrefix(lang, ".") {
-			if s := r.extensionMap["."+lang]; s != nil {
-				return s
-			}
-		}
 	}
 
 	return nil
@@ -30,6 +30,14 @@ type LanguageStrategy interface {
 	ShouldChunk(node *sitter.Node) bool
 }
 
+// HeaderStrategy is implemented by strategies whose file header is not made of
+// package and import declarations. When present, it replaces the built-in
+// header node types for that language.
+type HeaderStrategy interface {
+	// IsHeaderNode reports whether a top-level node belongs to the header.
+	IsHeaderNode(node *sitter.Node, source []byte) bool
+}
+
 // NodeTypeConfig defines which AST node types are significant for chunking.
 type NodeTypeConfig struct {
 	// Functions are node types that represent functions.
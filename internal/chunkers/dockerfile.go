@@ -70,6 +70,11 @@ func (c *DockerfileChunker) Priority() int {
 	return dockerfileChunkerPriority
 }
 
+// ChunkType returns the type of content this chunker produces.
+func (c *DockerfileChunker) ChunkType() ChunkType {
+	return ChunkTypeStructured
+}
+
 // Chunk splits Dockerfile content by FROM instructions (build stages).
 func (c *DockerfileChunker) Chunk(ctx context.Context, content []byte, opts ChunkOptions) (*ChunkResult, error) {
 	if len(content) == 0 {
@@ -84,7 +89,7 @@ func (c *DockerfileChunker) Chunk(ctx context.Context, content []byte, opts Chun
 
 	maxSize := opts.MaxChunkSize
 	if maxSize <= 0 {
-		maxSize = DefaultChunkOptions().MaxChunkSize
+		maxSize = DefaultChunkOptionsForType(c.ChunkType()).MaxChunkSize
 	}
 
 	text := string(content)
@@ -114,8 +119,9 @@ func (c *DockerfileChunker) Chunk(ctx context.Context, content []byte, opts Chun
 				StartOffset: offset,
 				EndOffset:   offset + len(stage.content),
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeStructured,
-					TokenEstimate: EstimateTokens(stage.content),
+					Type:               ChunkTypeStructured,
+					BoundaryConfidence: BoundaryConfidenceStructural,
+					TokenEstimate:      EstimateTokens(stage.content),
 					Build: &BuildMetadata{
 						StageName: stage.stageName,
 						BaseImage: stage.baseImage,
@@ -251,8 +257,9 @@ func (c *DockerfileChunker) splitLargeStage(ctx context.Context, stage dockerfil
 				StartOffset: offset - len(content),
 				EndOffset:   offset,
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeStructured,
-					TokenEstimate: EstimateTokens(content),
+					Type:               ChunkTypeStructured,
+					BoundaryConfidence: BoundaryConfidenceHeuristic,
+					TokenEstimate:      EstimateTokens(content),
 					Build: &BuildMetadata{
 						StageName: stage.stageName,
 						BaseImage: stage.baseImage,
@@ -277,8 +284,9 @@ func (c *DockerfileChunker) splitLargeStage(ctx context.Context, stage dockerfil
 			StartOffset: offset - len(content),
 			EndOffset:   offset,
 			Metadata: ChunkMetadata{
-				Type:          ChunkTypeStructured,
-				TokenEstimate: EstimateTokens(content),
+				Type:               ChunkTypeStructured,
+				BoundaryConfidence: BoundaryConfidenceHeuristic,
+				TokenEstimate:      EstimateTokens(content),
 				Build: &BuildMetadata{
 					StageName: stage.stageName,
 					BaseImage: stage.baseImage,
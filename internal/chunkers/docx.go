@@ -39,6 +39,11 @@ func (c *DOCXChunker) Priority() int {
 	return docxChunkerPriority
 }
 
+// ChunkType returns the type of content this chunker produces.
+func (c *DOCXChunker) ChunkType() ChunkType {
+	return ChunkTypeMarkdown
+}
+
 // Chunk splits DOCX content by heading boundaries.
 func (c *DOCXChunker) Chunk(ctx context.Context, content []byte, opts ChunkOptions) (*ChunkResult, error) {
 	if len(content) == 0 {
@@ -53,7 +58,7 @@ func (c *DOCXChunker) Chunk(ctx context.Context, content []byte, opts ChunkOptio
 
 	maxSize := opts.MaxChunkSize
 	if maxSize <= 0 {
-		maxSize = DefaultChunkOptions().MaxChunkSize
+		maxSize = DefaultChunkOptionsForType(c.ChunkType()).MaxChunkSize
 	}
 
 	// Open as ZIP
@@ -108,8 +113,9 @@ func (c *DOCXChunker) Chunk(ctx context.Context, content []byte, opts ChunkOptio
 				StartOffset: section.startOffset,
 				EndOffset:   section.endOffset,
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeMarkdown,
-					TokenEstimate: EstimateTokens(text),
+					Type:               ChunkTypeMarkdown,
+					BoundaryConfidence: BoundaryConfidenceStructural,
+					TokenEstimate:      EstimateTokens(text),
 					Document: &DocumentMetadata{
 						Heading:      section.heading,
 						HeadingLevel: section.level,
@@ -468,8 +474,9 @@ func (c *DOCXChunker) splitLargeSection(ctx context.Context, section docxSection
 				StartOffset: offset - len(content),
 				EndOffset:   offset,
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeMarkdown,
-					TokenEstimate: EstimateTokens(content),
+					Type:               ChunkTypeMarkdown,
+					BoundaryConfidence: BoundaryConfidenceHeuristic,
+					TokenEstimate:      EstimateTokens(content),
 					Document: &DocumentMetadata{
 						Heading:      section.heading,
 						HeadingLevel: section.level,
@@ -495,8 +502,9 @@ func (c *DOCXChunker) splitLargeSection(ctx context.Context, section docxSection
 			StartOffset: offset - len(content),
 			EndOffset:   offset,
 			Metadata: ChunkMetadata{
-				Type:          ChunkTypeMarkdown,
-				TokenEstimate: EstimateTokens(content),
+				Type:               ChunkTypeMarkdown,
+				BoundaryConfidence: BoundaryConfidenceHeuristic,
+				TokenEstimate:      EstimateTokens(content),
 				Document: &DocumentMetadata{
 					Heading:      section.heading,
 					HeadingLevel: section.level,
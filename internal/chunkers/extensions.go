@@ -0,0 +1,29 @@
+package chunkers
+
+import "strings"
+
+// NormalizeExtension reduces an extension, language hint, or file path to its
+// bare lowercase extension, so ".GO", "go", and "cmd/main.go" all yield "go".
+func NormalizeExtension(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	base := s[strings.LastIndexAny(s, `/\`)+1:]
+	if i := strings.LastIndex(base, "."); i >= 0 {
+		return base[i+1:]
+	}
+	return base
+}
+
+// MatchesExtension reports whether a language hint matches any of exts,
+// ignoring case and leading dots on either side.
+func MatchesExtension(language string, exts ...string) bool {
+	lang := NormalizeExtension(language)
+	if lang == "" {
+		return false
+	}
+	for _, ext := range exts {
+		if NormalizeExtension(ext) == lang {
+			return true
+		}
+	}
+	return false
+}
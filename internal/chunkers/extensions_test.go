@@ -0,0 +1,63 @@
+package chunkers
+
+import "testing"
+
+func TestNormalizeExtension(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{".go", "go"},
+		{".GO", "go"},
+		{"GO", "go"},
+		{" .Md ", "md"},
+		{"cmd/main.go", "go"},
+		{"docs.v2/Makefile", "makefile"},
+		{".h++", "h++"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeExtension(tt.input); got != tt.want {
+			t.Errorf("NormalizeExtension(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestMatchesExtension(t *testing.T) {
+	goExts := []string{".go"}
+	for _, lang := range []string{".GO", "go", ".go", "GO", "main.go"} {
+		if !MatchesExtension(lang, goExts...) {
+			t.Errorf("MatchesExtension(%q, %v) = false, want true", lang, goExts)
+		}
+	}
+	for _, lang := range []string{"", "golang", "mongo", ".gox"} {
+		if MatchesExtension(lang, goExts...) {
+			t.Errorf("MatchesExtension(%q, %v) = true, want false", lang, goExts)
+		}
+	}
+
+	// Extensions without a leading dot match as well
+	if !MatchesExtension(".GO", "go") {
+		t.Error("MatchesExtension should ignore the leading dot on extensions")
+	}
+}
+
+func TestCanHandleExtensionForms(t *testing.T) {
+	markdown := NewMarkdownChunker()
+	asciidoc := NewAsciiDocChunker()
+
+	for _, lang := range []string{".md", ".MD", "md", "README.md"} {
+		if !markdown.CanHandle("", lang) {
+			t.Errorf("MarkdownChunker.CanHandle(%q) = false, want true", lang)
+		}
+	}
+	for _, lang := range []string{".adoc", "ADOC", "guide.asciidoc", "asc"} {
+		if !asciidoc.CanHandle("", lang) {
+			t.Errorf("AsciiDocChunker.CanHandle(%q) = false, want true", lang)
+		}
+	}
+	if markdown.CanHandle("", ".adoc") || asciidoc.CanHandle("", ".md") {
+		t.Error("chunkers should not handle each other's extensions")
+	}
+}
@@ -2,6 +2,8 @@ package chunkers
 
 import (
 	"context"
+	"io"
+	"unicode/utf8"
 )
 
 const (
@@ -33,7 +35,14 @@ func (c *FallbackChunker) Priority() int {
 	return fallbackChunkerPriority
 }
 
-// Chunk splits content into fixed-size chunks with overlap.
+// ChunkType returns the type of content this chunker produces.
+func (c *FallbackChunker) ChunkType() ChunkType {
+	return ChunkTypeUnknown
+}
+
+// Chunk splits content into fixed-size chunks with overlap. A chunk closes at
+// MaxChunkSize bytes or, when MaxTokens is set, at MaxTokens tokens, whichever
+// comes first.
 func (c *FallbackChunker) Chunk(ctx context.Context, content []byte, opts ChunkOptions) (*ChunkResult, error) {
 	if len(content) == 0 {
 		return &ChunkResult{
@@ -45,21 +54,7 @@ func (c *FallbackChunker) Chunk(ctx context.Context, content []byte, opts ChunkO
 		}, nil
 	}
 
-	maxSize := opts.MaxChunkSize
-	if maxSize <= 0 {
-		maxSize = DefaultChunkOptions().MaxChunkSize
-	}
-
-	overlap := max(opts.Overlap, 0)
-	if overlap >= maxSize {
-		overlap = maxSize / 4
-	}
-
-	step := maxSize - overlap
-	if step <= 0 {
-		step = maxSize
-	}
-
+	limits, step, overlap := c.sizing(opts)
 	var chunks []Chunk
 	contentLen := len(content)
 
@@ -70,34 +65,9 @@ func (c *FallbackChunker) Chunk(ctx context.Context, content []byte, opts ChunkO
 		default:
 		}
 
-		end := min(offset+maxSize, contentLen)
-
-		// Try to break at whitespace if possible
-		if end < contentLen && end-offset > 100 {
-			breakPoint := findBreakPoint(content, offset, end)
-			if breakPoint > offset {
-				end = breakPoint
-			}
-		}
-
-		chunkContent := string(content[offset:end])
-		chunk := Chunk{
-			Index:       len(chunks),
-			Content:     chunkContent,
-			StartOffset: offset,
-			EndOffset:   end,
-			Metadata: ChunkMetadata{
-				Type:          ChunkTypeUnknown,
-				TokenEstimate: EstimateTokens(chunkContent),
-			},
-		}
-		chunks = append(chunks, chunk)
+		end, nextOffset := c.window(content, offset, limits, step, overlap)
+		chunks = append(chunks, c.newChunk(len(chunks), string(content[offset:end]), offset, end, limits))
 
-		// Move to next position
-		nextOffset := offset + step
-		if nextOffset <= offset {
-			nextOffset = offset + 1
-		}
 		if end >= contentLen {
 			break
 		}
@@ -113,6 +83,128 @@ func (c *FallbackChunker) Chunk(ctx context.Context, content []byte, opts ChunkO
 	}, nil
 }
 
+// ChunkStream splits content read from r into the same fixed-size chunks as
+// Chunk, holding little more than one window in memory at a time.
+func (c *FallbackChunker) ChunkStream(ctx context.Context, r io.Reader, opts ChunkOptions) (<-chan Chunk, <-chan error) {
+	limits, step, overlap := c.sizing(opts)
+
+	return streamChunks(ctx, func(emit func(Chunk) error) error {
+		var buf []byte
+		base, index := 0, 0
+		eof := false
+		for {
+			// Hold more than one window so cuts can look past its end
+			var err error
+			if !eof {
+				if buf, eof, err = fillBuffer(r, buf, limits.maxSize+1); err != nil {
+					return err
+				}
+			}
+			if len(buf) == 0 {
+				return nil
+			}
+
+			end, next := c.window(buf, 0, limits, step, overlap)
+			if err := emit(c.newChunk(index, string(buf[:end]), base, base+end, limits)); err != nil {
+				return err
+			}
+			index++
+
+			if eof && end >= len(buf) {
+				return nil
+			}
+			buf = buf[:copy(buf, buf[next:])]
+			base += next
+		}
+	})
+}
+
+// sizing returns the size limits, step between window starts, and overlap
+// for opts.
+func (c *FallbackChunker) sizing(opts ChunkOptions) (chunkLimits, int, int) {
+	maxSize := opts.MaxChunkSize
+	if maxSize <= 0 {
+		maxSize = DefaultChunkOptionsForType(c.ChunkType()).MaxChunkSize
+	}
+
+	overlap := max(opts.Overlap, 0)
+	if overlap >= maxSize {
+		overlap = maxSize / 4
+	}
+
+	step := maxSize - overlap
+	if step <= 0 {
+		step = maxSize
+	}
+
+	return chunkLimits{maxSize: maxSize, maxTokens: opts.MaxTokens, tokenizer: tokenizerFor(opts)}, step, overlap
+}
+
+// window returns the end of the chunk starting at offset and the offset of
+// the chunk after it. When content is cut off mid-stream it must extend past
+// offset+maxSize, so the end of content is never mistaken for the end of input.
+func (c *FallbackChunker) window(content []byte, offset int, limits chunkLimits, step, overlap int) (int, int) {
+	contentLen := len(content)
+	end := min(offset+limits.maxSize, contentLen)
+
+	// Never cut inside a multi-byte character
+	if end < contentLen {
+		end = runeStart(content, end)
+		if end <= offset {
+			end = nextRuneStart(content, offset+1)
+		}
+	}
+
+	// Close the window early when the token limit binds first
+	tokenLimited := false
+	if n := limits.tokenPrefix(string(content[offset:end])); offset+n < end {
+		end = offset + n
+		tokenLimited = true
+	}
+
+	// Try to break at whitespace if possible
+	if end < contentLen && end-offset > 100 {
+		breakPoint := findBreakPoint(content, offset, end)
+		if breakPoint > offset {
+			end = breakPoint
+		}
+	}
+
+	// Move to next position
+	nextOffset := runeStart(content, offset+step)
+	if tokenLimited {
+		// The window is shorter than maxSize, so overlap from its real end
+		windowOverlap := overlap
+		if windowOverlap >= end-offset {
+			windowOverlap = (end - offset) / 4
+		}
+		nextOffset = runeStart(content, end-windowOverlap)
+	}
+	if nextOffset <= offset {
+		nextOffset = nextRuneStart(content, offset+1)
+	}
+	// A whitespace break can pull end back past the next start, which would
+	// drop the bytes between them
+	nextOffset = min(nextOffset, end)
+
+	return end, nextOffset
+}
+
+// newChunk builds a fixed-window chunk.
+func (c *FallbackChunker) newChunk(index int, content string, start, end int, limits chunkLimits) Chunk {
+	return Chunk{
+		Index:       index,
+		Content:     content,
+		StartOffset: start,
+		EndOffset:   end,
+		Metadata: ChunkMetadata{
+			Type:               ChunkTypeUnknown,
+			TokenEstimate:      limits.tokenizer.EstimateTokens(content),
+			BoundaryConfidence: BoundaryConfidenceFixedWindow,
+		},
+	}
+}
+
 // findBreakPoint finds a good break point (whitespace) near the end of the range.
 func findBreakPoint(content []byte, start, end int) int {
 	// Search backwards from end for whitespace
@@ -131,3 +223,46 @@ func findBreakPoint(content []byte, start, end int) int {
 func isWhitespace(b byte) bool {
 	return b == ' ' || b == '\n' || b == '\r' || b == '\t'
 }
+
+// runeStart moves i back to the start of the UTF-8 sequence containing it.
+// Offsets outside content, and bytes that are not part of a valid-looking
+// sequence, are returned unchanged.
+func runeStart(content []byte, i int) int {
+	if i <= 0 || i >= len(content) {
+		return i
+	}
+	for j := i; j >= 0 && i-j < utf8.UTFMax; j-- {
+		if utf8.RuneStart(content[j]) {
+			return j
+		}
+	}
+	return i
+}
+
+// nextRuneStart moves i forward to the start of the next UTF-8 sequence, or
+// the end of content, when it falls inside one.
+func nextRuneStart(content []byte, i int) int {
+	for j := i; j-i < utf8.UTFMax; j++ {
+		if j >= len(content) {
+			return len(content)
+		}
+		if utf8.RuneStart(content[j]) {
+			return j
+		}
+	}
+	return i
+}
+
+// OverlapStart returns the offset in text where a trailing overlap of at most
+// overlap bytes begins, moved forward to a rune boundary. It returns len(text),
+// carrying nothing, when overlap is not positive or not smaller than text.
+func OverlapStart(text string, overlap int) int {
+	if overlap <= 0 || overlap >= len(text) {
+		return len(text)
+	}
+	i := len(text) - overlap
+	for i < len(text) && !utf8.RuneStart(text[i]) {
+		i++
+	}
+	return i
+}
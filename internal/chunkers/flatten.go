@@ -0,0 +1,84 @@
+package chunkers
+
+import (
+	"context"
+	"strings"
+)
+
+// flatChunkLimits returns the limits for size-based chunking: MaxChunkSize
+// bytes and, when set, MaxTokens counted with the configured tokenizer.
+func flatChunkLimits(opts ChunkOptions) chunkLimits {
+	maxSize := opts.MaxChunkSize
+	if maxSize <= 0 {
+		maxSize = DefaultChunkOptions().MaxChunkSize
+	}
+	return chunkLimits{maxSize: maxSize, maxTokens: opts.MaxTokens, tokenizer: tokenizerFor(opts)}
+}
+
+// chunkBySize splits content into consecutive windows within the flat chunk
+// limits, ignoring structural boundaries. Windows break at whitespace when possible.
+// Used by structure-aware chunkers when ChunkOptions.Flatten is set;
+// metadata supplies the chunk type and basic metadata for each window.
+func chunkBySize(ctx context.Context, content []byte, opts ChunkOptions, metadata func(text string) ChunkMetadata) ([]Chunk, error) {
+	limits := flatChunkLimits(opts)
+	maxSize := limits.maxSize
+
+	var chunks []Chunk
+	contentLen := len(content)
+
+	for offset := 0; offset < contentLen; {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		end := min(offset+maxSize, contentLen)
+		if end < contentLen {
+			end = runeStart(content, end)
+			if end <= offset {
+				end = nextRuneStart(content, offset+1)
+			}
+		}
+		// Close the window early when the token limit binds first
+		if n := limits.tokenPrefix(string(content[offset:end])); offset+n < end {
+			end = offset + n
+		}
+
+		if end < contentLen && end-offset > 100 {
+			if breakPoint := findBreakPoint(content, offset, end); breakPoint > offset {
+				end = breakPoint
+			}
+		}
+
+		text := string(content[offset:end])
+		if strings.TrimSpace(text) != "" {
+			meta := metadata(text)
+			meta.TokenEstimate = limits.tokenizer.EstimateTokens(text)
+			meta.BoundaryConfidence = BoundaryConfidenceFixedWindow
+			chunks = append(chunks, Chunk{
+				Index:       len(chunks),
+				Content:     text,
+				StartOffset: offset,
+				EndOffset:   end,
+				Metadata:    meta,
+			})
+		}
+
+		offset = end
+	}
+
+	return chunks, nil
+}
+
+// flatDocumentMetadata returns basic document metadata for a size-based chunk.
+func flatDocumentMetadata(chunkType ChunkType) func(text string) ChunkMetadata {
+	return func(text string) ChunkMetadata {
+		return ChunkMetadata{
+			Type: chunkType,
+			Document: &DocumentMetadata{
+				WordCount: len(strings.Fields(text)),
+			},
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package chunkers
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// Fuzz targets run their seed corpus as ordinary tests under go test. Run one
+// with random inputs via make fuzz, or: go test ./internal/chunkers -run '^$' -fuzz FuzzMarkdownChunker
+
+// fuzzSeeds are tricky inputs shared by every chunker fuzz target.
+var fuzzSeeds = []string{
+	"",
+	"\n",
+	"\r\n\r\n",
+	"\xff\xfe\xfd",
+	"héllo \xe2\x82",
+	"# Heading\n\nBody\n",
+	"```go\nfunc main() {\n",
+	"---\ntitle: [unterminated\n",
+	"Title\n=====\n\nSection\n-------\n",
+	"= Doc\n\n== Section\n----\nunclosed listing\n",
+	"[[[[((((<<<<{{{{",
+	`{"a": [1, 2, {"b": "c"`,
+	`[{"id":1},{"id":2},{"id":"<>"}]`,
+	strings.Repeat("[", 512) + strings.Repeat("]", 512),
+	strings.Repeat(`{"a":`, 256) + "1" + strings.Repeat("}", 256),
+	"a,b,c\n1,\"2\n3,4\n",
+	"{\"id\":1}\n{broken\n{\"id\":3}\r\n",
+	"key: value\n  - item\n\t- tab: [x\n",
+	strings.Repeat("word ", 400),
+	"\n" + strings.Repeat("0", 64),
+}
+
+// fuzzChunkOptions returns small chunk sizes so fuzz inputs exercise splitting.
+func fuzzChunkOptions(mimeType string, flatten bool) ChunkOptions {
+	return ChunkOptions{
+		MIMEType:          mimeType,
+		MaxChunkSize:      64,
+		Overlap:           8,
+		PreserveStructure: true,
+		Flatten:           flatten,
+	}
+}
+
+// checkFuzzResult fails if chunk offsets fall outside the content.
+func checkFuzzResult(t *testing.T, content []byte, result *ChunkResult, err error) {
+	t.Helper()
+	if err != nil {
+		return
+	}
+	if result == nil {
+		t.Fatal("nil result without error")
+	}
+	for _, chunk := range result.Chunks {
+		if chunk.StartOffset < 0 || chunk.StartOffset > chunk.EndOffset || chunk.EndOffset > len(content) {
+			t.Fatalf("chunk %d offsets [%d, %d] outside [0, %d]",
+				chunk.Index, chunk.StartOffset, chunk.EndOffset, len(content))
+		}
+	}
+}
+
+func FuzzMarkdownChunker(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add([]byte(seed), false)
+	}
+	chunker := NewMarkdownChunker()
+
+	f.Fuzz(func(t *testing.T, content []byte, flatten bool) {
+		result, err := chunker.Chunk(context.Background(), content, fuzzChunkOptions("text/markdown", flatten))
+		checkFuzzResult(t, content, result, err)
+	})
+}
+
+func FuzzAsciiDocChunker(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add([]byte(seed), false)
+	}
+	chunker := NewAsciiDocChunker()
+
+	f.Fuzz(func(t *testing.T, content []byte, flatten bool) {
+		result, err := chunker.Chunk(context.Background(), content, fuzzChunkOptions("text/asciidoc", flatten))
+		checkFuzzResult(t, content, result, err)
+	})
+}
+
+func FuzzStructuredChunker(f *testing.F) {
+	mimeTypes := []string{"application/json", "application/x-ndjson", "text/csv", "application/yaml"}
+	for _, seed := range fuzzSeeds {
+		for i := range mimeTypes {
+			f.Add([]byte(seed), uint8(i))
+		}
+	}
+	chunker := NewStructuredChunker()
+
+	f.Fuzz(func(t *testing.T, content []byte, format uint8) {
+		mimeType := mimeTypes[int(format)%len(mimeTypes)]
+		result, err := chunker.Chunk(context.Background(), content, fuzzChunkOptions(mimeType, false))
+		checkFuzzResult(t, content, result, err)
+	})
+}
+
+func FuzzRecursiveChunker(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add([]byte(seed))
+	}
+	chunker := NewRecursiveChunker()
+
+	f.Fuzz(func(t *testing.T, content []byte) {
+		result, err := chunker.Chunk(context.Background(), content, fuzzChunkOptions("text/plain", false))
+		checkFuzzResult(t, content, result, err)
+	})
+}
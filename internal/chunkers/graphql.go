@@ -64,6 +64,11 @@ func (c *GraphQLChunker) Priority() int {
 	return graphqlChunkerPriority
 }
 
+// ChunkType returns the type of content this chunker produces.
+func (c *GraphQLChunker) ChunkType() ChunkType {
+	return ChunkTypeStructured
+}
+
 // Chunk splits GraphQL content by type definitions.
 func (c *GraphQLChunker) Chunk(ctx context.Context, content []byte, opts ChunkOptions) (*ChunkResult, error) {
 	if len(content) == 0 {
@@ -78,7 +83,7 @@ func (c *GraphQLChunker) Chunk(ctx context.Context, content []byte, opts ChunkOp
 
 	maxSize := opts.MaxChunkSize
 	if maxSize <= 0 {
-		maxSize = DefaultChunkOptions().MaxChunkSize
+		maxSize = DefaultChunkOptionsForType(c.ChunkType()).MaxChunkSize
 	}
 
 	text := string(content)
@@ -108,8 +113,9 @@ func (c *GraphQLChunker) Chunk(ctx context.Context, content []byte, opts ChunkOp
 				StartOffset: offset,
 				EndOffset:   offset + len(def.content),
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeStructured,
-					TokenEstimate: EstimateTokens(def.content),
+					Type:               ChunkTypeStructured,
+					BoundaryConfidence: BoundaryConfidenceStructural,
+					TokenEstimate:      EstimateTokens(def.content),
 					Schema: &SchemaMetadata{
 						TypeName:    def.typeName,
 						TypeKind:    def.typeKind,
@@ -359,8 +365,9 @@ func (c *GraphQLChunker) splitLargeDefinition(ctx context.Context, def graphqlDe
 				StartOffset: offset - len(content),
 				EndOffset:   offset,
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeStructured,
-					TokenEstimate: EstimateTokens(content),
+					Type:               ChunkTypeStructured,
+					BoundaryConfidence: BoundaryConfidenceHeuristic,
+					TokenEstimate:      EstimateTokens(content),
 					Schema: &SchemaMetadata{
 						TypeName:    def.typeName,
 						TypeKind:    def.typeKind,
@@ -385,8 +392,9 @@ func (c *GraphQLChunker) splitLargeDefinition(ctx context.Context, def graphqlDe
 			StartOffset: offset - len(content),
 			EndOffset:   offset,
 			Metadata: ChunkMetadata{
-				Type:          ChunkTypeStructured,
-				TokenEstimate: EstimateTokens(content),
+				Type:               ChunkTypeStructured,
+				BoundaryConfidence: BoundaryConfidenceHeuristic,
+				TokenEstimate:      EstimateTokens(content),
 				Schema: &SchemaMetadata{
 					TypeName:    def.typeName,
 					TypeKind:    def.typeKind,
@@ -446,8 +454,9 @@ func (c *GraphQLChunker) splitByFields(ctx context.Context, def graphqlDefinitio
 				StartOffset: offset - len(content),
 				EndOffset:   offset,
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeStructured,
-					TokenEstimate: EstimateTokens(content),
+					Type:               ChunkTypeStructured,
+					BoundaryConfidence: BoundaryConfidenceHeuristic,
+					TokenEstimate:      EstimateTokens(content),
 					Schema: &SchemaMetadata{
 						TypeName:    def.typeName,
 						TypeKind:    def.typeKind,
@@ -476,8 +485,9 @@ func (c *GraphQLChunker) splitByFields(ctx context.Context, def graphqlDefinitio
 			StartOffset: offset - len(content),
 			EndOffset:   offset,
 			Metadata: ChunkMetadata{
-				Type:          ChunkTypeStructured,
-				TokenEstimate: EstimateTokens(content),
+				Type:               ChunkTypeStructured,
+				BoundaryConfidence: BoundaryConfidenceHeuristic,
+				TokenEstimate:      EstimateTokens(content),
 				Schema: &SchemaMetadata{
 					TypeName:    def.typeName,
 					TypeKind:    def.typeKind,
@@ -493,8 +503,9 @@ func (c *GraphQLChunker) splitByFields(ctx context.Context, def graphqlDefinitio
 			StartOffset: offset - len(content),
 			EndOffset:   offset,
 			Metadata: ChunkMetadata{
-				Type:          ChunkTypeStructured,
-				TokenEstimate: EstimateTokens(content),
+				Type:               ChunkTypeStructured,
+				BoundaryConfidence: BoundaryConfidenceHeuristic,
+				TokenEstimate:      EstimateTokens(content),
 				Schema: &SchemaMetadata{
 					TypeName:    def.typeName,
 					TypeKind:    def.typeKind,
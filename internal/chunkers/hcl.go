@@ -54,6 +54,11 @@ func (c *HCLChunker) Priority() int {
 	return hclChunkerPriority
 }
 
+// ChunkType returns the type of content this chunker produces.
+func (c *HCLChunker) ChunkType() ChunkType {
+	return ChunkTypeStructured
+}
+
 // Chunk splits HCL content by top-level blocks.
 func (c *HCLChunker) Chunk(ctx context.Context, content []byte, opts ChunkOptions) (*ChunkResult, error) {
 	if len(content) == 0 {
@@ -68,7 +73,7 @@ func (c *HCLChunker) Chunk(ctx context.Context, content []byte, opts ChunkOption
 
 	maxSize := opts.MaxChunkSize
 	if maxSize <= 0 {
-		maxSize = DefaultChunkOptions().MaxChunkSize
+		maxSize = DefaultChunkOptionsForType(c.ChunkType()).MaxChunkSize
 	}
 
 	// Parse HCL content
@@ -123,8 +128,9 @@ func (c *HCLChunker) Chunk(ctx context.Context, content []byte, opts ChunkOption
 				StartOffset: offset,
 				EndOffset:   offset + len(block.content),
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeStructured,
-					TokenEstimate: EstimateTokens(block.content),
+					Type:               ChunkTypeStructured,
+					BoundaryConfidence: BoundaryConfidenceStructural,
+					TokenEstimate:      EstimateTokens(block.content),
 					Infra: &InfraMetadata{
 						BlockType:    block.blockType,
 						ResourceType: block.resourceType,
@@ -305,8 +311,9 @@ func (c *HCLChunker) splitLargeBlock(ctx context.Context, block hclBlock, maxSiz
 				StartOffset: offset - len(content),
 				EndOffset:   offset,
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeStructured,
-					TokenEstimate: EstimateTokens(content),
+					Type:               ChunkTypeStructured,
+					BoundaryConfidence: BoundaryConfidenceHeuristic,
+					TokenEstimate:      EstimateTokens(content),
 					Infra: &InfraMetadata{
 						BlockType:    block.blockType,
 						ResourceType: block.resourceType,
@@ -332,8 +339,9 @@ func (c *HCLChunker) splitLargeBlock(ctx context.Context, block hclBlock, maxSiz
 			StartOffset: offset - len(content),
 			EndOffset:   offset,
 			Metadata: ChunkMetadata{
-				Type:          ChunkTypeStructured,
-				TokenEstimate: EstimateTokens(content),
+				Type:               ChunkTypeStructured,
+				BoundaryConfidence: BoundaryConfidenceHeuristic,
+				TokenEstimate:      EstimateTokens(content),
 				Infra: &InfraMetadata{
 					BlockType:    block.blockType,
 					ResourceType: block.resourceType,
@@ -350,7 +358,7 @@ func (c *HCLChunker) splitLargeBlock(ctx context.Context, block hclBlock, maxSiz
 func (c *HCLChunker) fallbackChunk(ctx context.Context, content []byte, opts ChunkOptions, warnings []ChunkWarning) (*ChunkResult, error) {
 	maxSize := opts.MaxChunkSize
 	if maxSize <= 0 {
-		maxSize = DefaultChunkOptions().MaxChunkSize
+		maxSize = DefaultChunkOptionsForType(c.ChunkType()).MaxChunkSize
 	}
 
 	text := string(content)
@@ -375,8 +383,9 @@ func (c *HCLChunker) fallbackChunk(ctx context.Context, content []byte, opts Chu
 				StartOffset: offset - len(chunkContent),
 				EndOffset:   offset,
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeStructured,
-					TokenEstimate: EstimateTokens(chunkContent),
+					Type:               ChunkTypeStructured,
+					BoundaryConfidence: BoundaryConfidenceHeuristic,
+					TokenEstimate:      EstimateTokens(chunkContent),
 					Infra: &InfraMetadata{
 						BlockType: "unknown",
 					},
@@ -400,8 +409,9 @@ func (c *HCLChunker) fallbackChunk(ctx context.Context, content []byte, opts Chu
 			StartOffset: offset - len(chunkContent),
 			EndOffset:   offset,
 			Metadata: ChunkMetadata{
-				Type:          ChunkTypeStructured,
-				TokenEstimate: EstimateTokens(chunkContent),
+				Type:               ChunkTypeStructured,
+				BoundaryConfidence: BoundaryConfidenceHeuristic,
+				TokenEstimate:      EstimateTokens(chunkContent),
 				Infra: &InfraMetadata{
 					BlockType: "unknown",
 				},
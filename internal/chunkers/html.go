@@ -59,6 +59,11 @@ func (c *HTMLChunker) Priority() int {
 	return htmlChunkerPriority
 }
 
+// ChunkType returns the type of content this chunker produces.
+func (c *HTMLChunker) ChunkType() ChunkType {
+	return ChunkTypeMarkdown
+}
+
 // Chunk splits HTML content by heading boundaries.
 func (c *HTMLChunker) Chunk(ctx context.Context, content []byte, opts ChunkOptions) (*ChunkResult, error) {
 	if len(content) == 0 {
@@ -73,7 +78,7 @@ func (c *HTMLChunker) Chunk(ctx context.Context, content []byte, opts ChunkOptio
 
 	maxSize := opts.MaxChunkSize
 	if maxSize <= 0 {
-		maxSize = DefaultChunkOptions().MaxChunkSize
+		maxSize = DefaultChunkOptionsForType(c.ChunkType()).MaxChunkSize
 	}
 
 	// Parse HTML
@@ -115,8 +120,9 @@ func (c *HTMLChunker) Chunk(ctx context.Context, content []byte, opts ChunkOptio
 				StartOffset: section.startOffset,
 				EndOffset:   section.endOffset,
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeMarkdown, // HTML is closest to markdown type
-					TokenEstimate: EstimateTokens(text),
+					Type:               ChunkTypeMarkdown, // HTML is closest to markdown type
+					BoundaryConfidence: BoundaryConfidenceStructural,
+					TokenEstimate:      EstimateTokens(text),
 					Document: &DocumentMetadata{
 						Heading:      section.heading,
 						HeadingLevel: section.level,
@@ -312,8 +318,9 @@ func (c *HTMLChunker) splitLargeSection(ctx context.Context, section htmlSection
 				StartOffset: offset - len(content),
 				EndOffset:   offset,
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeMarkdown,
-					TokenEstimate: EstimateTokens(content),
+					Type:               ChunkTypeMarkdown,
+					BoundaryConfidence: BoundaryConfidenceHeuristic,
+					TokenEstimate:      EstimateTokens(content),
 					Document: &DocumentMetadata{
 						Heading:      section.heading,
 						HeadingLevel: section.level,
@@ -339,8 +346,9 @@ func (c *HTMLChunker) splitLargeSection(ctx context.Context, section htmlSection
 			StartOffset: offset - len(content),
 			EndOffset:   offset,
 			Metadata: ChunkMetadata{
-				Type:          ChunkTypeMarkdown,
-				TokenEstimate: EstimateTokens(content),
+				Type:               ChunkTypeMarkdown,
+				BoundaryConfidence: BoundaryConfidenceHeuristic,
+				TokenEstimate:      EstimateTokens(content),
 				Document: &DocumentMetadata{
 					Heading:      section.heading,
 					HeadingLevel: section.level,
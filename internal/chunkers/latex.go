@@ -60,6 +60,11 @@ func (c *LaTeXChunker) Priority() int {
 	return latexChunkerPriority
 }
 
+// ChunkType returns the type of content this chunker produces.
+func (c *LaTeXChunker) ChunkType() ChunkType {
+	return ChunkTypeProse
+}
+
 // Chunk splits LaTeX content by sectioning commands.
 func (c *LaTeXChunker) Chunk(ctx context.Context, content []byte, opts ChunkOptions) (*ChunkResult, error) {
 	if len(content) == 0 {
@@ -74,7 +79,7 @@ func (c *LaTeXChunker) Chunk(ctx context.Context, content []byte, opts ChunkOpti
 
 	maxSize := opts.MaxChunkSize
 	if maxSize <= 0 {
-		maxSize = DefaultChunkOptions().MaxChunkSize
+		maxSize = DefaultChunkOptionsForType(c.ChunkType()).MaxChunkSize
 	}
 
 	text := string(content)
@@ -104,8 +109,9 @@ func (c *LaTeXChunker) Chunk(ctx context.Context, content []byte, opts ChunkOpti
 				StartOffset: offset,
 				EndOffset:   offset + len(section.content),
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeProse,
-					TokenEstimate: EstimateTokens(section.content),
+					Type:               ChunkTypeProse,
+					BoundaryConfidence: BoundaryConfidenceStructural,
+					TokenEstimate:      EstimateTokens(section.content),
 					Document: &DocumentMetadata{
 						Heading:      section.heading,
 						HeadingLevel: section.level,
@@ -262,8 +268,9 @@ func (c *LaTeXChunker) splitLargeSection(ctx context.Context, section latexSecti
 				StartOffset: offset - len(content),
 				EndOffset:   offset,
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeProse,
-					TokenEstimate: EstimateTokens(content),
+					Type:               ChunkTypeProse,
+					BoundaryConfidence: BoundaryConfidenceHeuristic,
+					TokenEstimate:      EstimateTokens(content),
 					Document: &DocumentMetadata{
 						Heading:      section.heading,
 						HeadingLevel: section.level,
@@ -289,8 +296,9 @@ func (c *LaTeXChunker) splitLargeSection(ctx context.Context, section latexSecti
 			StartOffset: offset - len(content),
 			EndOffset:   offset,
 			Metadata: ChunkMetadata{
-				Type:          ChunkTypeProse,
-				TokenEstimate: EstimateTokens(content),
+				Type:               ChunkTypeProse,
+				BoundaryConfidence: BoundaryConfidenceHeuristic,
+				TokenEstimate:      EstimateTokens(content),
 				Document: &DocumentMetadata{
 					Heading:      section.heading,
 					HeadingLevel: section.level,
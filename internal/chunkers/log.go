@@ -1,7 +1,10 @@
 package chunkers
 
 import (
+	"bufio"
 	"context"
+	"fmt"
+	"io"
 	"regexp"
 	"strings"
 	"time"
@@ -13,6 +16,10 @@ const (
 
 	// Minimum chunk size to prevent tiny chunks
 	logMinChunkSize = 500
+
+	// logStreamDetectSize is how much of a streamed log is read ahead to
+	// detect its format.
+	logStreamDetectSize = 64 * 1024
 )
 
 // Log format patterns
@@ -93,6 +100,11 @@ func (c *LogChunker) Priority() int {
 	return logChunkerPriority
 }
 
+// ChunkType returns the type of content this chunker produces.
+func (c *LogChunker) ChunkType() ChunkType {
+	return ChunkTypeStructured
+}
+
 // Chunk splits log content with error-aware boundaries.
 func (c *LogChunker) Chunk(ctx context.Context, content []byte, opts ChunkOptions) (*ChunkResult, error) {
 	if len(content) == 0 {
@@ -105,133 +117,199 @@ func (c *LogChunker) Chunk(ctx context.Context, content []byte, opts ChunkOption
 		}, nil
 	}
 
-	maxSize := opts.MaxChunkSize
-	if maxSize <= 0 {
-		maxSize = DefaultChunkOptions().MaxChunkSize
-	}
-
 	text := string(content)
-	format := c.detectFormat(text)
-	lines := strings.Split(text, "\n")
+	builder := c.newBuilder(c.detectFormat(text), opts)
 
 	var chunks []Chunk
-	var current strings.Builder
-	var chunkTimeStart, chunkTimeEnd time.Time
-	var chunkErrorCount int
-	var predominantLevel string
-	levelCounts := make(map[string]int)
-	offset := 0
-
-	flushChunk := func() {
-		if current.Len() == 0 {
-			return
+	for _, line := range strings.Split(text, "\n") {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
 		}
 
-		chunkContent := current.String()
-
-		// Determine predominant level
-		predominantLevel = "INFO"
-		maxCount := 0
-		for level, count := range levelCounts {
-			if count > maxCount {
-				maxCount = count
-				predominantLevel = level
-			}
+		if chunk, ok := builder.add(line); ok {
+			chunks = append(chunks, chunk)
 		}
+	}
 
-		chunks = append(chunks, Chunk{
-			Index:       len(chunks),
-			Content:     chunkContent,
-			StartOffset: offset - len(chunkContent),
-			EndOffset:   offset,
-			Metadata: ChunkMetadata{
-				Type:          ChunkTypeStructured,
-				TokenEstimate: EstimateTokens(chunkContent),
-				Log: &LogMetadata{
-					TimeStart:  chunkTimeStart,
-					TimeEnd:    chunkTimeEnd,
-					LogLevel:   predominantLevel,
-					LogFormat:  format,
-					ErrorCount: chunkErrorCount,
-				},
-			},
-		})
-
-		current.Reset()
-		chunkTimeStart = time.Time{}
-		chunkTimeEnd = time.Time{}
-		chunkErrorCount = 0
-		levelCounts = make(map[string]int)
+	// Flush remaining content
+	if chunk, ok := builder.flush(); ok {
+		chunks = append(chunks, chunk)
 	}
 
-	for _, line := range lines {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		default:
-		}
+	return &ChunkResult{
+		Chunks:       chunks,
+		Warnings:     nil,
+		TotalChunks:  len(chunks),
+		ChunkerUsed:  logChunkerName,
+		OriginalSize: len(content),
+	}, nil
+}
 
-		lineLen := len(line) + 1 // +1 for newline
-		lineLevel := c.extractLevel(line)
-		lineTime := c.extractTimestamp(line)
+// ChunkStream splits log content read from r line by line, producing the same
+// chunks as Chunk. The format is detected from the first logStreamDetectSize
+// bytes rather than the whole file.
+func (c *LogChunker) ChunkStream(ctx context.Context, r io.Reader, opts ChunkOptions) (<-chan Chunk, <-chan error) {
+	return streamChunks(ctx, func(emit func(Chunk) error) error {
+		reader := bufio.NewReaderSize(r, logStreamDetectSize)
+		head, err := reader.Peek(logStreamDetectSize)
+		if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+			return fmt.Errorf("failed to read stream; %w", err)
+		}
+		builder := c.newBuilder(c.detectFormat(string(head)), opts)
 
-		// Track timestamps
-		if !lineTime.IsZero() {
-			if chunkTimeStart.IsZero() || lineTime.Before(chunkTimeStart) {
-				chunkTimeStart = lineTime
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil && err != io.EOF {
+				return fmt.Errorf("failed to read stream; %w", err)
+			}
+			eof := err == io.EOF
+
+			// Like strings.Split, the text after the last newline is a line
+			// of its own, even when empty
+			if chunk, ok := builder.add(strings.TrimSuffix(line, "\n")); ok {
+				if err := emit(chunk); err != nil {
+					return err
+				}
 			}
-			if lineTime.After(chunkTimeEnd) {
-				chunkTimeEnd = lineTime
+			if eof {
+				break
 			}
 		}
 
-		// Track level counts
-		levelCounts[lineLevel]++
-
-		// Count errors
-		if lineLevel == "ERROR" || lineLevel == "FATAL" {
-			chunkErrorCount++
+		if chunk, ok := builder.flush(); ok {
+			return emit(chunk)
 		}
+		return nil
+	})
+}
 
-		// Check if we should flush before adding this line
-		shouldFlush := false
+// logChunkBuilder accumulates log lines into chunks with error-aware
+// boundaries, tracking the time range, levels and errors of each chunk.
+type logChunkBuilder struct {
+	chunker     *LogChunker
+	maxSize     int
+	format      string
+	tokenizer   Tokenizer
+	current     strings.Builder
+	timeStart   time.Time
+	timeEnd     time.Time
+	errorCount  int
+	levelCounts map[string]int
+	offset      int
+	index       int
+}
 
-		// Flush if adding this line would exceed max size
-		if current.Len()+lineLen > maxSize && current.Len() >= logMinChunkSize {
-			shouldFlush = true
-		}
+// newBuilder creates a chunk builder for logs of the given format.
+func (c *LogChunker) newBuilder(format string, opts ChunkOptions) *logChunkBuilder {
+	maxSize := opts.MaxChunkSize
+	if maxSize <= 0 {
+		maxSize = DefaultChunkOptionsForType(c.ChunkType()).MaxChunkSize
+	}
+	return &logChunkBuilder{
+		chunker:     c,
+		maxSize:     maxSize,
+		format:      format,
+		tokenizer:   tokenizerFor(opts),
+		levelCounts: make(map[string]int),
+	}
+}
 
-		// Error-aware flush: flush on ERROR/FATAL to keep errors with context
-		// but only if we have enough content and the chunk is at reasonable size
-		if (lineLevel == "ERROR" || lineLevel == "FATAL") &&
-			current.Len() >= logMinChunkSize &&
-			current.Len() > maxSize/2 {
-			// Flush before the error to keep previous context separate
-			// Then the error starts a new chunk with following context
-			flushChunk()
-			offset += 0 // offset already tracked
-			shouldFlush = false
+// add appends a line, returning the chunk completed before it, if any.
+func (b *logChunkBuilder) add(line string) (Chunk, bool) {
+	lineLen := len(line) + 1 // +1 for newline
+	lineLevel := b.chunker.extractLevel(line)
+	lineTime := b.chunker.extractTimestamp(line)
+
+	// Track timestamps
+	if !lineTime.IsZero() {
+		if b.timeStart.IsZero() || lineTime.Before(b.timeStart) {
+			b.timeStart = lineTime
+		}
+		if lineTime.After(b.timeEnd) {
+			b.timeEnd = lineTime
 		}
+	}
+
+	// Track level counts
+	b.levelCounts[lineLevel]++
+
+	// Count errors
+	if lineLevel == "ERROR" || lineLevel == "FATAL" {
+		b.errorCount++
+	}
+
+	// Flush if adding this line would exceed max size
+	shouldFlush := b.current.Len()+lineLen > b.maxSize && b.current.Len() >= logMinChunkSize
+
+	// Error-aware flush: flush on ERROR/FATAL to keep errors with context
+	// but only if we have enough content and the chunk is at reasonable size.
+	// The error then starts a new chunk with the context that follows it.
+	if (lineLevel == "ERROR" || lineLevel == "FATAL") &&
+		b.current.Len() >= logMinChunkSize &&
+		b.current.Len() > b.maxSize/2 {
+		shouldFlush = true
+	}
+
+	var chunk Chunk
+	var ok bool
+	if shouldFlush {
+		chunk, ok = b.flush()
+	}
+
+	b.current.WriteString(line)
+	b.current.WriteString("\n")
+	b.offset += lineLen
 
-		if shouldFlush {
-			flushChunk()
+	return chunk, ok
+}
+
+// flush completes the current chunk, reporting false if it is empty.
+func (b *logChunkBuilder) flush() (Chunk, bool) {
+	if b.current.Len() == 0 {
+		return Chunk{}, false
+	}
+
+	chunkContent := b.current.String()
+
+	// Determine predominant level
+	predominantLevel := "INFO"
+	maxCount := 0
+	for level, count := range b.levelCounts {
+		if count > maxCount {
+			maxCount = count
+			predominantLevel = level
 		}
+	}
 
-		current.WriteString(line)
-		current.WriteString("\n")
-		offset += lineLen
+	chunk := Chunk{
+		Index:       b.index,
+		Content:     chunkContent,
+		StartOffset: b.offset - len(chunkContent),
+		EndOffset:   b.offset,
+		Metadata: ChunkMetadata{
+			Type:               ChunkTypeStructured,
+			BoundaryConfidence: BoundaryConfidenceHeuristic,
+			TokenEstimate:      b.tokenizer.EstimateTokens(chunkContent),
+			Log: &LogMetadata{
+				TimeStart:  b.timeStart,
+				TimeEnd:    b.timeEnd,
+				LogLevel:   predominantLevel,
+				LogFormat:  b.format,
+				ErrorCount: b.errorCount,
+			},
+		},
 	}
+	b.index++
 
-	// Flush remaining content
-	flushChunk()
+	b.current.Reset()
+	b.timeStart = time.Time{}
+	b.timeEnd = time.Time{}
+	b.errorCount = 0
+	b.levelCounts = make(map[string]int)
 
-	return &ChunkResult{
-		Chunks:       chunks,
-		Warnings:     nil,
-		TotalChunks:  len(chunks),
-		ChunkerUsed:  logChunkerName,
-		OriginalSize: len(content),
-	}, nil
+	return chunk, true
 }
 
 // detectFormat determines the log format from content.
@@ -72,6 +72,11 @@ func (c *MakefileChunker) Priority() int {
 	return makefileChunkerPriority
 }
 
+// ChunkType returns the type of content this chunker produces.
+func (c *MakefileChunker) ChunkType() ChunkType {
+	return ChunkTypeStructured
+}
+
 // Chunk splits Makefile content by targets.
 func (c *MakefileChunker) Chunk(ctx context.Context, content []byte, opts ChunkOptions) (*ChunkResult, error) {
 	if len(content) == 0 {
@@ -86,7 +91,7 @@ func (c *MakefileChunker) Chunk(ctx context.Context, content []byte, opts ChunkO
 
 	maxSize := opts.MaxChunkSize
 	if maxSize <= 0 {
-		maxSize = DefaultChunkOptions().MaxChunkSize
+		maxSize = DefaultChunkOptionsForType(c.ChunkType()).MaxChunkSize
 	}
 
 	text := string(content)
@@ -116,8 +121,9 @@ func (c *MakefileChunker) Chunk(ctx context.Context, content []byte, opts ChunkO
 				StartOffset: offset,
 				EndOffset:   offset + len(target.content),
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeStructured,
-					TokenEstimate: EstimateTokens(target.content),
+					Type:               ChunkTypeStructured,
+					BoundaryConfidence: BoundaryConfidenceStructural,
+					TokenEstimate:      EstimateTokens(target.content),
 					Build: &BuildMetadata{
 						TargetName:   target.name,
 						Dependencies: target.dependencies,
@@ -282,8 +288,9 @@ func (c *MakefileChunker) splitLargeTarget(ctx context.Context, target makefileT
 				StartOffset: offset - len(content),
 				EndOffset:   offset,
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeStructured,
-					TokenEstimate: EstimateTokens(content),
+					Type:               ChunkTypeStructured,
+					BoundaryConfidence: BoundaryConfidenceHeuristic,
+					TokenEstimate:      EstimateTokens(content),
 					Build: &BuildMetadata{
 						TargetName:   target.name,
 						Dependencies: target.dependencies,
@@ -308,8 +315,9 @@ func (c *MakefileChunker) splitLargeTarget(ctx context.Context, target makefileT
 			StartOffset: offset - len(content),
 			EndOffset:   offset,
 			Metadata: ChunkMetadata{
-				Type:          ChunkTypeStructured,
-				TokenEstimate: EstimateTokens(content),
+				Type:               ChunkTypeStructured,
+				BoundaryConfidence: BoundaryConfidenceHeuristic,
+				TokenEstimate:      EstimateTokens(content),
 				Build: &BuildMetadata{
 					TargetName:   target.name,
 					Dependencies: target.dependencies,
@@ -2,8 +2,12 @@ package chunkers
 
 import (
 	"context"
+	"fmt"
 	"regexp"
 	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 const (
@@ -14,6 +18,18 @@ const (
 // Matches markdown headings (# to ######)
 var headingRegex = regexp.MustCompile(`^(#{1,6})\s+(.+)$`)
 
+// Matches setext heading underlines (=== for level 1, --- for level 2)
+var setextUnderlineRegex = regexp.MustCompile(`^ {0,3}(=+|-+)[ \t]*$`)
+
+// Matches lines that cannot be setext heading text (list items, blockquotes, indented code)
+var setextExcludedRegex = regexp.MustCompile(`^(?: {4}|\t| {0,3}(?:>|[-*+][ \t]|\d{1,9}[.)][ \t]))`)
+
+// Matches fenced code block delimiters, capturing the fence and info string
+var codeFenceRegex = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})[ \t]*([^`]*?)[ \t]*$")
+
+// Matches thematic breaks: three or more *, - or _ optionally separated by spaces
+var thematicBreakRegex = regexp.MustCompile(`^ {0,3}(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+
 // MarkdownChunker splits markdown content by sections.
 type MarkdownChunker struct{}
 
@@ -31,7 +47,7 @@ func (c *MarkdownChunker) Name() string {
 func (c *MarkdownChunker) CanHandle(mimeType string, language string) bool {
 	return mimeType == "text/markdown" ||
 		mimeType == "text/x-markdown" ||
-		strings.HasSuffix(strings.ToLower(language), ".md")
+		MatchesExtension(language, ".md")
 }
 
 // Priority returns the chunker's priority.
@@ -39,7 +55,13 @@ func (c *MarkdownChunker) Priority() int {
 	return markdownChunkerPriority
 }
 
-// Chunk splits markdown content by headings.
+// ChunkType returns the type of content this chunker produces.
+func (c *MarkdownChunker) ChunkType() ChunkType {
+	return ChunkTypeMarkdown
+}
+
+// Chunk splits markdown content by headings, or purely by size when
+// opts.Flatten is set.
 func (c *MarkdownChunker) Chunk(ctx context.Context, content []byte, opts ChunkOptions) (*ChunkResult, error) {
 	if len(content) == 0 {
 		return &ChunkResult{
@@ -51,101 +73,233 @@ func (c *MarkdownChunker) Chunk(ctx context.Context, content []byte, opts ChunkO
 		}, nil
 	}
 
+	// Valid front matter is lifted into the first chunk's metadata and left
+	// out of chunk content; malformed front matter is chunked as text
+	frontMatter, frontMatterLen, warning := parseFrontMatter(string(content))
+	var warnings []ChunkWarning
+	if warning != nil {
+		warnings = append(warnings, *warning)
+	}
+
+	if opts.Flatten {
+		chunks, err := chunkBySize(ctx, content[frontMatterLen:], opts, flatDocumentMetadata(ChunkTypeMarkdown))
+		if err != nil {
+			return nil, err
+		}
+		for i := range chunks {
+			chunks[i].StartOffset += frontMatterLen
+			chunks[i].EndOffset += frontMatterLen
+		}
+		if frontMatter != nil && len(chunks) > 0 {
+			frontMatter.apply(chunks[0].Metadata.Document)
+		}
+		setLineRanges(content, chunks)
+		return &ChunkResult{
+			Chunks:       chunks,
+			Warnings:     warnings,
+			TotalChunks:  len(chunks),
+			ChunkerUsed:  markdownChunkerName,
+			OriginalSize: len(content),
+		}, nil
+	}
+
 	maxSize := opts.MaxChunkSize
 	if maxSize <= 0 {
-		maxSize = DefaultChunkOptions().MaxChunkSize
+		maxSize = DefaultChunkOptionsForType(c.ChunkType()).MaxChunkSize
 	}
 
 	text := string(content)
-	sections := c.splitBySections(text)
+	sections := c.splitBySections(text, opts.SplitOnThematicBreak)
 
 	var chunks []Chunk
 	offset := 0
 
-	for _, section := range sections {
+	for i, section := range sections {
 		select {
 		case <-ctx.Done():
 			return nil, ctx.Err()
 		default:
 		}
 
-		heading, level := c.extractHeading(section)
+		// Headings are never detected inside front matter, so the first
+		// section always holds all of it
+		if i == 0 && frontMatterLen > 0 {
+			section.content = section.content[frontMatterLen:]
+			offset = frontMatterLen
+		}
+
+		// Tables may run slightly over the limit, since half a table is of
+		// little use for retrieval
+		isTable := isMarkdownTable(section.content)
+		limit := maxSize
+		if isTable {
+			limit = markdownTableLimit(maxSize)
+		}
 
 		// If section is too large, split it further
-		if len(section) > maxSize {
-			subChunks := c.splitLargeSection(ctx, section, heading, level, maxSize, offset)
+		if len(section.content) > limit {
+			subChunks := c.splitLargeSection(ctx, section, maxSize, opts.Overlap, offset)
 			for _, sc := range subChunks {
 				sc.Index = len(chunks)
 				chunks = append(chunks, sc)
 			}
-		} else if strings.TrimSpace(section) != "" {
+		} else if strings.TrimSpace(section.content) != "" {
+			hasCode, codeLanguage := markdownCodeBlocks(section.content)
 			chunks = append(chunks, Chunk{
 				Index:       len(chunks),
-				Content:     section,
+				Content:     section.content,
 				StartOffset: offset,
-				EndOffset:   offset + len(section),
+				EndOffset:   offset + len(section.content),
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeMarkdown,
-					TokenEstimate: EstimateTokens(section),
+					Type:               ChunkTypeMarkdown,
+					TokenEstimate:      EstimateTokens(section.content),
+					BoundaryConfidence: BoundaryConfidenceStructural,
 					Document: &DocumentMetadata{
-						Heading:      heading,
-						HeadingLevel: level,
+						Heading:      section.heading,
+						HeadingLevel: section.level,
+						SectionPath:  section.sectionPath,
+						HasCodeBlock: hasCode,
+						CodeLanguage: codeLanguage,
+						IsTable:      isTable,
+						Anchors:      extractMarkdownAnchors(section.content),
 					},
 				},
 			})
 		}
 
-		offset += len(section)
+		offset += len(section.content)
 	}
+	if frontMatter != nil && len(chunks) > 0 {
+		frontMatter.apply(chunks[0].Metadata.Document)
+	}
+	setLineRanges(content, chunks)
 
 	return &ChunkResult{
 		Chunks:       chunks,
-		Warnings:     nil,
+		Warnings:     warnings,
 		TotalChunks:  len(chunks),
 		ChunkerUsed:  markdownChunkerName,
 		OriginalSize: len(content),
 	}, nil
 }
 
-// splitBySections splits markdown by top-level headings.
-func (c *MarkdownChunker) splitBySections(text string) []string {
+// markdownSection represents a detected section in markdown content.
+type markdownSection struct {
+	content     string
+	heading     string
+	level       int
+	sectionPath string
+}
+
+// markdownHeading is an entry in the heading hierarchy used for section paths.
+type markdownHeading struct {
+	text  string
+	level int
+}
+
+// splitBySections splits markdown by ATX and setext headings. When
+// splitOnBreak is set, thematic breaks also end a section; the following
+// section keeps the enclosing heading and section path.
+func (c *MarkdownChunker) splitBySections(text string, splitOnBreak bool) []markdownSection {
 	lines := strings.Split(text, "\n")
-	var sections []string
+	var sections []markdownSection
 	var current strings.Builder
+	var stack []markdownHeading
+	var heading, sectionPath string
+	var level int
 	inCodeBlock := false
+	frontMatterEnd := frontMatterEndLine(lines)
+
+	flush := func() {
+		sections = append(sections, markdownSection{
+			content:     current.String(),
+			heading:     heading,
+			level:       level,
+			sectionPath: sectionPath,
+		})
+		current.Reset()
+	}
 
-	for _, line := range lines {
+	for i, line := range lines {
 		// Track code blocks to avoid splitting inside them
 		if strings.HasPrefix(strings.TrimSpace(line), "```") {
 			inCodeBlock = !inCodeBlock
 		}
 
-		// Check for heading outside code block
-		if !inCodeBlock && headingRegex.MatchString(line) && current.Len() > 0 {
-			sections = append(sections, current.String())
-			current.Reset()
+		// Check for heading outside code block and front matter
+		if !inCodeBlock && i > frontMatterEnd {
+			next := ""
+			if i+1 < len(lines) {
+				next = lines[i+1]
+			}
+			isSetext := i+1 < len(lines) && (i == 0 || strings.TrimSpace(lines[i-1]) == "") &&
+				setextLevel(line, next) > 0
+			if headingRegex.MatchString(line) || isSetext {
+				if current.Len() > 0 {
+					flush()
+				}
+				heading, level = c.extractHeading(line + "\n" + next)
+				for len(stack) > 0 && stack[len(stack)-1].level >= level {
+					stack = stack[:len(stack)-1]
+				}
+				stack = append(stack, markdownHeading{text: heading, level: level})
+				sectionPath = markdownSectionPath(stack)
+			} else if splitOnBreak && isThematicBreak(lines, i) {
+				// The break closes the current section
+				current.WriteString(line)
+				if i < len(lines)-1 {
+					current.WriteString("\n")
+				}
+				flush()
+				continue
+			}
 		}
 
 		current.WriteString(line)
-		current.WriteString("\n")
+		if i < len(lines)-1 {
+			current.WriteString("\n")
+		}
 	}
 
 	if current.Len() > 0 {
-		sections = append(sections, current.String())
+		flush()
 	}
 
 	return sections
 }
 
+// isThematicBreak reports whether lines[i] is a thematic break rather than a
+// setext underline for the preceding line.
+func isThematicBreak(lines []string, i int) bool {
+	if !thematicBreakRegex.MatchString(lines[i]) {
+		return false
+	}
+	return i == 0 || setextLevel(lines[i-1], lines[i]) == 0
+}
+
+// markdownSectionPath joins the heading hierarchy into a section path.
+func markdownSectionPath(stack []markdownHeading) string {
+	parts := make([]string, len(stack))
+	for i, h := range stack {
+		parts[i] = h.text
+	}
+	return strings.Join(parts, " > ")
+}
+
 // extractHeading extracts the heading text and level from a section.
 func (c *MarkdownChunker) extractHeading(section string) (string, int) {
-	lines := strings.SplitN(section, "\n", 2)
+	lines := strings.SplitN(section, "\n", 3)
 	if len(lines) == 0 {
 		return "", 0
 	}
 
 	matches := headingRegex.FindStringSubmatch(lines[0])
 	if matches == nil {
+		if len(lines) > 1 {
+			if level := setextLevel(lines[0], lines[1]); level > 0 {
+				return strings.TrimSpace(lines[0]), level
+			}
+		}
 		return "", 0
 	}
 
@@ -154,69 +308,210 @@ func (c *MarkdownChunker) extractHeading(section string) (string, int) {
 	return heading, level
 }
 
-// splitLargeSection splits a large section into smaller chunks.
-func (c *MarkdownChunker) splitLargeSection(ctx context.Context, section, heading string, level, maxSize, baseOffset int) []Chunk {
+// setextLevel returns the heading level when text is underlined by underline
+// (1 for "===", 2 for "---"), or 0 if the pair is not a setext heading.
+func setextLevel(text, underline string) int {
+	if strings.TrimSpace(text) == "" || headingRegex.MatchString(text) ||
+		setextUnderlineRegex.MatchString(text) || setextExcludedRegex.MatchString(text) ||
+		strings.HasPrefix(strings.TrimSpace(text), "```") {
+		return 0
+	}
+
+	matches := setextUnderlineRegex.FindStringSubmatch(underline)
+	if matches == nil {
+		return 0
+	}
+	if matches[1][0] == '=' {
+		return 1
+	}
+	return 2
+}
+
+// frontMatterEndLine returns the index of the closing delimiter of YAML front
+// matter at the start of lines, or -1 if there is none.
+func frontMatterEndLine(lines []string) int {
+	if len(lines) == 0 || strings.TrimRight(lines[0], " \t\r") != "---" {
+		return -1
+	}
+	for i := 1; i < len(lines); i++ {
+		if trimmed := strings.TrimRight(lines[i], " \t\r"); trimmed == "---" || trimmed == "..." {
+			return i
+		}
+	}
+	return -1
+}
+
+// markdownCodeBlocks reports whether text contains a fenced code block and
+// returns the language of the first block whose info string names one.
+func markdownCodeBlocks(text string) (bool, string) {
+	found := false
+	language := ""
+	fence := ""
+	for _, line := range strings.Split(text, "\n") {
+		matches := codeFenceRegex.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		if fence != "" {
+			// A closing fence uses the same character and is at least as long
+			if matches[1][0] == fence[0] && len(matches[1]) >= len(fence) && matches[2] == "" {
+				fence = ""
+			}
+			continue
+		}
+
+		fence = matches[1]
+		found = true
+		if fields := strings.Fields(matches[2]); language == "" && len(fields) > 0 {
+			language = strings.Trim(fields[0], "{}.")
+		}
+	}
+	return found, language
+}
+
+// markdownFrontMatter holds the document properties read from YAML front
+// matter.
+type markdownFrontMatter struct {
+	Title  string `yaml:"title"`
+	Author string `yaml:"author"`
+	Date   string `yaml:"date"`
+}
+
+// frontMatterDateFormats are the date layouts accepted for the front matter
+// date property.
+var frontMatterDateFormats = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// parseFrontMatter parses YAML front matter opening text. It returns the
+// parsed properties and the byte length of the block including its closing
+// delimiter. Front matter that is not valid YAML is reported as a warning and
+// yields a nil result and zero length, so the block is chunked as text.
+func parseFrontMatter(text string) (*markdownFrontMatter, int, *ChunkWarning) {
+	lines := strings.Split(text, "\n")
+	end := frontMatterEndLine(lines)
+	if end < 0 {
+		return nil, 0, nil
+	}
+
+	var fm markdownFrontMatter
+	if err := yaml.Unmarshal([]byte(strings.Join(lines[1:end], "\n")), &fm); err != nil {
+		return nil, 0, &ChunkWarning{
+			Offset:  0,
+			Message: fmt.Sprintf("invalid front matter; %v", err),
+			Code:    "FRONTMATTER_PARSE_ERROR",
+		}
+	}
+
+	length := 0
+	for _, line := range lines[:end+1] {
+		length += len(line) + 1
+	}
+	return &fm, min(length, len(text)), nil
+}
+
+// apply copies the front matter properties into document metadata.
+func (fm *markdownFrontMatter) apply(doc *DocumentMetadata) {
+	doc.Title = strings.TrimSpace(fm.Title)
+	doc.Author = strings.TrimSpace(fm.Author)
+	for _, format := range frontMatterDateFormats {
+		if t, err := time.Parse(format, strings.TrimSpace(fm.Date)); err == nil {
+			doc.CreatedDate = t
+			break
+		}
+	}
+}
+
+// splitLargeSection splits a large section into smaller chunks on paragraph
+// boundaries. Tables are kept whole unless they exceed the table limit, in
+// which case they are split on row boundaries. Each paragraph chunk after the
+// first starts with the last overlap bytes of the chunk before it; table row
+// pieces carry no overlap so rows stay whole.
+func (c *MarkdownChunker) splitLargeSection(ctx context.Context, section markdownSection, maxSize, overlap, baseOffset int) []Chunk {
 	var chunks []Chunk
+	emit := func(content string, start, end int, isTable bool) {
+		hasCode, codeLanguage := markdownCodeBlocks(content)
+		chunks = append(chunks, Chunk{
+			Content:     content,
+			StartOffset: start,
+			EndOffset:   end,
+			Metadata: ChunkMetadata{
+				Type:               ChunkTypeMarkdown,
+				TokenEstimate:      EstimateTokens(content),
+				BoundaryConfidence: BoundaryConfidenceHeuristic,
+				Document: &DocumentMetadata{
+					Heading:      section.heading,
+					HeadingLevel: section.level,
+					SectionPath:  section.sectionPath,
+					HasCodeBlock: hasCode,
+					CodeLanguage: codeLanguage,
+					IsTable:      isTable,
+					Anchors:      extractMarkdownAnchors(content),
+				},
+			},
+		})
+	}
 
 	// Try to split by paragraphs first
-	paragraphs := strings.Split(section, "\n\n")
+	paragraphs := strings.Split(section.content, "\n\n")
 	var current strings.Builder
-	offset := baseOffset
+	pos := baseOffset
+	start, end := baseOffset, baseOffset
+	carryStart := -1
 
-	for _, para := range paragraphs {
+	for _, raw := range paragraphs {
 		select {
 		case <-ctx.Done():
 			return chunks
 		default:
 		}
 
-		para = strings.TrimSpace(para)
+		rawStart := pos
+		pos += len(raw) + 2
+		para := strings.TrimSpace(raw)
 		if para == "" {
 			continue
 		}
+		paraStart := rawStart + strings.Index(raw, para)
 
 		// If adding this paragraph exceeds max, finalize current chunk
 		if current.Len()+len(para)+2 > maxSize && current.Len() > 0 {
 			content := current.String()
-			chunks = append(chunks, Chunk{
-				Content:     content,
-				StartOffset: offset - len(content),
-				EndOffset:   offset,
-				Metadata: ChunkMetadata{
-					Type:          ChunkTypeMarkdown,
-					TokenEstimate: EstimateTokens(content),
-					Document: &DocumentMetadata{
-						Heading:      heading,
-						HeadingLevel: level,
-					},
-				},
-			})
+			emit(content, start, end, isMarkdownTable(content))
 			current.Reset()
+			if i := baseOffset + OverlapStart(section.content[:end-baseOffset], overlap); i > start && i < end {
+				carryStart = i
+			}
+		}
+
+		if len(para) > markdownTableLimit(maxSize) && isMarkdownTable(para) {
+			carryStart = -1
+			for _, piece := range splitTableRows(para, maxSize) {
+				emit(para[piece[0]:piece[1]], paraStart+piece[0], paraStart+piece[1], true)
+			}
+			continue
 		}
 
 		if current.Len() > 0 {
 			current.WriteString("\n\n")
+		} else if carryStart >= 0 {
+			// Carry the overlap from the source so offsets stay exact
+			start = carryStart
+			current.WriteString(section.content[carryStart-baseOffset : paraStart-baseOffset])
+			carryStart = -1
+		} else {
+			start = paraStart
 		}
 		current.WriteString(para)
-		offset += len(para) + 2
+		end = paraStart + len(para)
 	}
 
 	// Finalize last chunk
 	if current.Len() > 0 {
-		content := current.String()
-		chunks = append(chunks, Chunk{
-			Content:     content,
-			StartOffset: offset - len(content),
-			EndOffset:   offset,
-			Metadata: ChunkMetadata{
-				Type:          ChunkTypeMarkdown,
-				TokenEstimate: EstimateTokens(content),
-				Document: &DocumentMetadata{
-					Heading:      heading,
-					HeadingLevel: level,
-				},
-			},
-		})
+		emit(current.String(), start, end, isMarkdownTable(current.String()))
 	}
 
 	return chunks
@@ -0,0 +1,109 @@
+package chunkers
+
+import (
+	"regexp"
+	"strings"
+)
+
+// markdownTableSlack is how far past MaxChunkSize a table may grow before it
+// is split on row boundaries, as a fraction of MaxChunkSize.
+const markdownTableSlack = 0.25
+
+// Matches GFM table delimiter rows (e.g. |---|:--:| or --- | ---:), which
+// must contain at least one pipe to be told apart from thematic breaks.
+var tableDelimiterRegex = regexp.MustCompile(`^ {0,3}(?:(?:\|[ \t]*:?-+:?[ \t]*)+\|?|:?-+:?[ \t]*(?:\|[ \t]*:?-+:?[ \t]*)+\|?)[ \t]*$`)
+
+// isMarkdownTable reports whether content is predominantly a GFM pipe table:
+// table rows make up more than half of its non-blank lines, not counting a
+// leading heading.
+func isMarkdownTable(content string) bool {
+	lines := strings.Split(content, "\n")
+	tableLines := markdownTableLines(lines)
+	if len(tableLines) == 0 {
+		return false
+	}
+
+	other := 0
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" || tableLines[i] {
+			continue
+		}
+		other++
+	}
+	if first := firstNonBlankLine(lines); first >= 0 {
+		if headingRegex.MatchString(lines[first]) {
+			other--
+		} else if first+1 < len(lines) && setextLevel(lines[first], lines[first+1]) > 0 {
+			other -= 2
+		}
+	}
+
+	return len(tableLines) > other
+}
+
+// markdownTableLines returns the set of line indexes belonging to pipe
+// tables: a header row, the delimiter row below it, and the data rows that
+// follow up to the first line without a pipe. Tables in fenced code blocks
+// are ignored.
+func markdownTableLines(lines []string) map[int]bool {
+	rows := make(map[int]bool)
+	inCodeBlock := false
+	for i := 0; i < len(lines); i++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock || i+1 >= len(lines) || !isTableRow(lines[i]) || !tableDelimiterRegex.MatchString(lines[i+1]) {
+			continue
+		}
+
+		rows[i], rows[i+1] = true, true
+		i += 2
+		for ; i < len(lines) && isTableRow(lines[i]); i++ {
+			rows[i] = true
+		}
+		i--
+	}
+	return rows
+}
+
+// isTableRow reports whether a line can be a table header or data row.
+func isTableRow(line string) bool {
+	return strings.TrimSpace(line) != "" && strings.Contains(line, "|")
+}
+
+// firstNonBlankLine returns the index of the first non-blank line, or -1.
+func firstNonBlankLine(lines []string) int {
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			return i
+		}
+	}
+	return -1
+}
+
+// markdownTableLimit returns the size a table may reach before it is split.
+func markdownTableLimit(maxSize int) int {
+	return maxSize + int(float64(maxSize)*markdownTableSlack)
+}
+
+// splitTableRows splits a table into pieces of at most maxSize bytes on row
+// boundaries, returning the [start, end) byte range of each piece. A single
+// row larger than maxSize becomes a piece of its own.
+func splitTableRows(table string, maxSize int) [][2]int {
+	var pieces [][2]int
+	start, end, pos := 0, 0, 0
+	for _, line := range strings.Split(table, "\n") {
+		lineStart := pos
+		pos += len(line) + 1
+		if end > start && lineStart+len(line)-start > maxSize {
+			pieces = append(pieces, [2]int{start, end})
+			start = lineStart
+		}
+		end = lineStart + len(line)
+	}
+	if end > start {
+		pieces = append(pieces, [2]int{start, end})
+	}
+	return pieces
+}
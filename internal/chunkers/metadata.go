@@ -1,6 +1,9 @@
 package chunkers
 
-import "time"
+import (
+	"sort"
+	"time"
+)
 
 // ChunkMetadata contains type-specific metadata for a chunk.
 // Only one of the typed metadata pointers will be populated based on Type.
@@ -11,6 +14,16 @@ type ChunkMetadata struct {
 	// TokenEstimate is an accurate token count via tiktoken.
 	TokenEstimate int
 
+	// BoundaryConfidence rates how natural the chunk's boundaries are, from 1.0
+	// for splits on document or syntax structure down to BoundaryConfidenceFixedWindow
+	// for arbitrary byte windows.
+	BoundaryConfidence float64
+
+	// LineStart and LineEnd are the 1-based line range the chunk covers in the
+	// source, or zero when the chunker does not track lines.
+	LineStart int
+	LineEnd   int
+
 	// Type-specific metadata (only one populated based on Type)
 	Code       *CodeMetadata
 	Document   *DocumentMetadata
@@ -23,6 +36,18 @@ type ChunkMetadata struct {
 	Log        *LogMetadata
 }
 
+// Boundary confidence levels reported in ChunkMetadata.BoundaryConfidence.
+const (
+	// BoundaryConfidenceStructural marks boundaries taken from headings, sections or syntax nodes.
+	BoundaryConfidenceStructural = 1.0
+
+	// BoundaryConfidenceHeuristic marks boundaries chosen at separators such as blank lines or sentences.
+	BoundaryConfidenceHeuristic = 0.6
+
+	// BoundaryConfidenceFixedWindow marks boundaries placed by byte-window splitting.
+	BoundaryConfidenceFixedWindow = 0.3
+)
+
 // CodeMetadata contains metadata for code chunks from Tree-sitter parsing.
 type CodeMetadata struct {
 	// Language is the programming language (go, python, javascript, etc.)
@@ -67,6 +92,10 @@ type CodeMetadata struct {
 	// IsConstructor indicates a constructor method.
 	IsConstructor bool
 
+	// IsTest indicates test code: a test, benchmark, example, or fuzz function,
+	// a test suite/case block, or a test case class.
+	IsTest bool
+
 	// Decorators contains decorator/annotation names.
 	Decorators []string
 
@@ -82,6 +111,10 @@ type CodeMetadata struct {
 	// Implements contains interfaces implemented.
 	Implements []string
 
+	// Raises contains errors/exceptions the function can raise or throw
+	// (e.g., "ValueError", "IOException", "error", "panic").
+	Raises []string
+
 	// LineStart is the starting line number (1-indexed).
 	LineStart int
 
@@ -103,6 +136,10 @@ type DocumentMetadata struct {
 	// SectionNumber is the section numbering (e.g., "1.2.3").
 	SectionNumber string
 
+	// Title is the document-level heading, such as a markdown front matter
+	// title. It is set on the first chunk only.
+	Title string
+
 	// Author is the document author.
 	Author string
 
@@ -136,6 +173,10 @@ type DocumentMetadata struct {
 	// IsFootnote indicates the chunk is a footnote/endnote.
 	IsFootnote bool
 
+	// Anchors are in-document link targets referenced by the chunk
+	// (e.g., "installation" for [see](#installation)).
+	Anchors []string
+
 	// ExtractionQuality indicates PDF extraction quality: "high", "medium", "low".
 	ExtractionQuality string
 }
@@ -223,7 +264,8 @@ type StructuredMetadata struct {
 	// RecordIndex is the record number for arrays/sequences.
 	RecordIndex int
 
-	// RecordCount is the number of records in chunk.
+	// RecordCount is the number of records in chunk, or in the whole source
+	// for CSV, where it counts every data row.
 	RecordCount int
 
 	// KeyNames contains keys/columns present.
@@ -268,3 +310,40 @@ type LogMetadata struct {
 	// SourceApp is the application name if detectable.
 	SourceApp string
 }
+
+// setLineRanges fills LineStart and LineEnd from each chunk's byte offsets
+// into content. Lines end at "\n", so CRLF line endings count once. Leading
+// and trailing line breaks are ignored so a chunk ending in a newline ends on
+// the line that newline terminates.
+func setLineRanges(content []byte, chunks []Chunk) {
+	var newlines []int
+	for i, b := range content {
+		if b == '\n' {
+			newlines = append(newlines, i)
+		}
+	}
+	lineAt := func(offset int) int {
+		return sort.SearchInts(newlines, offset) + 1
+	}
+
+	for i := range chunks {
+		// Section splitters may count a final newline the source lacks
+		start, end := chunks[i].StartOffset, min(chunks[i].EndOffset, len(content))
+		if start < 0 || start >= end {
+			continue
+		}
+		first, last := start, end-1
+		for first < last && isLineBreak(content[first]) {
+			first++
+		}
+		for last > first && isLineBreak(content[last]) {
+			last--
+		}
+		chunks[i].Metadata.LineStart = lineAt(first)
+		chunks[i].Metadata.LineEnd = lineAt(last)
+	}
+}
+
+func isLineBreak(b byte) bool {
+	return b == '\n' || b == '\r'
+}
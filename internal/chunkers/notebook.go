@@ -40,6 +40,11 @@ func (c *NotebookChunker) Priority() int {
 	return notebookChunkerPriority
 }
 
+// ChunkType returns the type of content this chunker produces.
+func (c *NotebookChunker) ChunkType() ChunkType {
+	return ChunkTypeCode
+}
+
 // Chunk splits notebook content by cells with smart grouping.
 func (c *NotebookChunker) Chunk(ctx context.Context, content []byte, opts ChunkOptions) (*ChunkResult, error) {
 	if len(content) == 0 {
@@ -54,7 +59,7 @@ func (c *NotebookChunker) Chunk(ctx context.Context, content []byte, opts ChunkO
 
 	maxSize := opts.MaxChunkSize
 	if maxSize <= 0 {
-		maxSize = DefaultChunkOptions().MaxChunkSize
+		maxSize = DefaultChunkOptionsForType(c.ChunkType()).MaxChunkSize
 	}
 
 	// Parse notebook JSON
@@ -107,8 +112,9 @@ func (c *NotebookChunker) Chunk(ctx context.Context, content []byte, opts ChunkO
 				StartOffset: offset,
 				EndOffset:   offset + len(text),
 				Metadata: ChunkMetadata{
-					Type:          c.getChunkType(cellType),
-					TokenEstimate: EstimateTokens(text),
+					Type:               c.getChunkType(cellType),
+					BoundaryConfidence: BoundaryConfidenceStructural,
+					TokenEstimate:      EstimateTokens(text),
 					Notebook: &NotebookMetadata{
 						CellType:       cellType,
 						CellIndex:      group[0].Index,
@@ -417,8 +423,9 @@ func (c *NotebookChunker) splitLargeGroup(ctx context.Context, group cellGroup,
 				StartOffset: offset,
 				EndOffset:   offset + len(text),
 				Metadata: ChunkMetadata{
-					Type:          c.getChunkType(cell.CellType),
-					TokenEstimate: EstimateTokens(text),
+					Type:               c.getChunkType(cell.CellType),
+					BoundaryConfidence: BoundaryConfidenceHeuristic,
+					TokenEstimate:      EstimateTokens(text),
 					Notebook: &NotebookMetadata{
 						CellType:       cell.CellType,
 						CellIndex:      cell.Index,
@@ -459,8 +466,9 @@ func (c *NotebookChunker) splitCellByLines(ctx context.Context, cell jupyterCell
 				StartOffset: offset,
 				EndOffset:   offset + len(content),
 				Metadata: ChunkMetadata{
-					Type:          c.getChunkType(cell.CellType),
-					TokenEstimate: EstimateTokens(content),
+					Type:               c.getChunkType(cell.CellType),
+					BoundaryConfidence: BoundaryConfidenceHeuristic,
+					TokenEstimate:      EstimateTokens(content),
 					Notebook: &NotebookMetadata{
 						CellType:       cell.CellType,
 						CellIndex:      cell.Index,
@@ -490,8 +498,9 @@ func (c *NotebookChunker) splitCellByLines(ctx context.Context, cell jupyterCell
 			StartOffset: offset,
 			EndOffset:   offset + len(content),
 			Metadata: ChunkMetadata{
-				Type:          c.getChunkType(cell.CellType),
-				TokenEstimate: EstimateTokens(content),
+				Type:               c.getChunkType(cell.CellType),
+				BoundaryConfidence: BoundaryConfidenceHeuristic,
+				TokenEstimate:      EstimateTokens(content),
 				Notebook: &NotebookMetadata{
 					CellType:       cell.CellType,
 					CellIndex:      cell.Index,
@@ -40,6 +40,11 @@ func (c *ODTChunker) Priority() int {
 	return odtChunkerPriority
 }
 
+// ChunkType returns the type of content this chunker produces.
+func (c *ODTChunker) ChunkType() ChunkType {
+	return ChunkTypeMarkdown
+}
+
 // Chunk splits ODT content by heading boundaries.
 func (c *ODTChunker) Chunk(ctx context.Context, content []byte, opts ChunkOptions) (*ChunkResult, error) {
 	if len(content) == 0 {
@@ -54,7 +59,7 @@ func (c *ODTChunker) Chunk(ctx context.Context, content []byte, opts ChunkOption
 
 	maxSize := opts.MaxChunkSize
 	if maxSize <= 0 {
-		maxSize = DefaultChunkOptions().MaxChunkSize
+		maxSize = DefaultChunkOptionsForType(c.ChunkType()).MaxChunkSize
 	}
 
 	// Open as ZIP
@@ -109,8 +114,9 @@ func (c *ODTChunker) Chunk(ctx context.Context, content []byte, opts ChunkOption
 				StartOffset: section.startOffset,
 				EndOffset:   section.endOffset,
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeMarkdown,
-					TokenEstimate: EstimateTokens(text),
+					Type:               ChunkTypeMarkdown,
+					BoundaryConfidence: BoundaryConfidenceStructural,
+					TokenEstimate:      EstimateTokens(text),
 					Document: &DocumentMetadata{
 						Heading:      section.heading,
 						HeadingLevel: section.level,
@@ -447,8 +453,9 @@ func (c *ODTChunker) splitLargeSection(ctx context.Context, section odtSection,
 				StartOffset: offset - len(content),
 				EndOffset:   offset,
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeMarkdown,
-					TokenEstimate: EstimateTokens(content),
+					Type:               ChunkTypeMarkdown,
+					BoundaryConfidence: BoundaryConfidenceHeuristic,
+					TokenEstimate:      EstimateTokens(content),
 					Document: &DocumentMetadata{
 						Heading:      section.heading,
 						HeadingLevel: section.level,
@@ -474,8 +481,9 @@ func (c *ODTChunker) splitLargeSection(ctx context.Context, section odtSection,
 			StartOffset: offset - len(content),
 			EndOffset:   offset,
 			Metadata: ChunkMetadata{
-				Type:          ChunkTypeMarkdown,
-				TokenEstimate: EstimateTokens(content),
+				Type:               ChunkTypeMarkdown,
+				BoundaryConfidence: BoundaryConfidenceHeuristic,
+				TokenEstimate:      EstimateTokens(content),
 				Document: &DocumentMetadata{
 					Heading:      section.heading,
 					HeadingLevel: section.level,
@@ -50,6 +50,11 @@ func (c *PDFChunker) Priority() int {
 	return pdfChunkerPriority
 }
 
+// ChunkType returns the type of content this chunker produces.
+func (c *PDFChunker) ChunkType() ChunkType {
+	return ChunkTypeProse
+}
+
 // Chunk splits PDF content by pages and sections.
 func (c *PDFChunker) Chunk(ctx context.Context, content []byte, opts ChunkOptions) (*ChunkResult, error) {
 	if len(content) == 0 {
@@ -64,7 +69,7 @@ func (c *PDFChunker) Chunk(ctx context.Context, content []byte, opts ChunkOption
 
 	maxSize := opts.MaxChunkSize
 	if maxSize <= 0 {
-		maxSize = DefaultChunkOptions().MaxChunkSize
+		maxSize = DefaultChunkOptionsForType(c.ChunkType()).MaxChunkSize
 	}
 
 	// Parse PDF
@@ -116,8 +121,9 @@ func (c *PDFChunker) Chunk(ctx context.Context, content []byte, opts ChunkOption
 				StartOffset: section.startOffset,
 				EndOffset:   section.endOffset,
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeProse,
-					TokenEstimate: EstimateTokens(text),
+					Type:               ChunkTypeProse,
+					BoundaryConfidence: BoundaryConfidenceStructural,
+					TokenEstimate:      EstimateTokens(text),
 					Document: &DocumentMetadata{
 						Heading:           section.heading,
 						HeadingLevel:      section.level,
@@ -457,8 +463,9 @@ func (c *PDFChunker) splitLargeSection(ctx context.Context, section pdfSection,
 				StartOffset: offset - len(content),
 				EndOffset:   offset,
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeProse,
-					TokenEstimate: EstimateTokens(content),
+					Type:               ChunkTypeProse,
+					BoundaryConfidence: BoundaryConfidenceHeuristic,
+					TokenEstimate:      EstimateTokens(content),
 					Document: &DocumentMetadata{
 						Heading:           section.heading,
 						HeadingLevel:      section.level,
@@ -487,8 +494,9 @@ func (c *PDFChunker) splitLargeSection(ctx context.Context, section pdfSection,
 			StartOffset: offset - len(content),
 			EndOffset:   offset,
 			Metadata: ChunkMetadata{
-				Type:          ChunkTypeProse,
-				TokenEstimate: EstimateTokens(content),
+				Type:               ChunkTypeProse,
+				BoundaryConfidence: BoundaryConfidenceHeuristic,
+				TokenEstimate:      EstimateTokens(content),
 				Document: &DocumentMetadata{
 					Heading:           section.heading,
 					HeadingLevel:      section.level,
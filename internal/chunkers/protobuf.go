@@ -51,6 +51,11 @@ func (c *ProtobufChunker) Priority() int {
 	return protobufChunkerPriority
 }
 
+// ChunkType returns the type of content this chunker produces.
+func (c *ProtobufChunker) ChunkType() ChunkType {
+	return ChunkTypeStructured
+}
+
 // Chunk splits Protocol Buffer content by definitions.
 func (c *ProtobufChunker) Chunk(ctx context.Context, content []byte, opts ChunkOptions) (*ChunkResult, error) {
 	if len(content) == 0 {
@@ -65,7 +70,7 @@ func (c *ProtobufChunker) Chunk(ctx context.Context, content []byte, opts ChunkO
 
 	maxSize := opts.MaxChunkSize
 	if maxSize <= 0 {
-		maxSize = DefaultChunkOptions().MaxChunkSize
+		maxSize = DefaultChunkOptionsForType(c.ChunkType()).MaxChunkSize
 	}
 
 	// Parse protobuf content
@@ -109,8 +114,9 @@ func (c *ProtobufChunker) Chunk(ctx context.Context, content []byte, opts ChunkO
 				StartOffset: offset,
 				EndOffset:   offset + len(def.content),
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeStructured,
-					TokenEstimate: EstimateTokens(def.content),
+					Type:               ChunkTypeStructured,
+					BoundaryConfidence: BoundaryConfidenceStructural,
+					TokenEstimate:      EstimateTokens(def.content),
 					Schema: &SchemaMetadata{
 						MessageName: def.messageName,
 						ServiceName: def.serviceName,
@@ -317,8 +323,9 @@ func (c *ProtobufChunker) splitLargeDefinition(ctx context.Context, def protobuf
 				StartOffset: offset - len(content),
 				EndOffset:   offset,
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeStructured,
-					TokenEstimate: EstimateTokens(content),
+					Type:               ChunkTypeStructured,
+					BoundaryConfidence: BoundaryConfidenceHeuristic,
+					TokenEstimate:      EstimateTokens(content),
 					Schema: &SchemaMetadata{
 						MessageName: def.messageName,
 						ServiceName: def.serviceName,
@@ -344,8 +351,9 @@ func (c *ProtobufChunker) splitLargeDefinition(ctx context.Context, def protobuf
 			StartOffset: offset - len(content),
 			EndOffset:   offset,
 			Metadata: ChunkMetadata{
-				Type:          ChunkTypeStructured,
-				TokenEstimate: EstimateTokens(content),
+				Type:               ChunkTypeStructured,
+				BoundaryConfidence: BoundaryConfidenceHeuristic,
+				TokenEstimate:      EstimateTokens(content),
 				Schema: &SchemaMetadata{
 					MessageName: def.messageName,
 					ServiceName: def.serviceName,
@@ -363,7 +371,7 @@ func (c *ProtobufChunker) splitLargeDefinition(ctx context.Context, def protobuf
 func (c *ProtobufChunker) fallbackChunk(ctx context.Context, content []byte, opts ChunkOptions, warnings []ChunkWarning) (*ChunkResult, error) {
 	maxSize := opts.MaxChunkSize
 	if maxSize <= 0 {
-		maxSize = DefaultChunkOptions().MaxChunkSize
+		maxSize = DefaultChunkOptionsForType(c.ChunkType()).MaxChunkSize
 	}
 
 	text := string(content)
@@ -386,8 +394,9 @@ func (c *ProtobufChunker) fallbackChunk(ctx context.Context, content []byte, opt
 				StartOffset: offset - len(chunkContent),
 				EndOffset:   offset,
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeStructured,
-					TokenEstimate: EstimateTokens(chunkContent),
+					Type:               ChunkTypeStructured,
+					BoundaryConfidence: BoundaryConfidenceHeuristic,
+					TokenEstimate:      EstimateTokens(chunkContent),
 					Schema: &SchemaMetadata{
 						TypeKind: "unknown",
 					},
@@ -411,8 +420,9 @@ func (c *ProtobufChunker) fallbackChunk(ctx context.Context, content []byte, opt
 			StartOffset: offset - len(chunkContent),
 			EndOffset:   offset,
 			Metadata: ChunkMetadata{
-				Type:          ChunkTypeStructured,
-				TokenEstimate: EstimateTokens(chunkContent),
+				Type:               ChunkTypeStructured,
+				BoundaryConfidence: BoundaryConfidenceHeuristic,
+				TokenEstimate:      EstimateTokens(chunkContent),
 				Schema: &SchemaMetadata{
 					TypeKind: "unknown",
 				},
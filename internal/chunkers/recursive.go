@@ -1,13 +1,20 @@
 package chunkers
 
 import (
+	"bytes"
 	"context"
+	"io"
 	"strings"
+	"unicode/utf8"
 )
 
 const (
 	recursiveChunkerName     = "recursive"
 	recursiveChunkerPriority = 10
+
+	// recursiveStreamWindow is the streaming window size in multiples of
+	// MaxChunkSize.
+	recursiveStreamWindow = 4
 )
 
 // Default separators in order of preference (largest to smallest boundaries).
@@ -51,7 +58,14 @@ func (c *RecursiveChunker) Priority() int {
 	return recursiveChunkerPriority
 }
 
-// Chunk splits content recursively using separators.
+// ChunkType returns the type of content this chunker produces.
+func (c *RecursiveChunker) ChunkType() ChunkType {
+	return ChunkTypeProse
+}
+
+// Chunk splits content recursively using separators. A chunk closes at
+// MaxChunkSize bytes or, when MaxTokens is set, at MaxTokens tokens, whichever
+// comes first.
 func (c *RecursiveChunker) Chunk(ctx context.Context, content []byte, opts ChunkOptions) (*ChunkResult, error) {
 	if len(content) == 0 {
 		return &ChunkResult{
@@ -65,14 +79,16 @@ func (c *RecursiveChunker) Chunk(ctx context.Context, content []byte, opts Chunk
 
 	maxSize := opts.MaxChunkSize
 	if maxSize <= 0 {
-		maxSize = DefaultChunkOptions().MaxChunkSize
+		maxSize = DefaultChunkOptionsForType(c.ChunkType()).MaxChunkSize
 	}
 
+	limits := chunkLimits{maxSize: maxSize, maxTokens: opts.MaxTokens, tokenizer: tokenizerFor(opts)}
 	text := string(content)
-	segments := c.splitRecursive(ctx, text, c.separators, maxSize)
+	segments := c.splitRecursive(ctx, text, c.separators, limits)
 
 	// Merge small segments and create chunks
-	chunks := c.mergeSegments(ctx, segments, maxSize, opts.Overlap)
+	chunks := c.mergeSegments(ctx, text, segments, limits, opts.Overlap)
+	setLineRanges(content, chunks)
 
 	return &ChunkResult{
 		Chunks:       chunks,
@@ -83,28 +99,111 @@ func (c *RecursiveChunker) Chunk(ctx context.Context, content []byte, opts Chunk
 	}, nil
 }
 
+// ChunkStream splits content read from r a window at a time. Windows of
+// recursiveStreamWindow times MaxChunkSize are cut at paragraph or line
+// breaks, and each window's last chunk is held back and rechunked with the
+// next window so it can still grow, so chunks match Chunk except where a
+// window had to be cut mid-line.
+func (c *RecursiveChunker) ChunkStream(ctx context.Context, r io.Reader, opts ChunkOptions) (<-chan Chunk, <-chan error) {
+	maxSize := opts.MaxChunkSize
+	if maxSize <= 0 {
+		maxSize = DefaultChunkOptionsForType(c.ChunkType()).MaxChunkSize
+	}
+	limits := chunkLimits{maxSize: maxSize, maxTokens: opts.MaxTokens, tokenizer: tokenizerFor(opts)}
+
+	return streamChunks(ctx, func(emit func(Chunk) error) error {
+		var buf []byte
+		base, index, line := 0, 0, 0
+		eof := false
+		for {
+			var err error
+			if !eof {
+				if buf, eof, err = fillBuffer(r, buf, recursiveStreamWindow*maxSize); err != nil {
+					return err
+				}
+			}
+			if len(buf) == 0 {
+				return nil
+			}
+
+			cut := len(buf)
+			if !eof {
+				cut = streamCut(buf)
+			}
+			text := string(buf[:cut])
+			segments := c.splitRecursive(ctx, text, c.separators, limits)
+			chunks := c.mergeSegments(ctx, text, segments, limits, opts.Overlap)
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			setLineRanges(buf[:cut], chunks)
+
+			consumed := cut
+			if !eof && len(chunks) > 1 && chunks[len(chunks)-1].StartOffset > 0 {
+				consumed = chunks[len(chunks)-1].StartOffset
+				chunks = chunks[:len(chunks)-1]
+			}
+			for _, chunk := range chunks {
+				chunk.Index = index
+				chunk.StartOffset += base
+				chunk.EndOffset += base
+				if chunk.Metadata.LineStart > 0 {
+					chunk.Metadata.LineStart += line
+					chunk.Metadata.LineEnd += line
+				}
+				if err := emit(chunk); err != nil {
+					return err
+				}
+				index++
+			}
+
+			if eof && consumed == len(buf) {
+				return nil
+			}
+			line += bytes.Count(buf[:consumed], []byte("\n"))
+			buf = buf[:copy(buf, buf[consumed:])]
+			base += consumed
+		}
+	})
+}
+
+// streamCut returns where to end a streaming window within buf: after its
+// last paragraph break, else its last line break, else its last whole rune.
+func streamCut(buf []byte) int {
+	if i := bytes.LastIndex(buf, []byte("\n\n")); i >= 0 {
+		return i + 2
+	}
+	if i := bytes.LastIndexByte(buf, '\n'); i >= 0 {
+		return i + 1
+	}
+	if i := runeStart(buf, len(buf)-1); !utf8.FullRune(buf[i:]) {
+		return i
+	}
+	return len(buf)
+}
+
 // splitRecursive splits text using the first applicable separator.
-func (c *RecursiveChunker) splitRecursive(ctx context.Context, text string, separators []string, maxSize int) []string {
-	if len(text) <= maxSize {
+func (c *RecursiveChunker) splitRecursive(ctx context.Context, text string, separators []string, limits chunkLimits) []string {
+	if limits.fits(text) {
 		return []string{text}
 	}
 
 	if len(separators) == 0 {
 		// Last resort: split by characters
-		return c.splitBySize(text, maxSize)
+		return c.splitBySize(text, limits)
 	}
 
 	sep := separators[0]
 	remainingSeps := separators[1:]
 
 	if sep == "" {
-		return c.splitBySize(text, maxSize)
+		return c.splitBySize(text, limits)
 	}
 
 	parts := strings.Split(text, sep)
 	if len(parts) == 1 {
 		// Separator not found; try next
-		return c.splitRecursive(ctx, text, remainingSeps, maxSize)
+		return c.splitRecursive(ctx, text, remainingSeps, limits)
 	}
 
 	var result []string
@@ -124,11 +223,11 @@ func (c *RecursiveChunker) splitRecursive(ctx context.Context, text string, sepa
 			part = part + sep
 		}
 
-		if len(part) <= maxSize {
+		if limits.fits(part) {
 			result = append(result, strings.TrimRight(part, sep))
 		} else {
 			// Recursively split with smaller separators
-			subParts := c.splitRecursive(ctx, part, remainingSeps, maxSize)
+			subParts := c.splitRecursive(ctx, part, remainingSeps, limits)
 			result = append(result, subParts...)
 		}
 	}
@@ -136,19 +235,22 @@ func (c *RecursiveChunker) splitRecursive(ctx context.Context, text string, sepa
 	return result
 }
 
-// splitBySize splits text into fixed-size chunks.
-func (c *RecursiveChunker) splitBySize(text string, maxSize int) []string {
+// splitBySize splits text into fixed-size chunks, shortened where needed to
+// stay within the token limit.
+func (c *RecursiveChunker) splitBySize(text string, limits chunkLimits) []string {
 	var result []string
 	for len(text) > 0 {
-		end := min(maxSize, len(text))
+		end := min(limits.maxSize, len(text))
+		end = limits.tokenPrefix(text[:end])
 		result = append(result, text[:end])
 		text = text[end:]
 	}
 	return result
 }
 
-// mergeSegments combines small segments and builds final chunks.
-func (c *RecursiveChunker) mergeSegments(ctx context.Context, segments []string, maxSize int, overlap int) []Chunk {
+// mergeSegments combines small segments and builds final chunks. Segments
+// are substrings of text in order, so offsets are found by scanning text.
+func (c *RecursiveChunker) mergeSegments(ctx context.Context, text string, segments []string, limits chunkLimits, overlap int) []Chunk {
 	if len(segments) == 0 {
 		return []Chunk{}
 	}
@@ -157,6 +259,7 @@ func (c *RecursiveChunker) mergeSegments(ctx context.Context, segments []string,
 	var current strings.Builder
 	currentStart := 0
 	totalOffset := 0
+	pos := 0
 
 segmentLoop:
 	for _, seg := range segments {
@@ -165,9 +268,16 @@ segmentLoop:
 		}
 
 		segLen := len(seg)
+		segStart := pos
+		if i := strings.Index(text[pos:], seg); i >= 0 {
+			segStart = pos + i
+		}
+		if current.Len() == 0 {
+			currentStart = segStart
+		}
 
-		// If adding this segment exceeds max, finalize current chunk
-		if current.Len()+segLen > maxSize && current.Len() > 0 {
+		// If adding this segment exceeds either limit, finalize current chunk
+		if current.Len() > 0 && (current.Len()+segLen > limits.maxSize || !limits.fitsTokens(mergedText(current.String(), seg))) {
 			content := current.String()
 			chunks = append(chunks, Chunk{
 				Index:       len(chunks),
@@ -175,8 +285,9 @@ segmentLoop:
 				StartOffset: currentStart,
 				EndOffset:   totalOffset,
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeProse,
-					TokenEstimate: EstimateTokens(content),
+					Type:               ChunkTypeProse,
+					TokenEstimate:      limits.tokenizer.EstimateTokens(content),
+					BoundaryConfidence: BoundaryConfidenceHeuristic,
 				},
 			})
 
@@ -185,10 +296,10 @@ segmentLoop:
 				overlapText := content[len(content)-overlap:]
 				current.Reset()
 				current.WriteString(overlapText)
-				currentStart = totalOffset - overlap
+				currentStart = max(totalOffset-overlap, 0)
 			} else {
 				current.Reset()
-				currentStart = totalOffset
+				currentStart = segStart
 			}
 		}
 
@@ -196,7 +307,9 @@ segmentLoop:
 		if current.Len() > 0 && !strings.HasSuffix(current.String(), " ") {
 			current.WriteString(" ")
 		}
-		totalOffset += segLen + 1
+		// A separator re-appended to the final part may not exist in text
+		pos = min(segStart+segLen, len(text))
+		totalOffset = pos
 	}
 
 	// Finalize last chunk
@@ -209,8 +322,9 @@ segmentLoop:
 				StartOffset: currentStart,
 				EndOffset:   totalOffset,
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeProse,
-					TokenEstimate: EstimateTokens(content),
+					Type:               ChunkTypeProse,
+					TokenEstimate:      limits.tokenizer.EstimateTokens(content),
+					BoundaryConfidence: BoundaryConfidenceHeuristic,
 				},
 			})
 		}
@@ -218,3 +332,13 @@ segmentLoop:
 
 	return chunks
 }
+
+// mergedText returns the text current becomes once seg is appended, including
+// the space that separates merged segments.
+func mergedText(current, seg string) string {
+	merged := current + seg
+	if !strings.HasSuffix(merged, " ") {
+		merged += " "
+	}
+	return merged
+}
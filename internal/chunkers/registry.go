@@ -2,7 +2,10 @@ package chunkers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"path/filepath"
 	"sort"
 	"sync"
 )
@@ -24,13 +27,33 @@ type Registry struct {
 	mu       sync.RWMutex
 	chunkers []Chunker
 	fallback Chunker
+	recover  bool
+}
+
+// RegistryOption configures a Registry.
+type RegistryOption func(*Registry)
+
+// WithRecover sets whether Chunk recovers from chunker panics, treating a
+// panicking chunker like a failed one. Enabled by default; disable it to let
+// panics propagate with their stack trace when debugging a chunker.
+func WithRecover(enabled bool) RegistryOption {
+	return func(r *Registry) {
+		r.recover = enabled
+	}
 }
 
 // NewRegistry creates a new chunker registry.
-func NewRegistry() *Registry {
-	return &Registry{
+func NewRegistry(opts ...RegistryOption) *Registry {
+	r := &Registry{
 		chunkers: make([]Chunker, 0),
+		recover:  true,
 	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
 }
 
 // Register adds a chunker to the registry.
@@ -67,6 +90,23 @@ func (r *Registry) Get(mimeType string, language string) Chunker {
 	return r.fallback
 }
 
+// GetAll returns every registered chunker that can handle the given content type,
+// sorted by priority (highest first). The fallback chunker is not included.
+// It is intended for diagnosing why content routed to a particular chunker.
+func (r *Registry) GetAll(mimeType string, language string) []Chunker {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matches []Chunker
+	for _, c := range r.chunkers {
+		if c.CanHandle(mimeType, language) {
+			matches = append(matches, c)
+		}
+	}
+
+	return matches
+}
+
 // Chunk uses the best available chunker for the content with graceful degradation.
 // If the primary chunker fails, it tries the next chunker in priority order.
 // Warnings from failed attempts are aggregated into the final result.
@@ -74,6 +114,8 @@ func (r *Registry) Chunk(ctx context.Context, content []byte, opts ChunkOptions)
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
+	opts.Language = scriptLanguage(content, opts)
+
 	var aggregatedWarnings []ChunkWarning
 	var lastErr error
 
@@ -83,14 +125,20 @@ func (r *Registry) Chunk(ctx context.Context, content []byte, opts ChunkOptions)
 			continue
 		}
 
-		result, err := chunker.Chunk(ctx, content, opts)
+		result, err := r.invoke(ctx, chunker, content, opts)
 		if err != nil {
 			// Record warning about failed chunker and try next
-			aggregatedWarnings = append(aggregatedWarnings, ChunkWarning{
+			warning := ChunkWarning{
 				Offset:  0,
 				Message: fmt.Sprintf("chunker %q failed: %v", chunker.Name(), err),
 				Code:    "CHUNKER_FAILED",
-			})
+			}
+			var panicErr *chunkerPanicError
+			if errors.As(err, &panicErr) {
+				warning.Message = panicErr.Error()
+				warning.Code = "CHUNKER_PANIC"
+			}
+			aggregatedWarnings = append(aggregatedWarnings, warning)
 			lastErr = err
 			continue
 		}
@@ -99,12 +147,14 @@ func (r *Registry) Chunk(ctx context.Context, content []byte, opts ChunkOptions)
 		if len(aggregatedWarnings) > 0 {
 			result.Warnings = append(aggregatedWarnings, result.Warnings...)
 		}
+		defaultBoundaryConfidence(result)
+		applyTokenizer(result, opts.Tokenizer)
 		return result, nil
 	}
 
 	// All specialized chunkers failed or none matched - try fallback
 	if r.fallback != nil {
-		result, err := r.fallback.Chunk(ctx, content, opts)
+		result, err := r.invoke(ctx, r.fallback, content, opts)
 		if err != nil {
 			return nil, fmt.Errorf("all chunkers failed; last error: %w", err)
 		}
@@ -113,6 +163,7 @@ func (r *Registry) Chunk(ctx context.Context, content []byte, opts ChunkOptions)
 		if len(aggregatedWarnings) > 0 {
 			result.Warnings = append(aggregatedWarnings, result.Warnings...)
 		}
+		applyTokenizer(result, opts.Tokenizer)
 		return result, nil
 	}
 
@@ -123,6 +174,167 @@ func (r *Registry) Chunk(ctx context.Context, content []byte, opts ChunkOptions)
 	return nil, fmt.Errorf("no chunker available for mime=%s lang=%s", opts.MIMEType, opts.Language)
 }
 
+// scriptLanguage returns opts.Language, or for content with no language and a
+// generic MIME type, the interpreter named by its shebang line. This routes
+// extensionless scripts to the code chunkers for their language.
+func scriptLanguage(content []byte, opts ChunkOptions) string {
+	if opts.Language != "" {
+		return opts.Language
+	}
+	switch opts.MIMEType {
+	case "", "text/plain", "application/octet-stream":
+		return ShebangInterpreter(content)
+	}
+	return ""
+}
+
+// ChunkStream chunks content read from r. When the chunker Chunk would select
+// first implements StreamChunker, its stream is returned so the content is
+// never held in memory whole; otherwise r is read fully and chunked through
+// Chunk, with its graceful degradation, and the chunks are sent in order.
+// Streaming chunkers are not retried on failure.
+func (r *Registry) ChunkStream(ctx context.Context, reader io.Reader, opts ChunkOptions) (<-chan Chunk, <-chan error) {
+	if streamer := r.streamChunkerFor(opts); streamer != nil {
+		return streamer.ChunkStream(ctx, reader, optionsFor(streamer, opts))
+	}
+
+	return streamChunks(ctx, func(emit func(Chunk) error) error {
+		content, err := io.ReadAll(reader)
+		if err != nil {
+			return fmt.Errorf("failed to read stream; %w", err)
+		}
+		result, err := r.Chunk(ctx, content, opts)
+		if err != nil {
+			return err
+		}
+		for _, chunk := range result.Chunks {
+			if err := emit(chunk); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// streamChunkerFor returns the chunker Chunk would try first for opts if it
+// supports streaming, or nil.
+func (r *Registry) streamChunkerFor(opts ChunkOptions) StreamChunker {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var selected Chunker
+	for _, c := range r.chunkers {
+		if c.CanHandle(opts.MIMEType, opts.Language) {
+			selected = c
+			break
+		}
+	}
+	if selected == nil {
+		selected = r.fallback
+	}
+
+	streamer, _ := selected.(StreamChunker)
+	return streamer
+}
+
+// chunkerPanicError reports a panic recovered from a chunker.
+type chunkerPanicError struct {
+	chunker string
+	value   any
+}
+
+func (e *chunkerPanicError) Error() string {
+	return fmt.Sprintf("chunker %q panicked: %v", e.chunker, e.value)
+}
+
+// invoke runs a chunker with its per-chunker options, converting a panic into
+// a chunkerPanicError when recovery is enabled.
+func (r *Registry) invoke(ctx context.Context, c Chunker, content []byte, opts ChunkOptions) (result *ChunkResult, err error) {
+	if r.recover {
+		defer func() {
+			if v := recover(); v != nil {
+				result = nil
+				err = &chunkerPanicError{chunker: c.Name(), value: v}
+			}
+		}()
+	}
+	return c.Chunk(ctx, content, optionsFor(c, opts))
+}
+
+// defaultBoundaryConfidence marks chunks whose chunker left BoundaryConfidence
+// unset as heuristic. Chunkers set their own confidence on structural and
+// fixed-window paths.
+func defaultBoundaryConfidence(result *ChunkResult) {
+	for i := range result.Chunks {
+		if result.Chunks[i].Metadata.BoundaryConfidence == 0 {
+			result.Chunks[i].Metadata.BoundaryConfidence = BoundaryConfidenceHeuristic
+		}
+	}
+}
+
+// applyTokenizer re-estimates each chunk's TokenEstimate with tokenizer, so
+// chunkers that estimate with the default still report counts for the
+// configured model. A nil tokenizer leaves the estimates unchanged.
+func applyTokenizer(result *ChunkResult, tokenizer Tokenizer) {
+	if tokenizer == nil {
+		return
+	}
+	for i := range result.Chunks {
+		result.Chunks[i].Metadata.TokenEstimate = tokenizer.EstimateTokens(result.Chunks[i].Content)
+	}
+}
+
+// ChunkContent chunks content with default options through the same chunker
+// selection used during ingestion, without touching the graph. It is intended
+// for ad-hoc inspection, such as chunking content piped from stdin; the result
+// marshals to JSON with full chunk metadata.
+func (r *Registry) ChunkContent(ctx context.Context, content []byte, mimeType, language string) (*ChunkResult, error) {
+	return r.Chunk(ctx, content, contentOptions(mimeType, language))
+}
+
+// ChunkFile chunks the content of the file at path like ChunkContent, and
+// resolves file references such as AsciiDoc includes relative to the file's
+// directory. An empty path resolves no references.
+func (r *Registry) ChunkFile(ctx context.Context, path string, content []byte, mimeType, language string) (*ChunkResult, error) {
+	opts := contentOptions(mimeType, language)
+	if path != "" {
+		opts.BaseDir = filepath.Dir(path)
+	}
+	return r.Chunk(ctx, content, opts)
+}
+
+// contentOptions returns the default options for chunking whole files.
+func contentOptions(mimeType, language string) ChunkOptions {
+	// Size limits are left unset so per-type defaults apply
+	opts := DefaultChunkOptions()
+	opts.MaxChunkSize = 0
+	opts.MaxTokens = 0
+	opts.MIMEType = mimeType
+	opts.Language = language
+	return opts
+}
+
+// optionsFor fills an unset MaxChunkSize, and the token budget alongside it,
+// with the defaults for the chunker's content type. An explicit MaxChunkSize
+// leaves opts unchanged.
+func optionsFor(c Chunker, opts ChunkOptions) ChunkOptions {
+	if opts.MaxChunkSize != 0 {
+		return opts
+	}
+
+	chunkType := ChunkTypeUnknown
+	if typed, ok := c.(TypedChunker); ok {
+		chunkType = typed.ChunkType()
+	}
+
+	defaults := DefaultChunkOptionsForType(chunkType)
+	opts.MaxChunkSize = defaults.MaxChunkSize
+	if opts.MaxTokens == 0 {
+		opts.MaxTokens = defaults.MaxTokens
+	}
+	return opts
+}
+
 // List returns all registered chunkers.
 func (r *Registry) List() []Chunker {
 	r.mu.RLock()
@@ -2,6 +2,8 @@ package chunkers_test
 
 import (
 	"context"
+	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/leefowlercu/agentic-memorizer/internal/chunkers"
@@ -390,3 +392,150 @@ func TestDefaultRegistryTextFormatChunkerPriorities(t *testing.T) {
 		t.Error("odt should have higher priority than rst")
 	}
 }
+
+func TestDefaultRegistryChunkTypeSizeDefaults(t *testing.T) {
+	registry := chunkers.DefaultRegistry()
+	ctx := context.Background()
+
+	// A ~6000 byte section exceeds the markdown default but not the code default
+	var md strings.Builder
+	md.WriteString("# Notes\n\n")
+	for i := 0; i < 60; i++ {
+		md.WriteString(strings.Repeat("word ", 19) + "end.\n\n")
+	}
+
+	var goCode strings.Builder
+	goCode.WriteString("package main\n\nfunc big() {\n")
+	for i := 0; i < 60; i++ {
+		goCode.WriteString("\t// " + strings.Repeat("x", 96) + "\n")
+	}
+	goCode.WriteString("}\n")
+
+	t.Run("MarkdownUsesProseWindow", func(t *testing.T) {
+		result, err := registry.Chunk(ctx, []byte(md.String()), chunkers.ChunkOptions{MIMEType: "text/markdown"})
+		if err != nil {
+			t.Fatalf("Chunk failed: %v", err)
+		}
+		if len(result.Chunks) < 2 {
+			t.Fatalf("expected markdown section to split at the markdown default, got %d chunk(s)", len(result.Chunks))
+		}
+		for _, chunk := range result.Chunks {
+			if len(chunk.Content) > chunkers.DefaultChunkOptionsForType(chunkers.ChunkTypeMarkdown).MaxChunkSize {
+				t.Errorf("markdown chunk %d is %d bytes, exceeds markdown default", chunk.Index, len(chunk.Content))
+			}
+		}
+	})
+
+	t.Run("CodeKeepsFunctionWhole", func(t *testing.T) {
+		result, err := registry.Chunk(ctx, []byte(goCode.String()), chunkers.ChunkOptions{Language: "go"})
+		if err != nil {
+			t.Fatalf("Chunk failed: %v", err)
+		}
+		if result.ChunkerUsed != "treesitter" {
+			t.Fatalf("ChunkerUsed = %q, want treesitter", result.ChunkerUsed)
+		}
+		functions := 0
+		for _, chunk := range result.Chunks {
+			if chunk.Metadata.Code != nil && chunk.Metadata.Code.FunctionName == "big" {
+				functions++
+			}
+		}
+		if functions != 1 {
+			t.Errorf("expected function to stay in one chunk, got %d", functions)
+		}
+	})
+
+	t.Run("ExplicitSizeOverridesDefault", func(t *testing.T) {
+		result, err := registry.Chunk(ctx, []byte(md.String()), chunkers.ChunkOptions{
+			MIMEType:     "text/markdown",
+			MaxChunkSize: 8000,
+		})
+		if err != nil {
+			t.Fatalf("Chunk failed: %v", err)
+		}
+		if len(result.Chunks) != 1 {
+			t.Errorf("expected explicit MaxChunkSize to keep section whole, got %d chunks", len(result.Chunks))
+		}
+	})
+}
+
+func TestRegistryChunkContentJSON(t *testing.T) {
+	registry := chunkers.DefaultRegistry()
+	ctx := context.Background()
+
+	t.Run("GoSource", func(t *testing.T) {
+		source := []byte(`package mathx
+
+// Add returns the sum of a and b.
+func Add(a, b int) int {
+	return a + b
+}
+`)
+		result, err := registry.ChunkContent(ctx, source, "text/x-go", "go")
+		if err != nil {
+			t.Fatalf("ChunkContent failed: %v", err)
+		}
+
+		out, err := json.Marshal(result)
+		if err != nil {
+			t.Fatalf("json.Marshal failed: %v", err)
+		}
+		for _, want := range []string{`"ChunkerUsed":"treesitter"`, `"FunctionName":"Add"`, `"Docstring":"Add returns the sum of a and b."`} {
+			if !strings.Contains(string(out), want) {
+				t.Errorf("JSON output missing %s:\n%s", want, out)
+			}
+		}
+	})
+
+	t.Run("Markdown", func(t *testing.T) {
+		content := []byte("# Guide\n\nIntro.\n\n## Install\n\nRun the installer.\n")
+		result, err := registry.ChunkContent(ctx, content, "text/markdown", "")
+		if err != nil {
+			t.Fatalf("ChunkContent failed: %v", err)
+		}
+
+		out, err := json.Marshal(result)
+		if err != nil {
+			t.Fatalf("json.Marshal failed: %v", err)
+		}
+		for _, want := range []string{`"ChunkerUsed":"markdown"`, `"Heading":"Install"`, `"HeadingLevel":2`} {
+			if !strings.Contains(string(out), want) {
+				t.Errorf("JSON output missing %s:\n%s", want, out)
+			}
+		}
+	})
+}
+
+func TestRegistryRoutesScriptsByShebang(t *testing.T) {
+	registry := chunkers.DefaultRegistry()
+	script := []byte("#!/usr/bin/env bash\n\nbuild() {\n  go build ./...\n}\n")
+
+	// Extensionless scripts are sniffed as plain text with no language
+	for _, mimeType := range []string{"", "text/plain"} {
+		result, err := registry.Chunk(context.Background(), script, chunkers.ChunkOptions{MIMEType: mimeType})
+		if err != nil {
+			t.Fatalf("Chunk(mime=%q) failed: %v", mimeType, err)
+		}
+		if result.ChunkerUsed != "treesitter" {
+			t.Errorf("Chunk(mime=%q) used %q, want treesitter", mimeType, result.ChunkerUsed)
+		}
+		var functions []string
+		for _, chunk := range result.Chunks {
+			if code := chunk.Metadata.Code; code != nil && code.FunctionName != "" {
+				functions = append(functions, code.FunctionName)
+			}
+		}
+		if len(functions) != 1 || functions[0] != "build" {
+			t.Errorf("Chunk(mime=%q) functions = %v, want [build]", mimeType, functions)
+		}
+	}
+
+	// A declared format wins over the shebang
+	result, err := registry.Chunk(context.Background(), []byte("#!/bin/sh\n# Title\n\nBody.\n"), chunkers.ChunkOptions{MIMEType: "text/markdown"})
+	if err != nil {
+		t.Fatalf("Chunk(markdown) failed: %v", err)
+	}
+	if result.ChunkerUsed != "markdown" {
+		t.Errorf("Chunk(markdown) used %q, want markdown", result.ChunkerUsed)
+	}
+}
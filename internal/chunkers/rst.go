@@ -41,6 +41,11 @@ func (c *RSTChunker) Priority() int {
 	return rstChunkerPriority
 }
 
+// ChunkType returns the type of content this chunker produces.
+func (c *RSTChunker) ChunkType() ChunkType {
+	return ChunkTypeProse
+}
+
 // Chunk splits RST content by section headings.
 func (c *RSTChunker) Chunk(ctx context.Context, content []byte, opts ChunkOptions) (*ChunkResult, error) {
 	if len(content) == 0 {
@@ -55,7 +60,7 @@ func (c *RSTChunker) Chunk(ctx context.Context, content []byte, opts ChunkOption
 
 	maxSize := opts.MaxChunkSize
 	if maxSize <= 0 {
-		maxSize = DefaultChunkOptions().MaxChunkSize
+		maxSize = DefaultChunkOptionsForType(c.ChunkType()).MaxChunkSize
 	}
 
 	text := string(content)
@@ -91,8 +96,9 @@ func (c *RSTChunker) Chunk(ctx context.Context, content []byte, opts ChunkOption
 				StartOffset: offset,
 				EndOffset:   offset + len(section.content),
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeProse,
-					TokenEstimate: EstimateTokens(section.content),
+					Type:               ChunkTypeProse,
+					BoundaryConfidence: BoundaryConfidenceStructural,
+					TokenEstimate:      EstimateTokens(section.content),
 					Document: &DocumentMetadata{
 						Heading:      section.heading,
 						HeadingLevel: section.level,
@@ -326,8 +332,9 @@ func (c *RSTChunker) splitLargeSection(ctx context.Context, section rstSection,
 				StartOffset: offset - len(content),
 				EndOffset:   offset,
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeProse,
-					TokenEstimate: EstimateTokens(content),
+					Type:               ChunkTypeProse,
+					BoundaryConfidence: BoundaryConfidenceHeuristic,
+					TokenEstimate:      EstimateTokens(content),
 					Document: &DocumentMetadata{
 						Heading:      section.heading,
 						HeadingLevel: section.level,
@@ -353,8 +360,9 @@ func (c *RSTChunker) splitLargeSection(ctx context.Context, section rstSection,
 			StartOffset: offset - len(content),
 			EndOffset:   offset,
 			Metadata: ChunkMetadata{
-				Type:          ChunkTypeProse,
-				TokenEstimate: EstimateTokens(content),
+				Type:               ChunkTypeProse,
+				BoundaryConfidence: BoundaryConfidenceHeuristic,
+				TokenEstimate:      EstimateTokens(content),
 				Document: &DocumentMetadata{
 					Heading:      section.heading,
 					HeadingLevel: section.level,
@@ -0,0 +1,42 @@
+package chunkers
+
+import (
+	"bytes"
+	"path"
+	"strings"
+)
+
+// ShebangInterpreter returns the interpreter named by a script's "#!" line,
+// such as "bash" for both "#!/bin/bash" and "#!/usr/bin/env bash". Version
+// suffixes are dropped, so "#!/usr/bin/python3" yields "python". It returns an
+// empty string when content has no shebang.
+func ShebangInterpreter(content []byte) string {
+	if !bytes.HasPrefix(content, []byte("#!")) {
+		return ""
+	}
+	line := content[2:]
+	if i := bytes.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+
+	fields := strings.Fields(string(line))
+	if len(fields) == 0 {
+		return ""
+	}
+	interpreter := path.Base(fields[0])
+	if interpreter == "env" {
+		// Skip env options such as -S to reach the command name
+		interpreter = ""
+		for _, field := range fields[1:] {
+			if !strings.HasPrefix(field, "-") && !strings.Contains(field, "=") {
+				interpreter = path.Base(field)
+				break
+			}
+		}
+	}
+
+	if trimmed := strings.TrimRight(interpreter, "0123456789."); trimmed != "" {
+		interpreter = trimmed
+	}
+	return interpreter
+}
@@ -84,6 +84,11 @@ func (c *SQLChunker) Priority() int {
 	return sqlChunkerPriority
 }
 
+// ChunkType returns the type of content this chunker produces.
+func (c *SQLChunker) ChunkType() ChunkType {
+	return ChunkTypeStructured
+}
+
 // Chunk splits SQL content by statements.
 func (c *SQLChunker) Chunk(ctx context.Context, content []byte, opts ChunkOptions) (*ChunkResult, error) {
 	if len(content) == 0 {
@@ -98,7 +103,7 @@ func (c *SQLChunker) Chunk(ctx context.Context, content []byte, opts ChunkOption
 
 	maxSize := opts.MaxChunkSize
 	if maxSize <= 0 {
-		maxSize = DefaultChunkOptions().MaxChunkSize
+		maxSize = DefaultChunkOptionsForType(c.ChunkType()).MaxChunkSize
 	}
 
 	text := string(content)
@@ -135,9 +140,10 @@ func (c *SQLChunker) Chunk(ctx context.Context, content []byte, opts ChunkOption
 				StartOffset: offset,
 				EndOffset:   offset + len(groupContent),
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeStructured,
-					TokenEstimate: EstimateTokens(groupContent),
-					SQL:           meta,
+					Type:               ChunkTypeStructured,
+					BoundaryConfidence: BoundaryConfidenceStructural,
+					TokenEstimate:      EstimateTokens(groupContent),
+					SQL:                meta,
 				},
 			})
 		}
@@ -540,9 +546,10 @@ func (c *SQLChunker) splitLargeGroup(ctx context.Context, group []sqlStatement,
 				StartOffset: offset,
 				EndOffset:   offset + len(content),
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeStructured,
-					TokenEstimate: EstimateTokens(content),
-					SQL:           meta,
+					Type:               ChunkTypeStructured,
+					BoundaryConfidence: BoundaryConfidenceHeuristic,
+					TokenEstimate:      EstimateTokens(content),
+					SQL:                meta,
 				},
 			})
 			offset += len(content)
@@ -569,9 +576,10 @@ func (c *SQLChunker) splitLargeGroup(ctx context.Context, group []sqlStatement,
 			StartOffset: offset,
 			EndOffset:   offset + len(content),
 			Metadata: ChunkMetadata{
-				Type:          ChunkTypeStructured,
-				TokenEstimate: EstimateTokens(content),
-				SQL:           meta,
+				Type:               ChunkTypeStructured,
+				BoundaryConfidence: BoundaryConfidenceHeuristic,
+				TokenEstimate:      EstimateTokens(content),
+				SQL:                meta,
 			},
 		})
 	}
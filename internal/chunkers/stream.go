@@ -0,0 +1,56 @@
+package chunkers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// streamReadSize is how many bytes streaming chunkers request per read.
+const streamReadSize = 32 * 1024
+
+// streamChunks runs produce in its own goroutine and returns the channels of
+// a StreamChunker. produce calls emit for each chunk; emit blocks until the
+// chunk is received and fails once ctx is done. An error returned by produce
+// is delivered after the chunk channel closes.
+func streamChunks(ctx context.Context, produce func(emit func(Chunk) error) error) (<-chan Chunk, <-chan error) {
+	chunks := make(chan Chunk)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+
+		err := produce(func(chunk Chunk) error {
+			select {
+			case chunks <- chunk:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		close(chunks)
+		if err != nil {
+			errs <- err
+		}
+	}()
+
+	return chunks, errs
+}
+
+// fillBuffer reads from r into buf until buf holds at least n bytes or r is
+// exhausted, reporting whether r reached EOF.
+func fillBuffer(r io.Reader, buf []byte, n int) ([]byte, bool, error) {
+	for len(buf) < n {
+		buf = slices.Grow(buf, streamReadSize)
+		m, err := r.Read(buf[len(buf):cap(buf)])
+		buf = buf[:len(buf)+m]
+		if err == io.EOF {
+			return buf, true, nil
+		}
+		if err != nil {
+			return buf, false, fmt.Errorf("failed to read stream; %w", err)
+		}
+	}
+	return buf, false, nil
+}
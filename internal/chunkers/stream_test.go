@@ -0,0 +1,270 @@
+package chunkers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// generatedReader produces size bytes by repeating pattern, without holding
+// the generated content in memory, and records how much has been read.
+type generatedReader struct {
+	pattern string
+	size    int
+	read    atomic.Int64
+}
+
+func (g *generatedReader) Read(p []byte) (int, error) {
+	read := int(g.read.Load())
+	if read >= g.size {
+		return 0, io.EOF
+	}
+	n := min(len(p), g.size-read)
+	for i := range n {
+		p[i] = g.pattern[(read+i)%len(g.pattern)]
+	}
+	g.read.Add(int64(n))
+	return n, nil
+}
+
+// failingReader returns its content and then err.
+type failingReader struct {
+	content *bytes.Reader
+	err     error
+}
+
+func (f *failingReader) Read(p []byte) (int, error) {
+	if f.content.Len() == 0 {
+		return 0, f.err
+	}
+	return f.content.Read(p)
+}
+
+// collectStream drains a chunk stream.
+func collectStream(chunks <-chan Chunk, errs <-chan error) ([]Chunk, error) {
+	var result []Chunk
+	for chunk := range chunks {
+		result = append(result, chunk)
+	}
+	return result, <-errs
+}
+
+func TestChunkStreamBoundedMemory(t *testing.T) {
+	const maxSize = 1000
+
+	tests := []struct {
+		name    string
+		chunker StreamChunker
+		pattern string
+		// bound is how far reading may run ahead of the last chunk received
+		bound int
+		// contiguous chunkers leave no bytes between chunks; the recursive
+		// chunker drops the separators it splits on
+		contiguous bool
+	}{
+		{
+			name:       "fallback",
+			chunker:    NewFallbackChunker(),
+			pattern:    "lorem ipsum dolor sit amet ",
+			bound:      maxSize + 2*streamReadSize,
+			contiguous: true,
+		},
+		{
+			name:    "recursive",
+			chunker: NewRecursiveChunker(),
+			pattern: "A sentence of prose. Another one follows it.\n\n",
+			bound:   recursiveStreamWindow*maxSize + 2*streamReadSize,
+		},
+		{
+			name:       "log",
+			chunker:    NewLogChunker(),
+			pattern:    "2024-01-15T10:00:00Z INFO request served in 12ms\n",
+			bound:      logStreamDetectSize + 2*maxSize,
+			contiguous: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := &generatedReader{pattern: tt.pattern, size: maxSize * 500}
+			opts := ChunkOptions{MaxChunkSize: maxSize}
+			chunks, errs := tt.chunker.ChunkStream(context.Background(), reader, opts)
+
+			count, end := 0, 0
+			for chunk := range chunks {
+				// Only the chunk in hand and a bounded buffer have been read
+				if ahead := int(reader.read.Load()) - chunk.EndOffset; ahead > tt.bound {
+					t.Fatalf("chunk %d: read %d bytes ahead of consumption, want at most %d", chunk.Index, ahead, tt.bound)
+				}
+				if chunk.Index != count {
+					t.Fatalf("chunk index = %d, want %d", chunk.Index, count)
+				}
+				if tt.contiguous && chunk.StartOffset > end {
+					t.Fatalf("gap before chunk %d: starts at %d, previous ended at %d", chunk.Index, chunk.StartOffset, end)
+				}
+				count++
+				end = chunk.EndOffset
+			}
+			if err := <-errs; err != nil {
+				t.Fatalf("stream returned error: %v", err)
+			}
+			if count < 100 {
+				t.Errorf("expected at least 100 chunks, got %d", count)
+			}
+			// The log chunker counts a newline after the final line, as Chunk does
+			if end < reader.size {
+				t.Errorf("chunks end at %d, want %d", end, reader.size)
+			}
+		})
+	}
+}
+
+func TestChunkStreamMatchesChunk(t *testing.T) {
+	var logContent strings.Builder
+	for i := range 300 {
+		level := "INFO"
+		if i%37 == 0 {
+			level = "ERROR"
+		}
+		fmt.Fprintf(&logContent, "2024-01-15T10:%02d:%02dZ %s event %d handled\n", i/60, i%60, level, i)
+	}
+
+	tests := []struct {
+		name    string
+		chunker StreamChunker
+		content string
+		opts    ChunkOptions
+	}{
+		{
+			name:    "fallback",
+			chunker: NewFallbackChunker(),
+			content: strings.Repeat("日本語 text with some words 😀 ", 400),
+			opts:    ChunkOptions{MaxChunkSize: 500, Overlap: 50},
+		},
+		{
+			name:    "log",
+			chunker: NewLogChunker(),
+			content: logContent.String(),
+			opts:    ChunkOptions{MaxChunkSize: 1000},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, err := tt.chunker.Chunk(context.Background(), []byte(tt.content), tt.opts)
+			if err != nil {
+				t.Fatalf("Chunk returned error: %v", err)
+			}
+			got, err := collectStream(tt.chunker.ChunkStream(context.Background(), strings.NewReader(tt.content), tt.opts))
+			if err != nil {
+				t.Fatalf("stream returned error: %v", err)
+			}
+			if !reflect.DeepEqual(got, want.Chunks) {
+				t.Errorf("streamed %d chunks that differ from Chunk's %d", len(got), len(want.Chunks))
+			}
+		})
+	}
+
+	t.Run("recursive keeps every word in order", func(t *testing.T) {
+		var sb strings.Builder
+		for i := range 500 {
+			fmt.Fprintf(&sb, "Paragraph %d has a few words in it.\n\n", i)
+		}
+		content := sb.String()
+
+		opts := ChunkOptions{MaxChunkSize: 300}
+		got, err := collectStream(NewRecursiveChunker().ChunkStream(context.Background(), strings.NewReader(content), opts))
+		if err != nil {
+			t.Fatalf("stream returned error: %v", err)
+		}
+		var words []string
+		for _, chunk := range got {
+			words = append(words, strings.Fields(chunk.Content)...)
+			if chunk.Metadata.LineStart == 0 || chunk.Metadata.LineEnd < chunk.Metadata.LineStart {
+				t.Errorf("chunk %d has line range %d-%d", chunk.Index, chunk.Metadata.LineStart, chunk.Metadata.LineEnd)
+			}
+		}
+		if !reflect.DeepEqual(words, strings.Fields(content)) {
+			t.Error("streamed chunks do not contain the content's words in order")
+		}
+
+		last := got[len(got)-1]
+		if want := strings.Count(content, "\n") - 1; last.Metadata.LineEnd != want {
+			t.Errorf("last chunk ends on line %d, want %d", last.Metadata.LineEnd, want)
+		}
+	})
+}
+
+func TestChunkStreamErrors(t *testing.T) {
+	t.Run("read error", func(t *testing.T) {
+		readErr := errors.New("disk on fire")
+		reader := &failingReader{content: bytes.NewReader([]byte(strings.Repeat("words ", 1000))), err: readErr}
+		chunks, errs := NewFallbackChunker().ChunkStream(context.Background(), reader, ChunkOptions{MaxChunkSize: 100})
+		for range chunks {
+		}
+		if err := <-errs; !errors.Is(err, readErr) {
+			t.Errorf("error = %v, want %v", err, readErr)
+		}
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		reader := &generatedReader{pattern: "some text ", size: 1 << 20}
+		chunks, errs := NewFallbackChunker().ChunkStream(ctx, reader, ChunkOptions{MaxChunkSize: 100})
+		<-chunks
+		cancel()
+		for range chunks {
+		}
+		if err := <-errs; !errors.Is(err, context.Canceled) {
+			t.Errorf("error = %v, want %v", err, context.Canceled)
+		}
+	})
+}
+
+func TestRegistryChunkStream(t *testing.T) {
+	registry := DefaultRegistry()
+
+	t.Run("streams with a streaming chunker", func(t *testing.T) {
+		reader := &generatedReader{pattern: "Plain text sentence. ", size: 1 << 20}
+		opts := DefaultChunkOptions()
+		opts.MIMEType = "text/plain"
+		chunks, errs := registry.ChunkStream(context.Background(), reader, opts)
+
+		first := <-chunks
+		if int(reader.read.Load()) >= reader.size {
+			t.Error("expected the reader not to be drained before the first chunk")
+		}
+		if first.Metadata.Type != ChunkTypeProse {
+			t.Errorf("chunk type = %q, want %q", first.Metadata.Type, ChunkTypeProse)
+		}
+		for range chunks {
+		}
+		if err := <-errs; err != nil {
+			t.Fatalf("stream returned error: %v", err)
+		}
+	})
+
+	t.Run("falls back to Chunk otherwise", func(t *testing.T) {
+		content := "# Title\n\nIntro.\n\n## Section\n\nBody.\n"
+		opts := DefaultChunkOptions()
+		opts.MIMEType = "text/markdown"
+
+		want, err := registry.Chunk(context.Background(), []byte(content), opts)
+		if err != nil {
+			t.Fatalf("Chunk returned error: %v", err)
+		}
+		got, err := collectStream(registry.ChunkStream(context.Background(), strings.NewReader(content), opts))
+		if err != nil {
+			t.Fatalf("stream returned error: %v", err)
+		}
+		if !reflect.DeepEqual(got, want.Chunks) {
+			t.Errorf("streamed %d chunks that differ from Chunk's %d", len(got), len(want.Chunks))
+		}
+	})
+}
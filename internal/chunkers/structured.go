@@ -1,8 +1,12 @@
 package chunkers
 
 import (
+	"bytes"
 	"context"
+	"encoding/csv"
 	"encoding/json"
+	"fmt"
+	"io"
 	"strings"
 )
 
@@ -29,6 +33,8 @@ func (c *StructuredChunker) CanHandle(mimeType string, language string) bool {
 	switch mimeType {
 	case "application/json", "text/json":
 		return true
+	case "application/x-ndjson":
+		return true
 	case "application/x-yaml", "text/yaml", "text/x-yaml":
 		return true
 	case "text/csv":
@@ -42,7 +48,13 @@ func (c *StructuredChunker) Priority() int {
 	return structuredChunkerPriority
 }
 
-// Chunk splits structured content by records.
+// ChunkType returns the type of content this chunker produces.
+func (c *StructuredChunker) ChunkType() ChunkType {
+	return ChunkTypeStructured
+}
+
+// Chunk splits structured content by records, or purely by size when
+// opts.Flatten is set.
 func (c *StructuredChunker) Chunk(ctx context.Context, content []byte, opts ChunkOptions) (*ChunkResult, error) {
 	if len(content) == 0 {
 		return &ChunkResult{
@@ -57,17 +69,28 @@ func (c *StructuredChunker) Chunk(ctx context.Context, content []byte, opts Chun
 	mimeType := opts.MIMEType
 	maxSize := opts.MaxChunkSize
 	if maxSize <= 0 {
-		maxSize = DefaultChunkOptions().MaxChunkSize
+		maxSize = DefaultChunkOptionsForType(c.ChunkType()).MaxChunkSize
 	}
 
 	var chunks []Chunk
 	var err error
 
 	switch {
+	case opts.Flatten:
+		chunks, err = chunkBySize(ctx, content, opts, func(string) ChunkMetadata {
+			return ChunkMetadata{
+				Type:       ChunkTypeStructured,
+				Structured: &StructuredMetadata{},
+			}
+		})
+	case strings.Contains(mimeType, "ndjson"):
+		chunks, err = c.chunkNDJSON(ctx, content, maxSize, opts.RecordsPerChunk)
 	case strings.Contains(mimeType, "json"):
-		chunks, err = c.chunkJSON(ctx, content, maxSize)
+		chunks, err = c.chunkJSON(ctx, content, maxSize, opts.RecordsPerChunk)
 	case strings.Contains(mimeType, "csv"):
 		chunks, err = c.chunkCSV(ctx, content, maxSize)
+	case strings.Contains(mimeType, "yaml"):
+		chunks, err = c.chunkYAML(ctx, content, maxSize)
 	default:
 		// Fallback to line-based chunking for unknown structured formats
 		chunks, err = c.chunkLines(ctx, content, maxSize)
@@ -76,6 +99,7 @@ func (c *StructuredChunker) Chunk(ctx context.Context, content []byte, opts Chun
 	if err != nil {
 		return nil, err
 	}
+	setLineRanges(content, chunks)
 
 	return &ChunkResult{
 		Chunks:       chunks,
@@ -87,17 +111,13 @@ func (c *StructuredChunker) Chunk(ctx context.Context, content []byte, opts Chun
 }
 
 // chunkJSON splits JSON content by array elements or object keys.
-func (c *StructuredChunker) chunkJSON(ctx context.Context, content []byte, maxSize int) ([]Chunk, error) {
-	// Try to parse as array
-	var arr []json.RawMessage
-	if err := json.Unmarshal(content, &arr); err == nil {
-		return c.chunkJSONArray(ctx, arr, maxSize)
-	}
-
-	// Try to parse as object
-	var obj map[string]json.RawMessage
-	if err := json.Unmarshal(content, &obj); err == nil {
-		return c.chunkJSONObject(ctx, obj, content, maxSize)
+func (c *StructuredChunker) chunkJSON(ctx context.Context, content []byte, maxSize, recordsPerChunk int) ([]Chunk, error) {
+	members, isArray, err := scanJSONContainer(content)
+	if err == nil {
+		if isArray {
+			return c.chunkJSONArray(ctx, members, maxSize, recordsPerChunk)
+		}
+		return c.chunkJSONObject(ctx, members, content, maxSize)
 	}
 
 	// Fall back to treating as single chunk
@@ -108,73 +128,236 @@ func (c *StructuredChunker) chunkJSON(ctx context.Context, content []byte, maxSi
 		StartOffset: 0,
 		EndOffset:   len(content),
 		Metadata: ChunkMetadata{
-			Type:          ChunkTypeStructured,
-			TokenEstimate: EstimateTokens(contentStr),
-			Structured:    &StructuredMetadata{},
+			Type:               ChunkTypeStructured,
+			BoundaryConfidence: BoundaryConfidenceHeuristic,
+			TokenEstimate:      EstimateTokens(contentStr),
+			Structured:         &StructuredMetadata{},
 		},
 	}}, nil
 }
 
+// jsonMember is a top-level array element or object member with its byte
+// range in the source. Key is empty for array elements.
+type jsonMember struct {
+	key        string
+	value      json.RawMessage
+	start, end int
+}
+
+// scanJSONContainer decodes the top-level elements of a JSON array, or the
+// members of a JSON object, in source order. It reports whether the content
+// is an array and fails for any other or malformed JSON.
+func scanJSONContainer(content []byte) ([]jsonMember, bool, error) {
+	dec := json.NewDecoder(bytes.NewReader(content))
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, false, err
+	}
+	if tok == nil {
+		// A top-level null decodes as an empty array
+		if _, err := dec.Token(); err != io.EOF {
+			return nil, false, fmt.Errorf("unexpected data after top-level JSON value")
+		}
+		return nil, true, nil
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok || (delim != '[' && delim != '{') {
+		return nil, false, fmt.Errorf("not a JSON array or object")
+	}
+	isArray := delim == '['
+
+	var members []jsonMember
+	for dec.More() {
+		m := jsonMember{start: skipJSONSeparators(content, int(dec.InputOffset()))}
+		if !isArray {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, false, err
+			}
+			m.key, _ = key.(string)
+		}
+		if err := dec.Decode(&m.value); err != nil {
+			return nil, false, err
+		}
+		m.end = int(dec.InputOffset())
+		members = append(members, m)
+	}
+
+	// Consume the closing delimiter and reject trailing data
+	if _, err := dec.Token(); err != nil {
+		return nil, false, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, false, fmt.Errorf("unexpected data after top-level JSON value")
+	}
+
+	return members, isArray, nil
+}
+
+// skipJSONSeparators returns the offset of the first byte at or after i that
+// is not whitespace or a comma.
+func skipJSONSeparators(content []byte, i int) int {
+	for i < len(content) && strings.IndexByte(" \t\r\n,", content[i]) >= 0 {
+		i++
+	}
+	return i
+}
+
 // chunkJSONArray splits a JSON array into chunks of records.
-func (c *StructuredChunker) chunkJSONArray(ctx context.Context, arr []json.RawMessage, maxSize int) ([]Chunk, error) {
-	var chunks []Chunk
-	var currentRecords []json.RawMessage
-	currentSize := 2 // "[]"
-	offset := 0
+func (c *StructuredChunker) chunkJSONArray(ctx context.Context, elems []jsonMember, maxSize, recordsPerChunk int) ([]Chunk, error) {
+	sizes := make([]int, len(elems))
+	for i, elem := range elems {
+		sizes[i] = len(elem.value)
+	}
 
-	for _, record := range arr {
+	groups, err := groupRecords(ctx, sizes, 2, maxSize, recordsPerChunk) // "[]"
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := make([]Chunk, 0, len(groups))
+	for _, g := range groups {
+		chunks = append(chunks, c.createArrayChunk(elems[g.start:g.end], len(chunks), g.start))
+	}
+
+	return chunks, nil
+}
+
+// recordGroup is a half-open range of record indexes forming one chunk.
+type recordGroup struct {
+	start, end int
+}
+
+// groupRecords partitions records into consecutive groups. With recordsPerChunk
+// set, each group holds that many records; otherwise groups are filled up to
+// maxSize, counting overhead bytes per group and one separator byte per record.
+// A record larger than maxSize always forms its own group.
+func groupRecords(ctx context.Context, sizes []int, overhead, maxSize, recordsPerChunk int) ([]recordGroup, error) {
+	var groups []recordGroup
+	start := 0
+	currentSize := overhead
+
+	for i, size := range sizes {
 		select {
 		case <-ctx.Done():
 			return nil, ctx.Err()
 		default:
 		}
 
-		recordSize := len(record)
-		if currentSize+recordSize+1 > maxSize && len(currentRecords) > 0 {
-			chunk := c.createArrayChunk(currentRecords, len(chunks), offset)
-			chunks = append(chunks, chunk)
-			offset += len(chunk.Content)
-			currentRecords = nil
-			currentSize = 2
+		oversized := overhead+size > maxSize
+		full := currentSize+size+1 > maxSize
+		if recordsPerChunk > 0 {
+			full = i-start >= recordsPerChunk
+		}
+		if i > start && (full || oversized) {
+			groups = append(groups, recordGroup{start: start, end: i})
+			start = i
+			currentSize = overhead
 		}
 
-		currentRecords = append(currentRecords, record)
-		currentSize += recordSize + 1 // +1 for comma
+		currentSize += size + 1 // +1 for separator
+		if oversized && recordsPerChunk > 0 {
+			groups = append(groups, recordGroup{start: i, end: i + 1})
+			start = i + 1
+			currentSize = overhead
+		}
 	}
 
 	// Finalize remaining records
-	if len(currentRecords) > 0 {
-		chunk := c.createArrayChunk(currentRecords, len(chunks), offset)
-		chunks = append(chunks, chunk)
+	if start < len(sizes) {
+		groups = append(groups, recordGroup{start: start, end: len(sizes)})
 	}
 
-	return chunks, nil
+	return groups, nil
 }
 
-// createArrayChunk creates a chunk from array records.
-func (c *StructuredChunker) createArrayChunk(records []json.RawMessage, index, offset int) Chunk {
+// createArrayChunk creates a chunk from array records starting at recordIndex.
+// Offsets span the records in the source array.
+func (c *StructuredChunker) createArrayChunk(records []jsonMember, index, recordIndex int) Chunk {
+	values := make([]json.RawMessage, len(records))
+	for i, r := range records {
+		values[i] = r.value
+	}
+
 	// Re-marshal as array
-	data, _ := json.Marshal(records)
+	data, _ := json.Marshal(values)
 	content := string(data)
 
 	return Chunk{
 		Index:       index,
 		Content:     content,
-		StartOffset: offset,
-		EndOffset:   offset + len(content),
+		StartOffset: records[0].start,
+		EndOffset:   records[len(records)-1].end,
 		Metadata: ChunkMetadata{
-			Type:          ChunkTypeStructured,
-			TokenEstimate: EstimateTokens(content),
+			Type:               ChunkTypeStructured,
+			BoundaryConfidence: BoundaryConfidenceStructural,
+			TokenEstimate:      EstimateTokens(content),
 			Structured: &StructuredMetadata{
-				RecordIndex: index,
+				RecordIndex: recordIndex,
 				RecordCount: len(records),
 			},
 		},
 	}
 }
 
-// chunkJSONObject splits a JSON object by top-level keys.
-func (c *StructuredChunker) chunkJSONObject(ctx context.Context, obj map[string]json.RawMessage, original []byte, maxSize int) ([]Chunk, error) {
+// chunkNDJSON splits newline-delimited JSON into chunks of lines.
+func (c *StructuredChunker) chunkNDJSON(ctx context.Context, content []byte, maxSize, recordsPerChunk int) ([]Chunk, error) {
+	type line struct {
+		text   string
+		offset int
+	}
+
+	var lines []line
+	offset := 0
+	for _, text := range strings.SplitAfter(string(content), "\n") {
+		if trimmed := strings.TrimSpace(text); trimmed != "" {
+			lines = append(lines, line{text: trimmed, offset: offset + strings.Index(text, trimmed)})
+		}
+		offset += len(text)
+	}
+
+	sizes := make([]int, len(lines))
+	for i, l := range lines {
+		sizes[i] = len(l.text)
+	}
+
+	groups, err := groupRecords(ctx, sizes, 0, maxSize, recordsPerChunk)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := make([]Chunk, 0, len(groups))
+	for _, g := range groups {
+		var b strings.Builder
+		for _, l := range lines[g.start:g.end] {
+			b.WriteString(l.text)
+			b.WriteString("\n")
+		}
+		chunkContent := b.String()
+		last := lines[g.end-1]
+
+		chunks = append(chunks, Chunk{
+			Index:       len(chunks),
+			Content:     chunkContent,
+			StartOffset: lines[g.start].offset,
+			EndOffset:   last.offset + len(last.text),
+			Metadata: ChunkMetadata{
+				Type:               ChunkTypeStructured,
+				BoundaryConfidence: BoundaryConfidenceStructural,
+				TokenEstimate:      EstimateTokens(chunkContent),
+				Structured: &StructuredMetadata{
+					RecordIndex: g.start,
+					RecordCount: g.end - g.start,
+				},
+			},
+		})
+	}
+
+	return chunks, nil
+}
+
+// chunkJSONObject splits a JSON object by top-level keys in source order.
+func (c *StructuredChunker) chunkJSONObject(ctx context.Context, members []jsonMember, original []byte, maxSize int) ([]Chunk, error) {
 	// If object fits in one chunk, return as-is
 	if len(original) <= maxSize {
 		contentStr := string(original)
@@ -184,57 +367,54 @@ func (c *StructuredChunker) chunkJSONObject(ctx context.Context, obj map[string]
 			StartOffset: 0,
 			EndOffset:   len(original),
 			Metadata: ChunkMetadata{
-				Type:          ChunkTypeStructured,
-				TokenEstimate: EstimateTokens(contentStr),
-				Structured:    &StructuredMetadata{},
+				Type:               ChunkTypeStructured,
+				BoundaryConfidence: BoundaryConfidenceStructural,
+				TokenEstimate:      EstimateTokens(contentStr),
+				Structured:         &StructuredMetadata{},
 			},
 		}}, nil
 	}
 
 	// Split by top-level keys
 	var chunks []Chunk
-	var currentKeys []string
-	var currentVals []json.RawMessage
+	var current []jsonMember
 	currentSize := 2 // "{}"
-	offset := 0
 
-	for key, val := range obj {
+	for _, member := range members {
 		select {
 		case <-ctx.Done():
 			return nil, ctx.Err()
 		default:
 		}
 
-		entrySize := len(key) + len(val) + 4 // "key":val,
+		entrySize := len(member.key) + len(member.value) + 4 // "key":val,
 
-		if currentSize+entrySize > maxSize && len(currentKeys) > 0 {
-			chunk := c.createObjectChunk(currentKeys, currentVals, len(chunks), offset)
-			chunks = append(chunks, chunk)
-			offset += len(chunk.Content)
-			currentKeys = nil
-			currentVals = nil
+		if currentSize+entrySize > maxSize && len(current) > 0 {
+			chunks = append(chunks, c.createObjectChunk(current, len(chunks)))
+			current = nil
 			currentSize = 2
 		}
 
-		currentKeys = append(currentKeys, key)
-		currentVals = append(currentVals, val)
+		current = append(current, member)
 		currentSize += entrySize
 	}
 
 	// Finalize remaining
-	if len(currentKeys) > 0 {
-		chunk := c.createObjectChunk(currentKeys, currentVals, len(chunks), offset)
-		chunks = append(chunks, chunk)
+	if len(current) > 0 {
+		chunks = append(chunks, c.createObjectChunk(current, len(chunks)))
 	}
 
 	return chunks, nil
 }
 
-// createObjectChunk creates a chunk from object entries.
-func (c *StructuredChunker) createObjectChunk(keys []string, vals []json.RawMessage, index, offset int) Chunk {
-	obj := make(map[string]json.RawMessage)
-	for i, k := range keys {
-		obj[k] = vals[i]
+// createObjectChunk creates a chunk from object entries. Offsets span the
+// entries in the source object.
+func (c *StructuredChunker) createObjectChunk(members []jsonMember, index int) Chunk {
+	keys := make([]string, len(members))
+	obj := make(map[string]json.RawMessage, len(members))
+	for i, m := range members {
+		keys[i] = m.key
+		obj[m.key] = m.value
 	}
 	data, _ := json.Marshal(obj)
 	content := string(data)
@@ -242,11 +422,12 @@ func (c *StructuredChunker) createObjectChunk(keys []string, vals []json.RawMess
 	return Chunk{
 		Index:       index,
 		Content:     content,
-		StartOffset: offset,
-		EndOffset:   offset + len(content),
+		StartOffset: members[0].start,
+		EndOffset:   members[len(members)-1].end,
 		Metadata: ChunkMetadata{
-			Type:          ChunkTypeStructured,
-			TokenEstimate: EstimateTokens(content),
+			Type:               ChunkTypeStructured,
+			BoundaryConfidence: BoundaryConfidenceStructural,
+			TokenEstimate:      EstimateTokens(content),
 			Structured: &StructuredMetadata{
 				RecordIndex: index,
 				KeyNames:    keys,
@@ -255,78 +436,63 @@ func (c *StructuredChunker) createObjectChunk(keys []string, vals []json.RawMess
 	}
 }
 
-// chunkCSV splits CSV content by rows.
+// chunkCSV splits CSV content by records. Each chunk repeats the header row,
+// whose columns are recorded as KeyNames.
 func (c *StructuredChunker) chunkCSV(ctx context.Context, content []byte, maxSize int) ([]Chunk, error) {
-	lines := strings.Split(string(content), "\n")
-	if len(lines) == 0 {
-		return []Chunk{}, nil
-	}
+	records := splitCSVRecords(content)
 
 	// Keep header for context
 	header := ""
-	startIdx := 0
-	if len(lines) > 0 && len(lines[0]) > 0 {
-		header = lines[0] + "\n"
-		startIdx = 1
+	var keyNames []string
+	if len(records) > 0 && len(records[0].text) > 0 {
+		header = records[0].text + "\n"
+		keyNames = parseCSVHeader(records[0].text)
+		records = records[1:]
 	}
 
-	var chunks []Chunk
-	var current strings.Builder
-	current.WriteString(header)
-	offset := len(header)
-	recordIndex := 0
-
-	for i := startIdx; i < len(lines); i++ {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		default:
-		}
-
-		line := lines[i]
-		if strings.TrimSpace(line) == "" {
-			continue
+	// Blank rows are not records
+	rows := records[:0]
+	for _, r := range records {
+		if strings.TrimSpace(r.text) != "" {
+			rows = append(rows, r)
 		}
+	}
 
-		lineLen := len(line) + 1 // +1 for newline
-		if current.Len()+lineLen > maxSize && current.Len() > len(header) {
-			chunkContent := current.String()
-			chunks = append(chunks, Chunk{
-				Index:       len(chunks),
-				Content:     chunkContent,
-				StartOffset: offset - current.Len(),
-				EndOffset:   offset,
-				Metadata: ChunkMetadata{
-					Type:          ChunkTypeStructured,
-					TokenEstimate: EstimateTokens(chunkContent),
-					Structured: &StructuredMetadata{
-						RecordIndex: recordIndex,
-					},
-				},
-			})
-			current.Reset()
-			current.WriteString(header)
-			recordIndex = i
-		}
+	sizes := make([]int, len(rows))
+	for i, r := range rows {
+		sizes[i] = len(r.text)
+	}
 
-		current.WriteString(line)
-		current.WriteString("\n")
-		offset += lineLen
+	groups, err := groupRecords(ctx, sizes, len(header), maxSize, 0)
+	if err != nil {
+		return nil, err
 	}
 
-	// Finalize
-	if current.Len() > len(header) {
-		chunkContent := current.String()
+	chunks := make([]Chunk, 0, len(groups))
+	for _, g := range groups {
+		var b strings.Builder
+		b.WriteString(header)
+		for _, r := range rows[g.start:g.end] {
+			b.WriteString(r.text)
+			b.WriteString("\n")
+		}
+		chunkContent := b.String()
+		last := rows[g.end-1]
+
+		// Chunks repeat the header, so offsets span only their own rows
 		chunks = append(chunks, Chunk{
 			Index:       len(chunks),
 			Content:     chunkContent,
-			StartOffset: offset - current.Len(),
-			EndOffset:   offset,
+			StartOffset: rows[g.start].offset,
+			EndOffset:   min(last.offset+len(last.text)+1, len(content)),
 			Metadata: ChunkMetadata{
-				Type:          ChunkTypeStructured,
-				TokenEstimate: EstimateTokens(chunkContent),
+				Type:               ChunkTypeStructured,
+				BoundaryConfidence: BoundaryConfidenceStructural,
+				TokenEstimate:      EstimateTokens(chunkContent),
 				Structured: &StructuredMetadata{
-					RecordIndex: recordIndex,
+					RecordIndex: g.start,
+					RecordCount: len(rows),
+					KeyNames:    keyNames,
 				},
 			},
 		})
@@ -335,9 +501,58 @@ func (c *StructuredChunker) chunkCSV(ctx context.Context, content []byte, maxSiz
 	return chunks, nil
 }
 
+// csvRecord is the raw text of one CSV record, without its line terminator,
+// and its offset in the source.
+type csvRecord struct {
+	text   string
+	offset int
+}
+
+// splitCSVRecords splits CSV content into records at newlines outside quoted
+// fields, so a quoted field spanning lines stays in one record.
+func splitCSVRecords(content []byte) []csvRecord {
+	var records []csvRecord
+	inQuotes := false
+	start := 0
+
+	for i, b := range content {
+		switch b {
+		case '"':
+			// An escaped quote ("") toggles twice
+			inQuotes = !inQuotes
+		case '\n':
+			if !inQuotes {
+				records = append(records, csvRecord{text: string(content[start:i]), offset: start})
+				start = i + 1
+			}
+		}
+	}
+
+	if start < len(content) {
+		records = append(records, csvRecord{text: string(content[start:]), offset: start})
+	}
+
+	return records
+}
+
+// parseCSVHeader returns the column names of a CSV header row, or nil if the
+// row does not parse.
+func parseCSVHeader(row string) []string {
+	r := csv.NewReader(strings.NewReader(row))
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+
+	keys, err := r.Read()
+	if err != nil {
+		return nil
+	}
+	keys[0] = strings.TrimPrefix(keys[0], "\ufeff")
+	return keys
+}
+
 // chunkLines splits content by lines.
 func (c *StructuredChunker) chunkLines(ctx context.Context, content []byte, maxSize int) ([]Chunk, error) {
-	lines := strings.Split(string(content), "\n")
+	lines := strings.SplitAfter(string(content), "\n")
 
 	var chunks []Chunk
 	var current strings.Builder
@@ -350,7 +565,7 @@ func (c *StructuredChunker) chunkLines(ctx context.Context, content []byte, maxS
 		default:
 		}
 
-		lineLen := len(line) + 1
+		lineLen := len(line)
 		if current.Len()+lineLen > maxSize && current.Len() > 0 {
 			chunkContent := current.String()
 			chunks = append(chunks, Chunk{
@@ -359,16 +574,16 @@ func (c *StructuredChunker) chunkLines(ctx context.Context, content []byte, maxS
 				StartOffset: offset - current.Len(),
 				EndOffset:   offset,
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeStructured,
-					TokenEstimate: EstimateTokens(chunkContent),
-					Structured:    &StructuredMetadata{},
+					Type:               ChunkTypeStructured,
+					BoundaryConfidence: BoundaryConfidenceHeuristic,
+					TokenEstimate:      EstimateTokens(chunkContent),
+					Structured:         &StructuredMetadata{},
 				},
 			})
 			current.Reset()
 		}
 
 		current.WriteString(line)
-		current.WriteString("\n")
 		offset += lineLen
 	}
 
@@ -380,9 +595,10 @@ func (c *StructuredChunker) chunkLines(ctx context.Context, content []byte, maxS
 			StartOffset: offset - current.Len(),
 			EndOffset:   offset,
 			Metadata: ChunkMetadata{
-				Type:          ChunkTypeStructured,
-				TokenEstimate: EstimateTokens(chunkContent),
-				Structured:    &StructuredMetadata{},
+				Type:               ChunkTypeStructured,
+				BoundaryConfidence: BoundaryConfidenceHeuristic,
+				TokenEstimate:      EstimateTokens(chunkContent),
+				Structured:         &StructuredMetadata{},
 			},
 		})
 	}
@@ -0,0 +1,362 @@
+package chunkers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlLine is a source line, including its line terminator, and its offset.
+type yamlLine struct {
+	text   string
+	offset int
+}
+
+// yamlSection is a half-open range of source lines holding a document, mapping
+// entry or sequence item, along with its parsed value.
+type yamlSection struct {
+	name       string
+	path       string
+	start, end int
+	value      *yaml.Node
+
+	// line is the index of the key or sequence dash line; lines before it
+	// are leading comments or document markers
+	line int
+
+	// base is the line index that the value's node positions are relative to
+	base int
+}
+
+// yamlChunker chunks the documents of one YAML stream.
+type yamlChunker struct {
+	ctx      context.Context
+	lines    []yamlLine
+	maxSize  int
+	document int
+}
+
+// chunkYAML splits YAML content into chunks of whole top-level entries, one
+// document at a time. An entry larger than maxSize is split at its child
+// entries, repeating its key line for context, and only scalars that cannot
+// be split further are cut between lines. Content that does not parse falls
+// back to line-based chunking.
+func (c *StructuredChunker) chunkYAML(ctx context.Context, content []byte, maxSize int) ([]Chunk, error) {
+	lines := splitYAMLLines(content)
+
+	docs, err := parseYAMLDocuments(lines)
+	if err != nil {
+		return c.chunkLines(ctx, content, maxSize)
+	}
+
+	var chunks []Chunk
+	for i, doc := range docs {
+		y := &yamlChunker{ctx: ctx, lines: lines, maxSize: maxSize, document: i}
+
+		sections := yamlChildren(lines, doc, doc.start)
+		if sections == nil {
+			sections = []yamlSection{doc}
+		}
+
+		docChunks, err := y.chunkSections(sections, "", doc.path)
+		if err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, docChunks...)
+	}
+
+	for i := range chunks {
+		chunks[i].Index = i
+	}
+
+	return chunks, nil
+}
+
+// splitYAMLLines splits content into lines, keeping line terminators.
+func splitYAMLLines(content []byte) []yamlLine {
+	var lines []yamlLine
+	offset := 0
+	for _, text := range strings.SplitAfter(string(content), "\n") {
+		if text == "" {
+			continue
+		}
+		lines = append(lines, yamlLine{text: text, offset: offset})
+		offset += len(text)
+	}
+	return lines
+}
+
+// parseYAMLDocuments splits lines into documents at "---" markers and parses
+// each one. Comments and directives before a document are kept with it, and
+// trailing comments with the last document.
+func parseYAMLDocuments(lines []yamlLine) ([]yamlSection, error) {
+	var docs []yamlSection
+	pending := 0
+
+	regionStart := 0
+	for i := 0; i <= len(lines); i++ {
+		if i < len(lines) && (i == regionStart || !isYAMLDocumentStart(lines[i].text)) {
+			continue
+		}
+
+		var b strings.Builder
+		for _, l := range lines[regionStart:i] {
+			b.WriteString(l.text)
+		}
+
+		dec := yaml.NewDecoder(strings.NewReader(b.String()))
+		var node yaml.Node
+		err := dec.Decode(&node)
+		switch {
+		case errors.Is(err, io.EOF):
+			// Nothing but comments or directives; keep them with the next document
+		case err != nil:
+			return nil, err
+		default:
+			var extra yaml.Node
+			if err := dec.Decode(&extra); !errors.Is(err, io.EOF) {
+				return nil, fmt.Errorf("unexpected document without a --- marker")
+			}
+
+			var value *yaml.Node
+			if len(node.Content) > 0 {
+				value = node.Content[0]
+			}
+			docs = append(docs, yamlSection{start: pending, end: i, line: pending, value: value, base: regionStart})
+			pending = i
+		}
+
+		regionStart = i
+	}
+
+	if pending < len(lines) {
+		if len(docs) > 0 {
+			docs[len(docs)-1].end = len(lines)
+		} else {
+			docs = append(docs, yamlSection{start: pending, end: len(lines), line: pending})
+		}
+	}
+
+	return docs, nil
+}
+
+// isYAMLDocumentStart reports whether a line is a "---" document marker.
+func isYAMLDocumentStart(line string) bool {
+	rest, ok := strings.CutPrefix(line, "---")
+	return ok && (rest == "" || strings.ContainsAny(rest[:1], " \t\r\n"))
+}
+
+// yamlChildren returns the sections of a block mapping's entries or a block
+// sequence's items, or nil if the value has none or their lines cannot be
+// located. Children may begin no earlier than line from; the first child
+// also takes the lines between from and its first line.
+func yamlChildren(lines []yamlLine, sec yamlSection, from int) []yamlSection {
+	v := sec.value
+	if v == nil || v.Style&yaml.FlowStyle != 0 {
+		return nil
+	}
+
+	var children []yamlSection
+	switch v.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(v.Content); i += 2 {
+			key := v.Content[i]
+			children = append(children, yamlSection{
+				name:  key.Value,
+				path:  sec.path + "/" + key.Value,
+				start: sec.base + key.Line - 1,
+				value: v.Content[i+1],
+				base:  sec.base,
+			})
+		}
+	case yaml.SequenceNode:
+		// Item nodes start after the dash, so find the dashes at the
+		// sequence's indentation instead
+		indent := v.Column - 1
+		for i := from; i < sec.end && len(children) < len(v.Content); i++ {
+			if isYAMLSequenceItem(lines[i].text, indent) {
+				children = append(children, yamlSection{
+					path:  sec.path + "/" + strconv.Itoa(len(children)),
+					start: i,
+					value: v.Content[len(children)],
+					base:  sec.base,
+				})
+			}
+		}
+		if len(children) != len(v.Content) {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	if len(children) == 0 {
+		return nil
+	}
+
+	for i := range children {
+		if children[i].start < from || children[i].start >= sec.end ||
+			(i > 0 && children[i].start <= children[i-1].start) {
+			return nil
+		}
+		if i+1 < len(children) {
+			children[i].end = children[i+1].start
+		} else {
+			children[i].end = sec.end
+		}
+		children[i].line = children[i].start
+	}
+	children[0].start = from
+
+	return children
+}
+
+// isYAMLSequenceItem reports whether line begins a block sequence item at the
+// given indentation.
+func isYAMLSequenceItem(line string, indent int) bool {
+	if len(line) <= indent || strings.TrimLeft(line[:indent], " ") != "" || line[indent] != '-' {
+		return false
+	}
+	rest := line[indent+1:]
+	return rest == "" || strings.ContainsAny(rest[:1], " \t\r\n")
+}
+
+// chunkSections groups consecutive sections into chunks up to maxSize, each
+// prefixed with header. Sections too large on their own are split.
+func (y *yamlChunker) chunkSections(sections []yamlSection, header, parentPath string) ([]Chunk, error) {
+	var chunks []Chunk
+	var group []yamlSection
+	groupSize := len(header)
+
+	flush := func() {
+		if len(group) > 0 {
+			chunks = y.appendChunk(chunks, header, group[0].start, group[len(group)-1].end, y.groupMetadata(group, parentPath), BoundaryConfidenceStructural)
+			group = nil
+			groupSize = len(header)
+		}
+	}
+
+	for _, sec := range sections {
+		if err := y.ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		size := y.size(sec)
+		if len(header)+size > y.maxSize {
+			flush()
+			split, err := y.splitSection(sec, header)
+			if err != nil {
+				return nil, err
+			}
+			chunks = append(chunks, split...)
+			continue
+		}
+
+		if groupSize+size > y.maxSize {
+			flush()
+		}
+		group = append(group, sec)
+		groupSize += size
+	}
+	flush()
+
+	return chunks, nil
+}
+
+// splitSection chunks a section larger than maxSize by its children, adding
+// its key line to the header, or by lines if it has no children.
+func (y *yamlChunker) splitSection(sec yamlSection, header string) ([]Chunk, error) {
+	if children := yamlChildren(y.lines, sec, sec.line+1); children != nil {
+		var b strings.Builder
+		b.WriteString(header)
+		for _, l := range y.lines[sec.start : sec.line+1] {
+			b.WriteString(l.text)
+		}
+		return y.chunkSections(children, b.String(), sec.path)
+	}
+
+	var chunks []Chunk
+	meta := y.groupMetadata([]yamlSection{sec}, sec.path)
+	start := sec.start
+	size := len(header)
+	for i := sec.start; i < sec.end; i++ {
+		n := len(y.lines[i].text)
+		if size+n > y.maxSize && i > start {
+			chunks = y.appendChunk(chunks, header, start, i, meta, BoundaryConfidenceHeuristic)
+			start = i
+			size = len(header)
+		}
+		size += n
+	}
+	if start < sec.end {
+		chunks = y.appendChunk(chunks, header, start, sec.end, meta, BoundaryConfidenceHeuristic)
+	}
+
+	return chunks, nil
+}
+
+// size returns the length of a section's source text.
+func (y *yamlChunker) size(sec yamlSection) int {
+	last := y.lines[sec.end-1]
+	return last.offset + len(last.text) - y.lines[sec.start].offset
+}
+
+// groupMetadata describes a chunk of sections. A single section is named by
+// its key and path; several are described by their parent's path.
+func (y *yamlChunker) groupMetadata(group []yamlSection, parentPath string) *StructuredMetadata {
+	meta := &StructuredMetadata{
+		ElementPath: parentPath,
+		RecordIndex: y.document,
+	}
+	if len(group) == 1 {
+		meta.ElementName = group[0].name
+		meta.ElementPath = group[0].path
+	}
+	if meta.ElementPath == "" {
+		meta.ElementPath = "/"
+	}
+
+	for _, sec := range group {
+		if sec.name != "" {
+			meta.KeyNames = append(meta.KeyNames, sec.name)
+		}
+	}
+
+	return meta
+}
+
+// appendChunk appends a chunk of lines [start, end) prefixed with header,
+// skipping ranges with no content. Offsets span only the chunk's own lines.
+func (y *yamlChunker) appendChunk(chunks []Chunk, header string, start, end int, meta *StructuredMetadata, confidence float64) []Chunk {
+	var b strings.Builder
+	blank := true
+	b.WriteString(header)
+	for _, l := range y.lines[start:end] {
+		b.WriteString(l.text)
+		if trimmed := strings.TrimSpace(l.text); trimmed != "" && !isYAMLDocumentStart(trimmed) && !strings.HasPrefix(trimmed, "#") {
+			blank = false
+		}
+	}
+	if blank {
+		return chunks
+	}
+
+	content := b.String()
+	last := y.lines[end-1]
+	return append(chunks, Chunk{
+		Content:     content,
+		StartOffset: y.lines[start].offset,
+		EndOffset:   last.offset + len(last.text),
+		Metadata: ChunkMetadata{
+			Type:               ChunkTypeStructured,
+			TokenEstimate:      EstimateTokens(content),
+			BoundaryConfidence: confidence,
+			Structured:         meta,
+		},
+	})
+}
@@ -2,6 +2,7 @@ package chunkers
 
 import (
 	"sync"
+	"unicode/utf8"
 
 	"github.com/pkoukk/tiktoken-go"
 )
@@ -43,3 +44,78 @@ func EstimateTokens(text string) int {
 func EstimateTokensBytes(content []byte) int {
 	return CountTokens(string(content))
 }
+
+// Tokenizer estimates how many tokens a downstream model counts in text, so
+// chunk sizing can match the embedding model in use. Implementations must be
+// safe for concurrent use.
+type Tokenizer interface {
+	EstimateTokens(text string) int
+}
+
+// TokenizerFunc adapts an ordinary function to the Tokenizer interface.
+type TokenizerFunc func(text string) int
+
+// EstimateTokens calls f(text).
+func (f TokenizerFunc) EstimateTokens(text string) int {
+	return f(text)
+}
+
+// DefaultTokenizer returns the tokenizer used when ChunkOptions.Tokenizer is
+// unset; it counts tokens with the package-level EstimateTokens.
+func DefaultTokenizer() Tokenizer {
+	return TokenizerFunc(EstimateTokens)
+}
+
+// tokenizerFor returns the tokenizer configured in opts, or the default.
+func tokenizerFor(opts ChunkOptions) Tokenizer {
+	if opts.Tokenizer != nil {
+		return opts.Tokenizer
+	}
+	return DefaultTokenizer()
+}
+
+// chunkLimits bounds a chunk by size in bytes and, when maxTokens is
+// positive, by tokens counted with tokenizer.
+type chunkLimits struct {
+	maxSize   int
+	maxTokens int
+	tokenizer Tokenizer
+}
+
+// fits reports whether text is within both the byte and token limits.
+func (l chunkLimits) fits(text string) bool {
+	return len(text) <= l.maxSize && l.fitsTokens(text)
+}
+
+// fitsTokens reports whether text is within the token limit.
+func (l chunkLimits) fitsTokens(text string) bool {
+	return l.maxTokens <= 0 || l.tokenizer.EstimateTokens(text) <= l.maxTokens
+}
+
+// tokenPrefix returns the byte length of the longest prefix of text, cut on a
+// rune boundary, that fits within the token limit. At least one rune is kept
+// so callers always make progress.
+func (l chunkLimits) tokenPrefix(text string) int {
+	if l.fitsTokens(text) {
+		return len(text)
+	}
+
+	// lo always fits (or is the single rune kept regardless); hi never does
+	_, lo := utf8.DecodeRuneInString(text)
+	hi := len(text)
+	for hi-lo > 1 {
+		mid := (lo + hi) / 2
+		for mid < hi && !utf8.RuneStart(text[mid]) {
+			mid++
+		}
+		if mid == hi {
+			break
+		}
+		if l.fitsTokens(text[:mid]) {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
@@ -1,6 +1,7 @@
 package chunkers
 
 import (
+	"context"
 	"strings"
 	"sync"
 	"testing"
@@ -350,6 +351,59 @@ func TestCountTokensEdgeCases(t *testing.T) {
 	})
 }
 
+func TestTokenizerOption(t *testing.T) {
+	// Counts whitespace-separated words, standing in for a model whose
+	// tokenization differs from cl100k_base
+	words := TokenizerFunc(func(text string) int {
+		return len(strings.Fields(text))
+	})
+	content := []byte(strings.Repeat("alpha beta gamma delta epsilon. ", 30))
+
+	t.Run("default matches EstimateTokens", func(t *testing.T) {
+		text := "The quick brown fox"
+		if got, want := DefaultTokenizer().EstimateTokens(text), EstimateTokens(text); got != want {
+			t.Errorf("DefaultTokenizer().EstimateTokens() = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("sizes chunks with the configured tokenizer", func(t *testing.T) {
+		for _, chunker := range []Chunker{NewRecursiveChunker(), NewFallbackChunker()} {
+			opts := ChunkOptions{MaxChunkSize: 4000, MaxTokens: 12, Tokenizer: words}
+			result, err := chunker.Chunk(context.Background(), content, opts)
+			if err != nil {
+				t.Fatalf("%s: Chunk returned error: %v", chunker.Name(), err)
+			}
+			if len(result.Chunks) < 2 {
+				t.Fatalf("%s: expected token limit to split content, got %d chunks", chunker.Name(), len(result.Chunks))
+			}
+			for _, chunk := range result.Chunks {
+				count := len(strings.Fields(chunk.Content))
+				if count > opts.MaxTokens {
+					t.Errorf("%s: chunk %d has %d words, want at most %d", chunker.Name(), chunk.Index, count, opts.MaxTokens)
+				}
+				if chunk.Metadata.TokenEstimate != count {
+					t.Errorf("%s: chunk %d TokenEstimate = %d, want %d", chunker.Name(), chunk.Index, chunk.Metadata.TokenEstimate, count)
+				}
+			}
+		}
+	})
+
+	t.Run("registry applies tokenizer to estimates", func(t *testing.T) {
+		opts := DefaultChunkOptions()
+		opts.MIMEType = "text/markdown"
+		opts.Tokenizer = words
+		result, err := DefaultRegistry().Chunk(context.Background(), []byte("# Title\n\nSome body text here.\n"), opts)
+		if err != nil {
+			t.Fatalf("Chunk returned error: %v", err)
+		}
+		for _, chunk := range result.Chunks {
+			if want := len(strings.Fields(chunk.Content)); chunk.Metadata.TokenEstimate != want {
+				t.Errorf("chunk %d TokenEstimate = %d, want %d", chunk.Index, chunk.Metadata.TokenEstimate, want)
+			}
+		}
+	})
+}
+
 func BenchmarkCountTokens(b *testing.B) {
 	texts := []struct {
 		name string
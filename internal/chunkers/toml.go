@@ -60,6 +60,11 @@ func (c *TOMLChunker) Priority() int {
 	return tomlChunkerPriority
 }
 
+// ChunkType returns the type of content this chunker produces.
+func (c *TOMLChunker) ChunkType() ChunkType {
+	return ChunkTypeStructured
+}
+
 // Chunk splits TOML content by top-level tables.
 func (c *TOMLChunker) Chunk(ctx context.Context, content []byte, opts ChunkOptions) (*ChunkResult, error) {
 	if len(content) == 0 {
@@ -74,7 +79,7 @@ func (c *TOMLChunker) Chunk(ctx context.Context, content []byte, opts ChunkOptio
 
 	maxSize := opts.MaxChunkSize
 	if maxSize <= 0 {
-		maxSize = DefaultChunkOptions().MaxChunkSize
+		maxSize = DefaultChunkOptionsForType(c.ChunkType()).MaxChunkSize
 	}
 
 	tables := c.parseTomlTables(string(content))
@@ -103,8 +108,9 @@ func (c *TOMLChunker) Chunk(ctx context.Context, content []byte, opts ChunkOptio
 				StartOffset: offset,
 				EndOffset:   offset + len(table.content),
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeStructured,
-					TokenEstimate: EstimateTokens(table.content),
+					Type:               ChunkTypeStructured,
+					BoundaryConfidence: BoundaryConfidenceStructural,
+					TokenEstimate:      EstimateTokens(table.content),
 					Structured: &StructuredMetadata{
 						TablePath: table.path,
 						KeyNames:  table.keys,
@@ -323,8 +329,9 @@ func (c *TOMLChunker) splitLargeTable(ctx context.Context, table tomlTable, maxS
 				StartOffset: offset,
 				EndOffset:   offset + len(content),
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeStructured,
-					TokenEstimate: EstimateTokens(content),
+					Type:               ChunkTypeStructured,
+					BoundaryConfidence: BoundaryConfidenceHeuristic,
+					TokenEstimate:      EstimateTokens(content),
 					Structured: &StructuredMetadata{
 						TablePath: table.path,
 						KeyNames:  currentKeys,
@@ -359,8 +366,9 @@ func (c *TOMLChunker) splitLargeTable(ctx context.Context, table tomlTable, maxS
 			StartOffset: offset,
 			EndOffset:   offset + len(content),
 			Metadata: ChunkMetadata{
-				Type:          ChunkTypeStructured,
-				TokenEstimate: EstimateTokens(content),
+				Type:               ChunkTypeStructured,
+				BoundaryConfidence: BoundaryConfidenceHeuristic,
+				TokenEstimate:      EstimateTokens(content),
 				Structured: &StructuredMetadata{
 					TablePath: table.path,
 					KeyNames:  currentKeys,
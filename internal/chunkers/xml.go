@@ -61,6 +61,11 @@ func (c *XMLChunker) Priority() int {
 	return xmlChunkerPriority
 }
 
+// ChunkType returns the type of content this chunker produces.
+func (c *XMLChunker) ChunkType() ChunkType {
+	return ChunkTypeStructured
+}
+
 // Chunk splits XML content by top-level elements.
 func (c *XMLChunker) Chunk(ctx context.Context, content []byte, opts ChunkOptions) (*ChunkResult, error) {
 	if len(content) == 0 {
@@ -75,7 +80,7 @@ func (c *XMLChunker) Chunk(ctx context.Context, content []byte, opts ChunkOption
 
 	maxSize := opts.MaxChunkSize
 	if maxSize <= 0 {
-		maxSize = DefaultChunkOptions().MaxChunkSize
+		maxSize = DefaultChunkOptionsForType(c.ChunkType()).MaxChunkSize
 	}
 
 	elements, warnings := c.parseXMLElements(content)
@@ -104,8 +109,9 @@ func (c *XMLChunker) Chunk(ctx context.Context, content []byte, opts ChunkOption
 				StartOffset: offset,
 				EndOffset:   offset + len(elem.content),
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeStructured,
-					TokenEstimate: EstimateTokens(elem.content),
+					Type:               ChunkTypeStructured,
+					BoundaryConfidence: BoundaryConfidenceStructural,
+					TokenEstimate:      EstimateTokens(elem.content),
 					Structured: &StructuredMetadata{
 						ElementName: elem.name,
 						ElementPath: elem.path,
@@ -316,8 +322,9 @@ func (c *XMLChunker) splitLargeElement(ctx context.Context, elem xmlElement, max
 					StartOffset: offset,
 					EndOffset:   offset + len(chunkContent),
 					Metadata: ChunkMetadata{
-						Type:          ChunkTypeStructured,
-						TokenEstimate: EstimateTokens(chunkContent),
+						Type:               ChunkTypeStructured,
+						BoundaryConfidence: BoundaryConfidenceHeuristic,
+						TokenEstimate:      EstimateTokens(chunkContent),
 						Structured: &StructuredMetadata{
 							ElementName: elem.name,
 							ElementPath: elem.path,
@@ -342,8 +349,9 @@ func (c *XMLChunker) splitLargeElement(ctx context.Context, elem xmlElement, max
 				StartOffset: offset,
 				EndOffset:   offset + len(chunkContent),
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeStructured,
-					TokenEstimate: EstimateTokens(chunkContent),
+					Type:               ChunkTypeStructured,
+					BoundaryConfidence: BoundaryConfidenceHeuristic,
+					TokenEstimate:      EstimateTokens(chunkContent),
 					Structured: &StructuredMetadata{
 						ElementName: elem.name,
 						ElementPath: elem.path,
@@ -374,8 +382,9 @@ func (c *XMLChunker) splitLargeElement(ctx context.Context, elem xmlElement, max
 				StartOffset: offset,
 				EndOffset:   offset + len(chunkContent),
 				Metadata: ChunkMetadata{
-					Type:          ChunkTypeStructured,
-					TokenEstimate: EstimateTokens(chunkContent),
+					Type:               ChunkTypeStructured,
+					BoundaryConfidence: BoundaryConfidenceHeuristic,
+					TokenEstimate:      EstimateTokens(chunkContent),
 					Structured: &StructuredMetadata{
 						ElementName: elem.name,
 						ElementPath: elem.path,
@@ -400,8 +409,9 @@ func (c *XMLChunker) splitLargeElement(ctx context.Context, elem xmlElement, max
 			StartOffset: offset,
 			EndOffset:   offset + len(chunkContent),
 			Metadata: ChunkMetadata{
-				Type:          ChunkTypeStructured,
-				TokenEstimate: EstimateTokens(chunkContent),
+				Type:               ChunkTypeStructured,
+				BoundaryConfidence: BoundaryConfidenceHeuristic,
+				TokenEstimate:      EstimateTokens(chunkContent),
 				Structured: &StructuredMetadata{
 					ElementName: elem.name,
 					ElementPath: elem.path,
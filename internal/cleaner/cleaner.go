@@ -4,26 +4,37 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/fs"
 	"log/slog"
+	"os"
 	"sync"
 	"time"
 
 	"github.com/leefowlercu/agentic-memorizer/internal/events"
 	"github.com/leefowlercu/agentic-memorizer/internal/graph"
 	"github.com/leefowlercu/agentic-memorizer/internal/registry"
+	"github.com/leefowlercu/agentic-memorizer/internal/walker"
 )
 
 // ErrAlreadyStarted is returned when Start() is called on an already-started cleaner.
 var ErrAlreadyStarted = errors.New("cleaner already started")
 
+// DefaultMaxStaleFraction is the default fraction of file_state entries that may
+// be stale before reconciliation is skipped.
+const DefaultMaxStaleFraction = 0.5
+
 // ReconcileResult contains statistics from a reconciliation run.
 type ReconcileResult struct {
-	FilesChecked int
-	StaleFound   int
-	StaleRemoved int
-	Errors       int
-	Skipped      bool // True if reconciliation was skipped (e.g., empty discovered paths)
-	Duration     time.Duration
+	FilesChecked  int
+	StaleFound    int
+	StaleRemoved  int
+	Errors        int
+	Skipped       bool     // True if reconciliation was skipped (e.g., empty discovered paths or partial walk)
+	SkipReason    string   // Why reconciliation was skipped, empty if it ran
+	StaleFraction float64  // Fraction of file_state entries not discovered by the walk
+	DryRun        bool     // True if stale entries were only reported, not removed
+	StalePaths    []string // Stale file_state and discovery paths, in the order found
+	Duration      time.Duration
 }
 
 // Cleaner handles file deletion cleanup from registry and graph.
@@ -33,6 +44,9 @@ type Cleaner struct {
 	bus      events.Bus
 	logger   *slog.Logger
 
+	// maxStaleFraction is the stale-to-total ratio above which Reconcile skips
+	maxStaleFraction float64
+
 	mu          sync.Mutex
 	started     bool
 	unsubscribe func()
@@ -51,6 +65,14 @@ func WithLogger(logger *slog.Logger) CleanerOption {
 	}
 }
 
+// WithMaxStaleFraction sets the fraction of file_state entries that may be stale
+// before reconciliation is skipped. A fraction of 1 or more disables the check.
+func WithMaxStaleFraction(fraction float64) CleanerOption {
+	return func(c *Cleaner) {
+		c.maxStaleFraction = fraction
+	}
+}
+
 // New creates a new Cleaner.
 func New(reg registry.Registry, g graph.Graph, bus events.Bus, opts ...CleanerOption) *Cleaner {
 	c := &Cleaner{
@@ -58,6 +80,8 @@ func New(reg registry.Registry, g graph.Graph, bus events.Bus, opts ...CleanerOp
 		graph:    g,
 		bus:      bus,
 		logger:   slog.Default(),
+
+		maxStaleFraction: DefaultMaxStaleFraction,
 	}
 
 	for _, opt := range opts {
@@ -177,12 +201,107 @@ func (c *Cleaner) DeletePath(ctx context.Context, path string) error {
 	return nil
 }
 
-// Reconcile compares discovered paths against file_state and cleans up stale entries.
-// If discoveredPaths is empty but file_state has entries, reconciliation is skipped
-// as a safeguard against accidental mass deletion (e.g., filter misconfiguration).
-func (c *Cleaner) Reconcile(ctx context.Context, parentPath string, discoveredPaths map[string]struct{}) (*ReconcileResult, error) {
+// Reconcile compares the paths discovered by a walk against file_state and cleans up
+// stale entries. If the walk was incomplete, discovered no paths while file_state
+// has entries, or missed more than the maximum stale fraction of file_state,
+// reconciliation is skipped as a safeguard against accidental mass deletion
+// (e.g., a failed walk, flaky mount or filter misconfiguration).
+func (c *Cleaner) Reconcile(ctx context.Context, parentPath string, walk walker.WalkResult) (*ReconcileResult, error) {
+	return c.reconcile(ctx, parentPath, walk, false)
+}
+
+// ReconcileDryRun reports the stale entries Reconcile would clean up, listing
+// them in StalePaths, without deleting anything from the registry or graph.
+// The same safeguards against mass deletion apply.
+func (c *Cleaner) ReconcileDryRun(ctx context.Context, parentPath string, walk walker.WalkResult) (*ReconcileResult, error) {
+	return c.reconcile(ctx, parentPath, walk, true)
+}
+
+// ReconcileMissing cleans up registry entries under parentPath whose files no
+// longer exist, checking each entry with a stat instead of comparing against a
+// full walk. Only missing files are detected; new or changed files are left for
+// the walker. Entries that cannot be stat'ed for other reasons are kept. The
+// same safeguards against mass deletion apply, and reconciliation is skipped
+// if parentPath itself cannot be stat'ed.
+func (c *Cleaner) ReconcileMissing(ctx context.Context, parentPath string) (*ReconcileResult, error) {
+	return c.reconcileMissing(ctx, parentPath, false)
+}
+
+// ReconcileMissingDryRun reports the entries ReconcileMissing would clean up
+// without deleting anything from the registry or graph.
+func (c *Cleaner) ReconcileMissingDryRun(ctx context.Context, parentPath string) (*ReconcileResult, error) {
+	return c.reconcileMissing(ctx, parentPath, true)
+}
+
+// reconcileMissing implements ReconcileMissing and ReconcileMissingDryRun by
+// building the set of registry entries that still exist on disk and
+// reconciling against it as if it were a complete walk.
+func (c *Cleaner) reconcileMissing(ctx context.Context, parentPath string, dryRun bool) (*ReconcileResult, error) {
 	start := time.Now()
-	result := &ReconcileResult{}
+
+	// Safeguard: an unmounted or unreadable root would make every entry
+	// look missing.
+	if _, err := os.Stat(parentPath); err != nil {
+		c.logger.Warn("reconciliation skipped: parent path inaccessible",
+			"parent_path", parentPath,
+			"error", err,
+		)
+		return &ReconcileResult{
+			DryRun:     dryRun,
+			Skipped:    true,
+			SkipReason: "parent path inaccessible",
+			Duration:   time.Since(start),
+		}, nil
+	}
+
+	states, err := c.registry.ListFileStates(ctx, parentPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list file states; %w", err)
+	}
+
+	discoveryStates, err := c.registry.ListDiscoveryStates(ctx, parentPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list discovery states; %w", err)
+	}
+
+	paths := make([]string, 0, len(states)+len(discoveryStates))
+	for _, state := range states {
+		paths = append(paths, state.Path)
+	}
+	for _, state := range discoveryStates {
+		paths = append(paths, state.Path)
+	}
+
+	existing := make(map[string]struct{}, len(paths))
+	for i, path := range paths {
+		if i%100 == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+
+		_, err := os.Lstat(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			c.logger.Debug("keeping entry that could not be checked", "path", path, "error", err)
+		}
+		existing[path] = struct{}{}
+	}
+
+	result, err := c.reconcile(ctx, parentPath, walker.WalkResult{Paths: existing, Complete: true}, dryRun)
+	if result != nil {
+		result.Duration = time.Since(start)
+	}
+	return result, err
+}
+
+// reconcile implements Reconcile and ReconcileDryRun.
+func (c *Cleaner) reconcile(ctx context.Context, parentPath string, walk walker.WalkResult, dryRun bool) (*ReconcileResult, error) {
+	start := time.Now()
+	result := &ReconcileResult{DryRun: dryRun}
+	discoveredPaths := walk.Paths
 
 	// Get all file_state entries under this parent path
 	states, err := c.registry.ListFileStates(ctx, parentPath)
@@ -198,6 +317,19 @@ func (c *Cleaner) Reconcile(ctx context.Context, parentPath string, discoveredPa
 
 	result.FilesChecked = len(states)
 
+	// Safeguard: a partial walk cannot prove undiscovered files are gone.
+	if !walk.Complete {
+		c.logger.Warn("reconciliation skipped: walk did not complete",
+			"parent_path", parentPath,
+			"discovered_count", len(discoveredPaths),
+			"error", walk.Err,
+		)
+		result.Skipped = true
+		result.SkipReason = "walk did not complete"
+		result.Duration = time.Since(start)
+		return result, nil
+	}
+
 	// Safeguard: if discoveredPaths is empty but we have file_state entries,
 	// something might be wrong (filter misconfiguration, permissions issue).
 	// Skip reconciliation to prevent accidental mass deletion.
@@ -208,10 +340,38 @@ func (c *Cleaner) Reconcile(ctx context.Context, parentPath string, discoveredPa
 			"discovery_count", len(discoveryStates),
 		)
 		result.Skipped = true
+		result.SkipReason = "no files discovered"
 		result.Duration = time.Since(start)
 		return result, nil
 	}
 
+	// Safeguard: a walk that misses most known files more likely hit a flaky
+	// mount or permissions issue than a mass deletion.
+	if len(states) > 0 {
+		stale := 0
+		for _, state := range states {
+			if _, exists := discoveredPaths[state.Path]; !exists {
+				stale++
+			}
+		}
+		result.StaleFraction = float64(stale) / float64(len(states))
+
+		if result.StaleFraction > c.maxStaleFraction {
+			c.logger.Warn("reconciliation skipped: stale fraction exceeds threshold",
+				"parent_path", parentPath,
+				"file_state_count", len(states),
+				"stale_count", stale,
+				"stale_fraction", result.StaleFraction,
+				"max_stale_fraction", c.maxStaleFraction,
+			)
+			result.StaleFound = stale
+			result.Skipped = true
+			result.SkipReason = fmt.Sprintf("stale fraction %.2f exceeds maximum %.2f", result.StaleFraction, c.maxStaleFraction)
+			result.Duration = time.Since(start)
+			return result, nil
+		}
+	}
+
 	staleFileStates := make(map[string]struct{})
 
 	// Find stale entries (in file_state but not in discovered)
@@ -228,6 +388,12 @@ func (c *Cleaner) Reconcile(ctx context.Context, parentPath string, discoveredPa
 		if _, exists := discoveredPaths[state.Path]; !exists {
 			staleFileStates[state.Path] = struct{}{}
 			result.StaleFound++
+			result.StalePaths = append(result.StalePaths, state.Path)
+
+			if dryRun {
+				c.logger.Debug("would clean up stale file", "path", state.Path)
+				continue
+			}
 
 			// Clean up stale entry
 			if err := c.DeletePath(ctx, state.Path); err != nil {
@@ -259,6 +425,12 @@ func (c *Cleaner) Reconcile(ctx context.Context, parentPath string, discoveredPa
 			continue
 		}
 
+		result.StalePaths = append(result.StalePaths, state.Path)
+		if dryRun {
+			c.logger.Debug("would clean up stale discovery state", "path", state.Path)
+			continue
+		}
+
 		if err := c.registry.DeleteDiscoveryState(ctx, state.Path); err != nil {
 			if errors.Is(err, registry.ErrPathNotFound) {
 				continue
@@ -276,6 +448,24 @@ func (c *Cleaner) Reconcile(ctx context.Context, parentPath string, discoveredPa
 	return result, nil
 }
 
+// PruneOrphans removes chunks without a file, and metadata and embedding nodes
+// without a chunk, from the graph. It is a no-op when the graph is unavailable.
+func (c *Cleaner) PruneOrphans(ctx context.Context) (graph.PruneStats, error) {
+	if c.graph == nil {
+		return graph.PruneStats{}, nil
+	}
+
+	stats, err := c.graph.PruneOrphans(ctx)
+	if err != nil {
+		return stats, fmt.Errorf("failed to prune orphaned nodes; %w", err)
+	}
+
+	if total := stats.Total(); total > 0 {
+		c.logger.Info("pruned orphaned graph nodes", "deleted", total, "by_label", stats.Deleted)
+	}
+	return stats, nil
+}
+
 // handlePathDeleted is the event handler for PathDeleted events.
 func (c *Cleaner) handlePathDeleted(e events.Event) {
 	fe, ok := e.Payload.(*events.FileEvent)
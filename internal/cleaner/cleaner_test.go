@@ -3,6 +3,11 @@ package cleaner
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -13,6 +18,7 @@ import (
 	"github.com/leefowlercu/agentic-memorizer/internal/events"
 	"github.com/leefowlercu/agentic-memorizer/internal/graph"
 	"github.com/leefowlercu/agentic-memorizer/internal/registry"
+	"github.com/leefowlercu/agentic-memorizer/internal/walker"
 )
 
 // mockRegistry implements registry.Registry for testing.
@@ -193,7 +199,11 @@ func (m *mockRegistry) UpdateSemanticState(ctx context.Context, path string, ana
 	return nil
 }
 
-func (m *mockRegistry) UpdateEmbeddingsState(ctx context.Context, path string, err error) error {
+func (m *mockRegistry) UpdateEmbeddingsState(ctx context.Context, path string, model string, err error) error {
+	return nil
+}
+
+func (m *mockRegistry) UpdateChunkerState(ctx context.Context, path string, chunkerVersion string) error {
 	return nil
 }
 
@@ -213,6 +223,50 @@ func (m *mockRegistry) ListFilesNeedingEmbeddings(ctx context.Context, parentPat
 	return nil, nil
 }
 
+func (m *mockRegistry) ListFilesWithStaleEmbeddings(ctx context.Context, parentPath string, currentModel string) ([]registry.FileState, error) {
+	return nil, nil
+}
+
+func (m *mockRegistry) CountByAnalysisStatus(ctx context.Context) (registry.AnalysisStatusCounts, error) {
+	return registry.AnalysisStatusCounts{}, nil
+}
+
+func (m *mockRegistry) EnqueueWorkItem(ctx context.Context, item *registry.PendingWorkItem) error {
+	return nil
+}
+
+func (m *mockRegistry) CompleteWorkItem(ctx context.Context, id int64, enqueuedAt time.Time) error {
+	return nil
+}
+
+func (m *mockRegistry) ListPendingWorkItems(ctx context.Context) ([]registry.PendingWorkItem, error) {
+	return nil, nil
+}
+
+func (m *mockRegistry) RecordDeadLetter(ctx context.Context, dl *registry.DeadLetter) error {
+	return nil
+}
+
+func (m *mockRegistry) GetDeadLetter(ctx context.Context, filePath string) (*registry.DeadLetter, error) {
+	return nil, registry.ErrPathNotFound
+}
+
+func (m *mockRegistry) ListDeadLetters(ctx context.Context) ([]registry.DeadLetter, error) {
+	return nil, nil
+}
+
+func (m *mockRegistry) DeleteDeadLetter(ctx context.Context, filePath string) error {
+	return nil
+}
+
+func (m *mockRegistry) Export(ctx context.Context, w io.Writer) error {
+	return nil
+}
+
+func (m *mockRegistry) Import(ctx context.Context, rd io.Reader) error {
+	return nil
+}
+
 func (m *mockRegistry) Close() error {
 	return nil
 }
@@ -266,6 +320,9 @@ type mockGraph struct {
 	deleteDirectoryError   error
 	deleteFilesUnderError  error
 	deleteDirsUnderError   error
+	pruneCalls             int
+	pruneStats             graph.PruneStats
+	pruneError             error
 }
 
 func newMockGraph() *mockGraph {
@@ -344,6 +401,14 @@ func (m *mockGraph) UpsertChunkWithMetadata(ctx context.Context, chunk *graph.Ch
 	return nil
 }
 
+func (m *mockGraph) UpsertChunksBatch(ctx context.Context, chunks []*graph.ChunkNode, metas []*chunkers.ChunkMetadata) error {
+	return nil
+}
+
+func (m *mockGraph) CommitTransaction(ctx context.Context, tx *graph.Transaction) error {
+	return nil
+}
+
 func (m *mockGraph) UpsertChunkEmbedding(ctx context.Context, chunkID string, emb *graph.ChunkEmbeddingNode) error {
 	return nil
 }
@@ -352,10 +417,21 @@ func (m *mockGraph) DeleteChunkEmbeddings(ctx context.Context, chunkID string, p
 	return nil
 }
 
+func (m *mockGraph) DeleteFileEmbeddings(ctx context.Context, filePath string, provider, model string) error {
+	return nil
+}
+
 func (m *mockGraph) DeleteChunks(ctx context.Context, filePath string) error {
 	return nil
 }
 
+func (m *mockGraph) PruneOrphans(ctx context.Context) (graph.PruneStats, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pruneCalls++
+	return m.pruneStats, m.pruneError
+}
+
 func (m *mockGraph) SetFileTags(ctx context.Context, path string, tags []string) error {
 	return nil
 }
@@ -368,10 +444,40 @@ func (m *mockGraph) SetFileEntities(ctx context.Context, path string, entities [
 	return nil
 }
 
+func (m *mockGraph) SetFileRelations(ctx context.Context, path string, relations []graph.EntityRelation) error {
+	return nil
+}
+
 func (m *mockGraph) SetFileReferences(ctx context.Context, path string, refs []graph.Reference) error {
 	return nil
 }
 
+func (m *mockGraph) SetChunkSectionReferences(ctx context.Context, chunkID string, targetIDs []string, unmatchedAnchors []string) error {
+	return nil
+}
+
+func (m *mockGraph) LinkChunks(ctx context.Context, path string, chunkIDs []string) error {
+	return nil
+}
+
+func (m *mockGraph) GetAdjacentChunks(ctx context.Context, chunkID string, before, after int) ([]graph.ChunkNode, error) {
+	return nil, nil
+}
+func (m *mockGraph) GetChunkByID(ctx context.Context, id string) (*graph.ChunkNode, *chunkers.ChunkMetadata, error) {
+	return nil, nil, nil
+}
+func (m *mockGraph) ListChunksForFile(ctx context.Context, filePath string) ([]graph.ChunkNode, error) {
+	return nil, nil
+}
+
+func (m *mockGraph) GetBacklinks(ctx context.Context, path string) ([]graph.FileNode, error) {
+	return nil, nil
+}
+
+func (m *mockGraph) GetReferences(ctx context.Context, path string) ([]graph.Reference, error) {
+	return nil, nil
+}
+
 func (m *mockGraph) Query(ctx context.Context, cypher string) (*graph.QueryResult, error) {
 	return nil, nil
 }
@@ -380,10 +486,42 @@ func (m *mockGraph) HasEmbedding(ctx context.Context, contentHash string, versio
 	return false, nil
 }
 
+func (m *mockGraph) GetEmbedding(ctx context.Context, contentHash, provider, model string, version int) ([]float32, error) {
+	return nil, nil
+}
+
+func (m *mockGraph) CountFilesByIngestReason(ctx context.Context) (map[string]int, error) {
+	return map[string]int{}, nil
+}
+
+func (m *mockGraph) GetDirectoryTree(ctx context.Context, rootPath string, maxDepth int) (*graph.DirectoryTree, error) {
+	return nil, nil
+}
+
+func (m *mockGraph) TopTags(ctx context.Context, n int) ([]graph.TagNode, error) {
+	return nil, nil
+}
+
+func (m *mockGraph) TopTopics(ctx context.Context, n int) ([]graph.TopicNode, error) {
+	return nil, nil
+}
+
+func (m *mockGraph) TopEntities(ctx context.Context, n int, entityType string) ([]graph.EntityNode, error) {
+	return nil, nil
+}
+
+func (m *mockGraph) GetRelatedFiles(ctx context.Context, path string, k int) ([]graph.RelatedFile, error) {
+	return nil, nil
+}
+
 func (m *mockGraph) ExportSnapshot(ctx context.Context) (*graph.GraphSnapshot, error) {
 	return nil, nil
 }
 
+func (m *mockGraph) ExportSnapshotSince(ctx context.Context, since time.Time) (*graph.GraphSnapshot, error) {
+	return nil, nil
+}
+
 func (m *mockGraph) GetFileWithRelations(ctx context.Context, path string) (*graph.FileWithRelations, error) {
 	return nil, nil
 }
@@ -392,6 +530,10 @@ func (m *mockGraph) SearchSimilarChunks(ctx context.Context, embedding []float32
 	return nil, nil
 }
 
+func (m *mockGraph) SearchSimilarChunksFiltered(ctx context.Context, embedding []float32, k int, filter graph.ChunkFilter) ([]graph.ChunkSearchHit, error) {
+	return nil, nil
+}
+
 func (m *mockGraph) IsConnected() bool {
 	return true
 }
@@ -511,7 +653,7 @@ func TestCleaner_Reconcile(t *testing.T) {
 		"/test/file2.go": {},
 	}
 
-	result, err := c.Reconcile(context.Background(), "/test", discoveredPaths)
+	result, err := c.Reconcile(context.Background(), "/test", walker.WalkResult{Paths: discoveredPaths, Complete: true})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -575,7 +717,7 @@ func TestCleaner_Reconcile_NoStale(t *testing.T) {
 		"/test/file2.go": {},
 	}
 
-	result, err := c.Reconcile(context.Background(), "/test", discoveredPaths)
+	result, err := c.Reconcile(context.Background(), "/test", walker.WalkResult{Paths: discoveredPaths, Complete: true})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -598,6 +740,77 @@ func TestCleaner_Reconcile_NoStale(t *testing.T) {
 	reg.mu.Unlock()
 }
 
+func TestCleaner_ReconcileDryRun(t *testing.T) {
+	reg := newMockRegistry()
+	g := newMockGraph()
+	bus := events.NewBus()
+	defer bus.Close()
+
+	reg.fileStates["/test/file1.go"] = registry.FileState{Path: "/test/file1.go"}
+	reg.fileStates["/test/file2.go"] = registry.FileState{Path: "/test/file2.go"}
+	reg.discoveryStates["/test/skipped.bin"] = registry.FileDiscovery{Path: "/test/skipped.bin"}
+
+	c := New(reg, g, bus)
+
+	// file2 and the discovery-only entry are stale
+	discoveredPaths := map[string]struct{}{
+		"/test/file1.go": {},
+	}
+
+	result, err := c.ReconcileDryRun(context.Background(), "/test", walker.WalkResult{Paths: discoveredPaths, Complete: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !result.DryRun {
+		t.Error("expected DryRun=true")
+	}
+	if result.StaleFound != 1 {
+		t.Errorf("expected StaleFound=1, got %d", result.StaleFound)
+	}
+	if result.StaleRemoved != 0 {
+		t.Errorf("expected StaleRemoved=0, got %d", result.StaleRemoved)
+	}
+	if !slices.Equal(result.StalePaths, []string{"/test/file2.go", "/test/skipped.bin"}) {
+		t.Errorf("unexpected StalePaths %v", result.StalePaths)
+	}
+
+	// Verify nothing was deleted
+	reg.mu.Lock()
+	if len(reg.deletedPaths) != 0 || len(reg.deletedDiscoveryPaths) != 0 || len(reg.fileStates) != 2 {
+		t.Errorf("expected no registry deletions, got %v and %v", reg.deletedPaths, reg.deletedDiscoveryPaths)
+	}
+	reg.mu.Unlock()
+
+	g.mu.Lock()
+	if len(g.deletedPaths) != 0 {
+		t.Errorf("expected no graph deletions, got %v", g.deletedPaths)
+	}
+	g.mu.Unlock()
+}
+
+func TestCleaner_ReconcileDryRun_EmptyDiscoveredPathsSkipped(t *testing.T) {
+	reg := newMockRegistry()
+	bus := events.NewBus()
+	defer bus.Close()
+
+	reg.fileStates["/test/file1.go"] = registry.FileState{Path: "/test/file1.go"}
+
+	c := New(reg, nil, bus)
+
+	result, err := c.ReconcileDryRun(context.Background(), "/test", walker.WalkResult{Paths: map[string]struct{}{}, Complete: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !result.Skipped {
+		t.Error("expected Skipped=true when discovered is empty but file_state has entries")
+	}
+	if result.StaleFound != 0 || len(result.StalePaths) != 0 {
+		t.Errorf("expected no stale entries when skipped, got %d (%v)", result.StaleFound, result.StalePaths)
+	}
+}
+
 func TestCleaner_Reconcile_ListStatesError(t *testing.T) {
 	reg := newMockRegistry()
 	bus := events.NewBus()
@@ -607,7 +820,7 @@ func TestCleaner_Reconcile_ListStatesError(t *testing.T) {
 
 	c := New(reg, nil, bus)
 
-	_, err := c.Reconcile(context.Background(), "/test", map[string]struct{}{})
+	_, err := c.Reconcile(context.Background(), "/test", walker.WalkResult{Complete: true})
 	if err == nil {
 		t.Fatal("expected error when listing file states fails")
 	}
@@ -800,7 +1013,7 @@ func TestCleaner_Reconcile_EmptyDiscoveredPathsNoMassDeletion(t *testing.T) {
 	// Reconcile with empty discovered paths - should skip to prevent mass deletion
 	emptyDiscovered := map[string]struct{}{}
 
-	result, err := c.Reconcile(context.Background(), "/test", emptyDiscovered)
+	result, err := c.Reconcile(context.Background(), "/test", walker.WalkResult{Paths: emptyDiscovered, Complete: true})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -827,6 +1040,299 @@ func TestCleaner_Reconcile_EmptyDiscoveredPathsNoMassDeletion(t *testing.T) {
 	reg.mu.Unlock()
 }
 
+func TestCleaner_Reconcile_StaleFractionBelowThresholdProceeds(t *testing.T) {
+	reg := newMockRegistry()
+	bus := events.NewBus()
+	defer bus.Close()
+
+	discoveredPaths := make(map[string]struct{})
+	for i := 0; i < 10; i++ {
+		path := fmt.Sprintf("/test/file%d.go", i)
+		reg.fileStates[path] = registry.FileState{Path: path}
+		if i >= 4 {
+			discoveredPaths[path] = struct{}{}
+		}
+	}
+
+	c := New(reg, nil, bus)
+
+	result, err := c.Reconcile(context.Background(), "/test", walker.WalkResult{Paths: discoveredPaths, Complete: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Skipped {
+		t.Fatalf("expected reconciliation to proceed, skipped: %s", result.SkipReason)
+	}
+	if result.StaleFraction != 0.4 {
+		t.Errorf("expected StaleFraction=0.4, got %v", result.StaleFraction)
+	}
+	if result.StaleRemoved != 4 {
+		t.Errorf("expected StaleRemoved=4, got %d", result.StaleRemoved)
+	}
+}
+
+func TestCleaner_Reconcile_StaleFractionAboveThresholdSkips(t *testing.T) {
+	reg := newMockRegistry()
+	g := newMockGraph()
+	bus := events.NewBus()
+	defer bus.Close()
+
+	// A flaky mount returned 2 of 10 files
+	discoveredPaths := make(map[string]struct{})
+	for i := 0; i < 10; i++ {
+		path := fmt.Sprintf("/test/file%d.go", i)
+		reg.fileStates[path] = registry.FileState{Path: path}
+		if i < 2 {
+			discoveredPaths[path] = struct{}{}
+		}
+	}
+
+	c := New(reg, g, bus, WithMaxStaleFraction(0.7))
+
+	result, err := c.Reconcile(context.Background(), "/test", walker.WalkResult{Paths: discoveredPaths, Complete: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !result.Skipped {
+		t.Fatal("expected Skipped=true when stale fraction exceeds the threshold")
+	}
+	if !strings.Contains(result.SkipReason, "stale fraction") {
+		t.Errorf("unexpected SkipReason %q", result.SkipReason)
+	}
+	if result.StaleFraction != 0.8 {
+		t.Errorf("expected StaleFraction=0.8, got %v", result.StaleFraction)
+	}
+	if result.StaleFound != 8 {
+		t.Errorf("expected StaleFound=8, got %d", result.StaleFound)
+	}
+	if result.StaleRemoved != 0 {
+		t.Errorf("expected StaleRemoved=0 (skipped), got %d", result.StaleRemoved)
+	}
+
+	reg.mu.Lock()
+	if len(reg.deletedPaths) != 0 {
+		t.Errorf("expected no deletions when reconciliation skipped, got %d", len(reg.deletedPaths))
+	}
+	reg.mu.Unlock()
+
+	g.mu.Lock()
+	if len(g.deletedPaths) != 0 {
+		t.Errorf("expected no graph deletions when reconciliation skipped, got %d", len(g.deletedPaths))
+	}
+	g.mu.Unlock()
+}
+
+func TestCleaner_Reconcile_IncompleteWalkSkipped(t *testing.T) {
+	reg := newMockRegistry()
+	g := newMockGraph()
+	bus := events.NewBus()
+	defer bus.Close()
+
+	reg.fileStates["/test/file1.go"] = registry.FileState{Path: "/test/file1.go"}
+	reg.fileStates["/test/file2.go"] = registry.FileState{Path: "/test/file2.go"}
+	reg.fileStates["/test/stale.go"] = registry.FileState{Path: "/test/stale.go"}
+
+	c := New(reg, g, bus)
+
+	// Partial walk: file2 and stale were never reached
+	walk := walker.WalkResult{
+		Paths:    map[string]struct{}{"/test/file1.go": {}},
+		Complete: false,
+		Err:      errors.New("permission denied"),
+	}
+
+	result, err := c.Reconcile(context.Background(), "/test", walk)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !result.Skipped {
+		t.Error("expected Skipped=true for incomplete walk")
+	}
+	if result.StaleFound != 0 || result.StaleRemoved != 0 {
+		t.Errorf("expected no stale handling (skipped), got found=%d removed=%d", result.StaleFound, result.StaleRemoved)
+	}
+
+	reg.mu.Lock()
+	if len(reg.deletedPaths) != 0 {
+		t.Errorf("expected no registry deletions for incomplete walk, got %v", reg.deletedPaths)
+	}
+	reg.mu.Unlock()
+
+	g.mu.Lock()
+	if len(g.deletedPaths) != 0 {
+		t.Errorf("expected no graph deletions for incomplete walk, got %v", g.deletedPaths)
+	}
+	g.mu.Unlock()
+}
+
+// writeReconcileFiles creates n files in dir and registers them in reg.
+func writeReconcileFiles(t *testing.T, reg *mockRegistry, dir string, n int) []string {
+	t.Helper()
+	paths := make([]string, n)
+	for i := range paths {
+		paths[i] = filepath.Join(dir, fmt.Sprintf("file%d.go", i))
+		if err := os.WriteFile(paths[i], []byte("package main"), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+		reg.fileStates[paths[i]] = registry.FileState{Path: paths[i]}
+	}
+	return paths
+}
+
+func TestCleaner_ReconcileMissing(t *testing.T) {
+	reg := newMockRegistry()
+	g := newMockGraph()
+	bus := events.NewBus()
+	defer bus.Close()
+
+	dir := t.TempDir()
+	paths := writeReconcileFiles(t, reg, dir, 4)
+
+	// One analyzed file and one discovery-only file were deleted on disk
+	if err := os.Remove(paths[3]); err != nil {
+		t.Fatalf("failed to remove file: %v", err)
+	}
+	skipped := filepath.Join(dir, "skipped.bin")
+	reg.discoveryStates[skipped] = registry.FileDiscovery{Path: skipped}
+
+	c := New(reg, g, bus)
+
+	result, err := c.ReconcileMissing(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Skipped {
+		t.Fatalf("unexpected skip: %s", result.SkipReason)
+	}
+	if result.FilesChecked != 4 {
+		t.Errorf("expected FilesChecked=4, got %d", result.FilesChecked)
+	}
+	if result.StaleFound != 1 || result.StaleRemoved != 1 {
+		t.Errorf("expected 1 stale file found and removed, got %d and %d", result.StaleFound, result.StaleRemoved)
+	}
+	if !slices.Equal(result.StalePaths, []string{paths[3], skipped}) {
+		t.Errorf("unexpected StalePaths %v", result.StalePaths)
+	}
+
+	reg.mu.Lock()
+	if !slices.Contains(reg.deletedPaths, paths[3]) {
+		t.Errorf("expected %s to be deleted from registry, got %v", paths[3], reg.deletedPaths)
+	}
+	if !slices.Contains(reg.deletedDiscoveryPaths, skipped) {
+		t.Errorf("expected %s discovery state to be deleted, got %v", skipped, reg.deletedDiscoveryPaths)
+	}
+	for _, path := range paths[:3] {
+		if slices.Contains(reg.deletedPaths, path) {
+			t.Errorf("existing file %s was deleted", path)
+		}
+	}
+	reg.mu.Unlock()
+
+	g.mu.Lock()
+	if !slices.Contains(g.deletedPaths, paths[3]) {
+		t.Errorf("expected %s to be deleted from graph, got %v", paths[3], g.deletedPaths)
+	}
+	g.mu.Unlock()
+}
+
+func TestCleaner_ReconcileMissing_StaleFractionAboveThresholdSkips(t *testing.T) {
+	reg := newMockRegistry()
+	g := newMockGraph()
+	bus := events.NewBus()
+	defer bus.Close()
+
+	dir := t.TempDir()
+	paths := writeReconcileFiles(t, reg, dir, 4)
+	for _, path := range paths[1:] {
+		if err := os.Remove(path); err != nil {
+			t.Fatalf("failed to remove file: %v", err)
+		}
+	}
+
+	c := New(reg, g, bus)
+
+	result, err := c.ReconcileMissing(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !result.Skipped || !strings.Contains(result.SkipReason, "stale fraction") {
+		t.Fatalf("expected stale fraction skip, got Skipped=%v SkipReason=%q", result.Skipped, result.SkipReason)
+	}
+	if result.StaleFound != 3 {
+		t.Errorf("expected StaleFound=3, got %d", result.StaleFound)
+	}
+
+	reg.mu.Lock()
+	if len(reg.deletedPaths) != 0 {
+		t.Errorf("expected no deletions when reconciliation skipped, got %v", reg.deletedPaths)
+	}
+	reg.mu.Unlock()
+}
+
+func TestCleaner_ReconcileMissing_ParentInaccessibleSkipped(t *testing.T) {
+	reg := newMockRegistry()
+	g := newMockGraph()
+	bus := events.NewBus()
+	defer bus.Close()
+
+	// The remembered path is gone, e.g. an unmounted volume
+	dir := filepath.Join(t.TempDir(), "unmounted")
+	path := filepath.Join(dir, "file.go")
+	reg.fileStates[path] = registry.FileState{Path: path}
+
+	c := New(reg, g, bus, WithMaxStaleFraction(1))
+
+	result, err := c.ReconcileMissing(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !result.Skipped || result.SkipReason != "parent path inaccessible" {
+		t.Fatalf("expected parent path skip, got Skipped=%v SkipReason=%q", result.Skipped, result.SkipReason)
+	}
+
+	reg.mu.Lock()
+	if len(reg.deletedPaths) != 0 {
+		t.Errorf("expected no deletions when reconciliation skipped, got %v", reg.deletedPaths)
+	}
+	reg.mu.Unlock()
+}
+
+func TestCleaner_ReconcileMissingDryRun(t *testing.T) {
+	reg := newMockRegistry()
+	g := newMockGraph()
+	bus := events.NewBus()
+	defer bus.Close()
+
+	dir := t.TempDir()
+	paths := writeReconcileFiles(t, reg, dir, 3)
+	if err := os.Remove(paths[0]); err != nil {
+		t.Fatalf("failed to remove file: %v", err)
+	}
+
+	c := New(reg, g, bus)
+
+	result, err := c.ReconcileMissingDryRun(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !result.DryRun || result.StaleFound != 1 || result.StaleRemoved != 0 {
+		t.Errorf("expected dry run reporting 1 stale file, got %+v", result)
+	}
+
+	reg.mu.Lock()
+	if len(reg.deletedPaths) != 0 || len(reg.fileStates) != 3 {
+		t.Errorf("expected no registry deletions, got %v", reg.deletedPaths)
+	}
+	reg.mu.Unlock()
+}
+
 func TestCleaner_Reconcile_RespectsContextCancellation(t *testing.T) {
 	reg := newMockRegistry()
 	bus := events.NewBus()
@@ -838,7 +1344,8 @@ func TestCleaner_Reconcile_RespectsContextCancellation(t *testing.T) {
 		reg.fileStates[path] = registry.FileState{Path: path}
 	}
 
-	c := New(reg, nil, bus)
+	// Every file is stale, so disable the stale fraction safeguard
+	c := New(reg, nil, bus, WithMaxStaleFraction(1))
 
 	// Create already-canceled context
 	ctx, cancel := context.WithCancel(context.Background())
@@ -849,7 +1356,7 @@ func TestCleaner_Reconcile_RespectsContextCancellation(t *testing.T) {
 		"/test/different.go": {},
 	}
 
-	_, err := c.Reconcile(ctx, "/test", discoveredPaths)
+	_, err := c.Reconcile(ctx, "/test", walker.WalkResult{Paths: discoveredPaths, Complete: true})
 	if err != context.Canceled {
 		t.Errorf("expected context.Canceled error, got %v", err)
 	}
@@ -1018,7 +1525,7 @@ func TestCleaner_Reconcile_NilDiscoveredPaths(t *testing.T) {
 
 	// Reconcile with nil discovered paths - should skip (safeguard)
 	// nil map has len() == 0, so safeguard should trigger
-	result, err := c.Reconcile(context.Background(), "/test", nil)
+	result, err := c.Reconcile(context.Background(), "/test", walker.WalkResult{Complete: true})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -1468,3 +1975,43 @@ func TestCleaner_DeletePath_PathPrefixNoFalsePositives(t *testing.T) {
 	// Note: The actual prefix matching behavior is tested in graph tests.
 	// This test just verifies the path is passed through correctly.
 }
+
+func TestCleaner_PruneOrphans(t *testing.T) {
+	g := newMockGraph()
+	g.pruneStats = graph.PruneStats{Deleted: map[string]int{"Chunk": 2, "CodeMeta": 3}}
+	bus := events.NewBus()
+	defer bus.Close()
+
+	c := New(newMockRegistry(), g, bus)
+
+	stats, err := c.PruneOrphans(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g.pruneCalls != 1 {
+		t.Errorf("expected 1 prune call, got %d", g.pruneCalls)
+	}
+	if stats.Total() != 5 || stats.Deleted["CodeMeta"] != 3 {
+		t.Errorf("unexpected stats %v", stats.Deleted)
+	}
+
+	g.pruneError = errors.New("graph unavailable")
+	if _, err := c.PruneOrphans(context.Background()); err == nil {
+		t.Error("expected error when graph prune fails")
+	}
+}
+
+func TestCleaner_PruneOrphans_GraphNil(t *testing.T) {
+	bus := events.NewBus()
+	defer bus.Close()
+
+	c := New(newMockRegistry(), nil, bus)
+
+	stats, err := c.PruneOrphans(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.Total() != 0 {
+		t.Errorf("expected no deletions, got %v", stats.Deleted)
+	}
+}
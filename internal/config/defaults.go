@@ -19,7 +19,8 @@ const (
 	DefaultDaemonEventBusCriticalQueueCapacity = 1000
 
 	// Storage configuration defaults.
-	DefaultStorageDatabasePath = "~/.config/memorizer/memorizer.db"
+	DefaultStorageDatabasePath  = "~/.config/memorizer/memorizer.db"
+	DefaultStorageBusyTimeoutMs = 5000
 
 	// Persistence queue configuration defaults.
 	DefaultPersistenceQueueMaxRetries            = 3
@@ -29,13 +30,24 @@ const (
 	DefaultPersistenceQueueFailedRetentionDays   = 7  // 1 week
 
 	// Graph configuration defaults.
-	DefaultGraphHost           = "localhost"
-	DefaultGraphPort           = 6379
-	DefaultGraphName           = "memorizer"
-	DefaultGraphPasswordEnv    = "MEMORIZER_GRAPH_PASSWORD"
-	DefaultGraphMaxRetries     = 3
-	DefaultGraphRetryDelayMs   = 1000 // 1 second
-	DefaultGraphWriteQueueSize = 1000
+	DefaultGraphHost              = "localhost"
+	DefaultGraphPort              = 6379
+	DefaultGraphName              = "memorizer"
+	DefaultGraphPasswordEnv       = "MEMORIZER_GRAPH_PASSWORD"
+	DefaultGraphMaxRetries        = 3
+	DefaultGraphRetryDelayMs      = 1000 // 1 second
+	DefaultGraphWriteQueueSize    = 1000
+	DefaultGraphStoreChunkContent = false
+	DefaultGraphDistanceMetric    = "cosine"
+	DefaultGraphUseTLS            = false
+	DefaultGraphTLSCAFileEnv      = "MEMORIZER_GRAPH_TLS_CA_FILE"
+
+	// Related file scoring weight defaults.
+	DefaultRelatedWeightTags       = 1.0
+	DefaultRelatedWeightTopics     = 1.0
+	DefaultRelatedWeightEntities   = 0.5
+	DefaultRelatedWeightImports    = 0.5
+	DefaultRelatedWeightSimilarity = 2.0
 
 	// Semantic provider defaults.
 	DefaultSemanticEnabled   = true
@@ -44,6 +56,10 @@ const (
 	DefaultSemanticRateLimit = 10
 	DefaultSemanticAPIKeyEnv = "ANTHROPIC_API_KEY"
 
+	// Default semantic token prices (USD per 1k tokens) for the default model.
+	DefaultSemanticInputCostPer1K  = 0.003
+	DefaultSemanticOutputCostPer1K = 0.015
+
 	// Embeddings provider defaults.
 	DefaultEmbeddingsEnabled    = true
 	DefaultEmbeddingsProvider   = "openai"
@@ -51,6 +67,44 @@ const (
 	DefaultEmbeddingsDimensions = 3072
 	DefaultEmbeddingsAPIKeyEnv  = "OPENAI_API_KEY"
 
+	// Default embeddings token price (USD per 1k tokens) for the default model.
+	DefaultEmbeddingsCostPer1K = 0.00013
+
+	// Default handling for chunks over the embeddings model's token limit.
+	DefaultEmbeddingsTruncation = "truncate-end"
+
+	// Analysis dry-run defaults.
+	DefaultAnalysisDryRun                = false
+	DefaultAnalysisEmbeddingCostPerToken = 0.13 / 1_000_000
+
+	// Analysis staleness defaults.
+	DefaultAnalysisModTimeToleranceMs = 0
+
+	// Analysis progress defaults.
+	DefaultAnalysisProgressInterval = 10 // seconds
+
+	// Analysis per-file timeout defaults.
+	DefaultAnalysisPerFileTimeout          = 0 // seconds; disabled
+	DefaultAnalysisPersistPartialOnTimeout = false
+
+	// Analysis summary cache defaults.
+	DefaultAnalysisSummaryCacheSize = 1000
+
+	// Analysis per-chunk summary defaults.
+	DefaultAnalysisChunkSummaries = false
+
+	// Analysis queue persistence defaults.
+	DefaultAnalysisPersistentQueue = false
+
+	// Analysis queue degradation defaults, as fractions of queue capacity.
+	DefaultAnalysisDegradationNoEmbedThreshold  = 0.80
+	DefaultAnalysisDegradationMetadataThreshold = 0.95
+
+	// Analysis redaction defaults.
+	DefaultRedactionEnabled          = false
+	DefaultRedactionEntropyThreshold = 4.5
+	DefaultRedactionSkipEmbeddings   = false
+
 	// Skip/include defaults.
 	DefaultSkipHidden = true
 )
@@ -111,7 +165,8 @@ func NewDefaultConfig() Config {
 		LogLevel: DefaultLogLevel,
 		LogFile:  DefaultLogFile,
 		Storage: StorageConfig{
-			DatabasePath: DefaultStorageDatabasePath,
+			DatabasePath:  DefaultStorageDatabasePath,
+			BusyTimeoutMs: DefaultStorageBusyTimeoutMs,
 		},
 		PersistenceQueue: PersistenceQueueConfig{
 			MaxRetries:            DefaultPersistenceQueueMaxRetries,
@@ -135,13 +190,24 @@ func NewDefaultConfig() Config {
 			},
 		},
 		Graph: GraphConfig{
-			Host:           DefaultGraphHost,
-			Port:           DefaultGraphPort,
-			Name:           DefaultGraphName,
-			PasswordEnv:    DefaultGraphPasswordEnv,
-			MaxRetries:     DefaultGraphMaxRetries,
-			RetryDelayMs:   DefaultGraphRetryDelayMs,
-			WriteQueueSize: DefaultGraphWriteQueueSize,
+			Host:              DefaultGraphHost,
+			Port:              DefaultGraphPort,
+			Name:              DefaultGraphName,
+			PasswordEnv:       DefaultGraphPasswordEnv,
+			MaxRetries:        DefaultGraphMaxRetries,
+			RetryDelayMs:      DefaultGraphRetryDelayMs,
+			WriteQueueSize:    DefaultGraphWriteQueueSize,
+			StoreChunkContent: DefaultGraphStoreChunkContent,
+			DistanceMetric:    DefaultGraphDistanceMetric,
+			UseTLS:            DefaultGraphUseTLS,
+			TLSCAFileEnv:      DefaultGraphTLSCAFileEnv,
+			RelatedWeights: RelatedWeightsConfig{
+				Tags:       DefaultRelatedWeightTags,
+				Topics:     DefaultRelatedWeightTopics,
+				Entities:   DefaultRelatedWeightEntities,
+				Imports:    DefaultRelatedWeightImports,
+				Similarity: DefaultRelatedWeightSimilarity,
+			},
 		},
 		Semantic: SemanticConfig{
 			Enabled:   DefaultSemanticEnabled,
@@ -150,6 +216,9 @@ func NewDefaultConfig() Config {
 			RateLimit: DefaultSemanticRateLimit,
 			APIKey:    nil,
 			APIKeyEnv: DefaultSemanticAPIKeyEnv,
+
+			InputCostPer1K:  DefaultSemanticInputCostPer1K,
+			OutputCostPer1K: DefaultSemanticOutputCostPer1K,
 		},
 		Embeddings: EmbeddingsConfig{
 			Enabled:    DefaultEmbeddingsEnabled,
@@ -158,6 +227,29 @@ func NewDefaultConfig() Config {
 			Dimensions: DefaultEmbeddingsDimensions,
 			APIKey:     nil,
 			APIKeyEnv:  DefaultEmbeddingsAPIKeyEnv,
+			CostPer1K:  DefaultEmbeddingsCostPer1K,
+			Truncation: DefaultEmbeddingsTruncation,
+		},
+		Analysis: AnalysisConfig{
+			DryRun:                  DefaultAnalysisDryRun,
+			EmbeddingCostPerToken:   DefaultAnalysisEmbeddingCostPerToken,
+			ModTimeToleranceMs:      DefaultAnalysisModTimeToleranceMs,
+			ProgressInterval:        DefaultAnalysisProgressInterval,
+			PerFileTimeout:          DefaultAnalysisPerFileTimeout,
+			PersistPartialOnTimeout: DefaultAnalysisPersistPartialOnTimeout,
+			CategoryOrder:           []string{},
+			SummaryCacheSize:        DefaultAnalysisSummaryCacheSize,
+			ChunkSummaries:          DefaultAnalysisChunkSummaries,
+			PersistentQueue:         DefaultAnalysisPersistentQueue,
+
+			DegradationNoEmbedThreshold:  DefaultAnalysisDegradationNoEmbedThreshold,
+			DegradationMetadataThreshold: DefaultAnalysisDegradationMetadataThreshold,
+			Redaction: RedactionConfig{
+				Enabled:          DefaultRedactionEnabled,
+				Patterns:         []string{},
+				EntropyThreshold: DefaultRedactionEntropyThreshold,
+				SkipEmbeddings:   DefaultRedactionSkipEmbeddings,
+			},
 		},
 		Defaults: DefaultsConfig{
 			Skip: SkipDefaults{
@@ -193,6 +285,7 @@ func setDefaults() {
 
 	// Storage defaults
 	viper.SetDefault("storage.database_path", DefaultStorageDatabasePath)
+	viper.SetDefault("storage.busy_timeout_ms", DefaultStorageBusyTimeoutMs)
 
 	// Persistence queue defaults
 	viper.SetDefault("persistence_queue.max_retries", DefaultPersistenceQueueMaxRetries)
@@ -209,6 +302,15 @@ func setDefaults() {
 	viper.SetDefault("graph.max_retries", DefaultGraphMaxRetries)
 	viper.SetDefault("graph.retry_delay_ms", DefaultGraphRetryDelayMs)
 	viper.SetDefault("graph.write_queue_size", DefaultGraphWriteQueueSize)
+	viper.SetDefault("graph.store_chunk_content", DefaultGraphStoreChunkContent)
+	viper.SetDefault("graph.distance_metric", DefaultGraphDistanceMetric)
+	viper.SetDefault("graph.use_tls", DefaultGraphUseTLS)
+	viper.SetDefault("graph.tls_ca_file_env", DefaultGraphTLSCAFileEnv)
+	viper.SetDefault("graph.related_weights.tags", DefaultRelatedWeightTags)
+	viper.SetDefault("graph.related_weights.topics", DefaultRelatedWeightTopics)
+	viper.SetDefault("graph.related_weights.entities", DefaultRelatedWeightEntities)
+	viper.SetDefault("graph.related_weights.imports", DefaultRelatedWeightImports)
+	viper.SetDefault("graph.related_weights.similarity", DefaultRelatedWeightSimilarity)
 
 	// Semantic defaults
 	viper.SetDefault("semantic.enabled", DefaultSemanticEnabled)
@@ -216,6 +318,8 @@ func setDefaults() {
 	viper.SetDefault("semantic.model", DefaultSemanticModel)
 	viper.SetDefault("semantic.rate_limit", DefaultSemanticRateLimit)
 	viper.SetDefault("semantic.api_key_env", DefaultSemanticAPIKeyEnv)
+	viper.SetDefault("semantic.input_cost_per_1k", DefaultSemanticInputCostPer1K)
+	viper.SetDefault("semantic.output_cost_per_1k", DefaultSemanticOutputCostPer1K)
 
 	// Embeddings defaults
 	viper.SetDefault("embeddings.enabled", DefaultEmbeddingsEnabled)
@@ -223,6 +327,26 @@ func setDefaults() {
 	viper.SetDefault("embeddings.model", DefaultEmbeddingsModel)
 	viper.SetDefault("embeddings.dimensions", DefaultEmbeddingsDimensions)
 	viper.SetDefault("embeddings.api_key_env", DefaultEmbeddingsAPIKeyEnv)
+	viper.SetDefault("embeddings.cost_per_1k", DefaultEmbeddingsCostPer1K)
+	viper.SetDefault("embeddings.truncation", DefaultEmbeddingsTruncation)
+
+	// Analysis defaults
+	viper.SetDefault("analysis.dry_run", DefaultAnalysisDryRun)
+	viper.SetDefault("analysis.embedding_cost_per_token", DefaultAnalysisEmbeddingCostPerToken)
+	viper.SetDefault("analysis.mod_time_tolerance_ms", DefaultAnalysisModTimeToleranceMs)
+	viper.SetDefault("analysis.progress_interval", DefaultAnalysisProgressInterval)
+	viper.SetDefault("analysis.per_file_timeout", DefaultAnalysisPerFileTimeout)
+	viper.SetDefault("analysis.persist_partial_on_timeout", DefaultAnalysisPersistPartialOnTimeout)
+	viper.SetDefault("analysis.summary_cache_size", DefaultAnalysisSummaryCacheSize)
+	viper.SetDefault("analysis.chunk_summaries", DefaultAnalysisChunkSummaries)
+	viper.SetDefault("analysis.persistent_queue", DefaultAnalysisPersistentQueue)
+	viper.SetDefault("analysis.degradation_no_embed_threshold", DefaultAnalysisDegradationNoEmbedThreshold)
+	viper.SetDefault("analysis.degradation_metadata_threshold", DefaultAnalysisDegradationMetadataThreshold)
+	viper.SetDefault("analysis.category_order", []string{})
+	viper.SetDefault("analysis.redaction.enabled", DefaultRedactionEnabled)
+	viper.SetDefault("analysis.redaction.patterns", []string{})
+	viper.SetDefault("analysis.redaction.entropy_threshold", DefaultRedactionEntropyThreshold)
+	viper.SetDefault("analysis.redaction.skip_embeddings", DefaultRedactionSkipEmbeddings)
 
 	// Skip/include defaults
 	viper.SetDefault("defaults.skip.extensions", DefaultSkipExtensions)
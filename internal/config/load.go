@@ -114,6 +114,9 @@ func setViperDefaults(v *viper.Viper) {
 	v.SetDefault("daemon.event_bus.buffer_size", DefaultDaemonEventBusBufferSize)
 	v.SetDefault("daemon.event_bus.critical_queue_capacity", DefaultDaemonEventBusCriticalQueueCapacity)
 
+	// Storage defaults
+	v.SetDefault("storage.busy_timeout_ms", DefaultStorageBusyTimeoutMs)
+
 	// Graph defaults
 	v.SetDefault("graph.host", DefaultGraphHost)
 	v.SetDefault("graph.port", DefaultGraphPort)
@@ -122,6 +125,10 @@ func setViperDefaults(v *viper.Viper) {
 	v.SetDefault("graph.max_retries", DefaultGraphMaxRetries)
 	v.SetDefault("graph.retry_delay_ms", DefaultGraphRetryDelayMs)
 	v.SetDefault("graph.write_queue_size", DefaultGraphWriteQueueSize)
+	v.SetDefault("graph.store_chunk_content", DefaultGraphStoreChunkContent)
+	v.SetDefault("graph.distance_metric", DefaultGraphDistanceMetric)
+	v.SetDefault("graph.use_tls", DefaultGraphUseTLS)
+	v.SetDefault("graph.tls_ca_file_env", DefaultGraphTLSCAFileEnv)
 
 	// Semantic defaults
 	v.SetDefault("semantic.enabled", DefaultSemanticEnabled)
@@ -136,4 +143,8 @@ func setViperDefaults(v *viper.Viper) {
 	v.SetDefault("embeddings.model", DefaultEmbeddingsModel)
 	v.SetDefault("embeddings.dimensions", DefaultEmbeddingsDimensions)
 	v.SetDefault("embeddings.api_key_env", DefaultEmbeddingsAPIKeyEnv)
+
+	// Analysis defaults
+	v.SetDefault("analysis.degradation_no_embed_threshold", DefaultAnalysisDegradationNoEmbedThreshold)
+	v.SetDefault("analysis.degradation_metadata_threshold", DefaultAnalysisDegradationMetadataThreshold)
 }
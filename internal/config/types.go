@@ -12,6 +12,7 @@ type Config struct {
 	Graph            GraphConfig            `yaml:"graph" mapstructure:"graph"`
 	Semantic         SemanticConfig         `yaml:"semantic" mapstructure:"semantic"`
 	Embeddings       EmbeddingsConfig       `yaml:"embeddings" mapstructure:"embeddings"`
+	Analysis         AnalysisConfig         `yaml:"analysis" mapstructure:"analysis"`
 	Defaults         DefaultsConfig         `yaml:"defaults" mapstructure:"defaults"`
 }
 
@@ -36,6 +37,10 @@ type StorageConfig struct {
 	// DatabasePath is the path to the consolidated SQLite database file.
 	// Supports ~ for home directory expansion.
 	DatabasePath string `yaml:"database_path" mapstructure:"database_path"`
+
+	// BusyTimeoutMs is how long, in milliseconds, a statement waits for a lock
+	// held by another connection before failing with "database is locked".
+	BusyTimeoutMs int `yaml:"busy_timeout_ms" mapstructure:"busy_timeout_ms"`
 }
 
 // PersistenceQueueConfig holds configuration for the durable persistence queue.
@@ -71,6 +76,35 @@ type GraphConfig struct {
 	MaxRetries     int    `yaml:"max_retries" mapstructure:"max_retries"`
 	RetryDelayMs   int    `yaml:"retry_delay_ms" mapstructure:"retry_delay_ms"`
 	WriteQueueSize int    `yaml:"write_queue_size" mapstructure:"write_queue_size"`
+
+	// ReadReplicaAddrs lists host:port addresses of read replicas used for
+	// read-only queries. Empty sends all queries to the primary.
+	ReadReplicaAddrs []string `yaml:"read_replica_addrs" mapstructure:"read_replica_addrs"`
+
+	// StoreChunkContent stores chunk text in the graph so search results can
+	// show it without re-reading source files. Increases graph size.
+	StoreChunkContent bool `yaml:"store_chunk_content" mapstructure:"store_chunk_content"`
+
+	// DistanceMetric is the vector index similarity function: cosine or euclidean.
+	DistanceMetric string `yaml:"distance_metric" mapstructure:"distance_metric"`
+
+	// UseTLS connects to FalkorDB and read replicas over TLS.
+	UseTLS bool `yaml:"use_tls" mapstructure:"use_tls"`
+
+	// TLSCAFileEnv names the env var holding a PEM CA file to trust for TLS.
+	TLSCAFileEnv string `yaml:"tls_ca_file_env" mapstructure:"tls_ca_file_env"`
+
+	// RelatedWeights weights the signals used to score related files.
+	RelatedWeights RelatedWeightsConfig `yaml:"related_weights" mapstructure:"related_weights"`
+}
+
+// RelatedWeightsConfig holds the per-signal weights for related file scoring.
+type RelatedWeightsConfig struct {
+	Tags       float64 `yaml:"tags" mapstructure:"tags"`
+	Topics     float64 `yaml:"topics" mapstructure:"topics"`
+	Entities   float64 `yaml:"entities" mapstructure:"entities"`
+	Imports    float64 `yaml:"imports" mapstructure:"imports"`
+	Similarity float64 `yaml:"similarity" mapstructure:"similarity"`
 }
 
 // SemanticConfig holds semantic analysis provider configuration.
@@ -81,6 +115,11 @@ type SemanticConfig struct {
 	RateLimit int     `yaml:"rate_limit" mapstructure:"rate_limit"`
 	APIKey    *string `yaml:"api_key,omitempty" mapstructure:"api_key"`
 	APIKeyEnv string  `yaml:"api_key_env" mapstructure:"api_key_env"`
+
+	// InputCostPer1K and OutputCostPer1K are the USD prices per 1k tokens used
+	// for the provider cost metric.
+	InputCostPer1K  float64 `yaml:"input_cost_per_1k" mapstructure:"input_cost_per_1k"`
+	OutputCostPer1K float64 `yaml:"output_cost_per_1k" mapstructure:"output_cost_per_1k"`
 }
 
 // ResolveAPIKey returns the API key from config or falls back to environment variable.
@@ -99,6 +138,85 @@ type EmbeddingsConfig struct {
 	Dimensions int     `yaml:"dimensions" mapstructure:"dimensions"`
 	APIKey     *string `yaml:"api_key,omitempty" mapstructure:"api_key"`
 	APIKeyEnv  string  `yaml:"api_key_env" mapstructure:"api_key_env"`
+
+	// BaseURL is the server address for self-hosted providers such as ollama.
+	// Empty uses the provider's default.
+	BaseURL string `yaml:"base_url,omitempty" mapstructure:"base_url"`
+
+	// CostPer1K is the USD price per 1k input tokens used for the provider cost metric.
+	CostPer1K float64 `yaml:"cost_per_1k" mapstructure:"cost_per_1k"`
+
+	// Truncation is how chunks over the model's token limit are handled:
+	// "error", "truncate-end", or "truncate-middle".
+	Truncation string `yaml:"truncation" mapstructure:"truncation"`
+}
+
+// AnalysisConfig holds analysis pipeline configuration.
+type AnalysisConfig struct {
+	// DryRun chunks files and reports projected token and embedding cost totals
+	// without calling providers or writing the knowledge graph.
+	DryRun bool `yaml:"dry_run" mapstructure:"dry_run"`
+
+	// EmbeddingCostPerToken is the per-token rate (USD) used to project embedding cost in dry runs.
+	EmbeddingCostPerToken float64 `yaml:"embedding_cost_per_token" mapstructure:"embedding_cost_per_token"`
+
+	// ModTimeToleranceMs is how far a file's mod time may drift, in milliseconds, before it
+	// is considered changed. Files within tolerance are compared by content hash.
+	ModTimeToleranceMs int `yaml:"mod_time_tolerance_ms" mapstructure:"mod_time_tolerance_ms"`
+
+	// ProgressInterval is how often ingestion progress events are published, in seconds.
+	// Zero disables progress events.
+	ProgressInterval int `yaml:"progress_interval" mapstructure:"progress_interval"`
+
+	// PerFileTimeout bounds the time spent analyzing a single file, in seconds.
+	// Files that exceed it are recorded as TIMEOUT failures. Zero disables the timeout.
+	PerFileTimeout int `yaml:"per_file_timeout" mapstructure:"per_file_timeout"`
+
+	// PersistPartialOnTimeout persists chunks analyzed before a per-file timeout,
+	// even when embeddings were not generated.
+	PersistPartialOnTimeout bool `yaml:"persist_partial_on_timeout" mapstructure:"persist_partial_on_timeout"`
+
+	// CategoryOrder prefers analyzing file categories (docs, config, code, data) in
+	// the listed order. Empty analyzes files in discovery order.
+	CategoryOrder []string `yaml:"category_order" mapstructure:"category_order"`
+
+	// SummaryCacheSize is how many semantic results are kept in memory, keyed by
+	// content hash, so identical content is summarized once. Zero disables the cache.
+	SummaryCacheSize int `yaml:"summary_cache_size" mapstructure:"summary_cache_size"`
+
+	// ChunkSummaries summarizes each chunk with its neighboring chunks and
+	// enclosing section or type as context. It costs one provider call per chunk.
+	ChunkSummaries bool `yaml:"chunk_summaries" mapstructure:"chunk_summaries"`
+
+	// PersistentQueue records queued work items in the registry so analysis
+	// pending at shutdown resumes on the next start instead of being dropped.
+	PersistentQueue bool `yaml:"persistent_queue" mapstructure:"persistent_queue"`
+
+	// DegradationNoEmbedThreshold is the queue capacity fraction at which
+	// analysis stops generating embeddings.
+	DegradationNoEmbedThreshold float64 `yaml:"degradation_no_embed_threshold" mapstructure:"degradation_no_embed_threshold"`
+
+	// DegradationMetadataThreshold is the queue capacity fraction at which
+	// analysis also skips semantic analysis and records metadata only.
+	DegradationMetadataThreshold float64 `yaml:"degradation_metadata_threshold" mapstructure:"degradation_metadata_threshold"`
+
+	Redaction RedactionConfig `yaml:"redaction" mapstructure:"redaction"`
+}
+
+// RedactionConfig holds secret redaction configuration for chunk content and summaries.
+type RedactionConfig struct {
+	// Enabled turns on secret redaction before content is analyzed or stored.
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
+
+	// Patterns are regular expressions matching secrets. Empty uses the built-in patterns.
+	Patterns []string `yaml:"patterns" mapstructure:"patterns"`
+
+	// EntropyThreshold is the Shannon entropy (bits per character) at or above which
+	// long tokens are redacted. Zero disables the entropy check.
+	EntropyThreshold float64 `yaml:"entropy_threshold" mapstructure:"entropy_threshold"`
+
+	// SkipEmbeddings excludes chunks containing redacted secrets from embeddings.
+	SkipEmbeddings bool `yaml:"skip_embeddings" mapstructure:"skip_embeddings"`
 }
 
 // DefaultsConfig holds default skip/include patterns for new remembered paths.
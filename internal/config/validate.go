@@ -3,8 +3,12 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net"
 	"path/filepath"
+	"regexp"
 	"strings"
+
+	"github.com/leefowlercu/agentic-memorizer/internal/providers"
 )
 
 // ValidationError represents a config validation failure.
@@ -50,6 +54,13 @@ var validEmbeddingsProviders = map[string]bool{
 	"openai": true,
 	"voyage": true,
 	"google": true,
+	"ollama": true,
+}
+
+// validGraphDistanceMetrics lists recognized vector index similarity functions.
+var validGraphDistanceMetrics = map[string]bool{
+	"cosine":    true,
+	"euclidean": true,
 }
 
 // Validate checks the configuration for errors.
@@ -86,6 +97,13 @@ func Validate(cfg *Config) error {
 		})
 	}
 
+	if cfg.Storage.BusyTimeoutMs < 1 {
+		errs = append(errs, ValidationError{
+			Field:   "storage.busy_timeout_ms",
+			Message: fmt.Sprintf("must be at least 1, got %d", cfg.Storage.BusyTimeoutMs),
+		})
+	}
+
 	if cfg.Daemon.Metrics.CollectionInterval < 1 {
 		errs = append(errs, ValidationError{
 			Field:   "daemon.metrics.collection_interval",
@@ -149,6 +167,38 @@ func Validate(cfg *Config) error {
 			Message: fmt.Sprintf("must be at least 1, got %d", cfg.Graph.WriteQueueSize),
 		})
 	}
+	for i, addr := range cfg.Graph.ReadReplicaAddrs {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			errs = append(errs, ValidationError{
+				Field:   fmt.Sprintf("graph.read_replica_addrs[%d]", i),
+				Message: fmt.Sprintf("must be host:port, got %q", addr),
+			})
+		}
+	}
+	if !validGraphDistanceMetrics[cfg.Graph.DistanceMetric] {
+		errs = append(errs, ValidationError{
+			Field:   "graph.distance_metric",
+			Message: fmt.Sprintf("must be one of: cosine, euclidean; got %q", cfg.Graph.DistanceMetric),
+		})
+	}
+	relatedWeights := []struct {
+		name  string
+		value float64
+	}{
+		{"tags", cfg.Graph.RelatedWeights.Tags},
+		{"topics", cfg.Graph.RelatedWeights.Topics},
+		{"entities", cfg.Graph.RelatedWeights.Entities},
+		{"imports", cfg.Graph.RelatedWeights.Imports},
+		{"similarity", cfg.Graph.RelatedWeights.Similarity},
+	}
+	for _, w := range relatedWeights {
+		if w.value < 0 {
+			errs = append(errs, ValidationError{
+				Field:   "graph.related_weights." + w.name,
+				Message: fmt.Sprintf("must be non-negative, got %g", w.value),
+			})
+		}
+	}
 
 	// Validate semantic config (only if enabled)
 	if cfg.Semantic.Enabled {
@@ -189,7 +239,7 @@ func Validate(cfg *Config) error {
 		} else if !validEmbeddingsProviders[cfg.Embeddings.Provider] {
 			errs = append(errs, ValidationError{
 				Field:   "embeddings.provider",
-				Message: fmt.Sprintf("must be one of: openai, voyage, google; got %q", cfg.Embeddings.Provider),
+				Message: fmt.Sprintf("must be one of: openai, voyage, google, ollama; got %q", cfg.Embeddings.Provider),
 			})
 		}
 
@@ -206,6 +256,101 @@ func Validate(cfg *Config) error {
 				Message: fmt.Sprintf("must be at least 1, got %d", cfg.Embeddings.Dimensions),
 			})
 		}
+
+		if cfg.Embeddings.Truncation != "" {
+			if _, err := providers.ParseTruncationStrategy(cfg.Embeddings.Truncation); err != nil {
+				errs = append(errs, ValidationError{
+					Field:   "embeddings.truncation",
+					Message: fmt.Sprintf("must be one of: error, truncate-end, truncate-middle; got %q", cfg.Embeddings.Truncation),
+				})
+			}
+		}
+	}
+
+	// Validate provider token prices
+	for _, price := range []struct {
+		field string
+		rate  float64
+	}{
+		{"semantic.input_cost_per_1k", cfg.Semantic.InputCostPer1K},
+		{"semantic.output_cost_per_1k", cfg.Semantic.OutputCostPer1K},
+		{"embeddings.cost_per_1k", cfg.Embeddings.CostPer1K},
+	} {
+		if price.rate < 0 {
+			errs = append(errs, ValidationError{
+				Field:   price.field,
+				Message: fmt.Sprintf("must be non-negative, got %g", price.rate),
+			})
+		}
+	}
+
+	// Validate analysis config
+	if cfg.Analysis.EmbeddingCostPerToken < 0 {
+		errs = append(errs, ValidationError{
+			Field:   "analysis.embedding_cost_per_token",
+			Message: fmt.Sprintf("must be non-negative, got %g", cfg.Analysis.EmbeddingCostPerToken),
+		})
+	}
+	if cfg.Analysis.ModTimeToleranceMs < 0 {
+		errs = append(errs, ValidationError{
+			Field:   "analysis.mod_time_tolerance_ms",
+			Message: fmt.Sprintf("must be non-negative, got %d", cfg.Analysis.ModTimeToleranceMs),
+		})
+	}
+	if cfg.Analysis.ProgressInterval < 0 {
+		errs = append(errs, ValidationError{
+			Field:   "analysis.progress_interval",
+			Message: fmt.Sprintf("must be non-negative, got %d", cfg.Analysis.ProgressInterval),
+		})
+	}
+	if cfg.Analysis.PerFileTimeout < 0 {
+		errs = append(errs, ValidationError{
+			Field:   "analysis.per_file_timeout",
+			Message: fmt.Sprintf("must be non-negative, got %d", cfg.Analysis.PerFileTimeout),
+		})
+	}
+	seenCategories := make(map[string]bool)
+	for i, category := range cfg.Analysis.CategoryOrder {
+		switch {
+		case category != "docs" && category != "config" && category != "code" && category != "data":
+			errs = append(errs, ValidationError{
+				Field:   "analysis.category_order",
+				Message: fmt.Sprintf("invalid category at index %d %q; must be one of docs, config, code, data", i, category),
+			})
+		case seenCategories[category]:
+			errs = append(errs, ValidationError{
+				Field:   "analysis.category_order",
+				Message: fmt.Sprintf("duplicate category at index %d %q", i, category),
+			})
+		}
+		seenCategories[category] = true
+	}
+	if cfg.Analysis.SummaryCacheSize < 0 {
+		errs = append(errs, ValidationError{
+			Field:   "analysis.summary_cache_size",
+			Message: fmt.Sprintf("must be non-negative, got %d", cfg.Analysis.SummaryCacheSize),
+		})
+	}
+	noEmbed, metadataOnly := cfg.Analysis.DegradationNoEmbedThreshold, cfg.Analysis.DegradationMetadataThreshold
+	if noEmbed <= 0 || noEmbed >= metadataOnly || metadataOnly > 1 {
+		errs = append(errs, ValidationError{
+			Field:   "analysis.degradation_no_embed_threshold",
+			Message: fmt.Sprintf("thresholds must satisfy 0 < degradation_no_embed_threshold < degradation_metadata_threshold <= 1, got %g and %g", noEmbed, metadataOnly),
+		})
+	}
+	if cfg.Analysis.Redaction.EntropyThreshold < 0 {
+		errs = append(errs, ValidationError{
+			Field:   "analysis.redaction.entropy_threshold",
+			Message: fmt.Sprintf("must be non-negative, got %g", cfg.Analysis.Redaction.EntropyThreshold),
+		})
+	}
+	for i, pattern := range cfg.Analysis.Redaction.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			errs = append(errs, ValidationError{
+				Field:   "analysis.redaction.patterns",
+				Message: fmt.Sprintf("invalid pattern at index %d %q: %v", i, pattern, err),
+			})
+		}
 	}
 
 	// Validate defaults config
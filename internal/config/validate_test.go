@@ -61,6 +61,70 @@ func TestValidate_InvalidShutdownTimeout_ReturnsError(t *testing.T) {
 	}
 }
 
+func TestValidate_AnalysisCategoryOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		order   []string
+		wantErr bool
+	}{
+		{"DocsFirst", []string{"docs", "config", "code", "data"}, false},
+		{"Empty", []string{}, false},
+		{"UnknownCategory", []string{"docs", "images"}, true},
+		{"Duplicate", []string{"code", "code"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewDefaultConfig()
+			cfg.Analysis.CategoryOrder = tt.order
+
+			err := Validate(&cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_NegativeSummaryCacheSize_ReturnsError(t *testing.T) {
+	cfg := NewDefaultConfig()
+	cfg.Analysis.SummaryCacheSize = -1
+
+	err := Validate(&cfg)
+	if err == nil {
+		t.Error("Validate() expected error for negative summary cache size")
+	}
+}
+
+func TestValidate_DegradationThresholds(t *testing.T) {
+	tests := []struct {
+		name         string
+		noEmbed      float64
+		metadataOnly float64
+		wantErr      bool
+	}{
+		{name: "defaults", noEmbed: DefaultAnalysisDegradationNoEmbedThreshold, metadataOnly: DefaultAnalysisDegradationMetadataThreshold},
+		{name: "metadata at full capacity", noEmbed: 0.9, metadataOnly: 1},
+		{name: "zero no-embed", noEmbed: 0, metadataOnly: 0.5, wantErr: true},
+		{name: "equal thresholds", noEmbed: 0.8, metadataOnly: 0.8, wantErr: true},
+		{name: "reversed thresholds", noEmbed: 0.95, metadataOnly: 0.8, wantErr: true},
+		{name: "metadata above one", noEmbed: 0.8, metadataOnly: 1.2, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewDefaultConfig()
+			cfg.Analysis.DegradationNoEmbedThreshold = tt.noEmbed
+			cfg.Analysis.DegradationMetadataThreshold = tt.metadataOnly
+
+			err := Validate(&cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestValidate_InvalidGraphPort_ReturnsError(t *testing.T) {
 	cfg := NewDefaultConfig()
 	cfg.Graph.Port = 0
@@ -71,6 +135,16 @@ func TestValidate_InvalidGraphPort_ReturnsError(t *testing.T) {
 	}
 }
 
+func TestValidate_InvalidGraphDistanceMetric_ReturnsError(t *testing.T) {
+	cfg := NewDefaultConfig()
+	cfg.Graph.DistanceMetric = "manhattan"
+
+	err := Validate(&cfg)
+	if err == nil {
+		t.Error("Validate() expected error for unknown graph distance metric")
+	}
+}
+
 func TestValidate_InvalidEventBusBufferSize_ReturnsError(t *testing.T) {
 	cfg := NewDefaultConfig()
 	cfg.Daemon.EventBus.BufferSize = 0
@@ -169,6 +243,27 @@ func TestValidate_ValidEmbeddingsProviders(t *testing.T) {
 	}
 }
 
+func TestValidate_InvalidEmbeddingsTruncation_ReturnsError(t *testing.T) {
+	cfg := NewDefaultConfig()
+	cfg.Embeddings.Enabled = true
+	cfg.Embeddings.Truncation = "truncate-start"
+
+	err := Validate(&cfg)
+	if err == nil {
+		t.Error("Validate() expected error for invalid embeddings truncation")
+	}
+}
+
+func TestValidate_NegativeRelatedWeight_ReturnsError(t *testing.T) {
+	cfg := NewDefaultConfig()
+	cfg.Graph.RelatedWeights.Similarity = -1
+
+	err := Validate(&cfg)
+	if err == nil {
+		t.Error("Validate() expected error for negative related weight")
+	}
+}
+
 func TestValidate_MultipleErrors_ReturnsAllErrors(t *testing.T) {
 	cfg := NewDefaultConfig()
 	cfg.Daemon.HTTPPort = 0
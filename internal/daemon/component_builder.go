@@ -171,7 +171,7 @@ func (b *ComponentBuilder) registerDefinitions() {
 		Dependencies:  nil,
 		Build: func(ctx context.Context, deps ComponentContext) (any, error) {
 			dbPath := config.ExpandPath(cfg.Storage.DatabasePath)
-			s, err := storage.Open(ctx, dbPath)
+			s, err := storage.Open(ctx, dbPath, storage.WithBusyTimeout(time.Duration(cfg.Storage.BusyTimeoutMs)*time.Millisecond))
 			if err != nil {
 				return nil, fmt.Errorf("failed to open storage; %w", err)
 			}
@@ -245,7 +245,7 @@ func (b *ComponentBuilder) registerDefinitions() {
 		Kind:          ComponentKindPersistent,
 		Criticality:   CriticalityDegradable,
 		RestartPolicy: RestartOnFailure,
-		Dependencies:  []string{"bus"},
+		Dependencies:  []string{"bus", "embeddings_provider"},
 		Build: func(ctx context.Context, deps ComponentContext) (any, error) {
 			graphCfg := graph.Config{
 				Host:               cfg.Graph.Host,
@@ -254,8 +254,20 @@ func (b *ComponentBuilder) registerDefinitions() {
 				PasswordEnv:        cfg.Graph.PasswordEnv,
 				MaxRetries:         cfg.Graph.MaxRetries,
 				RetryDelay:         time.Duration(cfg.Graph.RetryDelayMs) * time.Millisecond,
-				EmbeddingDimension: cfg.Embeddings.Dimensions,
+				EmbeddingDimension: embeddingDimension(cfg.Embeddings, deps.Providers.Embed),
 				WriteQueueSize:     cfg.Graph.WriteQueueSize,
+				ReadReplicaAddrs:   cfg.Graph.ReadReplicaAddrs,
+				StoreChunkContent:  cfg.Graph.StoreChunkContent,
+				DistanceMetric:     graph.DistanceMetric(cfg.Graph.DistanceMetric),
+				UseTLS:             cfg.Graph.UseTLS,
+				TLSCAFileEnv:       cfg.Graph.TLSCAFileEnv,
+				RelatedWeights: graph.RelatedFileWeights{
+					Tags:       cfg.Graph.RelatedWeights.Tags,
+					Topics:     cfg.Graph.RelatedWeights.Topics,
+					Entities:   cfg.Graph.RelatedWeights.Entities,
+					Imports:    cfg.Graph.RelatedWeights.Imports,
+					Similarity: cfg.Graph.RelatedWeights.Similarity,
+				},
 			}
 			opts := []graph.Option{
 				graph.WithConfig(graphCfg),
@@ -396,17 +408,50 @@ func (b *ComponentBuilder) registerDefinitions() {
 				PersistenceQueue:   deps.PersistenceQueue,
 				AnalysisVersion:    "1.0.0",
 				Logger:             logger,
+				SummaryCache:       analysis.NewSummaryCache(cfg.Analysis.SummaryCacheSize),
+				ChunkSummaries:     cfg.Analysis.ChunkSummaries,
+
+				SemanticTokenRates: metrics.TokenRates{
+					InputPer1K:  cfg.Semantic.InputCostPer1K,
+					OutputPer1K: cfg.Semantic.OutputCostPer1K,
+				},
+				EmbeddingsTokenRates: metrics.TokenRates{InputPer1K: cfg.Embeddings.CostPer1K},
+				EmbeddingsTruncation: providers.TruncationStrategy(cfg.Embeddings.Truncation),
 			}
 
-			q := analysis.NewQueue(deps.Bus,
+			if cfg.Analysis.Redaction.Enabled {
+				redactor, err := analysis.NewSecretRedactor(cfg.Analysis.Redaction.Patterns,
+					analysis.WithEntropyThreshold(cfg.Analysis.Redaction.EntropyThreshold),
+				)
+				if err != nil {
+					return nil, fmt.Errorf("failed to create secret redactor; %w", err)
+				}
+				pipelineCfg.Redactor = redactor
+				pipelineCfg.SkipRedactedEmbeddings = cfg.Analysis.Redaction.SkipEmbeddings
+			}
+
+			opts := []analysis.QueueOption{
 				analysis.WithWorkerCount(workerCount),
 				analysis.WithQueueCapacity(1000),
 				analysis.WithLogger(logger),
 				analysis.WithPipelineConfig(pipelineCfg),
-			)
+				analysis.WithDryRun(cfg.Analysis.DryRun),
+				analysis.WithEmbeddingCostPerToken(cfg.Analysis.EmbeddingCostPerToken),
+				analysis.WithProgressInterval(time.Duration(cfg.Analysis.ProgressInterval) * time.Second),
+				analysis.WithPerFileTimeout(time.Duration(cfg.Analysis.PerFileTimeout) * time.Second),
+				analysis.WithPersistPartialOnTimeout(cfg.Analysis.PersistPartialOnTimeout),
+				analysis.WithCategoryOrder(categoryOrder(cfg.Analysis.CategoryOrder)),
+				analysis.WithDegradationThresholds(cfg.Analysis.DegradationNoEmbedThreshold, cfg.Analysis.DegradationMetadataThreshold),
+			}
+			if cfg.Analysis.PersistentQueue && deps.Registry != nil {
+				opts = append(opts, analysis.WithPersistentQueue(deps.Registry))
+			}
+			q := analysis.NewQueue(deps.Bus, opts...)
 			slog.Info("analysis queue initialized",
 				"workers", workerCount,
 				"pipeline", true,
+				"dry_run", cfg.Analysis.DryRun,
+				"persistent_queue", cfg.Analysis.PersistentQueue,
 				"persistence_queue", deps.PersistenceQueue != nil,
 			)
 			return q, nil
@@ -425,7 +470,7 @@ func (b *ComponentBuilder) registerDefinitions() {
 		Kind:          ComponentKindPersistent,
 		Criticality:   CriticalityDegradable,
 		RestartPolicy: RestartOnFailure,
-		Dependencies:  []string{"bus", "graph", "persistence_queue"},
+		Dependencies:  []string{"bus", "graph", "persistence_queue", "registry"},
 		Build: func(ctx context.Context, deps ComponentContext) (any, error) {
 			if deps.PersistenceQueue == nil {
 				slog.Debug("persistence queue not available; drain worker disabled")
@@ -450,6 +495,7 @@ func (b *ComponentBuilder) registerDefinitions() {
 				deps.Graph,
 				deps.Bus,
 				analysis.WithDrainConfig(drainCfg),
+				analysis.WithDrainRegistry(deps.Registry),
 				analysis.WithDrainLogger(slog.Default().With("component", "drain_worker")),
 			)
 			slog.Info("drain worker initialized",
@@ -468,7 +514,10 @@ func (b *ComponentBuilder) registerDefinitions() {
 		RestartPolicy: RestartNever,
 		Dependencies:  []string{"registry", "bus"},
 		Build: func(ctx context.Context, deps ComponentContext) (any, error) {
-			w := walker.New(deps.Registry, deps.Bus, walker.WithSemanticEnabled(cfg.Semantic.Enabled))
+			w := walker.New(deps.Registry, deps.Bus,
+				walker.WithSemanticEnabled(cfg.Semantic.Enabled),
+				walker.WithModTimeTolerance(time.Duration(cfg.Analysis.ModTimeToleranceMs)*time.Millisecond),
+			)
 			slog.Info("walker initialized")
 			return w, nil
 		},
@@ -513,6 +562,18 @@ func (b *ComponentBuilder) registerDefinitions() {
 		},
 	})
 
+	b.registry.Register(ComponentDefinition{
+		Name:          "job.prune_orphans",
+		Kind:          ComponentKindJob,
+		Criticality:   CriticalityDegradable,
+		RestartPolicy: RestartNever,
+		Dependencies:  []string{"graph", "cleaner"},
+		Build: func(ctx context.Context, deps ComponentContext) (any, error) {
+			// Dependency placeholder only; orchestrator runs jobs directly
+			return nil, nil
+		},
+	})
+
 	// Watcher
 	b.registry.Register(ComponentDefinition{
 		Name:          "watcher",
@@ -605,3 +666,24 @@ func (b *ComponentBuilder) registerDefinitions() {
 		},
 	})
 }
+
+// categoryOrder converts configured category names to analysis file categories.
+func categoryOrder(names []string) []analysis.FileCategory {
+	order := make([]analysis.FileCategory, 0, len(names))
+	for _, name := range names {
+		order = append(order, analysis.FileCategory(name))
+	}
+	return order
+}
+
+// embeddingDimension returns the vector index dimension: the provider's
+// reported dimensions when known, since models such as Ollama's are probed
+// rather than configured, or the configured dimensions otherwise.
+func embeddingDimension(cfg config.EmbeddingsConfig, provider providers.EmbeddingsProvider) int {
+	if provider != nil {
+		if dims := provider.Dimensions(); dims > 0 {
+			return dims
+		}
+	}
+	return cfg.Dimensions
+}
@@ -2,8 +2,11 @@ package daemon
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"log/slog"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/leefowlercu/agentic-memorizer/internal/config"
@@ -251,3 +254,30 @@ func TestComponentBuilder_WithBuilderLogger(t *testing.T) {
 		t.Fatal("expected non-nil builder")
 	}
 }
+
+func TestEmbeddingDimensionUsesOllamaProbe(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"embedding": make([]float64, 768)})
+	}))
+	defer srv.Close()
+
+	cfg := config.NewDefaultConfig()
+	cfg.Embeddings.Enabled = true
+	cfg.Embeddings.Provider = "ollama"
+	cfg.Embeddings.Model = "nomic-embed-text"
+	cfg.Embeddings.BaseURL = srv.URL
+
+	provider, err := createEmbeddingsProvider(&cfg.Embeddings)
+	if err != nil {
+		t.Fatalf("createEmbeddingsProvider failed: %v", err)
+	}
+	if got := provider.Dimensions(); got != 768 {
+		t.Errorf("provider dimensions = %d, want probed 768 over configured %d", got, cfg.Embeddings.Dimensions)
+	}
+	if got := embeddingDimension(cfg.Embeddings, provider); got != 768 {
+		t.Errorf("index dimension = %d, want 768", got)
+	}
+	if got := embeddingDimension(cfg.Embeddings, nil); got != cfg.Embeddings.Dimensions {
+		t.Errorf("index dimension without provider = %d, want configured %d", got, cfg.Embeddings.Dimensions)
+	}
+}
@@ -158,6 +158,8 @@ func (c *ComponentHealthCollector) CollectComponents() map[string]ComponentHealt
 		switch stats.State {
 		case analysis.QueueStateRunning:
 			status = ComponentStatusRunning
+		case analysis.QueueStatePaused:
+			status = ComponentStatusDegraded
 		case analysis.QueueStateStopped:
 			status = ComponentStatusStopped
 		default:
@@ -176,6 +178,7 @@ func (c *ComponentHealthCollector) CollectComponents() map[string]ComponentHealt
 				"analysis_failures":    stats.AnalysisFailures,
 				"persistence_failures": stats.PersistenceFailures,
 				"degradation_mode":     stats.DegradationMode,
+				"paused":               stats.State == analysis.QueueStatePaused,
 			},
 		}
 	}
@@ -103,15 +103,15 @@ func (m *JobManager) Rebuild(ctx context.Context, full bool) (*RebuildResult, er
 	}
 
 	// Run reconciliation after walk completes to clean up stale entries
-	discoveredPaths := m.walker.DrainDiscoveredPaths()
-	if discoveredPaths != nil && m.cleaner != nil {
+	walkResult := m.walker.DrainWalkResult()
+	if walkResult != nil && m.cleaner != nil {
 		// Get remembered paths to reconcile against
 		rememberedPaths, listErr := m.registry.ListPaths(ctx)
 		if listErr != nil {
 			m.logger.Warn("failed to list paths for reconciliation", "error", listErr)
 		} else {
 			for _, rp := range rememberedPaths {
-				result, reconcileErr := m.cleaner.Reconcile(ctx, rp.Path, discoveredPaths)
+				result, reconcileErr := m.cleaner.Reconcile(ctx, rp.Path, *walkResult)
 				if reconcileErr != nil {
 					m.logger.Warn("reconciliation failed", "path", rp.Path, "error", reconcileErr)
 				} else if result.StaleRemoved > 0 {
@@ -198,6 +198,56 @@ func (m *JobManager) RebuildWithRecord(ctx context.Context, full bool, jobName s
 	return rebuildResult, runErr
 }
 
+// Prune deletes graph nodes orphaned by interrupted writes, recording the run
+// for health/status.
+func (m *JobManager) Prune(ctx context.Context) (*PruneResult, error) {
+	if m.cleaner == nil {
+		return nil, fmt.Errorf("cleaner not initialized")
+	}
+
+	const jobName = "job.prune_orphans"
+	var pruneResult *PruneResult
+	var runErr error
+
+	if m.healthCollector != nil {
+		m.healthCollector.RecordJobStart(jobName, time.Now())
+	}
+
+	runResult := m.jobRunner.Run(ctx, jobName, func(runCtx context.Context) RunResult {
+		start := time.Now()
+		result := RunResult{
+			Status:    RunFailed,
+			StartedAt: start,
+			Counts:    map[string]int{"deleted": 0},
+		}
+
+		stats, err := m.cleaner.PruneOrphans(runCtx)
+		runErr = err
+		result.FinishedAt = time.Now()
+		result.Counts["deleted"] = stats.Total()
+
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+
+		pruneResult = &PruneResult{
+			Status:   "completed",
+			Deleted:  stats.Deleted,
+			Total:    stats.Total(),
+			Duration: time.Since(start).String(),
+		}
+		result.Status = RunSuccess
+		return result
+	})
+
+	if m.healthCollector != nil {
+		m.healthCollector.RecordJobResult(jobName, runResult)
+	}
+
+	return pruneResult, runErr
+}
+
 // StartPeriodicRebuild starts a goroutine that triggers incremental rebuilds at the configured interval.
 func (m *JobManager) StartPeriodicRebuild(ctx context.Context, interval time.Duration) {
 	m.rebuildStopChan = make(chan struct{})
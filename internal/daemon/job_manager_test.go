@@ -3,11 +3,13 @@ package daemon
 import (
 	"context"
 	"errors"
+	"io"
 	"log/slog"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/leefowlercu/agentic-memorizer/internal/cleaner"
 	"github.com/leefowlercu/agentic-memorizer/internal/registry"
 	"github.com/leefowlercu/agentic-memorizer/internal/walker"
 )
@@ -63,10 +65,10 @@ func (m *mockWalker) Stats() walker.WalkerStats {
 	return m.stats
 }
 
-func (m *mockWalker) DrainDiscoveredPaths() map[string]struct{} {
+func (m *mockWalker) DrainWalkResult() *walker.WalkResult {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	return m.discoveredPaths
+	return &walker.WalkResult{Paths: m.discoveredPaths, Complete: true}
 }
 
 // mockRegistry implements registry.Registry for testing.
@@ -169,7 +171,11 @@ func (m *mockRegistry) UpdateSemanticState(ctx context.Context, path string, ana
 	return nil
 }
 
-func (m *mockRegistry) UpdateEmbeddingsState(ctx context.Context, path string, err error) error {
+func (m *mockRegistry) UpdateEmbeddingsState(ctx context.Context, path string, model string, err error) error {
+	return nil
+}
+
+func (m *mockRegistry) UpdateChunkerState(ctx context.Context, path string, chunkerVersion string) error {
 	return nil
 }
 
@@ -189,6 +195,50 @@ func (m *mockRegistry) ListFilesNeedingEmbeddings(ctx context.Context, parentPat
 	return nil, nil
 }
 
+func (m *mockRegistry) ListFilesWithStaleEmbeddings(ctx context.Context, parentPath string, currentModel string) ([]registry.FileState, error) {
+	return nil, nil
+}
+
+func (m *mockRegistry) CountByAnalysisStatus(ctx context.Context) (registry.AnalysisStatusCounts, error) {
+	return registry.AnalysisStatusCounts{}, nil
+}
+
+func (m *mockRegistry) EnqueueWorkItem(ctx context.Context, item *registry.PendingWorkItem) error {
+	return nil
+}
+
+func (m *mockRegistry) CompleteWorkItem(ctx context.Context, id int64, enqueuedAt time.Time) error {
+	return nil
+}
+
+func (m *mockRegistry) ListPendingWorkItems(ctx context.Context) ([]registry.PendingWorkItem, error) {
+	return nil, nil
+}
+
+func (m *mockRegistry) RecordDeadLetter(ctx context.Context, dl *registry.DeadLetter) error {
+	return nil
+}
+
+func (m *mockRegistry) GetDeadLetter(ctx context.Context, filePath string) (*registry.DeadLetter, error) {
+	return nil, registry.ErrPathNotFound
+}
+
+func (m *mockRegistry) ListDeadLetters(ctx context.Context) ([]registry.DeadLetter, error) {
+	return nil, nil
+}
+
+func (m *mockRegistry) DeleteDeadLetter(ctx context.Context, filePath string) error {
+	return nil
+}
+
+func (m *mockRegistry) Export(ctx context.Context, w io.Writer) error {
+	return nil
+}
+
+func (m *mockRegistry) Import(ctx context.Context, rd io.Reader) error {
+	return nil
+}
+
 func (m *mockRegistry) CheckPathHealth(ctx context.Context) ([]registry.PathStatus, error) {
 	return nil, nil
 }
@@ -322,6 +372,39 @@ func TestJobManager_Rebuild_NilWalker(t *testing.T) {
 	}
 }
 
+func TestJobManager_Prune(t *testing.T) {
+	r := newMockRegistry()
+	bag := &ComponentBag{}
+	hc := NewComponentHealthCollector(bag)
+
+	// Without a graph the cleaner has nothing to prune
+	m := NewJobManager(nil, nil, cleaner.New(r, nil, nil), r, hc)
+
+	result, err := m.Prune(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != "completed" || result.Total != 0 {
+		t.Errorf("result = %+v, want completed with 0 deleted", result)
+	}
+
+	hc.jobMu.Lock()
+	run, ok := hc.jobResults["job.prune_orphans"]
+	hc.jobMu.Unlock()
+	if !ok || run.Status != RunSuccess {
+		t.Errorf("expected successful prune job to be recorded, got %+v", run)
+	}
+}
+
+func TestJobManager_Prune_NilCleaner(t *testing.T) {
+	m := NewJobManager(nil, nil, nil, newMockRegistry(), nil)
+
+	_, err := m.Prune(context.Background())
+	if err == nil || err.Error() != "cleaner not initialized" {
+		t.Errorf("expected cleaner not initialized error, got %v", err)
+	}
+}
+
 func TestJobManager_Rebuild_WalkError(t *testing.T) {
 	w := newMockWalker()
 	w.walkErr = errors.New("simulated walk error")
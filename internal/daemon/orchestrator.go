@@ -165,6 +165,7 @@ func (o *Orchestrator) Initialize(ctx context.Context) error {
 		}
 		return o.jobManager.RebuildWithRecord(ctx, full, jobName)
 	})
+	o.daemon.server.SetPruneFunc(o.jobManager.Prune)
 
 	o.subscribeRememberedPathEvents()
 	o.subscribeHealthAndMetricsEvents()
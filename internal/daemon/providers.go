@@ -72,6 +72,20 @@ func createEmbeddingsProvider(cfg *config.EmbeddingsConfig) (providers.Embedding
 		return nil, nil
 	}
 
+	// Ollama runs locally and needs no API key
+	if cfg.Provider == "ollama" {
+		var opts []embeddings.OllamaEmbeddingsOption
+		if cfg.Model != "" {
+			opts = append(opts, embeddings.WithOllamaModel(cfg.Model))
+		}
+		if cfg.BaseURL != "" {
+			opts = append(opts, embeddings.WithOllamaBaseURL(cfg.BaseURL))
+		}
+		// Dimensions are probed from the model rather than taken from
+		// embeddings.dimensions, whose default belongs to hosted models
+		return embeddings.NewOllamaEmbeddingsProvider(opts...), nil
+	}
+
 	// Ensure API key is available in environment
 	apiKey := cfg.ResolveAPIKey()
 	if apiKey == "" {
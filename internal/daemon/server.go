@@ -33,6 +33,18 @@ type RebuildResult struct {
 // RebuildFunc is a function that triggers a rebuild operation.
 type RebuildFunc func(ctx context.Context, full bool) (*RebuildResult, error)
 
+// PruneResult contains the result of pruning orphaned graph nodes.
+type PruneResult struct {
+	Status   string         `json:"status"`
+	Deleted  map[string]int `json:"deleted,omitempty"`
+	Total    int            `json:"total"`
+	Duration string         `json:"duration"`
+	Error    string         `json:"error,omitempty"`
+}
+
+// PruneFunc is a function that prunes orphaned graph nodes.
+type PruneFunc func(ctx context.Context) (*PruneResult, error)
+
 // RememberFunc handles remember requests.
 type RememberFunc func(ctx context.Context, req RememberRequest) (*RememberResponse, error)
 
@@ -50,6 +62,7 @@ type Server struct {
 	mcpHandler     http.Handler
 	metricsHandler http.Handler
 	rebuildFunc    RebuildFunc
+	pruneFunc      PruneFunc
 	rememberFunc   RememberFunc
 	forgetFunc     ForgetFunc
 	listFunc       ListFunc
@@ -73,6 +86,7 @@ func (s *Server) setupRoutes() {
 	s.router.Get("/healthz", s.handleHealthz)
 	s.router.Get("/readyz", s.handleReadyz)
 	s.router.Post("/rebuild", s.handleRebuild)
+	s.router.Post("/prune", s.handlePrune)
 	s.router.Post("/remember", s.handleRemember)
 	s.router.Post("/forget", s.handleForget)
 	s.router.Get("/list", s.handleList)
@@ -114,6 +128,11 @@ func (s *Server) SetRebuildFunc(fn RebuildFunc) {
 	s.rebuildFunc = fn
 }
 
+// SetPruneFunc sets the function to call when pruning is requested.
+func (s *Server) SetPruneFunc(fn PruneFunc) {
+	s.pruneFunc = fn
+}
+
 // SetRememberFunc sets the function to call when remember is requested.
 func (s *Server) SetRememberFunc(fn RememberFunc) {
 	s.rememberFunc = fn
@@ -204,6 +223,38 @@ func (s *Server) handleRebuild(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(result)
 }
 
+// handlePrune handles the /prune endpoint.
+// Deletes graph nodes orphaned by interrupted writes.
+func (s *Server) handlePrune(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if s.pruneFunc == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(PruneResult{
+			Status: "error",
+			Error:  "prune not available",
+		})
+		return
+	}
+
+	// Like rebuild, pruning completes even if the client disconnects
+	pruneCtx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	result, err := s.pruneFunc(pruneCtx)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(PruneResult{
+			Status: "error",
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(result)
+}
+
 // handleRemember handles the /remember endpoint.
 func (s *Server) handleRemember(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
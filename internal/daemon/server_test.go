@@ -561,6 +561,44 @@ func TestServer_Rebuild_Success(t *testing.T) {
 	}
 }
 
+func TestServer_Prune(t *testing.T) {
+	hm := NewHealthManager()
+	srv := NewServer(hm, ServerConfig{
+		Port: 0,
+		Bind: "127.0.0.1",
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/prune", nil)
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("POST /prune without handler status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+
+	srv.SetPruneFunc(func(ctx context.Context) (*PruneResult, error) {
+		return &PruneResult{
+			Status:  "completed",
+			Deleted: map[string]int{"Chunk": 2, "CodeMeta": 1},
+			Total:   3,
+		}, nil
+	})
+
+	req = httptest.NewRequest(http.MethodPost, "/prune", nil)
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("POST /prune status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	var response PruneResult
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Total != 3 || response.Deleted["Chunk"] != 2 {
+		t.Errorf("response = %+v, want 3 deleted with 2 chunks", response)
+	}
+}
+
 func TestServer_Rebuild_Failure(t *testing.T) {
 	hm := NewHealthManager()
 	srv := NewServer(hm, ServerConfig{
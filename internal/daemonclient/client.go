@@ -103,6 +103,15 @@ func (c *Client) Rebuild(ctx context.Context, full bool) (*daemon.RebuildResult,
 	return &result, nil
 }
 
+// Prune triggers /prune to delete orphaned graph nodes.
+func (c *Client) Prune(ctx context.Context) (*daemon.PruneResult, error) {
+	var result daemon.PruneResult
+	if err := c.doJSON(ctx, http.MethodPost, "/prune", nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
 // Remember registers a path with the daemon.
 func (c *Client) Remember(ctx context.Context, req daemon.RememberRequest) (*daemon.RememberResponse, error) {
 	var result daemon.RememberResponse
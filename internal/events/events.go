@@ -64,6 +64,12 @@ const (
 	// AnalysisEmbeddingsComplete is published when embeddings generation completes.
 	AnalysisEmbeddingsComplete EventType = "analysis.embeddings_complete"
 
+	// AnalysisDryRun is published with projected chunk and cost figures for a dry-run file.
+	AnalysisDryRun EventType = "analysis.dry_run"
+
+	// IngestProgress is published periodically during ingestion and when a batch completes.
+	IngestProgress EventType = "ingest.progress"
+
 	// ConfigReloaded is published when configuration is successfully reloaded.
 	ConfigReloaded EventType = "config.reloaded"
 
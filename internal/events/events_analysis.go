@@ -106,6 +106,27 @@ type IngestDecisionEvent struct {
 	Reason string
 }
 
+// DryRunEvent contains projected analysis figures for a file processed in dry-run mode.
+type DryRunEvent struct {
+	// Path is the absolute path to the file.
+	Path string
+
+	// IngestMode is the ingest mode the file would be processed with.
+	IngestMode string
+
+	// ChunkerUsed is the chunker that produced the chunks.
+	ChunkerUsed string
+
+	// ChunkCount is the number of chunks produced.
+	ChunkCount int
+
+	// TokenEstimate is the estimated total tokens across all chunks.
+	TokenEstimate int
+
+	// EstimatedEmbeddingCost is the projected embeddings API cost in USD.
+	EstimatedEmbeddingCost float64
+}
+
 // NewAnalysisComplete creates an AnalysisComplete event.
 func NewAnalysisComplete(path, contentHash string, analysisType AnalysisType, duration time.Duration) Event {
 	return NewEvent(AnalysisComplete, &AnalysisEvent{
@@ -223,6 +244,18 @@ func NewAnalysisEmbeddingsComplete(path, contentHash string, duration time.Durat
 	})
 }
 
+// NewAnalysisDryRun creates an AnalysisDryRun event.
+func NewAnalysisDryRun(path, ingestMode, chunkerUsed string, chunkCount, tokenEstimate int, estimatedCost float64) Event {
+	return NewEvent(AnalysisDryRun, &DryRunEvent{
+		Path:                   path,
+		IngestMode:             ingestMode,
+		ChunkerUsed:            chunkerUsed,
+		ChunkCount:             chunkCount,
+		TokenEstimate:          tokenEstimate,
+		EstimatedEmbeddingCost: estimatedCost,
+	})
+}
+
 func errorString(err error) string {
 	if err == nil {
 		return ""
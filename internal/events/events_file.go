@@ -18,8 +18,16 @@ type FileEvent struct {
 
 	// IsNew indicates if this is a newly discovered file (for FileDiscovered events).
 	IsNew bool
+
+	// Priority hints how urgently the file should be analyzed; higher is sooner.
+	// Zero for background discovery.
+	Priority int
 }
 
+// FilePriorityExplicit is the priority of files discovered because a user
+// explicitly remembered or re-remembered a path.
+const FilePriorityExplicit = 100
+
 // RememberedPathRemovedEvent contains data for remembered path removal events.
 type RememberedPathRemovedEvent struct {
 	// Path is the remembered path that was removed.
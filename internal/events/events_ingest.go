@@ -0,0 +1,44 @@
+package events
+
+import "time"
+
+// IngestProgressEvent contains ingestion progress counts for the current batch.
+type IngestProgressEvent struct {
+	// Discovered is the total number of files queued for analysis.
+	Discovered int
+
+	// Processed is the number of files analyzed successfully.
+	Processed int
+
+	// Pending is the number of discovered files not yet processed or failed.
+	Pending int
+
+	// Failed is the number of files that failed analysis or persistence permanently.
+	Failed int
+
+	// Throughput is the number of files completed per second in the current batch.
+	Throughput float64
+
+	// Elapsed is the time since the current batch started.
+	Elapsed time.Duration
+
+	// ETA is the estimated time until pending files complete, or zero if unknown.
+	ETA time.Duration
+
+	// Complete indicates all discovered files have been processed or failed.
+	Complete bool
+}
+
+// NewIngestProgress creates an IngestProgress event.
+func NewIngestProgress(discovered, processed, pending, failed int, throughput float64, elapsed, eta time.Duration, complete bool) Event {
+	return NewEvent(IngestProgress, &IngestProgressEvent{
+		Discovered: discovered,
+		Processed:  processed,
+		Pending:    pending,
+		Failed:     failed,
+		Throughput: throughput,
+		Elapsed:    elapsed,
+		ETA:        eta,
+		Complete:   complete,
+	})
+}
@@ -0,0 +1,43 @@
+package events
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// SubscribeTyped registers a handler for an event type whose payload is of
+// type T. The payload assertion is performed once here; events carrying a
+// different payload type are logged and not delivered to the handler.
+func SubscribeTyped[T any](bus Bus, eventType EventType, handler func(T)) func() {
+	return bus.Subscribe(eventType, func(event Event) {
+		payload, ok := event.Payload.(T)
+		if !ok {
+			var want T
+			slog.Warn("event payload type mismatch",
+				"event_type", event.Type,
+				"got", typeName(event.Payload),
+				"expected", typeName(want),
+			)
+			return
+		}
+		handler(payload)
+	})
+}
+
+// SubscribeAnalysis registers a handler for an analysis event type.
+func SubscribeAnalysis(bus Bus, eventType EventType, handler func(*AnalysisEvent)) func() {
+	return SubscribeTyped(bus, eventType, handler)
+}
+
+// SubscribeFile registers a handler for a file event type.
+func SubscribeFile(bus Bus, eventType EventType, handler func(*FileEvent)) func() {
+	return SubscribeTyped(bus, eventType, handler)
+}
+
+// typeName returns a printable type name for log output.
+func typeName(v any) string {
+	if v == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("%T", v)
+}
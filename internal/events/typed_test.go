@@ -0,0 +1,84 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// recordHandler forwards log records to a channel for inspection.
+type recordHandler struct {
+	records chan slog.Record
+}
+
+func (h *recordHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordHandler) Handle(_ context.Context, r slog.Record) error {
+	h.records <- r
+	return nil
+}
+
+func (h *recordHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h *recordHandler) WithGroup(string) slog.Handler { return h }
+
+func TestSubscribeTyped(t *testing.T) {
+	handler := &recordHandler{records: make(chan slog.Record, 10)}
+	prev := slog.Default()
+	slog.SetDefault(slog.New(handler))
+	defer slog.SetDefault(prev)
+
+	bus := NewBus()
+	defer bus.Close()
+
+	analysis := make(chan *AnalysisEvent, 4)
+	unsubAnalysis := SubscribeAnalysis(bus, AnalysisFailed, func(e *AnalysisEvent) {
+		analysis <- e
+	})
+	defer unsubAnalysis()
+
+	files := make(chan *FileEvent, 4)
+	unsubFile := SubscribeFile(bus, FileDiscovered, func(e *FileEvent) {
+		files <- e
+	})
+	defer unsubFile()
+
+	ctx := context.Background()
+	bus.Publish(ctx, NewFileDiscovered("/test/file.go", "abc123", 1, time.Time{}, true))
+	bus.Publish(ctx, NewPathDeleted("/test/other.go"))
+
+	select {
+	case e := <-files:
+		if e.Path != "/test/file.go" {
+			t.Errorf("file path = %q, want %q", e.Path, "/test/file.go")
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("expected FileDiscovered to reach the typed handler")
+	}
+
+	// Payload mismatch is logged and not delivered
+	bus.Publish(ctx, NewEvent(AnalysisFailed, &FileEvent{Path: "/test/file.go"}))
+
+	select {
+	case r := <-handler.records:
+		if r.Level != slog.LevelWarn {
+			t.Errorf("log level = %v, want %v", r.Level, slog.LevelWarn)
+		}
+		if !strings.Contains(r.Message, "mismatch") {
+			t.Errorf("log message = %q, want payload mismatch", r.Message)
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("expected warning for payload mismatch")
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	if len(analysis) != 0 {
+		t.Errorf("analysis handler invoked %d times, want 0", len(analysis))
+	}
+	if len(files) != 0 {
+		t.Errorf("file handler invoked %d extra times, want 0", len(files))
+	}
+}
@@ -16,6 +16,8 @@ var payloadTypes = map[EventType]reflect.Type{
 	AnalysisSkipped:            reflect.TypeOf(&IngestDecisionEvent{}),
 	AnalysisSemanticComplete:   reflect.TypeOf(&AnalysisEvent{}),
 	AnalysisEmbeddingsComplete: reflect.TypeOf(&AnalysisEvent{}),
+	IngestProgress:             reflect.TypeOf(&IngestProgressEvent{}),
+	AnalysisDryRun:             reflect.TypeOf(&DryRunEvent{}),
 	GraphPersistenceFailed:     reflect.TypeOf(&GraphEvent{}),
 	GraphFatal:                 reflect.TypeOf(&GraphFatalEvent{}),
 	GraphConnected:             reflect.TypeOf(&GraphConnectionEvent{}),
@@ -113,6 +113,25 @@ func TestXMLFormatter(t *testing.T) {
 		}
 	})
 
+	t.Run("ChunkContent", func(t *testing.T) {
+		snapshot := testSnapshot()
+		snapshot.Chunks = []graph.ChunkNode{
+			{ID: "chunk-1", FilePath: "/test/file1.go", Index: 0, ContentHash: "c1", EndOffset: 12, ChunkType: "code", Content: "package main"},
+		}
+		output, err := formatter.Format(snapshot)
+		if err != nil {
+			t.Fatalf("Format failed: %v", err)
+		}
+
+		var parsed xmlSnapshot
+		if err := xml.Unmarshal(output, &parsed); err != nil {
+			t.Fatalf("Output is not valid XML: %v", err)
+		}
+		if len(parsed.Chunks) != 1 || parsed.Chunks[0].Content != "package main" || parsed.Chunks[0].FilePath != "/test/file1.go" {
+			t.Errorf("Chunks = %+v, want the chunk with its content", parsed.Chunks)
+		}
+	})
+
 	t.Run("EmptySnapshot", func(t *testing.T) {
 		snapshot := &graph.GraphSnapshot{
 			Version:    1,
@@ -173,6 +192,28 @@ func TestJSONFormatter(t *testing.T) {
 		if parsed.TotalChunks != 10 {
 			t.Errorf("TotalChunks = %d, want %d", parsed.TotalChunks, 10)
 		}
+		if strings.Contains(string(output), `"chunks"`) {
+			t.Error("chunks should be omitted when no chunk content is stored")
+		}
+	})
+
+	t.Run("ChunkContent", func(t *testing.T) {
+		snapshot := testSnapshot()
+		snapshot.Chunks = []graph.ChunkNode{
+			{ID: "chunk-1", FilePath: "/test/file1.go", Index: 0, ContentHash: "c1", EndOffset: 12, ChunkType: "code", Content: "package main"},
+		}
+		output, err := formatter.Format(snapshot)
+		if err != nil {
+			t.Fatalf("Format failed: %v", err)
+		}
+
+		var parsed jsonSnapshot
+		if err := json.Unmarshal(output, &parsed); err != nil {
+			t.Fatalf("Output is not valid JSON: %v", err)
+		}
+		if len(parsed.Chunks) != 1 || parsed.Chunks[0].Content != "package main" || parsed.Chunks[0].FilePath != "/test/file1.go" {
+			t.Errorf("Chunks = %+v, want the chunk with its content", parsed.Chunks)
+		}
 	})
 
 	t.Run("PrettyFormat", func(t *testing.T) {
@@ -257,6 +298,25 @@ func TestTOONFormatter(t *testing.T) {
 		if !strings.Contains(content, "@stats f=2 d=1 c=10") {
 			t.Error("Output should contain stats footer")
 		}
+		if strings.Contains(content, "#c\n") {
+			t.Error("chunks section should be omitted when no chunk content is stored")
+		}
+	})
+
+	t.Run("ChunkContent", func(t *testing.T) {
+		snapshot := testSnapshot()
+		snapshot.Chunks = []graph.ChunkNode{
+			{ID: "chunk-1", FilePath: "/test/file1.go", Index: 0, ContentHash: "c1", EndOffset: 25, ChunkType: "code", Content: "package main\n\nfunc main() {}"},
+		}
+		output, err := formatter.Format(snapshot)
+		if err != nil {
+			t.Fatalf("Format failed: %v", err)
+		}
+
+		want := "#c\nchunk-1|/test/file1.go|0|code|package main\\n\\nfunc main() {}\n"
+		if !strings.Contains(string(output), want) {
+			t.Errorf("Output should contain chunk line %q, got:\n%s", want, output)
+		}
 	})
 
 	t.Run("TokenEfficiency", func(t *testing.T) {
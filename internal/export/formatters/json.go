@@ -46,6 +46,7 @@ type jsonSnapshot struct {
 	Tags               []jsonTag       `json:"tags"`
 	Topics             []jsonTopic     `json:"topics"`
 	Entities           []jsonEntity    `json:"entities"`
+	Chunks             []jsonChunk     `json:"chunks,omitempty"`
 	TotalChunks        int             `json:"total_chunks"`
 	TotalRelationships int             `json:"total_relationships"`
 }
@@ -88,6 +89,17 @@ type jsonEntity struct {
 	UsageCount int    `json:"usage_count"`
 }
 
+type jsonChunk struct {
+	ID          string `json:"id"`
+	FilePath    string `json:"file_path"`
+	Index       int    `json:"index"`
+	ContentHash string `json:"content_hash"`
+	StartOffset int    `json:"start_offset"`
+	EndOffset   int    `json:"end_offset"`
+	ChunkType   string `json:"chunk_type,omitempty"`
+	Content     string `json:"content"`
+}
+
 // Format converts the graph snapshot to JSON.
 func (f *JSONFormatter) Format(snapshot *graph.GraphSnapshot) ([]byte, error) {
 	js := jsonSnapshot{
@@ -155,6 +167,20 @@ func (f *JSONFormatter) Format(snapshot *graph.GraphSnapshot) ([]byte, error) {
 		})
 	}
 
+	// Convert chunks with stored content
+	for _, chunk := range snapshot.Chunks {
+		js.Chunks = append(js.Chunks, jsonChunk{
+			ID:          chunk.ID,
+			FilePath:    chunk.FilePath,
+			Index:       chunk.Index,
+			ContentHash: chunk.ContentHash,
+			StartOffset: chunk.StartOffset,
+			EndOffset:   chunk.EndOffset,
+			ChunkType:   chunk.ChunkType,
+			Content:     chunk.Content,
+		})
+	}
+
 	// Marshal to JSON
 	var data []byte
 	var err error
@@ -20,6 +20,7 @@ import (
 //	#t name|cnt
 //	#p name|desc|cnt
 //	#e name|type|cnt
+//	#c id|path|idx|type|content
 //	@stats f=10 d=5 c=100 t=5 p=3 e=8 r=50
 type TOONFormatter struct{}
 
@@ -116,6 +117,20 @@ func (f *TOONFormatter) Format(snapshot *graph.GraphSnapshot) ([]byte, error) {
 		}
 	}
 
+	// Chunks section, present only when chunk content is stored. Content is
+	// kept whole since it is the point of exporting chunks.
+	if len(snapshot.Chunks) > 0 {
+		buf.WriteString("#c\n")
+		for _, chunk := range snapshot.Chunks {
+			fmt.Fprintf(&buf, "%s|%s|%d|%s|%s\n",
+				chunk.ID,
+				chunk.FilePath,
+				chunk.Index,
+				chunk.ChunkType,
+				escapeTOON(chunk.Content))
+		}
+	}
+
 	// Stats footer
 	fmt.Fprintf(&buf, "@stats f=%d d=%d c=%d t=%d p=%d e=%d r=%d\n",
 		len(snapshot.Files),
@@ -41,6 +41,7 @@ type xmlSnapshot struct {
 	Tags        []xmlTag       `xml:"tags>tag"`
 	Topics      []xmlTopic     `xml:"topics>topic"`
 	Entities    []xmlEntity    `xml:"entities>entity"`
+	Chunks      []xmlChunk     `xml:"chunks>chunk"`
 	Stats       xmlStats       `xml:"stats"`
 }
 
@@ -82,6 +83,17 @@ type xmlEntity struct {
 	UsageCount int    `xml:"usage-count"`
 }
 
+type xmlChunk struct {
+	ID          string `xml:"id,attr"`
+	FilePath    string `xml:"file-path,attr"`
+	Index       int    `xml:"index,attr"`
+	ContentHash string `xml:"content-hash"`
+	StartOffset int    `xml:"start-offset"`
+	EndOffset   int    `xml:"end-offset"`
+	ChunkType   string `xml:"chunk-type,omitempty"`
+	Content     string `xml:"content"`
+}
+
 type xmlStats struct {
 	TotalFiles         int `xml:"total-files"`
 	TotalDirectories   int `xml:"total-directories"`
@@ -161,6 +173,20 @@ func (f *XMLFormatter) Format(snapshot *graph.GraphSnapshot) ([]byte, error) {
 		})
 	}
 
+	// Convert chunks with stored content
+	for _, chunk := range snapshot.Chunks {
+		xs.Chunks = append(xs.Chunks, xmlChunk{
+			ID:          chunk.ID,
+			FilePath:    chunk.FilePath,
+			Index:       chunk.Index,
+			ContentHash: chunk.ContentHash,
+			StartOffset: chunk.StartOffset,
+			EndOffset:   chunk.EndOffset,
+			ChunkType:   chunk.ChunkType,
+			Content:     chunk.Content,
+		})
+	}
+
 	// Marshal to XML
 	var buf bytes.Buffer
 	buf.WriteString(xml.Header)
@@ -79,8 +79,16 @@ func MIMEFromExtension(ext string) string {
 	return extensionToMIME(ext)
 }
 
-// DetectLanguage determines the programming language from file extension.
+// DetectLanguage determines the programming language from file extension,
+// or from the file name for extensionless build files such as Jenkinsfile.
 func DetectLanguage(path string) string {
+	switch base := filepath.Base(path); {
+	case strings.EqualFold(base, "Jenkinsfile"):
+		return "groovy"
+	case base == "Gemfile", base == "Rakefile":
+		return "ruby"
+	}
+
 	ext := strings.ToLower(filepath.Ext(path))
 	switch ext {
 	case ".go":
@@ -99,18 +107,26 @@ func DetectLanguage(path string) string {
 		return "cpp"
 	case ".rs":
 		return "rust"
-	case ".rb":
+	case ".rb", ".rake":
 		return "ruby"
-	case ".php":
+	case ".php", ".phtml":
 		return "php"
 	case ".cs":
 		return "csharp"
 	case ".swift":
 		return "swift"
+	case ".dart":
+		return "dart"
+	case ".lua":
+		return "lua"
+	case ".zig":
+		return "zig"
 	case ".kt", ".kts":
 		return "kotlin"
-	case ".scala":
+	case ".scala", ".sc":
 		return "scala"
+	case ".groovy", ".gradle":
+		return "groovy"
 	case ".sh", ".bash":
 		return "bash"
 	case ".sql":
@@ -123,40 +139,47 @@ func DetectLanguage(path string) string {
 func extensionToMIME(ext string) string {
 	mimeMap := map[string]string{
 		// Programming languages
-		".go":    "text/x-go",
-		".py":    "text/x-python",
-		".js":    "text/javascript",
-		".ts":    "text/typescript",
-		".tsx":   "text/typescript-jsx",
-		".jsx":   "text/javascript-jsx",
-		".rs":    "text/x-rust",
-		".rb":    "text/x-ruby",
-		".java":  "text/x-java",
-		".kt":    "text/x-kotlin",
-		".swift": "text/x-swift",
-		".c":     "text/x-c",
-		".cpp":   "text/x-c++",
-		".h":     "text/x-c-header",
-		".hpp":   "text/x-c++-header",
-		".cs":    "text/x-csharp",
-		".php":   "text/x-php",
-		".scala": "text/x-scala",
-		".clj":   "text/x-clojure",
-		".ex":    "text/x-elixir",
-		".exs":   "text/x-elixir",
-		".erl":   "text/x-erlang",
-		".hs":    "text/x-haskell",
-		".lua":   "text/x-lua",
-		".pl":    "text/x-perl",
-		".r":     "text/x-r",
-		".sql":   "text/x-sql",
-		".sh":    "text/x-shellscript",
-		".bash":  "text/x-shellscript",
-		".zsh":   "text/x-shellscript",
-		".fish":  "text/x-shellscript",
-		".ps1":   "text/x-powershell",
-		".vim":   "text/x-vim",
-		".zig":   "text/x-zig",
+		".go":     "text/x-go",
+		".py":     "text/x-python",
+		".js":     "text/javascript",
+		".ts":     "text/typescript",
+		".tsx":    "text/typescript-jsx",
+		".jsx":    "text/javascript-jsx",
+		".rs":     "text/x-rust",
+		".rb":     "text/x-ruby",
+		".rake":   "text/x-ruby",
+		".java":   "text/x-java",
+		".kt":     "text/x-kotlin",
+		".kts":    "text/x-kotlin",
+		".swift":  "text/x-swift",
+		".dart":   "text/x-dart",
+		".c":      "text/x-c",
+		".cpp":    "text/x-c++",
+		".h":      "text/x-c-header",
+		".hpp":    "text/x-c++-header",
+		".cs":     "text/x-csharp",
+		".php":    "text/x-php",
+		".phtml":  "text/x-php",
+		".scala":  "text/x-scala",
+		".sc":     "text/x-scala",
+		".groovy": "text/x-groovy",
+		".gradle": "text/x-groovy",
+		".clj":    "text/x-clojure",
+		".ex":     "text/x-elixir",
+		".exs":    "text/x-elixir",
+		".erl":    "text/x-erlang",
+		".hs":     "text/x-haskell",
+		".lua":    "text/x-lua",
+		".pl":     "text/x-perl",
+		".r":      "text/x-r",
+		".sql":    "text/x-sql",
+		".sh":     "text/x-shellscript",
+		".bash":   "text/x-shellscript",
+		".zsh":    "text/x-shellscript",
+		".fish":   "text/x-shellscript",
+		".ps1":    "text/x-powershell",
+		".vim":    "text/x-vim",
+		".zig":    "text/x-zig",
 
 		// Markup and config
 		".md":         "text/markdown",
@@ -78,6 +78,16 @@ func TestDetectLanguage(t *testing.T) {
 		{"/test/file.ts", "typescript"},
 		{"/test/file.rs", "rust"},
 		{"/test/file.rb", "ruby"},
+		{"/test/tasks/db.rake", "ruby"},
+		{"/test/Gemfile", "ruby"},
+		{"/test/views/index.phtml", "php"},
+		{"/test/notes.sc", "scala"},
+		{"/test/lib/main.dart", "dart"},
+		{"/test/nvim/init.lua", "lua"},
+		{"/test/src/main.zig", "zig"},
+		{"/test/build.gradle", "groovy"},
+		{"/test/Jenkinsfile", "groovy"},
+		{"/test/build.gradle.kts", "kotlin"},
 		{"/test/file.unknown", ""},
 	}
 
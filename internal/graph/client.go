@@ -2,15 +2,22 @@ package graph
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
 	"net"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/RedisGraph/redisgraph-go"
@@ -57,15 +64,30 @@ type Graph interface {
 	// This replaces the old UpsertChunk method and handles all metadata types.
 	UpsertChunkWithMetadata(ctx context.Context, chunk *ChunkNode, meta *chunkers.ChunkMetadata) error
 
+	// UpsertChunksBatch creates or updates many chunks of a file with their
+	// typed metadata in a few batched writes. metas is indexed like chunks.
+	UpsertChunksBatch(ctx context.Context, chunks []*ChunkNode, metas []*chunkers.ChunkMetadata) error
+
+	// CommitTransaction commits the writes recorded in tx, which was created
+	// with WithTransaction, so they apply together.
+	CommitTransaction(ctx context.Context, tx *Transaction) error
+
 	// UpsertChunkEmbedding creates or updates an embedding for a chunk.
 	UpsertChunkEmbedding(ctx context.Context, chunkID string, emb *ChunkEmbeddingNode) error
 
 	// DeleteChunkEmbeddings deletes embeddings for a chunk.
 	DeleteChunkEmbeddings(ctx context.Context, chunkID string, provider, model string) error
 
+	// DeleteFileEmbeddings deletes embeddings for all chunks of a file.
+	DeleteFileEmbeddings(ctx context.Context, filePath string, provider, model string) error
+
 	// DeleteChunks removes all chunks for a file.
 	DeleteChunks(ctx context.Context, filePath string) error
 
+	// PruneOrphans deletes chunks without a file and metadata or embedding
+	// nodes without a chunk, returning the number deleted per label.
+	PruneOrphans(ctx context.Context) (PruneStats, error)
+
 	// SetFileTags sets the tags for a file.
 	SetFileTags(ctx context.Context, path string, tags []string) error
 
@@ -75,24 +97,84 @@ type Graph interface {
 	// SetFileEntities sets the entities mentioned in a file.
 	SetFileEntities(ctx context.Context, path string, entities []Entity) error
 
+	// SetFileRelations sets the entity relations extracted from a file.
+	SetFileRelations(ctx context.Context, path string, relations []EntityRelation) error
+
 	// SetFileReferences sets the references from a file.
 	SetFileReferences(ctx context.Context, path string, refs []Reference) error
 
+	// SetChunkSectionReferences links a chunk to the chunks its in-document anchors
+	// resolve to. Anchors without a matching section are stored on the chunk.
+	SetChunkSectionReferences(ctx context.Context, chunkID string, targetIDs []string, unmatchedAnchors []string) error
+
+	// LinkChunks replaces the NEXT_CHUNK edges of the file at path with a chain
+	// through chunkIDs, which are in reading order.
+	LinkChunks(ctx context.Context, path string, chunkIDs []string) error
+
+	// GetAdjacentChunks returns the chunk with chunkID together with up to
+	// before preceding and after following chunks of its file, in reading order.
+	GetAdjacentChunks(ctx context.Context, chunkID string, before, after int) ([]ChunkNode, error)
+
+	// GetChunkByID returns a chunk and its metadata, or nil if it does not exist.
+	GetChunkByID(ctx context.Context, id string) (*ChunkNode, *chunkers.ChunkMetadata, error)
+
+	// ListChunksForFile returns the chunks of the file at filePath ordered by index.
+	ListChunksForFile(ctx context.Context, filePath string) ([]ChunkNode, error)
+
+	// GetBacklinks returns the files that reference or import the file at path.
+	GetBacklinks(ctx context.Context, path string) ([]FileNode, error)
+
+	// GetReferences returns the references and imports from the file at path.
+	GetReferences(ctx context.Context, path string) ([]Reference, error)
+
 	// Query executes a raw Cypher query.
 	Query(ctx context.Context, cypher string) (*QueryResult, error)
 
 	// HasEmbedding checks if an embedding exists for the given content hash and version.
 	HasEmbedding(ctx context.Context, contentHash string, version int) (bool, error)
 
+	// GetEmbedding returns a stored embedding for the given content hash,
+	// provider, model and version, or nil if none exists.
+	GetEmbedding(ctx context.Context, contentHash, provider, model string, version int) ([]float32, error)
+
+	// CountFilesByIngestReason returns the number of files per reason they
+	// were last ingested.
+	CountFilesByIngestReason(ctx context.Context) (map[string]int, error)
+
+	// GetDirectoryTree returns the directory tree rooted at rootPath, nested up
+	// to maxDepth levels below the root. A maxDepth of zero or less is unbounded.
+	GetDirectoryTree(ctx context.Context, rootPath string, maxDepth int) (*DirectoryTree, error)
+
+	// TopTags returns the n most used tags in descending usage order.
+	TopTags(ctx context.Context, n int) ([]TagNode, error)
+
+	// TopTopics returns the n most used topics in descending usage order.
+	TopTopics(ctx context.Context, n int) ([]TopicNode, error)
+
+	// TopEntities returns the n most mentioned entities in descending usage
+	// order. An empty entityType includes entities of every type.
+	TopEntities(ctx context.Context, n int, entityType string) ([]EntityNode, error)
+
+	// GetRelatedFiles returns the k files most related to the file at path,
+	// scored by shared tags, topics, entities and imports plus chunk-embedding
+	// similarity.
+	GetRelatedFiles(ctx context.Context, path string, k int) ([]RelatedFile, error)
+
 	// ExportSnapshot exports a complete snapshot of the graph.
 	ExportSnapshot(ctx context.Context) (*GraphSnapshot, error)
 
+	// ExportSnapshotSince exports a delta snapshot of nodes updated at or after since.
+	ExportSnapshotSince(ctx context.Context, since time.Time) (*GraphSnapshot, error)
+
 	// GetFileWithRelations retrieves a file with all its related data.
 	GetFileWithRelations(ctx context.Context, path string) (*FileWithRelations, error)
 
 	// SearchSimilarChunks finds chunks similar to the given embedding using k-NN search.
 	SearchSimilarChunks(ctx context.Context, embedding []float32, k int) ([]ChunkSearchHit, error)
 
+	// SearchSimilarChunksFiltered finds similar chunks matching filter.
+	SearchSimilarChunksFiltered(ctx context.Context, embedding []float32, k int, filter ChunkFilter) ([]ChunkSearchHit, error)
+
 	// IsConnected returns true if connected to the database.
 	IsConnected() bool
 
@@ -111,6 +193,33 @@ type Config struct {
 	EmbeddingDimension int  // Vector embedding dimensions for index creation
 	WriteQueueSize     int  // Write queue buffer size
 	SkipSchemaInit     bool // Skip schema initialization (for read-only clients)
+
+	// ReadReplicaAddrs lists host:port addresses of read replicas. Read-only
+	// queries are spread across them round-robin; writes stay on the primary.
+	ReadReplicaAddrs []string
+
+	// RelatedWeights scores GetRelatedFiles signals. The zero value uses
+	// DefaultRelatedFileWeights.
+	RelatedWeights RelatedFileWeights
+
+	// StoreChunkContent stores chunk text on chunk nodes, so search hits can
+	// be shown without re-reading the source file.
+	StoreChunkContent bool
+
+	// DistanceMetric is the similarity function of the chunk vector index.
+	// It should match how embeddings are normalized. Empty uses DistanceCosine.
+	DistanceMetric DistanceMetric
+
+	// UseTLS connects to the primary and read replicas over TLS.
+	UseTLS bool
+
+	// TLSConfig customizes TLS connections. Nil uses the system roots and
+	// verifies the server name against the host.
+	TLSConfig *tls.Config
+
+	// TLSCAFileEnv names an environment variable holding the path of a PEM
+	// file with extra CA certificates to trust, such as a managed Redis CA.
+	TLSCAFileEnv string
 }
 
 // DefaultConfig returns sensible defaults.
@@ -124,9 +233,19 @@ func DefaultConfig() Config {
 		RetryDelay:         time.Second,
 		EmbeddingDimension: 1536, // OpenAI text-embedding-3-small default
 		WriteQueueSize:     1000,
+		RelatedWeights:     DefaultRelatedFileWeights(),
+		DistanceMetric:     DistanceCosine,
+		TLSCAFileEnv:       "MEMORIZER_GRAPH_TLS_CA_FILE",
 	}
 }
 
+// ErrTLSHandshake indicates the server did not complete a TLS handshake,
+// usually because it does not accept TLS or presents an untrusted certificate.
+var ErrTLSHandshake = errors.New("TLS handshake failed")
+
+// ErrEmbeddingDimensionMismatch indicates an embedding does not match the vector index dimension.
+var ErrEmbeddingDimensionMismatch = errors.New("embedding dimension mismatch")
+
 // FalkorDBGraph implements Graph using FalkorDB/RedisGraph.
 type FalkorDBGraph struct {
 	mu        sync.RWMutex
@@ -134,14 +253,31 @@ type FalkorDBGraph struct {
 	config    Config
 	logger    *slog.Logger
 	conn      redis.Conn
-	graph     redisgraph.Graph
+	graph     *redisgraph.Graph
+	primary   graphQuerier
 	connected bool
 
+	// transactor commits transactions on the primary connection
+	transactor graphTransactor
+
+	// Read replicas for read-only queries, chosen round-robin
+	replicas    []*replicaConn
+	replicaNext atomic.Uint64
+
 	// Write queue for graceful degradation
 	writeQueue chan writeOp
 	wg         sync.WaitGroup
 	stopChan   chan struct{}
 
+	// lostChan is closed when the connection drops, stopping the write queue
+	// processor without draining so queued writes wait for the reconnect.
+	lostChan chan struct{}
+
+	// reconnecting is set while a dropped connection is being re-established;
+	// writes are still queued meanwhile. reconnectCancel stops the attempt.
+	reconnecting    bool
+	reconnectCancel context.CancelFunc
+
 	errChan chan error
 
 	// bus for publishing connection events (optional).
@@ -151,10 +287,36 @@ type FalkorDBGraph struct {
 	lastQueueFullEmit time.Time
 }
 
+// graphQuerier executes Cypher against a single connection.
+type graphQuerier interface {
+	Query(q string) (*redisgraph.QueryResult, error)
+}
+
+// replicaConn is a read replica connection. Connections are not safe for
+// concurrent use, so each replica serializes its own queries.
+type replicaConn struct {
+	mu      sync.Mutex
+	addr    string
+	conn    redis.Conn
+	querier graphQuerier
+}
+
 // writeOp represents a queued write operation.
 type writeOp struct {
 	query  string
 	result chan error
+
+	// queries, when set, are committed together as one transaction instead of query.
+	queries []string
+
+	// onResult, when set, receives the result of a successful query.
+	onResult func(*redisgraph.QueryResult)
+
+	// done reports the outcome of an async write to its WriteBatch, if any.
+	done func(error)
+
+	// flush marks a no-op barrier that completes once all earlier writes have executed.
+	flush bool
 }
 
 // Option configures the FalkorDB graph client.
@@ -181,12 +343,20 @@ func WithBus(bus events.Bus) Option {
 	}
 }
 
+// WithReadReplica adds read replica addresses (host:port) for read-only queries.
+func WithReadReplica(addrs ...string) Option {
+	return func(g *FalkorDBGraph) {
+		g.config.ReadReplicaAddrs = append(g.config.ReadReplicaAddrs, addrs...)
+	}
+}
+
 // NewFalkorDBGraph creates a new FalkorDB graph client.
 func NewFalkorDBGraph(opts ...Option) *FalkorDBGraph {
 	g := &FalkorDBGraph{
 		config:   DefaultConfig(),
 		logger:   slog.Default(),
 		stopChan: make(chan struct{}),
+		lostChan: make(chan struct{}),
 		errChan:  make(chan error, 1),
 	}
 
@@ -207,14 +377,72 @@ func (g *FalkorDBGraph) Name() string {
 	return "graph"
 }
 
-// Start initializes the graph connection.
+// Start initializes the graph connection, retrying with backoff while the
+// server is unreachable. GraphConnected is published once connected.
 func (g *FalkorDBGraph) Start(ctx context.Context) error {
+	g.mu.RLock()
+	connected := g.connected
+	g.mu.RUnlock()
+	if connected {
+		return nil
+	}
+
+	dialOpts, err := g.dialOptions()
+	if err != nil {
+		return err
+	}
+
+	// Connect to FalkorDB (Redis protocol)
+	addr := fmt.Sprintf("%s:%d", g.config.Host, g.config.Port)
+
+	// Dial, connect replicas and initialize the schema without holding the
+	// lock: IsConnected must not block on retries, and a schema query that
+	// fails fatally on the new connection must not take the lock itself.
+	conn, err := g.dial(ctx, addr, dialOpts)
+	if err != nil {
+		return fmt.Errorf("failed to connect to FalkorDB at %s; %w", addr, err)
+	}
+	graph := redisgraph.GraphNew(g.config.GraphName, conn)
+
+	// Replicas are best effort; reads fall back to the primary without them
+	var replicas []*replicaConn
+	for _, replicaAddr := range g.config.ReadReplicaAddrs {
+		replica, err := redis.Dial("tcp", replicaAddr, dialOpts...)
+		if err != nil {
+			g.logger.Warn("failed to connect to read replica", "addr", replicaAddr, "error", err)
+			continue
+		}
+		replicaGraph := redisgraph.GraphNew(g.config.GraphName, replica)
+		replicas = append(replicas, &replicaConn{addr: replicaAddr, conn: replica, querier: &replicaGraph})
+	}
+
+	abort := func() {
+		conn.Close()
+		for _, replica := range replicas {
+			replica.conn.Close()
+		}
+	}
+
+	// Create schema indexes and constraints (skip for read-only clients)
+	if !g.config.SkipSchemaInit {
+		if err := g.initSchema(ctx, &graph); err != nil {
+			abort()
+			return fmt.Errorf("failed to initialize schema on %s; %w", addr, err)
+		}
+	}
+
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
 	if g.connected {
+		abort()
 		return nil
 	}
+	// A reconnect cancelled by Stop while dialing must not reconnect
+	if err := ctx.Err(); err != nil {
+		abort()
+		return err
+	}
 
 	// Drain any stale errors from previous connection failures
 	for {
@@ -227,45 +455,28 @@ func (g *FalkorDBGraph) Start(ctx context.Context) error {
 	}
 drained:
 
-	// Get password from environment
-	password := os.Getenv(g.config.PasswordEnv)
-
-	// Connect to FalkorDB (Redis protocol)
-	addr := fmt.Sprintf("%s:%d", g.config.Host, g.config.Port)
-
-	var dialOpts []redis.DialOption
-	if password != "" {
-		dialOpts = append(dialOpts, redis.DialPassword(password))
-	}
-
-	conn, err := redis.Dial("tcp", addr, dialOpts...)
-	if err != nil {
-		return fmt.Errorf("failed to connect to FalkorDB at %s; %w", addr, err)
-	}
-
 	g.conn = conn
-	g.graph = redisgraph.GraphNew(g.config.GraphName, conn)
+	g.graph = &graph
+	g.primary = g.graph
+	g.transactor = &redisTransactor{conn: conn, graphName: g.config.GraphName}
+	g.replicas = replicas
 	g.connected = true
+	g.reconnecting = false
 
-	// Recreate stopChan for write queue (may have been closed on previous Stop/fatal)
+	// Recreate stopChan and lostChan for write queue (may have been closed on previous Stop/fatal)
 	g.stopChan = make(chan struct{})
-
-	// Create schema indexes and constraints (skip for read-only clients)
-	if !g.config.SkipSchemaInit {
-		if err := g.initSchema(ctx); err != nil {
-			g.logger.Warn("failed to create schema constraints", "error", err)
-		}
-	}
+	g.lostChan = make(chan struct{})
 
 	// Start write queue processor
 	g.wg.Add(1)
-	go g.processWriteQueue()
+	go g.processWriteQueue(g.stopChan, g.lostChan)
 
 	endpoint := fmt.Sprintf("%s:%d", g.config.Host, g.config.Port)
 	g.logger.Info("connected to FalkorDB",
 		"host", g.config.Host,
 		"port", g.config.Port,
-		"graph", g.config.GraphName)
+		"graph", g.config.GraphName,
+		"read_replicas", len(g.replicas))
 
 	// Publish connected event
 	if g.bus != nil {
@@ -275,6 +486,107 @@ drained:
 	return nil
 }
 
+// dial connects to addr, retrying up to MaxRetries times with exponential
+// backoff from RetryDelay, so a graph that is still starting does not fail
+// startup. It stops early when ctx is cancelled.
+func (g *FalkorDBGraph) dial(ctx context.Context, addr string, opts []redis.DialOption) (redis.Conn, error) {
+	var err error
+	for i := 0; i <= g.config.MaxRetries; i++ {
+		var conn redis.Conn
+		conn, err = redis.DialContext(ctx, "tcp", addr, opts...)
+		if err == nil {
+			return conn, nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		// A rejected handshake will not succeed on retry
+		if g.config.UseTLS && isTLSHandshakeError(err) {
+			return nil, fmt.Errorf("%w; check that %s accepts TLS and its certificate is trusted; %w", ErrTLSHandshake, addr, err)
+		}
+
+		if i < g.config.MaxRetries {
+			delay := g.config.RetryDelay * time.Duration(1<<i)
+			g.logger.Warn("failed to connect to FalkorDB; retrying",
+				"addr", addr,
+				"attempt", i+1,
+				"delay", delay,
+				"error", err)
+
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err()
+			case <-timer.C:
+			}
+		}
+	}
+	return nil, err
+}
+
+// dialOptions returns the connection options: the password read from
+// PasswordEnv and, when UseTLS is set, the TLS settings.
+func (g *FalkorDBGraph) dialOptions() ([]redis.DialOption, error) {
+	var opts []redis.DialOption
+	if password := os.Getenv(g.config.PasswordEnv); password != "" {
+		opts = append(opts, redis.DialPassword(password))
+	}
+
+	if g.config.UseTLS {
+		tlsConfig, err := g.tlsConfig()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, redis.DialUseTLS(true), redis.DialTLSConfig(tlsConfig))
+	}
+	return opts, nil
+}
+
+// tlsConfig returns Config.TLSConfig, or a default, trusting the CA
+// certificates in the file named by TLSCAFileEnv in addition to its roots.
+func (g *FalkorDBGraph) tlsConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if g.config.TLSConfig != nil {
+		tlsConfig = g.config.TLSConfig.Clone()
+	}
+
+	caFile := ""
+	if g.config.TLSCAFileEnv != "" {
+		caFile = os.Getenv(g.config.TLSCAFileEnv)
+	}
+	if caFile == "" {
+		return tlsConfig, nil
+	}
+
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read TLS CA file; %w", err)
+	}
+	roots := tlsConfig.RootCAs
+	if roots == nil {
+		if roots, err = x509.SystemCertPool(); err != nil {
+			roots = x509.NewCertPool()
+		}
+	} else {
+		roots = roots.Clone()
+	}
+	if !roots.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in TLS CA file %s", caFile)
+	}
+	tlsConfig.RootCAs = roots
+	return tlsConfig, nil
+}
+
+// isTLSHandshakeError reports whether err comes from a failed TLS handshake:
+// a non-TLS reply, an alert from the server, or an untrusted certificate.
+func isTLSHandshakeError(err error) bool {
+	var recordErr tls.RecordHeaderError
+	var alertErr tls.AlertError
+	var verifyErr *tls.CertificateVerificationError
+	return errors.As(err, &recordErr) || errors.As(err, &alertErr) || errors.As(err, &verifyErr)
+}
+
 // Errors returns fatal connection errors.
 func (g *FalkorDBGraph) Errors() <-chan error {
 	return g.errChan
@@ -285,6 +597,13 @@ func (g *FalkorDBGraph) Stop(ctx context.Context) error {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
+	// Abandon any reconnect; writes still queued for it are not executed
+	if g.reconnectCancel != nil {
+		g.reconnectCancel()
+		g.reconnectCancel = nil
+	}
+	g.reconnecting = false
+
 	if !g.connected {
 		return nil
 	}
@@ -310,6 +629,7 @@ func (g *FalkorDBGraph) Stop(ctx context.Context) error {
 	if g.conn != nil {
 		_ = g.conn.Close()
 	}
+	g.closeReplicas()
 
 	g.connected = false
 	endpoint := fmt.Sprintf("%s:%d", g.config.Host, g.config.Port)
@@ -324,21 +644,27 @@ func (g *FalkorDBGraph) Stop(ctx context.Context) error {
 }
 
 // signalFatal sends a fatal error to errChan without blocking.
-// It also marks the connection as disconnected so that Start() will reconnect.
+// It marks the connection as disconnected and starts reconnecting in the
+// background; writes queued meanwhile run once the connection is restored.
 func (g *FalkorDBGraph) signalFatal(err error) {
-	// Mark as disconnected so Start() will reconnect
 	g.mu.Lock()
+	if !g.connected {
+		// Already handled by an earlier failure on the same connection
+		g.mu.Unlock()
+		return
+	}
 	g.connected = false
 	if g.conn != nil {
 		_ = g.conn.Close()
 		g.conn = nil
 	}
-	// Close stopChan to stop the old processWriteQueue goroutine
-	// Use recover in case it's already closed
-	func() {
-		defer func() { recover() }()
-		close(g.stopChan)
-	}()
+	g.closeReplicas()
+	// Stop the processWriteQueue goroutine without draining the queue
+	close(g.lostChan)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	g.reconnecting = true
+	g.reconnectCancel = cancel
 	g.mu.Unlock()
 
 	select {
@@ -351,6 +677,37 @@ func (g *FalkorDBGraph) signalFatal(err error) {
 		endpoint := fmt.Sprintf("%s:%d", g.config.Host, g.config.Port)
 		g.bus.Publish(context.Background(), events.NewGraphDisconnected(endpoint, err))
 	}
+
+	go g.reconnect(ctx, cancel)
+}
+
+// maxReconnectDelay caps the wait between reconnect rounds.
+const maxReconnectDelay = 30 * time.Second
+
+// reconnect re-establishes a dropped connection until it succeeds or ctx is
+// cancelled by Stop. Each round is a Start call, which redials with backoff,
+// re-runs schema initialization and publishes GraphConnected.
+func (g *FalkorDBGraph) reconnect(ctx context.Context, cancel context.CancelFunc) {
+	defer cancel()
+
+	delay := g.config.RetryDelay
+	if delay <= 0 {
+		delay = time.Second
+	}
+	for {
+		err := g.Start(ctx)
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+
+		g.logger.Warn("failed to reconnect to FalkorDB; retrying", "delay", delay, "error", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxReconnectDelay)
+	}
 }
 
 // IsConnected returns true if connected to the database.
@@ -360,6 +717,14 @@ func (g *FalkorDBGraph) IsConnected() bool {
 	return g.connected
 }
 
+// acceptsWrites reports whether writes can be queued: while connected, and
+// while reconnecting, when they wait in the queue for the connection.
+func (g *FalkorDBGraph) acceptsWrites() bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.connected || g.reconnecting
+}
+
 // CollectMetrics implements metrics.MetricsProvider.
 func (g *FalkorDBGraph) CollectMetrics(ctx context.Context) error {
 	if !g.IsConnected() {
@@ -377,13 +742,24 @@ func (g *FalkorDBGraph) CollectMetrics(ctx context.Context) error {
 	return nil
 }
 
-// processWriteQueue handles queued write operations.
-func (g *FalkorDBGraph) processWriteQueue() {
+// processWriteQueue handles queued write operations until stop, draining the
+// queue first, or until lost, leaving queued writes for the next connection.
+func (g *FalkorDBGraph) processWriteQueue(stop, lost <-chan struct{}) {
 	defer g.wg.Done()
 
 	for {
+		// Check lost first so no queued write runs on a dropped connection
 		select {
-		case <-g.stopChan:
+		case <-lost:
+			return
+		default:
+		}
+
+		select {
+		case <-lost:
+			// Leave queued writes for the processor started on reconnect
+			return
+		case <-stop:
 			// Drain remaining operations
 			for {
 				select {
@@ -404,26 +780,81 @@ func (g *FalkorDBGraph) query(cypher string) (*redisgraph.QueryResult, error) {
 	g.queryMu.Lock()
 	defer g.queryMu.Unlock()
 
-	result, err := g.graph.Query(cypher)
+	result, err := g.primary.Query(cypher)
 	if err != nil && isFatalGraphError(err) {
 		g.signalFatal(err)
 	}
 	return result, err
 }
 
+// readQuery executes a read-only Cypher query on the next read replica,
+// falling back to the primary when no replica is available or it fails.
+func (g *FalkorDBGraph) readQuery(cypher string) (*redisgraph.QueryResult, error) {
+	g.mu.RLock()
+	replicas := g.replicas
+	g.mu.RUnlock()
+
+	if len(replicas) == 0 {
+		return g.query(cypher)
+	}
+
+	replica := replicas[(g.replicaNext.Add(1)-1)%uint64(len(replicas))]
+	replica.mu.Lock()
+	result, err := replica.querier.Query(cypher)
+	replica.mu.Unlock()
+	if err == nil {
+		return result, nil
+	}
+
+	g.logger.Debug("read replica query failed; retrying on primary", "addr", replica.addr, "error", err)
+	return g.query(cypher)
+}
+
+// closeReplicas closes read replica connections. Callers hold g.mu.
+func (g *FalkorDBGraph) closeReplicas() {
+	for _, replica := range g.replicas {
+		replica.mu.Lock()
+		if replica.conn != nil {
+			_ = replica.conn.Close()
+		}
+		replica.mu.Unlock()
+	}
+	g.replicas = nil
+}
+
+// writeClauseRegex matches Cypher clauses that may modify the graph. CALL is
+// included because procedures may write.
+var writeClauseRegex = regexp.MustCompile(`(?i)\b(CREATE|MERGE|SET|DELETE|REMOVE|DROP|CALL|FOREACH|LOAD)\b`)
+
+// isReadOnlyCypher reports whether a raw query is safe to route to a read
+// replica. It errs towards the primary: any write keyword, even inside a
+// string literal, marks the query as a write.
+func isReadOnlyCypher(cypher string) bool {
+	return !writeClauseRegex.MatchString(cypher)
+}
+
 // executeWrite executes a write operation with retry.
 func (g *FalkorDBGraph) executeWrite(op writeOp) {
+	if op.flush {
+		op.result <- nil
+		return
+	}
+
 	var err error
 	for i := 0; i <= g.config.MaxRetries; i++ {
-		_, err = g.query(op.query)
+		err = g.execute(op)
 		if err == nil {
 			if op.result != nil {
 				op.result <- nil
 			}
+			if op.done != nil {
+				op.done(nil)
+			}
 			return
 		}
 
-		if isFatalGraphError(err) {
+		// Retrying a failed transaction would re-run the queries that succeeded
+		if isFatalGraphError(err) || errors.Is(err, ErrTransactionFailed) {
 			break
 		}
 
@@ -435,30 +866,132 @@ func (g *FalkorDBGraph) executeWrite(op writeOp) {
 	if op.result != nil {
 		op.result <- err
 	}
+	if op.done != nil {
+		op.done(err)
+	}
 	g.logger.Error("write operation failed after retries", "error", err)
 }
 
-// queueWrite queues a write operation for async execution.
-func (g *FalkorDBGraph) queueWrite(query string) error {
+// execute runs a write operation's query, or its queries as one transaction.
+func (g *FalkorDBGraph) execute(op writeOp) error {
+	if op.queries == nil {
+		result, err := g.query(op.query)
+		if err == nil && op.onResult != nil {
+			op.onResult(result)
+		}
+		return err
+	}
+
+	g.queryMu.Lock()
+	defer g.queryMu.Unlock()
+
+	err := g.transactor.Exec(op.queries)
+	if err != nil && isFatalGraphError(err) {
+		g.signalFatal(err)
+	}
+	return err
+}
+
+// queueWrite queues a write operation for async execution. When ctx carries a
+// WriteBatch, the write's eventual outcome is reported to it. When ctx carries
+// a Transaction, the write is recorded in it instead.
+func (g *FalkorDBGraph) queueWrite(ctx context.Context, query string) error {
+	if tx := TransactionFromContext(ctx); tx != nil {
+		return tx.add(query)
+	}
+
+	done := WriteBatchFromContext(ctx).Track()
 	select {
-	case g.writeQueue <- writeOp{query: query}:
+	case g.writeQueue <- writeOp{query: query, done: done}:
 		return nil
 	default:
+		done(nil) // the caller sees the error directly
 		g.emitWriteQueueFull()
 		return fmt.Errorf("write queue full")
 	}
 }
 
-// queueWriteSync queues a write operation and waits for completion.
-func (g *FalkorDBGraph) queueWriteSync(query string) error {
+// queueWriteSync queues a write operation and waits for completion or context cancellation.
+// On cancellation the write stays queued and may still complete asynchronously.
+// When ctx carries a Transaction, the write is recorded in it instead.
+func (g *FalkorDBGraph) queueWriteSync(ctx context.Context, query string) error {
+	if tx := TransactionFromContext(ctx); tx != nil {
+		return tx.add(query)
+	}
+	return g.submitSync(ctx, writeOp{query: query})
+}
+
+// queueWriteSyncResult queues a write like queueWriteSync, bypassing any
+// Transaction in ctx, and returns its result.
+func (g *FalkorDBGraph) queueWriteSyncResult(ctx context.Context, query string) (*redisgraph.QueryResult, error) {
+	var result *redisgraph.QueryResult
+	if err := g.submitSync(ctx, writeOp{query: query, onResult: func(r *redisgraph.QueryResult) { result = r }}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CommitTransaction executes the writes recorded in tx as one MULTI/EXEC
+// transaction, in order with other queued writes, and waits for the result.
+// Writes in a transaction that fails to reach the graph are all discarded; a
+// query failing inside the transaction returns ErrTransactionFailed.
+func (g *FalkorDBGraph) CommitTransaction(ctx context.Context, tx *Transaction) error {
+	if !g.acceptsWrites() {
+		return fmt.Errorf("not connected to graph database")
+	}
+
+	queries, err := tx.finish()
+	if err != nil {
+		return err
+	}
+	if len(queries) == 0 {
+		return nil
+	}
+	return g.submitSync(ctx, writeOp{queries: queries})
+}
+
+// submitSync queues op and waits for completion or context cancellation.
+func (g *FalkorDBGraph) submitSync(ctx context.Context, op writeOp) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	result := make(chan error, 1)
+	op.result = result
 	select {
-	case g.writeQueue <- writeOp{query: query, result: result}:
-		return <-result
+	case g.writeQueue <- op:
 	default:
 		g.emitWriteQueueFull()
 		return fmt.Errorf("write queue full")
 	}
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Flush blocks until all writes queued before the call have executed or ctx expires.
+func (g *FalkorDBGraph) Flush(ctx context.Context) error {
+	if !g.acceptsWrites() {
+		return fmt.Errorf("not connected to graph database")
+	}
+
+	result := make(chan error, 1)
+	select {
+	case g.writeQueue <- writeOp{flush: true, result: result}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 // emitWriteQueueFull publishes write queue full event with rate limiting (1/sec).
@@ -482,91 +1015,107 @@ func (g *FalkorDBGraph) emitWriteQueueFull() {
 
 // UpsertFile creates or updates a file node and its directory relationship.
 func (g *FalkorDBGraph) UpsertFile(ctx context.Context, file *FileNode) error {
-	if !g.IsConnected() {
+	if !g.acceptsWrites() {
 		return fmt.Errorf("not connected to graph database")
 	}
 
-	query := fmt.Sprintf(`
-		MERGE (f:File {path: '%s'})
-		SET f.name = '%s',
-			f.extension = '%s',
-			f.mime_type = '%s',
-			f.language = '%s',
-			f.ingest_kind = '%s',
-			f.ingest_mode = '%s',
-			f.ingest_reason = '%s',
-			f.size = %d,
-			f.mod_time = %d,
-			f.content_hash = '%s',
-			f.metadata_hash = '%s',
-			f.summary = '%s',
-			f.complexity = %d,
-			f.analyzed_at = %d,
-			f.analysis_version = %d,
-			f.updated_at = %d
-	`, escapeString(file.Path),
-		escapeString(file.Name),
-		escapeString(file.Extension),
-		escapeString(file.MIMEType),
-		escapeString(file.Language),
-		escapeString(file.IngestKind),
-		escapeString(file.IngestMode),
-		escapeString(file.IngestReason),
-		file.Size,
-		file.ModTime.Unix(),
-		escapeString(file.ContentHash),
-		escapeString(file.MetadataHash),
-		escapeString(file.Summary),
-		file.Complexity,
-		file.AnalyzedAt.Unix(),
-		file.AnalysisVersion,
-		time.Now().Unix())
-
-	if err := g.queueWrite(query); err != nil {
+	now := time.Now().Unix()
+	if err := g.queueWrite(ctx, upsertFileQuery(file, now)); err != nil {
 		return err
 	}
 
 	// Create CONTAINS relationship from parent directory to file
-	parentDir := filepath.Dir(file.Path)
-	parentName := filepath.Base(parentDir)
-
-	relQuery := fmt.Sprintf(`
-		MERGE (d:Directory {path: '%s'})
-		ON CREATE SET d.name = '%s', d.is_remembered = false, d.file_count = 0, d.created_at = %d
-		SET d.updated_at = %d
+	return g.queueWrite(ctx, fileDirectoryQuery(file.Path, now))
+}
+
+// upsertFileQuery builds the query creating or updating a file node.
+func upsertFileQuery(file *FileNode, now int64) string {
+	return parameterized(`
+		MERGE (f:File {path: $path})
+		SET f.name = $name,
+			f.extension = $extension,
+			f.mime_type = $mime_type,
+			f.language = $language,
+			f.ingest_kind = $ingest_kind,
+			f.ingest_mode = $ingest_mode,
+			f.ingest_reason = $ingest_reason,
+			f.change_reason = $change_reason,
+			f.size = $size,
+			f.mod_time = $mod_time,
+			f.content_hash = $content_hash,
+			f.metadata_hash = $metadata_hash,
+			f.summary = $summary,
+			f.complexity = $complexity,
+			f.analyzed_at = $analyzed_at,
+			f.analysis_version = $analysis_version,
+			f.updated_at = $updated_at
+	`, map[string]any{
+		"path":             file.Path,
+		"name":             file.Name,
+		"extension":        file.Extension,
+		"mime_type":        file.MIMEType,
+		"language":         file.Language,
+		"ingest_kind":      file.IngestKind,
+		"ingest_mode":      file.IngestMode,
+		"ingest_reason":    file.IngestReason,
+		"change_reason":    file.ChangeReason,
+		"size":             file.Size,
+		"mod_time":         file.ModTime.Unix(),
+		"content_hash":     file.ContentHash,
+		"metadata_hash":    file.MetadataHash,
+		"summary":          file.Summary,
+		"complexity":       file.Complexity,
+		"analyzed_at":      file.AnalyzedAt.Unix(),
+		"analysis_version": file.AnalysisVersion,
+		"updated_at":       now,
+	})
+}
+
+// fileDirectoryQuery builds the query linking a file to its parent directory,
+// creating the directory node if needed.
+func fileDirectoryQuery(path string, now int64) string {
+	parentDir := filepath.Dir(path)
+	return parameterized(`
+		MERGE (d:Directory {path: $dir_path})
+		ON CREATE SET d.name = $dir_name, d.is_remembered = false, d.file_count = 0, d.created_at = $now
+		SET d.updated_at = $now
 		WITH d
-		MATCH (f:File {path: '%s'})
+		MATCH (f:File {path: $path})
 		MERGE (d)-[:CONTAINS]->(f)
-	`, escapeString(parentDir),
-		escapeString(parentName),
-		time.Now().Unix(),
-		time.Now().Unix(),
-		escapeString(file.Path))
-
-	return g.queueWrite(relQuery)
+	`, map[string]any{
+		"dir_path": parentDir,
+		"dir_name": filepath.Base(parentDir),
+		"path":     path,
+		"now":      now,
+	})
 }
 
 // DeleteFile removes a file node and its relationships.
 func (g *FalkorDBGraph) DeleteFile(ctx context.Context, path string) error {
-	if !g.IsConnected() {
+	if !g.acceptsWrites() {
 		return fmt.Errorf("not connected to graph database")
 	}
 
 	// Delete chunks first
-	chunkQuery := fmt.Sprintf(`
-		MATCH (c:Chunk {file_path: '%s'})
+	chunkQuery := parameterized(`
+		MATCH (c:Chunk {file_path: $path})
 		DETACH DELETE c
-	`, escapeString(path))
-	if err := g.queueWriteSync(chunkQuery); err != nil {
+	`, map[string]any{"path": path})
+	if err := g.queueWriteSync(ctx, chunkQuery); err != nil {
+		return err
+	}
+
+	// Delete entity relations extracted from the file
+	if err := g.queueWriteSync(ctx, deleteFileRelationsQuery(path)); err != nil {
 		return err
 	}
 
 	// Delete file
-	query := fmt.Sprintf(`
-		MATCH (f:File {path: '%s'})
+	query := parameterized(`
+		MATCH (f:File {path: $path})
 		DETACH DELETE f
-	`, escapeString(path))
-	return g.queueWriteSync(query)
+	`, map[string]any{"path": path})
+	return g.queueWriteSync(ctx, query)
 }
 
 // GetFile retrieves a file node by path.
@@ -575,15 +1124,16 @@ func (g *FalkorDBGraph) GetFile(ctx context.Context, path string) (*FileNode, er
 		return nil, fmt.Errorf("not connected to graph database")
 	}
 
-	query := fmt.Sprintf(`
-		MATCH (f:File {path: '%s'})
+	query := parameterized(`
+		MATCH (f:File {path: $path})
 		RETURN f.path, f.name, f.extension, f.mime_type, f.language,
 			   f.ingest_kind, f.ingest_mode, f.ingest_reason,
 			   f.size, f.mod_time, f.content_hash, f.metadata_hash,
-			   f.summary, f.complexity, f.analyzed_at, f.analysis_version
-	`, escapeString(path))
+			   f.summary, f.complexity, f.analyzed_at, f.analysis_version,
+			   f.change_reason
+	`, map[string]any{"path": path})
 
-	result, err := g.query(query)
+	result, err := g.readQuery(query)
 	if err != nil {
 		return nil, fmt.Errorf("query failed; %w", err)
 	}
@@ -602,383 +1152,449 @@ func (g *FalkorDBGraph) GetFile(ctx context.Context, path string) (*FileNode, er
 
 // UpsertDirectory creates or updates a directory node.
 func (g *FalkorDBGraph) UpsertDirectory(ctx context.Context, dir *DirectoryNode) error {
-	if !g.IsConnected() {
+	if !g.acceptsWrites() {
 		return fmt.Errorf("not connected to graph database")
 	}
 
-	query := fmt.Sprintf(`
-		MERGE (d:Directory {path: '%s'})
-		SET d.name = '%s',
-			d.is_remembered = %t,
-			d.file_count = %d,
-			d.updated_at = %d
-	`, escapeString(dir.Path),
-		escapeString(dir.Name),
-		dir.IsRemembered,
-		dir.FileCount,
-		time.Now().Unix())
+	query := parameterized(`
+		MERGE (d:Directory {path: $path})
+		SET d.name = $name,
+			d.is_remembered = $is_remembered,
+			d.file_count = $file_count,
+			d.updated_at = $updated_at
+	`, map[string]any{
+		"path":          dir.Path,
+		"name":          dir.Name,
+		"is_remembered": dir.IsRemembered,
+		"file_count":    dir.FileCount,
+		"updated_at":    time.Now().Unix(),
+	})
 
-	return g.queueWrite(query)
+	return g.queueWrite(ctx, query)
 }
 
 // DeleteDirectory removes a directory node and its relationships.
 func (g *FalkorDBGraph) DeleteDirectory(ctx context.Context, path string) error {
-	if !g.IsConnected() {
+	if !g.acceptsWrites() {
 		return fmt.Errorf("not connected to graph database")
 	}
 
-	query := fmt.Sprintf(`
-		MATCH (d:Directory {path: '%s'})
+	query := parameterized(`
+		MATCH (d:Directory {path: $path})
 		DETACH DELETE d
-	`, escapeString(path))
-	return g.queueWriteSync(query)
+	`, map[string]any{"path": path})
+	return g.queueWriteSync(ctx, query)
 }
 
 // DeleteFilesUnderPath removes all file nodes under a parent path.
 // Uses prefix matching with trailing slash to avoid false positives.
 func (g *FalkorDBGraph) DeleteFilesUnderPath(ctx context.Context, parentPath string) error {
-	if !g.IsConnected() {
+	if !g.acceptsWrites() {
 		return fmt.Errorf("not connected to graph database")
 	}
 
 	// Delete chunks for all files under path first
-	chunkQuery := fmt.Sprintf(`
+	chunkQuery := parameterized(`
 		MATCH (c:Chunk)
-		WHERE c.file_path STARTS WITH '%s/'
+		WHERE c.file_path STARTS WITH $prefix
 		DETACH DELETE c
-	`, escapeString(parentPath))
-	if err := g.queueWriteSync(chunkQuery); err != nil {
+	`, map[string]any{"prefix": parentPath + "/"})
+	if err := g.queueWriteSync(ctx, chunkQuery); err != nil {
+		return err
+	}
+
+	// Delete entity relations extracted from files under path
+	if err := g.queueWriteSync(ctx, deleteRelationsUnderPathQuery(parentPath+"/")); err != nil {
 		return err
 	}
 
 	// Delete file nodes
-	query := fmt.Sprintf(`
+	query := parameterized(`
 		MATCH (f:File)
-		WHERE f.path STARTS WITH '%s/'
+		WHERE f.path STARTS WITH $prefix
 		DETACH DELETE f
-	`, escapeString(parentPath))
-	return g.queueWriteSync(query)
+	`, map[string]any{"prefix": parentPath + "/"})
+	return g.queueWriteSync(ctx, query)
 }
 
 // DeleteDirectoriesUnderPath removes all directory nodes under a parent path.
 // Uses prefix matching with trailing slash to avoid false positives.
 func (g *FalkorDBGraph) DeleteDirectoriesUnderPath(ctx context.Context, parentPath string) error {
-	if !g.IsConnected() {
+	if !g.acceptsWrites() {
 		return fmt.Errorf("not connected to graph database")
 	}
 
-	query := fmt.Sprintf(`
+	query := parameterized(`
 		MATCH (d:Directory)
-		WHERE d.path STARTS WITH '%s/'
+		WHERE d.path STARTS WITH $prefix
 		DETACH DELETE d
-	`, escapeString(parentPath))
-	return g.queueWriteSync(query)
+	`, map[string]any{"prefix": parentPath + "/"})
+	return g.queueWriteSync(ctx, query)
 }
 
 // UpsertChunkWithMetadata creates or updates a chunk node with its typed metadata.
 // This handles all metadata types (Code, Document, Notebook, Build, Infra, Schema, Structured, SQL, Log).
 func (g *FalkorDBGraph) UpsertChunkWithMetadata(ctx context.Context, chunk *ChunkNode, meta *chunkers.ChunkMetadata) error {
-	if !g.IsConnected() {
+	if !g.acceptsWrites() {
 		return fmt.Errorf("not connected to graph database")
 	}
 
 	// Create core chunk node
-	query := fmt.Sprintf(`
-		MERGE (c:Chunk {id: '%s'})
-		SET c.file_path = '%s',
-			c.index = %d,
-			c.content_hash = '%s',
-			c.start_offset = %d,
-			c.end_offset = %d,
-			c.chunk_type = '%s',
-			c.token_count = %d,
-			c.summary = '%s',
-			c.updated_at = %d
-	`, escapeString(chunk.ID),
-		escapeString(chunk.FilePath),
-		chunk.Index,
-		escapeString(chunk.ContentHash),
-		chunk.StartOffset,
-		chunk.EndOffset,
-		escapeString(chunk.ChunkType),
-		chunk.TokenCount,
-		escapeString(chunk.Summary),
-		time.Now().Unix())
-
-	if err := g.queueWrite(query); err != nil {
+	if err := g.queueWrite(ctx, upsertChunkQuery(chunk, time.Now().Unix(), g.config.StoreChunkContent)); err != nil {
 		return err
 	}
 
 	// Create relationship to file
-	relQuery := fmt.Sprintf(`
-		MATCH (f:File {path: '%s'})
-		MATCH (c:Chunk {id: '%s'})
+	relQuery := parameterized(`
+		MATCH (f:File {path: $file_path})
+		MATCH (c:Chunk {id: $id})
 		MERGE (f)-[:HAS_CHUNK]->(c)
-	`, escapeString(chunk.FilePath), escapeString(chunk.ID))
+	`, map[string]any{"file_path": chunk.FilePath, "id": chunk.ID})
 
-	if err := g.queueWrite(relQuery); err != nil {
+	if err := g.queueWrite(ctx, relQuery); err != nil {
 		return err
 	}
 
 	// Create metadata node based on type
-	if meta == nil {
+	node, ok := chunkMetaNodeFor(meta)
+	if !ok {
 		return nil
 	}
-
-	switch {
-	case meta.Code != nil:
-		return g.upsertCodeMeta(ctx, chunk.ID, meta.Code)
-	case meta.Document != nil:
-		return g.upsertDocumentMeta(ctx, chunk.ID, meta.Document)
-	case meta.Notebook != nil:
-		return g.upsertNotebookMeta(ctx, chunk.ID, meta.Notebook)
-	case meta.Build != nil:
-		return g.upsertBuildMeta(ctx, chunk.ID, meta.Build)
-	case meta.Infra != nil:
-		return g.upsertInfraMeta(ctx, chunk.ID, meta.Infra)
-	case meta.Schema != nil:
-		return g.upsertSchemaMeta(ctx, chunk.ID, meta.Schema)
-	case meta.Structured != nil:
-		return g.upsertStructuredMeta(ctx, chunk.ID, meta.Structured)
-	case meta.SQL != nil:
-		return g.upsertSQLMeta(ctx, chunk.ID, meta.SQL)
-	case meta.Log != nil:
-		return g.upsertLogMeta(ctx, chunk.ID, meta.Log)
-	}
-
-	return nil
+	return g.queueWrite(ctx, chunkMetaQuery(chunk.ID, node.rel, node.label, node.props))
 }
 
-// upsertCodeMeta creates or updates code metadata for a chunk.
-func (g *FalkorDBGraph) upsertCodeMeta(ctx context.Context, chunkID string, meta *chunkers.CodeMetadata) error {
-	query := fmt.Sprintf(`
-		MATCH (c:Chunk {id: '%s'})
-		MERGE (c)-[:HAS_CODE_META]->(m:CodeMeta)
-		SET m.language = '%s',
-			m.function_name = '%s',
-			m.class_name = '%s',
-			m.signature = '%s',
-			m.return_type = '%s',
-			m.visibility = '%s',
-			m.docstring = '%s',
-			m.namespace = '%s',
-			m.parent_class = '%s',
-			m.is_async = %t,
-			m.is_static = %t,
-			m.is_exported = %t,
-			m.is_generator = %t,
-			m.is_getter = %t,
-			m.is_setter = %t,
-			m.is_constructor = %t,
-			m.line_start = %d,
-			m.line_end = %d,
-			m.parameters = %s,
-			m.decorators = %s,
-			m.implements = %s
-	`, escapeString(chunkID),
-		escapeString(meta.Language),
-		escapeString(meta.FunctionName),
-		escapeString(meta.ClassName),
-		escapeString(meta.Signature),
-		escapeString(meta.ReturnType),
-		escapeString(meta.Visibility),
-		escapeString(meta.Docstring),
-		escapeString(meta.Namespace),
-		escapeString(meta.ParentClass),
-		meta.IsAsync,
-		meta.IsStatic,
-		meta.IsExported,
-		meta.IsGenerator,
-		meta.IsGetter,
-		meta.IsSetter,
-		meta.IsConstructor,
-		meta.LineStart,
-		meta.LineEnd,
-		formatStringArray(meta.Parameters),
-		formatStringArray(meta.Decorators),
-		formatStringArray(meta.Implements))
-
-	return g.queueWrite(query)
-}
-
-// upsertDocumentMeta creates or updates document metadata for a chunk.
-func (g *FalkorDBGraph) upsertDocumentMeta(ctx context.Context, chunkID string, meta *chunkers.DocumentMetadata) error {
-	query := fmt.Sprintf(`
-		MATCH (c:Chunk {id: '%s'})
-		MERGE (c)-[:HAS_DOC_META]->(m:DocumentMeta)
-		SET m.heading = '%s',
-			m.heading_level = %d,
-			m.section_path = '%s',
-			m.section_number = '%s',
-			m.author = '%s',
-			m.page_number = %d,
-			m.page_count = %d,
-			m.word_count = %d,
-			m.has_code_block = %t,
-			m.code_language = '%s',
-			m.list_depth = %d,
-			m.is_table = %t,
-			m.is_footnote = %t,
-			m.extraction_quality = '%s'
-	`, escapeString(chunkID),
-		escapeString(meta.Heading),
-		meta.HeadingLevel,
-		escapeString(meta.SectionPath),
-		escapeString(meta.SectionNumber),
-		escapeString(meta.Author),
-		meta.PageNumber,
-		meta.PageCount,
-		meta.WordCount,
-		meta.HasCodeBlock,
-		escapeString(meta.CodeLanguage),
-		meta.ListDepth,
-		meta.IsTable,
-		meta.IsFootnote,
-		escapeString(meta.ExtractionQuality))
-
-	return g.queueWrite(query)
-}
-
-// upsertNotebookMeta creates or updates notebook metadata for a chunk.
-func (g *FalkorDBGraph) upsertNotebookMeta(ctx context.Context, chunkID string, meta *chunkers.NotebookMetadata) error {
-	query := fmt.Sprintf(`
-		MATCH (c:Chunk {id: '%s'})
-		MERGE (c)-[:HAS_NOTEBOOK_META]->(m:NotebookMeta)
-		SET m.cell_type = '%s',
-			m.cell_index = %d,
-			m.execution_count = %d,
-			m.has_output = %t,
-			m.output_types = %s,
-			m.kernel = '%s'
-	`, escapeString(chunkID),
-		escapeString(meta.CellType),
-		meta.CellIndex,
-		meta.ExecutionCount,
-		meta.HasOutput,
-		formatStringArray(meta.OutputTypes),
-		escapeString(meta.Kernel))
-
-	return g.queueWrite(query)
-}
+// chunkBatchSize bounds the chunks written per UNWIND query, keeping the
+// parameter header of a single query to a manageable size.
+const chunkBatchSize = 200
 
-// upsertBuildMeta creates or updates build metadata for a chunk.
-func (g *FalkorDBGraph) upsertBuildMeta(ctx context.Context, chunkID string, meta *chunkers.BuildMetadata) error {
-	query := fmt.Sprintf(`
-		MATCH (c:Chunk {id: '%s'})
-		MERGE (c)-[:HAS_BUILD_META]->(m:BuildMeta)
-		SET m.target_name = '%s',
-			m.dependencies = %s,
-			m.stage_name = '%s',
-			m.base_image = '%s'
-	`, escapeString(chunkID),
-		escapeString(meta.TargetName),
-		formatStringArray(meta.Dependencies),
-		escapeString(meta.StageName),
-		escapeString(meta.BaseImage))
-
-	return g.queueWrite(query)
-}
-
-// upsertInfraMeta creates or updates infrastructure metadata for a chunk.
-func (g *FalkorDBGraph) upsertInfraMeta(ctx context.Context, chunkID string, meta *chunkers.InfraMetadata) error {
-	query := fmt.Sprintf(`
-		MATCH (c:Chunk {id: '%s'})
-		MERGE (c)-[:HAS_INFRA_META]->(m:InfraMeta)
-		SET m.resource_type = '%s',
-			m.resource_name = '%s',
-			m.block_type = '%s'
-	`, escapeString(chunkID),
-		escapeString(meta.ResourceType),
-		escapeString(meta.ResourceName),
-		escapeString(meta.BlockType))
+// UpsertChunksBatch creates or updates chunk nodes, their file relationships
+// and their typed metadata using UNWIND queries, issuing one write per
+// chunkBatchSize chunks plus one per metadata type present instead of up to
+// three writes per chunk. metas is indexed like chunks; missing or nil entries
+// create no metadata node.
+func (g *FalkorDBGraph) UpsertChunksBatch(ctx context.Context, chunks []*ChunkNode, metas []*chunkers.ChunkMetadata) error {
+	if !g.acceptsWrites() {
+		return fmt.Errorf("not connected to graph database")
+	}
 
-	return g.queueWrite(query)
+	now := time.Now().Unix()
+	for start := 0; start < len(chunks); start += chunkBatchSize {
+		end := min(start+chunkBatchSize, len(chunks))
+		var batchMetas []*chunkers.ChunkMetadata
+		if start < len(metas) {
+			batchMetas = metas[start:min(end, len(metas))]
+		}
+		for _, query := range upsertChunksBatchQueries(chunks[start:end], batchMetas, now, g.config.StoreChunkContent) {
+			if err := g.queueWrite(ctx, query); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
 
-// upsertSchemaMeta creates or updates schema metadata for a chunk.
-func (g *FalkorDBGraph) upsertSchemaMeta(ctx context.Context, chunkID string, meta *chunkers.SchemaMetadata) error {
-	query := fmt.Sprintf(`
-		MATCH (c:Chunk {id: '%s'})
-		MERGE (c)-[:HAS_SCHEMA_META]->(m:SchemaMeta)
-		SET m.message_name = '%s',
-			m.service_name = '%s',
-			m.rpc_name = '%s',
-			m.type_name = '%s',
-			m.type_kind = '%s'
-	`, escapeString(chunkID),
-		escapeString(meta.MessageName),
-		escapeString(meta.ServiceName),
-		escapeString(meta.RPCName),
-		escapeString(meta.TypeName),
-		escapeString(meta.TypeKind))
-
-	return g.queueWrite(query)
-}
+// upsertChunkQuery builds the query creating or updating a chunk node. The
+// chunk text is stored only when storeContent is set.
+func upsertChunkQuery(chunk *ChunkNode, now int64, storeContent bool) string {
+	return parameterized(`
+		MERGE (c:Chunk {id: $id})
+		SET c.file_path = $file_path,
+			c.index = $index,
+			c.content_hash = $content_hash,
+			c.start_offset = $start_offset,
+			c.end_offset = $end_offset,
+			c.chunk_type = $chunk_type,
+			c.token_count = $token_count,
+			c.boundary_confidence = $boundary_confidence,
+			c.line_start = $line_start,
+			c.line_end = $line_end,
+			c.summary = $summary,
+			c.content = $content,
+			c.updated_at = $updated_at
+	`, chunkParams(chunk, now, storeContent))
+}
+
+// chunkParams returns the chunk node properties, keyed by parameter name.
+// Content is null, which removes the property, unless storeContent is set and
+// the chunk has text.
+func chunkParams(chunk *ChunkNode, now int64, storeContent bool) map[string]any {
+	var content any
+	if storeContent && chunk.Content != "" {
+		content = chunk.Content
+	}
+
+	return map[string]any{
+		"id":                  chunk.ID,
+		"file_path":           chunk.FilePath,
+		"index":               chunk.Index,
+		"content_hash":        chunk.ContentHash,
+		"start_offset":        chunk.StartOffset,
+		"end_offset":          chunk.EndOffset,
+		"chunk_type":          chunk.ChunkType,
+		"token_count":         chunk.TokenCount,
+		"boundary_confidence": chunk.BoundaryConfidence,
+		"line_start":          chunk.LineStart,
+		"line_end":            chunk.LineEnd,
+		"summary":             chunk.Summary,
+		"content":             content,
+		"updated_at":          now,
+	}
+}
+
+// upsertChunksBatchQueries builds the UNWIND queries for a chunk batch: one
+// creating the chunk nodes and their HAS_CHUNK relationships, then one per
+// metadata type present.
+func upsertChunksBatchQueries(chunks []*ChunkNode, metas []*chunkers.ChunkMetadata, now int64, storeContent bool) []string {
+	if len(chunks) == 0 {
+		return nil
+	}
 
-// upsertStructuredMeta creates or updates structured data metadata for a chunk.
-func (g *FalkorDBGraph) upsertStructuredMeta(ctx context.Context, chunkID string, meta *chunkers.StructuredMetadata) error {
-	query := fmt.Sprintf(`
-		MATCH (c:Chunk {id: '%s'})
-		MERGE (c)-[:HAS_STRUCT_META]->(m:StructuredMeta)
-		SET m.schema_path = '%s',
-			m.element_name = '%s',
-			m.element_path = '%s',
-			m.table_path = '%s',
-			m.record_index = %d,
-			m.record_count = %d,
-			m.key_names = %s
-	`, escapeString(chunkID),
-		escapeString(meta.SchemaPath),
-		escapeString(meta.ElementName),
-		escapeString(meta.ElementPath),
-		escapeString(meta.TablePath),
-		meta.RecordIndex,
-		meta.RecordCount,
-		formatStringArray(meta.KeyNames))
+	rows := make([]map[string]any, len(chunks))
+	for i, chunk := range chunks {
+		rows[i] = chunkParams(chunk, now, storeContent)
+	}
+	queries := []string{parameterized(`
+		UNWIND $chunks AS row
+		MERGE (c:Chunk {id: row.id})
+		SET c.file_path = row.file_path,
+			c.index = row.index,
+			c.content_hash = row.content_hash,
+			c.start_offset = row.start_offset,
+			c.end_offset = row.end_offset,
+			c.chunk_type = row.chunk_type,
+			c.token_count = row.token_count,
+			c.boundary_confidence = row.boundary_confidence,
+			c.line_start = row.line_start,
+			c.line_end = row.line_end,
+			c.summary = row.summary,
+			c.content = row.content,
+			c.updated_at = row.updated_at
+		WITH c, row
+		MATCH (f:File {path: row.file_path})
+		MERGE (f)-[:HAS_CHUNK]->(c)
+	`, map[string]any{"chunks": rows})}
+
+	// Group metadata rows by node type, keeping the first-seen type order
+	var order []string
+	groups := make(map[string]*chunkMetaNode)
+	metaRows := make(map[string][]map[string]any)
+	for i, meta := range metas {
+		node, ok := chunkMetaNodeFor(meta)
+		if !ok {
+			continue
+		}
+		if _, seen := groups[node.label]; !seen {
+			order = append(order, node.label)
+			groups[node.label] = &node
+		}
+		row := maps.Clone(node.props)
+		row["chunk_id"] = chunks[i].ID
+		metaRows[node.label] = append(metaRows[node.label], row)
+	}
+	for _, label := range order {
+		node := groups[label]
+		queries = append(queries, chunkMetaBatchQuery(node.rel, node.label, node.props, metaRows[label]))
+	}
 
-	return g.queueWrite(query)
+	return queries
 }
 
-// upsertSQLMeta creates or updates SQL metadata for a chunk.
-func (g *FalkorDBGraph) upsertSQLMeta(ctx context.Context, chunkID string, meta *chunkers.SQLMetadata) error {
-	query := fmt.Sprintf(`
-		MATCH (c:Chunk {id: '%s'})
-		MERGE (c)-[:HAS_SQL_META]->(m:SQLMeta)
-		SET m.statement_type = '%s',
-			m.object_type = '%s',
-			m.table_name = '%s',
-			m.procedure_name = '%s',
-			m.sql_dialect = '%s'
-	`, escapeString(chunkID),
-		escapeString(meta.StatementType),
-		escapeString(meta.ObjectType),
-		escapeString(meta.TableName),
-		escapeString(meta.ProcedureName),
-		escapeString(meta.SQLDialect))
-
-	return g.queueWrite(query)
+// chunkMetaNode describes the typed metadata node attached to a chunk.
+type chunkMetaNode struct {
+	rel   string
+	label string
+	props map[string]any
 }
 
-// upsertLogMeta creates or updates log metadata for a chunk.
-func (g *FalkorDBGraph) upsertLogMeta(ctx context.Context, chunkID string, meta *chunkers.LogMetadata) error {
-	query := fmt.Sprintf(`
-		MATCH (c:Chunk {id: '%s'})
-		MERGE (c)-[:HAS_LOG_META]->(m:LogMeta)
-		SET m.time_start = %d,
-			m.time_end = %d,
-			m.log_level = '%s',
-			m.log_format = '%s',
-			m.error_count = %d,
-			m.source_app = '%s'
-	`, escapeString(chunkID),
-		meta.TimeStart.Unix(),
-		meta.TimeEnd.Unix(),
-		escapeString(meta.LogLevel),
-		escapeString(meta.LogFormat),
-		meta.ErrorCount,
-		escapeString(meta.SourceApp))
-
-	return g.queueWrite(query)
+// chunkMetaNodeFor returns the metadata node for meta, or false when meta
+// carries no typed metadata.
+func chunkMetaNodeFor(meta *chunkers.ChunkMetadata) (chunkMetaNode, bool) {
+	switch {
+	case meta == nil:
+		return chunkMetaNode{}, false
+	case meta.Code != nil:
+		return chunkMetaNode{rel: RelHasCodeMeta, label: LabelCodeMeta, props: codeMetaProps(meta.Code)}, true
+	case meta.Document != nil:
+		return chunkMetaNode{rel: RelHasDocMeta, label: LabelDocumentMeta, props: documentMetaProps(meta.Document)}, true
+	case meta.Notebook != nil:
+		return chunkMetaNode{rel: RelHasNotebookMeta, label: LabelNotebookMeta, props: notebookMetaProps(meta.Notebook)}, true
+	case meta.Build != nil:
+		return chunkMetaNode{rel: RelHasBuildMeta, label: LabelBuildMeta, props: buildMetaProps(meta.Build)}, true
+	case meta.Infra != nil:
+		return chunkMetaNode{rel: RelHasInfraMeta, label: LabelInfraMeta, props: infraMetaProps(meta.Infra)}, true
+	case meta.Schema != nil:
+		return chunkMetaNode{rel: RelHasSchemaMeta, label: LabelSchemaMeta, props: schemaMetaProps(meta.Schema)}, true
+	case meta.Structured != nil:
+		return chunkMetaNode{rel: RelHasStructMeta, label: LabelStructuredMeta, props: structuredMetaProps(meta.Structured)}, true
+	case meta.SQL != nil:
+		return chunkMetaNode{rel: RelHasSQLMeta, label: LabelSQLMeta, props: sqlMetaProps(meta.SQL)}, true
+	case meta.Log != nil:
+		return chunkMetaNode{rel: RelHasLogMeta, label: LabelLogMeta, props: logMetaProps(meta.Log)}, true
+	}
+	return chunkMetaNode{}, false
+}
+
+// chunkMetaQuery builds the query merging a chunk's metadata node, reached
+// over rel, and setting props on it. Property names double as parameter names.
+func chunkMetaQuery(chunkID, rel, label string, props map[string]any) string {
+	params := maps.Clone(props)
+	params["chunk_id"] = chunkID
+
+	return parameterized(fmt.Sprintf(`
+		MATCH (c:Chunk {id: $chunk_id})
+		MERGE (c)-[:%s]->(m:%s)
+		SET %s
+	`, rel, label, metaAssignments(props, "$")), params)
+}
+
+// chunkMetaBatchQuery builds the UNWIND query merging metadata nodes of one
+// type for many chunks. Each row holds the node's props plus its chunk_id.
+func chunkMetaBatchQuery(rel, label string, props map[string]any, rows []map[string]any) string {
+	return parameterized(fmt.Sprintf(`
+		UNWIND $rows AS row
+		MATCH (c:Chunk {id: row.chunk_id})
+		MERGE (c)-[:%s]->(m:%s)
+		SET %s
+	`, rel, label, metaAssignments(props, "row.")), map[string]any{"rows": rows})
+}
+
+// metaAssignments returns the SET assignments copying props onto m, reading
+// each value from prefix followed by the property name.
+func metaAssignments(props map[string]any, prefix string) string {
+	names := slices.Sorted(maps.Keys(props))
+	sets := make([]string, len(names))
+	for i, name := range names {
+		sets[i] = fmt.Sprintf("m.%s = %s%s", name, prefix, name)
+	}
+	return strings.Join(sets, ",\n\t\t\t")
+}
+
+// codeMetaProps returns the properties of a chunk's code metadata node.
+func codeMetaProps(meta *chunkers.CodeMetadata) map[string]any {
+	return map[string]any{
+		"language":       meta.Language,
+		"function_name":  meta.FunctionName,
+		"class_name":     meta.ClassName,
+		"signature":      meta.Signature,
+		"return_type":    meta.ReturnType,
+		"visibility":     meta.Visibility,
+		"docstring":      meta.Docstring,
+		"namespace":      meta.Namespace,
+		"parent_class":   meta.ParentClass,
+		"is_async":       meta.IsAsync,
+		"is_static":      meta.IsStatic,
+		"is_exported":    meta.IsExported,
+		"is_generator":   meta.IsGenerator,
+		"is_getter":      meta.IsGetter,
+		"is_setter":      meta.IsSetter,
+		"is_constructor": meta.IsConstructor,
+		"is_test":        meta.IsTest,
+		"line_start":     meta.LineStart,
+		"line_end":       meta.LineEnd,
+		"parameters":     meta.Parameters,
+		"decorators":     meta.Decorators,
+		"implements":     meta.Implements,
+		"raises":         meta.Raises,
+	}
+}
+
+// documentMetaProps returns the properties of a chunk's document metadata node.
+func documentMetaProps(meta *chunkers.DocumentMetadata) map[string]any {
+	return map[string]any{
+		"heading":            meta.Heading,
+		"heading_level":      meta.HeadingLevel,
+		"section_path":       meta.SectionPath,
+		"section_number":     meta.SectionNumber,
+		"title":              meta.Title,
+		"author":             meta.Author,
+		"page_number":        meta.PageNumber,
+		"page_count":         meta.PageCount,
+		"word_count":         meta.WordCount,
+		"has_code_block":     meta.HasCodeBlock,
+		"code_language":      meta.CodeLanguage,
+		"list_depth":         meta.ListDepth,
+		"is_table":           meta.IsTable,
+		"is_footnote":        meta.IsFootnote,
+		"extraction_quality": meta.ExtractionQuality,
+	}
+}
+
+// notebookMetaProps returns the properties of a chunk's notebook metadata node.
+func notebookMetaProps(meta *chunkers.NotebookMetadata) map[string]any {
+	return map[string]any{
+		"cell_type":       meta.CellType,
+		"cell_index":      meta.CellIndex,
+		"execution_count": meta.ExecutionCount,
+		"has_output":      meta.HasOutput,
+		"output_types":    meta.OutputTypes,
+		"kernel":          meta.Kernel,
+	}
+}
+
+// buildMetaProps returns the properties of a chunk's build metadata node.
+func buildMetaProps(meta *chunkers.BuildMetadata) map[string]any {
+	return map[string]any{
+		"target_name":  meta.TargetName,
+		"dependencies": meta.Dependencies,
+		"stage_name":   meta.StageName,
+		"base_image":   meta.BaseImage,
+	}
+}
+
+// infraMetaProps returns the properties of a chunk's infrastructure metadata node.
+func infraMetaProps(meta *chunkers.InfraMetadata) map[string]any {
+	return map[string]any{
+		"resource_type": meta.ResourceType,
+		"resource_name": meta.ResourceName,
+		"block_type":    meta.BlockType,
+	}
+}
+
+// schemaMetaProps returns the properties of a chunk's schema metadata node.
+func schemaMetaProps(meta *chunkers.SchemaMetadata) map[string]any {
+	return map[string]any{
+		"message_name": meta.MessageName,
+		"service_name": meta.ServiceName,
+		"rpc_name":     meta.RPCName,
+		"type_name":    meta.TypeName,
+		"type_kind":    meta.TypeKind,
+	}
+}
+
+// structuredMetaProps returns the properties of a chunk's structured data metadata node.
+func structuredMetaProps(meta *chunkers.StructuredMetadata) map[string]any {
+	return map[string]any{
+		"schema_path":  meta.SchemaPath,
+		"element_name": meta.ElementName,
+		"element_path": meta.ElementPath,
+		"table_path":   meta.TablePath,
+		"record_index": meta.RecordIndex,
+		"record_count": meta.RecordCount,
+		"key_names":    meta.KeyNames,
+	}
+}
+
+// sqlMetaProps returns the properties of a chunk's SQL metadata node.
+func sqlMetaProps(meta *chunkers.SQLMetadata) map[string]any {
+	return map[string]any{
+		"statement_type": meta.StatementType,
+		"object_type":    meta.ObjectType,
+		"table_name":     meta.TableName,
+		"procedure_name": meta.ProcedureName,
+		"sql_dialect":    meta.SQLDialect,
+	}
+}
+
+// logMetaProps returns the properties of a chunk's log metadata node.
+func logMetaProps(meta *chunkers.LogMetadata) map[string]any {
+	return map[string]any{
+		"time_start":  meta.TimeStart.Unix(),
+		"time_end":    meta.TimeEnd.Unix(),
+		"log_level":   meta.LogLevel,
+		"log_format":  meta.LogFormat,
+		"error_count": meta.ErrorCount,
+		"source_app":  meta.SourceApp,
+	}
 }
 
 // formatStringArray formats a string slice as a Cypher array literal.
@@ -994,54 +1610,116 @@ func formatStringArray(arr []string) string {
 }
 
 // UpsertChunkEmbedding creates or updates an embedding for a chunk.
+// Embeddings whose dimensions do not match the vector index are rejected.
 func (g *FalkorDBGraph) UpsertChunkEmbedding(ctx context.Context, chunkID string, emb *ChunkEmbeddingNode) error {
-	if !g.IsConnected() {
+	if err := g.validateEmbeddingDimension(emb); err != nil {
+		return err
+	}
+
+	if !g.acceptsWrites() {
 		return fmt.Errorf("not connected to graph database")
 	}
 
-	embeddingStr := formatEmbeddingArray(emb.Embedding)
+	return g.queueWrite(ctx, upsertChunkEmbeddingQuery(chunkID, emb, time.Now()))
+}
 
-	query := fmt.Sprintf(`
+// embeddingDimension returns the vector index dimension.
+func (g *FalkorDBGraph) embeddingDimension() int {
+	if g.config.EmbeddingDimension == 0 {
+		return 1536 // Default OpenAI text-embedding-3-small
+	}
+	return g.config.EmbeddingDimension
+}
+
+// distanceMetric returns the vector index similarity function.
+func (g *FalkorDBGraph) distanceMetric() DistanceMetric {
+	if g.config.DistanceMetric == "" {
+		return DistanceCosine
+	}
+	return g.config.DistanceMetric
+}
+
+// validateEmbeddingDimension checks that emb matches the vector index dimension.
+// A mismatched vector would be stored but never match similarity searches.
+func (g *FalkorDBGraph) validateEmbeddingDimension(emb *ChunkEmbeddingNode) error {
+	want := g.embeddingDimension()
+	if emb.Dimensions != want {
+		return fmt.Errorf("%w; embedding from %s/%s has %d dimensions, index expects %d",
+			ErrEmbeddingDimensionMismatch, emb.Provider, emb.Model, emb.Dimensions, want)
+	}
+	if len(emb.Embedding) != want {
+		return fmt.Errorf("%w; embedding from %s/%s has %d values, index expects %d",
+			ErrEmbeddingDimensionMismatch, emb.Provider, emb.Model, len(emb.Embedding), want)
+	}
+	return nil
+}
+
+// upsertChunkEmbeddingQuery builds the query storing emb on a ChunkEmbedding node
+// linked from the chunk. The version is matched by HasEmbedding.
+func upsertChunkEmbeddingQuery(chunkID string, emb *ChunkEmbeddingNode, now time.Time) string {
+	return fmt.Sprintf(`
 		MATCH (c:Chunk {id: '%s'})
 		MERGE (c)-[:HAS_EMBEDDING]->(e:ChunkEmbedding {provider: '%s', model: '%s'})
 		SET e.dimensions = %d,
 			e.embedding = %s,
+			e.version = %d,
 			e.created_at = %d
 	`, escapeString(chunkID),
 		escapeString(emb.Provider),
 		escapeString(emb.Model),
 		emb.Dimensions,
-		embeddingStr,
-		time.Now().Unix())
-
-	return g.queueWrite(query)
+		formatEmbeddingArray(emb.Embedding),
+		emb.Version,
+		now.Unix())
 }
 
 // DeleteChunkEmbeddings deletes embeddings for a chunk, optionally filtered by provider/model.
 func (g *FalkorDBGraph) DeleteChunkEmbeddings(ctx context.Context, chunkID string, provider, model string) error {
-	if !g.IsConnected() {
+	if !g.acceptsWrites() {
 		return fmt.Errorf("not connected to graph database")
 	}
 
-	var query string
-	if provider != "" && model != "" {
-		query = fmt.Sprintf(`
-			MATCH (c:Chunk {id: '%s'})-[:HAS_EMBEDDING]->(e:ChunkEmbedding {provider: '%s', model: '%s'})
-			DETACH DELETE e
-		`, escapeString(chunkID), escapeString(provider), escapeString(model))
-	} else if provider != "" {
-		query = fmt.Sprintf(`
-			MATCH (c:Chunk {id: '%s'})-[:HAS_EMBEDDING]->(e:ChunkEmbedding {provider: '%s'})
-			DETACH DELETE e
-		`, escapeString(chunkID), escapeString(provider))
-	} else {
-		query = fmt.Sprintf(`
-			MATCH (c:Chunk {id: '%s'})-[:HAS_EMBEDDING]->(e:ChunkEmbedding)
-			DETACH DELETE e
-		`, escapeString(chunkID))
+	query := fmt.Sprintf(`
+		MATCH (c:Chunk {id: '%s'})-[:HAS_EMBEDDING]->(e:ChunkEmbedding%s)
+		DETACH DELETE e
+	`, escapeString(chunkID), embeddingFilter(provider, model))
+
+	return g.queueWrite(ctx, query)
+}
+
+// DeleteFileEmbeddings deletes embeddings for all chunks of a file in a single
+// query, optionally filtered by provider/model. Chunks are left in place so
+// they can be re-embedded.
+func (g *FalkorDBGraph) DeleteFileEmbeddings(ctx context.Context, filePath string, provider, model string) error {
+	if !g.acceptsWrites() {
+		return fmt.Errorf("not connected to graph database")
 	}
 
-	return g.queueWrite(query)
+	return g.queueWrite(ctx, deleteFileEmbeddingsQuery(filePath, provider, model))
+}
+
+// deleteFileEmbeddingsQuery builds the query deleting embeddings for every chunk of a file.
+func deleteFileEmbeddingsQuery(filePath, provider, model string) string {
+	return fmt.Sprintf(`
+		MATCH (c:Chunk {file_path: '%s'})-[:HAS_EMBEDDING]->(e:ChunkEmbedding%s)
+		DETACH DELETE e
+	`, escapeString(filePath), embeddingFilter(provider, model))
+}
+
+// embeddingFilter returns a ChunkEmbedding property map for the non-empty
+// provider and model, or an empty string to match all embeddings.
+func embeddingFilter(provider, model string) string {
+	var props []string
+	if provider != "" {
+		props = append(props, fmt.Sprintf("provider: '%s'", escapeString(provider)))
+	}
+	if model != "" {
+		props = append(props, fmt.Sprintf("model: '%s'", escapeString(model)))
+	}
+	if len(props) == 0 {
+		return ""
+	}
+	return " {" + strings.Join(props, ", ") + "}"
 }
 
 // formatEmbeddingArray formats a float32 slice as a Cypher array literal.
@@ -1064,270 +1742,1401 @@ func formatEmbeddingArray(embedding []float32) string {
 
 // DeleteChunks removes all chunks for a file, including their metadata and embedding nodes.
 func (g *FalkorDBGraph) DeleteChunks(ctx context.Context, filePath string) error {
-	if !g.IsConnected() {
+	if !g.acceptsWrites() {
 		return fmt.Errorf("not connected to graph database")
 	}
 
 	// Delete metadata nodes first
-	metaQuery := fmt.Sprintf(`
-		MATCH (c:Chunk {file_path: '%s'})-[:HAS_CODE_META|HAS_DOC_META|HAS_NOTEBOOK_META|HAS_BUILD_META|HAS_INFRA_META|HAS_SCHEMA_META|HAS_STRUCT_META|HAS_SQL_META|HAS_LOG_META|HAS_EMBEDDING]->(m)
+	metaQuery := parameterized(`
+		MATCH (c:Chunk {file_path: $file_path})-[:HAS_CODE_META|HAS_DOC_META|HAS_NOTEBOOK_META|HAS_BUILD_META|HAS_INFRA_META|HAS_SCHEMA_META|HAS_STRUCT_META|HAS_SQL_META|HAS_LOG_META|HAS_EMBEDDING]->(m)
 		DETACH DELETE m
-	`, escapeString(filePath))
-	if err := g.queueWriteSync(metaQuery); err != nil {
+	`, map[string]any{"file_path": filePath})
+	if err := g.queueWriteSync(ctx, metaQuery); err != nil {
 		return err
 	}
 
 	// Delete chunks
-	query := fmt.Sprintf(`
-		MATCH (c:Chunk {file_path: '%s'})
+	query := parameterized(`
+		MATCH (c:Chunk {file_path: $file_path})
+		DETACH DELETE c
+	`, map[string]any{"file_path": filePath})
+	return g.queueWriteSync(ctx, query)
+}
+
+// orphanChunkGracePeriod keeps PruneOrphans away from chunks updated this
+// recently, whose HAS_CHUNK relationship may still be queued behind them.
+const orphanChunkGracePeriod = 5 * time.Minute
+
+// PruneOrphans deletes chunks no file links to with HAS_CHUNK, then metadata
+// and embedding nodes no chunk links to, such as those left behind by an
+// interrupted DeleteChunks. Chunks updated within orphanChunkGracePeriod are
+// kept. It returns the number of nodes deleted per label.
+func (g *FalkorDBGraph) PruneOrphans(ctx context.Context) (PruneStats, error) {
+	stats := PruneStats{Deleted: make(map[string]int)}
+	if !g.acceptsWrites() {
+		return stats, fmt.Errorf("not connected to graph database")
+	}
+
+	cutoff := time.Now().Add(-orphanChunkGracePeriod).Unix()
+	for _, prune := range pruneOrphansQueries(cutoff) {
+		result, err := g.queueWriteSyncResult(ctx, prune.query)
+		if err != nil {
+			return stats, fmt.Errorf("failed to prune orphaned %s nodes; %w", prune.label, err)
+		}
+		stats.Deleted[prune.label] += result.NodesDeleted()
+	}
+
+	return stats, nil
+}
+
+// orphanQuery is a query deleting orphaned nodes with label.
+type orphanQuery struct {
+	label string
+	query string
+}
+
+// pruneOrphansQueries builds the queries deleting orphaned nodes. Chunks come
+// first so the metadata and embeddings of pruned chunks become orphans in turn.
+func pruneOrphansQueries(cutoff int64) []orphanQuery {
+	queries := []orphanQuery{{
+		label: "Chunk",
+		query: parameterized(`
+		MATCH (c:Chunk)
+		WHERE NOT (:File)-[:HAS_CHUNK]->(c)
+		  AND (c.updated_at IS NULL OR c.updated_at < $cutoff)
 		DETACH DELETE c
-	`, escapeString(filePath))
-	return g.queueWriteSync(query)
+	`, map[string]any{"cutoff": cutoff}),
+	}}
+
+	for _, t := range chunkMetaTypes {
+		queries = append(queries, orphanQuery{label: t.label, query: orphanNodeQuery(t.rel, t.label)})
+	}
+	return append(queries, orphanQuery{
+		label: LabelChunkEmbedding,
+		query: orphanNodeQuery(RelHasEmbedding, LabelChunkEmbedding),
+	})
+}
+
+// orphanNodeQuery builds the query deleting nodes with label that no chunk
+// links to over rel.
+func orphanNodeQuery(rel, label string) string {
+	return fmt.Sprintf(`
+		MATCH (m:%s)
+		WHERE NOT (:Chunk)-[:%s]->(m)
+		DETACH DELETE m
+	`, label, rel)
 }
 
 // SetFileTags sets the tags for a file.
 func (g *FalkorDBGraph) SetFileTags(ctx context.Context, path string, tags []string) error {
-	if !g.IsConnected() {
+	if !g.acceptsWrites() {
+		return fmt.Errorf("not connected to graph database")
+	}
+
+	// First remove existing tag relationships
+	removeQuery := parameterized(`
+		MATCH (f:File {path: $path})-[r:HAS_TAG]->()
+		DELETE r
+	`, map[string]any{"path": path})
+	if err := g.queueWriteSync(ctx, removeQuery); err != nil {
+		return err
+	}
+
+	// Add new tags
+	for _, tag := range tags {
+		query := parameterized(`
+			MATCH (f:File {path: $path})
+			MERGE (t:Tag {normalized_name: $normalized_name})
+			ON CREATE SET t.name = $name, t.usage_count = 1, t.created_at = $now
+			ON MATCH SET t.usage_count = t.usage_count + 1
+			MERGE (f)-[:HAS_TAG]->(t)
+		`, map[string]any{
+			"path":            path,
+			"normalized_name": normalizeString(tag),
+			"name":            tag,
+			"now":             time.Now().Unix(),
+		})
+
+		if err := g.queueWrite(ctx, query); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetFileTopics sets the topics for a file.
+func (g *FalkorDBGraph) SetFileTopics(ctx context.Context, path string, topics []Topic) error {
+	if !g.acceptsWrites() {
+		return fmt.Errorf("not connected to graph database")
+	}
+
+	// First remove existing topic relationships
+	removeQuery := parameterized(`
+		MATCH (f:File {path: $path})-[r:COVERS_TOPIC]->()
+		DELETE r
+	`, map[string]any{"path": path})
+	if err := g.queueWriteSync(ctx, removeQuery); err != nil {
+		return err
+	}
+
+	// Add new topics
+	for _, topic := range topics {
+		query := parameterized(`
+			MATCH (f:File {path: $path})
+			MERGE (t:Topic {normalized_name: $normalized_name})
+			ON CREATE SET t.name = $name, t.usage_count = 1, t.created_at = $now
+			ON MATCH SET t.usage_count = t.usage_count + 1
+			MERGE (f)-[:COVERS_TOPIC {confidence: $confidence}]->(t)
+		`, map[string]any{
+			"path":            path,
+			"normalized_name": normalizeString(topic.Name),
+			"name":            topic.Name,
+			"now":             time.Now().Unix(),
+			"confidence":      topic.Confidence,
+		})
+
+		if err := g.queueWrite(ctx, query); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetFileEntities sets the entities mentioned in a file.
+func (g *FalkorDBGraph) SetFileEntities(ctx context.Context, path string, entities []Entity) error {
+	if !g.acceptsWrites() {
+		return fmt.Errorf("not connected to graph database")
+	}
+
+	// First remove existing entity relationships
+	removeQuery := parameterized(`
+		MATCH (f:File {path: $path})-[r:MENTIONS]->()
+		DELETE r
+	`, map[string]any{"path": path})
+	if err := g.queueWriteSync(ctx, removeQuery); err != nil {
+		return err
+	}
+
+	// Add new entities
+	for _, entity := range entities {
+		query := parameterized(`
+			MATCH (f:File {path: $path})
+			MERGE (e:Entity {normalized_name: $normalized_name, type: $type})
+			ON CREATE SET e.name = $name, e.usage_count = 1, e.created_at = $now
+			ON MATCH SET e.usage_count = e.usage_count + 1
+			MERGE (f)-[:MENTIONS]->(e)
+		`, map[string]any{
+			"path":            path,
+			"normalized_name": normalizeString(entity.Name),
+			"type":            entity.Type,
+			"name":            entity.Name,
+			"now":             time.Now().Unix(),
+		})
+
+		if err := g.queueWrite(ctx, query); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetFileRelations sets the entity relations extracted from a file. Relations
+// only link entities that already exist, so entities must be set first.
+func (g *FalkorDBGraph) SetFileRelations(ctx context.Context, path string, relations []EntityRelation) error {
+	if !g.acceptsWrites() {
+		return fmt.Errorf("not connected to graph database")
+	}
+
+	// First remove relations previously extracted from this file
+	if err := g.queueWriteSync(ctx, deleteFileRelationsQuery(path)); err != nil {
+		return err
+	}
+
+	for _, rel := range relations {
+		if rel.Subject == "" || rel.Predicate == "" || rel.Object == "" {
+			continue
+		}
+		if err := g.queueWrite(ctx, fileRelationQuery(path, rel)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteFileRelationsQuery builds the query removing the entity relations
+// extracted from a file. Relations live between entities rather than on the
+// file node, so deleting the file does not remove them.
+func deleteFileRelationsQuery(path string) string {
+	return parameterized(`
+		MATCH (:Entity)-[r:RELATED {file: $path}]->(:Entity)
+		DELETE r
+	`, map[string]any{"path": path})
+}
+
+// deleteRelationsUnderPathQuery builds the query removing the entity relations
+// extracted from files under a path prefix.
+func deleteRelationsUnderPathQuery(prefix string) string {
+	return parameterized(`
+		MATCH (:Entity)-[r:RELATED]->(:Entity)
+		WHERE r.file STARTS WITH $prefix
+		DELETE r
+	`, map[string]any{"prefix": prefix})
+}
+
+// fileRelationQuery builds the query linking a relation's subject and object entities.
+func fileRelationQuery(path string, rel EntityRelation) string {
+	return parameterized(`
+		MATCH (s:Entity {normalized_name: $subject}), (o:Entity {normalized_name: $object})
+		MERGE (s)-[:RELATED {predicate: $predicate, file: $path}]->(o)
+	`, map[string]any{
+		"subject":   normalizeString(rel.Subject),
+		"object":    normalizeString(rel.Object),
+		"predicate": rel.Predicate,
+		"path":      path,
+	})
+}
+
+// SetFileReferences sets the references from a file.
+func (g *FalkorDBGraph) SetFileReferences(ctx context.Context, path string, refs []Reference) error {
+	if !g.acceptsWrites() {
+		return fmt.Errorf("not connected to graph database")
+	}
+
+	// First remove existing reference relationships
+	removeQuery := parameterized(`
+		MATCH (f:File {path: $path})-[r:REFERENCES]->()
+		DELETE r
+	`, map[string]any{"path": path})
+	if err := g.queueWriteSync(ctx, removeQuery); err != nil {
+		return err
+	}
+
+	// Add new references
+	for _, ref := range refs {
+		if ref.Type == "file" {
+			// Reference to another file
+			query := parameterized(`
+				MATCH (f:File {path: $path})
+				MERGE (t:File {path: $target})
+				MERGE (f)-[:REFERENCES {type: 'file'}]->(t)
+			`, map[string]any{"path": path, "target": ref.Target})
+
+			if err := g.queueWrite(ctx, query); err != nil {
+				return err
+			}
+		}
+		// Other reference types can be stored as properties on the relationship
+	}
+
+	return nil
+}
+
+// SetChunkSectionReferences links a chunk to the chunks its in-document anchors
+// resolve to. Anchors without a matching section are stored on the chunk.
+func (g *FalkorDBGraph) SetChunkSectionReferences(ctx context.Context, chunkID string, targetIDs []string, unmatchedAnchors []string) error {
+	if !g.acceptsWrites() {
+		return fmt.Errorf("not connected to graph database")
+	}
+
+	return g.queueWrite(ctx, chunkSectionReferencesQuery(chunkID, targetIDs, unmatchedAnchors))
+}
+
+// chunkSectionReferencesQuery builds the query replacing a chunk's section links.
+func chunkSectionReferencesQuery(chunkID string, targetIDs []string, unmatchedAnchors []string) string {
+	return fmt.Sprintf(`
+		MATCH (c:Chunk {id: '%s'})
+		OPTIONAL MATCH (c)-[old:REFERENCES_SECTION]->()
+		DELETE old
+		WITH DISTINCT c
+		SET c.unmatched_anchors = %s
+		WITH c
+		MATCH (t:Chunk)
+		WHERE t.id IN %s
+		MERGE (c)-[:REFERENCES_SECTION]->(t)
+	`, escapeString(chunkID), formatStringArray(unmatchedAnchors), formatStringArray(targetIDs))
+}
+
+// LinkChunks replaces the NEXT_CHUNK edges of the file at path with a chain
+// through chunkIDs, which are in reading order. Chunk nodes are shared by files
+// with identical chunks, so each edge records the file it belongs to.
+func (g *FalkorDBGraph) LinkChunks(ctx context.Context, path string, chunkIDs []string) error {
+	if !g.acceptsWrites() {
 		return fmt.Errorf("not connected to graph database")
 	}
 
-	// First remove existing tag relationships
-	removeQuery := fmt.Sprintf(`
-		MATCH (f:File {path: '%s'})-[r:HAS_TAG]->()
-		DELETE r
-	`, escapeString(path))
-	if err := g.queueWriteSync(removeQuery); err != nil {
-		return err
+	return g.queueWrite(ctx, linkChunksQuery(path, chunkIDs))
+}
+
+// linkChunksQuery builds the query removing a file's NEXT_CHUNK edges and
+// linking each chunk to the next. Chunk IDs are content hashes, so a chunk
+// repeated within the file is linked only where it first appears; linking
+// later occurrences would close a cycle.
+func linkChunksQuery(path string, chunkIDs []string) string {
+	seen := make(map[string]bool, len(chunkIDs))
+	var ordered []string
+	for _, id := range chunkIDs {
+		if !seen[id] {
+			seen[id] = true
+			ordered = append(ordered, id)
+		}
+	}
+
+	pairs := []map[string]any{}
+	for i := 1; i < len(ordered); i++ {
+		pairs = append(pairs, map[string]any{"from": ordered[i-1], "to": ordered[i]})
+	}
+
+	return parameterized(`
+		OPTIONAL MATCH (:File {path: $path})-[:HAS_CHUNK]->(:Chunk)-[old:NEXT_CHUNK {file_path: $path}]->()
+		DELETE old
+		WITH count(*) AS removed
+		UNWIND $pairs AS pair
+		MATCH (a:Chunk {id: pair.from}), (b:Chunk {id: pair.to})
+		MERGE (a)-[:NEXT_CHUNK {file_path: $path}]->(b)
+	`, map[string]any{
+		"path":  path,
+		"pairs": pairs,
+	})
+}
+
+// GetAdjacentChunks returns the chunk with chunkID together with up to before
+// preceding and after following chunks, in reading order, by walking the
+// NEXT_CHUNK edges of the chunk's file. It returns nil if the chunk is unknown.
+func (g *FalkorDBGraph) GetAdjacentChunks(ctx context.Context, chunkID string, before, after int) ([]ChunkNode, error) {
+	if !g.IsConnected() {
+		return nil, fmt.Errorf("not connected to graph database")
+	}
+
+	result, err := g.readQuery(adjacentChunksQuery(chunkID, max(before, 0), max(after, 0)))
+	if err != nil {
+		return nil, fmt.Errorf("query failed; %w", err)
+	}
+
+	type positioned struct {
+		chunk  ChunkNode
+		offset int
+	}
+	var rows []positioned
+	for result.Next() {
+		record := result.Record()
+		rows = append(rows, positioned{
+			chunk: ChunkNode{
+				ID:          getStringFromRecord(record, 0),
+				FilePath:    getStringFromRecord(record, 1),
+				Index:       getIntFromRecord(record, 2),
+				ContentHash: getStringFromRecord(record, 3),
+				StartOffset: getIntFromRecord(record, 4),
+				EndOffset:   getIntFromRecord(record, 5),
+				ChunkType:   getStringFromRecord(record, 6),
+				Summary:     getStringFromRecord(record, 7),
+				LineStart:   getIntFromRecord(record, 8),
+				LineEnd:     getIntFromRecord(record, 9),
+				Content:     getStringFromRecord(record, 10),
+			},
+			offset: getIntFromRecord(record, 11),
+		})
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].offset < rows[j].offset })
+
+	var chunks []ChunkNode
+	for _, row := range rows {
+		chunks = append(chunks, row.chunk)
+	}
+	return chunks, nil
+}
+
+// adjacentChunksQuery builds the query returning a chunk and its neighbours
+// along NEXT_CHUNK edges of the chunk's file, each with its offset from the
+// chunk: negative before it, positive after it.
+func adjacentChunksQuery(chunkID string, before, after int) string {
+	const columns = `n.id, n.file_path, n.index, n.content_hash, n.start_offset, n.end_offset,
+		       n.chunk_type, n.summary, n.line_start, n.line_end, n.content`
+
+	parts := []string{fmt.Sprintf(`
+		MATCH (n:Chunk {id: $id})
+		RETURN %s, 0 AS offset`, columns)}
+	if before > 0 {
+		parts = append(parts, fmt.Sprintf(`
+		MATCH (c:Chunk {id: $id})
+		MATCH p = (n:Chunk)-[:NEXT_CHUNK*1..%d]->(c)
+		WHERE all(r IN relationships(p) WHERE r.file_path = c.file_path)
+		RETURN %s, -length(p) AS offset`, before, columns))
+	}
+	if after > 0 {
+		parts = append(parts, fmt.Sprintf(`
+		MATCH (c:Chunk {id: $id})
+		MATCH p = (c)-[:NEXT_CHUNK*1..%d]->(n:Chunk)
+		WHERE all(r IN relationships(p) WHERE r.file_path = c.file_path)
+		RETURN %s, length(p) AS offset`, after, columns))
+	}
+
+	return parameterized(strings.Join(parts, "\n\t\tUNION"), map[string]any{"id": chunkID})
+}
+
+// GetChunkByID returns the chunk with id together with its metadata, rebuilt
+// from whichever typed metadata node is attached to it. It returns nil if the
+// chunk is unknown.
+func (g *FalkorDBGraph) GetChunkByID(ctx context.Context, id string) (*ChunkNode, *chunkers.ChunkMetadata, error) {
+	if !g.IsConnected() {
+		return nil, nil, fmt.Errorf("not connected to graph database")
+	}
+
+	result, err := g.readQuery(chunkByIDQuery(id))
+	if err != nil {
+		return nil, nil, fmt.Errorf("query failed; %w", err)
+	}
+
+	if !result.Next() {
+		return nil, nil, nil
+	}
+
+	record := result.Record()
+	chunk := chunkFromRecord(record)
+	props := metaPropsFromRecord(record, chunkColumnCount+1, chunkColumnCount+2)
+	meta := chunkMetadataFrom(chunk, getStringFromRecord(record, chunkColumnCount), props)
+
+	return &chunk, meta, nil
+}
+
+// ListChunksForFile returns the chunks of the file at filePath ordered by
+// their index.
+func (g *FalkorDBGraph) ListChunksForFile(ctx context.Context, filePath string) ([]ChunkNode, error) {
+	if !g.IsConnected() {
+		return nil, fmt.Errorf("not connected to graph database")
+	}
+
+	result, err := g.readQuery(chunksForFileQuery(filePath))
+	if err != nil {
+		return nil, fmt.Errorf("query failed; %w", err)
+	}
+
+	var chunks []ChunkNode
+	for result.Next() {
+		chunks = append(chunks, chunkFromRecord(result.Record()))
+	}
+
+	return chunks, nil
+}
+
+// chunkColumns are the chunk properties read by chunkFromRecord, in order.
+const chunkColumns = `c.id, c.file_path, c.index, c.content_hash, c.start_offset, c.end_offset,
+		       c.chunk_type, c.token_count, c.boundary_confidence, c.line_start, c.line_end,
+		       c.summary, c.content`
+
+// chunkColumnCount is the number of columns in chunkColumns.
+const chunkColumnCount = 13
+
+// chunkByIDQuery builds the query returning a chunk, the relationship type of
+// its metadata node, and that node's property names and values.
+func chunkByIDQuery(id string) string {
+	return parameterized(fmt.Sprintf(`
+		MATCH (c:Chunk {id: $id})
+		OPTIONAL MATCH (c)-[r:%s]->(m)
+		RETURN %s,
+		       type(r), keys(m), [k IN keys(m) | m[k]]
+		LIMIT 1
+	`, chunkMetaRelPattern(), chunkColumns), map[string]any{"id": id})
+}
+
+// chunksForFileQuery builds the query returning the chunks of a file in index
+// order.
+func chunksForFileQuery(filePath string) string {
+	return parameterized(fmt.Sprintf(`
+		MATCH (f:File {path: $path})-[:HAS_CHUNK]->(c:Chunk)
+		RETURN %s
+		ORDER BY c.index
+	`, chunkColumns), map[string]any{"path": filePath})
+}
+
+// chunkMetaTypes are the relationship types and labels of a chunk's typed
+// metadata nodes.
+var chunkMetaTypes = []struct{ rel, label string }{
+	{RelHasCodeMeta, LabelCodeMeta},
+	{RelHasDocMeta, LabelDocumentMeta},
+	{RelHasNotebookMeta, LabelNotebookMeta},
+	{RelHasBuildMeta, LabelBuildMeta},
+	{RelHasInfraMeta, LabelInfraMeta},
+	{RelHasSchemaMeta, LabelSchemaMeta},
+	{RelHasStructMeta, LabelStructuredMeta},
+	{RelHasSQLMeta, LabelSQLMeta},
+	{RelHasLogMeta, LabelLogMeta},
+}
+
+// chunkMetaRelPattern returns the relationship type alternation matching any
+// chunk metadata relationship, e.g. HAS_CODE_META|HAS_DOC_META.
+func chunkMetaRelPattern() string {
+	rels := make([]string, len(chunkMetaTypes))
+	for i, t := range chunkMetaTypes {
+		rels[i] = t.rel
+	}
+	return strings.Join(rels, "|")
+}
+
+// chunkFromRecord parses a chunk from a record whose leading columns are
+// chunkColumns.
+func chunkFromRecord(record *redisgraph.Record) ChunkNode {
+	return ChunkNode{
+		ID:                 getStringFromRecord(record, 0),
+		FilePath:           getStringFromRecord(record, 1),
+		Index:              getIntFromRecord(record, 2),
+		ContentHash:        getStringFromRecord(record, 3),
+		StartOffset:        getIntFromRecord(record, 4),
+		EndOffset:          getIntFromRecord(record, 5),
+		ChunkType:          getStringFromRecord(record, 6),
+		TokenCount:         getIntFromRecord(record, 7),
+		BoundaryConfidence: getFloatFromRecord(record, 8),
+		LineStart:          getIntFromRecord(record, 9),
+		LineEnd:            getIntFromRecord(record, 10),
+		Summary:            getStringFromRecord(record, 11),
+		Content:            getStringFromRecord(record, 12),
+	}
+}
+
+// metaPropsFromRecord zips the property names and values held in the given
+// record columns into a map. It returns nil when the columns are empty.
+func metaPropsFromRecord(record *redisgraph.Record, keysIndex, valuesIndex int) map[string]any {
+	keys, _ := record.GetByIndex(keysIndex).([]any)
+	values, _ := record.GetByIndex(valuesIndex).([]any)
+	if len(keys) == 0 || len(keys) != len(values) {
+		return nil
+	}
+
+	props := make(map[string]any, len(keys))
+	for i, key := range keys {
+		if name, ok := key.(string); ok {
+			props[name] = values[i]
+		}
+	}
+	return props
+}
+
+// chunkMetadataFrom rebuilds a chunk's metadata from the chunk and the
+// properties of the metadata node reached over rel. It inverts the *MetaProps
+// functions; an unknown or empty rel yields metadata without a typed part.
+func chunkMetadataFrom(chunk ChunkNode, rel string, props map[string]any) *chunkers.ChunkMetadata {
+	meta := &chunkers.ChunkMetadata{
+		Type:               chunkers.ChunkType(chunk.ChunkType),
+		TokenEstimate:      chunk.TokenCount,
+		BoundaryConfidence: chunk.BoundaryConfidence,
+		LineStart:          chunk.LineStart,
+		LineEnd:            chunk.LineEnd,
+	}
+
+	switch rel {
+	case "HAS_CODE_META":
+		meta.Code = &chunkers.CodeMetadata{
+			Language:      propString(props, "language"),
+			FunctionName:  propString(props, "function_name"),
+			ClassName:     propString(props, "class_name"),
+			Signature:     propString(props, "signature"),
+			ReturnType:    propString(props, "return_type"),
+			Visibility:    propString(props, "visibility"),
+			Docstring:     propString(props, "docstring"),
+			Namespace:     propString(props, "namespace"),
+			ParentClass:   propString(props, "parent_class"),
+			IsAsync:       propBool(props, "is_async"),
+			IsStatic:      propBool(props, "is_static"),
+			IsExported:    propBool(props, "is_exported"),
+			IsGenerator:   propBool(props, "is_generator"),
+			IsGetter:      propBool(props, "is_getter"),
+			IsSetter:      propBool(props, "is_setter"),
+			IsConstructor: propBool(props, "is_constructor"),
+			IsTest:        propBool(props, "is_test"),
+			LineStart:     propInt(props, "line_start"),
+			LineEnd:       propInt(props, "line_end"),
+			Parameters:    propStrings(props, "parameters"),
+			Decorators:    propStrings(props, "decorators"),
+			Implements:    propStrings(props, "implements"),
+			Raises:        propStrings(props, "raises"),
+		}
+	case "HAS_DOC_META":
+		meta.Document = &chunkers.DocumentMetadata{
+			Heading:           propString(props, "heading"),
+			HeadingLevel:      propInt(props, "heading_level"),
+			SectionPath:       propString(props, "section_path"),
+			SectionNumber:     propString(props, "section_number"),
+			Title:             propString(props, "title"),
+			Author:            propString(props, "author"),
+			PageNumber:        propInt(props, "page_number"),
+			PageCount:         propInt(props, "page_count"),
+			WordCount:         propInt(props, "word_count"),
+			HasCodeBlock:      propBool(props, "has_code_block"),
+			CodeLanguage:      propString(props, "code_language"),
+			ListDepth:         propInt(props, "list_depth"),
+			IsTable:           propBool(props, "is_table"),
+			IsFootnote:        propBool(props, "is_footnote"),
+			ExtractionQuality: propString(props, "extraction_quality"),
+		}
+	case "HAS_NOTEBOOK_META":
+		meta.Notebook = &chunkers.NotebookMetadata{
+			CellType:       propString(props, "cell_type"),
+			CellIndex:      propInt(props, "cell_index"),
+			ExecutionCount: propInt(props, "execution_count"),
+			HasOutput:      propBool(props, "has_output"),
+			OutputTypes:    propStrings(props, "output_types"),
+			Kernel:         propString(props, "kernel"),
+		}
+	case "HAS_BUILD_META":
+		meta.Build = &chunkers.BuildMetadata{
+			TargetName:   propString(props, "target_name"),
+			Dependencies: propStrings(props, "dependencies"),
+			StageName:    propString(props, "stage_name"),
+			BaseImage:    propString(props, "base_image"),
+		}
+	case "HAS_INFRA_META":
+		meta.Infra = &chunkers.InfraMetadata{
+			ResourceType: propString(props, "resource_type"),
+			ResourceName: propString(props, "resource_name"),
+			BlockType:    propString(props, "block_type"),
+		}
+	case "HAS_SCHEMA_META":
+		meta.Schema = &chunkers.SchemaMetadata{
+			MessageName: propString(props, "message_name"),
+			ServiceName: propString(props, "service_name"),
+			RPCName:     propString(props, "rpc_name"),
+			TypeName:    propString(props, "type_name"),
+			TypeKind:    propString(props, "type_kind"),
+		}
+	case "HAS_STRUCT_META":
+		meta.Structured = &chunkers.StructuredMetadata{
+			SchemaPath:  propString(props, "schema_path"),
+			ElementName: propString(props, "element_name"),
+			ElementPath: propString(props, "element_path"),
+			TablePath:   propString(props, "table_path"),
+			RecordIndex: propInt(props, "record_index"),
+			RecordCount: propInt(props, "record_count"),
+			KeyNames:    propStrings(props, "key_names"),
+		}
+	case "HAS_SQL_META":
+		meta.SQL = &chunkers.SQLMetadata{
+			StatementType: propString(props, "statement_type"),
+			ObjectType:    propString(props, "object_type"),
+			TableName:     propString(props, "table_name"),
+			ProcedureName: propString(props, "procedure_name"),
+			SQLDialect:    propString(props, "sql_dialect"),
+		}
+	case "HAS_LOG_META":
+		meta.Log = &chunkers.LogMetadata{
+			TimeStart:  time.Unix(int64(propInt(props, "time_start")), 0),
+			TimeEnd:    time.Unix(int64(propInt(props, "time_end")), 0),
+			LogLevel:   propString(props, "log_level"),
+			LogFormat:  propString(props, "log_format"),
+			ErrorCount: propInt(props, "error_count"),
+			SourceApp:  propString(props, "source_app"),
+		}
+	}
+
+	return meta
+}
+
+// propString returns the string property name of props, or "" if absent.
+func propString(props map[string]any, name string) string {
+	s, _ := props[name].(string)
+	return s
+}
+
+// propInt returns the integer property name of props, or 0 if absent.
+func propInt(props map[string]any, name string) int {
+	switch v := props[name].(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+// propBool returns the boolean property name of props, or false if absent.
+func propBool(props map[string]any, name string) bool {
+	b, _ := props[name].(bool)
+	return b
+}
+
+// propStrings returns the string list property name of props, or nil if
+// absent or empty.
+func propStrings(props map[string]any, name string) []string {
+	switch v := props[name].(type) {
+	case []string:
+		if len(v) == 0 {
+			return nil
+		}
+		return v
+	case []any:
+		var out []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// GetBacklinks returns the files that reference or import the file at path.
+func (g *FalkorDBGraph) GetBacklinks(ctx context.Context, path string) ([]FileNode, error) {
+	if !g.IsConnected() {
+		return nil, fmt.Errorf("not connected to graph database")
+	}
+
+	result, err := g.readQuery(backlinksQuery(path))
+	if err != nil {
+		return nil, fmt.Errorf("query failed; %w", err)
+	}
+
+	var files []FileNode
+	for result.Next() {
+		file, err := parseFileFromRecord(result.Record())
+		if err != nil {
+			continue
+		}
+		files = append(files, *file)
+	}
+
+	return files, nil
+}
+
+// GetReferences returns the references and imports from the file at path.
+func (g *FalkorDBGraph) GetReferences(ctx context.Context, path string) ([]Reference, error) {
+	if !g.IsConnected() {
+		return nil, fmt.Errorf("not connected to graph database")
+	}
+
+	result, err := g.readQuery(referencesQuery(path))
+	if err != nil {
+		return nil, fmt.Errorf("query failed; %w", err)
+	}
+
+	var refs []Reference
+	for result.Next() {
+		record := result.Record()
+		target := getStringFromRecord(record, 1)
+		if target == "" {
+			continue
+		}
+		refs = append(refs, Reference{
+			Type:   getStringFromRecord(record, 0),
+			Target: target,
+		})
+	}
+
+	return refs, nil
+}
+
+// backlinksQuery builds the query for files linking to path.
+func backlinksQuery(path string) string {
+	return fmt.Sprintf(`
+		MATCH (f:File)-[:REFERENCES|IMPORTS]->(t:File {path: '%s'})
+		WHERE f.path <> t.path
+		RETURN DISTINCT f.path, f.name, f.extension, f.mime_type, f.language,
+			   f.ingest_kind, f.ingest_mode, f.ingest_reason,
+			   f.size, f.mod_time, f.content_hash, f.metadata_hash,
+			   f.summary, f.complexity, f.analyzed_at, f.analysis_version,
+			   f.change_reason
+		ORDER BY f.path
+	`, escapeString(path))
+}
+
+// referencesQuery builds the query for files linked from path.
+func referencesQuery(path string) string {
+	return fmt.Sprintf(`
+		MATCH (f:File {path: '%s'})-[r:REFERENCES|IMPORTS]->(t:File)
+		RETURN DISTINCT CASE WHEN type(r) = 'IMPORTS' THEN 'import' ELSE coalesce(r.type, 'file') END, t.path
+		ORDER BY t.path
+	`, escapeString(path))
+}
+
+// relatedChunkNeighbors is the number of nearest chunks considered per source
+// chunk when scoring related files by embedding similarity.
+const relatedChunkNeighbors = 20
+
+// GetRelatedFiles returns the k files most related to the file at path, scored
+// by shared tags, topics, entities and imports plus chunk-embedding similarity.
+func (g *FalkorDBGraph) GetRelatedFiles(ctx context.Context, path string, k int) ([]RelatedFile, error) {
+	if !g.IsConnected() {
+		return nil, fmt.Errorf("not connected to graph database")
+	}
+	if k <= 0 {
+		return nil, nil
+	}
+
+	result, err := g.readQuery(relatedFilesQuery(path, relatedChunkNeighbors))
+	if err != nil {
+		return nil, fmt.Errorf("query failed; %w", err)
+	}
+
+	metric := g.distanceMetric()
+	var rows []relatedSignalRow
+	for result.Next() {
+		record := result.Record()
+		row := relatedSignalRow{
+			Signal: getStringFromRecord(record, 0),
+			Path:   getStringFromRecord(record, 1),
+			Name:   getStringFromRecord(record, 2),
+			Score:  getFloatFromRecord(record, 3),
+		}
+		if row.Signal == "similarity" {
+			row.Score = metric.Similarity(row.Score)
+		}
+		rows = append(rows, row)
+	}
+
+	return scoreRelatedFiles(rows, g.config.RelatedWeights, k), nil
+}
+
+// relatedFilesQuery builds the query returning one row per shared signal
+// between path and another file: the signal, the other file, the shared item
+// and, for similarity rows, the vector index distance between the chunks.
+// Imports are the file references both files hold, as SetFileReferences
+// stores them.
+func relatedFilesQuery(path string, neighbors int) string {
+	p := escapeString(path)
+	return fmt.Sprintf(`
+		MATCH (f:File {path: '%[1]s'})-[:HAS_TAG]->(t:Tag)<-[:HAS_TAG]-(o:File)
+		WHERE o.path <> f.path
+		RETURN 'tag' AS signal, o.path AS path, t.name AS name, 1.0 AS score
+		UNION ALL
+		MATCH (f:File {path: '%[1]s'})-[:COVERS_TOPIC]->(t:Topic)<-[:COVERS_TOPIC]-(o:File)
+		WHERE o.path <> f.path
+		RETURN 'topic' AS signal, o.path AS path, t.name AS name, 1.0 AS score
+		UNION ALL
+		MATCH (f:File {path: '%[1]s'})-[:MENTIONS]->(e:Entity)<-[:MENTIONS]-(o:File)
+		WHERE o.path <> f.path
+		RETURN 'entity' AS signal, o.path AS path, e.name AS name, 1.0 AS score
+		UNION ALL
+		MATCH (f:File {path: '%[1]s'})-[:REFERENCES {type: 'file'}]->(m:File)<-[:REFERENCES {type: 'file'}]-(o:File)
+		WHERE o.path <> f.path
+		RETURN 'import' AS signal, o.path AS path, m.path AS name, 1.0 AS score
+		UNION ALL
+		MATCH (f:File {path: '%[1]s'})-[:HAS_CHUNK]->(:Chunk)-[:HAS_EMBEDDING]->(e:ChunkEmbedding)
+		CALL db.idx.vector.queryNodes('ChunkEmbedding', 'embedding', %[2]d, e.embedding)
+		YIELD node, score
+		MATCH (c:Chunk)-[:HAS_EMBEDDING]->(node)
+		WHERE c.file_path <> f.path AND node.provider = e.provider AND node.model = e.model
+		RETURN 'similarity' AS signal, c.file_path AS path, c.id AS name, score
+	`, p, neighbors)
+}
+
+// relatedSignalRow is one shared signal returned by relatedFilesQuery.
+type relatedSignalRow struct {
+	Signal string
+	Path   string
+	Name   string
+	Score  float64
+}
+
+// scoreRelatedFiles aggregates signal rows per file, scores each file with
+// weights and returns the k highest scoring files. Ties are ordered by path.
+func scoreRelatedFiles(rows []relatedSignalRow, weights RelatedFileWeights, k int) []RelatedFile {
+	if weights == (RelatedFileWeights{}) {
+		weights = DefaultRelatedFileWeights()
+	}
+
+	byPath := make(map[string]*RelatedFile)
+	similarityCounts := make(map[string]int)
+	for _, row := range rows {
+		if row.Path == "" {
+			continue
+		}
+		file, ok := byPath[row.Path]
+		if !ok {
+			file = &RelatedFile{Path: row.Path}
+			byPath[row.Path] = file
+		}
+		switch row.Signal {
+		case "tag":
+			file.SharedTags = appendUnique(file.SharedTags, row.Name)
+		case "topic":
+			file.SharedTopics = appendUnique(file.SharedTopics, row.Name)
+		case "entity":
+			file.SharedEntities = appendUnique(file.SharedEntities, row.Name)
+		case "import":
+			file.SharedImports = appendUnique(file.SharedImports, row.Name)
+		case "similarity":
+			file.Similarity += row.Score
+			similarityCounts[row.Path]++
+		}
+	}
+
+	related := make([]RelatedFile, 0, len(byPath))
+	for path, file := range byPath {
+		if n := similarityCounts[path]; n > 0 {
+			file.Similarity /= float64(n)
+		}
+		file.Score = weights.Tags*float64(len(file.SharedTags)) +
+			weights.Topics*float64(len(file.SharedTopics)) +
+			weights.Entities*float64(len(file.SharedEntities)) +
+			weights.Imports*float64(len(file.SharedImports)) +
+			weights.Similarity*file.Similarity
+		related = append(related, *file)
+	}
+
+	sort.Slice(related, func(i, j int) bool {
+		if related[i].Score != related[j].Score {
+			return related[i].Score > related[j].Score
+		}
+		return related[i].Path < related[j].Path
+	})
+	if len(related) > k {
+		related = related[:k]
+	}
+	return related
+}
+
+// appendUnique appends s to list unless already present.
+func appendUnique(list []string, s string) []string {
+	if slices.Contains(list, s) {
+		return list
+	}
+	return append(list, s)
+}
+
+// Query executes a raw Cypher query.
+func (g *FalkorDBGraph) Query(ctx context.Context, cypher string) (*QueryResult, error) {
+	if !g.IsConnected() {
+		return nil, fmt.Errorf("not connected to graph database")
+	}
+
+	run := g.query
+	if isReadOnlyCypher(cypher) {
+		run = g.readQuery
+	}
+
+	result, err := run(cypher)
+	if err != nil {
+		return nil, fmt.Errorf("query failed; %w", err)
+	}
+
+	return convertQueryResult(result), nil
+}
+
+// hasEmbeddingQuery builds the query counting ChunkEmbedding nodes of the given
+// version attached to chunks with contentHash.
+func hasEmbeddingQuery(contentHash string, version int) string {
+	return fmt.Sprintf(`
+		MATCH (c:Chunk {content_hash: '%s'})-[:HAS_EMBEDDING]->(e:ChunkEmbedding {version: %d})
+		WHERE e.embedding IS NOT NULL
+		RETURN count(e)
+	`, escapeString(contentHash), version)
+}
+
+// HasEmbedding checks if an embedding exists for the given content hash and version.
+func (g *FalkorDBGraph) HasEmbedding(ctx context.Context, contentHash string, version int) (bool, error) {
+	if !g.IsConnected() {
+		return false, fmt.Errorf("not connected to graph database")
+	}
+
+	result, err := g.query(hasEmbeddingQuery(contentHash, version))
+	if err != nil {
+		return false, fmt.Errorf("query failed; %w", err)
+	}
+
+	if result.Empty() || !result.Next() {
+		return false, nil
+	}
+
+	record := result.Record()
+	countVal := record.GetByIndex(0)
+	if countVal == nil {
+		return false, nil
+	}
+
+	count, ok := countVal.(int64)
+	if !ok {
+		return false, nil
+	}
+
+	return count > 0, nil
+}
+
+// getEmbeddingQuery builds the query returning the most recent embedding from
+// provider and model at the given version attached to a chunk with contentHash.
+func getEmbeddingQuery(contentHash, provider, model string, version int) string {
+	return parameterized(`
+		MATCH (c:Chunk {content_hash: $content_hash})-[:HAS_EMBEDDING]->(e:ChunkEmbedding {provider: $provider, model: $model, version: $version})
+		WHERE e.embedding IS NOT NULL
+		RETURN e.embedding
+		ORDER BY e.created_at DESC
+		LIMIT 1
+	`, map[string]any{
+		"content_hash": contentHash,
+		"provider":     provider,
+		"model":        model,
+		"version":      version,
+	})
+}
+
+// GetEmbedding returns a stored embedding for the given content hash,
+// provider, model and version, or nil if none exists.
+func (g *FalkorDBGraph) GetEmbedding(ctx context.Context, contentHash, provider, model string, version int) ([]float32, error) {
+	if !g.IsConnected() {
+		return nil, fmt.Errorf("not connected to graph database")
+	}
+
+	result, err := g.readQuery(getEmbeddingQuery(contentHash, provider, model, version))
+	if err != nil {
+		return nil, fmt.Errorf("query failed; %w", err)
+	}
+
+	if result.Empty() || !result.Next() {
+		return nil, nil
 	}
 
-	// Add new tags
-	for _, tag := range tags {
-		query := fmt.Sprintf(`
-			MATCH (f:File {path: '%s'})
-			MERGE (t:Tag {normalized_name: '%s'})
-			ON CREATE SET t.name = '%s', t.usage_count = 1, t.created_at = %d
-			ON MATCH SET t.usage_count = t.usage_count + 1
-			MERGE (f)-[:HAS_TAG]->(t)
-		`, escapeString(path),
-			escapeString(normalizeString(tag)),
-			escapeString(tag),
-			time.Now().Unix())
+	return toFloat32s(result.Record().GetByIndex(0)), nil
+}
 
-		if err := g.queueWrite(query); err != nil {
-			return err
+// toFloat32s converts a list value returned by the graph to a float32 slice,
+// or nil if it is not a numeric list.
+func toFloat32s(val any) []float32 {
+	items, ok := val.([]any)
+	if !ok || len(items) == 0 {
+		return nil
+	}
+
+	out := make([]float32, len(items))
+	for i, item := range items {
+		switch v := item.(type) {
+		case float64:
+			out[i] = float32(v)
+		case float32:
+			out[i] = v
+		case int64:
+			out[i] = float32(v)
+		case int:
+			out[i] = float32(v)
+		default:
+			return nil
 		}
 	}
+	return out
+}
 
-	return nil
+// filesByIngestReasonQuery builds the query grouping file counts by the reason
+// they were last ingested.
+func filesByIngestReasonQuery() string {
+	return `
+		MATCH (f:File)
+		RETURN coalesce(f.change_reason, ''), count(f)
+	`
 }
 
-// SetFileTopics sets the topics for a file.
-func (g *FalkorDBGraph) SetFileTopics(ctx context.Context, path string, topics []Topic) error {
+// CountFilesByIngestReason returns the number of files per reason they were
+// last ingested (new, modified, forced, version_bump). Files persisted before
+// reasons were recorded are counted under "".
+func (g *FalkorDBGraph) CountFilesByIngestReason(ctx context.Context) (map[string]int, error) {
 	if !g.IsConnected() {
-		return fmt.Errorf("not connected to graph database")
+		return nil, fmt.Errorf("not connected to graph database")
 	}
 
-	// First remove existing topic relationships
-	removeQuery := fmt.Sprintf(`
-		MATCH (f:File {path: '%s'})-[r:COVERS_TOPIC]->()
-		DELETE r
-	`, escapeString(path))
-	if err := g.queueWriteSync(removeQuery); err != nil {
-		return err
+	result, err := g.readQuery(filesByIngestReasonQuery())
+	if err != nil {
+		return nil, fmt.Errorf("query failed; %w", err)
 	}
 
-	// Add new topics
-	for _, topic := range topics {
-		query := fmt.Sprintf(`
-			MATCH (f:File {path: '%s'})
-			MERGE (t:Topic {normalized_name: '%s'})
-			ON CREATE SET t.name = '%s', t.usage_count = 1, t.created_at = %d
-			ON MATCH SET t.usage_count = t.usage_count + 1
-			MERGE (f)-[:COVERS_TOPIC {confidence: %f}]->(t)
-		`, escapeString(path),
-			escapeString(normalizeString(topic.Name)),
-			escapeString(topic.Name),
-			time.Now().Unix(),
-			topic.Confidence)
-
-		if err := g.queueWrite(query); err != nil {
-			return err
-		}
+	counts := make(map[string]int)
+	for result.Next() {
+		record := result.Record()
+		counts[getStringFromRecord(record, 0)] += getIntFromRecord(record, 1)
 	}
 
-	return nil
+	return counts, nil
 }
 
-// SetFileEntities sets the entities mentioned in a file.
-func (g *FalkorDBGraph) SetFileEntities(ctx context.Context, path string, entities []Entity) error {
+// GetDirectoryTree returns the directory tree rooted at rootPath, nested up
+// to maxDepth levels below the root. A maxDepth of zero or less is unbounded.
+func (g *FalkorDBGraph) GetDirectoryTree(ctx context.Context, rootPath string, maxDepth int) (*DirectoryTree, error) {
 	if !g.IsConnected() {
-		return fmt.Errorf("not connected to graph database")
+		return nil, fmt.Errorf("not connected to graph database")
 	}
 
-	// First remove existing entity relationships
-	removeQuery := fmt.Sprintf(`
-		MATCH (f:File {path: '%s'})-[r:MENTIONS]->()
-		DELETE r
-	`, escapeString(path))
-	if err := g.queueWriteSync(removeQuery); err != nil {
-		return err
+	rootPath = filepath.Clean(rootPath)
+	result, err := g.readQuery(directoryTreeQuery(rootPath))
+	if err != nil {
+		return nil, fmt.Errorf("query failed; %w", err)
 	}
 
-	// Add new entities
-	for _, entity := range entities {
-		query := fmt.Sprintf(`
-			MATCH (f:File {path: '%s'})
-			MERGE (e:Entity {normalized_name: '%s', type: '%s'})
-			ON CREATE SET e.name = '%s', e.usage_count = 1, e.created_at = %d
-			ON MATCH SET e.usage_count = e.usage_count + 1
-			MERGE (f)-[:MENTIONS]->(e)
-		`, escapeString(path),
-			escapeString(normalizeString(entity.Name)),
-			escapeString(entity.Type),
-			escapeString(entity.Name),
-			time.Now().Unix())
-
-		if err := g.queueWrite(query); err != nil {
-			return err
-		}
+	var rows []directoryTreeRow
+	for result.Next() {
+		record := result.Record()
+		rows = append(rows, directoryTreeRow{
+			dirPath:      getStringFromRecord(record, 0),
+			dirName:      getStringFromRecord(record, 1),
+			isRemembered: getBoolFromRecord(record, 2),
+			filePath:     getStringFromRecord(record, 3),
+			fileName:     getStringFromRecord(record, 4),
+			fileSummary:  getStringFromRecord(record, 5),
+		})
 	}
 
-	return nil
+	return buildDirectoryTree(rootPath, maxDepth, rows), nil
 }
 
-// SetFileReferences sets the references from a file.
-func (g *FalkorDBGraph) SetFileReferences(ctx context.Context, path string, refs []Reference) error {
-	if !g.IsConnected() {
-		return fmt.Errorf("not connected to graph database")
+// directoryTreeQuery builds the query for every directory under rootPath
+// together with the files each directory contains.
+func directoryTreeQuery(rootPath string) string {
+	root := escapeString(rootPath)
+	return fmt.Sprintf(`
+		MATCH (d:Directory)
+		WHERE d.path = '%s' OR d.path STARTS WITH '%s/'
+		OPTIONAL MATCH (d)-[:CONTAINS]->(f:File)
+		RETURN d.path, d.name, d.is_remembered, f.path, f.name, f.summary
+		ORDER BY d.path, f.path
+	`, root, strings.TrimSuffix(root, "/"))
+}
+
+// directoryTreeRow is one directory/file pair returned by directoryTreeQuery.
+// File fields are empty for directories without files.
+type directoryTreeRow struct {
+	dirPath      string
+	dirName      string
+	isRemembered bool
+	filePath     string
+	fileName     string
+	fileSummary  string
+}
+
+// buildDirectoryTree nests directory rows under rootPath by path. Directory
+// nodes are only linked to their files, so intermediate directories missing
+// from the graph are synthesized from their descendants' paths.
+func buildDirectoryTree(rootPath string, maxDepth int, rows []directoryTreeRow) *DirectoryTree {
+	rootPath = filepath.Clean(rootPath)
+	root := &DirectoryTree{Path: rootPath, Name: filepath.Base(rootPath)}
+	nodes := map[string]*DirectoryTree{rootPath: root}
+
+	depthOf := func(path string) int {
+		rel, err := filepath.Rel(rootPath, path)
+		if err != nil || rel == "." {
+			return 0
+		}
+		return strings.Count(rel, string(filepath.Separator)) + 1
 	}
 
-	// First remove existing reference relationships
-	removeQuery := fmt.Sprintf(`
-		MATCH (f:File {path: '%s'})-[r:REFERENCES]->()
-		DELETE r
-	`, escapeString(path))
-	if err := g.queueWriteSync(removeQuery); err != nil {
-		return err
+	var node func(path string) *DirectoryTree
+	node = func(path string) *DirectoryTree {
+		if n, ok := nodes[path]; ok {
+			return n
+		}
+		n := &DirectoryTree{Path: path, Name: filepath.Base(path)}
+		nodes[path] = n
+		parent := node(filepath.Dir(path))
+		parent.Children = append(parent.Children, n)
+		return n
 	}
 
-	// Add new references
-	for _, ref := range refs {
-		if ref.Type == "file" {
-			// Reference to another file
-			query := fmt.Sprintf(`
-				MATCH (f:File {path: '%s'})
-				MERGE (t:File {path: '%s'})
-				MERGE (f)-[:REFERENCES {type: 'file'}]->(t)
-			`, escapeString(path), escapeString(ref.Target))
+	for _, row := range rows {
+		dirPath := filepath.Clean(row.dirPath)
+		if maxDepth > 0 && depthOf(dirPath) > maxDepth {
+			continue
+		}
 
-			if err := g.queueWrite(query); err != nil {
-				return err
-			}
+		dir := node(dirPath)
+		if row.dirName != "" {
+			dir.Name = row.dirName
 		}
-		// Other reference types can be stored as properties on the relationship
+		dir.IsRemembered = dir.IsRemembered || row.isRemembered
+
+		if row.filePath == "" {
+			continue
+		}
+		if len(dir.Files) > 0 && dir.Files[len(dir.Files)-1].Path == row.filePath {
+			continue
+		}
+		dir.Files = append(dir.Files, DirectoryTreeFile{
+			Path:    row.filePath,
+			Name:    row.fileName,
+			Summary: row.fileSummary,
+		})
+		dir.FileCount++
 	}
 
-	return nil
+	return root
 }
 
-// Query executes a raw Cypher query.
-func (g *FalkorDBGraph) Query(ctx context.Context, cypher string) (*QueryResult, error) {
+// topNodesQuery builds the query returning the n most used nodes with the
+// given label, optionally restricted to a node type.
+func topNodesQuery(label string, fields string, n int, nodeType string) string {
+	where := ""
+	if nodeType != "" {
+		where = fmt.Sprintf("WHERE n.type = '%s'", escapeString(nodeType))
+	}
+	return fmt.Sprintf(`
+		MATCH (n:%s)
+		%s
+		RETURN %s
+		ORDER BY n.usage_count DESC, n.normalized_name
+		LIMIT %d
+	`, label, where, fields, n)
+}
+
+// TopTags returns the n most used tags in descending usage order.
+func (g *FalkorDBGraph) TopTags(ctx context.Context, n int) ([]TagNode, error) {
 	if !g.IsConnected() {
 		return nil, fmt.Errorf("not connected to graph database")
 	}
+	if n <= 0 {
+		return nil, nil
+	}
 
-	result, err := g.query(cypher)
+	result, err := g.readQuery(topNodesQuery(LabelTag, "n.name, n.normalized_name, n.usage_count", n, ""))
 	if err != nil {
 		return nil, fmt.Errorf("query failed; %w", err)
 	}
 
-	return convertQueryResult(result), nil
+	var tags []TagNode
+	for result.Next() {
+		record := result.Record()
+		tags = append(tags, TagNode{
+			Name:           getStringFromRecord(record, 0),
+			NormalizedName: getStringFromRecord(record, 1),
+			UsageCount:     getIntFromRecord(record, 2),
+		})
+	}
+
+	return tags, nil
 }
 
-// HasEmbedding checks if an embedding exists for the given content hash and version.
-func (g *FalkorDBGraph) HasEmbedding(ctx context.Context, contentHash string, version int) (bool, error) {
+// TopTopics returns the n most used topics in descending usage order.
+func (g *FalkorDBGraph) TopTopics(ctx context.Context, n int) ([]TopicNode, error) {
 	if !g.IsConnected() {
-		return false, fmt.Errorf("not connected to graph database")
+		return nil, fmt.Errorf("not connected to graph database")
+	}
+	if n <= 0 {
+		return nil, nil
 	}
 
-	query := fmt.Sprintf(`
-		MATCH (c:Chunk {content_hash: '%s', embedding_version: %d})
-		WHERE c.embedding IS NOT NULL
-		RETURN count(c)
-	`, escapeString(contentHash), version)
-
-	result, err := g.query(query)
+	result, err := g.readQuery(topNodesQuery(LabelTopic, "n.name, n.normalized_name, n.usage_count", n, ""))
 	if err != nil {
-		return false, fmt.Errorf("query failed; %w", err)
+		return nil, fmt.Errorf("query failed; %w", err)
 	}
 
-	if result.Empty() || !result.Next() {
-		return false, nil
+	var topics []TopicNode
+	for result.Next() {
+		record := result.Record()
+		topics = append(topics, TopicNode{
+			Name:           getStringFromRecord(record, 0),
+			NormalizedName: getStringFromRecord(record, 1),
+			UsageCount:     getIntFromRecord(record, 2),
+		})
 	}
 
-	record := result.Record()
-	countVal := record.GetByIndex(0)
-	if countVal == nil {
-		return false, nil
+	return topics, nil
+}
+
+// TopEntities returns the n most mentioned entities in descending usage order.
+// An empty entityType includes entities of every type.
+func (g *FalkorDBGraph) TopEntities(ctx context.Context, n int, entityType string) ([]EntityNode, error) {
+	if !g.IsConnected() {
+		return nil, fmt.Errorf("not connected to graph database")
+	}
+	if n <= 0 {
+		return nil, nil
 	}
 
-	count, ok := countVal.(int64)
-	if !ok {
-		return false, nil
+	result, err := g.readQuery(topNodesQuery(LabelEntity, "n.name, n.type, n.normalized_name, n.usage_count", n, entityType))
+	if err != nil {
+		return nil, fmt.Errorf("query failed; %w", err)
 	}
 
-	return count > 0, nil
+	var entities []EntityNode
+	for result.Next() {
+		record := result.Record()
+		entities = append(entities, EntityNode{
+			Name:           getStringFromRecord(record, 0),
+			Type:           getStringFromRecord(record, 1),
+			NormalizedName: getStringFromRecord(record, 2),
+			UsageCount:     getIntFromRecord(record, 3),
+		})
+	}
+
+	return entities, nil
 }
 
 // ExportSnapshot exports a complete snapshot of the graph.
 func (g *FalkorDBGraph) ExportSnapshot(ctx context.Context) (*GraphSnapshot, error) {
+	return g.exportSnapshot(ctx, time.Time{})
+}
+
+// ExportSnapshotSince exports a delta snapshot containing files, directories,
+// and chunks whose updated_at is at or after since, along with the tags, topics,
+// and entities linked to the changed files. Pass the ExportedAt of the previous
+// snapshot as since to chain incremental exports.
+func (g *FalkorDBGraph) ExportSnapshotSince(ctx context.Context, since time.Time) (*GraphSnapshot, error) {
+	if since.IsZero() {
+		return nil, fmt.Errorf("since must be set for a delta snapshot")
+	}
+	return g.exportSnapshot(ctx, since)
+}
+
+// exportSnapshot exports nodes updated at or after since; a zero since exports everything.
+func (g *FalkorDBGraph) exportSnapshot(ctx context.Context, since time.Time) (*GraphSnapshot, error) {
 	if !g.IsConnected() {
 		return nil, fmt.Errorf("not connected to graph database")
 	}
 
+	// Include writes still sitting in the queue
+	if err := g.Flush(ctx); err != nil {
+		return nil, fmt.Errorf("failed to flush pending writes; %w", err)
+	}
+
 	snapshot := &GraphSnapshot{
 		ExportedAt: time.Now(),
 		Version:    1,
 	}
+	if !since.IsZero() {
+		snapshot.Since = &since
+	}
 
 	// Export files
-	files, err := g.exportFiles(ctx)
+	files, err := g.exportFiles(ctx, since)
 	if err != nil {
 		return nil, fmt.Errorf("failed to export files; %w", err)
 	}
 	snapshot.Files = files
 
 	// Export directories
-	dirs, err := g.exportDirectories(ctx)
+	dirs, err := g.exportDirectories(ctx, since)
 	if err != nil {
 		return nil, fmt.Errorf("failed to export directories; %w", err)
 	}
 	snapshot.Directories = dirs
 
 	// Export tags
-	tags, err := g.exportTags(ctx)
+	tags, err := g.exportTags(ctx, since)
 	if err != nil {
 		return nil, fmt.Errorf("failed to export tags; %w", err)
 	}
 	snapshot.Tags = tags
 
 	// Export topics
-	topics, err := g.exportTopics(ctx)
+	topics, err := g.exportTopics(ctx, since)
 	if err != nil {
 		return nil, fmt.Errorf("failed to export topics; %w", err)
 	}
 	snapshot.Topics = topics
 
 	// Export entities
-	entities, err := g.exportEntities(ctx)
+	entities, err := g.exportEntities(ctx, since)
 	if err != nil {
 		return nil, fmt.Errorf("failed to export entities; %w", err)
 	}
 	snapshot.Entities = entities
 
+	// Export chunks with stored content
+	chunks, err := g.exportChunks(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export chunks; %w", err)
+	}
+	snapshot.Chunks = chunks
+
 	// Get counts
-	snapshot.TotalChunks, _ = g.countNodes(ctx, LabelChunk)
-	snapshot.TotalRelationships, _ = g.countRelationships(ctx)
+	snapshot.TotalChunks, _ = g.count(ctx, exportChunkCountQuery(since))
+	snapshot.TotalRelationships, _ = g.count(ctx, exportRelationshipCountQuery(since))
 
 	return snapshot, nil
 }
@@ -1353,7 +3162,7 @@ func (g *FalkorDBGraph) GetFileWithRelations(ctx context.Context, path string) (
 		MATCH (f:File {path: '%s'})-[:HAS_TAG]->(t:Tag)
 		RETURN t.name
 	`, escapeString(path))
-	tagResult, err := g.query(tagQuery)
+	tagResult, err := g.readQuery(tagQuery)
 	if err == nil {
 		for tagResult.Next() {
 			record := tagResult.Record()
@@ -1368,7 +3177,7 @@ func (g *FalkorDBGraph) GetFileWithRelations(ctx context.Context, path string) (
 		MATCH (f:File {path: '%s'})-[r:COVERS_TOPIC]->(t:Topic)
 		RETURN t.name, r.confidence
 	`, escapeString(path))
-	topicResult, err := g.query(topicQuery)
+	topicResult, err := g.readQuery(topicQuery)
 	if err == nil {
 		for topicResult.Next() {
 			record := topicResult.Record()
@@ -1385,7 +3194,7 @@ func (g *FalkorDBGraph) GetFileWithRelations(ctx context.Context, path string) (
 		MATCH (f:File {path: '%s'})-[:MENTIONS]->(e:Entity)
 		RETURN e.name, e.type
 	`, escapeString(path))
-	entityResult, err := g.query(entityQuery)
+	entityResult, err := g.readQuery(entityQuery)
 	if err == nil {
 		for entityResult.Next() {
 			record := entityResult.Record()
@@ -1402,7 +3211,7 @@ func (g *FalkorDBGraph) GetFileWithRelations(ctx context.Context, path string) (
 		MATCH (f:File {path: '%s'})-[:HAS_CHUNK]->(c:Chunk)
 		RETURN count(c)
 	`, escapeString(path))
-	countResult, err := g.query(countQuery)
+	countResult, err := g.readQuery(countQuery)
 	if err == nil && countResult.Next() {
 		result.ChunkCount = getIntFromRecord(countResult.Record(), 0)
 	}
@@ -1411,7 +3220,14 @@ func (g *FalkorDBGraph) GetFileWithRelations(ctx context.Context, path string) (
 }
 
 // SearchSimilarChunks finds chunks similar to the given embedding using k-NN search.
+// Hits are ordered most similar first; see ChunkSearchHit for score semantics.
 func (g *FalkorDBGraph) SearchSimilarChunks(ctx context.Context, embedding []float32, k int) ([]ChunkSearchHit, error) {
+	return g.SearchSimilarChunksFiltered(ctx, embedding, k, ChunkFilter{})
+}
+
+// SearchSimilarChunksFiltered finds up to k chunks similar to the given
+// embedding that match filter. Hits are ordered most similar first.
+func (g *FalkorDBGraph) SearchSimilarChunksFiltered(ctx context.Context, embedding []float32, k int, filter ChunkFilter) ([]ChunkSearchHit, error) {
 	if !g.IsConnected() {
 		return nil, fmt.Errorf("not connected to graph database")
 	}
@@ -1424,42 +3240,34 @@ func (g *FalkorDBGraph) SearchSimilarChunks(ctx context.Context, embedding []flo
 		k = 10 // Default to 10 results
 	}
 
-	// Format embedding as array for query
-	embeddingStr := formatEmbeddingArray(embedding)
-
-	// Use FalkorDB's vector similarity search against ChunkEmbedding nodes, then
-	// resolve back to parent Chunk nodes.
-	query := fmt.Sprintf(`
-		CALL db.idx.vector.queryNodes('ChunkEmbedding', 'embedding', %d, %s)
-		YIELD node, score
-		MATCH (c:Chunk)-[:HAS_EMBEDDING]->(node)
-		RETURN c.id, c.file_path, c.index, c.content_hash,
-		       c.start_offset, c.end_offset, c.chunk_type,
-		       c.summary, score, node.provider, node.model
-		ORDER BY score DESC
-		LIMIT %d
-	`, k, embeddingStr, k)
-
-	result, err := g.query(query)
+	query := similarChunksQuery(embedding, k, filter)
+	result, err := g.readQuery(query)
 	if err != nil {
 		return nil, fmt.Errorf("vector search failed; %w", err)
 	}
 
+	metric := g.distanceMetric()
 	var chunks []ChunkSearchHit
 	for result.Next() {
 		record := result.Record()
+		distance := getFloatFromRecord(record, 8)
 		chunk := ChunkSearchHit{
 			Chunk: ChunkNode{
-				ID:          getStringFromRecord(record, 0),
-				FilePath:    getStringFromRecord(record, 1),
-				Index:       getIntFromRecord(record, 2),
-				ContentHash: getStringFromRecord(record, 3),
-				StartOffset: getIntFromRecord(record, 4),
-				EndOffset:   getIntFromRecord(record, 5),
-				ChunkType:   getStringFromRecord(record, 6),
-				Summary:     getStringFromRecord(record, 7),
+				ID:                 getStringFromRecord(record, 0),
+				FilePath:           getStringFromRecord(record, 1),
+				Index:              getIntFromRecord(record, 2),
+				ContentHash:        getStringFromRecord(record, 3),
+				StartOffset:        getIntFromRecord(record, 4),
+				EndOffset:          getIntFromRecord(record, 5),
+				ChunkType:          getStringFromRecord(record, 6),
+				Summary:            getStringFromRecord(record, 7),
+				BoundaryConfidence: getFloatFromRecord(record, 11),
+				LineStart:          getIntFromRecord(record, 12),
+				LineEnd:            getIntFromRecord(record, 13),
+				Content:            getStringFromRecord(record, 14),
 			},
-			Score:    getFloatFromRecord(record, 8),
+			Score:    metric.Similarity(distance),
+			Distance: distance,
 			Provider: getStringFromRecord(record, 9),
 			Model:    getStringFromRecord(record, 10),
 		}
@@ -1469,17 +3277,65 @@ func (g *FalkorDBGraph) SearchSimilarChunks(ctx context.Context, embedding []flo
 	return chunks, nil
 }
 
+// filteredSearchOverfetch multiplies the vector candidates fetched for a
+// filtered search. The index returns nearest neighbours before the filter
+// applies, so fetching only k would leave fewer than k matches.
+const filteredSearchOverfetch = 10
+
+// similarChunksQuery builds the k-NN query over ChunkEmbedding nodes, resolved
+// back to parent Chunk nodes and restricted by filter. Scores are distances,
+// so the nearest chunks sort first.
+func similarChunksQuery(embedding []float32, k int, filter ChunkFilter) string {
+	match := "MATCH (c:Chunk)-[:HAS_EMBEDDING]->(node)"
+	var conditions []string
+	params := map[string]any{}
+	if filter.FilePathPrefix != "" {
+		conditions = append(conditions, "c.file_path STARTS WITH $path_prefix")
+		params["path_prefix"] = filter.FilePathPrefix
+	}
+	if filter.ChunkType != "" {
+		conditions = append(conditions, "c.chunk_type = $chunk_type")
+		params["chunk_type"] = filter.ChunkType
+	}
+	if filter.Language != "" {
+		match = "MATCH (f:File)-[:HAS_CHUNK]->(c:Chunk)-[:HAS_EMBEDDING]->(node)"
+		conditions = append(conditions, "f.language = $language")
+		params["language"] = filter.Language
+	}
+
+	candidates := k
+	where := ""
+	if len(conditions) > 0 {
+		candidates = k * filteredSearchOverfetch
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	return parameterized(fmt.Sprintf(`
+		CALL db.idx.vector.queryNodes('ChunkEmbedding', 'embedding', %d, %s)
+		YIELD node, score
+		%s
+		%s
+		RETURN c.id, c.file_path, c.index, c.content_hash,
+		       c.start_offset, c.end_offset, c.chunk_type,
+		       c.summary, score, node.provider, node.model,
+		       c.boundary_confidence, c.line_start, c.line_end, c.content
+		ORDER BY score ASC
+		LIMIT %d
+	`, candidates, formatEmbeddingArray(embedding), match, where, k), params)
+}
+
 // Helper functions for export
 
-func (g *FalkorDBGraph) exportFiles(ctx context.Context) ([]FileNode, error) {
+func (g *FalkorDBGraph) exportFiles(ctx context.Context, since time.Time) ([]FileNode, error) {
 	query := `
-		MATCH (f:File)
+		MATCH (f:File)` + updatedSince("f", since) + `
 		RETURN f.path, f.name, f.extension, f.mime_type, f.language,
 			   f.ingest_kind, f.ingest_mode, f.ingest_reason,
 			   f.size, f.mod_time, f.content_hash, f.metadata_hash,
-			   f.summary, f.complexity, f.analyzed_at, f.analysis_version
+			   f.summary, f.complexity, f.analyzed_at, f.analysis_version,
+			   f.change_reason
 	`
-	result, err := g.query(query)
+	result, err := g.readQuery(query)
 	if err != nil {
 		return nil, err
 	}
@@ -1496,12 +3352,36 @@ func (g *FalkorDBGraph) exportFiles(ctx context.Context) ([]FileNode, error) {
 	return files, nil
 }
 
-func (g *FalkorDBGraph) exportDirectories(ctx context.Context) ([]DirectoryNode, error) {
+func (g *FalkorDBGraph) exportChunks(ctx context.Context, since time.Time) ([]ChunkNode, error) {
+	result, err := g.readQuery(exportChunksQuery(since))
+	if err != nil {
+		return nil, err
+	}
+
+	var chunks []ChunkNode
+	for result.Next() {
+		record := result.Record()
+		chunks = append(chunks, ChunkNode{
+			ID:          getStringFromRecord(record, 0),
+			FilePath:    getStringFromRecord(record, 1),
+			Index:       getIntFromRecord(record, 2),
+			ContentHash: getStringFromRecord(record, 3),
+			StartOffset: getIntFromRecord(record, 4),
+			EndOffset:   getIntFromRecord(record, 5),
+			ChunkType:   getStringFromRecord(record, 6),
+			Content:     getStringFromRecord(record, 7),
+		})
+	}
+
+	return chunks, nil
+}
+
+func (g *FalkorDBGraph) exportDirectories(ctx context.Context, since time.Time) ([]DirectoryNode, error) {
 	query := `
-		MATCH (d:Directory)
+		MATCH (d:Directory)` + updatedSince("d", since) + `
 		RETURN d.path, d.name, d.is_remembered, d.file_count
 	`
-	result, err := g.query(query)
+	result, err := g.readQuery(query)
 	if err != nil {
 		return nil, err
 	}
@@ -1520,12 +3400,9 @@ func (g *FalkorDBGraph) exportDirectories(ctx context.Context) ([]DirectoryNode,
 	return dirs, nil
 }
 
-func (g *FalkorDBGraph) exportTags(ctx context.Context) ([]TagNode, error) {
-	query := `
-		MATCH (t:Tag)
-		RETURN t.name, t.normalized_name, t.usage_count
-	`
-	result, err := g.query(query)
+func (g *FalkorDBGraph) exportTags(ctx context.Context, since time.Time) ([]TagNode, error) {
+	query := exportLinkedQuery(LabelTag, RelHasTag, "n.name, n.normalized_name, n.usage_count", since)
+	result, err := g.readQuery(query)
 	if err != nil {
 		return nil, err
 	}
@@ -1543,12 +3420,9 @@ func (g *FalkorDBGraph) exportTags(ctx context.Context) ([]TagNode, error) {
 	return tags, nil
 }
 
-func (g *FalkorDBGraph) exportTopics(ctx context.Context) ([]TopicNode, error) {
-	query := `
-		MATCH (t:Topic)
-		RETURN t.name, t.normalized_name, t.usage_count
-	`
-	result, err := g.query(query)
+func (g *FalkorDBGraph) exportTopics(ctx context.Context, since time.Time) ([]TopicNode, error) {
+	query := exportLinkedQuery(LabelTopic, RelCoversTopic, "n.name, n.normalized_name, n.usage_count", since)
+	result, err := g.readQuery(query)
 	if err != nil {
 		return nil, err
 	}
@@ -1566,12 +3440,9 @@ func (g *FalkorDBGraph) exportTopics(ctx context.Context) ([]TopicNode, error) {
 	return topics, nil
 }
 
-func (g *FalkorDBGraph) exportEntities(ctx context.Context) ([]EntityNode, error) {
-	query := `
-		MATCH (e:Entity)
-		RETURN e.name, e.type, e.normalized_name, e.usage_count
-	`
-	result, err := g.query(query)
+func (g *FalkorDBGraph) exportEntities(ctx context.Context, since time.Time) ([]EntityNode, error) {
+	query := exportLinkedQuery(LabelEntity, RelMentions, "n.name, n.type, n.normalized_name, n.usage_count", since)
+	result, err := g.readQuery(query)
 	if err != nil {
 		return nil, err
 	}
@@ -1590,11 +3461,58 @@ func (g *FalkorDBGraph) exportEntities(ctx context.Context) ([]EntityNode, error
 	return entities, nil
 }
 
-func (g *FalkorDBGraph) countNodes(ctx context.Context, label string) (int, error) {
-	query := fmt.Sprintf("MATCH (n:%s) RETURN count(n)", label)
-	result, err := g.query(query)
+// updatedSince returns a WHERE clause matching alias nodes updated at or after
+// since, or an empty string when since is zero.
+func updatedSince(alias string, since time.Time) string {
+	if since.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf(" WHERE %s.updated_at >= %d", alias, since.Unix())
+}
+
+// exportLinkedQuery builds the export query for label nodes. Delta exports only
+// include nodes linked via rel from files updated at or after since.
+func exportLinkedQuery(label, rel, returns string, since time.Time) string {
+	if since.IsZero() {
+		return fmt.Sprintf("MATCH (n:%s) RETURN %s", label, returns)
+	}
+	return fmt.Sprintf("MATCH (f:File)-[:%s]->(n:%s)%s RETURN DISTINCT %s",
+		rel, label, updatedSince("f", since), returns)
+}
+
+// exportChunkCountQuery counts chunks updated at or after since.
+func exportChunkCountQuery(since time.Time) string {
+	return fmt.Sprintf("MATCH (c:%s)%s RETURN count(c)", LabelChunk, updatedSince("c", since))
+}
+
+// exportChunksQuery selects the chunks that have stored content, or for delta
+// exports those updated at or after since.
+func exportChunksQuery(since time.Time) string {
+	where := " WHERE c.content IS NOT NULL"
+	if !since.IsZero() {
+		where = updatedSince("c", since) + " AND c.content IS NOT NULL"
+	}
+	return fmt.Sprintf(`
+		MATCH (c:%s)%s
+		RETURN c.id, c.file_path, c.index, c.content_hash,
+			   c.start_offset, c.end_offset, c.chunk_type, c.content
+		ORDER BY c.file_path, c.index
+	`, LabelChunk, where)
+}
+
+// exportRelationshipCountQuery counts all relationships, or for delta exports
+// the relationships of files updated at or after since.
+func exportRelationshipCountQuery(since time.Time) string {
+	if since.IsZero() {
+		return "MATCH ()-[r]->() RETURN count(r)"
+	}
+	return fmt.Sprintf("MATCH (f:File)-[r]->()%s RETURN count(r)", updatedSince("f", since))
+}
+
+// count runs a query returning a single count.
+func (g *FalkorDBGraph) count(ctx context.Context, query string) (int, error) {
+	result, err := g.readQuery(query)
 	if err != nil {
-		g.signalFatal(err)
 		return 0, err
 	}
 
@@ -1605,9 +3523,11 @@ func (g *FalkorDBGraph) countNodes(ctx context.Context, label string) (int, erro
 	return 0, nil
 }
 
-func (g *FalkorDBGraph) countRelationships(ctx context.Context) (int, error) {
-	result, err := g.query("MATCH ()-[r]->() RETURN count(r)")
+func (g *FalkorDBGraph) countNodes(ctx context.Context, label string) (int, error) {
+	query := fmt.Sprintf("MATCH (n:%s) RETURN count(n)", label)
+	result, err := g.query(query)
 	if err != nil {
+		g.signalFatal(err)
 		return 0, err
 	}
 
@@ -1692,6 +3612,7 @@ func parseFileFromRecord(record *redisgraph.Record) (*FileNode, error) {
 		Complexity:      getIntFromRecord(record, 13),
 		AnalyzedAt:      time.Unix(int64(getIntFromRecord(record, 14)), 0),
 		AnalysisVersion: getIntFromRecord(record, 15),
+		ChangeReason:    getStringFromRecord(record, 16),
 	}
 
 	return file, nil
@@ -1758,7 +3679,8 @@ func getBoolFromRecord(record *redisgraph.Record, index int) bool {
 	return false
 }
 
-// escapeString escapes single quotes for Cypher queries.
+// escapeString escapes single quotes for Cypher queries. It does not handle
+// control characters; pass values as parameters (see parameterized) instead.
 func escapeString(s string) string {
 	result := ""
 	for _, c := range s {
@@ -1,9 +1,37 @@
 package graph
 
 import (
+	"bufio"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/RedisGraph/redisgraph-go"
+
+	"github.com/leefowlercu/agentic-memorizer/internal/chunkers"
+	"github.com/leefowlercu/agentic-memorizer/internal/events"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -178,6 +206,9 @@ func TestRelationshipTypes(t *testing.T) {
 	if RelReferences != "REFERENCES" {
 		t.Errorf("RelReferences = %q, want %q", RelReferences, "REFERENCES")
 	}
+	if RelReferencesSection != "REFERENCES_SECTION" {
+		t.Errorf("RelReferencesSection = %q, want %q", RelReferencesSection, "REFERENCES_SECTION")
+	}
 	if RelSimilarTo != "SIMILAR_TO" {
 		t.Errorf("RelSimilarTo = %q, want %q", RelSimilarTo, "SIMILAR_TO")
 	}
@@ -458,6 +489,55 @@ func TestOperationsWithoutConnection(t *testing.T) {
 		}
 	})
 
+	t.Run("GetBacklinks", func(t *testing.T) {
+		_, err := g.GetBacklinks(context.TODO(), "/test")
+		if err == nil {
+			t.Error("Expected error when not connected")
+		}
+	})
+
+	t.Run("GetReferences", func(t *testing.T) {
+		_, err := g.GetReferences(context.TODO(), "/test")
+		if err == nil {
+			t.Error("Expected error when not connected")
+		}
+	})
+
+	t.Run("SetChunkSectionReferences", func(t *testing.T) {
+		err := g.SetChunkSectionReferences(context.TODO(), "chunk", []string{"other"}, nil)
+		if err == nil {
+			t.Error("Expected error when not connected")
+		}
+	})
+
+	t.Run("SetFileRelations", func(t *testing.T) {
+		err := g.SetFileRelations(context.TODO(), "/test", []EntityRelation{{Subject: "Alice", Predicate: "WORKS_AT", Object: "Acme"}})
+		if err == nil {
+			t.Error("Expected error when not connected")
+		}
+	})
+
+	t.Run("CountFilesByIngestReason", func(t *testing.T) {
+		_, err := g.CountFilesByIngestReason(context.TODO())
+		if err == nil {
+			t.Error("Expected error when not connected")
+		}
+	})
+
+	t.Run("GetDirectoryTree", func(t *testing.T) {
+		_, err := g.GetDirectoryTree(context.TODO(), "/test", 0)
+		if err == nil {
+			t.Error("Expected error when not connected")
+		}
+	})
+
+	t.Run("TopTags", func(t *testing.T) {
+		_, err := g.TopTags(context.TODO(), 10)
+		if err == nil {
+			t.Error("Expected error when not connected")
+		}
+	})
+
 	t.Run("ExportSnapshot", func(t *testing.T) {
 		_, err := g.ExportSnapshot(context.TODO())
 		if err == nil {
@@ -465,6 +545,13 @@ func TestOperationsWithoutConnection(t *testing.T) {
 		}
 	})
 
+	t.Run("DeleteFileEmbeddings", func(t *testing.T) {
+		err := g.DeleteFileEmbeddings(context.TODO(), "/test", "", "")
+		if err == nil {
+			t.Error("Expected error when not connected")
+		}
+	})
+
 	t.Run("DeleteFilesUnderPath", func(t *testing.T) {
 		err := g.DeleteFilesUnderPath(context.TODO(), "/test")
 		if err == nil {
@@ -789,3 +876,1940 @@ func TestChunkEmbeddingMultipleProviders(t *testing.T) {
 		t.Error("Dimensions should be different for this test")
 	}
 }
+
+func TestLinkQueries(t *testing.T) {
+	t.Run("Backlinks", func(t *testing.T) {
+		query := backlinksQuery("/docs/it's.md")
+		if !strings.Contains(query, "(f:File)-[:REFERENCES|IMPORTS]->(t:File {path: '/docs/it\\'s.md'})") {
+			t.Errorf("backlinks query does not match incoming links:\n%s", query)
+		}
+		if !strings.Contains(query, "RETURN DISTINCT f.path") {
+			t.Errorf("backlinks query does not return referencing files:\n%s", query)
+		}
+	})
+
+	t.Run("SetFileReferencesWritesBacklinkEdge", func(t *testing.T) {
+		g, _ := newReplicatedTestGraph()
+		ctx, tx := WithTransaction(context.Background())
+		refs := []Reference{{Type: "file", Target: "/docs/b.md"}, {Type: "url", Target: "https://example.com"}}
+		if err := g.SetFileReferences(ctx, "/docs/a.md", refs); err != nil {
+			t.Fatalf("SetFileReferences failed: %v", err)
+		}
+
+		// The old edges are removed and only file references are linked
+		if tx.Len() != 2 {
+			t.Fatalf("transaction writes = %d, want a reset and one link", tx.Len())
+		}
+		link := tx.queries[1]
+		if !strings.HasPrefix(link, `CYPHER path="/docs/a.md" target="/docs/b.md" `) {
+			t.Errorf("reference query should bind the source and target paths:\n%s", link)
+		}
+
+		// The edge written is the one backlinksQuery matches
+		if !strings.Contains(link, "MERGE (f)-[:REFERENCES {type: 'file'}]->(t)") {
+			t.Errorf("reference query does not write a REFERENCES edge:\n%s", link)
+		}
+		if !strings.Contains(backlinksQuery("/docs/b.md"), "-[:REFERENCES|IMPORTS]->(t:File {path: '/docs/b.md'})") {
+			t.Error("backlinks query does not follow REFERENCES edges to the target")
+		}
+	})
+
+	t.Run("References", func(t *testing.T) {
+		query := referencesQuery("/docs/a.md")
+		if !strings.Contains(query, "(f:File {path: '/docs/a.md'})-[r:REFERENCES|IMPORTS]->(t:File)") {
+			t.Errorf("references query does not match outgoing links:\n%s", query)
+		}
+		if !strings.Contains(query, "t.path") {
+			t.Errorf("references query does not return targets:\n%s", query)
+		}
+	})
+}
+
+func TestDirectoryTreeQuery(t *testing.T) {
+	query := directoryTreeQuery("/docs/it's")
+	for _, want := range []string{
+		"d.path = '/docs/it\\'s' OR d.path STARTS WITH '/docs/it\\'s/'",
+		"OPTIONAL MATCH (d)-[:CONTAINS]->(f:File)",
+		"RETURN d.path, d.name, d.is_remembered, f.path, f.name, f.summary",
+	} {
+		if !strings.Contains(query, want) {
+			t.Errorf("directory tree query missing %q:\n%s", want, query)
+		}
+	}
+}
+
+func TestBuildDirectoryTree(t *testing.T) {
+	rows := []directoryTreeRow{
+		{dirPath: "/root", dirName: "root", isRemembered: true, filePath: "/root/a.md", fileName: "a.md", fileSummary: "Top level"},
+		{dirPath: "/root", dirName: "root", isRemembered: true, filePath: "/root/b.md", fileName: "b.md"},
+		{dirPath: "/root/docs", dirName: "docs", filePath: "/root/docs/c.md", fileName: "c.md", fileSummary: "Nested"},
+		{dirPath: "/root/docs/deep", dirName: "deep", filePath: "/root/docs/deep/d.md", fileName: "d.md"},
+		{dirPath: "/root/empty", dirName: "empty"},
+	}
+
+	t.Run("Unbounded", func(t *testing.T) {
+		tree := buildDirectoryTree("/root", 0, rows)
+		if tree.Path != "/root" || !tree.IsRemembered {
+			t.Fatalf("root = %+v, want remembered /root", tree)
+		}
+		if tree.FileCount != 2 || len(tree.Files) != 2 || tree.Files[0].Summary != "Top level" {
+			t.Errorf("root files = %+v, want a.md and b.md", tree.Files)
+		}
+		if len(tree.Children) != 2 {
+			t.Fatalf("root children = %d, want 2", len(tree.Children))
+		}
+
+		docs := tree.Children[0]
+		if docs.Path != "/root/docs" || docs.IsRemembered || docs.FileCount != 1 {
+			t.Errorf("docs = %+v, want /root/docs with one file", docs)
+		}
+		if len(docs.Children) != 1 || docs.Children[0].Files[0].Path != "/root/docs/deep/d.md" {
+			t.Errorf("docs children = %+v, want deep with d.md", docs.Children)
+		}
+
+		empty := tree.Children[1]
+		if empty.Path != "/root/empty" || empty.FileCount != 0 || len(empty.Files) != 0 {
+			t.Errorf("empty = %+v, want no files", empty)
+		}
+	})
+
+	t.Run("MaxDepth", func(t *testing.T) {
+		tree := buildDirectoryTree("/root", 1, rows)
+		if len(tree.Children) != 2 {
+			t.Fatalf("root children = %d, want 2", len(tree.Children))
+		}
+		if docs := tree.Children[0]; len(docs.Children) != 0 || docs.FileCount != 1 {
+			t.Errorf("docs = %+v, want files but no children beyond depth 1", docs)
+		}
+	})
+
+	t.Run("MissingIntermediateDirectory", func(t *testing.T) {
+		tree := buildDirectoryTree("/root/", 0, []directoryTreeRow{
+			{dirPath: "/root/a/b", dirName: "b", filePath: "/root/a/b/x.go", fileName: "x.go"},
+		})
+		if len(tree.Children) != 1 || tree.Children[0].Name != "a" {
+			t.Fatalf("root children = %+v, want synthesized a", tree.Children)
+		}
+		if b := tree.Children[0].Children; len(b) != 1 || b[0].FileCount != 1 {
+			t.Errorf("a children = %+v, want b with one file", b)
+		}
+	})
+}
+
+// stubQuerier records queries and returns result, or empty results when nil.
+type stubQuerier struct {
+	queries []string
+	err     error
+	result  *redisgraph.QueryResult
+
+	// transactions records the query sets committed with Exec
+	transactions [][]string
+	execErr      error
+}
+
+func (s *stubQuerier) Exec(queries []string) error {
+	s.transactions = append(s.transactions, queries)
+	return s.execErr
+}
+
+func (s *stubQuerier) Query(q string) (*redisgraph.QueryResult, error) {
+	s.queries = append(s.queries, q)
+	if s.err != nil {
+		return nil, s.err
+	}
+	if s.result != nil {
+		return s.result, nil
+	}
+	return &redisgraph.QueryResult{}, nil
+}
+
+// scalarQueryResult builds a query result of string, integer and float columns in the
+// wire format FalkorDB returns.
+func scalarQueryResult(t *testing.T, columns []string, rows [][]any) *redisgraph.QueryResult {
+	t.Helper()
+
+	header := make([]any, len(columns))
+	for i, name := range columns {
+		header[i] = []any{int64(redisgraph.COLUMN_SCALAR), name}
+	}
+	records := make([]any, len(rows))
+	for i, row := range rows {
+		cells := make([]any, len(row))
+		for j, v := range row {
+			cells[j] = scalarCell(t, v)
+		}
+		records[i] = cells
+	}
+	stats := []any{"Query internal execution time: 0.1 milliseconds"}
+
+	result, err := redisgraph.QueryResultNew(nil, []any{header, records, stats})
+	if err != nil {
+		t.Fatalf("QueryResultNew failed: %v", err)
+	}
+	return result
+}
+
+// scalarCell encodes v as a compact-protocol scalar cell.
+func scalarCell(t *testing.T, v any) []any {
+	t.Helper()
+
+	switch v := v.(type) {
+	case nil:
+		return []any{int64(redisgraph.VALUE_NULL), nil}
+	case string:
+		return []any{int64(redisgraph.VALUE_STRING), v}
+	case int:
+		return []any{int64(redisgraph.VALUE_INTEGER), int64(v)}
+	case bool:
+		return []any{int64(redisgraph.VALUE_BOOLEAN), []byte(strconv.FormatBool(v))}
+	case float64:
+		return []any{int64(redisgraph.VALUE_DOUBLE), []byte(strconv.FormatFloat(v, 'f', -1, 64))}
+	case []any:
+		items := make([]any, len(v))
+		for i, item := range v {
+			items[i] = scalarCell(t, item)
+		}
+		return []any{int64(redisgraph.VALUE_ARRAY), items}
+	default:
+		t.Fatalf("unsupported cell type %T", v)
+		return nil
+	}
+}
+
+func newReplicatedTestGraph(replicas ...*stubQuerier) (*FalkorDBGraph, *stubQuerier) {
+	primary := &stubQuerier{}
+	g := NewFalkorDBGraph()
+	g.primary = primary
+	g.transactor = primary
+	g.connected = true
+	for i, r := range replicas {
+		g.replicas = append(g.replicas, &replicaConn{addr: fmt.Sprintf("replica-%d:6379", i), querier: r})
+	}
+	return g, primary
+}
+
+func TestReadReplicaRouting(t *testing.T) {
+	t.Run("ReadsUseReplicasRoundRobin", func(t *testing.T) {
+		r1, r2 := &stubQuerier{}, &stubQuerier{}
+		g, primary := newReplicatedTestGraph(r1, r2)
+
+		for range 2 {
+			if _, err := g.GetFile(context.TODO(), "/a.go"); err != nil {
+				t.Fatalf("GetFile failed: %v", err)
+			}
+		}
+		if _, err := g.Query(context.TODO(), "MATCH (f:File) RETURN f.path"); err != nil {
+			t.Fatalf("Query failed: %v", err)
+		}
+
+		if len(primary.queries) != 0 {
+			t.Errorf("primary queries = %d, want 0", len(primary.queries))
+		}
+		if len(r1.queries) != 2 || len(r2.queries) != 1 {
+			t.Errorf("replica queries = %d, %d; want 2, 1", len(r1.queries), len(r2.queries))
+		}
+	})
+
+	t.Run("WritesUsePrimary", func(t *testing.T) {
+		replica := &stubQuerier{}
+		g, primary := newReplicatedTestGraph(replica)
+
+		if _, err := g.Query(context.TODO(), "MATCH (f:File {path: '/a.go'}) SET f.summary = 'x'"); err != nil {
+			t.Fatalf("Query failed: %v", err)
+		}
+		if _, err := g.Query(context.TODO(), "CALL db.indexes()"); err != nil {
+			t.Fatalf("Query failed: %v", err)
+		}
+
+		if len(primary.queries) != 2 {
+			t.Errorf("primary queries = %d, want 2", len(primary.queries))
+		}
+		if len(replica.queries) != 0 {
+			t.Errorf("replica queries = %d, want 0", len(replica.queries))
+		}
+	})
+
+	t.Run("ReplicaFailureFallsBackToPrimary", func(t *testing.T) {
+		replica := &stubQuerier{err: errors.New("replica down")}
+		g, primary := newReplicatedTestGraph(replica)
+
+		if _, err := g.GetFile(context.TODO(), "/a.go"); err != nil {
+			t.Fatalf("GetFile failed: %v", err)
+		}
+		if len(replica.queries) != 1 || len(primary.queries) != 1 {
+			t.Errorf("replica, primary queries = %d, %d; want 1, 1", len(replica.queries), len(primary.queries))
+		}
+	})
+}
+
+func TestIsReadOnlyCypher(t *testing.T) {
+	tests := []struct {
+		cypher string
+		want   bool
+	}{
+		{"MATCH (f:File) RETURN f.path ORDER BY f.path", true},
+		{"MATCH (c:Chunk) RETURN c.start_offset, c.reset_count", true},
+		{"CREATE (n:Tag {name: 'x'})", false},
+		{"match (f:File) detach delete f", false},
+		{"MERGE (t:Tag {name: 'x'})", false},
+		{"MATCH (f:File) REMOVE f.summary", false},
+		{"CALL db.idx.vector.queryNodes('Chunk', 'embedding', 5, vecf32([1]))", false},
+		{"MATCH (f:File {summary: 'set up'}) RETURN f", false},
+	}
+	for _, tt := range tests {
+		if got := isReadOnlyCypher(tt.cypher); got != tt.want {
+			t.Errorf("isReadOnlyCypher(%q) = %v, want %v", tt.cypher, got, tt.want)
+		}
+	}
+}
+
+func TestChunkSectionReferencesQuery(t *testing.T) {
+	query := chunkSectionReferencesQuery("from", []string{"to-1", "to-2"}, []string{"it's-missing"})
+	for _, want := range []string{
+		"MATCH (c:Chunk {id: 'from'})",
+		"(c)-[old:REFERENCES_SECTION]->()",
+		"SET c.unmatched_anchors = ['it\\'s-missing']",
+		"WHERE t.id IN ['to-1','to-2']",
+		"MERGE (c)-[:REFERENCES_SECTION]->(t)",
+	} {
+		if !strings.Contains(query, want) {
+			t.Errorf("section references query missing %q:\n%s", want, query)
+		}
+	}
+}
+
+func TestEmbeddingQueries(t *testing.T) {
+	emb := &ChunkEmbeddingNode{
+		Provider:   "openai",
+		Model:      "text-embedding-3-small",
+		Dimensions: 2,
+		Embedding:  []float32{0.1, 0.2},
+		Version:    3,
+	}
+
+	upsert := upsertChunkEmbeddingQuery("hash-1", emb, time.Unix(100, 0))
+	for _, want := range []string{
+		"MATCH (c:Chunk {id: 'hash-1'})",
+		"(c)-[:HAS_EMBEDDING]->(e:ChunkEmbedding {provider: 'openai', model: 'text-embedding-3-small'})",
+		"e.embedding = [0.100000,0.200000]",
+		"e.version = 3",
+	} {
+		if !strings.Contains(upsert, want) {
+			t.Errorf("upsert query missing %q:\n%s", want, upsert)
+		}
+	}
+
+	// HasEmbedding must read the relationship and properties the upsert writes.
+	has := hasEmbeddingQuery("hash-1", 3)
+	for _, want := range []string{
+		"(c:Chunk {content_hash: 'hash-1'})-[:HAS_EMBEDDING]->(e:ChunkEmbedding {version: 3})",
+		"e.embedding IS NOT NULL",
+	} {
+		if !strings.Contains(has, want) {
+			t.Errorf("has-embedding query missing %q:\n%s", want, has)
+		}
+	}
+	if strings.Contains(has, "embedding_version") || strings.Contains(has, "c.embedding") {
+		t.Errorf("has-embedding query reads chunk properties that are never written:\n%s", has)
+	}
+
+	// GetEmbedding must read the same nodes HasEmbedding counts.
+	get := getEmbeddingQuery("hash-'1'", "openai", "text-embedding-3-small", 3)
+	if !strings.HasPrefix(get, `CYPHER content_hash="hash-'1'" model="text-embedding-3-small" provider="openai" version=3 `) {
+		t.Errorf("get-embedding query should bind the content hash, model and version as parameters:\n%s", get)
+	}
+	for _, want := range []string{
+		"(c:Chunk {content_hash: $content_hash})-[:HAS_EMBEDDING]->(e:ChunkEmbedding {provider: $provider, model: $model, version: $version})",
+		"RETURN e.embedding",
+		"LIMIT 1",
+	} {
+		if !strings.Contains(get, want) {
+			t.Errorf("get-embedding query missing %q:\n%s", want, get)
+		}
+	}
+}
+
+func TestDeleteFileEmbeddingsQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		model    string
+		want     string
+	}{
+		{"All", "", "", "(e:ChunkEmbedding)"},
+		{"Provider", "openai", "", "(e:ChunkEmbedding {provider: 'openai'})"},
+		{"ProviderModel", "openai", "text-embedding-3-small", "(e:ChunkEmbedding {provider: 'openai', model: 'text-embedding-3-small'})"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := deleteFileEmbeddingsQuery("/src/it's.go", tt.provider, tt.model)
+
+			// One query covers every chunk of the file
+			if !strings.Contains(query, "MATCH (c:Chunk {file_path: '/src/it\\'s.go'})-[:HAS_EMBEDDING]->"+tt.want) {
+				t.Errorf("query does not match all file chunk embeddings:\n%s", query)
+			}
+			if strings.Contains(query, "{id:") {
+				t.Errorf("query should not be scoped to a single chunk:\n%s", query)
+			}
+			if !strings.Contains(query, "DETACH DELETE e") || strings.Contains(query, "DELETE c") {
+				t.Errorf("query should delete embeddings only:\n%s", query)
+			}
+		})
+	}
+}
+
+func TestFileRelationQuery(t *testing.T) {
+	query := fileRelationQuery("/docs/team.md", EntityRelation{Subject: "Alice", Predicate: "WORKS_AT", Object: "Acme's"})
+
+	if !strings.HasPrefix(query, `CYPHER object="acme's" path="/docs/team.md" predicate="WORKS_AT" subject="alice" `) {
+		t.Errorf("query does not bind the relation parameters:\n%s", query)
+	}
+	if !strings.Contains(query, "MATCH (s:Entity {normalized_name: $subject}), (o:Entity {normalized_name: $object})") {
+		t.Errorf("query does not match subject and object entities:\n%s", query)
+	}
+	if !strings.Contains(query, "MERGE (s)-[:RELATED {predicate: $predicate, file: $path}]->(o)") {
+		t.Errorf("query does not create the relation edge:\n%s", query)
+	}
+	if strings.Contains(query, "MERGE (s:Entity") || strings.Contains(query, "MERGE (o:Entity") {
+		t.Errorf("query should not create entity nodes:\n%s", query)
+	}
+}
+
+func TestDeleteFileRemovesRelations(t *testing.T) {
+	tests := []struct {
+		name   string
+		delete func(g *FalkorDBGraph) error
+		want   string
+	}{
+		{
+			name:   "DeleteFile",
+			delete: func(g *FalkorDBGraph) error { return g.DeleteFile(context.Background(), "/docs/team.md") },
+			want:   `CYPHER path="/docs/team.md" `,
+		},
+		{
+			name:   "DeleteFilesUnderPath",
+			delete: func(g *FalkorDBGraph) error { return g.DeleteFilesUnderPath(context.Background(), "/docs") },
+			want:   `CYPHER prefix="/docs/" `,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, primary := newReplicatedTestGraph()
+
+			done := make(chan error, 1)
+			go func() { done <- tt.delete(g) }()
+			for range 3 {
+				g.executeWrite(<-g.writeQueue)
+			}
+			if err := <-done; err != nil {
+				t.Fatalf("%s failed: %v", tt.name, err)
+			}
+
+			var relationQuery string
+			for _, q := range primary.queries {
+				if strings.Contains(q, "[r:RELATED") {
+					relationQuery = q
+				}
+			}
+			if relationQuery == "" {
+				t.Fatalf("no query removes RELATED edges:\n%s", strings.Join(primary.queries, "\n"))
+			}
+			if !strings.HasPrefix(relationQuery, tt.want) || !strings.Contains(relationQuery, "DELETE r") {
+				t.Errorf("relation query does not delete the file's edges:\n%s", relationQuery)
+			}
+		})
+	}
+}
+
+func TestUpsertFileQueryParameters(t *testing.T) {
+	paths := []string{
+		`/docs/it's "quoted".md`,
+		`C:\notes\new.md`,
+		`/docs/literal\n.md`,
+		"/docs/line\nbreak.md",
+		"/docs/日本語 ☃.md",
+		"/docs/x'}) DETACH DELETE f //.md",
+	}
+
+	for _, path := range paths {
+		t.Run(path, func(t *testing.T) {
+			file := &FileNode{Path: path, Name: filepath.Base(path), Summary: "it's a \"summary\"", Size: 42}
+			query := upsertFileQuery(file, 1700000000)
+
+			header, body, ok := strings.Cut(query, "\n")
+			if !ok {
+				t.Fatalf("query has no parameter header:\n%s", query)
+			}
+			if !strings.Contains(header, "path="+quoteCypherString(path)+" ") {
+				t.Errorf("header does not bind path %q:\n%s", path, header)
+			}
+			if !strings.Contains(header, "size=42 ") || !strings.Contains(header, "updated_at=1700000000 ") {
+				t.Errorf("header does not bind numeric parameters:\n%s", header)
+			}
+			if strings.Contains(body, path) || strings.Contains(body, "summary\"") {
+				t.Errorf("query body contains a parameter value:\n%s", body)
+			}
+			if !strings.Contains(body, "MERGE (f:File {path: $path})") {
+				t.Errorf("query does not merge on $path:\n%s", body)
+			}
+
+			dirQuery := fileDirectoryQuery(path, 1700000000)
+			if !strings.Contains(dirQuery, "dir_path="+quoteCypherString(filepath.Dir(path))+" ") {
+				t.Errorf("directory query does not bind the parent path:\n%s", dirQuery)
+			}
+		})
+	}
+}
+
+func TestChunkMetaQuery(t *testing.T) {
+	query := chunkMetaQuery("chunk-'1'", "HAS_DOC_META", "DocumentMeta", map[string]any{
+		"heading":       "Café \\ \"notes\"",
+		"heading_level": 2,
+		"is_table":      true,
+	})
+
+	wantHeader := `CYPHER chunk_id="chunk-'1'" heading="Café \\ \"notes\"" heading_level=2 is_table=true `
+	if !strings.HasPrefix(query, wantHeader) {
+		t.Errorf("query header = %q, want prefix %q", query, wantHeader)
+	}
+	if !strings.Contains(query, "MATCH (c:Chunk {id: $chunk_id})") || !strings.Contains(query, "MERGE (c)-[:HAS_DOC_META]->(m:DocumentMeta)") {
+		t.Errorf("query does not merge the metadata node:\n%s", query)
+	}
+	for _, set := range []string{"m.heading = $heading", "m.heading_level = $heading_level", "m.is_table = $is_table"} {
+		if !strings.Contains(query, set) {
+			t.Errorf("query missing %q:\n%s", set, query)
+		}
+	}
+}
+
+func TestUpsertChunksBatch(t *testing.T) {
+	const n = 300
+	chunks := make([]*ChunkNode, n)
+	metas := make([]*chunkers.ChunkMetadata, n)
+	for i := range chunks {
+		chunks[i] = &ChunkNode{ID: fmt.Sprintf("chunk-%d", i), FilePath: "/docs/it's.md", Index: i}
+		metas[i] = &chunkers.ChunkMetadata{Document: &chunkers.DocumentMetadata{Heading: fmt.Sprintf("Section %d", i)}}
+	}
+	metas[0] = &chunkers.ChunkMetadata{Code: &chunkers.CodeMetadata{Language: "go"}}
+	metas[1] = nil
+
+	// Writes are not executed without a running queue processor, so the
+	// queue length counts the operations each approach issues
+	single, _ := newReplicatedTestGraph()
+	for i, chunk := range chunks {
+		if err := single.UpsertChunkWithMetadata(context.Background(), chunk, metas[i]); err != nil {
+			t.Fatalf("UpsertChunkWithMetadata failed: %v", err)
+		}
+	}
+	batched, _ := newReplicatedTestGraph()
+	if err := batched.UpsertChunksBatch(context.Background(), chunks, metas); err != nil {
+		t.Fatalf("UpsertChunksBatch failed: %v", err)
+	}
+
+	// 300 chunks: 899 queued writes one chunk at a time (two per chunk plus a
+	// metadata write for all but one), 5 batched (two UNWIND batches, each with
+	// a chunk write and a DocumentMeta write, plus one CodeMeta write)
+	if got := len(single.writeQueue); got != 3*n-1 {
+		t.Errorf("per-chunk writes = %d, want %d", got, 3*n-1)
+	}
+	if got := len(batched.writeQueue); got != 5 {
+		t.Errorf("batched writes = %d, want 5", got)
+	}
+
+	var queries []string
+	for len(batched.writeQueue) > 0 {
+		queries = append(queries, (<-batched.writeQueue).query)
+	}
+	if !strings.Contains(queries[0], "UNWIND $chunks AS row") || !strings.Contains(queries[0], "MERGE (f)-[:HAS_CHUNK]->(c)") {
+		t.Errorf("first query does not upsert chunks and their file relationship:\n%s", queries[0])
+	}
+	if !strings.Contains(queries[0], `file_path: "/docs/it's.md"`) || strings.Contains(queries[0], "chunk-200") {
+		t.Error("first query should bind the first chunkBatchSize chunks as parameters")
+	}
+	if !strings.Contains(queries[1], "MERGE (c)-[:HAS_CODE_META]->(m:CodeMeta)") || !strings.Contains(queries[1], "m.language = row.language") {
+		t.Errorf("second query does not upsert code metadata:\n%s", queries[1])
+	}
+	if !strings.Contains(queries[2], "MERGE (c)-[:HAS_DOC_META]->(m:DocumentMeta)") || strings.Contains(queries[2], `chunk_id: "chunk-1"`) {
+		t.Errorf("third query should upsert document metadata for chunks that have it:\n%.300s", queries[2])
+	}
+}
+
+func TestChunkContentStorage(t *testing.T) {
+	chunk := &ChunkNode{ID: "chunk-1", FilePath: "/docs/a.md", Content: "It's \"stored\"\ntext"}
+
+	if query := upsertChunkQuery(chunk, 1700000000, false); !strings.Contains(query, "content=null ") {
+		t.Errorf("content should not be stored by default:\n%s", query)
+	}
+	query := upsertChunkQuery(chunk, 1700000000, true)
+	if !strings.Contains(query, `content="It's \"stored\"\ntext" `) || !strings.Contains(query, "c.content = $content") {
+		t.Errorf("content should be stored when enabled:\n%s", query)
+	}
+	if query := upsertChunkQuery(&ChunkNode{ID: "chunk-2"}, 1700000000, true); !strings.Contains(query, "content=null ") {
+		t.Errorf("empty content should not be stored:\n%s", query)
+	}
+
+	batch := upsertChunksBatchQueries([]*ChunkNode{chunk}, nil, 1700000000, true)
+	if len(batch) != 1 || !strings.Contains(batch[0], `content: "It's`) || !strings.Contains(batch[0], "c.content = row.content") {
+		t.Errorf("batched chunks should store content when enabled:\n%v", batch)
+	}
+}
+
+func TestExportChunksQuery(t *testing.T) {
+	full := exportChunksQuery(time.Time{})
+	if !strings.Contains(full, "MATCH (c:Chunk) WHERE c.content IS NOT NULL") {
+		t.Errorf("full export should select chunks with content:\n%s", full)
+	}
+	delta := exportChunksQuery(time.Unix(1700000000, 0))
+	if !strings.Contains(delta, "WHERE c.updated_at >= 1700000000 AND c.content IS NOT NULL") {
+		t.Errorf("delta export should be bounded by since:\n%s", delta)
+	}
+}
+
+func TestUpsertChunkEmbeddingDimensionMismatch(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.EmbeddingDimension = 4
+	g := NewFalkorDBGraph(WithConfig(cfg))
+
+	emb := &ChunkEmbeddingNode{
+		Provider:   "ollama",
+		Model:      "nomic-embed-text",
+		Dimensions: 2,
+		Embedding:  []float32{0.1, 0.2},
+	}
+
+	err := g.UpsertChunkEmbedding(context.TODO(), "hash-1", emb)
+	if !errors.Is(err, ErrEmbeddingDimensionMismatch) {
+		t.Fatalf("UpsertChunkEmbedding() error = %v, want ErrEmbeddingDimensionMismatch", err)
+	}
+	for _, want := range []string{"ollama/nomic-embed-text", "2 dimensions", "expects 4"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q missing %q", err, want)
+		}
+	}
+
+	// Declared dimensions must also agree with the vector length
+	emb.Dimensions = 4
+	if err := g.UpsertChunkEmbedding(context.TODO(), "hash-1", emb); !errors.Is(err, ErrEmbeddingDimensionMismatch) {
+		t.Errorf("UpsertChunkEmbedding() error = %v, want ErrEmbeddingDimensionMismatch", err)
+	}
+
+	// A matching embedding passes validation and fails only on the missing connection
+	emb.Embedding = []float32{0.1, 0.2, 0.3, 0.4}
+	err = g.UpsertChunkEmbedding(context.TODO(), "hash-1", emb)
+	if err == nil || errors.Is(err, ErrEmbeddingDimensionMismatch) {
+		t.Errorf("UpsertChunkEmbedding() error = %v, want not-connected error", err)
+	}
+}
+
+func TestExportSnapshotSinceQueries(t *testing.T) {
+	// Full exports match every node
+	if got := updatedSince("f", time.Time{}); got != "" {
+		t.Errorf("updatedSince(zero) = %q, want empty", got)
+	}
+	if got := exportLinkedQuery(LabelTag, RelHasTag, "n.name", time.Time{}); got != "MATCH (n:Tag) RETURN n.name" {
+		t.Errorf("full tag query = %q", got)
+	}
+	if got := exportRelationshipCountQuery(time.Time{}); got != "MATCH ()-[r]->() RETURN count(r)" {
+		t.Errorf("full relationship count query = %q", got)
+	}
+
+	// Deltas are bounded by the previous export time
+	since := time.Unix(1700000000, 0)
+	if got := updatedSince("f", since); got != " WHERE f.updated_at >= 1700000000" {
+		t.Errorf("updatedSince(since) = %q", got)
+	}
+	if got := exportChunkCountQuery(since); got != "MATCH (c:Chunk) WHERE c.updated_at >= 1700000000 RETURN count(c)" {
+		t.Errorf("delta chunk count query = %q", got)
+	}
+
+	// Tags, topics, and entities follow the files that changed
+	for _, tt := range []struct {
+		label, rel string
+	}{
+		{LabelTag, RelHasTag},
+		{LabelTopic, RelCoversTopic},
+		{LabelEntity, RelMentions},
+	} {
+		got := exportLinkedQuery(tt.label, tt.rel, "n.name", since)
+		want := "MATCH (f:File)-[:" + tt.rel + "]->(n:" + tt.label + ") WHERE f.updated_at >= 1700000000 RETURN DISTINCT n.name"
+		if got != want {
+			t.Errorf("delta %s query = %q, want %q", tt.label, got, want)
+		}
+	}
+}
+
+func TestExportSnapshotSinceRequiresBound(t *testing.T) {
+	g := NewFalkorDBGraph()
+
+	if _, err := g.ExportSnapshotSince(context.TODO(), time.Time{}); err == nil || !strings.Contains(err.Error(), "since") {
+		t.Errorf("ExportSnapshotSince(zero) error = %v, want since error", err)
+	}
+	if _, err := g.ExportSnapshotSince(context.TODO(), time.Now()); err == nil {
+		t.Error("Expected error when not connected")
+	}
+}
+
+func TestQueueWriteSyncCancellation(t *testing.T) {
+	g := NewFalkorDBGraph()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := g.queueWriteSync(ctx, "MATCH (n) RETURN n")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("queueWriteSync() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("queueWriteSync() returned after %v, want prompt return", elapsed)
+	}
+
+	t.Run("AlreadyCancelled", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(context.Background())
+		cancel()
+		queued := len(g.writeQueue)
+		if err := g.queueWriteSync(cancelled, "MATCH (n) RETURN n"); !errors.Is(err, context.Canceled) {
+			t.Errorf("queueWriteSync() error = %v, want context.Canceled", err)
+		}
+		if len(g.writeQueue) != queued {
+			t.Error("expected cancelled write not to be queued")
+		}
+	})
+}
+
+func TestWriteBatch(t *testing.T) {
+	t.Run("JoinsFailedWrites", func(t *testing.T) {
+		_, batch := WithWriteBatch(context.Background())
+		ok := batch.Track()
+		failed := batch.Track()
+
+		go ok(nil)
+		go failed(errors.New("write failed"))
+
+		err := batch.Wait(context.Background())
+		if err == nil || !strings.Contains(err.Error(), "write failed") {
+			t.Errorf("Wait() error = %v, want write failed", err)
+		}
+	})
+
+	t.Run("WaitsForQueuedWrites", func(t *testing.T) {
+		g := NewFalkorDBGraph()
+		ctx, batch := WithWriteBatch(context.Background())
+
+		// A queued write with no processor running keeps the batch pending
+		if err := g.queueWrite(ctx, "MATCH (n) RETURN n"); err != nil {
+			t.Fatalf("queueWrite failed: %v", err)
+		}
+
+		waitCtx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if err := batch.Wait(waitCtx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Wait() error = %v, want context.DeadlineExceeded", err)
+		}
+
+		op := <-g.writeQueue
+		op.done(errors.New("write failed"))
+		if err := batch.Wait(context.Background()); err == nil {
+			t.Error("expected queued write failure to reach the batch")
+		}
+	})
+
+	t.Run("NilBatch", func(t *testing.T) {
+		batch := WriteBatchFromContext(context.Background())
+		batch.Track()(errors.New("ignored"))
+		if err := batch.Wait(context.Background()); err != nil {
+			t.Errorf("Wait() on nil batch = %v, want nil", err)
+		}
+	})
+}
+
+func TestFlush(t *testing.T) {
+	t.Run("NotConnected", func(t *testing.T) {
+		g := NewFalkorDBGraph()
+		if err := g.Flush(context.Background()); err == nil {
+			t.Error("Expected error when not connected")
+		}
+	})
+
+	t.Run("WaitsForPendingWrites", func(t *testing.T) {
+		g := NewFalkorDBGraph()
+		g.connected = true
+
+		// A pending write with no processor running keeps Flush blocked
+		if err := g.queueWrite(context.Background(), "MATCH (n) RETURN n"); err != nil {
+			t.Fatalf("queueWrite failed: %v", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if err := g.Flush(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Flush() error = %v, want context.DeadlineExceeded", err)
+		}
+	})
+
+	t.Run("ReturnsWhenDrained", func(t *testing.T) {
+		g := NewFalkorDBGraph()
+		g.connected = true
+		g.wg.Add(1)
+		go g.processWriteQueue(g.stopChan, g.lostChan)
+		defer close(g.stopChan)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		if err := g.Flush(ctx); err != nil {
+			t.Errorf("Flush() error = %v, want nil", err)
+		}
+	})
+}
+
+func TestTopNodesQuery(t *testing.T) {
+	query := topNodesQuery(LabelEntity, "n.name", 5, "o'brien")
+
+	for _, want := range []string{
+		"MATCH (n:Entity)",
+		`WHERE n.type = 'o\'brien'`,
+		"ORDER BY n.usage_count DESC",
+		"LIMIT 5",
+	} {
+		if !strings.Contains(query, want) {
+			t.Errorf("query missing %q:\n%s", want, query)
+		}
+	}
+
+	if strings.Contains(topNodesQuery(LabelTag, "n.name", 5, ""), "WHERE") {
+		t.Error("expected no type filter for empty node type")
+	}
+}
+
+func TestTopTags(t *testing.T) {
+	replica := &stubQuerier{}
+	g, _ := newReplicatedTestGraph(replica)
+	replica.result = scalarQueryResult(t,
+		[]string{"n.name", "n.normalized_name", "n.usage_count"},
+		[][]any{
+			{"Go", "go", 42},
+			{"Testing", "testing", 17},
+			{"Graphs", "graphs", 3},
+		})
+
+	tags, err := g.TopTags(context.TODO(), 3)
+	if err != nil {
+		t.Fatalf("TopTags failed: %v", err)
+	}
+
+	if len(replica.queries) != 1 || !strings.Contains(replica.queries[0], "LIMIT 3") {
+		t.Errorf("queries = %q, want one limited to 3", replica.queries)
+	}
+	want := []TagNode{
+		{Name: "Go", NormalizedName: "go", UsageCount: 42},
+		{Name: "Testing", NormalizedName: "testing", UsageCount: 17},
+		{Name: "Graphs", NormalizedName: "graphs", UsageCount: 3},
+	}
+	if len(tags) != len(want) {
+		t.Fatalf("got %d tags, want %d", len(tags), len(want))
+	}
+	for i := range want {
+		if tags[i] != want[i] {
+			t.Errorf("tag %d = %+v, want %+v", i, tags[i], want[i])
+		}
+		if i > 0 && tags[i].UsageCount > tags[i-1].UsageCount {
+			t.Errorf("tag %d usage %d exceeds previous %d", i, tags[i].UsageCount, tags[i-1].UsageCount)
+		}
+	}
+
+	if tags, err := g.TopTags(context.TODO(), 0); err != nil || tags != nil {
+		t.Errorf("TopTags(0) = %v, %v; want nil, nil", tags, err)
+	}
+}
+
+func TestGetRelatedFiles(t *testing.T) {
+	columns := []string{"signal", "path", "name", "score"}
+	// Signals as seen from each file: a.go and b.go share tags, a topic and
+	// similar chunks; c.go only shares one tag with each. Similarity rows carry
+	// cosine distances, as returned by the vector index.
+	signals := map[string][][]any{
+		"/src/a.go": {
+			{"tag", "/src/b.go", "graph", 1.0},
+			{"tag", "/src/b.go", "storage", 1.0},
+			{"topic", "/src/b.go", "Persistence", 1.0},
+			{"similarity", "/src/b.go", "b-0", 0.08},
+			{"similarity", "/src/b.go", "b-1", 0.12},
+			{"tag", "/src/c.go", "graph", 1.0},
+			{"similarity", "/src/c.go", "c-0", 0.69},
+		},
+		"/src/b.go": {
+			{"tag", "/src/a.go", "graph", 1.0},
+			{"tag", "/src/a.go", "storage", 1.0},
+			{"topic", "/src/a.go", "Persistence", 1.0},
+			{"similarity", "/src/a.go", "a-0", 0.08},
+			{"similarity", "/src/a.go", "a-1", 0.12},
+			{"tag", "/src/c.go", "graph", 1.0},
+		},
+	}
+
+	related := func(path string) []RelatedFile {
+		t.Helper()
+		replica := &stubQuerier{result: scalarQueryResult(t, columns, signals[path])}
+		g, _ := newReplicatedTestGraph(replica)
+
+		files, err := g.GetRelatedFiles(context.TODO(), path, 2)
+		if err != nil {
+			t.Fatalf("GetRelatedFiles(%s) failed: %v", path, err)
+		}
+		if len(replica.queries) != 1 || !strings.Contains(replica.queries[0], "{path: '"+path+"'}") {
+			t.Errorf("queries = %q, want one for %s", replica.queries, path)
+		}
+		return files
+	}
+
+	fromA := related("/src/a.go")
+	fromB := related("/src/b.go")
+	if len(fromA) != 2 || fromA[0].Path != "/src/b.go" {
+		t.Fatalf("related to a.go = %+v, want b.go first", fromA)
+	}
+	if len(fromB) != 2 || fromB[0].Path != "/src/a.go" {
+		t.Fatalf("related to b.go = %+v, want a.go first", fromB)
+	}
+
+	top := fromA[0]
+	if len(top.SharedTags) != 2 || len(top.SharedTopics) != 1 {
+		t.Errorf("signals = %+v, want 2 shared tags and 1 shared topic", top)
+	}
+	if top.Similarity < 0.899 || top.Similarity > 0.901 {
+		t.Errorf("Similarity = %v, want the 0.9 average", top.Similarity)
+	}
+	weights := DefaultRelatedFileWeights()
+	if want := 2*weights.Tags + weights.Topics + 0.9*weights.Similarity; top.Score < want-0.001 || top.Score > want+0.001 {
+		t.Errorf("Score = %v, want %v", top.Score, want)
+	}
+	if fromA[1].Score >= top.Score {
+		t.Errorf("c.go score %v should rank below b.go score %v", fromA[1].Score, top.Score)
+	}
+
+	t.Run("CustomWeights", func(t *testing.T) {
+		rows := []relatedSignalRow{
+			{Signal: "tag", Path: "/x.go", Name: "go", Score: 1},
+			{Signal: "similarity", Path: "/y.go", Name: "y-0", Score: 0.9},
+		}
+		got := scoreRelatedFiles(rows, RelatedFileWeights{Tags: 0.1, Similarity: 1}, 1)
+		if len(got) != 1 || got[0].Path != "/y.go" {
+			t.Errorf("scoreRelatedFiles = %+v, want y.go ranked by similarity weight", got)
+		}
+	})
+
+	t.Run("ImportsFollowStoredReferences", func(t *testing.T) {
+		g, _ := newReplicatedTestGraph()
+		ctx, tx := WithTransaction(context.Background())
+		if err := g.SetFileReferences(ctx, "/src/a.go", []Reference{{Type: "file", Target: "/src/util.go"}}); err != nil {
+			t.Fatalf("SetFileReferences failed: %v", err)
+		}
+		if len(tx.queries) != 2 {
+			t.Fatalf("queries = %d, want 2", len(tx.queries))
+		}
+		stored := regexp.MustCompile(`MERGE \(f\)-(\[[^\]]+\])->\(t\)`).FindStringSubmatch(tx.queries[1])
+		if stored == nil {
+			t.Fatalf("reference query does not create an edge:\n%s", tx.queries[1])
+		}
+
+		query := relatedFilesQuery("/src/a.go", relatedChunkNeighbors)
+		coImport := "(f:File {path: '/src/a.go'})-" + stored[1] + "->(m:File)<-" + stored[1] + "-(o:File)"
+		if !strings.Contains(query, coImport) {
+			t.Errorf("import signal does not match stored %s edges:\n%s", stored[1], query)
+		}
+		if strings.Contains(query, ":IMPORTS") {
+			t.Errorf("import signal matches IMPORTS edges that are never written:\n%s", query)
+		}
+	})
+
+	t.Run("NonPositiveK", func(t *testing.T) {
+		g, _ := newReplicatedTestGraph(&stubQuerier{})
+		if files, err := g.GetRelatedFiles(context.TODO(), "/src/a.go", 0); err != nil || files != nil {
+			t.Errorf("GetRelatedFiles(k=0) = %v, %v; want nil, nil", files, err)
+		}
+	})
+}
+
+func TestSearchSimilarChunksScoresAreHigherIsBetter(t *testing.T) {
+	columns := []string{"c.id", "c.file_path", "c.index", "c.content_hash", "c.start_offset", "c.end_offset",
+		"c.chunk_type", "c.summary", "score", "node.provider", "node.model", "c.boundary_confidence",
+		"c.line_start", "c.line_end", "c.content"}
+	rows := [][]any{
+		{"near", "/a.go", 0, "h1", 0, 10, "code", "", 0.5, "openai", "m", 1.0, 1, 2, ""},
+		{"far", "/b.go", 0, "h2", 0, 10, "code", "", 3.0, "openai", "m", 1.0, 1, 2, ""},
+	}
+	g, primary := newReplicatedTestGraph()
+	g.config.DistanceMetric = DistanceEuclidean
+	primary.result = scalarQueryResult(t, columns, rows)
+
+	hits, err := g.SearchSimilarChunks(context.Background(), []float32{0.1, 0.2}, 2)
+	if err != nil {
+		t.Fatalf("SearchSimilarChunks failed: %v", err)
+	}
+	if !strings.Contains(primary.queries[0], "ORDER BY score ASC") {
+		t.Errorf("query should order by ascending distance:\n%s", primary.queries[0])
+	}
+	if len(hits) != 2 || hits[0].Distance != 0.5 || hits[1].Distance != 3.0 {
+		t.Fatalf("hits = %+v, want distances 0.5 and 3.0", hits)
+	}
+	if hits[0].Score <= hits[1].Score {
+		t.Errorf("scores = %v, %v; nearer chunk should score higher", hits[0].Score, hits[1].Score)
+	}
+}
+
+func TestSimilarChunksQueryFilter(t *testing.T) {
+	embedding := []float32{0.1, 0.2}
+
+	unfiltered := similarChunksQuery(embedding, 5, ChunkFilter{})
+	if strings.Contains(unfiltered, "WHERE") || strings.Contains(unfiltered, "CYPHER") {
+		t.Errorf("unfiltered query should not filter:\n%s", unfiltered)
+	}
+	if !strings.Contains(unfiltered, "'embedding', 5,") {
+		t.Errorf("unfiltered query should fetch k candidates:\n%s", unfiltered)
+	}
+
+	filtered := similarChunksQuery(embedding, 5, ChunkFilter{
+		FilePathPrefix: "/src/it's/",
+		Language:       "go",
+		ChunkType:      "code",
+	})
+	for _, want := range []string{
+		`CYPHER chunk_type="code" language="go" path_prefix="/src/it's/" `,
+		"'embedding', 50,",
+		"MATCH (f:File)-[:HAS_CHUNK]->(c:Chunk)-[:HAS_EMBEDDING]->(node)",
+		"WHERE c.file_path STARTS WITH $path_prefix AND c.chunk_type = $chunk_type AND f.language = $language",
+		"LIMIT 5",
+	} {
+		if !strings.Contains(filtered, want) {
+			t.Errorf("filtered query missing %q:\n%s", want, filtered)
+		}
+	}
+
+	byType := similarChunksQuery(embedding, 5, ChunkFilter{ChunkType: "markdown"})
+	if strings.Contains(byType, "(f:File)") {
+		t.Errorf("query should only join files when filtering by language:\n%s", byType)
+	}
+}
+
+func TestSearchSimilarChunksFiltered(t *testing.T) {
+	columns := []string{"c.id", "c.file_path", "c.index", "c.content_hash", "c.start_offset", "c.end_offset",
+		"c.chunk_type", "c.summary", "score", "node.provider", "node.model", "c.boundary_confidence",
+		"c.line_start", "c.line_end", "c.content"}
+	replica := &stubQuerier{result: scalarQueryResult(t, columns, [][]any{
+		{"a-0", "/src/a.go", 0, "h1", 0, 10, "code", "", 0.1, "openai", "m", 1.0, 1, 2, ""},
+	})}
+	g, primary := newReplicatedTestGraph(replica)
+
+	hits, err := g.SearchSimilarChunksFiltered(context.Background(), []float32{0.1, 0.2}, 3, ChunkFilter{Language: "go"})
+	if err != nil {
+		t.Fatalf("SearchSimilarChunksFiltered failed: %v", err)
+	}
+	if len(primary.queries) != 0 || len(replica.queries) != 1 {
+		t.Fatalf("primary, replica queries = %d, %d; want search on the replica", len(primary.queries), len(replica.queries))
+	}
+	if !strings.Contains(replica.queries[0], "f.language = $language") {
+		t.Errorf("query should filter by language:\n%s", replica.queries[0])
+	}
+	if len(hits) != 1 || hits[0].Chunk.ID != "a-0" {
+		t.Errorf("hits = %+v, want a-0", hits)
+	}
+}
+
+// fakeRedisServer is a local stand-in for FalkorDB. It answers GRAPH.QUERY
+// with an empty result and other commands with +OK, and records the graph
+// queries it receives. It drops connections while down or still failing,
+// and drops the connection of a graph query while queryDrops remain.
+type fakeRedisServer struct {
+	ln         net.Listener
+	attempts   atomic.Int32
+	failures   atomic.Int32
+	queryDrops atomic.Int32
+	down       atomic.Bool
+
+	mu      sync.Mutex
+	conns   []net.Conn
+	queries []string
+}
+
+// newFakeRedisServer starts a server that drops its first failures connections.
+func newFakeRedisServer(t *testing.T, failures int) *fakeRedisServer {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	return serveFakeRedis(t, ln, failures)
+}
+
+// serveFakeRedis runs a fake server on ln, which may be a TLS listener.
+func serveFakeRedis(t *testing.T, ln net.Listener, failures int) *fakeRedisServer {
+	t.Helper()
+
+	srv := &fakeRedisServer{ln: ln}
+	srv.failures.Store(int32(failures))
+	t.Cleanup(func() {
+		ln.Close()
+		srv.closeConns()
+	})
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			srv.attempts.Add(1)
+			if srv.down.Load() || srv.failures.Add(-1) >= 0 {
+				conn.Close()
+				continue
+			}
+			srv.mu.Lock()
+			srv.conns = append(srv.conns, conn)
+			srv.mu.Unlock()
+			go srv.serve(conn)
+		}
+	}()
+	return srv
+}
+
+// config returns a graph config for the server. A password makes each dial
+// wait for the AUTH reply, so dropped connections fail the attempt.
+func (s *fakeRedisServer) config(t *testing.T) Config {
+	t.Helper()
+	t.Setenv("TEST_GRAPH_PASSWORD", "secret")
+
+	addr := s.ln.Addr().(*net.TCPAddr)
+	cfg := DefaultConfig()
+	cfg.Host = addr.IP.String()
+	cfg.Port = addr.Port
+	cfg.PasswordEnv = "TEST_GRAPH_PASSWORD"
+	cfg.RetryDelay = time.Millisecond
+	cfg.SkipSchemaInit = true
+	return cfg
+}
+
+func (s *fakeRedisServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	// Like Redis, reply with a plain error to input that is not a command,
+	// such as a TLS ClientHello sent to a server without TLS
+	if first, err := r.Peek(1); err != nil || first[0] != '*' {
+		conn.Write([]byte("-ERR unknown command\r\n"))
+		return
+	}
+	for {
+		args, err := readRESPCommand(r)
+		if err != nil {
+			return
+		}
+		reply := "+OK\r\n"
+		if len(args) >= 3 && args[0] == "GRAPH.QUERY" {
+			if s.queryDrops.Add(-1) >= 0 {
+				return
+			}
+			s.mu.Lock()
+			s.queries = append(s.queries, args[2])
+			s.mu.Unlock()
+			stats := "Query internal execution time: 0.1 milliseconds"
+			reply = fmt.Sprintf("*1\r\n*1\r\n$%d\r\n%s\r\n", len(stats), stats)
+		}
+		if _, err := conn.Write([]byte(reply)); err != nil {
+			return
+		}
+	}
+}
+
+// closeConns drops every open connection, as a server restart would.
+func (s *fakeRedisServer) closeConns() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+	s.conns = nil
+}
+
+// graphQueries returns the graph queries received so far.
+func (s *fakeRedisServer) graphQueries() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.queries)
+}
+
+// readRESPCommand reads one command sent as an array of bulk strings.
+func readRESPCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		header, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(header, "$")))
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func TestStartRetriesConnection(t *testing.T) {
+	t.Run("SucceedsOnThirdAttempt", func(t *testing.T) {
+		srv := newFakeRedisServer(t, 2)
+		cfg := srv.config(t)
+		cfg.MaxRetries = 3
+
+		bus := events.NewBus()
+		defer bus.Close()
+		connected := make(chan struct{}, 1)
+		bus.Subscribe(events.GraphConnected, func(events.Event) { connected <- struct{}{} })
+
+		g := NewFalkorDBGraph(WithConfig(cfg), WithBus(bus))
+		if err := g.Start(context.Background()); err != nil {
+			t.Fatalf("Start failed: %v", err)
+		}
+		defer g.Stop(context.Background())
+
+		if got := srv.attempts.Load(); got != 3 {
+			t.Errorf("connection attempts = %d, want 3", got)
+		}
+		if !g.IsConnected() {
+			t.Error("graph should be connected")
+		}
+		select {
+		case <-connected:
+		case <-time.After(time.Second):
+			t.Error("GraphConnected was not published")
+		}
+	})
+
+	t.Run("GivesUpAfterMaxRetries", func(t *testing.T) {
+		srv := newFakeRedisServer(t, 10)
+		cfg := srv.config(t)
+		cfg.MaxRetries = 2
+
+		g := NewFalkorDBGraph(WithConfig(cfg))
+		if err := g.Start(context.Background()); err == nil {
+			t.Fatal("Start should fail when every attempt fails")
+		}
+		if got := srv.attempts.Load(); got != 3 {
+			t.Errorf("connection attempts = %d, want 3", got)
+		}
+		if g.IsConnected() {
+			t.Error("graph should not be connected")
+		}
+	})
+
+	t.Run("StopsWhenContextCancelled", func(t *testing.T) {
+		srv := newFakeRedisServer(t, 10)
+		cfg := srv.config(t)
+		cfg.MaxRetries = 5
+		cfg.RetryDelay = time.Hour
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		g := NewFalkorDBGraph(WithConfig(cfg))
+		err := g.Start(ctx)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Start error = %v, want context.DeadlineExceeded", err)
+		}
+	})
+}
+
+func TestReconnectAfterConnectionDrop(t *testing.T) {
+	srv := newFakeRedisServer(t, 0)
+	cfg := srv.config(t)
+	cfg.MaxRetries = 1
+	cfg.SkipSchemaInit = false
+
+	bus := events.NewBus()
+	defer bus.Close()
+	connected := make(chan struct{}, 2)
+	bus.Subscribe(events.GraphConnected, func(events.Event) { connected <- struct{}{} })
+
+	g := NewFalkorDBGraph(WithConfig(cfg), WithBus(bus))
+	if err := g.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer g.Stop(context.Background())
+	<-connected
+	schemaQueries := len(srv.graphQueries())
+
+	// The server restarts: open connections drop and new ones are refused
+	srv.down.Store(true)
+	srv.closeConns()
+
+	if _, err := g.Query(context.Background(), "MATCH (n:Directory) DELETE n"); err == nil {
+		t.Fatal("Query should fail on the dropped connection")
+	}
+	if g.IsConnected() {
+		t.Fatal("graph should be disconnected after the connection drops")
+	}
+
+	// Writes made during the outage wait in the queue
+	if err := g.UpsertDirectory(context.Background(), &DirectoryNode{Path: "/during/outage"}); err != nil {
+		t.Fatalf("UpsertDirectory during outage failed: %v", err)
+	}
+	if got := len(g.writeQueue); got != 1 {
+		t.Fatalf("queued writes = %d, want 1", got)
+	}
+
+	srv.down.Store(false)
+	select {
+	case <-connected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("GraphConnected was not published after the server came back")
+	}
+	if !g.IsConnected() {
+		t.Fatal("graph should be reconnected")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := g.Flush(ctx); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	queries := srv.graphQueries()
+	indexes := 0
+	for _, q := range queries {
+		if strings.HasPrefix(q, "CREATE INDEX") {
+			indexes++
+		}
+	}
+	if want := 2 * len(coreIndexes); indexes < want {
+		t.Errorf("CREATE INDEX queries = %d, want schema initialized again (%d)", indexes, want)
+	}
+	if !slices.ContainsFunc(queries[schemaQueries:], func(q string) bool {
+		return strings.Contains(q, `path="/during/outage"`)
+	}) {
+		t.Error("write queued during the outage was not executed after reconnecting")
+	}
+}
+
+func TestReconnectSchemaInitConnectionFailure(t *testing.T) {
+	srv := newFakeRedisServer(t, 0)
+	cfg := srv.config(t)
+	cfg.MaxRetries = 1
+	cfg.SkipSchemaInit = false
+
+	bus := events.NewBus()
+	defer bus.Close()
+	connected := make(chan struct{}, 2)
+	bus.Subscribe(events.GraphConnected, func(events.Event) { connected <- struct{}{} })
+
+	g := NewFalkorDBGraph(WithConfig(cfg), WithBus(bus))
+	if err := g.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer g.Stop(context.Background())
+	<-connected
+
+	// The server restarts and drops the next reconnect during schema init
+	srv.down.Store(true)
+	srv.closeConns()
+	if _, err := g.Query(context.Background(), "MATCH (n) RETURN n"); err == nil {
+		t.Fatal("Query should fail on the dropped connection")
+	}
+	srv.queryDrops.Store(1)
+	srv.down.Store(false)
+
+	select {
+	case <-connected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("GraphConnected was not published after schema init failed on a reconnect")
+	}
+	if !g.IsConnected() {
+		t.Fatal("graph should be reconnected")
+	}
+	if got := srv.queryDrops.Load(); got >= 0 {
+		t.Errorf("query drops remaining = %d, want schema init to have hit the dropped connection", got+1)
+	}
+	if _, err := g.Query(context.Background(), "MATCH (n) RETURN n"); err != nil {
+		t.Errorf("Query after reconnect failed: %v", err)
+	}
+}
+
+// testTLSListener returns a TLS listener on a local port with a self-signed
+// certificate for 127.0.0.1, and the path of that certificate as a PEM file.
+func testTLSListener(t *testing.T) (net.Listener, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "falkordb-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate failed: %v", err)
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	})
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	return ln, caFile
+}
+
+func TestStartWithTLS(t *testing.T) {
+	t.Run("HandshakeSucceeds", func(t *testing.T) {
+		ln, caFile := testTLSListener(t)
+		srv := serveFakeRedis(t, ln, 0)
+		t.Setenv("TEST_GRAPH_TLS_CA_FILE", caFile)
+		cfg := srv.config(t)
+		cfg.UseTLS = true
+		cfg.TLSCAFileEnv = "TEST_GRAPH_TLS_CA_FILE"
+
+		g := NewFalkorDBGraph(WithConfig(cfg))
+		if err := g.Start(context.Background()); err != nil {
+			t.Fatalf("Start failed: %v", err)
+		}
+		defer g.Stop(context.Background())
+
+		if _, err := g.Query(context.Background(), "CREATE (:Probe)"); err != nil {
+			t.Fatalf("Query over TLS failed: %v", err)
+		}
+		if queries := srv.graphQueries(); len(queries) != 1 || queries[0] != "CREATE (:Probe)" {
+			t.Errorf("server queries = %q, want the probe query", queries)
+		}
+	})
+
+	t.Run("UntrustedCertificate", func(t *testing.T) {
+		ln, _ := testTLSListener(t)
+		srv := serveFakeRedis(t, ln, 0)
+		cfg := srv.config(t)
+		cfg.UseTLS = true
+		cfg.TLSCAFileEnv = ""
+
+		g := NewFalkorDBGraph(WithConfig(cfg))
+		if err := g.Start(context.Background()); !errors.Is(err, ErrTLSHandshake) {
+			t.Fatalf("Start error = %v, want ErrTLSHandshake", err)
+		}
+	})
+
+	t.Run("ServerRejectsTLS", func(t *testing.T) {
+		srv := newFakeRedisServer(t, 0)
+		cfg := srv.config(t)
+		cfg.UseTLS = true
+		cfg.MaxRetries = 3
+
+		g := NewFalkorDBGraph(WithConfig(cfg))
+		err := g.Start(context.Background())
+		if !errors.Is(err, ErrTLSHandshake) {
+			t.Fatalf("Start error = %v, want ErrTLSHandshake", err)
+		}
+		if !strings.Contains(err.Error(), "accepts TLS") {
+			t.Errorf("error %q should explain the server may not accept TLS", err)
+		}
+		if got := srv.attempts.Load(); got != 1 {
+			t.Errorf("connection attempts = %d, want 1; a rejected handshake is not retried", got)
+		}
+	})
+
+	t.Run("MissingCAFile", func(t *testing.T) {
+		t.Setenv("TEST_GRAPH_TLS_CA_FILE", filepath.Join(t.TempDir(), "missing.pem"))
+		cfg := DefaultConfig()
+		cfg.UseTLS = true
+		cfg.TLSCAFileEnv = "TEST_GRAPH_TLS_CA_FILE"
+
+		g := NewFalkorDBGraph(WithConfig(cfg))
+		if err := g.Start(context.Background()); err == nil || !strings.Contains(err.Error(), "TLS CA file") {
+			t.Errorf("Start error = %v, want a TLS CA file error", err)
+		}
+	})
+}
+
+func TestLinkChunksQuery(t *testing.T) {
+	query := linkChunksQuery("/docs/guide.md", []string{"c0", "c1", "c2"})
+
+	// Three chunks yield two edges, each from a chunk to the one after it
+	if !strings.Contains(query, `pairs=[{from: "c0", to: "c1"},{from: "c1", to: "c2"}]`) {
+		t.Errorf("query should link c0->c1 and c1->c2:\n%s", query)
+	}
+	if !strings.Contains(query, "MATCH (a:Chunk {id: pair.from}), (b:Chunk {id: pair.to})") ||
+		!strings.Contains(query, "MERGE (a)-[:NEXT_CHUNK {file_path: $path}]->(b)") {
+		t.Errorf("edges should point from each chunk to the next:\n%s", query)
+	}
+
+	// Re-ingesting removes the file's previous edges before linking
+	deleteAt := strings.Index(query, "OPTIONAL MATCH (:File {path: $path})-[:HAS_CHUNK]->(:Chunk)-[old:NEXT_CHUNK {file_path: $path}]->()")
+	if deleteAt < 0 || deleteAt > strings.Index(query, "MERGE") {
+		t.Errorf("query should delete existing edges before merging:\n%s", query)
+	}
+
+	repeated := linkChunksQuery("/docs/a.md", []string{"c0", "c0", "c1"})
+	if !strings.Contains(repeated, `pairs=[{from: "c0", to: "c1"}]`) {
+		t.Errorf("repeated adjacent chunks should not be self-linked:\n%s", repeated)
+	}
+	cyclic := linkChunksQuery("/docs/a.md", []string{"c0", "c1", "c0", "c2"})
+	if !strings.Contains(cyclic, `pairs=[{from: "c0", to: "c1"},{from: "c1", to: "c2"}]`) {
+		t.Errorf("a chunk repeated later in the file should not close a cycle:\n%s", cyclic)
+	}
+	if single := linkChunksQuery("/docs/a.md", []string{"c0"}); !strings.Contains(single, "pairs=[]") {
+		t.Errorf("a single chunk should only remove edges:\n%s", single)
+	}
+}
+
+func TestAdjacentChunksQuery(t *testing.T) {
+	only := adjacentChunksQuery("c1", 0, 0)
+	if strings.Contains(only, "UNION") || strings.Contains(only, "NEXT_CHUNK") {
+		t.Errorf("zero neighbours should only return the chunk:\n%s", only)
+	}
+
+	query := adjacentChunksQuery("c1", 2, 3)
+	for _, want := range []string{
+		`CYPHER id="c1" `,
+		"MATCH p = (n:Chunk)-[:NEXT_CHUNK*1..2]->(c)",
+		"MATCH p = (c)-[:NEXT_CHUNK*1..3]->(n:Chunk)",
+		"WHERE all(r IN relationships(p) WHERE r.file_path = c.file_path)",
+	} {
+		if !strings.Contains(query, want) {
+			t.Errorf("query missing %q:\n%s", want, query)
+		}
+	}
+	if got := strings.Count(query, "UNION"); got != 2 {
+		t.Errorf("UNION count = %d, want 2", got)
+	}
+}
+
+func TestGetAdjacentChunks(t *testing.T) {
+	columns := []string{"n.id", "n.file_path", "n.index", "n.content_hash", "n.start_offset", "n.end_offset",
+		"n.chunk_type", "n.summary", "n.line_start", "n.line_end", "n.content", "offset"}
+	replica := &stubQuerier{result: scalarQueryResult(t, columns, [][]any{
+		{"c1", "/docs/guide.md", 1, "c1", 10, 20, "markdown", "", 3, 4, "", 0},
+		{"c2", "/docs/guide.md", 2, "c2", 20, 30, "markdown", "", 5, 6, "", 1},
+		{"c0", "/docs/guide.md", 0, "c0", 0, 10, "markdown", "", 1, 2, "", -1},
+	})}
+	g, _ := newReplicatedTestGraph(replica)
+
+	chunks, err := g.GetAdjacentChunks(context.Background(), "c1", 1, 1)
+	if err != nil {
+		t.Fatalf("GetAdjacentChunks failed: %v", err)
+	}
+	var ids []string
+	for _, c := range chunks {
+		ids = append(ids, c.ID)
+	}
+	if strings.Join(ids, ",") != "c0,c1,c2" {
+		t.Errorf("chunks = %v, want c0,c1,c2 in reading order", ids)
+	}
+	if len(replica.queries) != 1 {
+		t.Errorf("replica queries = %d, want 1", len(replica.queries))
+	}
+}
+
+func TestChunkByIDQuery(t *testing.T) {
+	query := chunkByIDQuery("c1")
+	for _, want := range []string{
+		`CYPHER id="c1" `,
+		"OPTIONAL MATCH (c)-[r:HAS_CODE_META|HAS_DOC_META|",
+		"type(r), keys(m), [k IN keys(m) | m[k]]",
+	} {
+		if !strings.Contains(query, want) {
+			t.Errorf("query missing %q:\n%s", want, query)
+		}
+	}
+}
+
+func TestGetChunkByID(t *testing.T) {
+	columns := []string{"c.id", "c.file_path", "c.index", "c.content_hash", "c.start_offset", "c.end_offset",
+		"c.chunk_type", "c.token_count", "c.boundary_confidence", "c.line_start", "c.line_end",
+		"c.summary", "c.content", "type(r)", "keys(m)", "values"}
+
+	// metaCells returns the property names and values of a metadata node the
+	// way FalkorDB returns them, with lists as untyped arrays.
+	metaCells := func(props map[string]any) (keys, values []any) {
+		for _, name := range slices.Sorted(maps.Keys(props)) {
+			value := props[name]
+			if list, ok := value.([]string); ok {
+				items := make([]any, len(list))
+				for i, item := range list {
+					items[i] = item
+				}
+				value = items
+			}
+			keys = append(keys, name)
+			values = append(values, value)
+		}
+		return keys, values
+	}
+
+	t.Run("CodeChunk", func(t *testing.T) {
+		code := &chunkers.CodeMetadata{
+			Language:     "go",
+			FunctionName: "Handle",
+			Signature:    "func Handle(ctx context.Context) error",
+			IsExported:   true,
+			LineStart:    10,
+			LineEnd:      20,
+			Parameters:   []string{"ctx"},
+		}
+		keys, values := metaCells(codeMetaProps(code))
+		replica := &stubQuerier{result: scalarQueryResult(t, columns, [][]any{
+			{"c1", "/src/handler.go", 2, "h1", 100, 200, "code", 42, 0.9, 10, 20, "handles", nil,
+				"HAS_CODE_META", keys, values},
+		})}
+		g, _ := newReplicatedTestGraph(replica)
+
+		chunk, meta, err := g.GetChunkByID(context.Background(), "c1")
+		if err != nil {
+			t.Fatalf("GetChunkByID failed: %v", err)
+		}
+		if chunk == nil || chunk.ID != "c1" || chunk.Index != 2 || chunk.TokenCount != 42 {
+			t.Fatalf("chunk = %+v, want c1 at index 2 with 42 tokens", chunk)
+		}
+		if meta.Type != chunkers.ChunkTypeCode || meta.BoundaryConfidence != 0.9 {
+			t.Errorf("meta type = %q, confidence = %v", meta.Type, meta.BoundaryConfidence)
+		}
+		if meta.Document != nil {
+			t.Error("code chunk should not have document metadata")
+		}
+		if meta.Code == nil {
+			t.Fatal("expected code metadata")
+		}
+		if meta.Code.Language != code.Language || meta.Code.FunctionName != code.FunctionName ||
+			meta.Code.Signature != code.Signature || !meta.Code.IsExported ||
+			meta.Code.LineStart != 10 || meta.Code.LineEnd != 20 {
+			t.Errorf("code metadata = %+v, want %+v", meta.Code, code)
+		}
+		if !slices.Equal(meta.Code.Parameters, code.Parameters) {
+			t.Errorf("parameters = %v, want %v", meta.Code.Parameters, code.Parameters)
+		}
+	})
+
+	t.Run("DocumentChunk", func(t *testing.T) {
+		doc := &chunkers.DocumentMetadata{
+			Heading:      "Install",
+			HeadingLevel: 2,
+			SectionPath:  "Guide > Install",
+			WordCount:    120,
+			HasCodeBlock: true,
+			CodeLanguage: "bash",
+		}
+		keys, values := metaCells(documentMetaProps(doc))
+		replica := &stubQuerier{result: scalarQueryResult(t, columns, [][]any{
+			{"c2", "/docs/guide.md", 1, "h2", 0, 50, "markdown", 30, 1.0, 3, 9, "", "## Install",
+				"HAS_DOC_META", keys, values},
+		})}
+		g, _ := newReplicatedTestGraph(replica)
+
+		chunk, meta, err := g.GetChunkByID(context.Background(), "c2")
+		if err != nil {
+			t.Fatalf("GetChunkByID failed: %v", err)
+		}
+		if chunk.Content != "## Install" {
+			t.Errorf("content = %q, want stored content", chunk.Content)
+		}
+		if meta.Type != chunkers.ChunkTypeMarkdown {
+			t.Errorf("meta type = %q, want markdown", meta.Type)
+		}
+		if meta.Code != nil {
+			t.Error("document chunk should not have code metadata")
+		}
+		if meta.Document == nil || !reflect.DeepEqual(meta.Document, doc) {
+			t.Errorf("document metadata = %+v, want %+v", meta.Document, doc)
+		}
+	})
+
+	t.Run("StructuredChunk", func(t *testing.T) {
+		structured := &chunkers.StructuredMetadata{
+			RecordIndex: 40,
+			RecordCount: 100,
+			KeyNames:    []string{"id", "name"},
+		}
+		keys, values := metaCells(structuredMetaProps(structured))
+		replica := &stubQuerier{result: scalarQueryResult(t, columns, [][]any{
+			{"c3", "/data/users.csv", 2, "h3", 500, 900, "structured", 80, 1.0, 0, 0, "", "id,name",
+				"HAS_STRUCT_META", keys, values},
+		})}
+		g, _ := newReplicatedTestGraph(replica)
+
+		_, meta, err := g.GetChunkByID(context.Background(), "c3")
+		if err != nil {
+			t.Fatalf("GetChunkByID failed: %v", err)
+		}
+		if meta.Structured == nil || !reflect.DeepEqual(meta.Structured, structured) {
+			t.Errorf("structured metadata = %+v, want %+v", meta.Structured, structured)
+		}
+	})
+
+	t.Run("NoMetadata", func(t *testing.T) {
+		replica := &stubQuerier{result: scalarQueryResult(t, columns, [][]any{
+			{"c3", "/notes.txt", 0, "h3", 0, 5, "prose", 2, 1.0, 1, 1, "", nil, nil, nil, nil},
+		})}
+		g, _ := newReplicatedTestGraph(replica)
+
+		_, meta, err := g.GetChunkByID(context.Background(), "c3")
+		if err != nil {
+			t.Fatalf("GetChunkByID failed: %v", err)
+		}
+		if meta.Type != chunkers.ChunkTypeProse || meta.Code != nil || meta.Document != nil {
+			t.Errorf("meta = %+v, want untyped prose metadata", meta)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		replica := &stubQuerier{result: scalarQueryResult(t, columns, nil)}
+		g, _ := newReplicatedTestGraph(replica)
+
+		chunk, meta, err := g.GetChunkByID(context.Background(), "missing")
+		if err != nil || chunk != nil || meta != nil {
+			t.Errorf("GetChunkByID = %v, %v, %v; want all nil", chunk, meta, err)
+		}
+	})
+}
+
+func TestListChunksForFile(t *testing.T) {
+	query := chunksForFileQuery("/src/main.go")
+	for _, want := range []string{
+		`CYPHER path="/src/main.go" `,
+		"MATCH (f:File {path: $path})-[:HAS_CHUNK]->(c:Chunk)",
+		"ORDER BY c.index",
+	} {
+		if !strings.Contains(query, want) {
+			t.Errorf("query missing %q:\n%s", want, query)
+		}
+	}
+
+	columns := []string{"c.id", "c.file_path", "c.index", "c.content_hash", "c.start_offset", "c.end_offset",
+		"c.chunk_type", "c.token_count", "c.boundary_confidence", "c.line_start", "c.line_end",
+		"c.summary", "c.content"}
+	replica := &stubQuerier{result: scalarQueryResult(t, columns, [][]any{
+		{"c0", "/src/main.go", 0, "h0", 0, 10, "code", 3, 1.0, 1, 2, "", nil},
+		{"c1", "/src/main.go", 1, "h1", 10, 20, "code", 3, 1.0, 3, 4, "", nil},
+	})}
+	g, _ := newReplicatedTestGraph(replica)
+
+	chunks, err := g.ListChunksForFile(context.Background(), "/src/main.go")
+	if err != nil {
+		t.Fatalf("ListChunksForFile failed: %v", err)
+	}
+	if len(chunks) != 2 || chunks[0].ID != "c0" || chunks[1].Index != 1 || chunks[1].LineStart != 3 {
+		t.Errorf("chunks = %+v, want c0 and c1 in index order", chunks)
+	}
+}
+
+func TestPruneOrphansQueries(t *testing.T) {
+	queries := pruneOrphansQueries(1700000000)
+	if len(queries) != len(chunkMetaTypes)+2 {
+		t.Fatalf("queries = %d, want %d", len(queries), len(chunkMetaTypes)+2)
+	}
+
+	// Chunks are pruned first so their metadata and embeddings become orphans
+	chunks := queries[0]
+	if chunks.label != "Chunk" {
+		t.Fatalf("first query label = %q, want Chunk", chunks.label)
+	}
+	for _, want := range []string{
+		"CYPHER cutoff=1700000000 ",
+		"WHERE NOT (:File)-[:HAS_CHUNK]->(c)",
+		"c.updated_at < $cutoff",
+		"DETACH DELETE c",
+	} {
+		if !strings.Contains(chunks.query, want) {
+			t.Errorf("chunk query missing %q:\n%s", want, chunks.query)
+		}
+	}
+
+	byLabel := make(map[string]string)
+	for _, q := range queries[1:] {
+		byLabel[q.label] = q.query
+	}
+	for label, want := range map[string]string{
+		"CodeMeta":       "WHERE NOT (:Chunk)-[:HAS_CODE_META]->(m)",
+		"DocumentMeta":   "WHERE NOT (:Chunk)-[:HAS_DOC_META]->(m)",
+		"LogMeta":        "WHERE NOT (:Chunk)-[:HAS_LOG_META]->(m)",
+		"ChunkEmbedding": "WHERE NOT (:Chunk)-[:HAS_EMBEDDING]->(m)",
+	} {
+		query, ok := byLabel[label]
+		if !ok {
+			t.Errorf("no prune query for %s", label)
+			continue
+		}
+		if !strings.Contains(query, "MATCH (m:"+label+")") || !strings.Contains(query, want) {
+			t.Errorf("%s query missing orphan match %q:\n%s", label, want, query)
+		}
+	}
+}
+
+func TestPruneOrphans(t *testing.T) {
+	g, primary := newReplicatedTestGraph()
+	result, err := redisgraph.QueryResultNew(nil, []any{[]any{"Nodes deleted: 2", "Query internal execution time: 0.1 milliseconds"}})
+	if err != nil {
+		t.Fatalf("QueryResultNew failed: %v", err)
+	}
+	primary.result = result
+
+	type pruneResult struct {
+		stats PruneStats
+		err   error
+	}
+	done := make(chan pruneResult, 1)
+	go func() {
+		stats, err := g.PruneOrphans(context.Background())
+		done <- pruneResult{stats, err}
+	}()
+	for range len(chunkMetaTypes) + 2 {
+		g.executeWrite(<-g.writeQueue)
+	}
+
+	pruned := <-done
+	if pruned.err != nil {
+		t.Fatalf("PruneOrphans failed: %v", pruned.err)
+	}
+	if pruned.stats.Deleted["Chunk"] != 2 || pruned.stats.Deleted["CodeMeta"] != 2 || pruned.stats.Deleted["ChunkEmbedding"] != 2 {
+		t.Errorf("deleted = %v, want 2 per label", pruned.stats.Deleted)
+	}
+	if got, want := pruned.stats.Total(), 2*(len(chunkMetaTypes)+2); got != want {
+		t.Errorf("total = %d, want %d", got, want)
+	}
+	if len(primary.queries) != len(chunkMetaTypes)+2 {
+		t.Errorf("primary queries = %d, want %d", len(primary.queries), len(chunkMetaTypes)+2)
+	}
+}
+
+// TestPruneOrphansKeepsConnectedNodes runs PruneOrphans against a FalkorDB
+// server at MEMORIZER_TEST_FALKORDB_ADDR (host:port) and is skipped without one.
+func TestPruneOrphansKeepsConnectedNodes(t *testing.T) {
+	addr := os.Getenv("MEMORIZER_TEST_FALKORDB_ADDR")
+	if addr == "" {
+		t.Skip("MEMORIZER_TEST_FALKORDB_ADDR not set")
+	}
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		t.Fatalf("invalid MEMORIZER_TEST_FALKORDB_ADDR %q: %v", addr, err)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		t.Fatalf("invalid port %q: %v", portStr, err)
+	}
+
+	ctx := context.Background()
+	cfg := DefaultConfig()
+	cfg.Host = host
+	cfg.Port = port
+	cfg.GraphName = fmt.Sprintf("memorizer_prune_test_%d", time.Now().UnixNano())
+	g := NewFalkorDBGraph(WithConfig(cfg))
+	if err := g.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer g.Stop(ctx)
+	defer g.Query(ctx, "MATCH (n) DETACH DELETE n")
+
+	// Every chunk predates the grace period except "recent"
+	seed := fmt.Sprintf(`
+		CREATE (f:File {path: '/repo/kept.go'})-[:HAS_CHUNK]->(c:Chunk {id: 'kept', updated_at: 0}),
+			(c)-[:HAS_CODE_META]->(:CodeMeta {chunk_id: 'kept'}),
+			(c)-[:HAS_EMBEDDING]->(:ChunkEmbedding {chunk_id: 'kept'}),
+			(o:Chunk {id: 'orphan', updated_at: 0}),
+			(o)-[:HAS_CODE_META]->(:CodeMeta {chunk_id: 'orphan'}),
+			(o)-[:HAS_EMBEDDING]->(:ChunkEmbedding {chunk_id: 'orphan'}),
+			(:Chunk {id: 'recent', updated_at: %d}),
+			(:DocumentMeta {chunk_id: 'stray'})
+	`, time.Now().Unix())
+	if _, err := g.Query(ctx, seed); err != nil {
+		t.Fatalf("seed failed: %v", err)
+	}
+
+	stats, err := g.PruneOrphans(ctx)
+	if err != nil {
+		t.Fatalf("PruneOrphans failed: %v", err)
+	}
+	for label, want := range map[string]int{"Chunk": 1, "CodeMeta": 1, "ChunkEmbedding": 1, "DocumentMeta": 1} {
+		if stats.Deleted[label] != want {
+			t.Errorf("deleted %s = %d, want %d", label, stats.Deleted[label], want)
+		}
+	}
+
+	remaining := func(label, key string) []string {
+		result, err := g.Query(ctx, fmt.Sprintf("MATCH (n:%s) RETURN n.%s ORDER BY n.%s", label, key, key))
+		if err != nil {
+			t.Fatalf("query %s failed: %v", label, err)
+		}
+		var ids []string
+		for _, row := range result.Rows {
+			ids = append(ids, fmt.Sprint(row[0]))
+		}
+		return ids
+	}
+	for label, want := range map[string][]string{
+		"File":           {"/repo/kept.go"},
+		"Chunk":          {"kept", "recent"},
+		"CodeMeta":       {"kept"},
+		"ChunkEmbedding": {"kept"},
+		"DocumentMeta":   nil,
+	} {
+		key := "chunk_id"
+		switch label {
+		case "File":
+			key = "path"
+		case "Chunk":
+			key = "id"
+		}
+		if got := remaining(label, key); !slices.Equal(got, want) {
+			t.Errorf("remaining %s = %v, want %v", label, got, want)
+		}
+	}
+}
+
+func TestToFloat32s(t *testing.T) {
+	tests := []struct {
+		name string
+		val  any
+		want []float32
+	}{
+		{"floats", []any{0.5, 1.0, -0.25}, []float32{0.5, 1, -0.25}},
+		{"integers", []any{int64(1), 2}, []float32{1, 2}},
+		{"non-numeric item", []any{0.5, "x"}, nil},
+		{"empty", []any{}, nil},
+		{"not a list", "0.5", nil},
+		{"nil", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := toFloat32s(tt.val)
+			if len(got) != len(tt.want) {
+				t.Fatalf("toFloat32s(%v) = %v, want %v", tt.val, got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("toFloat32s(%v)[%d] = %v, want %v", tt.val, i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
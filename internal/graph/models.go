@@ -26,24 +26,27 @@ const (
 
 // Relationship types for the graph schema.
 const (
-	RelContains        = "CONTAINS"          // Directory -> File/Directory
-	RelHasChunk        = "HAS_CHUNK"         // File -> Chunk
-	RelHasTag          = "HAS_TAG"           // File -> Tag
-	RelCoversTopic     = "COVERS_TOPIC"      // File -> Topic
-	RelMentions        = "MENTIONS"          // File/Chunk -> Entity
-	RelReferences      = "REFERENCES"        // File/Chunk -> File/URL
-	RelSimilarTo       = "SIMILAR_TO"        // Chunk -> Chunk (semantic similarity)
-	RelDependsOn       = "DEPENDS_ON"        // File -> File (code dependencies)
-	RelHasCodeMeta     = "HAS_CODE_META"     // Chunk -> CodeMeta
-	RelHasDocMeta      = "HAS_DOC_META"      // Chunk -> DocumentMeta
-	RelHasNotebookMeta = "HAS_NOTEBOOK_META" // Chunk -> NotebookMeta
-	RelHasBuildMeta    = "HAS_BUILD_META"    // Chunk -> BuildMeta
-	RelHasInfraMeta    = "HAS_INFRA_META"    // Chunk -> InfraMeta
-	RelHasSchemaMeta   = "HAS_SCHEMA_META"   // Chunk -> SchemaMeta
-	RelHasStructMeta   = "HAS_STRUCT_META"   // Chunk -> StructuredMeta
-	RelHasSQLMeta      = "HAS_SQL_META"      // Chunk -> SQLMeta
-	RelHasLogMeta      = "HAS_LOG_META"      // Chunk -> LogMeta
-	RelHasEmbedding    = "HAS_EMBEDDING"     // Chunk -> ChunkEmbedding
+	RelContains          = "CONTAINS"           // Directory -> File/Directory
+	RelHasChunk          = "HAS_CHUNK"          // File -> Chunk
+	RelNextChunk         = "NEXT_CHUNK"         // Chunk -> Chunk (reading order within a file)
+	RelHasTag            = "HAS_TAG"            // File -> Tag
+	RelCoversTopic       = "COVERS_TOPIC"       // File -> Topic
+	RelMentions          = "MENTIONS"           // File/Chunk -> Entity
+	RelReferences        = "REFERENCES"         // File/Chunk -> File/URL
+	RelReferencesSection = "REFERENCES_SECTION" // Chunk -> Chunk (in-document anchor)
+	RelSimilarTo         = "SIMILAR_TO"         // Chunk -> Chunk (semantic similarity)
+	RelDependsOn         = "DEPENDS_ON"         // File -> File (code dependencies)
+	RelHasCodeMeta       = "HAS_CODE_META"      // Chunk -> CodeMeta
+	RelHasDocMeta        = "HAS_DOC_META"       // Chunk -> DocumentMeta
+	RelHasNotebookMeta   = "HAS_NOTEBOOK_META"  // Chunk -> NotebookMeta
+	RelHasBuildMeta      = "HAS_BUILD_META"     // Chunk -> BuildMeta
+	RelHasInfraMeta      = "HAS_INFRA_META"     // Chunk -> InfraMeta
+	RelHasSchemaMeta     = "HAS_SCHEMA_META"    // Chunk -> SchemaMeta
+	RelHasStructMeta     = "HAS_STRUCT_META"    // Chunk -> StructuredMeta
+	RelHasSQLMeta        = "HAS_SQL_META"       // Chunk -> SQLMeta
+	RelHasLogMeta        = "HAS_LOG_META"       // Chunk -> LogMeta
+	RelHasEmbedding      = "HAS_EMBEDDING"      // Chunk -> ChunkEmbedding
+	RelRelated           = "RELATED"            // Entity -> Entity (semantic relation)
 )
 
 // FileNode represents a file in the knowledge graph.
@@ -72,6 +75,10 @@ type FileNode struct {
 	// IngestReason explains why the ingest mode was selected.
 	IngestReason string `json:"ingest_reason,omitempty"`
 
+	// ChangeReason explains why the file was (re)ingested (new, modified,
+	// forced, version_bump).
+	ChangeReason string `json:"change_reason,omitempty"`
+
 	// Size is the file size in bytes.
 	Size int64 `json:"size"`
 
@@ -116,8 +123,9 @@ type ChunkNode struct {
 	// Index is the chunk index within the file.
 	Index int `json:"index"`
 
-	// Content is the chunk text content.
-	Content string `json:"content"`
+	// Content is the chunk text content. It is only persisted when
+	// Config.StoreChunkContent is enabled.
+	Content string `json:"content,omitempty"`
 
 	// ContentHash is the hash of the chunk content.
 	ContentHash string `json:"content_hash"`
@@ -134,6 +142,16 @@ type ChunkNode struct {
 	// TokenCount is the estimated token count.
 	TokenCount int `json:"token_count,omitempty"`
 
+	// BoundaryConfidence rates how natural the chunk boundaries are (1.0 for
+	// structural splits, lower for heuristic or fixed-window splits).
+	BoundaryConfidence float64 `json:"boundary_confidence,omitempty"`
+
+	// LineStart is the 1-based first source line of the chunk, or zero if unknown.
+	LineStart int `json:"line_start,omitempty"`
+
+	// LineEnd is the 1-based last source line of the chunk, or zero if unknown.
+	LineEnd int `json:"line_end,omitempty"`
+
 	// Summary is the semantic summary of the chunk.
 	Summary string `json:"summary,omitempty"`
 
@@ -145,13 +163,30 @@ type ChunkNode struct {
 }
 
 // ChunkSearchHit represents a semantic search result with similarity score.
+// Score is higher-is-better: cosine similarity for cosine indexes, and
+// 1 / (1 + distance) for euclidean ones. Distance is the raw vector index
+// distance, which is lower-is-better.
 type ChunkSearchHit struct {
 	Chunk    ChunkNode `json:"chunk"`
 	Score    float64   `json:"score"`
+	Distance float64   `json:"distance"`
 	Provider string    `json:"provider,omitempty"`
 	Model    string    `json:"model,omitempty"`
 }
 
+// ChunkFilter restricts a similarity search. Empty fields match everything.
+type ChunkFilter struct {
+	// FilePathPrefix keeps chunks whose file path starts with the prefix.
+	// Use a trailing separator to match a directory and not its siblings.
+	FilePathPrefix string `json:"file_path_prefix,omitempty"`
+
+	// Language keeps chunks of files detected as the language, such as "go".
+	Language string `json:"language,omitempty"`
+
+	// ChunkType keeps chunks of the type, such as "code" or "markdown".
+	ChunkType string `json:"chunk_type,omitempty"`
+}
+
 // CodeMetaNode stores code-specific metadata for a chunk.
 type CodeMetaNode struct {
 	Language     string   `json:"language,omitempty"`
@@ -162,6 +197,7 @@ type CodeMetaNode struct {
 	Parameters   []string `json:"parameters,omitempty"`
 	Decorators   []string `json:"decorators,omitempty"`
 	Implements   []string `json:"implements,omitempty"`
+	Raises       []string `json:"raises,omitempty"`
 	Visibility   string   `json:"visibility,omitempty"`
 	Docstring    string   `json:"docstring,omitempty"`
 	Namespace    string   `json:"namespace,omitempty"`
@@ -169,6 +205,7 @@ type CodeMetaNode struct {
 	IsAsync      bool     `json:"is_async,omitempty"`
 	IsStatic     bool     `json:"is_static,omitempty"`
 	IsExported   bool     `json:"is_exported,omitempty"`
+	IsTest       bool     `json:"is_test,omitempty"`
 	LineStart    int      `json:"line_start,omitempty"`
 	LineEnd      int      `json:"line_end,omitempty"`
 }
@@ -256,6 +293,7 @@ type ChunkEmbeddingNode struct {
 	Model      string    `json:"model"`
 	Dimensions int       `json:"dimensions"`
 	Embedding  []float32 `json:"embedding"`
+	Version    int       `json:"version"`
 	CreatedAt  time.Time `json:"created_at"`
 }
 
@@ -280,6 +318,34 @@ type DirectoryNode struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// DirectoryTree is a directory with its nested subdirectories and files.
+type DirectoryTree struct {
+	// Path is the absolute path to the directory.
+	Path string `json:"path"`
+
+	// Name is the directory name.
+	Name string `json:"name"`
+
+	// IsRemembered indicates if this is a remembered root directory.
+	IsRemembered bool `json:"is_remembered"`
+
+	// FileCount is the number of files directly in this directory.
+	FileCount int `json:"file_count"`
+
+	// Files are the files directly in this directory.
+	Files []DirectoryTreeFile `json:"files,omitempty"`
+
+	// Children are the subdirectories within the depth bound.
+	Children []*DirectoryTree `json:"children,omitempty"`
+}
+
+// DirectoryTreeFile is a leaf file summary in a DirectoryTree.
+type DirectoryTreeFile struct {
+	Path    string `json:"path"`
+	Name    string `json:"name"`
+	Summary string `json:"summary,omitempty"`
+}
+
 // TagNode represents a tag in the knowledge graph.
 type TagNode struct {
 	// Name is the tag name.
@@ -343,12 +409,53 @@ type Entity struct {
 	Type string `json:"type"`
 }
 
+// EntityRelation represents a predicate linking two named entities.
+type EntityRelation struct {
+	Subject   string `json:"subject"`
+	Predicate string `json:"predicate"`
+	Object    string `json:"object"`
+}
+
 // Reference represents a reference to another resource.
 type Reference struct {
 	Type   string `json:"type"`   // url, file, package, symbol
 	Target string `json:"target"` // the actual reference value
 }
 
+// RelatedFile is a file recommended as related to another, with the signals
+// that contributed to its score.
+type RelatedFile struct {
+	Path           string   `json:"path"`
+	Score          float64  `json:"score"`
+	SharedTags     []string `json:"shared_tags,omitempty"`
+	SharedTopics   []string `json:"shared_topics,omitempty"`
+	SharedEntities []string `json:"shared_entities,omitempty"`
+	SharedImports  []string `json:"shared_imports,omitempty"`
+	Similarity     float64  `json:"similarity,omitempty"` // average chunk-embedding similarity
+}
+
+// RelatedFileWeights weights each signal when scoring related files. Shared
+// tags, topics, entities and imports count per shared item; Similarity scales
+// the average chunk-embedding similarity.
+type RelatedFileWeights struct {
+	Tags       float64
+	Topics     float64
+	Entities   float64
+	Imports    float64
+	Similarity float64
+}
+
+// DefaultRelatedFileWeights returns the default related file weights.
+func DefaultRelatedFileWeights() RelatedFileWeights {
+	return RelatedFileWeights{
+		Tags:       1.0,
+		Topics:     1.0,
+		Entities:   0.5,
+		Imports:    0.5,
+		Similarity: 2.0,
+	}
+}
+
 // QueryResult contains the results of a Cypher query.
 type QueryResult struct {
 	// Columns are the column names returned.
@@ -371,6 +478,21 @@ type QueryStats struct {
 	ExecutionTimeMs  float64
 }
 
+// PruneStats contains the results of pruning orphaned nodes.
+type PruneStats struct {
+	// Deleted is the number of orphaned nodes deleted, keyed by node label.
+	Deleted map[string]int `json:"deleted"`
+}
+
+// Total returns the number of orphaned nodes deleted across all labels.
+func (s PruneStats) Total() int {
+	total := 0
+	for _, n := range s.Deleted {
+		total += n
+	}
+	return total
+}
+
 // GraphSnapshot contains a point-in-time snapshot of the graph.
 type GraphSnapshot struct {
 	// Files are all file nodes.
@@ -388,6 +510,10 @@ type GraphSnapshot struct {
 	// Entities are all entity nodes.
 	Entities []EntityNode `json:"entities"`
 
+	// Chunks are the chunk nodes with stored content. Chunk content is only
+	// stored when Config.StoreChunkContent is enabled.
+	Chunks []ChunkNode `json:"chunks,omitempty"`
+
 	// TotalChunks is the total number of chunks.
 	TotalChunks int `json:"total_chunks"`
 
@@ -397,6 +523,10 @@ type GraphSnapshot struct {
 	// ExportedAt is when the snapshot was created.
 	ExportedAt time.Time `json:"exported_at"`
 
+	// Since is the lower bound of a delta snapshot; nil for full snapshots.
+	// The next delta in a chain uses this snapshot's ExportedAt.
+	Since *time.Time `json:"since,omitempty"`
+
 	// Version is the snapshot format version.
 	Version int `json:"version"`
 }
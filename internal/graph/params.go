@@ -0,0 +1,145 @@
+package graph
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// parameterized prefixes cypher with a CYPHER parameter header binding params,
+// which is how RedisGraph and FalkorDB receive query parameters. The query
+// refers to each parameter as $name, so values never become part of the query
+// text. Keys are written in sorted order to keep queries deterministic.
+func parameterized(cypher string, params map[string]any) string {
+	if len(params) == 0 {
+		return cypher
+	}
+
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	var sb strings.Builder
+	sb.WriteString("CYPHER")
+	for _, key := range keys {
+		sb.WriteByte(' ')
+		sb.WriteString(key)
+		sb.WriteByte('=')
+		sb.WriteString(cypherLiteral(params[key]))
+	}
+	sb.WriteByte(' ')
+	sb.WriteString(cypher)
+	return sb.String()
+}
+
+// cypherLiteral encodes a parameter value as a Cypher literal. Unsupported
+// types are encoded as their string form.
+func cypherLiteral(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return quoteCypherString(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float32:
+		return formatCypherFloat(float64(v))
+	case float64:
+		return formatCypherFloat(v)
+	case []string:
+		return cypherList(v)
+	case []float32:
+		return cypherList(v)
+	case []any:
+		return cypherList(v)
+	case map[string]any:
+		return cypherMap(v)
+	case []map[string]any:
+		return cypherList(v)
+	default:
+		return quoteCypherString(fmt.Sprint(v))
+	}
+}
+
+// cypherList encodes a slice as a Cypher list literal.
+func cypherList[T any](values []T) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = cypherLiteral(v)
+	}
+	return "[" + strings.Join(parts, ",") + "]"
+}
+
+// cypherMap encodes a map as a Cypher map literal with sorted keys. Keys must
+// be valid identifiers.
+func cypherMap(values map[string]any) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = key + ": " + cypherLiteral(values[key])
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// formatCypherFloat formats f so that it always parses as a Cypher float.
+// Non-finite values have no literal form and are encoded as null.
+func formatCypherFloat(f float64) string {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "null"
+	}
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	if !strings.Contains(s, ".") {
+		s += ".0"
+	}
+	return s
+}
+
+// quoteCypherString encodes s as a double-quoted Cypher string literal.
+// Control characters are escaped, invalid UTF-8 is replaced, and NUL bytes are
+// dropped because FalkorDB stores strings NUL-terminated.
+func quoteCypherString(s string) string {
+	if !utf8.ValidString(s) {
+		s = strings.ToValidUTF8(s, string(utf8.RuneError))
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(s) + 2)
+	sb.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case 0:
+		case '"':
+			sb.WriteString(`\"`)
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&sb, `\u%04x`, r)
+				continue
+			}
+			sb.WriteRune(r)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
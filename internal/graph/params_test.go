@@ -0,0 +1,81 @@
+package graph
+
+import (
+	"math"
+	"testing"
+)
+
+func TestQuoteCypherString(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"plain", "hello", `"hello"`},
+		{"empty", "", `""`},
+		{"single quote", "it's", `"it's"`},
+		{"double quote", `say "hi"`, `"say \"hi\""`},
+		{"backslash", `C:\notes\new.md`, `"C:\\notes\\new.md"`},
+		{"literal backslash n", `/docs/a\n.md`, `"/docs/a\\n.md"`},
+		{"newline and tab", "a\nb\tc\r", `"a\nb\tc\r"`},
+		{"control character", "bell\x07", `"bell\u0007"`},
+		{"null byte dropped", "a\x00b", `"ab"`},
+		{"unicode", "日本語 ☃ café", `"日本語 ☃ café"`},
+		{"invalid utf-8", "a\xffb", "\"a\uFFFDb\""},
+		{"cypher syntax", "x'}) DETACH DELETE f //", `"x'}) DETACH DELETE f //"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := quoteCypherString(tt.input); got != tt.want {
+				t.Errorf("quoteCypherString(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCypherLiteral(t *testing.T) {
+	tests := []struct {
+		name  string
+		input any
+		want  string
+	}{
+		{"nil", nil, "null"},
+		{"bool", true, "true"},
+		{"int", 42, "42"},
+		{"int64", int64(-7), "-7"},
+		{"float64", 0.25, "0.25"},
+		{"whole float64", 2.0, "2.0"},
+		{"float32", float32(0.5), "0.5"},
+		{"NaN", math.NaN(), "null"},
+		{"string slice", []string{"a", `b"c`}, `["a","b\"c"]`},
+		{"nil string slice", []string(nil), "[]"},
+		{"float32 slice", []float32{0.5, 1}, "[0.5,1.0]"},
+		{"mixed slice", []any{"a", 1, false}, `["a",1,false]`},
+		{"map", map[string]any{"path": `it's "x"`, "index": 1}, `{index: 1, path: "it's \"x\""}`},
+		{"map slice", []map[string]any{{"id": "a"}, {"id": "b"}}, `[{id: "a"},{id: "b"}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cypherLiteral(tt.input); got != tt.want {
+				t.Errorf("cypherLiteral(%v) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParameterized(t *testing.T) {
+	query := parameterized("MATCH (f:File {path: $path}) RETURN f.size > $min", map[string]any{
+		"path": "/docs/it's.md",
+		"min":  10,
+	})
+	want := `CYPHER min=10 path="/docs/it's.md" MATCH (f:File {path: $path}) RETURN f.size > $min`
+	if query != want {
+		t.Errorf("parameterized() = %q, want %q", query, want)
+	}
+
+	if got := parameterized("MATCH (n) RETURN n", nil); got != "MATCH (n) RETURN n" {
+		t.Errorf("parameterized() without params = %q, want the query unchanged", got)
+	}
+}
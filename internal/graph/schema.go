@@ -60,12 +60,42 @@ var metadataIndexes = []string{
 	"CREATE INDEX FOR (e:ChunkEmbedding) ON (e.model)",
 }
 
-// initSchema creates all indexes and constraints for the graph.
-// Safe to call multiple times - existing indexes are ignored.
-func (g *FalkorDBGraph) initSchema(ctx context.Context) error {
+// DistanceMetric is the similarity function used by the chunk vector index.
+type DistanceMetric string
+
+const (
+	// DistanceCosine compares embedding direction. Suited to normalized embeddings.
+	DistanceCosine DistanceMetric = "cosine"
+
+	// DistanceEuclidean compares embeddings by straight-line distance.
+	DistanceEuclidean DistanceMetric = "euclidean"
+)
+
+// Valid reports whether m is a supported distance metric.
+func (m DistanceMetric) Valid() bool {
+	return m == DistanceCosine || m == DistanceEuclidean
+}
+
+// Similarity converts a vector index distance, where lower is closer, into a
+// higher-is-better score. Cosine distance is 1 - cosine similarity, so it is
+// converted back; euclidean distance maps to 1 / (1 + distance).
+func (m DistanceMetric) Similarity(distance float64) float64 {
+	if m == DistanceEuclidean {
+		return 1 / (1 + distance)
+	}
+	return 1 - distance
+}
+
+// initSchema creates all indexes and constraints for the graph on q.
+// Safe to call multiple times - existing indexes are ignored. It returns an
+// error only when the connection fails.
+func (g *FalkorDBGraph) initSchema(ctx context.Context, q graphQuerier) error {
 	// Create core indexes
 	for _, query := range coreIndexes {
-		if _, err := g.query(query); err != nil {
+		if _, err := q.Query(query); err != nil {
+			if isFatalGraphError(err) {
+				return err
+			}
 			// Ignore errors for existing indexes
 			g.logger.Debug("schema query", "query", query, "error", err)
 		}
@@ -73,26 +103,29 @@ func (g *FalkorDBGraph) initSchema(ctx context.Context) error {
 
 	// Create metadata indexes
 	for _, query := range metadataIndexes {
-		if _, err := g.query(query); err != nil {
+		if _, err := q.Query(query); err != nil {
+			if isFatalGraphError(err) {
+				return err
+			}
 			// Ignore errors for existing indexes
 			g.logger.Debug("schema query", "query", query, "error", err)
 		}
 	}
 
 	// Create vector index for similarity search
-	if err := g.initVectorIndex(ctx); err != nil {
-		g.logger.Warn("failed to create vector index", "error", err)
+	if err := g.initVectorIndex(ctx, q); err != nil {
+		return err
 	}
 
 	return nil
 }
 
 // initVectorIndex creates an HNSW vector index on ChunkEmbedding.embedding.
-func (g *FalkorDBGraph) initVectorIndex(ctx context.Context) error {
-	dim := g.config.EmbeddingDimension
-	if dim == 0 {
-		dim = 1536 // Default OpenAI text-embedding-3-small
-	}
+// An existing index keeps the metric it was created with, so changing the
+// configured metric requires dropping the index first.
+func (g *FalkorDBGraph) initVectorIndex(ctx context.Context, q graphQuerier) error {
+	dim := g.embeddingDimension()
+	metric := g.distanceMetric()
 
 	// FalkorDB uses CREATE VECTOR INDEX syntax
 	query := fmt.Sprintf(`
@@ -100,16 +133,22 @@ func (g *FalkorDBGraph) initVectorIndex(ctx context.Context) error {
 		OPTIONS {
 			indexType: 'HNSW',
 			dimension: %d,
-			similarityFunction: 'cosine'
+			similarityFunction: '%s'
 		}
-	`, dim)
+	`, dim, metric)
 
-	if _, err := g.query(query); err != nil {
+	if _, err := q.Query(query); err != nil {
+		if isFatalGraphError(err) {
+			return err
+		}
 		// Try alternative syntax for older FalkorDB versions
 		altQuery := fmt.Sprintf(`
-			CALL db.idx.vector.createNodeIndex('ChunkEmbedding', 'embedding', %d, 'cosine')
-		`, dim)
-		if _, altErr := g.query(altQuery); altErr != nil {
+			CALL db.idx.vector.createNodeIndex('ChunkEmbedding', 'embedding', %d, '%s')
+		`, dim, metric)
+		if _, altErr := q.Query(altQuery); altErr != nil {
+			if isFatalGraphError(altErr) {
+				return altErr
+			}
 			g.logger.Debug("vector index creation failed",
 				"primary_error", err,
 				"alt_error", altErr)
@@ -120,7 +159,8 @@ func (g *FalkorDBGraph) initVectorIndex(ctx context.Context) error {
 	g.logger.Info("vector index created/verified",
 		"label", "ChunkEmbedding",
 		"property", "embedding",
-		"dimension", dim)
+		"dimension", dim,
+		"metric", metric)
 
 	return nil
 }
@@ -1,6 +1,7 @@
 package graph
 
 import (
+	"context"
 	"strings"
 	"testing"
 )
@@ -269,3 +270,43 @@ func TestTotalIndexCount(t *testing.T) {
 		}
 	})
 }
+
+func TestInitVectorIndexUsesDistanceMetric(t *testing.T) {
+	tests := []struct {
+		metric DistanceMetric
+		want   string
+	}{
+		{"", "similarityFunction: 'cosine'"},
+		{DistanceCosine, "similarityFunction: 'cosine'"},
+		{DistanceEuclidean, "similarityFunction: 'euclidean'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.metric), func(t *testing.T) {
+			g, primary := newReplicatedTestGraph()
+			g.config.DistanceMetric = tt.metric
+
+			if err := g.initVectorIndex(context.Background(), primary); err != nil {
+				t.Fatalf("initVectorIndex failed: %v", err)
+			}
+			if len(primary.queries) != 1 || !strings.Contains(primary.queries[0], tt.want) {
+				t.Errorf("vector index query = %v, want it to contain %q", primary.queries, tt.want)
+			}
+		})
+	}
+}
+
+func TestDistanceMetricSimilarity(t *testing.T) {
+	if got := DistanceCosine.Similarity(0.25); got != 0.75 {
+		t.Errorf("cosine Similarity(0.25) = %v, want 0.75", got)
+	}
+	if got := DistanceEuclidean.Similarity(1); got != 0.5 {
+		t.Errorf("euclidean Similarity(1) = %v, want 0.5", got)
+	}
+	if DistanceEuclidean.Similarity(0.1) <= DistanceEuclidean.Similarity(2) {
+		t.Error("closer embeddings should score higher")
+	}
+	if !DistanceCosine.Valid() || !DistanceEuclidean.Valid() || DistanceMetric("dot").Valid() {
+		t.Error("Valid() should accept only cosine and euclidean")
+	}
+}
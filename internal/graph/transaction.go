@@ -0,0 +1,115 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// ErrTransactionFailed is returned when a query inside a committed
+// transaction fails. Redis does not undo the transaction's other queries, so
+// callers that need all-or-nothing semantics must remove partial state.
+var ErrTransactionFailed = errors.New("transaction query failed")
+
+// errTransactionFinished is returned when writing to a transaction that has
+// already been committed or rolled back.
+var errTransactionFinished = errors.New("transaction already finished")
+
+type transactionKey struct{}
+
+// Transaction collects graph writes so they commit together. Writes made
+// with a transaction-carrying context are recorded instead of queued, and
+// have no effect until the transaction is committed with CommitTransaction.
+type Transaction struct {
+	mu       sync.Mutex
+	queries  []string
+	finished bool
+}
+
+// WithTransaction returns a context carrying a new transaction.
+func WithTransaction(ctx context.Context) (context.Context, *Transaction) {
+	tx := &Transaction{}
+	return context.WithValue(ctx, transactionKey{}, tx), tx
+}
+
+// TransactionFromContext returns the transaction carried by ctx, or nil.
+func TransactionFromContext(ctx context.Context) *Transaction {
+	tx, _ := ctx.Value(transactionKey{}).(*Transaction)
+	return tx
+}
+
+// Len returns the number of writes recorded in the transaction.
+func (tx *Transaction) Len() int {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	return len(tx.queries)
+}
+
+// Rollback discards the transaction's writes. Nothing has reached the graph
+// before commit, so this only prevents a later commit.
+func (tx *Transaction) Rollback() {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	tx.queries = nil
+	tx.finished = true
+}
+
+// add records a write.
+func (tx *Transaction) add(query string) error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if tx.finished {
+		return errTransactionFinished
+	}
+	tx.queries = append(tx.queries, query)
+	return nil
+}
+
+// finish marks the transaction finished and returns its writes.
+func (tx *Transaction) finish() ([]string, error) {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if tx.finished {
+		return nil, errTransactionFinished
+	}
+	tx.finished = true
+	return tx.queries, nil
+}
+
+// graphTransactor executes a set of Cypher queries as one transaction.
+type graphTransactor interface {
+	Exec(queries []string) error
+}
+
+// redisTransactor runs queries in a Redis MULTI/EXEC block, so the graph
+// applies either all of them or, if the client never reaches EXEC, none.
+type redisTransactor struct {
+	conn      redis.Conn
+	graphName string
+}
+
+// Exec sends queries between MULTI and EXEC and reports the first failing query.
+func (t *redisTransactor) Exec(queries []string) error {
+	if err := t.conn.Send("MULTI"); err != nil {
+		return err
+	}
+	for _, query := range queries {
+		if err := t.conn.Send("GRAPH.QUERY", t.graphName, query, "--compact"); err != nil {
+			return err
+		}
+	}
+
+	replies, err := redis.Values(t.conn.Do("EXEC"))
+	if err != nil {
+		return err
+	}
+	for i, reply := range replies {
+		if replyErr, ok := reply.(redis.Error); ok {
+			return fmt.Errorf("%w; query %d of %d: %v", ErrTransactionFailed, i+1, len(queries), replyErr)
+		}
+	}
+	return nil
+}
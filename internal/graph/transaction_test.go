@@ -0,0 +1,131 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// fakeConn is a redis.Conn recording sent commands and answering EXEC with execReply.
+type fakeConn struct {
+	sent      []string
+	execReply any
+}
+
+func (c *fakeConn) Close() error { return nil }
+func (c *fakeConn) Err() error   { return nil }
+func (c *fakeConn) Flush() error { return nil }
+func (c *fakeConn) Receive() (any, error) {
+	return nil, errors.New("not supported")
+}
+func (c *fakeConn) Send(cmd string, args ...any) error {
+	c.sent = append(c.sent, strings.TrimSpace(fmt.Sprintln(append([]any{cmd}, args...)...)))
+	return nil
+}
+func (c *fakeConn) Do(cmd string, args ...any) (any, error) {
+	c.sent = append(c.sent, cmd)
+	return c.execReply, nil
+}
+
+func TestTransaction(t *testing.T) {
+	t.Run("RecordsWritesInsteadOfQueueing", func(t *testing.T) {
+		g, primary := newReplicatedTestGraph()
+		ctx, tx := WithTransaction(context.Background())
+
+		if err := g.UpsertFile(ctx, &FileNode{Path: "/docs/a.md", Name: "a.md"}); err != nil {
+			t.Fatalf("UpsertFile failed: %v", err)
+		}
+		if err := g.DeleteChunks(ctx, "/docs/a.md"); err != nil {
+			t.Fatalf("DeleteChunks failed: %v", err)
+		}
+		if err := g.SetFileTags(ctx, "/docs/a.md", []string{"go", "graphs"}); err != nil {
+			t.Fatalf("SetFileTags failed: %v", err)
+		}
+
+		if got := len(g.writeQueue); got != 0 {
+			t.Errorf("queued writes = %d, want 0 before commit", got)
+		}
+		// File and directory upserts, two chunk deletes, a tag reset and two tags
+		if got := tx.Len(); got != 7 {
+			t.Fatalf("transaction writes = %d, want 7", got)
+		}
+
+		commitErr := make(chan error, 1)
+		go func() { commitErr <- g.CommitTransaction(context.Background(), tx) }()
+		g.executeWrite(<-g.writeQueue)
+		if err := <-commitErr; err != nil {
+			t.Fatalf("CommitTransaction failed: %v", err)
+		}
+
+		if len(primary.transactions) != 1 || len(primary.transactions[0]) != 7 {
+			t.Fatalf("transactions = %d, want one with 7 queries", len(primary.transactions))
+		}
+		if !strings.Contains(primary.transactions[0][0], "MERGE (f:File {path: $path})") {
+			t.Errorf("first query = %q, want the file upsert", primary.transactions[0][0])
+		}
+		if len(primary.queries) != 0 {
+			t.Errorf("individual queries = %d, want 0", len(primary.queries))
+		}
+	})
+
+	t.Run("FinishedTransactionRejectsWrites", func(t *testing.T) {
+		g, _ := newReplicatedTestGraph()
+		ctx, tx := WithTransaction(context.Background())
+		tx.Rollback()
+
+		if err := g.UpsertFile(ctx, &FileNode{Path: "/docs/a.md"}); !errors.Is(err, errTransactionFinished) {
+			t.Errorf("UpsertFile after rollback error = %v, want %v", err, errTransactionFinished)
+		}
+		if err := g.CommitTransaction(context.Background(), tx); !errors.Is(err, errTransactionFinished) {
+			t.Errorf("CommitTransaction after rollback error = %v, want %v", err, errTransactionFinished)
+		}
+	})
+
+	t.Run("FailedTransactionIsNotRetried", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.RetryDelay = time.Millisecond
+		g, primary := newReplicatedTestGraph()
+		g.config = cfg
+		primary.execErr = fmt.Errorf("%w; query 2 of 2: boom", ErrTransactionFailed)
+
+		result := make(chan error, 1)
+		g.executeWrite(writeOp{queries: []string{"q1", "q2"}, result: result})
+		if err := <-result; !errors.Is(err, ErrTransactionFailed) {
+			t.Errorf("executeWrite error = %v, want %v", err, ErrTransactionFailed)
+		}
+		if len(primary.transactions) != 1 {
+			t.Errorf("transaction attempts = %d, want 1", len(primary.transactions))
+		}
+	})
+}
+
+func TestRedisTransactor(t *testing.T) {
+	t.Run("WrapsQueriesInMultiExec", func(t *testing.T) {
+		conn := &fakeConn{execReply: []any{[]any{}, []any{}}}
+		tx := &redisTransactor{conn: conn, graphName: "memorizer"}
+
+		if err := tx.Exec([]string{"q1", "q2"}); err != nil {
+			t.Fatalf("Exec failed: %v", err)
+		}
+		want := []string{"MULTI", "GRAPH.QUERY memorizer q1 --compact", "GRAPH.QUERY memorizer q2 --compact", "EXEC"}
+		if !reflect.DeepEqual(conn.sent, want) {
+			t.Errorf("sent = %q, want %q", conn.sent, want)
+		}
+	})
+
+	t.Run("ReportsFailedQuery", func(t *testing.T) {
+		conn := &fakeConn{execReply: []any{[]any{}, redis.Error("Invalid input")}}
+		tx := &redisTransactor{conn: conn, graphName: "memorizer"}
+
+		err := tx.Exec([]string{"q1", "q2"})
+		if !errors.Is(err, ErrTransactionFailed) || !strings.Contains(err.Error(), "query 2 of 2") {
+			t.Errorf("Exec error = %v, want failed query 2", err)
+		}
+	})
+}
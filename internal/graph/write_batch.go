@@ -0,0 +1,76 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+type writeBatchKey struct{}
+
+// WriteBatch correlates asynchronous graph writes with the operation that
+// queued them. Writes queued with a batch-carrying context report their
+// outcome to the batch, so callers can learn about failures that happen after
+// the write method has already returned.
+type WriteBatch struct {
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	errs []error
+}
+
+// WithWriteBatch returns a context carrying a new write batch.
+func WithWriteBatch(ctx context.Context) (context.Context, *WriteBatch) {
+	batch := &WriteBatch{}
+	return context.WithValue(ctx, writeBatchKey{}, batch), batch
+}
+
+// WriteBatchFromContext returns the write batch carried by ctx, or nil.
+func WriteBatchFromContext(ctx context.Context) *WriteBatch {
+	batch, _ := ctx.Value(writeBatchKey{}).(*WriteBatch)
+	return batch
+}
+
+// Track registers a pending write and returns the callback that completes it.
+// The callback must be called exactly once. Track on a nil batch returns a no-op.
+func (b *WriteBatch) Track() func(error) {
+	if b == nil {
+		return func(error) {}
+	}
+
+	b.wg.Add(1)
+	var once sync.Once
+	return func(err error) {
+		once.Do(func() {
+			if err != nil {
+				b.mu.Lock()
+				b.errs = append(b.errs, err)
+				b.mu.Unlock()
+			}
+			b.wg.Done()
+		})
+	}
+}
+
+// Wait blocks until every tracked write has completed or ctx expires, and
+// returns the joined errors of the writes that failed.
+func (b *WriteBatch) Wait(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return errors.Join(b.errs...)
+}
@@ -46,6 +46,20 @@ const (
 	ReasonUnsupported      = "unsupported"
 )
 
+// ChangeReason explains why a file was (re)ingested.
+type ChangeReason string
+
+const (
+	// ChangeNew marks a file with no prior analysis state.
+	ChangeNew ChangeReason = "new"
+	// ChangeModified marks a file whose content or metadata changed.
+	ChangeModified ChangeReason = "modified"
+	// ChangeForced marks a file re-analyzed without any detected change.
+	ChangeForced ChangeReason = "forced"
+	// ChangeVersionBump marks an unchanged file analyzed by an older analysis version.
+	ChangeVersionBump ChangeReason = "version_bump"
+)
+
 // Decision explains how and why a file is handled.
 type Decision struct {
 	Kind   Kind
@@ -42,6 +42,7 @@ type mockGraph struct {
 	searchErr           error
 	lastSearchEmbedding []float32
 	lastSearchK         int
+	lastSearchFilter    graph.ChunkFilter
 }
 
 func newMockGraph() *mockGraph {
@@ -93,12 +94,21 @@ func (m *mockGraph) DeleteDirectoriesUnderPath(ctx context.Context, parentPath s
 func (m *mockGraph) UpsertChunkWithMetadata(ctx context.Context, chunk *graph.ChunkNode, meta *chunkers.ChunkMetadata) error {
 	return nil
 }
+func (m *mockGraph) UpsertChunksBatch(ctx context.Context, chunks []*graph.ChunkNode, metas []*chunkers.ChunkMetadata) error {
+	return nil
+}
+func (m *mockGraph) CommitTransaction(ctx context.Context, tx *graph.Transaction) error {
+	return nil
+}
 func (m *mockGraph) UpsertChunkEmbedding(ctx context.Context, chunkID string, emb *graph.ChunkEmbeddingNode) error {
 	return nil
 }
 func (m *mockGraph) DeleteChunkEmbeddings(ctx context.Context, chunkID string, provider, model string) error {
 	return nil
 }
+func (m *mockGraph) DeleteFileEmbeddings(ctx context.Context, filePath string, provider, model string) error {
+	return nil
+}
 func (m *mockGraph) DeleteChunks(ctx context.Context, filePath string) error           { return nil }
 func (m *mockGraph) SetFileTags(ctx context.Context, path string, tags []string) error { return nil }
 func (m *mockGraph) SetFileTopics(ctx context.Context, path string, topics []graph.Topic) error {
@@ -107,9 +117,38 @@ func (m *mockGraph) SetFileTopics(ctx context.Context, path string, topics []gra
 func (m *mockGraph) SetFileEntities(ctx context.Context, path string, entities []graph.Entity) error {
 	return nil
 }
+func (m *mockGraph) SetFileRelations(ctx context.Context, path string, relations []graph.EntityRelation) error {
+	return nil
+}
 func (m *mockGraph) SetFileReferences(ctx context.Context, path string, refs []graph.Reference) error {
 	return nil
 }
+func (m *mockGraph) SetChunkSectionReferences(ctx context.Context, chunkID string, targetIDs []string, unmatchedAnchors []string) error {
+	return nil
+}
+
+func (m *mockGraph) LinkChunks(ctx context.Context, path string, chunkIDs []string) error {
+	return nil
+}
+
+func (m *mockGraph) GetAdjacentChunks(ctx context.Context, chunkID string, before, after int) ([]graph.ChunkNode, error) {
+	return nil, nil
+}
+func (m *mockGraph) PruneOrphans(ctx context.Context) (graph.PruneStats, error) {
+	return graph.PruneStats{}, nil
+}
+func (m *mockGraph) GetChunkByID(ctx context.Context, id string) (*graph.ChunkNode, *chunkers.ChunkMetadata, error) {
+	return nil, nil, nil
+}
+func (m *mockGraph) ListChunksForFile(ctx context.Context, filePath string) ([]graph.ChunkNode, error) {
+	return nil, nil
+}
+func (m *mockGraph) GetBacklinks(ctx context.Context, path string) ([]graph.FileNode, error) {
+	return nil, nil
+}
+func (m *mockGraph) GetReferences(ctx context.Context, path string) ([]graph.Reference, error) {
+	return nil, nil
+}
 func (m *mockGraph) Query(ctx context.Context, cypher string) (*graph.QueryResult, error) {
 	return nil, nil
 }
@@ -118,9 +157,33 @@ func (m *mockGraph) Errors() <-chan error { return nil }
 func (m *mockGraph) HasEmbedding(ctx context.Context, contentHash string, version int) (bool, error) {
 	return false, nil
 }
+func (m *mockGraph) GetEmbedding(ctx context.Context, contentHash, provider, model string, version int) ([]float32, error) {
+	return nil, nil
+}
+func (m *mockGraph) CountFilesByIngestReason(ctx context.Context) (map[string]int, error) {
+	return map[string]int{}, nil
+}
+func (m *mockGraph) GetDirectoryTree(ctx context.Context, rootPath string, maxDepth int) (*graph.DirectoryTree, error) {
+	return nil, nil
+}
+func (m *mockGraph) TopTags(ctx context.Context, n int) ([]graph.TagNode, error) {
+	return nil, nil
+}
+func (m *mockGraph) TopTopics(ctx context.Context, n int) ([]graph.TopicNode, error) {
+	return nil, nil
+}
+func (m *mockGraph) TopEntities(ctx context.Context, n int, entityType string) ([]graph.EntityNode, error) {
+	return nil, nil
+}
+func (m *mockGraph) GetRelatedFiles(ctx context.Context, path string, k int) ([]graph.RelatedFile, error) {
+	return nil, nil
+}
 func (m *mockGraph) ExportSnapshot(ctx context.Context) (*graph.GraphSnapshot, error) {
 	return m.snapshot, nil
 }
+func (m *mockGraph) ExportSnapshotSince(ctx context.Context, since time.Time) (*graph.GraphSnapshot, error) {
+	return m.snapshot, nil
+}
 func (m *mockGraph) GetFileWithRelations(ctx context.Context, path string) (*graph.FileWithRelations, error) {
 	// Return sample data for test file path
 	if path == "/test/file.go" {
@@ -148,8 +211,12 @@ func (m *mockGraph) GetFileWithRelations(ctx context.Context, path string) (*gra
 	return nil, nil
 }
 func (m *mockGraph) SearchSimilarChunks(ctx context.Context, embedding []float32, k int) ([]graph.ChunkSearchHit, error) {
+	return m.SearchSimilarChunksFiltered(ctx, embedding, k, graph.ChunkFilter{})
+}
+func (m *mockGraph) SearchSimilarChunksFiltered(ctx context.Context, embedding []float32, k int, filter graph.ChunkFilter) ([]graph.ChunkSearchHit, error) {
 	m.lastSearchEmbedding = embedding
 	m.lastSearchK = k
+	m.lastSearchFilter = filter
 	if m.searchErr != nil {
 		return nil, m.searchErr
 	}
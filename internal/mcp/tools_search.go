@@ -11,6 +11,7 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 
+	"github.com/leefowlercu/agentic-memorizer/internal/graph"
 	"github.com/leefowlercu/agentic-memorizer/internal/providers"
 )
 
@@ -173,8 +174,10 @@ func (s *Server) handleSearchMemory(ctx context.Context, request mcp.CallToolReq
 		return mcp.NewToolResultError(fmt.Sprintf("failed to embed query: %v", err)), nil
 	}
 
+	// The path prefix is applied by the graph, which over-fetches for it; the
+	// remaining filters run on the returned hits and need extra candidates.
 	candidateK := topK
-	if hasMinScore || pathPrefix != "" || len(includeSet) > 0 || len(excludeSet) > 0 {
+	if hasMinScore || len(includeSet) > 0 || len(excludeSet) > 0 {
 		candidateK = topK * 10
 		if candidateK < minCandidateK {
 			candidateK = minCandidateK
@@ -184,7 +187,9 @@ func (s *Server) handleSearchMemory(ctx context.Context, request mcp.CallToolReq
 		}
 	}
 
-	searchHits, err := s.graph.SearchSimilarChunks(ctx, embeddingResult.Embedding, candidateK)
+	searchHits, err := s.graph.SearchSimilarChunksFiltered(ctx, embeddingResult.Embedding, candidateK, graph.ChunkFilter{
+		FilePathPrefix: pathPrefix,
+	})
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("semantic search failed: %v", err)), nil
 	}
@@ -228,7 +233,10 @@ func (s *Server) handleSearchMemory(ctx context.Context, request mcp.CallToolReq
 			Model:       hit.Model,
 		}
 
-		if includeSnippets {
+		if includeSnippets && chunk.Content != "" {
+			// Stored chunk content survives source files changing or being deleted
+			out.Snippet, out.SnippetTruncated = truncateSnippet(chunk.Content, snippetMaxChars)
+		} else if includeSnippets {
 			snippet, truncated, snippetErr := extractSnippet(fileCache, chunk.FilePath, chunk.StartOffset, chunk.EndOffset, snippetMaxChars)
 			if snippetErr != nil {
 				out.SnippetError = snippetErr.Error()
@@ -377,13 +385,17 @@ func extractSnippet(fileCache map[string][]byte, path string, start, end, maxCha
 		return "", false, fmt.Errorf("empty snippet range")
 	}
 
-	text := string(content[start:end])
+	snippet, truncated := truncateSnippet(string(content[start:end]), maxChars)
+	return snippet, truncated, nil
+}
+
+// truncateSnippet limits text to maxChars runes, reporting whether it was cut.
+func truncateSnippet(text string, maxChars int) (string, bool) {
 	runes := []rune(text)
 	if len(runes) > maxChars {
-		return string(runes[:maxChars]), true, nil
+		return string(runes[:maxChars]), true
 	}
-
-	return text, false, nil
+	return text, false
 }
 
 func renderSearchMemoryText(result searchMemoryResult) string {
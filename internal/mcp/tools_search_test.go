@@ -154,11 +154,66 @@ func TestSearchMemoryToolSuccessWithFiltersAndSnippets(t *testing.T) {
 	if g.lastSearchK != 100 {
 		t.Fatalf("expected overfetch candidate_k=100, got %d", g.lastSearchK)
 	}
+	if g.lastSearchFilter.FilePathPrefix != tmpDir {
+		t.Fatalf("expected path_prefix pushed to graph filter, got %q", g.lastSearchFilter.FilePathPrefix)
+	}
 	if len(g.lastSearchEmbedding) != 3 {
 		t.Fatalf("expected query embedding length 3, got %d", len(g.lastSearchEmbedding))
 	}
 }
 
+func TestSearchMemoryToolSnippetFromStoredContent(t *testing.T) {
+	tmpDir := t.TempDir()
+	// The source file no longer exists, so only stored content can be shown
+	filePath := filepath.Join(tmpDir, "deleted.go")
+
+	g := newMockGraph()
+	g.searchHits = []graph.ChunkSearchHit{
+		{
+			Chunk: graph.ChunkNode{
+				ID:          "chunk-go",
+				FilePath:    filePath,
+				ContentHash: "hash-go",
+				EndOffset:   27,
+				ChunkType:   "code",
+				Content:     "func StoredHandler() error {}",
+			},
+			Score: 0.9,
+		},
+	}
+	reg := &mockRegistry{rememberedPaths: map[string]bool{tmpDir: true}}
+	provider := &mockEmbeddingsProvider{available: true, embedding: []float32{0.3, 0.4, 0.5}}
+	s := NewServer(g, provider, reg, events.NewBus(), DefaultConfig())
+
+	request := mcplib.CallToolRequest{
+		Params: mcplib.CallToolParams{
+			Name: toolSearchMemory,
+			Arguments: map[string]any{
+				"query":             "stored handler",
+				"include_snippets":  true,
+				"snippet_max_chars": 18,
+			},
+		},
+	}
+
+	result, err := s.handleSearchMemory(context.Background(), request)
+	if err != nil {
+		t.Fatalf("handleSearchMemory returned protocol error: %v", err)
+	}
+	typed, ok := result.StructuredContent.(searchMemoryResult)
+	if !ok || len(typed.Hits) != 1 {
+		t.Fatalf("expected one structured hit, got %#v", result.StructuredContent)
+	}
+
+	hit := typed.Hits[0]
+	if hit.SnippetError != "" {
+		t.Errorf("unexpected snippet error %q", hit.SnippetError)
+	}
+	if hit.Snippet != "func StoredHandler" || !hit.SnippetTruncated {
+		t.Errorf("snippet = %q (truncated %v), want truncated stored content", hit.Snippet, hit.SnippetTruncated)
+	}
+}
+
 func TestSearchMemoryToolProviderUnavailable(t *testing.T) {
 	g := newMockGraph()
 	reg := newMockRegistry()
@@ -191,6 +191,31 @@ func RecordProviderRequest(provider, operation string, duration time.Duration, i
 	}
 }
 
+// TokenRates are per-1k-token prices (USD) used to estimate provider cost.
+type TokenRates struct {
+	InputPer1K  float64
+	OutputPer1K float64
+}
+
+// Cost returns the estimated cost of the given token counts.
+func (r TokenRates) Cost(inputTokens, outputTokens int) float64 {
+	return float64(inputTokens)/1000*r.InputPer1K + float64(outputTokens)/1000*r.OutputPer1K
+}
+
+// RecordTokenUsage records tokens consumed by an analysis stage and the
+// estimated cost at the given rates.
+func RecordTokenUsage(stage, provider, model string, inputTokens, outputTokens int, rates TokenRates) {
+	if inputTokens > 0 {
+		ProviderUsageTokensTotal.WithLabelValues(stage, provider, model, "input").Add(float64(inputTokens))
+	}
+	if outputTokens > 0 {
+		ProviderUsageTokensTotal.WithLabelValues(stage, provider, model, "output").Add(float64(outputTokens))
+	}
+	if cost := rates.Cost(inputTokens, outputTokens); cost > 0 {
+		ProviderCostDollarsTotal.WithLabelValues(stage, provider, model).Add(cost)
+	}
+}
+
 // RecordCacheAccess records a cache access.
 func RecordCacheAccess(cacheType string, hit bool) {
 	if hit {
@@ -176,6 +176,22 @@ var (
 		Help:      "Total number of tokens consumed",
 	}, []string{"provider", "type"})
 
+	// ProviderUsageTokensTotal is the total number of tokens consumed by analysis
+	// stage, provider, model, and direction (input or output).
+	ProviderUsageTokensTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "provider_usage_tokens_total",
+		Help:      "Total number of tokens consumed by provider and model",
+	}, []string{"stage", "provider", "model", "direction"})
+
+	// ProviderCostDollarsTotal is the estimated provider spend in US dollars,
+	// computed from configured per-1k-token rates.
+	ProviderCostDollarsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "provider_cost_dollars_total",
+		Help:      "Estimated provider cost in US dollars",
+	}, []string{"stage", "provider", "model"})
+
 	// ProviderDuration is a histogram of provider request duration in seconds.
 	ProviderDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
 		Namespace: namespace,
@@ -51,6 +51,17 @@ func TestRecordProviderRequest(t *testing.T) {
 	// Verify metrics are recorded (no panic)
 }
 
+func TestTokenRatesCost(t *testing.T) {
+	rates := TokenRates{InputPer1K: 0.003, OutputPer1K: 0.015}
+	got := rates.Cost(2000, 1000)
+	if got < 0.0209 || got > 0.0211 {
+		t.Errorf("Cost = %v, want 0.021", got)
+	}
+	if (TokenRates{}).Cost(2000, 1000) != 0 {
+		t.Error("zero rates should cost nothing")
+	}
+}
+
 func TestRecordCacheAccess(t *testing.T) {
 	// Record cache hit
 	RecordCacheAccess("semantic", true)
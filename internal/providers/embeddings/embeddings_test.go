@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -125,3 +126,191 @@ func TestGoogleEmbeddingsProvider_EmbedBatch_NotAvailable(t *testing.T) {
 		t.Error("expected error when provider not available")
 	}
 }
+
+// newOllamaTestServer returns a server mimicking Ollama's embeddings and tags
+// endpoints. Each embedding has dims values; the first encodes the prompt length.
+func newOllamaTestServer(t *testing.T, dims int, embedCalls *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/tags":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"models":[{"name":"nomic-embed-text:latest"}]}`))
+
+		case "/api/embeddings":
+			if r.Method != "POST" {
+				t.Errorf("expected POST, got %s", r.Method)
+			}
+			var reqBody struct {
+				Model  string `json:"model"`
+				Prompt string `json:"prompt"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+			}
+			if reqBody.Model != "nomic-embed-text" {
+				t.Errorf("expected model nomic-embed-text, got %q", reqBody.Model)
+			}
+			if embedCalls != nil {
+				embedCalls.Add(1)
+			}
+
+			embedding := make([]float64, dims)
+			embedding[0] = float64(len(reqBody.Prompt))
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]any{"embedding": embedding})
+
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestOllamaEmbeddingsProvider_InterfaceCompliance(t *testing.T) {
+	server := newOllamaTestServer(t, 3, nil)
+	p := NewOllamaEmbeddingsProvider(WithOllamaBaseURL(server.URL))
+	var _ providers.EmbeddingsProvider = p
+}
+
+func TestOllamaEmbeddingsProvider_Dimensions(t *testing.T) {
+	t.Run("probed at construction", func(t *testing.T) {
+		var calls atomic.Int32
+		server := newOllamaTestServer(t, 768, &calls)
+
+		p := NewOllamaEmbeddingsProvider(WithOllamaBaseURL(server.URL))
+		if got := p.Dimensions(); got != 768 {
+			t.Errorf("Dimensions() = %d, want 768", got)
+		}
+		if calls.Load() != 1 {
+			t.Errorf("expected 1 probe request, got %d", calls.Load())
+		}
+	})
+
+	t.Run("configured", func(t *testing.T) {
+		var calls atomic.Int32
+		server := newOllamaTestServer(t, 768, &calls)
+
+		p := NewOllamaEmbeddingsProvider(WithOllamaBaseURL(server.URL), WithOllamaDimensions(1024))
+		if got := p.Dimensions(); got != 1024 {
+			t.Errorf("Dimensions() = %d, want 1024", got)
+		}
+		if calls.Load() != 0 {
+			t.Errorf("expected no probe request, got %d", calls.Load())
+		}
+	})
+
+	t.Run("unreachable server", func(t *testing.T) {
+		server := newOllamaTestServer(t, 768, nil)
+		url := server.URL
+		server.Close()
+
+		p := NewOllamaEmbeddingsProvider(WithOllamaBaseURL(url))
+		if got := p.Dimensions(); got != 0 {
+			t.Errorf("Dimensions() = %d, want 0", got)
+		}
+	})
+}
+
+func TestOllamaEmbeddingsProvider_Available(t *testing.T) {
+	server := newOllamaTestServer(t, 3, nil)
+	p := NewOllamaEmbeddingsProvider(WithOllamaBaseURL(server.URL), WithOllamaDimensions(3))
+	if !p.Available() {
+		t.Error("expected provider to be available")
+	}
+
+	down := newOllamaTestServer(t, 3, nil)
+	url := down.URL
+	down.Close()
+
+	p = NewOllamaEmbeddingsProvider(WithOllamaBaseURL(url), WithOllamaDimensions(3))
+	if p.Available() {
+		t.Error("expected provider to be unavailable when the server is down")
+	}
+}
+
+func TestOllamaEmbeddingsProvider_AvailableDoesNotBlockDimensions(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte(`{"models":[]}`))
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+
+	p := NewOllamaEmbeddingsProvider(WithOllamaBaseURL(server.URL), WithOllamaDimensions(3))
+	go p.Available()
+
+	// Give the ping time to start before reading dimensions
+	time.Sleep(20 * time.Millisecond)
+	done := make(chan int, 1)
+	go func() { done <- p.Dimensions() }()
+	select {
+	case got := <-done:
+		if got != 3 {
+			t.Errorf("Dimensions() = %d, want 3", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Dimensions() blocked on an in-flight availability check")
+	}
+}
+
+func TestOllamaEmbeddingsProvider_Embed(t *testing.T) {
+	server := newOllamaTestServer(t, 3, nil)
+	p := NewOllamaEmbeddingsProvider(WithOllamaBaseURL(server.URL), WithOllamaDimensions(3))
+
+	result, err := p.Embed(context.Background(), providers.EmbeddingsRequest{Content: "hello"})
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	if result.Dimensions != 3 || len(result.Embedding) != 3 {
+		t.Errorf("expected 3 dimensions, got %d (%d values)", result.Dimensions, len(result.Embedding))
+	}
+	if result.Embedding[0] != 5 {
+		t.Errorf("expected embedding for prompt %q, got %v", "hello", result.Embedding)
+	}
+	if result.ProviderName != "ollama-embeddings" || result.ModelName != "nomic-embed-text" {
+		t.Errorf("unexpected provider/model %q/%q", result.ProviderName, result.ModelName)
+	}
+}
+
+func TestOllamaEmbeddingsProvider_EmbedBatch(t *testing.T) {
+	var calls atomic.Int32
+	server := newOllamaTestServer(t, 3, &calls)
+	p := NewOllamaEmbeddingsProvider(WithOllamaBaseURL(server.URL), WithOllamaDimensions(3))
+
+	texts := []string{"a", "bb", "ccc"}
+	results, err := p.EmbedBatch(context.Background(), texts)
+	if err != nil {
+		t.Fatalf("EmbedBatch failed: %v", err)
+	}
+	if len(results) != len(texts) {
+		t.Fatalf("expected %d results, got %d", len(texts), len(results))
+	}
+	for i, result := range results {
+		if result.Index != i || result.Embedding[0] != float32(len(texts[i])) {
+			t.Errorf("result %d = index %d embedding %v", i, result.Index, result.Embedding)
+		}
+	}
+	if calls.Load() != int32(len(texts)) {
+		t.Errorf("expected %d requests, got %d", len(texts), calls.Load())
+	}
+
+	results, err = p.EmbedBatch(context.Background(), nil)
+	if err != nil || len(results) != 0 {
+		t.Errorf("expected no results for empty input, got %v, %v", results, err)
+	}
+}
+
+func TestOllamaEmbeddingsProvider_Embed_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"model \"missing\" not found"}`, http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	p := NewOllamaEmbeddingsProvider(WithOllamaBaseURL(server.URL), WithOllamaModel("missing"), WithOllamaDimensions(3))
+	if _, err := p.Embed(context.Background(), providers.EmbeddingsRequest{Content: "hello"}); err == nil {
+		t.Error("expected error for missing model")
+	}
+}
@@ -91,12 +91,24 @@ func (p *GoogleEmbeddingsProvider) MaxTokens() int {
 	return 2048
 }
 
+// BatchLimits returns the per-call limits of the batch embeddings API.
+func (p *GoogleEmbeddingsProvider) BatchLimits() providers.EmbeddingsBatchLimits {
+	return providers.EmbeddingsBatchLimits{
+		MaxInputs: 100, // batchEmbedContents requests per call
+	}
+}
+
 // Embed generates embeddings for the given content.
 func (p *GoogleEmbeddingsProvider) Embed(ctx context.Context, req providers.EmbeddingsRequest) (*providers.EmbeddingsResult, error) {
 	if !p.Available() {
 		return nil, fmt.Errorf("google embeddings provider not available; GOOGLE_API_KEY not set")
 	}
 
+	content, truncated, err := providers.TruncateEmbeddingsInput(p, req)
+	if err != nil {
+		return nil, err
+	}
+
 	// Wait for rate limit
 	if err := p.rateLimiter.Wait(ctx); err != nil {
 		return nil, fmt.Errorf("rate limit wait failed; %w", err)
@@ -109,7 +121,7 @@ func (p *GoogleEmbeddingsProvider) Embed(ctx context.Context, req providers.Embe
 		"model": fmt.Sprintf("models/%s", p.model),
 		"content": map[string]any{
 			"parts": []map[string]string{
-				{"text": req.Content},
+				{"text": content},
 			},
 		},
 	}
@@ -161,6 +173,7 @@ func (p *GoogleEmbeddingsProvider) Embed(ctx context.Context, req providers.Embe
 		TokensUsed:   0, // Google doesn't return token count
 		GeneratedAt:  time.Now(),
 		Version:      embeddingsVersion,
+		Truncated:    truncated,
 	}, nil
 }
 
@@ -0,0 +1,313 @@
+package embeddings
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/leefowlercu/agentic-memorizer/internal/providers"
+)
+
+const (
+	ollamaDefaultBaseURL = "http://localhost:11434"
+	ollamaDefaultModel   = "nomic-embed-text"
+
+	// Ollama serves models with a 2048 token context unless num_ctx is raised.
+	ollamaDefaultMaxTokens = 2048
+
+	// How long a health check result is reused before the server is pinged again.
+	ollamaAvailabilityTTL = 30 * time.Second
+
+	// Time allowed for the dimension probe and health checks.
+	ollamaProbeTimeout = 5 * time.Second
+)
+
+// OllamaEmbeddingsProvider implements EmbeddingsProvider using a local Ollama server.
+type OllamaEmbeddingsProvider struct {
+	baseURL     string
+	model       string
+	httpClient  *http.Client
+	rateLimiter *providers.RateLimiter
+
+	mu         sync.Mutex
+	dimensions int
+	available  bool
+	checkedAt  time.Time
+}
+
+// OllamaEmbeddingsOption configures the OllamaEmbeddingsProvider.
+type OllamaEmbeddingsOption func(*OllamaEmbeddingsProvider)
+
+// WithOllamaModel sets the model to use.
+func WithOllamaModel(model string) OllamaEmbeddingsOption {
+	return func(p *OllamaEmbeddingsProvider) {
+		p.model = model
+	}
+}
+
+// WithOllamaBaseURL sets the Ollama server address.
+func WithOllamaBaseURL(baseURL string) OllamaEmbeddingsOption {
+	return func(p *OllamaEmbeddingsProvider) {
+		if baseURL != "" {
+			p.baseURL = strings.TrimRight(baseURL, "/")
+		}
+	}
+}
+
+// WithOllamaDimensions sets the embedding dimensions, skipping the probe
+// embedding made at construction.
+func WithOllamaDimensions(dims int) OllamaEmbeddingsOption {
+	return func(p *OllamaEmbeddingsProvider) {
+		if dims > 0 {
+			p.dimensions = dims
+		}
+	}
+}
+
+// NewOllamaEmbeddingsProvider creates a new Ollama embeddings provider. The
+// server address defaults to OLLAMA_HOST, then localhost. When dimensions are
+// not configured they are discovered from a probe embedding.
+func NewOllamaEmbeddingsProvider(opts ...OllamaEmbeddingsOption) *OllamaEmbeddingsProvider {
+	p := &OllamaEmbeddingsProvider{
+		baseURL:    ollamaDefaultBaseURL,
+		model:      ollamaDefaultModel,
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+	}
+
+	if host := os.Getenv("OLLAMA_HOST"); host != "" {
+		if !strings.Contains(host, "://") {
+			host = "http://" + host
+		}
+		p.baseURL = strings.TrimRight(host, "/")
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	p.rateLimiter = providers.NewRateLimiter(p.RateLimit())
+
+	if p.dimensions == 0 {
+		p.probeDimensions()
+	}
+
+	return p
+}
+
+// Name returns the provider's unique identifier.
+func (p *OllamaEmbeddingsProvider) Name() string {
+	return "ollama-embeddings"
+}
+
+// Type returns the provider type.
+func (p *OllamaEmbeddingsProvider) Type() providers.ProviderType {
+	return providers.ProviderTypeEmbeddings
+}
+
+// Available returns true if the Ollama server responds. The result is cached
+// briefly since availability is checked for every analyzed file. The server is
+// pinged without holding the lock so Dimensions does not wait on it.
+func (p *OllamaEmbeddingsProvider) Available() bool {
+	p.mu.Lock()
+	if !p.checkedAt.IsZero() && time.Since(p.checkedAt) < ollamaAvailabilityTTL {
+		available := p.available
+		p.mu.Unlock()
+		return available
+	}
+	p.mu.Unlock()
+
+	available := p.ping()
+
+	p.mu.Lock()
+	p.available = available
+	p.checkedAt = time.Now()
+	p.mu.Unlock()
+	return available
+}
+
+// RateLimit returns the rate limit configuration. A local server has no quota,
+// so the limit only smooths bursts.
+func (p *OllamaEmbeddingsProvider) RateLimit() providers.RateLimitConfig {
+	return providers.RateLimitConfig{
+		RequestsPerMinute: 6000,
+		BurstSize:         100,
+	}
+}
+
+// ModelName returns the name of the embedding model.
+func (p *OllamaEmbeddingsProvider) ModelName() string {
+	return p.model
+}
+
+// Dimensions returns the dimensionality of the embedding vectors, or 0 if
+// they have not been configured or discovered yet.
+func (p *OllamaEmbeddingsProvider) Dimensions() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.dimensions
+}
+
+// MaxTokens returns the maximum number of tokens per request.
+func (p *OllamaEmbeddingsProvider) MaxTokens() int {
+	return ollamaDefaultMaxTokens
+}
+
+// Embed generates embeddings for the given content.
+func (p *OllamaEmbeddingsProvider) Embed(ctx context.Context, req providers.EmbeddingsRequest) (*providers.EmbeddingsResult, error) {
+	content, truncated, err := providers.TruncateEmbeddingsInput(p, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Wait for rate limit
+	if err := p.rateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("rate limit wait failed; %w", err)
+	}
+
+	embedding, err := p.embed(ctx, content)
+	if err != nil {
+		return nil, err
+	}
+
+	return &providers.EmbeddingsResult{
+		Embedding:    embedding,
+		ProviderName: p.Name(),
+		ModelName:    p.model,
+		Dimensions:   len(embedding),
+		GeneratedAt:  time.Now(),
+		Version:      embeddingsVersion,
+		Truncated:    truncated,
+	}, nil
+}
+
+// EmbedBatch generates embeddings for multiple texts. The Ollama embeddings
+// endpoint takes a single prompt, so texts are embedded one request at a time.
+func (p *OllamaEmbeddingsProvider) EmbedBatch(ctx context.Context, texts []string) ([]providers.EmbeddingsBatchResult, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	results := make([]providers.EmbeddingsBatchResult, len(texts))
+	for i, text := range texts {
+		if err := p.rateLimiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("rate limit wait failed; %w", err)
+		}
+
+		embedding, err := p.embed(ctx, text)
+		if err != nil {
+			return nil, fmt.Errorf("failed to embed text %d; %w", i, err)
+		}
+
+		results[i] = providers.EmbeddingsBatchResult{
+			Index:     i,
+			Embedding: embedding,
+		}
+	}
+
+	return results, nil
+}
+
+// embed calls the Ollama embeddings endpoint for a single prompt and records
+// the dimensions of the result if they are not yet known.
+func (p *OllamaEmbeddingsProvider) embed(ctx context.Context, prompt string) ([]float32, error) {
+	// Build request body
+	requestBody := map[string]any{
+		"model":  p.model,
+		"prompt": prompt,
+	}
+
+	jsonBody, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request; %w", err)
+	}
+
+	// Create HTTP request
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/api/embeddings", bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request; %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	// Execute request
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("API request failed; %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Read response
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response; %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+	}
+
+	// Parse response
+	var apiResp ollamaEmbeddingsResponse
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response; %w", err)
+	}
+
+	if len(apiResp.Embedding) == 0 {
+		return nil, fmt.Errorf("no embeddings returned")
+	}
+
+	// Convert []float64 to []float32
+	embedding := make([]float32, len(apiResp.Embedding))
+	for i, v := range apiResp.Embedding {
+		embedding[i] = float32(v)
+	}
+
+	p.mu.Lock()
+	if p.dimensions == 0 {
+		p.dimensions = len(embedding)
+	}
+	p.mu.Unlock()
+
+	return embedding, nil
+}
+
+// probeDimensions embeds a short prompt to discover the model's dimensions.
+// Failures are left for Available and Embed to surface.
+func (p *OllamaEmbeddingsProvider) probeDimensions() {
+	ctx, cancel := context.WithTimeout(context.Background(), ollamaProbeTimeout)
+	defer cancel()
+
+	_, _ = p.embed(ctx, "dimension probe")
+}
+
+// ping reports whether the Ollama server is reachable.
+func (p *OllamaEmbeddingsProvider) ping() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), ollamaProbeTimeout)
+	defer cancel()
+
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", p.baseURL+"/api/tags", nil)
+	if err != nil {
+		return false
+	}
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	return resp.StatusCode == http.StatusOK
+}
+
+// ollamaEmbeddingsResponse represents the Ollama embeddings API response.
+type ollamaEmbeddingsResponse struct {
+	Embedding []float64 `json:"embedding"`
+}
@@ -109,12 +109,25 @@ func (p *OpenAIEmbeddingsProvider) MaxTokens() int {
 	return 8191 // text-embedding-3-small limit
 }
 
+// BatchLimits returns the per-call limits of the batch embeddings API.
+func (p *OpenAIEmbeddingsProvider) BatchLimits() providers.EmbeddingsBatchLimits {
+	return providers.EmbeddingsBatchLimits{
+		MaxInputs: 2048,
+		MaxTokens: 300000,
+	}
+}
+
 // Embed generates embeddings for the given content.
 func (p *OpenAIEmbeddingsProvider) Embed(ctx context.Context, req providers.EmbeddingsRequest) (*providers.EmbeddingsResult, error) {
 	if !p.Available() {
 		return nil, fmt.Errorf("openai embeddings provider not available; OPENAI_API_KEY not set")
 	}
 
+	content, truncated, err := providers.TruncateEmbeddingsInput(p, req)
+	if err != nil {
+		return nil, err
+	}
+
 	// Wait for rate limit
 	if err := p.rateLimiter.Wait(ctx); err != nil {
 		return nil, fmt.Errorf("rate limit wait failed; %w", err)
@@ -123,7 +136,7 @@ func (p *OpenAIEmbeddingsProvider) Embed(ctx context.Context, req providers.Embe
 	// Build request body
 	requestBody := map[string]any{
 		"model": p.model,
-		"input": req.Content,
+		"input": content,
 	}
 
 	// Add dimensions if using text-embedding-3 models
@@ -186,6 +199,7 @@ func (p *OpenAIEmbeddingsProvider) Embed(ctx context.Context, req providers.Embe
 		TokensUsed:   apiResp.Usage.TotalTokens,
 		GeneratedAt:  time.Now(),
 		Version:      embeddingsVersion,
+		Truncated:    truncated,
 	}, nil
 }
 
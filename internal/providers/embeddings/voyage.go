@@ -92,12 +92,25 @@ func (p *VoyageEmbeddingsProvider) MaxTokens() int {
 	return 32000 // voyage-code-3 context length
 }
 
+// BatchLimits returns the per-call limits of the batch embeddings API.
+func (p *VoyageEmbeddingsProvider) BatchLimits() providers.EmbeddingsBatchLimits {
+	return providers.EmbeddingsBatchLimits{
+		MaxInputs: 1000,
+		MaxTokens: 120000, // voyage-code-3 total tokens per request
+	}
+}
+
 // Embed generates embeddings for the given content.
 func (p *VoyageEmbeddingsProvider) Embed(ctx context.Context, req providers.EmbeddingsRequest) (*providers.EmbeddingsResult, error) {
 	if !p.Available() {
 		return nil, fmt.Errorf("voyage embeddings provider not available; VOYAGE_API_KEY not set")
 	}
 
+	content, truncated, err := providers.TruncateEmbeddingsInput(p, req)
+	if err != nil {
+		return nil, err
+	}
+
 	// Wait for rate limit
 	if err := p.rateLimiter.Wait(ctx); err != nil {
 		return nil, fmt.Errorf("rate limit wait failed; %w", err)
@@ -106,7 +119,7 @@ func (p *VoyageEmbeddingsProvider) Embed(ctx context.Context, req providers.Embe
 	// Build request body
 	requestBody := map[string]any{
 		"model": p.model,
-		"input": req.Content,
+		"input": content,
 	}
 
 	jsonBody, err := json.Marshal(requestBody)
@@ -164,6 +177,7 @@ func (p *VoyageEmbeddingsProvider) Embed(ctx context.Context, req providers.Embe
 		TokensUsed:   apiResp.Usage.TotalTokens,
 		GeneratedAt:  time.Now(),
 		Version:      embeddingsVersion,
+		Truncated:    truncated,
 	}, nil
 }
 
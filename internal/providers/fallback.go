@@ -0,0 +1,235 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+// FallbackEmbeddingsProvider wraps a primary embeddings provider and falls back to a
+// secondary provider when the primary is unavailable or returns an error.
+// The secondary is refused when its dimensions differ from the primary's, since
+// mixing vector spaces would corrupt similarity search.
+type FallbackEmbeddingsProvider struct {
+	primary   EmbeddingsProvider
+	secondary EmbeddingsProvider
+
+	mu       sync.Mutex
+	servedBy string
+}
+
+// NewFallbackEmbeddingsProvider creates an embeddings provider that tries primary
+// before secondary. A secondary with mismatched dimensions is ignored with a warning.
+func NewFallbackEmbeddingsProvider(primary, secondary EmbeddingsProvider) *FallbackEmbeddingsProvider {
+	if secondary != nil && secondary.Dimensions() != primary.Dimensions() {
+		slog.Warn("embeddings fallback refused; dimensions mismatch",
+			"primary", primary.Name(),
+			"primary_dimensions", primary.Dimensions(),
+			"secondary", secondary.Name(),
+			"secondary_dimensions", secondary.Dimensions())
+		secondary = nil
+	}
+
+	return &FallbackEmbeddingsProvider{
+		primary:   primary,
+		secondary: secondary,
+	}
+}
+
+// Name returns the primary provider's name.
+func (p *FallbackEmbeddingsProvider) Name() string {
+	return p.primary.Name()
+}
+
+// Type returns the provider type.
+func (p *FallbackEmbeddingsProvider) Type() ProviderType {
+	return ProviderTypeEmbeddings
+}
+
+// Available returns true if either provider is available.
+func (p *FallbackEmbeddingsProvider) Available() bool {
+	return p.primary.Available() || (p.secondary != nil && p.secondary.Available())
+}
+
+// RateLimit returns the rate limit configuration of the active provider.
+func (p *FallbackEmbeddingsProvider) RateLimit() RateLimitConfig {
+	return p.active().RateLimit()
+}
+
+// ModelName returns the model name of the active provider.
+func (p *FallbackEmbeddingsProvider) ModelName() string {
+	return p.active().ModelName()
+}
+
+// Dimensions returns the embedding dimensionality shared by both providers.
+func (p *FallbackEmbeddingsProvider) Dimensions() int {
+	return p.primary.Dimensions()
+}
+
+// MaxTokens returns the maximum tokens per request of the active provider.
+func (p *FallbackEmbeddingsProvider) MaxTokens() int {
+	return p.active().MaxTokens()
+}
+
+// ServedBy returns the name of the provider that served the most recent request.
+func (p *FallbackEmbeddingsProvider) ServedBy() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.servedBy
+}
+
+// Embed generates an embedding with the primary provider, falling back to the secondary.
+func (p *FallbackEmbeddingsProvider) Embed(ctx context.Context, req EmbeddingsRequest) (*EmbeddingsResult, error) {
+	return withFallback(ctx, p.primary, p.secondary, &p.mu, &p.servedBy,
+		func(provider EmbeddingsProvider) (*EmbeddingsResult, error) {
+			result, err := provider.Embed(ctx, req)
+			if err == nil && result != nil && result.ProviderName == "" {
+				result.ProviderName = provider.Name()
+			}
+			return result, err
+		})
+}
+
+// EmbedBatch generates embeddings with the primary provider, falling back to the secondary.
+func (p *FallbackEmbeddingsProvider) EmbedBatch(ctx context.Context, texts []string) ([]EmbeddingsBatchResult, error) {
+	return withFallback(ctx, p.primary, p.secondary, &p.mu, &p.servedBy,
+		func(provider EmbeddingsProvider) ([]EmbeddingsBatchResult, error) {
+			return provider.EmbedBatch(ctx, texts)
+		})
+}
+
+// active returns the provider that would serve the next request.
+func (p *FallbackEmbeddingsProvider) active() EmbeddingsProvider {
+	if !p.primary.Available() && p.secondary != nil && p.secondary.Available() {
+		return p.secondary
+	}
+	return p.primary
+}
+
+// FallbackSemanticProvider wraps a primary semantic provider and falls back to a
+// secondary provider when the primary is unavailable or returns an error.
+type FallbackSemanticProvider struct {
+	primary   SemanticProvider
+	secondary SemanticProvider
+
+	mu       sync.Mutex
+	servedBy string
+}
+
+// NewFallbackSemanticProvider creates a semantic provider that tries primary before secondary.
+func NewFallbackSemanticProvider(primary, secondary SemanticProvider) *FallbackSemanticProvider {
+	return &FallbackSemanticProvider{
+		primary:   primary,
+		secondary: secondary,
+	}
+}
+
+// Name returns the primary provider's name.
+func (p *FallbackSemanticProvider) Name() string {
+	return p.primary.Name()
+}
+
+// Type returns the provider type.
+func (p *FallbackSemanticProvider) Type() ProviderType {
+	return ProviderTypeSemantic
+}
+
+// Available returns true if either provider is available.
+func (p *FallbackSemanticProvider) Available() bool {
+	return p.primary.Available() || (p.secondary != nil && p.secondary.Available())
+}
+
+// RateLimit returns the rate limit configuration of the active provider.
+func (p *FallbackSemanticProvider) RateLimit() RateLimitConfig {
+	return p.active().RateLimit()
+}
+
+// ModelName returns the model name of the active provider.
+func (p *FallbackSemanticProvider) ModelName() string {
+	return p.active().ModelName()
+}
+
+// Capabilities returns the capabilities of the active provider.
+func (p *FallbackSemanticProvider) Capabilities() SemanticCapabilities {
+	return p.active().Capabilities()
+}
+
+// ServedBy returns the name of the provider that served the most recent request.
+func (p *FallbackSemanticProvider) ServedBy() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.servedBy
+}
+
+// Analyze performs semantic analysis with the primary provider, falling back to the secondary.
+func (p *FallbackSemanticProvider) Analyze(ctx context.Context, input SemanticInput) (*SemanticResult, error) {
+	return withFallback(ctx, p.primary, p.secondary, &p.mu, &p.servedBy,
+		func(provider SemanticProvider) (*SemanticResult, error) {
+			result, err := provider.Analyze(ctx, input)
+			if err == nil && result != nil && result.ProviderName == "" {
+				result.ProviderName = provider.Name()
+			}
+			return result, err
+		})
+}
+
+// active returns the provider that would serve the next request.
+func (p *FallbackSemanticProvider) active() SemanticProvider {
+	if !p.primary.Available() && p.secondary != nil && p.secondary.Available() {
+		return p.secondary
+	}
+	return p.primary
+}
+
+// withFallback calls fn with primary and, when primary is unavailable or fails,
+// with secondary. The serving provider's name is recorded in servedBy.
+// Context cancellation is returned without falling back.
+func withFallback[P Provider, R any](ctx context.Context, primary, secondary P, mu *sync.Mutex, servedBy *string, fn func(P) (R, error)) (R, error) {
+	var zero R
+	hasSecondary := any(secondary) != nil
+
+	record := func(name string) {
+		mu.Lock()
+		*servedBy = name
+		mu.Unlock()
+	}
+
+	var primaryErr error
+	if primary.Available() {
+		result, err := fn(primary)
+		if err == nil {
+			record(primary.Name())
+			return result, nil
+		}
+		if ctx.Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return zero, err
+		}
+		primaryErr = err
+	} else {
+		primaryErr = fmt.Errorf("provider %s unavailable", primary.Name())
+	}
+
+	if !hasSecondary || !secondary.Available() {
+		return zero, primaryErr
+	}
+
+	slog.Warn("primary provider failed; falling back",
+		"primary", primary.Name(),
+		"secondary", secondary.Name(),
+		"error", primaryErr)
+
+	result, err := fn(secondary)
+	if err != nil {
+		return zero, fmt.Errorf("fallback provider %s failed after primary error (%v); %w", secondary.Name(), primaryErr, err)
+	}
+	record(secondary.Name())
+	return result, nil
+}
+
+// Ensure fallback providers implement the provider interfaces.
+var (
+	_ EmbeddingsProvider = (*FallbackEmbeddingsProvider)(nil)
+	_ SemanticProvider   = (*FallbackSemanticProvider)(nil)
+)
@@ -0,0 +1,141 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// stubEmbeddingsProvider returns a fixed embedding or error and counts calls.
+type stubEmbeddingsProvider struct {
+	mockEmbeddingsProvider
+	dimensions int
+	embedding  []float32
+	err        error
+	calls      int
+}
+
+func (p *stubEmbeddingsProvider) Dimensions() int { return p.dimensions }
+func (p *stubEmbeddingsProvider) Embed(ctx context.Context, req EmbeddingsRequest) (*EmbeddingsResult, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &EmbeddingsResult{Embedding: p.embedding, Dimensions: len(p.embedding)}, nil
+}
+
+func TestFallbackEmbeddingsProvider(t *testing.T) {
+	t.Run("UnavailablePrimaryUsesSecondary", func(t *testing.T) {
+		primary := &stubEmbeddingsProvider{
+			mockEmbeddingsProvider: mockEmbeddingsProvider{name: "primary", available: false},
+			dimensions:             3,
+		}
+		secondary := &stubEmbeddingsProvider{
+			mockEmbeddingsProvider: mockEmbeddingsProvider{name: "secondary", available: true},
+			dimensions:             3,
+			embedding:              []float32{0.1, 0.2, 0.3},
+		}
+
+		p := NewFallbackEmbeddingsProvider(primary, secondary)
+		if !p.Available() {
+			t.Fatal("expected fallback provider to be available")
+		}
+
+		result, err := p.Embed(context.Background(), EmbeddingsRequest{Content: "hello"})
+		if err != nil {
+			t.Fatalf("Embed failed: %v", err)
+		}
+		if len(result.Embedding) != 3 {
+			t.Errorf("embedding length = %d, want 3", len(result.Embedding))
+		}
+		if result.ProviderName != "secondary" {
+			t.Errorf("ProviderName = %q, want %q", result.ProviderName, "secondary")
+		}
+		if p.ServedBy() != "secondary" {
+			t.Errorf("ServedBy() = %q, want %q", p.ServedBy(), "secondary")
+		}
+		if primary.calls != 0 {
+			t.Errorf("unavailable primary called %d times, want 0", primary.calls)
+		}
+	})
+
+	t.Run("PrimaryErrorUsesSecondary", func(t *testing.T) {
+		primary := &stubEmbeddingsProvider{
+			mockEmbeddingsProvider: mockEmbeddingsProvider{name: "primary", available: true},
+			dimensions:             2,
+			err:                    errors.New("api error"),
+		}
+		secondary := &stubEmbeddingsProvider{
+			mockEmbeddingsProvider: mockEmbeddingsProvider{name: "secondary", available: true},
+			dimensions:             2,
+			embedding:              []float32{0.5, 0.5},
+		}
+
+		p := NewFallbackEmbeddingsProvider(primary, secondary)
+		if _, err := p.Embed(context.Background(), EmbeddingsRequest{Content: "hello"}); err != nil {
+			t.Fatalf("Embed failed: %v", err)
+		}
+		if primary.calls != 1 || secondary.calls != 1 {
+			t.Errorf("calls = primary %d, secondary %d; want 1, 1", primary.calls, secondary.calls)
+		}
+		if p.ServedBy() != "secondary" {
+			t.Errorf("ServedBy() = %q, want %q", p.ServedBy(), "secondary")
+		}
+	})
+
+	t.Run("DimensionMismatchRefusesFallback", func(t *testing.T) {
+		primary := &stubEmbeddingsProvider{
+			mockEmbeddingsProvider: mockEmbeddingsProvider{name: "primary", available: false},
+			dimensions:             1536,
+		}
+		secondary := &stubEmbeddingsProvider{
+			mockEmbeddingsProvider: mockEmbeddingsProvider{name: "secondary", available: true},
+			dimensions:             768,
+			embedding:              make([]float32, 768),
+		}
+
+		p := NewFallbackEmbeddingsProvider(primary, secondary)
+		if p.Available() {
+			t.Error("expected fallback provider to be unavailable")
+		}
+		if _, err := p.Embed(context.Background(), EmbeddingsRequest{Content: "hello"}); err == nil {
+			t.Error("expected error when fallback is refused")
+		}
+		if secondary.calls != 0 {
+			t.Errorf("refused secondary called %d times, want 0", secondary.calls)
+		}
+	})
+
+	t.Run("ContextCancellationDoesNotFallBack", func(t *testing.T) {
+		primary := &stubEmbeddingsProvider{
+			mockEmbeddingsProvider: mockEmbeddingsProvider{name: "primary", available: true},
+			dimensions:             2,
+			err:                    context.Canceled,
+		}
+		secondary := &stubEmbeddingsProvider{
+			mockEmbeddingsProvider: mockEmbeddingsProvider{name: "secondary", available: true},
+			dimensions:             2,
+		}
+
+		p := NewFallbackEmbeddingsProvider(primary, secondary)
+		if _, err := p.Embed(context.Background(), EmbeddingsRequest{}); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+		if secondary.calls != 0 {
+			t.Errorf("secondary called %d times, want 0", secondary.calls)
+		}
+	})
+}
+
+func TestFallbackSemanticProvider(t *testing.T) {
+	primary := &mockSemanticProvider{name: "primary", available: false}
+	secondary := &mockSemanticProvider{name: "secondary", available: true}
+
+	p := NewFallbackSemanticProvider(primary, secondary)
+	if _, err := p.Analyze(context.Background(), SemanticInput{Text: "hello"}); err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if p.ServedBy() != "secondary" {
+		t.Errorf("ServedBy() = %q, want %q", p.ServedBy(), "secondary")
+	}
+}
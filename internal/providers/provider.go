@@ -72,6 +72,27 @@ type SemanticInput struct {
 
 	// Meta contains additional context about the file.
 	Meta map[string]any
+
+	// Neighbors carries surrounding context for chunk-level inputs; nil for
+	// whole-file inputs.
+	Neighbors *SemanticNeighbors
+}
+
+// SemanticNeighbors describes the context surrounding a chunk so providers can
+// summarize it without seeing the whole file. Neighbor content is truncated to
+// keep within the input token budget.
+type SemanticNeighbors struct {
+	// Previous is the tail of the preceding chunk.
+	Previous string
+
+	// Next is the head of the following chunk.
+	Next string
+
+	// SectionPath is the enclosing heading path for document chunks.
+	SectionPath string
+
+	// ClassName is the enclosing class/struct/interface for code chunks.
+	ClassName string
 }
 
 // SemanticCapabilities describes model-specific input limits and supported modalities.
@@ -113,6 +134,9 @@ type SemanticResult struct {
 	// Entities are named entities found in the content.
 	Entities []Entity `json:"entities"`
 
+	// Relations are subject-predicate-object links between entities.
+	Relations []EntityRelation `json:"relations,omitempty"`
+
 	// References are external references found in the content.
 	References []Reference `json:"references"`
 
@@ -137,6 +161,11 @@ type SemanticResult struct {
 	// TokensUsed is the number of tokens consumed.
 	TokensUsed int `json:"tokens_used"`
 
+	// InputTokens and OutputTokens split TokensUsed when the provider reports
+	// them; zero when unavailable.
+	InputTokens  int `json:"input_tokens,omitempty"`
+	OutputTokens int `json:"output_tokens,omitempty"`
+
 	// Version is the analysis version for cache invalidation.
 	Version int `json:"version"`
 }
@@ -153,6 +182,14 @@ type Entity struct {
 	Type string `json:"type"` // person, organization, location, concept, etc.
 }
 
+// EntityRelation links two named entities through a predicate
+// (e.g. "Alice" WORKS_AT "Acme").
+type EntityRelation struct {
+	Subject   string `json:"subject"`
+	Predicate string `json:"predicate"`
+	Object    string `json:"object"`
+}
+
 // Reference represents an external reference found in the content.
 type Reference struct {
 	Type   string `json:"type"`   // url, file, package, etc.
@@ -180,6 +217,24 @@ type EmbeddingsProvider interface {
 	MaxTokens() int
 }
 
+// EmbeddingsBatchLimits bounds the size of a single EmbedBatch call.
+type EmbeddingsBatchLimits struct {
+	// MaxInputs is the most texts per call; zero is unbounded.
+	MaxInputs int
+
+	// MaxTokens is the most total tokens per call; zero is unbounded.
+	MaxTokens int
+}
+
+// BatchLimitedEmbeddingsProvider is implemented by embeddings providers whose
+// API bounds the number of texts or tokens in one EmbedBatch call.
+type BatchLimitedEmbeddingsProvider interface {
+	EmbeddingsProvider
+
+	// BatchLimits returns the per-call batch limits.
+	BatchLimits() EmbeddingsBatchLimits
+}
+
 // EmbeddingsRequest represents a request for embeddings generation.
 type EmbeddingsRequest struct {
 	// Content is the text to embed.
@@ -190,6 +245,10 @@ type EmbeddingsRequest struct {
 
 	// ContentHash is the hash of the content for cache lookup.
 	ContentHash string
+
+	// Truncation controls how content over the model's token limit is handled;
+	// empty sends the content unchanged.
+	Truncation TruncationStrategy
 }
 
 // EmbeddingsResult contains the results of embeddings generation.
@@ -214,6 +273,9 @@ type EmbeddingsResult struct {
 
 	// Version is the embedding version for cache invalidation.
 	Version int `json:"version"`
+
+	// Truncated reports whether the content was shortened to fit the model.
+	Truncated bool `json:"truncated,omitempty"`
 }
 
 // EmbeddingsBatchResult contains the result for a single item in a batch.
@@ -37,18 +37,28 @@ func NewRateLimiter(config RateLimitConfig) *RateLimiter {
 
 // Wait blocks until a token is available or context is canceled.
 func (r *RateLimiter) Wait(ctx context.Context) error {
+	return r.WaitN(ctx, r.requestTokens)
+}
+
+// WaitN blocks until n tokens are available or context is canceled. Requests
+// for more than the bucket holds wait for a full bucket.
+func (r *RateLimiter) WaitN(ctx context.Context, n float64) error {
+	if n > r.maxTokens {
+		n = r.maxTokens
+	}
+
 	for {
 		r.mu.Lock()
 		r.refill()
 
-		if r.tokens >= r.requestTokens {
-			r.tokens -= r.requestTokens
+		if r.tokens >= n {
+			r.tokens -= n
 			r.mu.Unlock()
 			return nil
 		}
 
 		// Calculate wait time
-		tokensNeeded := r.requestTokens - r.tokens
+		tokensNeeded := n - r.tokens
 		waitTime := time.Duration(tokensNeeded/r.refillRate*1000) * time.Millisecond
 		r.mu.Unlock()
 
@@ -97,17 +107,78 @@ func (r *RateLimiter) Available() float64 {
 	return r.tokens
 }
 
+// UsageLimiter enforces a provider's requests-per-minute and tokens-per-minute
+// limits together. Limits that are zero are not enforced, and a nil
+// UsageLimiter never blocks.
+type UsageLimiter struct {
+	requests *RateLimiter
+	tokens   *RateLimiter
+}
+
+// NewUsageLimiter creates a usage limiter for a provider's rate limits.
+func NewUsageLimiter(config RateLimitConfig) *UsageLimiter {
+	l := &UsageLimiter{}
+	if config.RequestsPerMinute > 0 {
+		l.requests = NewRateLimiter(config)
+	}
+	if config.TokensPerMinute > 0 {
+		// A minute's worth of tokens may be spent at once
+		l.tokens = NewRateLimiter(RateLimitConfig{RequestsPerMinute: config.TokensPerMinute})
+	}
+	return l
+}
+
+// Wait blocks until a request consuming the given number of tokens fits
+// within both limits, or context is canceled.
+func (l *UsageLimiter) Wait(ctx context.Context, tokens int) error {
+	if l == nil {
+		return nil
+	}
+	if l.requests != nil {
+		if err := l.requests.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	if l.tokens != nil && tokens > 0 {
+		if err := l.tokens.WaitN(ctx, float64(tokens)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // RateLimiterManager manages rate limiters for multiple providers.
 type RateLimiterManager struct {
 	mu       sync.RWMutex
 	limiters map[string]*RateLimiter
+	usage    map[string]*UsageLimiter
 }
 
 // NewRateLimiterManager creates a new rate limiter manager.
 func NewRateLimiterManager() *RateLimiterManager {
 	return &RateLimiterManager{
 		limiters: make(map[string]*RateLimiter),
+		usage:    make(map[string]*UsageLimiter),
+	}
+}
+
+// GetOrCreateUsage returns the usage limiter for a provider, creating if
+// needed. Returns nil on a nil manager.
+func (m *RateLimiterManager) GetOrCreateUsage(providerName string, config RateLimitConfig) *UsageLimiter {
+	if m == nil {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if limiter, exists := m.usage[providerName]; exists {
+		return limiter
 	}
+
+	limiter := NewUsageLimiter(config)
+	m.usage[providerName] = limiter
+	return limiter
 }
 
 // GetOrCreate returns the rate limiter for a provider, creating if needed.
@@ -139,3 +139,55 @@ func TestRateLimiterManager_Get(t *testing.T) {
 		t.Error("expected non-nil rate limiter")
 	}
 }
+
+func TestUsageLimiter_TokensPerMinute(t *testing.T) {
+	// 6000 tokens/minute refills 100 tokens per second
+	limiter := NewUsageLimiter(RateLimitConfig{TokensPerMinute: 6000})
+	ctx := context.Background()
+
+	// A full minute's budget is available at once, and oversize requests are capped to it
+	start := time.Now()
+	if err := limiter.Wait(ctx, 10000); err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("first request took %v, want immediate", elapsed)
+	}
+
+	// The bucket is empty; 10 tokens take about 100ms to refill
+	start = time.Now()
+	if err := limiter.Wait(ctx, 10); err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("second request took %v, want about 100ms", elapsed)
+	}
+}
+
+func TestUsageLimiter_NilAndUnlimited(t *testing.T) {
+	var nilLimiter *UsageLimiter
+	if err := nilLimiter.Wait(context.Background(), 1000); err != nil {
+		t.Errorf("nil limiter Wait = %v, want nil", err)
+	}
+
+	unlimited := NewUsageLimiter(RateLimitConfig{})
+	for i := 0; i < 100; i++ {
+		if err := unlimited.Wait(context.Background(), 1000); err != nil {
+			t.Fatalf("unlimited Wait = %v, want nil", err)
+		}
+	}
+}
+
+func TestRateLimiterManager_GetOrCreateUsage(t *testing.T) {
+	manager := NewRateLimiterManager()
+	config := RateLimitConfig{RequestsPerMinute: 60, TokensPerMinute: 1000}
+
+	if manager.GetOrCreateUsage("test", config) != manager.GetOrCreateUsage("test", config) {
+		t.Error("expected same usage limiter instance")
+	}
+
+	var nilManager *RateLimiterManager
+	if nilManager.GetOrCreateUsage("test", config) != nil {
+		t.Error("expected nil usage limiter from nil manager")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"mime/multipart"
 	"net/http"
 	"os"
@@ -124,16 +125,28 @@ func (p *AnthropicProvider) Capabilities() providers.SemanticCapabilities {
 		Model:           p.model,
 	}
 
-	switch p.model {
-	case "claude-sonnet-4-5-20250929", "claude-opus-4-5-20251101":
-		caps.MaxInputTokens = 1000000
-	case "claude-haiku-4-5-20251015":
-		caps.MaxInputTokens = 200000
+	// Match by family prefix so undated aliases resolve like dated snapshots
+	for _, limit := range anthropicInputTokenLimits {
+		if strings.HasPrefix(p.model, limit.prefix) {
+			caps.MaxInputTokens = limit.tokens
+			break
+		}
 	}
 
 	return caps
 }
 
+// anthropicInputTokenLimits lists context windows by model family. Models not
+// listed use the 200k default.
+var anthropicInputTokenLimits = []struct {
+	prefix string
+	tokens int
+}{
+	{"claude-sonnet-4-5", 1000000},
+	{"claude-opus-4-5", 1000000},
+	{"claude-haiku-4-5", 200000},
+}
+
 // Analyze performs semantic analysis on the given file-level input.
 func (p *AnthropicProvider) Analyze(ctx context.Context, input providers.SemanticInput) (*providers.SemanticResult, error) {
 	if !p.Available() {
@@ -208,13 +221,24 @@ func (p *AnthropicProvider) Analyze(ctx context.Context, input providers.Semanti
 
 	result, err := parseAnalysisResponse(textContent)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse analysis; %w", err)
+		partial, partialErr := parsePartialAnalysisResponse(textContent)
+		if partialErr != nil {
+			return nil, fmt.Errorf("failed to parse analysis; %w", err)
+		}
+		slog.Warn("semantic analysis response was not valid JSON; using partially parsed result",
+			"provider", p.Name(),
+			"path", input.Path,
+			"stop_reason", apiResp.StopReason,
+			"error", err)
+		result = partial
 	}
 
 	result.ProviderName = p.Name()
 	result.ModelName = p.model
 	result.AnalyzedAt = time.Now()
 	result.TokensUsed = apiResp.Usage.InputTokens + apiResp.Usage.OutputTokens
+	result.InputTokens = apiResp.Usage.InputTokens
+	result.OutputTokens = apiResp.Usage.OutputTokens
 	result.Version = analysisVersion
 
 	return result, nil
@@ -332,7 +356,8 @@ type anthropicResponse struct {
 		Type string `json:"type"`
 		Text string `json:"text"`
 	} `json:"content"`
-	Usage struct {
+	StopReason string `json:"stop_reason"`
+	Usage      struct {
 		InputTokens  int `json:"input_tokens"`
 		OutputTokens int `json:"output_tokens"`
 	} `json:"usage"`
@@ -347,6 +372,7 @@ Respond with a JSON object containing:
 - tags: Array of categorical labels (e.g., "documentation", "implementation", "test", "config")
 - topics: Array of objects with "name" and "confidence" (0.0-1.0) fields
 - entities: Array of objects with "name" and "type" fields (types: person, organization, concept, technology, package)
+- relations: Array of objects with "subject", "predicate", and "object" fields linking entity names (e.g. {"subject": "Alice", "predicate": "WORKS_AT", "object": "Acme"})
 - references: Array of objects with "type" (url, file, package) and "target" fields
 - language: Programming language if code, or natural language
 - complexity: Integer 1-10 indicating complexity
@@ -0,0 +1,262 @@
+package semantic
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/leefowlercu/agentic-memorizer/internal/providers"
+)
+
+// redirectTransport sends every request to a test server, keeping the path.
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (t redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newAnthropicTestProvider returns a provider whose messages API replies with
+// the given model text and stop reason.
+func newAnthropicTestProvider(t *testing.T, text string, stopReason string) *AnthropicProvider {
+	t.Helper()
+	t.Setenv("ANTHROPIC_API_KEY", "test-key")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" {
+			t.Errorf("expected /v1/messages, got %s", r.URL.Path)
+		}
+		if r.Header.Get("x-api-key") != "test-key" {
+			t.Errorf("expected x-api-key test-key, got %q", r.Header.Get("x-api-key"))
+		}
+		if r.Header.Get("anthropic-version") != anthropicAPIVersion {
+			t.Errorf("expected anthropic-version %s, got %q", anthropicAPIVersion, r.Header.Get("anthropic-version"))
+		}
+
+		var reqBody struct {
+			Model string `json:"model"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		if reqBody.Model != "claude-haiku-4-5" {
+			t.Errorf("expected model claude-haiku-4-5, got %q", reqBody.Model)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"content":     []map[string]any{{"type": "text", "text": text}},
+			"stop_reason": stopReason,
+			"usage":       map[string]any{"input_tokens": 120, "output_tokens": 30},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	target, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("failed to parse server URL: %v", err)
+	}
+
+	return NewAnthropicProvider(
+		WithModel("claude-haiku-4-5"),
+		WithHTTPClient(&http.Client{Transport: redirectTransport{target: target}}),
+	)
+}
+
+func TestAnthropicProvider_Analyze(t *testing.T) {
+	text := `{
+		"summary": "Parses configuration files.",
+		"tags": ["implementation", "config"],
+		"topics": [{"name": "configuration", "confidence": 0.9}],
+		"entities": [{"name": "YAML", "type": "technology"}],
+		"language": "go",
+		"complexity": 4,
+		"keywords": ["config", "yaml"]
+	}`
+	p := newAnthropicTestProvider(t, text, "end_turn")
+
+	result, err := p.Analyze(context.Background(), providers.SemanticInput{
+		Type:     providers.SemanticInputText,
+		Path:     "/repo/config.go",
+		Text:     "package config",
+		MIMEType: "text/x-go",
+	})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	if result.Summary != "Parses configuration files." {
+		t.Errorf("Summary = %q", result.Summary)
+	}
+	if len(result.Tags) != 2 || result.Tags[1] != "config" {
+		t.Errorf("Tags = %v", result.Tags)
+	}
+	if len(result.Topics) != 1 || result.Topics[0].Confidence != 0.9 {
+		t.Errorf("Topics = %v", result.Topics)
+	}
+	if len(result.Entities) != 1 || result.Entities[0].Type != "technology" {
+		t.Errorf("Entities = %v", result.Entities)
+	}
+	if result.Complexity != 4 {
+		t.Errorf("Complexity = %d, want 4", result.Complexity)
+	}
+	if result.ProviderName != "anthropic" || result.ModelName != "claude-haiku-4-5" {
+		t.Errorf("provider/model = %q/%q", result.ProviderName, result.ModelName)
+	}
+	if result.InputTokens != 120 || result.OutputTokens != 30 || result.TokensUsed != 150 {
+		t.Errorf("tokens = %d in, %d out, %d total", result.InputTokens, result.OutputTokens, result.TokensUsed)
+	}
+}
+
+func TestAnthropicProvider_Analyze_PartialJSON(t *testing.T) {
+	// Output cut off at the token limit partway through the topics list
+	text := `{"summary": "Parses configuration files.", "tags": ["implementation", "config"], "topics": [{"name": "configuration", "confidence": 0.9}, {"name": "pars`
+	p := newAnthropicTestProvider(t, text, "max_tokens")
+
+	result, err := p.Analyze(context.Background(), providers.SemanticInput{
+		Type: providers.SemanticInputText,
+		Path: "/repo/config.go",
+		Text: "package config",
+	})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	if result.Summary != "Parses configuration files." {
+		t.Errorf("Summary = %q", result.Summary)
+	}
+	if len(result.Tags) != 2 {
+		t.Errorf("Tags = %v", result.Tags)
+	}
+	if len(result.Topics) == 0 || result.Topics[0].Name != "configuration" {
+		t.Errorf("Topics = %v", result.Topics)
+	}
+	if result.ProviderName != "anthropic" || result.TokensUsed != 150 {
+		t.Errorf("metadata not set on partial result: %+v", result)
+	}
+}
+
+func TestAnthropicProvider_Analyze_InvalidJSON(t *testing.T) {
+	p := newAnthropicTestProvider(t, "I could not analyze this file.", "end_turn")
+
+	_, err := p.Analyze(context.Background(), providers.SemanticInput{
+		Type: providers.SemanticInputText,
+		Path: "/repo/config.go",
+		Text: "package config",
+	})
+	if err == nil {
+		t.Error("expected error when the response contains no JSON")
+	}
+}
+
+func TestAnthropicProvider_Capabilities(t *testing.T) {
+	tests := []struct {
+		model string
+		want  int
+	}{
+		{"claude-sonnet-4-5-20250929", 1000000},
+		{"claude-sonnet-4-5", 1000000},
+		{"claude-haiku-4-5-20251015", 200000},
+		{"claude-3-5-haiku-latest", 200000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			caps := NewAnthropicProvider(WithModel(tt.model)).Capabilities()
+			if caps.MaxInputTokens != tt.want {
+				t.Errorf("MaxInputTokens = %d, want %d", caps.MaxInputTokens, tt.want)
+			}
+			if caps.Model != tt.model {
+				t.Errorf("Model = %q, want %q", caps.Model, tt.model)
+			}
+		})
+	}
+}
+
+func TestParsePartialAnalysisResponse(t *testing.T) {
+	tests := []struct {
+		name        string
+		text        string
+		wantErr     bool
+		wantSummary string
+		wantTags    int
+		wantTopics  int
+		wantComplex int
+	}{
+		{
+			name:        "wrapped in prose",
+			text:        "Here is the analysis:\n```json\n{\"summary\": \"A test.\", \"tags\": [\"test\"]}\n```\nLet me know!",
+			wantSummary: "A test.",
+			wantTags:    1,
+		},
+		{
+			name:        "truncated inside a string",
+			text:        `{"summary": "A test.", "tags": ["test", "unit"], "keywords": ["asse`,
+			wantSummary: "A test.",
+			wantTags:    2,
+		},
+		{
+			name:        "truncated after a key",
+			text:        `{"summary": "A test.", "tags": ["test"], "complexity":`,
+			wantSummary: "A test.",
+			wantTags:    1,
+		},
+		{
+			name:        "malformed items are dropped",
+			text:        `{"summary": "A test.", "topics": [{"name": "a", "confidence": "high"}, {"name": "b", "confidence": 0.5}], "complexity": 6.6}`,
+			wantSummary: "A test.",
+			wantTopics:  1,
+			wantComplex: 7,
+		},
+		{
+			name:        "invalid value drops trailing fields",
+			text:        `{"summary": "A test.", "tags": ["test"], "complexity": high}`,
+			wantSummary: "A test.",
+			wantTags:    1,
+		},
+		{
+			name:    "no object",
+			text:    "no analysis available",
+			wantErr: true,
+		},
+		{
+			name:    "no recognized fields",
+			text:    `{"note": "nothing here"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parsePartialAnalysisResponse(tt.text)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %+v", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Summary != tt.wantSummary {
+				t.Errorf("Summary = %q, want %q", result.Summary, tt.wantSummary)
+			}
+			if len(result.Tags) != tt.wantTags {
+				t.Errorf("Tags = %v, want %d", result.Tags, tt.wantTags)
+			}
+			if len(result.Topics) != tt.wantTopics {
+				t.Errorf("Topics = %v, want %d", result.Topics, tt.wantTopics)
+			}
+			if result.Complexity != tt.wantComplex {
+				t.Errorf("Complexity = %d, want %d", result.Complexity, tt.wantComplex)
+			}
+		})
+	}
+}
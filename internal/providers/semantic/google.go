@@ -193,6 +193,8 @@ func (p *GoogleSemanticProvider) Analyze(ctx context.Context, input providers.Se
 	result.ModelName = p.model
 	result.AnalyzedAt = time.Now()
 	result.TokensUsed = apiResp.Usage.TotalTokens
+	result.InputTokens = apiResp.Usage.PromptTokens
+	result.OutputTokens = apiResp.Usage.CandidatesTokens
 	result.Version = analysisVersion
 
 	return result, nil
@@ -244,7 +246,9 @@ type googleResponse struct {
 		} `json:"content"`
 	} `json:"candidates"`
 	Usage struct {
-		TotalTokens int `json:"totalTokens"`
+		PromptTokens     int `json:"promptTokenCount"`
+		CandidatesTokens int `json:"candidatesTokenCount"`
+		TotalTokens      int `json:"totalTokenCount"`
 	} `json:"usageMetadata"`
 }
 
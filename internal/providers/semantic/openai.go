@@ -193,7 +193,7 @@ func (p *OpenAISemanticProvider) Analyze(ctx context.Context, input providers.Se
 		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
 	}
 
-	text, tokens, err := parseOpenAIResponse(body)
+	text, usage, err := parseOpenAIResponse(body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse response; %w", err)
 	}
@@ -206,7 +206,9 @@ func (p *OpenAISemanticProvider) Analyze(ctx context.Context, input providers.Se
 	result.ProviderName = p.Name()
 	result.ModelName = p.model
 	result.AnalyzedAt = time.Now()
-	result.TokensUsed = tokens
+	result.TokensUsed = usage.TotalTokens
+	result.InputTokens = usage.InputTokens
+	result.OutputTokens = usage.OutputTokens
 	result.Version = analysisVersion
 
 	return result, nil
@@ -258,6 +260,21 @@ func buildTextContext(input providers.SemanticInput) string {
 	if input.Truncated {
 		context += "Content was truncated to fit model limits.\n"
 	}
+	if n := input.Neighbors; n != nil {
+		context += "Content is one chunk of the file.\n"
+		if n.SectionPath != "" {
+			context += "Section: " + n.SectionPath + "\n"
+		}
+		if n.ClassName != "" {
+			context += "Enclosing type: " + n.ClassName + "\n"
+		}
+		if n.Previous != "" {
+			context += "\nPreceding content (context only):\n" + n.Previous + "\n"
+		}
+		if n.Next != "" {
+			context += "\nFollowing content (context only):\n" + n.Next + "\n"
+		}
+	}
 	context += "\nContent:\n" + input.Text
 	return context
 }
@@ -318,7 +335,14 @@ func (p *OpenAISemanticProvider) uploadFile(ctx context.Context, data []byte, pa
 	return fileResp.ID, nil
 }
 
-func parseOpenAIResponse(body []byte) (string, int, error) {
+// openAIUsage is the token usage reported by the Responses API.
+type openAIUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+	TotalTokens  int `json:"total_tokens"`
+}
+
+func parseOpenAIResponse(body []byte) (string, openAIUsage, error) {
 	var resp struct {
 		Output []struct {
 			Content []struct {
@@ -326,22 +350,20 @@ func parseOpenAIResponse(body []byte) (string, int, error) {
 				Text string `json:"text"`
 			} `json:"content"`
 		} `json:"output"`
-		Usage struct {
-			TotalTokens int `json:"total_tokens"`
-		} `json:"usage"`
+		Usage openAIUsage `json:"usage"`
 	}
 
 	if err := json.Unmarshal(body, &resp); err != nil {
-		return "", 0, err
+		return "", openAIUsage{}, err
 	}
 
 	for _, item := range resp.Output {
 		for _, content := range item.Content {
 			if content.Text != "" {
-				return content.Text, resp.Usage.TotalTokens, nil
+				return content.Text, resp.Usage, nil
 			}
 		}
 	}
 
-	return "", resp.Usage.TotalTokens, fmt.Errorf("no output text in response")
+	return "", resp.Usage, fmt.Errorf("no output text in response")
 }
@@ -0,0 +1,181 @@
+package semantic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/leefowlercu/agentic-memorizer/internal/providers"
+)
+
+// parsePartialAnalysisResponse recovers what it can from an analysis response
+// that is not valid JSON, such as output cut off at the token limit or wrapped
+// in prose. Fields that fail to decode are skipped, as are malformed list
+// items. An error is returned only when no field could be recovered.
+func parsePartialAnalysisResponse(text string) (*providers.SemanticResult, error) {
+	fields, ok := decodeAnalysisFields(text)
+	if !ok {
+		return nil, fmt.Errorf("no JSON object found in response")
+	}
+
+	var result providers.SemanticResult
+	decodeField(fields["summary"], &result.Summary)
+	decodeField(fields["language"], &result.Language)
+	result.Tags = decodeList[string](fields["tags"])
+	result.Topics = decodeList[providers.Topic](fields["topics"])
+	result.Entities = decodeList[providers.Entity](fields["entities"])
+	result.Relations = decodeList[providers.EntityRelation](fields["relations"])
+	result.References = decodeList[providers.Reference](fields["references"])
+	result.Keywords = decodeList[string](fields["keywords"])
+
+	var complexity float64
+	if decodeField(fields["complexity"], &complexity) {
+		result.Complexity = int(math.Round(complexity))
+	}
+
+	if result.Summary == "" && len(result.Tags) == 0 && len(result.Topics) == 0 &&
+		len(result.Entities) == 0 && len(result.Keywords) == 0 {
+		return nil, fmt.Errorf("no analysis fields recovered from response")
+	}
+
+	return &result, nil
+}
+
+// decodeAnalysisFields extracts the top-level fields of the first JSON object
+// in text. Truncated output is repaired by closing open strings and containers,
+// backing off to earlier element boundaries until the object decodes.
+func decodeAnalysisFields(text string) (map[string]json.RawMessage, bool) {
+	start := strings.IndexByte(text, '{')
+	if start < 0 {
+		return nil, false
+	}
+	text = text[start:]
+
+	// A complete object followed by trailing prose decodes as-is.
+	var fields map[string]json.RawMessage
+	if err := json.NewDecoder(strings.NewReader(text)).Decode(&fields); err == nil {
+		return fields, true
+	}
+
+	for _, candidate := range truncationRepairs(text) {
+		fields = nil
+		if err := json.Unmarshal([]byte(candidate), &fields); err == nil {
+			return fields, true
+		}
+	}
+
+	return nil, false
+}
+
+// truncationRepairs returns candidate completions of a truncated JSON object,
+// most complete first: the text with its open string and containers closed,
+// then the text cut at each preceding element separator.
+func truncationRepairs(text string) []string {
+	var (
+		stack    []byte
+		cuts     []elementCut
+		inString bool
+		escaped  bool
+	)
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			stack = append(stack, c)
+		case '}', ']':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			if len(stack) == 0 {
+				// The object is complete but failed to decode; only
+				// dropping trailing elements can help
+				return append([]string{text[:i+1]}, cutRepairs(text, cuts)...)
+			}
+		case ',':
+			cuts = append(cuts, elementCut{pos: i, stack: string(stack)})
+		}
+	}
+
+	full := text
+	if inString {
+		if escaped {
+			full = full[:len(full)-1]
+		}
+		full += `"`
+	}
+	full = strings.TrimRight(full, ",: \t\r\n")
+
+	return append([]string{full + closers(string(stack))}, cutRepairs(text, cuts)...)
+}
+
+// elementCut is a separator position in a truncated object along with the
+// containers open at that point.
+type elementCut struct {
+	pos   int
+	stack string
+}
+
+// cutRepairs closes text at each separator, latest first.
+func cutRepairs(text string, cuts []elementCut) []string {
+	repairs := make([]string, 0, len(cuts))
+	for i := len(cuts) - 1; i >= 0; i-- {
+		repairs = append(repairs, text[:cuts[i].pos]+closers(cuts[i].stack))
+	}
+	return repairs
+}
+
+// closers returns the brackets that close the given stack of open containers.
+func closers(stack string) string {
+	var b bytes.Buffer
+	for i := len(stack) - 1; i >= 0; i-- {
+		if stack[i] == '{' {
+			b.WriteByte('}')
+		} else {
+			b.WriteByte(']')
+		}
+	}
+	return b.String()
+}
+
+// decodeField decodes raw into dst, reporting whether it succeeded.
+func decodeField(raw json.RawMessage, dst any) bool {
+	if len(raw) == 0 {
+		return false
+	}
+	return json.Unmarshal(raw, dst) == nil
+}
+
+// decodeList decodes a JSON array item by item, dropping items that do not
+// match T.
+func decodeList[T any](raw json.RawMessage) []T {
+	var items []json.RawMessage
+	if !decodeField(raw, &items) {
+		return nil
+	}
+
+	list := make([]T, 0, len(items))
+	for _, item := range items {
+		var v T
+		if decodeField(item, &v) {
+			list = append(list, v)
+		}
+	}
+	return list
+}
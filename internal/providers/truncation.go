@@ -0,0 +1,122 @@
+package providers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"unicode/utf8"
+
+	"github.com/leefowlercu/agentic-memorizer/internal/chunkers"
+)
+
+// TruncationStrategy controls how embeddings inputs longer than the model's
+// token limit are handled.
+type TruncationStrategy string
+
+const (
+	// TruncationError rejects oversize inputs with ErrInputTooLong.
+	TruncationError TruncationStrategy = "error"
+
+	// TruncationEnd keeps the start of the input and drops the end.
+	TruncationEnd TruncationStrategy = "truncate-end"
+
+	// TruncationMiddle keeps the start and end of the input and drops the middle.
+	TruncationMiddle TruncationStrategy = "truncate-middle"
+)
+
+// truncationMarker joins the kept head and tail under TruncationMiddle.
+const truncationMarker = "\n...\n"
+
+// ErrInputTooLong is returned when an input exceeds the model's token limit
+// under TruncationError.
+var ErrInputTooLong = errors.New("input exceeds model token limit")
+
+// ParseTruncationStrategy validates a truncation strategy name.
+func ParseTruncationStrategy(s string) (TruncationStrategy, error) {
+	switch strategy := TruncationStrategy(s); strategy {
+	case TruncationError, TruncationEnd, TruncationMiddle:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unknown truncation strategy %q; must be one of error, truncate-end, truncate-middle", s)
+	}
+}
+
+// TruncateText fits text within maxTokens using strategy, returning the text to
+// send and whether it was shortened. An empty strategy or a non-positive
+// maxTokens leaves text unchanged.
+func TruncateText(text string, maxTokens int, strategy TruncationStrategy) (string, bool, error) {
+	if strategy == "" || maxTokens <= 0 {
+		return text, false, nil
+	}
+
+	tokens := chunkers.CountTokens(text)
+	if tokens <= maxTokens {
+		return text, false, nil
+	}
+
+	switch strategy {
+	case TruncationError:
+		return "", false, fmt.Errorf("%w; %d tokens, limit %d", ErrInputTooLong, tokens, maxTokens)
+	case TruncationEnd:
+		return headWithin(text, maxTokens), true, nil
+	case TruncationMiddle:
+		budget := max(maxTokens-chunkers.CountTokens(truncationMarker), 0)
+		head := headWithin(text, budget/2)
+		tail := tailWithin(text[len(head):], budget-budget/2)
+		return head + truncationMarker + tail, true, nil
+	default:
+		return "", false, fmt.Errorf("unknown truncation strategy %q", strategy)
+	}
+}
+
+// TruncateEmbeddingsInput applies req.Truncation to req.Content against the
+// provider's token limit, logging a warning when the input is shortened.
+func TruncateEmbeddingsInput(p EmbeddingsProvider, req EmbeddingsRequest) (string, bool, error) {
+	text, truncated, err := TruncateText(req.Content, p.MaxTokens(), req.Truncation)
+	if err != nil {
+		return "", false, err
+	}
+	if truncated {
+		slog.Warn("embeddings input truncated",
+			"provider", p.Name(),
+			"strategy", req.Truncation,
+			"max_tokens", p.MaxTokens(),
+			"original_bytes", len(req.Content),
+			"sent_bytes", len(text))
+	}
+	return text, truncated, nil
+}
+
+// headWithin returns the longest rune-aligned prefix of text within budget tokens.
+func headWithin(text string, budget int) string {
+	lo, hi := 0, len(text)
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if chunkers.CountTokens(text[:mid]) <= budget {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	for lo > 0 && lo < len(text) && !utf8.RuneStart(text[lo]) {
+		lo--
+	}
+	return text[:lo]
+}
+
+// tailWithin returns the longest rune-aligned suffix of text within budget tokens.
+func tailWithin(text string, budget int) string {
+	lo, hi := 0, len(text)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		if chunkers.CountTokens(text[mid:]) <= budget {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	for lo < len(text) && !utf8.RuneStart(text[lo]) {
+		lo++
+	}
+	return text[lo:]
+}
@@ -19,6 +19,7 @@ type PathConfigPatch struct {
 	AddIncludeExtensions  []string `json:"add_include_extensions,omitempty"`
 	AddIncludeDirectories []string `json:"add_include_directories,omitempty"`
 	AddIncludeFiles       []string `json:"add_include_files,omitempty"`
+	AddIncludeHiddenGlobs []string `json:"add_include_hidden_globs,omitempty"`
 }
 
 // IsEmpty returns true if the patch has no changes.
@@ -36,7 +37,8 @@ func (p *PathConfigPatch) IsEmpty() bool {
 		len(p.AddSkipFiles) == 0 &&
 		len(p.AddIncludeExtensions) == 0 &&
 		len(p.AddIncludeDirectories) == 0 &&
-		len(p.AddIncludeFiles) == 0
+		len(p.AddIncludeFiles) == 0 &&
+		len(p.AddIncludeHiddenGlobs) == 0
 }
 
 // ApplyPathConfigPatch applies a patch to a base config and returns a new config.
@@ -83,6 +85,9 @@ func ApplyPathConfigPatch(base *PathConfig, patch *PathConfigPatch) *PathConfig
 	if len(patch.AddIncludeFiles) > 0 {
 		cfg.IncludeFiles = mergeUnique(cfg.IncludeFiles, patch.AddIncludeFiles)
 	}
+	if len(patch.AddIncludeHiddenGlobs) > 0 {
+		cfg.IncludeHiddenGlobs = mergeUnique(cfg.IncludeHiddenGlobs, patch.AddIncludeHiddenGlobs)
+	}
 
 	return cfg
 }
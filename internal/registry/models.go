@@ -22,6 +22,18 @@ type FileState = storage.FileState
 // FileDiscovery tracks files discovered by the walker/watcher.
 type FileDiscovery = storage.FileDiscovery
 
+// PendingWorkItem is an analysis work item recorded so it survives a restart.
+type PendingWorkItem = storage.PendingWorkItem
+
+// DeadLetter is an analysis work item that failed permanently.
+type DeadLetter = storage.DeadLetter
+
+// StageStatusCounts contains file counts for a single analysis stage.
+type StageStatusCounts = storage.StageStatusCounts
+
+// AnalysisStatusCounts contains registry-wide file counts for each analysis stage.
+type AnalysisStatusCounts = storage.AnalysisStatusCounts
+
 // PathStatus represents the health status of a remembered path.
 type PathStatus = storage.PathStatus
 
@@ -2,6 +2,7 @@ package registry
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/leefowlercu/agentic-memorizer/internal/storage"
@@ -13,6 +14,9 @@ var ErrPathNotFound = storage.ErrPathNotFound
 // ErrPathExists is returned when attempting to add a path that already exists.
 var ErrPathExists = storage.ErrPathExists
 
+// ErrSchemaTooNew is returned when the database was migrated by a newer binary.
+var ErrSchemaTooNew = storage.ErrSchemaTooNew
+
 // Registry manages remembered paths and file state in SQLite.
 type Registry interface {
 	// Path management
@@ -47,13 +51,31 @@ type Registry interface {
 	// Granular analysis state updates
 	UpdateMetadataState(ctx context.Context, path string, contentHash string, metadataHash string, size int64, modTime time.Time) error
 	UpdateSemanticState(ctx context.Context, path string, analysisVersion string, err error) error
-	UpdateEmbeddingsState(ctx context.Context, path string, err error) error
+	UpdateEmbeddingsState(ctx context.Context, path string, model string, err error) error
+	UpdateChunkerState(ctx context.Context, path string, chunkerVersion string) error
 	ClearAnalysisState(ctx context.Context, path string) error
 
 	// Query methods for analysis scheduling
 	ListFilesNeedingMetadata(ctx context.Context, parentPath string) ([]FileState, error)
 	ListFilesNeedingSemantic(ctx context.Context, parentPath string, maxRetries int) ([]FileState, error)
 	ListFilesNeedingEmbeddings(ctx context.Context, parentPath string, maxRetries int) ([]FileState, error)
+	ListFilesWithStaleEmbeddings(ctx context.Context, parentPath string, currentModel string) ([]FileState, error)
+	CountByAnalysisStatus(ctx context.Context) (AnalysisStatusCounts, error)
+
+	// Analysis queue persistence
+	EnqueueWorkItem(ctx context.Context, item *PendingWorkItem) error
+	CompleteWorkItem(ctx context.Context, id int64, enqueuedAt time.Time) error
+	ListPendingWorkItems(ctx context.Context) ([]PendingWorkItem, error)
+
+	// Analysis dead letters
+	RecordDeadLetter(ctx context.Context, dl *DeadLetter) error
+	GetDeadLetter(ctx context.Context, filePath string) (*DeadLetter, error)
+	ListDeadLetters(ctx context.Context) ([]DeadLetter, error)
+	DeleteDeadLetter(ctx context.Context, filePath string) error
+
+	// Export and import for migrating to another host
+	Export(ctx context.Context, w io.Writer) error
+	Import(ctx context.Context, r io.Reader) error
 
 	// Path health checking
 	CheckPathHealth(ctx context.Context) ([]PathStatus, error)
@@ -71,8 +93,8 @@ type SQLiteRegistry struct {
 
 // Open creates a new SQLiteRegistry with the given database path.
 // This function maintains backward compatibility with existing code.
-func Open(ctx context.Context, dbPath string) (*SQLiteRegistry, error) {
-	s, err := storage.Open(ctx, dbPath)
+func Open(ctx context.Context, dbPath string, opts ...storage.Option) (*SQLiteRegistry, error) {
+	s, err := storage.Open(ctx, dbPath, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -191,8 +213,13 @@ func (r *SQLiteRegistry) UpdateSemanticState(ctx context.Context, path string, a
 }
 
 // UpdateEmbeddingsState updates the embeddings generation tracking fields for a file.
-func (r *SQLiteRegistry) UpdateEmbeddingsState(ctx context.Context, path string, err error) error {
-	return r.storage.UpdateEmbeddingsState(ctx, path, err)
+func (r *SQLiteRegistry) UpdateEmbeddingsState(ctx context.Context, path string, model string, err error) error {
+	return r.storage.UpdateEmbeddingsState(ctx, path, model, err)
+}
+
+// UpdateChunkerState records the chunker version that produced a file's current chunks.
+func (r *SQLiteRegistry) UpdateChunkerState(ctx context.Context, path string, chunkerVersion string) error {
+	return r.storage.UpdateChunkerState(ctx, path, chunkerVersion)
 }
 
 // UpdateDiscoveryState updates the discovery state for a file.
@@ -235,6 +262,62 @@ func (r *SQLiteRegistry) ListFilesNeedingEmbeddings(ctx context.Context, parentP
 	return r.storage.ListFilesNeedingEmbeddings(ctx, parentPath, maxRetries)
 }
 
+// ListFilesWithStaleEmbeddings returns files embedded with a model other than currentModel.
+func (r *SQLiteRegistry) ListFilesWithStaleEmbeddings(ctx context.Context, parentPath string, currentModel string) ([]FileState, error) {
+	return r.storage.ListFilesWithStaleEmbeddings(ctx, parentPath, currentModel)
+}
+
+// CountByAnalysisStatus returns registry-wide file counts for each analysis stage.
+func (r *SQLiteRegistry) CountByAnalysisStatus(ctx context.Context) (AnalysisStatusCounts, error) {
+	return r.storage.CountByAnalysisStatus(ctx)
+}
+
+// EnqueueWorkItem records a pending analysis work item so it survives a restart.
+func (r *SQLiteRegistry) EnqueueWorkItem(ctx context.Context, item *PendingWorkItem) error {
+	return r.storage.EnqueueWorkItem(ctx, item)
+}
+
+// CompleteWorkItem removes a pending analysis work item once it has been processed,
+// unless it was re-enqueued after enqueuedAt.
+func (r *SQLiteRegistry) CompleteWorkItem(ctx context.Context, id int64, enqueuedAt time.Time) error {
+	return r.storage.CompleteWorkItem(ctx, id, enqueuedAt)
+}
+
+// ListPendingWorkItems returns the pending analysis work items in enqueue order.
+func (r *SQLiteRegistry) ListPendingWorkItems(ctx context.Context) ([]PendingWorkItem, error) {
+	return r.storage.ListPendingWorkItems(ctx)
+}
+
+// RecordDeadLetter records a permanently failed analysis work item.
+func (r *SQLiteRegistry) RecordDeadLetter(ctx context.Context, dl *DeadLetter) error {
+	return r.storage.RecordDeadLetter(ctx, dl)
+}
+
+// GetDeadLetter returns the dead letter for a path.
+func (r *SQLiteRegistry) GetDeadLetter(ctx context.Context, filePath string) (*DeadLetter, error) {
+	return r.storage.GetDeadLetter(ctx, filePath)
+}
+
+// ListDeadLetters returns all dead letters, most recently failed first.
+func (r *SQLiteRegistry) ListDeadLetters(ctx context.Context) ([]DeadLetter, error) {
+	return r.storage.ListDeadLetters(ctx)
+}
+
+// DeleteDeadLetter removes the dead letter for a path.
+func (r *SQLiteRegistry) DeleteDeadLetter(ctx context.Context, filePath string) error {
+	return r.storage.DeleteDeadLetter(ctx, filePath)
+}
+
+// Export writes all remembered paths and file states to w as newline-delimited JSON.
+func (r *SQLiteRegistry) Export(ctx context.Context, w io.Writer) error {
+	return r.storage.Export(ctx, w)
+}
+
+// Import upserts the remembered paths and file states from an export.
+func (r *SQLiteRegistry) Import(ctx context.Context, rd io.Reader) error {
+	return r.storage.Import(ctx, rd)
+}
+
 // CheckPathHealth validates all remembered paths and returns their status.
 func (r *SQLiteRegistry) CheckPathHealth(ctx context.Context) ([]PathStatus, error) {
 	return r.storage.CheckPathHealth(ctx)
@@ -1,11 +1,15 @@
 package registry
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sync"
 	"testing"
 	"time"
 )
@@ -43,6 +47,54 @@ func TestOpen_CreatesDirectory(t *testing.T) {
 	}
 }
 
+func TestUpdateFileState_ConcurrentWriters(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	// Two registries on one file contend like the daemon and a CLI command
+	var regs []*SQLiteRegistry
+	for range 2 {
+		reg, err := Open(ctx, dbPath)
+		if err != nil {
+			t.Fatalf("failed to open registry: %v", err)
+		}
+		defer reg.Close()
+		regs = append(regs, reg)
+	}
+
+	const writers = 50
+	errs := make(chan error, writers)
+	var wg sync.WaitGroup
+	for i := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- regs[i%len(regs)].UpdateFileState(ctx, &FileState{
+				Path:        fmt.Sprintf("/test/file-%d.go", i),
+				ContentHash: fmt.Sprintf("hash-%d", i),
+				Size:        int64(i),
+				ModTime:     time.Now(),
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("concurrent UpdateFileState failed: %v", err)
+		}
+	}
+
+	states, err := regs[0].ListFileStates(ctx, "/test")
+	if err != nil {
+		t.Fatalf("failed to list file states: %v", err)
+	}
+	if len(states) != writers {
+		t.Errorf("expected %d file states, got %d", writers, len(states))
+	}
+}
+
 func TestAddPath(t *testing.T) {
 	reg := newTestRegistry(t)
 	defer reg.Close()
@@ -349,6 +401,52 @@ func TestGetEffectiveConfig(t *testing.T) {
 	}
 }
 
+func TestGetEffectiveConfig_IncludeHiddenGlobs(t *testing.T) {
+	reg := newTestRegistry(t)
+	defer reg.Close()
+
+	ctx := context.Background()
+
+	config := &PathConfig{
+		SkipHidden:         true,
+		IncludeHiddenGlobs: []string{".github/workflows/*.yml", ".env.example"},
+	}
+	if err := reg.AddPath(ctx, "/projects/myapp", config); err != nil {
+		t.Fatalf("AddPath failed: %v", err)
+	}
+
+	cfg, err := reg.GetEffectiveConfig(ctx, "/projects/myapp/.github/workflows/ci.yml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"/projects/myapp/.env", false, true},
+		{"/projects/myapp/.env.example", false, false},
+		{"/projects/myapp/.github", true, false},
+		{"/projects/myapp/.github/workflows", true, false},
+		{"/projects/myapp/.github/workflows/ci.yml", false, false},
+		{"/projects/myapp/.github/CODEOWNERS", false, true},
+		{"/projects/myapp/.github/workflows/notes.md", false, true},
+		{"/projects/myapp/.git", true, true},
+		{"/projects/myapp/src/main.go", false, false},
+	}
+	for _, tt := range tests {
+		if got := cfg.SkipsHidden(tt.path, tt.isDir); got != tt.want {
+			t.Errorf("SkipsHidden(%q, %v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+		}
+	}
+
+	cfg.SkipHidden = false
+	if cfg.SkipsHidden("/projects/myapp/.env", false) {
+		t.Error("expected hidden files to be kept when SkipHidden is false")
+	}
+}
+
 func TestFileState_CRUD(t *testing.T) {
 	reg := newTestRegistry(t)
 	defer reg.Close()
@@ -840,7 +938,7 @@ func TestUpdateEmbeddingsState(t *testing.T) {
 	}
 
 	// Update embeddings state as success
-	err = reg.UpdateEmbeddingsState(ctx, testPath, nil)
+	err = reg.UpdateEmbeddingsState(ctx, testPath, "", nil)
 	if err != nil {
 		t.Fatalf("failed to update embeddings state: %v", err)
 	}
@@ -873,7 +971,7 @@ func TestClearAnalysisState(t *testing.T) {
 		t.Fatalf("failed to setup semantic state: %v", err)
 	}
 
-	err = reg.UpdateEmbeddingsState(ctx, testPath, nil)
+	err = reg.UpdateEmbeddingsState(ctx, testPath, "", nil)
 	if err != nil {
 		t.Fatalf("failed to setup embeddings state: %v", err)
 	}
@@ -933,7 +1031,7 @@ func TestListFilesNeedingAnalysis(t *testing.T) {
 	// Fully analyzed file
 	reg.UpdateMetadataState(ctx, "/test/complete.go", "hash4", "meta4", 400, modTime)
 	reg.UpdateSemanticState(ctx, "/test/complete.go", "1.0.0", nil)
-	reg.UpdateEmbeddingsState(ctx, "/test/complete.go", nil)
+	reg.UpdateEmbeddingsState(ctx, "/test/complete.go", "", nil)
 
 	// Test ListFilesNeedingMetadata
 	needsMetadata, err := reg.ListFilesNeedingMetadata(ctx, "/test")
@@ -963,6 +1061,60 @@ func TestListFilesNeedingAnalysis(t *testing.T) {
 	}
 }
 
+func TestListFilesWithStaleEmbeddings(t *testing.T) {
+	reg := newTestRegistry(t)
+	defer reg.Close()
+
+	ctx := context.Background()
+	modTime := time.Now().Truncate(time.Second)
+
+	// Embedded with model A
+	reg.UpdateMetadataState(ctx, "/test/embedded.go", "hash1", "meta1", 100, modTime)
+	reg.UpdateSemanticState(ctx, "/test/embedded.go", "1.0.0", nil)
+	if err := reg.UpdateEmbeddingsState(ctx, "/test/embedded.go", "model-a", nil); err != nil {
+		t.Fatalf("UpdateEmbeddingsState failed: %v", err)
+	}
+
+	// Completed without producing embeddings, so no model is recorded
+	reg.UpdateMetadataState(ctx, "/test/metadata-only.bin", "hash2", "meta2", 200, modTime)
+	reg.UpdateEmbeddingsState(ctx, "/test/metadata-only.bin", "", nil)
+
+	state, err := reg.GetFileState(ctx, "/test/embedded.go")
+	if err != nil {
+		t.Fatalf("GetFileState failed: %v", err)
+	}
+	if state.EmbeddingsModel != "model-a" {
+		t.Errorf("expected embeddings model %q, got %q", "model-a", state.EmbeddingsModel)
+	}
+
+	stale, err := reg.ListFilesWithStaleEmbeddings(ctx, "/test", "model-b")
+	if err != nil {
+		t.Fatalf("ListFilesWithStaleEmbeddings failed: %v", err)
+	}
+	if len(stale) != 1 || stale[0].Path != "/test/embedded.go" {
+		t.Errorf("expected only /test/embedded.go to be stale for model-b, got %v", stale)
+	}
+
+	stale, err = reg.ListFilesWithStaleEmbeddings(ctx, "/test", "model-a")
+	if err != nil {
+		t.Fatalf("ListFilesWithStaleEmbeddings failed: %v", err)
+	}
+	if len(stale) != 0 {
+		t.Errorf("expected no stale files for model-a, got %d", len(stale))
+	}
+
+	// Content changes clear the recorded model along with the rest of the state
+	if err := reg.ClearAnalysisState(ctx, "/test/embedded.go"); err != nil {
+		t.Fatalf("ClearAnalysisState failed: %v", err)
+	}
+	stale, err = reg.ListFilesWithStaleEmbeddings(ctx, "/test", "model-b")
+	if err != nil {
+		t.Fatalf("ListFilesWithStaleEmbeddings failed: %v", err)
+	}
+	if len(stale) != 0 {
+		t.Errorf("expected cleared file not to be stale, got %d", len(stale))
+	}
+}
 func TestListFilesNeedingSemantic_RespectsMaxRetries(t *testing.T) {
 	reg := newTestRegistry(t)
 	defer reg.Close()
@@ -1311,6 +1463,7 @@ func TestPathConfig_Clone_ReturnsDeepCopy(t *testing.T) {
 		IncludeExtensions:  []string{".env"},
 		IncludeDirectories: []string{".github"},
 		IncludeFiles:       []string{".gitignore"},
+		IncludeHiddenGlobs: []string{".github/workflows/*.yml"},
 		UseVision:          boolPtr(true),
 	}
 
@@ -1328,6 +1481,9 @@ func TestPathConfig_Clone_ReturnsDeepCopy(t *testing.T) {
 	if len(clone.SkipExtensions) != len(original.SkipExtensions) {
 		t.Errorf("SkipExtensions length = %d, want %d", len(clone.SkipExtensions), len(original.SkipExtensions))
 	}
+	if len(clone.IncludeHiddenGlobs) != len(original.IncludeHiddenGlobs) {
+		t.Errorf("IncludeHiddenGlobs length = %d, want %d", len(clone.IncludeHiddenGlobs), len(original.IncludeHiddenGlobs))
+	}
 	if *clone.UseVision != *original.UseVision {
 		t.Errorf("UseVision = %v, want %v", *clone.UseVision, *original.UseVision)
 	}
@@ -1427,6 +1583,129 @@ func TestPathConfig_Clone_EmptySlices(t *testing.T) {
 
 // Helper functions
 
+func TestExportImport_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	src := newTestRegistry(t)
+	defer src.Close()
+
+	// Populate paths with and without configs, and file states at every stage
+	if err := src.AddPath(ctx, "/projects/app", &PathConfig{
+		SkipExtensions:  []string{".log"},
+		SkipDirectories: []string{"node_modules"},
+		SkipHidden:      true,
+		UseVision:       boolPtr(false),
+	}); err != nil {
+		t.Fatalf("failed to add path: %v", err)
+	}
+	if err := src.AddPath(ctx, "/projects/docs", nil); err != nil {
+		t.Fatalf("failed to add path: %v", err)
+	}
+	if err := src.UpdatePathLastWalk(ctx, "/projects/app", time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("failed to update last walk: %v", err)
+	}
+
+	modTime := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+	for _, path := range []string{"/projects/app/main.go", "/projects/app/util.go", "/projects/docs/guide.md"} {
+		if err := src.UpdateMetadataState(ctx, path, "content-"+path, "meta-"+path, 512, modTime); err != nil {
+			t.Fatalf("failed to update metadata state: %v", err)
+		}
+	}
+	if err := src.UpdateSemanticState(ctx, "/projects/app/main.go", "3", nil); err != nil {
+		t.Fatalf("failed to update semantic state: %v", err)
+	}
+	if err := src.UpdateEmbeddingsState(ctx, "/projects/app/main.go", "text-embedding-3-small", nil); err != nil {
+		t.Fatalf("failed to update embeddings state: %v", err)
+	}
+	if err := src.UpdateChunkerState(ctx, "/projects/app/main.go", "2"); err != nil {
+		t.Fatalf("failed to update chunker state: %v", err)
+	}
+	if err := src.UpdateSemanticState(ctx, "/projects/app/util.go", "3", errors.New("rate limited")); err != nil {
+		t.Fatalf("failed to update semantic state: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := src.Export(ctx, &buf); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	dst := newTestRegistry(t)
+	defer dst.Close()
+
+	// Importing twice must leave the same result
+	for range 2 {
+		if err := dst.Import(ctx, bytes.NewReader(buf.Bytes())); err != nil {
+			t.Fatalf("Import failed: %v", err)
+		}
+	}
+
+	wantPaths, err := src.ListPaths(ctx)
+	if err != nil {
+		t.Fatalf("failed to list source paths: %v", err)
+	}
+	gotPaths, err := dst.ListPaths(ctx)
+	if err != nil {
+		t.Fatalf("failed to list imported paths: %v", err)
+	}
+	if want, got := normalizePaths(wantPaths), normalizePaths(gotPaths); !reflect.DeepEqual(got, want) {
+		t.Errorf("imported paths differ:\ngot  %+v\nwant %+v", got, want)
+	}
+
+	wantStates, err := src.ListFileStates(ctx, "/projects")
+	if err != nil {
+		t.Fatalf("failed to list source file states: %v", err)
+	}
+	gotStates, err := dst.ListFileStates(ctx, "/projects")
+	if err != nil {
+		t.Fatalf("failed to list imported file states: %v", err)
+	}
+	if len(wantStates) != 3 {
+		t.Fatalf("expected 3 source file states, got %d", len(wantStates))
+	}
+	if want, got := normalizeFileStates(wantStates), normalizeFileStates(gotStates); !reflect.DeepEqual(got, want) {
+		t.Errorf("imported file states differ:\ngot  %+v\nwant %+v", got, want)
+	}
+}
+
+// normalizePaths clears row IDs and converts times to UTC for comparison
+// across databases.
+func normalizePaths(paths []RememberedPath) []RememberedPath {
+	out := make([]RememberedPath, len(paths))
+	for i, p := range paths {
+		p.ID = 0
+		p.LastWalkAt = utcPtr(p.LastWalkAt)
+		p.CreatedAt = p.CreatedAt.UTC()
+		p.UpdatedAt = p.UpdatedAt.UTC()
+		out[i] = p
+	}
+	return out
+}
+
+// normalizeFileStates clears row IDs and converts times to UTC for comparison
+// across databases.
+func normalizeFileStates(states []FileState) []FileState {
+	out := make([]FileState, len(states))
+	for i, st := range states {
+		st.ID = 0
+		st.ModTime = st.ModTime.UTC()
+		st.LastAnalyzedAt = utcPtr(st.LastAnalyzedAt)
+		st.MetadataAnalyzedAt = utcPtr(st.MetadataAnalyzedAt)
+		st.SemanticAnalyzedAt = utcPtr(st.SemanticAnalyzedAt)
+		st.EmbeddingsAnalyzedAt = utcPtr(st.EmbeddingsAnalyzedAt)
+		st.CreatedAt = st.CreatedAt.UTC()
+		st.UpdatedAt = st.UpdatedAt.UTC()
+		out[i] = st
+	}
+	return out
+}
+
+func utcPtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
+
 func newTestRegistry(t *testing.T) *SQLiteRegistry {
 	t.Helper()
 	ctx := context.Background()
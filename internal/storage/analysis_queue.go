@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// EnqueueWorkItem records a pending analysis work item and sets its ID and
+// EnqueuedAt. A path has at most one pending item: enqueueing a path that is
+// already pending updates that item in place and keeps its ID.
+func (s *Storage) EnqueueWorkItem(ctx context.Context, item *PendingWorkItem) error {
+	enqueuedAt := time.Now().UTC()
+
+	var id int64
+	err := s.db.QueryRowContext(ctx,
+		`INSERT INTO analysis_queue (file_path, file_size, mod_time, event_type, enqueued_at)
+		 VALUES (?, ?, ?, ?, ?)
+		 ON CONFLICT(file_path) DO UPDATE SET
+		   file_size = excluded.file_size,
+		   mod_time = excluded.mod_time,
+		   event_type = excluded.event_type,
+		   enqueued_at = excluded.enqueued_at
+		 RETURNING id`,
+		item.FilePath, item.FileSize, item.ModTime, item.EventType, enqueuedAt,
+	).Scan(&id)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue work item; %w", err)
+	}
+
+	item.ID = id
+	item.EnqueuedAt = enqueuedAt
+	return nil
+}
+
+// CompleteWorkItem removes a pending analysis work item once it has been
+// processed. The item is kept if its path was enqueued again after enqueuedAt,
+// since the newer event has not been processed yet. Completing an unknown item
+// is not an error.
+func (s *Storage) CompleteWorkItem(ctx context.Context, id int64, enqueuedAt time.Time) error {
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM analysis_queue WHERE id = ? AND enqueued_at = ?", id, enqueuedAt); err != nil {
+		return fmt.Errorf("failed to complete work item; %w", err)
+	}
+	return nil
+}
+
+// ListPendingWorkItems returns the pending analysis work items in the order
+// they were enqueued.
+func (s *Storage) ListPendingWorkItems(ctx context.Context) ([]PendingWorkItem, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, file_path, file_size, mod_time, event_type, enqueued_at
+		 FROM analysis_queue
+		 ORDER BY id ASC`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending work items; %w", err)
+	}
+	defer rows.Close()
+
+	var items []PendingWorkItem
+	for rows.Next() {
+		var item PendingWorkItem
+		if err := rows.Scan(&item.ID, &item.FilePath, &item.FileSize, &item.ModTime, &item.EventType, &item.EnqueuedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan work item; %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate work items; %w", err)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RecordDeadLetter records a permanently failed analysis work item and sets
+// its FailedAt. A path that is already dead-lettered is replaced.
+func (s *Storage) RecordDeadLetter(ctx context.Context, dl *DeadLetter) error {
+	failedAt := time.Now().UTC()
+
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO analysis_dead_letters (file_path, file_size, mod_time, event_type, retries, last_error, failed_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(file_path) DO UPDATE SET
+		   file_size = excluded.file_size,
+		   mod_time = excluded.mod_time,
+		   event_type = excluded.event_type,
+		   retries = excluded.retries,
+		   last_error = excluded.last_error,
+		   failed_at = excluded.failed_at`,
+		dl.FilePath, dl.FileSize, dl.ModTime, dl.EventType, dl.Retries, dl.LastError, failedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record dead letter; %w", err)
+	}
+
+	dl.FailedAt = failedAt
+	return nil
+}
+
+// GetDeadLetter returns the dead letter for a path. Returns ErrPathNotFound
+// if the path is not dead-lettered.
+func (s *Storage) GetDeadLetter(ctx context.Context, filePath string) (*DeadLetter, error) {
+	row := s.db.QueryRowContext(ctx,
+		`SELECT file_path, file_size, mod_time, event_type, retries, last_error, failed_at
+		 FROM analysis_dead_letters
+		 WHERE file_path = ?`,
+		filePath,
+	)
+
+	var dl DeadLetter
+	if err := row.Scan(&dl.FilePath, &dl.FileSize, &dl.ModTime, &dl.EventType, &dl.Retries, &dl.LastError, &dl.FailedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrPathNotFound
+		}
+		return nil, fmt.Errorf("failed to get dead letter; %w", err)
+	}
+
+	return &dl, nil
+}
+
+// ListDeadLetters returns all dead letters, most recently failed first.
+func (s *Storage) ListDeadLetters(ctx context.Context) ([]DeadLetter, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT file_path, file_size, mod_time, event_type, retries, last_error, failed_at
+		 FROM analysis_dead_letters
+		 ORDER BY failed_at DESC, file_path ASC`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dead letters; %w", err)
+	}
+	defer rows.Close()
+
+	var dls []DeadLetter
+	for rows.Next() {
+		var dl DeadLetter
+		if err := rows.Scan(&dl.FilePath, &dl.FileSize, &dl.ModTime, &dl.EventType, &dl.Retries, &dl.LastError, &dl.FailedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan dead letter; %w", err)
+		}
+		dls = append(dls, dl)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate dead letters; %w", err)
+	}
+
+	return dls, nil
+}
+
+// DeleteDeadLetter removes the dead letter for a path. Deleting a path that is
+// not dead-lettered is not an error.
+func (s *Storage) DeleteDeadLetter(ctx context.Context, filePath string) error {
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM analysis_dead_letters WHERE file_path = ?", filePath); err != nil {
+		return fmt.Errorf("failed to delete dead letter; %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDeadLetters_RecordGetDelete(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := context.Background()
+	modTime := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	dl := &DeadLetter{
+		FilePath:  "/test/file.go",
+		FileSize:  128,
+		ModTime:   modTime,
+		EventType: 1,
+		Retries:   3,
+		LastError: "failed to stat file",
+	}
+	if err := s.RecordDeadLetter(ctx, dl); err != nil {
+		t.Fatalf("failed to record dead letter: %v", err)
+	}
+	if dl.FailedAt.IsZero() {
+		t.Error("expected FailedAt to be set")
+	}
+
+	got, err := s.GetDeadLetter(ctx, "/test/file.go")
+	if err != nil {
+		t.Fatalf("failed to get dead letter: %v", err)
+	}
+	if got.FileSize != 128 || !got.ModTime.Equal(modTime) || got.EventType != 1 || got.Retries != 3 || got.LastError != "failed to stat file" {
+		t.Errorf("unexpected dead letter %+v", got)
+	}
+
+	if err := s.DeleteDeadLetter(ctx, "/test/file.go"); err != nil {
+		t.Fatalf("failed to delete dead letter: %v", err)
+	}
+	if _, err := s.GetDeadLetter(ctx, "/test/file.go"); !errors.Is(err, ErrPathNotFound) {
+		t.Errorf("expected ErrPathNotFound after delete, got %v", err)
+	}
+
+	// Deleting again is not an error
+	if err := s.DeleteDeadLetter(ctx, "/test/file.go"); err != nil {
+		t.Errorf("expected delete of unknown path to succeed, got %v", err)
+	}
+}
+
+func TestDeadLetters_RecordReplacesPath(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := context.Background()
+
+	for _, lastError := range []string{"first failure", "second failure"} {
+		if err := s.RecordDeadLetter(ctx, &DeadLetter{FilePath: "/test/file.go", LastError: lastError}); err != nil {
+			t.Fatalf("failed to record dead letter: %v", err)
+		}
+	}
+	if err := s.RecordDeadLetter(ctx, &DeadLetter{FilePath: "/test/other.go", LastError: "other failure"}); err != nil {
+		t.Fatalf("failed to record dead letter: %v", err)
+	}
+
+	dls, err := s.ListDeadLetters(ctx)
+	if err != nil {
+		t.Fatalf("failed to list dead letters: %v", err)
+	}
+	if len(dls) != 2 {
+		t.Fatalf("expected 2 dead letters, got %d", len(dls))
+	}
+	for _, dl := range dls {
+		if dl.FilePath == "/test/file.go" && dl.LastError != "second failure" {
+			t.Errorf("expected latest failure to replace earlier one, got %q", dl.LastError)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+)
+
+// ExportFormatVersion is the version of the registry export format written by
+// Export. Import rejects exports with a newer version.
+const ExportFormatVersion = 1
+
+// Export record kinds, one JSON object per line.
+const (
+	exportKindHeader    = "header"
+	exportKindPath      = "path"
+	exportKindFileState = "file_state"
+)
+
+// exportRecord is one line of a registry export. Kind selects which of the
+// remaining fields is set.
+type exportRecord struct {
+	Kind string `json:"kind"`
+
+	// Header
+	Version    int        `json:"version,omitempty"`
+	ExportedAt *time.Time `json:"exported_at,omitempty"`
+
+	Path      *exportPath      `json:"path,omitempty"`
+	FileState *exportFileState `json:"file_state,omitempty"`
+}
+
+// exportPath is the exported form of a RememberedPath.
+type exportPath struct {
+	Path       string      `json:"path"`
+	Config     *PathConfig `json:"config,omitempty"`
+	LastWalkAt *time.Time  `json:"last_walk_at,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+	UpdatedAt  time.Time   `json:"updated_at"`
+}
+
+// exportFileState is the exported form of a FileState.
+type exportFileState struct {
+	Path                 string     `json:"path"`
+	ContentHash          string     `json:"content_hash"`
+	MetadataHash         string     `json:"metadata_hash"`
+	Size                 int64      `json:"size"`
+	ModTime              time.Time  `json:"mod_time"`
+	LastAnalyzedAt       *time.Time `json:"last_analyzed_at,omitempty"`
+	AnalysisVersion      string     `json:"analysis_version,omitempty"`
+	MetadataAnalyzedAt   *time.Time `json:"metadata_analyzed_at,omitempty"`
+	SemanticAnalyzedAt   *time.Time `json:"semantic_analyzed_at,omitempty"`
+	SemanticError        *string    `json:"semantic_error,omitempty"`
+	SemanticRetryCount   int        `json:"semantic_retry_count,omitempty"`
+	EmbeddingsAnalyzedAt *time.Time `json:"embeddings_analyzed_at,omitempty"`
+	EmbeddingsError      *string    `json:"embeddings_error,omitempty"`
+	EmbeddingsRetryCount int        `json:"embeddings_retry_count,omitempty"`
+	EmbeddingsModel      string     `json:"embeddings_model,omitempty"`
+	ChunkerVersion       string     `json:"chunker_version,omitempty"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+}
+
+// Export writes all remembered paths and file states to w as newline-delimited
+// JSON: a header line followed by one line per path and per file state, each
+// ordered by path. Row IDs are not exported.
+func (s *Storage) Export(ctx context.Context, w io.Writer) error {
+	paths, err := s.ListPaths(ctx)
+	if err != nil {
+		return err
+	}
+
+	states, err := s.listAllFileStates(ctx)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+
+	now := time.Now().UTC()
+	if err := enc.Encode(exportRecord{Kind: exportKindHeader, Version: ExportFormatVersion, ExportedAt: &now}); err != nil {
+		return fmt.Errorf("failed to write export header; %w", err)
+	}
+
+	for _, p := range paths {
+		rec := exportRecord{Kind: exportKindPath, Path: &exportPath{
+			Path:       p.Path,
+			Config:     p.Config,
+			LastWalkAt: p.LastWalkAt,
+			CreatedAt:  p.CreatedAt,
+			UpdatedAt:  p.UpdatedAt,
+		}}
+		if err := enc.Encode(rec); err != nil {
+			return fmt.Errorf("failed to write path %s; %w", p.Path, err)
+		}
+	}
+
+	for _, st := range states {
+		rec := exportRecord{Kind: exportKindFileState, FileState: &exportFileState{
+			Path:                 st.Path,
+			ContentHash:          st.ContentHash,
+			MetadataHash:         st.MetadataHash,
+			Size:                 st.Size,
+			ModTime:              st.ModTime,
+			LastAnalyzedAt:       st.LastAnalyzedAt,
+			AnalysisVersion:      st.AnalysisVersion,
+			MetadataAnalyzedAt:   st.MetadataAnalyzedAt,
+			SemanticAnalyzedAt:   st.SemanticAnalyzedAt,
+			SemanticError:        st.SemanticError,
+			SemanticRetryCount:   st.SemanticRetryCount,
+			EmbeddingsAnalyzedAt: st.EmbeddingsAnalyzedAt,
+			EmbeddingsError:      st.EmbeddingsError,
+			EmbeddingsRetryCount: st.EmbeddingsRetryCount,
+			EmbeddingsModel:      st.EmbeddingsModel,
+			ChunkerVersion:       st.ChunkerVersion,
+			CreatedAt:            st.CreatedAt,
+			UpdatedAt:            st.UpdatedAt,
+		}}
+		if err := enc.Encode(rec); err != nil {
+			return fmt.Errorf("failed to write file state %s; %w", st.Path, err)
+		}
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write export; %w", err)
+	}
+	return nil
+}
+
+// Import loads an export written by Export. Paths and file states are upserted
+// by path, so importing the same export twice leaves the database unchanged,
+// and records absent from the export are kept. The import is applied in a
+// single transaction; on error nothing is imported.
+func (s *Storage) Import(ctx context.Context, r io.Reader) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin import; %w", err)
+	}
+	defer tx.Rollback()
+
+	dec := json.NewDecoder(r)
+	line := 0
+	for {
+		var rec exportRecord
+		if err := dec.Decode(&rec); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return fmt.Errorf("failed to read export record %d; %w", line+1, err)
+		}
+		line++
+
+		if line == 1 {
+			if rec.Kind != exportKindHeader {
+				return fmt.Errorf("export is missing its header")
+			}
+			if rec.Version > ExportFormatVersion {
+				return fmt.Errorf("export format version %d is newer than supported version %d", rec.Version, ExportFormatVersion)
+			}
+			continue
+		}
+
+		switch {
+		case rec.Kind == exportKindPath && rec.Path != nil:
+			err = importPath(ctx, tx, rec.Path)
+		case rec.Kind == exportKindFileState && rec.FileState != nil:
+			err = importFileState(ctx, tx, rec.FileState)
+		default:
+			err = fmt.Errorf("unknown record kind %q", rec.Kind)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to import record %d; %w", line, err)
+		}
+	}
+
+	if line == 0 {
+		return fmt.Errorf("export is missing its header")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit import; %w", err)
+	}
+	return nil
+}
+
+// importPath upserts a remembered path, keeping its exported timestamps.
+func importPath(ctx context.Context, tx *sql.Tx, p *exportPath) error {
+	var configJSON *string
+	if p.Config != nil {
+		data, err := json.Marshal(p.Config)
+		if err != nil {
+			return fmt.Errorf("failed to marshal config; %w", err)
+		}
+		str := string(data)
+		configJSON = &str
+	}
+
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO remembered_paths (path, config_json, last_walk_at, created_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?)
+		 ON CONFLICT(path) DO UPDATE SET
+		   config_json = excluded.config_json,
+		   last_walk_at = excluded.last_walk_at,
+		   created_at = excluded.created_at,
+		   updated_at = excluded.updated_at`,
+		filepath.Clean(p.Path), configJSON, p.LastWalkAt, p.CreatedAt, p.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to import path %s; %w", p.Path, err)
+	}
+	return nil
+}
+
+// importFileState upserts a file state, keeping its exported timestamps.
+func importFileState(ctx context.Context, tx *sql.Tx, st *exportFileState) error {
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO file_state (path, content_hash, metadata_hash, size, mod_time,
+		                         last_analyzed_at, analysis_version,
+		                         metadata_analyzed_at, semantic_analyzed_at, semantic_error, semantic_retry_count,
+		                         embeddings_analyzed_at, embeddings_error, embeddings_retry_count, embeddings_model,
+		                         chunker_version,
+		                         created_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), ?, ?)
+		 ON CONFLICT(path) DO UPDATE SET
+		   content_hash = excluded.content_hash,
+		   metadata_hash = excluded.metadata_hash,
+		   size = excluded.size,
+		   mod_time = excluded.mod_time,
+		   last_analyzed_at = excluded.last_analyzed_at,
+		   analysis_version = excluded.analysis_version,
+		   metadata_analyzed_at = excluded.metadata_analyzed_at,
+		   semantic_analyzed_at = excluded.semantic_analyzed_at,
+		   semantic_error = excluded.semantic_error,
+		   semantic_retry_count = excluded.semantic_retry_count,
+		   embeddings_analyzed_at = excluded.embeddings_analyzed_at,
+		   embeddings_error = excluded.embeddings_error,
+		   embeddings_retry_count = excluded.embeddings_retry_count,
+		   embeddings_model = excluded.embeddings_model,
+		   chunker_version = excluded.chunker_version,
+		   created_at = excluded.created_at,
+		   updated_at = excluded.updated_at`,
+		filepath.Clean(st.Path), st.ContentHash, st.MetadataHash, st.Size, st.ModTime,
+		st.LastAnalyzedAt, st.AnalysisVersion,
+		st.MetadataAnalyzedAt, st.SemanticAnalyzedAt, st.SemanticError, st.SemanticRetryCount,
+		st.EmbeddingsAnalyzedAt, st.EmbeddingsError, st.EmbeddingsRetryCount, st.EmbeddingsModel,
+		st.ChunkerVersion,
+		st.CreatedAt, st.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to import file state %s; %w", st.Path, err)
+	}
+	return nil
+}
+
+// listAllFileStates returns every file state ordered by path.
+func (s *Storage) listAllFileStates(ctx context.Context) ([]FileState, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, path, content_hash, metadata_hash, size, mod_time,
+		        last_analyzed_at, analysis_version,
+		        metadata_analyzed_at, semantic_analyzed_at, semantic_error, semantic_retry_count,
+		        embeddings_analyzed_at, embeddings_error, embeddings_retry_count, embeddings_model,
+		        chunker_version,
+		        created_at, updated_at
+		 FROM file_state
+		 ORDER BY path`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list file states; %w", err)
+	}
+	defer rows.Close()
+
+	var states []FileState
+	for rows.Next() {
+		st, err := scanFileStateRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		states = append(states, *st)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating file states; %w", err)
+	}
+
+	return states, nil
+}
@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExport_Format(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := context.Background()
+
+	if err := s.AddPath(ctx, "/b", nil); err != nil {
+		t.Fatalf("AddPath failed: %v", err)
+	}
+	if err := s.AddPath(ctx, "/a", nil); err != nil {
+		t.Fatalf("AddPath failed: %v", err)
+	}
+	if err := s.UpdateFileState(ctx, &FileState{Path: "/a/x.go", ContentHash: "h", ModTime: time.Now()}); err != nil {
+		t.Fatalf("UpdateFileState failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := s.Export(ctx, &buf); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	var kinds, paths []string
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var rec exportRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatalf("line %q is not JSON: %v", scanner.Text(), err)
+		}
+		kinds = append(kinds, rec.Kind)
+		if rec.Path != nil {
+			paths = append(paths, rec.Path.Path)
+		}
+		if rec.Kind == exportKindHeader && rec.Version != ExportFormatVersion {
+			t.Errorf("header version = %d, want %d", rec.Version, ExportFormatVersion)
+		}
+	}
+
+	if got := strings.Join(kinds, ","); got != "header,path,path,file_state" {
+		t.Errorf("record kinds = %s", got)
+	}
+	if got := strings.Join(paths, ","); got != "/a,/b" {
+		t.Errorf("paths = %s, want ordered by path", got)
+	}
+}
+
+func TestImport_UpsertsExistingRecords(t *testing.T) {
+	ctx := context.Background()
+	src := newTestStorage(t)
+	if err := src.AddPath(ctx, "/a", &PathConfig{SkipHidden: true}); err != nil {
+		t.Fatalf("AddPath failed: %v", err)
+	}
+	if err := src.UpdateFileState(ctx, &FileState{Path: "/a/x.go", ContentHash: "new", ModTime: time.Now()}); err != nil {
+		t.Fatalf("UpdateFileState failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := src.Export(ctx, &buf); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	dst := newTestStorage(t)
+	if err := dst.AddPath(ctx, "/a", nil); err != nil {
+		t.Fatalf("AddPath failed: %v", err)
+	}
+	if err := dst.UpdateFileState(ctx, &FileState{Path: "/a/x.go", ContentHash: "old", ModTime: time.Now()}); err != nil {
+		t.Fatalf("UpdateFileState failed: %v", err)
+	}
+	if err := dst.UpdateFileState(ctx, &FileState{Path: "/a/y.go", ContentHash: "kept", ModTime: time.Now()}); err != nil {
+		t.Fatalf("UpdateFileState failed: %v", err)
+	}
+
+	if err := dst.Import(ctx, &buf); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+
+	p, err := dst.GetPath(ctx, "/a")
+	if err != nil {
+		t.Fatalf("GetPath failed: %v", err)
+	}
+	if p.Config == nil || !p.Config.SkipHidden {
+		t.Errorf("path config = %+v, want imported config", p.Config)
+	}
+
+	st, err := dst.GetFileState(ctx, "/a/x.go")
+	if err != nil {
+		t.Fatalf("GetFileState failed: %v", err)
+	}
+	if st.ContentHash != "new" {
+		t.Errorf("content hash = %q, want imported %q", st.ContentHash, "new")
+	}
+
+	// Records absent from the export are left alone
+	if _, err := dst.GetFileState(ctx, "/a/y.go"); err != nil {
+		t.Errorf("existing file state was removed: %v", err)
+	}
+}
+
+func TestImport_Errors(t *testing.T) {
+	header := `{"kind":"header","version":1}`
+	path := `{"kind":"path","path":{"path":"/a","created_at":"2025-01-01T00:00:00Z","updated_at":"2025-01-01T00:00:00Z"}}`
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"empty", ""},
+		{"missing header", path},
+		{"newer version", `{"kind":"header","version":99}`},
+		{"unknown kind", header + "\n" + path + "\n" + `{"kind":"chunk"}`},
+		{"malformed line", header + "\n" + path + "\n" + `{"kind":`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStorage(t)
+			ctx := context.Background()
+
+			if err := s.Import(ctx, strings.NewReader(tt.input)); err == nil {
+				t.Fatal("expected error")
+			}
+
+			// A failed import applies nothing
+			paths, err := s.ListPaths(ctx)
+			if err != nil {
+				t.Fatalf("ListPaths failed: %v", err)
+			}
+			if len(paths) != 0 {
+				t.Errorf("expected no paths after failed import, got %d", len(paths))
+			}
+		})
+	}
+}
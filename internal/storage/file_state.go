@@ -17,7 +17,8 @@ func (s *Storage) GetFileState(ctx context.Context, path string) (*FileState, er
 		`SELECT id, path, content_hash, metadata_hash, size, mod_time,
 		        last_analyzed_at, analysis_version,
 		        metadata_analyzed_at, semantic_analyzed_at, semantic_error, semantic_retry_count,
-		        embeddings_analyzed_at, embeddings_error, embeddings_retry_count,
+		        embeddings_analyzed_at, embeddings_error, embeddings_retry_count, embeddings_model,
+		        chunker_version,
 		        created_at, updated_at
 		 FROM file_state WHERE path = ?`,
 		path,
@@ -34,9 +35,10 @@ func (s *Storage) UpdateFileState(ctx context.Context, state *FileState) error {
 		`INSERT INTO file_state (path, content_hash, metadata_hash, size, mod_time,
 		                         last_analyzed_at, analysis_version,
 		                         metadata_analyzed_at, semantic_analyzed_at, semantic_error, semantic_retry_count,
-		                         embeddings_analyzed_at, embeddings_error, embeddings_retry_count,
+		                         embeddings_analyzed_at, embeddings_error, embeddings_retry_count, embeddings_model,
+		                         chunker_version,
 		                         created_at, updated_at)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
 		 ON CONFLICT(path) DO UPDATE SET
 		   content_hash = excluded.content_hash,
 		   metadata_hash = excluded.metadata_hash,
@@ -51,11 +53,14 @@ func (s *Storage) UpdateFileState(ctx context.Context, state *FileState) error {
 		   embeddings_analyzed_at = excluded.embeddings_analyzed_at,
 		   embeddings_error = excluded.embeddings_error,
 		   embeddings_retry_count = excluded.embeddings_retry_count,
+		   embeddings_model = excluded.embeddings_model,
+		   chunker_version = excluded.chunker_version,
 		   updated_at = CURRENT_TIMESTAMP`,
 		state.Path, state.ContentHash, state.MetadataHash, state.Size, state.ModTime,
 		state.LastAnalyzedAt, state.AnalysisVersion,
 		state.MetadataAnalyzedAt, state.SemanticAnalyzedAt, state.SemanticError, state.SemanticRetryCount,
-		state.EmbeddingsAnalyzedAt, state.EmbeddingsError, state.EmbeddingsRetryCount,
+		state.EmbeddingsAnalyzedAt, state.EmbeddingsError, state.EmbeddingsRetryCount, state.EmbeddingsModel,
+		state.ChunkerVersion,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update file state; %w", err)
@@ -96,7 +101,8 @@ func (s *Storage) ListFileStates(ctx context.Context, parentPath string) ([]File
 		`SELECT id, path, content_hash, metadata_hash, size, mod_time,
 		        last_analyzed_at, analysis_version,
 		        metadata_analyzed_at, semantic_analyzed_at, semantic_error, semantic_retry_count,
-		        embeddings_analyzed_at, embeddings_error, embeddings_retry_count,
+		        embeddings_analyzed_at, embeddings_error, embeddings_retry_count, embeddings_model,
+		        chunker_version,
 		        created_at, updated_at
 		 FROM file_state
 		 WHERE path LIKE ? OR path = ?
@@ -195,6 +201,50 @@ func (s *Storage) CountEmbeddingsFiles(ctx context.Context, parentPath string) (
 	return count, nil
 }
 
+// CountByAnalysisStatus returns registry-wide file counts for each analysis stage.
+// A file is pending a stage once the previous stage has completed; files whose
+// last attempt failed are counted as errored until they succeed.
+func (s *Storage) CountByAnalysisStatus(ctx context.Context) (AnalysisStatusCounts, error) {
+	var counts AnalysisStatusCounts
+
+	err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*),
+		        COALESCE(SUM(metadata_analyzed_at IS NULL), 0),
+		        COALESCE(SUM(metadata_analyzed_at IS NOT NULL), 0),
+		        COALESCE(SUM(metadata_analyzed_at IS NOT NULL AND semantic_analyzed_at IS NULL AND semantic_error IS NULL), 0),
+		        COALESCE(SUM(semantic_analyzed_at IS NOT NULL), 0),
+		        COALESCE(SUM(semantic_analyzed_at IS NULL AND semantic_error IS NOT NULL), 0),
+		        COALESCE(SUM(semantic_analyzed_at IS NOT NULL AND embeddings_analyzed_at IS NULL AND embeddings_error IS NULL), 0),
+		        COALESCE(SUM(embeddings_analyzed_at IS NOT NULL), 0),
+		        COALESCE(SUM(embeddings_analyzed_at IS NULL AND embeddings_error IS NOT NULL), 0)
+		 FROM file_state`,
+	).Scan(&counts.Total,
+		&counts.Metadata.Pending, &counts.Metadata.Completed,
+		&counts.Semantic.Pending, &counts.Semantic.Completed, &counts.Semantic.Errored,
+		&counts.Embeddings.Pending, &counts.Embeddings.Completed, &counts.Embeddings.Errored)
+	if err != nil {
+		return counts, fmt.Errorf("failed to count file states by analysis status; %w", err)
+	}
+
+	// Selecting the column rather than MIN() keeps its declared type, so the
+	// driver returns a time instead of a string
+	var oldest sql.NullTime
+	err = s.db.QueryRowContext(ctx,
+		`SELECT updated_at FROM file_state
+		 WHERE metadata_analyzed_at IS NULL
+		 ORDER BY updated_at
+		 LIMIT 1`,
+	).Scan(&oldest)
+	if err != nil && err != sql.ErrNoRows {
+		return counts, fmt.Errorf("failed to get oldest pending metadata; %w", err)
+	}
+	if oldest.Valid {
+		counts.OldestPendingMetadataAt = &oldest.Time
+	}
+
+	return counts, nil
+}
+
 // UpdateMetadataState updates the metadata tracking fields for a file.
 // This is called after computing content hash and file metadata.
 func (s *Storage) UpdateMetadataState(ctx context.Context, path string, contentHash string, metadataHash string, size int64, modTime time.Time) error {
@@ -267,8 +317,9 @@ func (s *Storage) UpdateSemanticState(ctx context.Context, path string, analysis
 }
 
 // UpdateEmbeddingsState updates the embeddings generation tracking fields for a file.
-// Pass nil for err if generation succeeded, otherwise pass the error.
-func (s *Storage) UpdateEmbeddingsState(ctx context.Context, path string, embeddingsErr error) error {
+// Pass nil for err if generation succeeded, otherwise pass the error. On success
+// model records the embedding model used; pass "" when no embeddings were produced.
+func (s *Storage) UpdateEmbeddingsState(ctx context.Context, path string, model string, embeddingsErr error) error {
 	path = filepath.Clean(path)
 	now := time.Now()
 
@@ -285,9 +336,10 @@ func (s *Storage) UpdateEmbeddingsState(ctx context.Context, path string, embedd
 			   embeddings_analyzed_at = ?,
 			   embeddings_error = NULL,
 			   embeddings_retry_count = 0,
+			   embeddings_model = NULLIF(?, ''),
 			   updated_at = CURRENT_TIMESTAMP
 			 WHERE path = ?`,
-			now, path,
+			now, model, path,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to update embeddings state; %w", err)
@@ -310,6 +362,24 @@ func (s *Storage) UpdateEmbeddingsState(ctx context.Context, path string, embedd
 	return nil
 }
 
+// UpdateChunkerState records the chunker version that produced a file's current chunks.
+func (s *Storage) UpdateChunkerState(ctx context.Context, path string, chunkerVersion string) error {
+	path = filepath.Clean(path)
+
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE file_state SET
+		   chunker_version = NULLIF(?, ''),
+		   updated_at = CURRENT_TIMESTAMP
+		 WHERE path = ?`,
+		chunkerVersion, path,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update chunker state; %w", err)
+	}
+
+	return nil
+}
+
 // ClearAnalysisState clears all analysis state for a file, forcing reanalysis.
 // This is called when a file's content hash changes.
 func (s *Storage) ClearAnalysisState(ctx context.Context, path string) error {
@@ -326,6 +396,8 @@ func (s *Storage) ClearAnalysisState(ctx context.Context, path string) error {
 		   embeddings_analyzed_at = NULL,
 		   embeddings_error = NULL,
 		   embeddings_retry_count = 0,
+		   embeddings_model = NULL,
+		   chunker_version = NULL,
 		   updated_at = CURRENT_TIMESTAMP
 		 WHERE path = ?`,
 		path,
@@ -346,7 +418,8 @@ func (s *Storage) ListFilesNeedingMetadata(ctx context.Context, parentPath strin
 		`SELECT id, path, content_hash, metadata_hash, size, mod_time,
 		        last_analyzed_at, analysis_version,
 		        metadata_analyzed_at, semantic_analyzed_at, semantic_error, semantic_retry_count,
-		        embeddings_analyzed_at, embeddings_error, embeddings_retry_count,
+		        embeddings_analyzed_at, embeddings_error, embeddings_retry_count, embeddings_model,
+		        chunker_version,
 		        created_at, updated_at
 		 FROM file_state
 		 WHERE (path LIKE ? OR path = ?)
@@ -372,7 +445,8 @@ func (s *Storage) ListFilesNeedingSemantic(ctx context.Context, parentPath strin
 		`SELECT id, path, content_hash, metadata_hash, size, mod_time,
 		        last_analyzed_at, analysis_version,
 		        metadata_analyzed_at, semantic_analyzed_at, semantic_error, semantic_retry_count,
-		        embeddings_analyzed_at, embeddings_error, embeddings_retry_count,
+		        embeddings_analyzed_at, embeddings_error, embeddings_retry_count, embeddings_model,
+		        chunker_version,
 		        created_at, updated_at
 		 FROM file_state
 		 WHERE (path LIKE ? OR path = ?)
@@ -400,7 +474,8 @@ func (s *Storage) ListFilesNeedingEmbeddings(ctx context.Context, parentPath str
 		`SELECT id, path, content_hash, metadata_hash, size, mod_time,
 		        last_analyzed_at, analysis_version,
 		        metadata_analyzed_at, semantic_analyzed_at, semantic_error, semantic_retry_count,
-		        embeddings_analyzed_at, embeddings_error, embeddings_retry_count,
+		        embeddings_analyzed_at, embeddings_error, embeddings_retry_count, embeddings_model,
+		        chunker_version,
 		        created_at, updated_at
 		 FROM file_state
 		 WHERE (path LIKE ? OR path = ?)
@@ -418,6 +493,36 @@ func (s *Storage) ListFilesNeedingEmbeddings(ctx context.Context, parentPath str
 	return scanAllFileStates(rows)
 }
 
+// ListFilesWithStaleEmbeddings returns files whose embeddings were generated
+// with a model other than currentModel. Files without a recorded model are
+// not returned.
+func (s *Storage) ListFilesWithStaleEmbeddings(ctx context.Context, parentPath string, currentModel string) ([]FileState, error) {
+	parentPath = filepath.Clean(parentPath)
+	prefix := parentPath + string(filepath.Separator)
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, path, content_hash, metadata_hash, size, mod_time,
+		        last_analyzed_at, analysis_version,
+		        metadata_analyzed_at, semantic_analyzed_at, semantic_error, semantic_retry_count,
+		        embeddings_analyzed_at, embeddings_error, embeddings_retry_count, embeddings_model,
+		        chunker_version,
+		        created_at, updated_at
+		 FROM file_state
+		 WHERE (path LIKE ? OR path = ?)
+		   AND embeddings_analyzed_at IS NOT NULL
+		   AND embeddings_model IS NOT NULL
+		   AND embeddings_model != ?
+		 ORDER BY path`,
+		prefix+"%", parentPath, currentModel,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files with stale embeddings; %w", err)
+	}
+	defer rows.Close()
+
+	return scanAllFileStates(rows)
+}
+
 // scanAllFileStates is a helper that scans all rows into FileState slice.
 func scanAllFileStates(rows *sql.Rows) ([]FileState, error) {
 	var states []FileState
@@ -446,11 +551,14 @@ func scanFileState(row *sql.Row) (*FileState, error) {
 	var semanticError sql.NullString
 	var embeddingsAnalyzedAt sql.NullTime
 	var embeddingsError sql.NullString
+	var embeddingsModel sql.NullString
+	var chunkerVersion sql.NullString
 
 	err := row.Scan(&st.ID, &st.Path, &st.ContentHash, &st.MetadataHash, &st.Size, &st.ModTime,
 		&lastAnalyzedAt, &analysisVersion,
 		&metadataAnalyzedAt, &semanticAnalyzedAt, &semanticError, &st.SemanticRetryCount,
-		&embeddingsAnalyzedAt, &embeddingsError, &st.EmbeddingsRetryCount,
+		&embeddingsAnalyzedAt, &embeddingsError, &st.EmbeddingsRetryCount, &embeddingsModel,
+		&chunkerVersion,
 		&st.CreatedAt, &st.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -480,6 +588,12 @@ func scanFileState(row *sql.Row) (*FileState, error) {
 	if embeddingsError.Valid {
 		st.EmbeddingsError = &embeddingsError.String
 	}
+	if embeddingsModel.Valid {
+		st.EmbeddingsModel = embeddingsModel.String
+	}
+	if chunkerVersion.Valid {
+		st.ChunkerVersion = chunkerVersion.String
+	}
 
 	return &st, nil
 }
@@ -494,11 +608,14 @@ func scanFileStateRows(rows *sql.Rows) (*FileState, error) {
 	var semanticError sql.NullString
 	var embeddingsAnalyzedAt sql.NullTime
 	var embeddingsError sql.NullString
+	var embeddingsModel sql.NullString
+	var chunkerVersion sql.NullString
 
 	err := rows.Scan(&st.ID, &st.Path, &st.ContentHash, &st.MetadataHash, &st.Size, &st.ModTime,
 		&lastAnalyzedAt, &analysisVersion,
 		&metadataAnalyzedAt, &semanticAnalyzedAt, &semanticError, &st.SemanticRetryCount,
-		&embeddingsAnalyzedAt, &embeddingsError, &st.EmbeddingsRetryCount,
+		&embeddingsAnalyzedAt, &embeddingsError, &st.EmbeddingsRetryCount, &embeddingsModel,
+		&chunkerVersion,
 		&st.CreatedAt, &st.UpdatedAt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to scan file state; %w", err)
@@ -525,6 +642,12 @@ func scanFileStateRows(rows *sql.Rows) (*FileState, error) {
 	if embeddingsError.Valid {
 		st.EmbeddingsError = &embeddingsError.String
 	}
+	if embeddingsModel.Valid {
+		st.EmbeddingsModel = embeddingsModel.String
+	}
+	if chunkerVersion.Valid {
+		st.ChunkerVersion = chunkerVersion.String
+	}
 
 	return &st, nil
 }
@@ -2,6 +2,8 @@ package storage
 
 import (
 	"encoding/json"
+	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -49,6 +51,10 @@ type PathConfig struct {
 	// IncludeFiles lists files to include even if in SkipFiles.
 	IncludeFiles []string `json:"include_files,omitempty"`
 
+	// IncludeHiddenGlobs lists globs (e.g. ".github/workflows/*.yml") that
+	// re-include hidden paths even when SkipHidden is true.
+	IncludeHiddenGlobs []string `json:"include_hidden_globs,omitempty"`
+
 	// UseVision indicates whether to use vision API for images/PDFs.
 	// nil means use global default.
 	UseVision *bool `json:"use_vision,omitempty"`
@@ -101,6 +107,10 @@ func (c *PathConfig) Clone() *PathConfig {
 		clone.IncludeFiles = make([]string, len(c.IncludeFiles))
 		copy(clone.IncludeFiles, c.IncludeFiles)
 	}
+	if c.IncludeHiddenGlobs != nil {
+		clone.IncludeHiddenGlobs = make([]string, len(c.IncludeHiddenGlobs))
+		copy(clone.IncludeHiddenGlobs, c.IncludeHiddenGlobs)
+	}
 
 	// Deep copy pointer
 	if c.UseVision != nil {
@@ -111,6 +121,76 @@ func (c *PathConfig) Clone() *PathConfig {
 	return clone
 }
 
+// SkipsHidden reports whether path is hidden and skipped by SkipHidden.
+//
+// IncludeHiddenGlobs are matched against the trailing components of path, so
+// ".github/workflows/*.yml" matches at any depth. A directory is re-included
+// when it matches a leading part of a glob, letting walkers descend toward
+// matching files. Files inside a hidden directory count as hidden, so only
+// files matching a glob are kept from re-included directories.
+func (c *PathConfig) SkipsHidden(path string, isDir bool) bool {
+	if c == nil || !c.SkipHidden {
+		return false
+	}
+
+	components := strings.Split(filepath.ToSlash(filepath.Clean(path)), "/")
+	if !isHiddenName(components[len(components)-1]) && (isDir || !c.hasHiddenParent(components)) {
+		return false
+	}
+
+	for _, glob := range c.IncludeHiddenGlobs {
+		segments := strings.Split(strings.Trim(filepath.ToSlash(glob), "/"), "/")
+		if isDir {
+			for n := 1; n <= len(segments); n++ {
+				if matchTrailing(segments[:n], components) {
+					return false
+				}
+			}
+		} else if matchTrailing(segments, components) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// hasHiddenParent reports whether any parent directory within reach of the
+// longest hidden include glob is hidden. Parents are only traversed under
+// SkipHidden when a glob re-included them.
+func (c *PathConfig) hasHiddenParent(components []string) bool {
+	depth := 0
+	for _, glob := range c.IncludeHiddenGlobs {
+		depth = max(depth, strings.Count(strings.Trim(filepath.ToSlash(glob), "/"), "/"))
+	}
+
+	parents := components[:len(components)-1]
+	for i := max(len(parents)-depth, 0); i < len(parents); i++ {
+		if isHiddenName(parents[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchTrailing reports whether the glob segments match the last components.
+func matchTrailing(segments, components []string) bool {
+	if len(segments) > len(components) {
+		return false
+	}
+	offset := len(components) - len(segments)
+	for i, segment := range segments {
+		matched, err := filepath.Match(segment, components[offset+i])
+		if err != nil || !matched {
+			return false
+		}
+	}
+	return true
+}
+
+func isHiddenName(name string) bool {
+	return strings.HasPrefix(name, ".") && name != "." && name != ".."
+}
+
 // FileState tracks the state of a file for incremental processing.
 type FileState struct {
 	// ID is the unique identifier for this file state.
@@ -160,6 +240,13 @@ type FileState struct {
 	// EmbeddingsRetryCount is the number of failed embeddings generation attempts.
 	EmbeddingsRetryCount int
 
+	// EmbeddingsModel is the embedding model used for the last successful generation.
+	EmbeddingsModel string
+
+	// ChunkerVersion is the chunker version in effect when the file's results were
+	// last persisted. It is tracked independently of AnalysisVersion.
+	ChunkerVersion string
+
 	// CreatedAt is when this file state was first created.
 	CreatedAt time.Time
 
@@ -193,7 +280,23 @@ type FileDiscovery struct {
 
 // IsStale returns true if the file appears to have changed based on size or mod time.
 func (f *FileState) IsStale(size int64, modTime time.Time) bool {
-	return f.Size != size || !f.ModTime.Equal(modTime)
+	return f.IsStaleWithin(size, modTime, 0)
+}
+
+// IsStaleWithin returns true if the file size differs or the mod time differs by more
+// than tolerance. Used on filesystems with coarse mtime granularity, where the content
+// hash remains the authoritative change check.
+func (f *FileState) IsStaleWithin(size int64, modTime time.Time, tolerance time.Duration) bool {
+	return f.Size != size || !ModTimeWithin(f.ModTime, modTime, tolerance)
+}
+
+// ModTimeWithin returns true if a and b differ by at most tolerance.
+func ModTimeWithin(a, b time.Time, tolerance time.Duration) bool {
+	diff := a.Sub(b)
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= max(tolerance, 0)
 }
 
 // NeedsAnalysis returns true if the file has never been analyzed or was analyzed
@@ -202,6 +305,45 @@ func (f *FileState) NeedsAnalysis(currentVersion string) bool {
 	return f.LastAnalyzedAt == nil || f.AnalysisVersion != currentVersion
 }
 
+// NeedsRechunk returns true if the file was persisted under a different chunker
+// version. Files with no recorded chunker version predate version tracking and
+// are re-chunked.
+func (f *FileState) NeedsRechunk(currentVersion string) bool {
+	return f.ChunkerVersion != currentVersion
+}
+
+// StageStatusCounts contains file counts for a single analysis stage.
+type StageStatusCounts struct {
+	// Pending is the count of files ready for this stage that have not completed it.
+	Pending int
+
+	// Completed is the count of files that have completed this stage.
+	Completed int
+
+	// Errored is the count of files whose last attempt at this stage failed.
+	Errored int
+}
+
+// AnalysisStatusCounts contains registry-wide file counts for each analysis stage.
+type AnalysisStatusCounts struct {
+	// Total is the count of all tracked files.
+	Total int
+
+	// Metadata counts files by metadata state. Metadata failures are not
+	// recorded, so Errored is always zero.
+	Metadata StageStatusCounts
+
+	// Semantic counts files by semantic analysis state.
+	Semantic StageStatusCounts
+
+	// Embeddings counts files by embeddings generation state.
+	Embeddings StageStatusCounts
+
+	// OldestPendingMetadataAt is when the longest-waiting file pending metadata
+	// was last updated, or nil if no files are pending.
+	OldestPendingMetadataAt *time.Time
+}
+
 // PathStatus represents the health status of a remembered path.
 type PathStatus struct {
 	// Path is the remembered path being checked.
@@ -256,6 +398,52 @@ type QueuedResult struct {
 	CompletedAt *time.Time
 }
 
+// PendingWorkItem is an analysis work item recorded so it survives a restart.
+type PendingWorkItem struct {
+	// ID is the database primary key.
+	ID int64
+
+	// FilePath is the path to the file to analyze.
+	FilePath string
+
+	// FileSize is the file size when the item was enqueued.
+	FileSize int64
+
+	// ModTime is the file modification time when the item was enqueued.
+	ModTime time.Time
+
+	// EventType is the analysis queue's work item type.
+	EventType int
+
+	// EnqueuedAt is when the item was recorded.
+	EnqueuedAt time.Time
+}
+
+// DeadLetter is an analysis work item that failed permanently, kept so it can
+// be inspected and retried.
+type DeadLetter struct {
+	// FilePath is the path to the file that failed.
+	FilePath string
+
+	// FileSize is the file size when the item was enqueued.
+	FileSize int64
+
+	// ModTime is the file modification time when the item was enqueued.
+	ModTime time.Time
+
+	// EventType is the analysis queue's work item type.
+	EventType int
+
+	// Retries is the number of retries made before giving up.
+	Retries int
+
+	// LastError is the error from the final attempt.
+	LastError string
+
+	// FailedAt is when the item was given up on.
+	FailedAt time.Time
+}
+
 // QueueStatus represents the status of a queued persistence item.
 type QueueStatus string
 
@@ -1,64 +1,101 @@
 // Package storage provides consolidated SQLite storage for the memorizer daemon.
-// It manages remembered paths, file state, critical events, and the persistence queue
-// in a single database file.
+// It manages remembered paths, file state, critical events, the persistence queue
+// and pending and dead-lettered analysis work in a single database file.
+//
+// The database runs in WAL mode, so -wal and -shm sidecar files appear next to
+// the database file while it is open. They are part of the database and should
+// be copied or removed along with it.
 package storage
 
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
+	"time"
 
 	_ "modernc.org/sqlite"
 )
 
+// DefaultBusyTimeout is how long a statement waits for a lock held by another
+// connection before failing with "database is locked".
+const DefaultBusyTimeout = 5 * time.Second
+
+// ErrSchemaTooNew is returned by Open when the database was migrated by a newer
+// version of the binary than the one opening it.
+var ErrSchemaTooNew = errors.New("database schema is newer than supported")
+
 // Storage provides access to the consolidated SQLite database.
 type Storage struct {
-	db     *sql.DB
-	dbPath string
-	mu     sync.RWMutex
+	db          *sql.DB
+	dbPath      string
+	mu          sync.RWMutex
+	busyTimeout time.Duration
+}
+
+// Option configures a Storage instance.
+type Option func(*Storage)
+
+// WithBusyTimeout sets how long statements wait on locks held by other
+// connections, such as a CLI command writing while the daemon runs.
+func WithBusyTimeout(d time.Duration) Option {
+	return func(s *Storage) {
+		if d > 0 {
+			s.busyTimeout = d
+		}
+	}
 }
 
 // Open creates a new Storage instance with the given database path.
 // It creates the directory structure if needed and runs migrations.
-func Open(ctx context.Context, dbPath string) (*Storage, error) {
+func Open(ctx context.Context, dbPath string, opts ...Option) (*Storage, error) {
+	s := &Storage{
+		dbPath:      dbPath,
+		busyTimeout: DefaultBusyTimeout,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
 	// Ensure directory exists
 	dir := filepath.Dir(dbPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create database directory; %w", err)
 	}
 
-	// Open database
-	db, err := sql.Open("sqlite", dbPath)
+	// Open database. Pragmas are passed in the DSN so they apply to every
+	// connection the pool opens, not just the first.
+	db, err := sql.Open("sqlite", dataSourceName(dbPath, s.busyTimeout))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database; %w", err)
 	}
 
-	// Serialize access to avoid SQLite write contention.
+	// SQLite allows a single writer; one connection serializes access within
+	// the process, and the busy timeout covers other processes.
 	db.SetMaxOpenConns(1)
 	db.SetMaxIdleConns(1)
+	db.SetConnMaxLifetime(0)
 
-	// Configure busy timeout and enable foreign keys/WAL mode for better concurrency
-	if _, err := db.ExecContext(ctx, "PRAGMA busy_timeout = 5000"); err != nil {
-		db.Close()
-		return nil, fmt.Errorf("failed to set busy timeout; %w", err)
-	}
-	if _, err := db.ExecContext(ctx, "PRAGMA foreign_keys = ON"); err != nil {
-		db.Close()
-		return nil, fmt.Errorf("failed to enable foreign keys; %w", err)
-	}
-	if _, err := db.ExecContext(ctx, "PRAGMA journal_mode = WAL"); err != nil {
+	var journalMode string
+	if err := db.QueryRowContext(ctx, "PRAGMA journal_mode").Scan(&journalMode); err != nil {
 		db.Close()
 		return nil, fmt.Errorf("failed to enable WAL mode; %w", err)
 	}
-
-	s := &Storage{
-		db:     db,
-		dbPath: dbPath,
+	if !strings.EqualFold(journalMode, "wal") {
+		// Some filesystems, such as network mounts, cannot host a WAL
+		slog.Warn("sqlite WAL mode unavailable; concurrent writes may fail with database is locked",
+			"path", dbPath,
+			"journal_mode", journalMode)
 	}
 
+	s.db = db
+
 	// Run migrations
 	if err := s.migrate(ctx); err != nil {
 		db.Close()
@@ -68,6 +105,18 @@ func Open(ctx context.Context, dbPath string) (*Storage, error) {
 	return s, nil
 }
 
+// dataSourceName returns the DSN opening dbPath with the busy timeout, foreign
+// keys and WAL journaling. Transactions begin immediately so a writer waits on
+// the busy timeout instead of failing when upgrading a read lock.
+func dataSourceName(dbPath string, busyTimeout time.Duration) string {
+	params := url.Values{}
+	params.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", busyTimeout.Milliseconds()))
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Set("_txlock", "immediate")
+	return dbPath + "?" + params.Encode()
+}
+
 // DB returns the underlying database connection.
 // Use with care; prefer using Storage methods.
 func (s *Storage) DB() *sql.DB {
@@ -106,6 +155,13 @@ func (s *Storage) migrate(ctx context.Context) error {
 		return fmt.Errorf("failed to get current version; %w", err)
 	}
 
+	// Refuse databases migrated by a newer binary; their schema may have
+	// changed in ways this version cannot read or write safely
+	if latest := LatestSchemaVersion(); currentVersion > latest {
+		return fmt.Errorf("database is at schema version %d, this binary supports up to %d; %w",
+			currentVersion, latest, ErrSchemaTooNew)
+	}
+
 	// Run pending migrations
 	for _, m := range migrations {
 		if m.Version <= currentVersion {
@@ -138,6 +194,18 @@ func (s *Storage) runMigration(ctx context.Context, m Migration) error {
 	}
 	defer tx.Rollback()
 
+	// Another process may have applied this migration since the version was
+	// read; transactions begin immediately, so the check holds until commit
+	var applied int
+	if err := tx.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM schema_migrations WHERE version = ?", m.Version,
+	).Scan(&applied); err != nil {
+		return fmt.Errorf("failed to check migration; %w", err)
+	}
+	if applied > 0 {
+		return nil
+	}
+
 	// Execute the migration
 	if _, err := tx.ExecContext(ctx, m.Up); err != nil {
 		return fmt.Errorf("failed to execute migration; %w", err)
@@ -163,6 +231,11 @@ func (s *Storage) GetSchemaVersion(ctx context.Context) (int, error) {
 	return s.getCurrentVersion(ctx)
 }
 
+// LatestSchemaVersion returns the schema version this binary migrates to.
+func LatestSchemaVersion() int {
+	return migrations[len(migrations)-1].Version
+}
+
 // Migration represents a database schema migration.
 type Migration struct {
 	Version     int
@@ -170,7 +243,8 @@ type Migration struct {
 	Up          string
 }
 
-// migrations contains all schema migrations in order.
+// migrations contains all schema migrations in order. Versions must increase;
+// append new migrations rather than editing applied ones.
 var migrations = []Migration{
 	{
 		Version:     1,
@@ -272,4 +346,57 @@ var migrations = []Migration{
 			CREATE INDEX IF NOT EXISTS idx_file_discovery_content_hash ON file_discovery(content_hash);
 		`,
 	},
+	{
+		Version:     6,
+		Description: "Add embeddings_model to file_state",
+		Up: `
+			ALTER TABLE file_state ADD COLUMN embeddings_model TEXT;
+		`,
+	},
+	{
+		Version:     7,
+		Description: "Add chunker_version to file_state",
+		Up: `
+			ALTER TABLE file_state ADD COLUMN chunker_version TEXT;
+		`,
+	},
+	{
+		Version:     8,
+		Description: "Create analysis_queue table",
+		Up: `
+			CREATE TABLE IF NOT EXISTS analysis_queue (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				file_path TEXT NOT NULL,
+				file_size INTEGER NOT NULL,
+				mod_time TIMESTAMP NOT NULL,
+				event_type INTEGER NOT NULL,
+				enqueued_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);
+		`,
+	},
+	{
+		Version:     9,
+		Description: "Create analysis_dead_letters table",
+		Up: `
+			CREATE TABLE IF NOT EXISTS analysis_dead_letters (
+				file_path TEXT PRIMARY KEY,
+				file_size INTEGER NOT NULL,
+				mod_time TIMESTAMP NOT NULL,
+				event_type INTEGER NOT NULL,
+				retries INTEGER NOT NULL,
+				last_error TEXT NOT NULL,
+				failed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);
+		`,
+	},
+	{
+		Version:     10,
+		Description: "Make analysis_queue file_path unique",
+		Up: `
+			DELETE FROM analysis_queue
+			WHERE id NOT IN (SELECT MAX(id) FROM analysis_queue GROUP BY file_path);
+
+			CREATE UNIQUE INDEX IF NOT EXISTS idx_analysis_queue_file_path ON analysis_queue(file_path);
+		`,
+	},
 }
@@ -67,6 +67,43 @@ func TestOpen_CreatesDirectory(t *testing.T) {
 	}
 }
 
+func TestOpen_ConfiguresConnection(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	s, err := Open(ctx, dbPath, WithBusyTimeout(2*time.Second))
+	if err != nil {
+		t.Fatalf("failed to open storage: %v", err)
+	}
+	defer s.Close()
+
+	for _, tt := range []struct {
+		pragma string
+		want   string
+	}{
+		{"journal_mode", "wal"},
+		{"busy_timeout", "2000"},
+		{"foreign_keys", "1"},
+	} {
+		var got string
+		if err := s.DB().QueryRowContext(ctx, "PRAGMA "+tt.pragma).Scan(&got); err != nil {
+			t.Fatalf("failed to read %s: %v", tt.pragma, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.pragma, got, tt.want)
+		}
+	}
+
+	if max := s.DB().Stats().MaxOpenConnections; max != 1 {
+		t.Errorf("MaxOpenConnections = %d, want 1", max)
+	}
+
+	// WAL sidecar files live next to the database
+	if _, err := os.Stat(dbPath + "-wal"); err != nil {
+		t.Errorf("expected WAL file next to database: %v", err)
+	}
+}
+
 func TestMigrations_Idempotent(t *testing.T) {
 	ctx := context.Background()
 	dbPath := filepath.Join(t.TempDir(), "test.db")
@@ -101,6 +138,89 @@ func TestGetSchemaVersion(t *testing.T) {
 	}
 }
 
+func TestMigrations_Ordered(t *testing.T) {
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].Version <= migrations[i-1].Version {
+			t.Errorf("migration %d (%s) does not follow version %d",
+				migrations[i].Version, migrations[i].Description, migrations[i-1].Version)
+		}
+	}
+}
+
+func TestMigrations_UpgradePreservesRows(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	// Create a database at the previous schema version
+	current := migrations
+	migrations = current[:len(current)-1]
+	old, err := Open(ctx, dbPath)
+	migrations = current
+	if err != nil {
+		t.Fatalf("failed to open old storage: %v", err)
+	}
+
+	if err := old.AddPath(ctx, "/project", &PathConfig{SkipHidden: true}); err != nil {
+		t.Fatalf("AddPath failed: %v", err)
+	}
+	if err := old.UpdateMetadataState(ctx, "/project/main.go", "hash", "meta", 42, time.Now()); err != nil {
+		t.Fatalf("UpdateMetadataState failed: %v", err)
+	}
+	old.Close()
+
+	s, err := Open(ctx, dbPath)
+	if err != nil {
+		t.Fatalf("failed to open storage after upgrade: %v", err)
+	}
+	defer s.Close()
+
+	version, err := s.GetSchemaVersion(ctx)
+	if err != nil {
+		t.Fatalf("failed to get schema version: %v", err)
+	}
+	if version != LatestSchemaVersion() {
+		t.Errorf("schema version = %d, want %d", version, LatestSchemaVersion())
+	}
+
+	p, err := s.GetPath(ctx, "/project")
+	if err != nil {
+		t.Fatalf("path lost during migration: %v", err)
+	}
+	if p.Config == nil || !p.Config.SkipHidden {
+		t.Errorf("path config = %+v, want preserved config", p.Config)
+	}
+
+	st, err := s.GetFileState(ctx, "/project/main.go")
+	if err != nil {
+		t.Fatalf("file state lost during migration: %v", err)
+	}
+	if st.ContentHash != "hash" || st.Size != 42 || st.MetadataAnalyzedAt == nil {
+		t.Errorf("file state = %+v, want preserved state", st)
+	}
+}
+
+func TestOpen_RejectsNewerSchema(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	s, err := Open(ctx, dbPath)
+	if err != nil {
+		t.Fatalf("failed to open storage: %v", err)
+	}
+	if _, err := s.DB().ExecContext(ctx,
+		"INSERT INTO schema_migrations (version, description) VALUES (?, ?)",
+		LatestSchemaVersion()+1, "from a newer binary",
+	); err != nil {
+		t.Fatalf("failed to record future migration: %v", err)
+	}
+	s.Close()
+
+	_, err = Open(ctx, dbPath)
+	if !errors.Is(err, ErrSchemaTooNew) {
+		t.Fatalf("expected ErrSchemaTooNew, got %v", err)
+	}
+}
+
 // Remembered paths tests
 
 func TestAddPath(t *testing.T) {
@@ -601,6 +721,35 @@ func TestUpdateSemanticState_Failure(t *testing.T) {
 	}
 }
 
+func TestUpdateChunkerState(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := context.Background()
+	testPath := "/test/file.go"
+
+	if err := s.UpdateMetadataState(ctx, testPath, "hash", "meta", 100, time.Now().Truncate(time.Second)); err != nil {
+		t.Fatalf("failed to setup file state: %v", err)
+	}
+	if err := s.UpdateChunkerState(ctx, testPath, "2"); err != nil {
+		t.Fatalf("failed to update chunker state: %v", err)
+	}
+
+	state, err := s.GetFileState(ctx, testPath)
+	if err != nil {
+		t.Fatalf("failed to get file state: %v", err)
+	}
+	if state.ChunkerVersion != "2" {
+		t.Errorf("expected chunker version 2, got %q", state.ChunkerVersion)
+	}
+
+	if err := s.ClearAnalysisState(ctx, testPath); err != nil {
+		t.Fatalf("failed to clear analysis state: %v", err)
+	}
+	state, _ = s.GetFileState(ctx, testPath)
+	if state.ChunkerVersion != "" {
+		t.Errorf("expected chunker version cleared, got %q", state.ChunkerVersion)
+	}
+}
+
 func TestClearAnalysisState(t *testing.T) {
 	s := newTestStorage(t)
 	ctx := context.Background()
@@ -618,7 +767,7 @@ func TestClearAnalysisState(t *testing.T) {
 		t.Fatalf("failed to setup semantic state: %v", err)
 	}
 
-	err = s.UpdateEmbeddingsState(ctx, testPath, nil)
+	err = s.UpdateEmbeddingsState(ctx, testPath, "", nil)
 	if err != nil {
 		t.Fatalf("failed to setup embeddings state: %v", err)
 	}
@@ -676,7 +825,7 @@ func TestListFilesNeedingAnalysis(t *testing.T) {
 	// Fully analyzed file
 	s.UpdateMetadataState(ctx, "/test/complete.go", "hash4", "meta4", 400, modTime)
 	s.UpdateSemanticState(ctx, "/test/complete.go", "1.0.0", nil)
-	s.UpdateEmbeddingsState(ctx, "/test/complete.go", nil)
+	s.UpdateEmbeddingsState(ctx, "/test/complete.go", "", nil)
 
 	// Test ListFilesNeedingMetadata
 	needsMetadata, err := s.ListFilesNeedingMetadata(ctx, "/test")
@@ -708,6 +857,74 @@ func TestListFilesNeedingAnalysis(t *testing.T) {
 
 // PathConfig tests
 
+func TestCountByAnalysisStatus(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := context.Background()
+	modTime := time.Now()
+
+	counts, err := s.CountByAnalysisStatus(ctx)
+	if err != nil {
+		t.Fatalf("CountByAnalysisStatus failed: %v", err)
+	}
+	if counts.Total != 0 || counts.OldestPendingMetadataAt != nil {
+		t.Errorf("expected empty counts, got %+v", counts)
+	}
+
+	// Two files pending metadata, across different remembered paths
+	for _, path := range []string{"/a/new1.go", "/b/new2.go"} {
+		if err := s.UpdateFileState(ctx, &FileState{Path: path, ContentHash: "h", ModTime: modTime}); err != nil {
+			t.Fatalf("UpdateFileState failed: %v", err)
+		}
+	}
+
+	// Four files through metadata: one pending semantic, one errored semantic,
+	// one complete through embeddings and one errored embeddings
+	for _, path := range []string{"/a/meta.go", "/a/semfail.go", "/b/done.go", "/b/embfail.go"} {
+		if err := s.UpdateMetadataState(ctx, path, "h", "m", 10, modTime); err != nil {
+			t.Fatalf("UpdateMetadataState failed: %v", err)
+		}
+	}
+	if err := s.UpdateSemanticState(ctx, "/a/semfail.go", "v1", errors.New("rate limited")); err != nil {
+		t.Fatalf("UpdateSemanticState failed: %v", err)
+	}
+	for _, path := range []string{"/b/done.go", "/b/embfail.go"} {
+		if err := s.UpdateSemanticState(ctx, path, "v1", nil); err != nil {
+			t.Fatalf("UpdateSemanticState failed: %v", err)
+		}
+	}
+	if err := s.UpdateEmbeddingsState(ctx, "/b/done.go", "model", nil); err != nil {
+		t.Fatalf("UpdateEmbeddingsState failed: %v", err)
+	}
+	if err := s.UpdateEmbeddingsState(ctx, "/b/embfail.go", "", errors.New("timeout")); err != nil {
+		t.Fatalf("UpdateEmbeddingsState failed: %v", err)
+	}
+
+	counts, err = s.CountByAnalysisStatus(ctx)
+	if err != nil {
+		t.Fatalf("CountByAnalysisStatus failed: %v", err)
+	}
+
+	if counts.Total != 6 {
+		t.Errorf("Total = %d, want 6", counts.Total)
+	}
+	if want := (StageStatusCounts{Pending: 2, Completed: 4}); counts.Metadata != want {
+		t.Errorf("Metadata = %+v, want %+v", counts.Metadata, want)
+	}
+	if want := (StageStatusCounts{Pending: 1, Completed: 2, Errored: 1}); counts.Semantic != want {
+		t.Errorf("Semantic = %+v, want %+v", counts.Semantic, want)
+	}
+	if want := (StageStatusCounts{Pending: 0, Completed: 1, Errored: 1}); counts.Embeddings != want {
+		t.Errorf("Embeddings = %+v, want %+v", counts.Embeddings, want)
+	}
+
+	if counts.OldestPendingMetadataAt == nil {
+		t.Fatal("expected OldestPendingMetadataAt to be set")
+	}
+	if age := time.Since(*counts.OldestPendingMetadataAt); age < 0 || age > time.Hour {
+		t.Errorf("OldestPendingMetadataAt = %v, want a recent time", counts.OldestPendingMetadataAt)
+	}
+}
+
 func TestPathConfig_JSON(t *testing.T) {
 	config := &PathConfig{
 		SkipExtensions:     []string{".exe", ".dll"},
@@ -920,6 +1137,66 @@ func TestFileState_IsStale(t *testing.T) {
 	}
 }
 
+func TestFileState_IsStaleWithin(t *testing.T) {
+	modTime := time.Now()
+	state := &FileState{
+		Size:    1024,
+		ModTime: modTime,
+	}
+
+	tests := []struct {
+		name      string
+		size      int64
+		modTime   time.Time
+		tolerance time.Duration
+		want      bool
+	}{
+		{
+			name:      "mod time shift within tolerance",
+			size:      1024,
+			modTime:   modTime.Add(1500 * time.Millisecond),
+			tolerance: 2 * time.Second,
+			want:      false,
+		},
+		{
+			name:      "earlier mod time within tolerance",
+			size:      1024,
+			modTime:   modTime.Add(-time.Second),
+			tolerance: 2 * time.Second,
+			want:      false,
+		},
+		{
+			name:      "mod time shift beyond tolerance",
+			size:      1024,
+			modTime:   modTime.Add(3 * time.Second),
+			tolerance: 2 * time.Second,
+			want:      true,
+		},
+		{
+			name:      "size change within tolerance",
+			size:      2048,
+			modTime:   modTime.Add(time.Second),
+			tolerance: 2 * time.Second,
+			want:      true,
+		},
+		{
+			name:      "zero tolerance requires exact match",
+			size:      1024,
+			modTime:   modTime.Add(time.Millisecond),
+			tolerance: 0,
+			want:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := state.IsStaleWithin(tt.size, tt.modTime, tt.tolerance); got != tt.want {
+				t.Errorf("IsStaleWithin() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestFileState_NeedsAnalysis(t *testing.T) {
 	now := time.Now()
 
@@ -966,6 +1243,28 @@ func TestFileState_NeedsAnalysis(t *testing.T) {
 	}
 }
 
+func TestFileState_NeedsRechunk(t *testing.T) {
+	tests := []struct {
+		name           string
+		version        string
+		currentVersion string
+		want           bool
+	}{
+		{"never recorded", "", "2", true},
+		{"same version", "2", "2", false},
+		{"older version", "1", "2", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &FileState{ChunkerVersion: tt.version}
+			if got := state.NeedsRechunk(tt.currentVersion); got != tt.want {
+				t.Errorf("NeedsRechunk() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 // QueueStats tests
 
 func TestQueueStats_Total(t *testing.T) {
@@ -31,7 +31,7 @@ func (f *Filter) ShouldProcessFile(path string) bool {
 	}
 
 	// Check skip rules
-	if f.isFileSkipped(name, ext) {
+	if f.isFileSkipped(path, name, ext) {
 		return false
 	}
 
@@ -53,7 +53,7 @@ func (f *Filter) ShouldProcessDir(path string) bool {
 	}
 
 	// Check skip rules
-	if f.isDirSkipped(name) {
+	if f.isDirSkipped(path, name) {
 		return false
 	}
 
@@ -61,9 +61,9 @@ func (f *Filter) ShouldProcessDir(path string) bool {
 }
 
 // isFileSkipped checks if a file matches skip rules.
-func (f *Filter) isFileSkipped(name, ext string) bool {
-	// Check hidden files
-	if f.config.SkipHidden && strings.HasPrefix(name, ".") {
+func (f *Filter) isFileSkipped(path, name, ext string) bool {
+	// Check hidden files, honoring hidden include globs
+	if f.config.SkipsHidden(path, false) {
 		return true
 	}
 
@@ -104,9 +104,9 @@ func (f *Filter) isFileIncluded(name, ext string) bool {
 }
 
 // isDirSkipped checks if a directory matches skip rules.
-func (f *Filter) isDirSkipped(name string) bool {
-	// Check hidden directories
-	if f.config.SkipHidden && strings.HasPrefix(name, ".") {
+func (f *Filter) isDirSkipped(path, name string) bool {
+	// Check hidden directories, honoring hidden include globs
+	if f.config.SkipsHidden(path, true) {
 		return true
 	}
 
@@ -73,6 +73,24 @@ func TestFilter_ShouldProcessFile(t *testing.T) {
 			path: "/test/visible.txt",
 			want: true,
 		},
+		{
+			name: "allow hidden file matching include hidden glob",
+			config: &registry.PathConfig{
+				SkipHidden:         true,
+				IncludeHiddenGlobs: []string{".github/workflows/*.yml"},
+			},
+			path: "/test/.github/workflows/ci.yml",
+			want: true,
+		},
+		{
+			name: "skip file in re-included hidden directory without glob match",
+			config: &registry.PathConfig{
+				SkipHidden:         true,
+				IncludeHiddenGlobs: []string{".github/workflows/*.yml"},
+			},
+			path: "/test/.github/CODEOWNERS",
+			want: false,
+		},
 		{
 			name: "allow hidden file when skip hidden false",
 			config: &registry.PathConfig{
@@ -195,6 +213,15 @@ func TestFilter_ShouldProcessDir(t *testing.T) {
 			path: "/test/.git",
 			want: false,
 		},
+		{
+			name: "allow hidden directory leading to include hidden glob",
+			config: &registry.PathConfig{
+				SkipHidden:         true,
+				IncludeHiddenGlobs: []string{".github/workflows/*.yml"},
+			},
+			path: "/test/.github",
+			want: true,
+		},
 		{
 			name: "allow non-hidden directory when skip hidden",
 			config: &registry.PathConfig{
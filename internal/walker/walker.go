@@ -10,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/leefowlercu/agentic-memorizer/internal/chunkers"
 	"github.com/leefowlercu/agentic-memorizer/internal/events"
 	"github.com/leefowlercu/agentic-memorizer/internal/fsutil"
 	"github.com/leefowlercu/agentic-memorizer/internal/registry"
@@ -32,9 +33,23 @@ type Walker interface {
 	// Stats returns current walker statistics.
 	Stats() WalkerStats
 
-	// DrainDiscoveredPaths returns paths discovered during the last walk and clears the set.
+	// DrainWalkResult returns the result of the last walk and clears it.
 	// Returns nil if no walk has occurred. Used for reconciliation against file_state.
-	DrainDiscoveredPaths() map[string]struct{}
+	DrainWalkResult() *WalkResult
+}
+
+// WalkResult contains the paths discovered by a walk of all remembered paths.
+// Reconciliation must only delete entries missing from Paths when Complete is
+// true; a partial walk does not prove that undiscovered files are gone.
+type WalkResult struct {
+	// Paths is the set of discovered file paths.
+	Paths map[string]struct{}
+
+	// Complete is true if every remembered path was walked without error.
+	Complete bool
+
+	// Err is the error that left the walk incomplete, if any.
+	Err error
 }
 
 // WalkerStats contains statistics about walker activity.
@@ -72,6 +87,23 @@ func WithBatchSize(size int) WalkerOption {
 	}
 }
 
+// WithModTimeTolerance sets how far a file's mod time may drift from its recorded
+// state before an incremental walk treats it as changed. Files within tolerance are
+// re-hashed and compared by content hash. Zero requires an exact match.
+func WithModTimeTolerance(d time.Duration) WalkerOption {
+	return func(w *walker) {
+		w.modTimeTolerance = d
+	}
+}
+
+// WithChunkerVersion sets the chunker version compared against each file's recorded
+// chunker version. Files chunked by a different version are treated as changed.
+func WithChunkerVersion(version string) WalkerOption {
+	return func(w *walker) {
+		w.chunkerVersion = version
+	}
+}
+
 // walker implements the Walker interface.
 type walker struct {
 	registry registry.Registry
@@ -80,11 +112,15 @@ type walker struct {
 	paceInterval time.Duration
 	batchSize    int
 
-	semanticEnabled bool
+	semanticEnabled  bool
+	modTimeTolerance time.Duration
+	chunkerVersion   string
 
 	mu              sync.RWMutex
 	stats           WalkerStats
 	discoveredPaths map[string]struct{}
+	walkComplete    bool
+	walkErr         error
 }
 
 // New creates a new Walker with the given dependencies.
@@ -95,6 +131,7 @@ func New(reg registry.Registry, bus events.Bus, opts ...WalkerOption) Walker {
 		paceInterval:    0,
 		batchSize:       100,
 		semanticEnabled: true,
+		chunkerVersion:  chunkers.Version,
 	}
 
 	for _, opt := range opts {
@@ -104,9 +141,11 @@ func New(reg registry.Registry, bus events.Bus, opts ...WalkerOption) Walker {
 	return w
 }
 
-// Walk performs a full walk of the specified path.
+// Walk performs a full walk of the specified path. Files it discovers are
+// analyzed ahead of background walks, since a single-path walk is requested
+// when a user remembers the path.
 func (w *walker) Walk(ctx context.Context, path string) error {
-	return w.walkPath(ctx, path, false)
+	return w.walkPath(ctx, path, false, events.FilePriorityExplicit)
 }
 
 // WalkAll walks all remembered paths.
@@ -139,16 +178,19 @@ func (w *walker) WalkAll(ctx context.Context) (err error) {
 	rememberedPaths = len(paths)
 
 	// Initialize discovered paths map for reconciliation
-	w.mu.Lock()
-	w.discoveredPaths = make(map[string]struct{})
-	w.mu.Unlock()
+	w.resetWalkResult()
+	defer func() {
+		if err != nil {
+			w.markIncomplete(err)
+		}
+	}()
 
 	for _, rp := range paths {
 		if err := ctx.Err(); err != nil {
 			return err
 		}
 
-		if err := w.Walk(ctx, rp.Path); err != nil {
+		if err := w.walkPath(ctx, rp.Path, false, 0); err != nil {
 			return fmt.Errorf("failed to walk %s; %w", rp.Path, err)
 		}
 	}
@@ -186,9 +228,12 @@ func (w *walker) WalkAllIncremental(ctx context.Context) (err error) {
 	rememberedPaths = len(paths)
 
 	// Initialize discovered paths map for reconciliation
-	w.mu.Lock()
-	w.discoveredPaths = make(map[string]struct{})
-	w.mu.Unlock()
+	w.resetWalkResult()
+	defer func() {
+		if err != nil {
+			w.markIncomplete(err)
+		}
+	}()
 
 	for _, rp := range paths {
 		if err := ctx.Err(); err != nil {
@@ -205,7 +250,7 @@ func (w *walker) WalkAllIncremental(ctx context.Context) (err error) {
 
 // WalkIncremental walks a path but only processes files that have changed.
 func (w *walker) WalkIncremental(ctx context.Context, path string) error {
-	return w.walkPath(ctx, path, true)
+	return w.walkPath(ctx, path, true, 0)
 }
 
 // Stats returns current walker statistics.
@@ -215,18 +260,49 @@ func (w *walker) Stats() WalkerStats {
 	return w.stats
 }
 
-// DrainDiscoveredPaths returns paths discovered during the last walk and clears the set.
+// DrainWalkResult returns the result of the last walk and clears it.
 // Returns nil if no walk has occurred. Used for reconciliation against file_state.
-func (w *walker) DrainDiscoveredPaths() map[string]struct{} {
+func (w *walker) DrainWalkResult() *WalkResult {
 	w.mu.Lock()
 	defer w.mu.Unlock()
-	paths := w.discoveredPaths
+	if w.discoveredPaths == nil {
+		return nil
+	}
+	result := &WalkResult{
+		Paths:    w.discoveredPaths,
+		Complete: w.walkComplete,
+		Err:      w.walkErr,
+	}
 	w.discoveredPaths = nil
-	return paths
+	w.walkComplete = false
+	w.walkErr = nil
+	return result
+}
+
+// resetWalkResult starts tracking a new walk result.
+func (w *walker) resetWalkResult() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.discoveredPaths = make(map[string]struct{})
+	w.walkComplete = true
+	w.walkErr = nil
+}
+
+// markIncomplete records that the current walk missed files that may exist.
+func (w *walker) markIncomplete(err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.discoveredPaths == nil {
+		return
+	}
+	w.walkComplete = false
+	if w.walkErr == nil {
+		w.walkErr = err
+	}
 }
 
 // walkPath performs the actual directory walk.
-func (w *walker) walkPath(ctx context.Context, path string, incremental bool) error {
+func (w *walker) walkPath(ctx context.Context, path string, incremental bool, priority int) error {
 	slog.Info("walker: starting walk", "path", path, "incremental", incremental)
 	startTime := time.Now()
 
@@ -339,7 +415,10 @@ func (w *walker) walkPath(ctx context.Context, path string, incremental bool) er
 		// Get file info
 		info, err := d.Info()
 		if err != nil {
-			return nil //nolint:nilerr // Skip files we can't stat
+			// The file may still exist, so the walk can no longer vouch for
+			// every path under the root
+			w.markIncomplete(fmt.Errorf("failed to stat %s; %w", filePath, err))
+			return nil
 		}
 
 		// Track discovered path for reconciliation
@@ -378,7 +457,14 @@ func (w *walker) walkPath(ctx context.Context, path string, incremental bool) er
 
 		// Publish file discovered event
 		slog.Debug("walker: discovered file", "path", filePath, "size", info.Size())
-		event := events.NewFileDiscovered(filePath, contentHash, info.Size(), info.ModTime(), !incremental)
+		event := events.NewEvent(events.FileDiscovered, &events.FileEvent{
+			Path:        filePath,
+			ContentHash: contentHash,
+			Size:        info.Size(),
+			ModTime:     info.ModTime(),
+			IsNew:       !incremental,
+			Priority:    priority,
+		})
 		if err := w.bus.Publish(ctx, event); err != nil {
 			return fmt.Errorf("failed to publish event; %w", err)
 		}
@@ -413,15 +499,28 @@ func (w *walker) hasFileChanged(ctx context.Context, path string, info fs.FileIn
 	}
 
 	// Check mod time and size first (quick check)
-	if state.ModTime.Equal(info.ModTime()) && state.Size == info.Size() {
-		if w.semanticEnabled && state.SemanticAnalyzedAt == nil {
-			return true, nil
+	if state.IsStaleWithin(info.Size(), info.ModTime(), w.modTimeTolerance) {
+		// File metadata changed, likely content changed too
+		return true, nil
+	}
+
+	// Mod time shifted within tolerance; the content hash decides
+	if !state.ModTime.Equal(info.ModTime()) {
+		contentHash, err := fsutil.HashFile(path)
+		if err != nil || contentHash != state.ContentHash {
+			return true, nil //nolint:nilerr // Unhashable files are treated as changed
 		}
-		return false, nil
 	}
 
-	// File metadata changed, likely content changed too
-	return true, nil
+	if w.semanticEnabled && state.SemanticAnalyzedAt == nil {
+		return true, nil
+	}
+
+	// Chunks from an older chunker version are rebuilt even when content is unchanged
+	if state.NeedsRechunk(w.chunkerVersion) {
+		return true, nil
+	}
+	return false, nil
 }
 
 func diffInt64(a, b int64) int64 {
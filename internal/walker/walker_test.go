@@ -3,6 +3,7 @@ package walker
 import (
 	"context"
 	"errors"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -10,7 +11,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/leefowlercu/agentic-memorizer/internal/chunkers"
 	"github.com/leefowlercu/agentic-memorizer/internal/events"
+	"github.com/leefowlercu/agentic-memorizer/internal/fsutil"
 	"github.com/leefowlercu/agentic-memorizer/internal/registry"
 )
 
@@ -220,7 +223,11 @@ func (r *mockRegistry) UpdateSemanticState(ctx context.Context, path string, ana
 	return nil
 }
 
-func (r *mockRegistry) UpdateEmbeddingsState(ctx context.Context, path string, err error) error {
+func (r *mockRegistry) UpdateEmbeddingsState(ctx context.Context, path string, model string, err error) error {
+	return nil
+}
+
+func (r *mockRegistry) UpdateChunkerState(ctx context.Context, path string, chunkerVersion string) error {
 	return nil
 }
 
@@ -240,6 +247,50 @@ func (r *mockRegistry) ListFilesNeedingEmbeddings(ctx context.Context, parentPat
 	return nil, nil
 }
 
+func (r *mockRegistry) ListFilesWithStaleEmbeddings(ctx context.Context, parentPath string, currentModel string) ([]registry.FileState, error) {
+	return nil, nil
+}
+
+func (r *mockRegistry) CountByAnalysisStatus(ctx context.Context) (registry.AnalysisStatusCounts, error) {
+	return registry.AnalysisStatusCounts{}, nil
+}
+
+func (r *mockRegistry) EnqueueWorkItem(ctx context.Context, item *registry.PendingWorkItem) error {
+	return nil
+}
+
+func (r *mockRegistry) CompleteWorkItem(ctx context.Context, id int64, enqueuedAt time.Time) error {
+	return nil
+}
+
+func (r *mockRegistry) ListPendingWorkItems(ctx context.Context) ([]registry.PendingWorkItem, error) {
+	return nil, nil
+}
+
+func (r *mockRegistry) RecordDeadLetter(ctx context.Context, dl *registry.DeadLetter) error {
+	return nil
+}
+
+func (r *mockRegistry) GetDeadLetter(ctx context.Context, filePath string) (*registry.DeadLetter, error) {
+	return nil, registry.ErrPathNotFound
+}
+
+func (r *mockRegistry) ListDeadLetters(ctx context.Context) ([]registry.DeadLetter, error) {
+	return nil, nil
+}
+
+func (r *mockRegistry) DeleteDeadLetter(ctx context.Context, filePath string) error {
+	return nil
+}
+
+func (r *mockRegistry) Export(ctx context.Context, w io.Writer) error {
+	return nil
+}
+
+func (r *mockRegistry) Import(ctx context.Context, rd io.Reader) error {
+	return nil
+}
+
 func (r *mockRegistry) Close() error {
 	return nil
 }
@@ -534,6 +585,7 @@ func TestWalker_WalkIncremental(t *testing.T) {
 		Size:              mainInfo.Size(),
 		ModTime:            mainInfo.ModTime(),
 		SemanticAnalyzedAt: &semanticAt,
+		ChunkerVersion:     chunkers.Version,
 	})
 
 	w := New(reg, bus)
@@ -555,6 +607,117 @@ func TestWalker_WalkIncremental(t *testing.T) {
 	}
 }
 
+func TestWalker_WalkIncremental_ModTimeTolerance(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	createTestFiles(t, tmpDir, map[string]string{
+		"same.go":    "package same",
+		"edited.go":  "package edit",
+		"resized.go": "package resized",
+	})
+
+	reg := newMockRegistry()
+	bus := newMockBus()
+	_ = reg.AddPath(context.Background(), tmpDir, &registry.PathConfig{})
+
+	semanticAt := time.Now()
+	record := func(name, recordedContent string, sizeDelta int64) {
+		path := filepath.Join(tmpDir, name)
+		info, _ := os.Stat(path)
+		_ = reg.UpdateFileState(context.Background(), &registry.FileState{
+			Path:               path,
+			ContentHash:        fsutil.HashBytes([]byte(recordedContent)),
+			Size:               info.Size() + sizeDelta,
+			ModTime:            info.ModTime().Add(-time.Second),
+			SemanticAnalyzedAt: &semanticAt,
+			ChunkerVersion:     chunkers.Version,
+		})
+	}
+	record("same.go", "package same", 0)
+	record("edited.go", "package orig", 0) // same size, different content
+	record("resized.go", "package resized", -1)
+
+	w := New(reg, bus, WithModTimeTolerance(2*time.Second))
+	if err := w.WalkIncremental(context.Background(), tmpDir); err != nil {
+		t.Fatalf("WalkIncremental failed: %v", err)
+	}
+
+	discovered := make(map[string]bool)
+	for _, e := range bus.Events() {
+		if fe, ok := e.Payload.(*events.FileEvent); ok {
+			discovered[filepath.Base(fe.Path)] = true
+		}
+	}
+
+	if discovered["same.go"] {
+		t.Error("expected mod time shift within tolerance to be unchanged")
+	}
+	if !discovered["edited.go"] {
+		t.Error("expected content hash change to be discovered")
+	}
+	if !discovered["resized.go"] {
+		t.Error("expected size change to be discovered")
+	}
+	if stats := w.Stats(); stats.FilesUnchanged != 1 {
+		t.Errorf("expected 1 file unchanged, got %d", stats.FilesUnchanged)
+	}
+}
+
+func TestWalker_WalkIncremental_ChunkerVersion(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	createTestFiles(t, tmpDir, map[string]string{
+		"current.go":    "package current",
+		"older.go":      "package older",
+		"unrecorded.go": "package unrecorded",
+	})
+
+	reg := newMockRegistry()
+	bus := newMockBus()
+	_ = reg.AddPath(context.Background(), tmpDir, &registry.PathConfig{})
+
+	semanticAt := time.Now()
+	record := func(name, chunkerVersion string) {
+		path := filepath.Join(tmpDir, name)
+		info, _ := os.Stat(path)
+		_ = reg.UpdateFileState(context.Background(), &registry.FileState{
+			Path:               path,
+			Size:               info.Size(),
+			ModTime:            info.ModTime(),
+			SemanticAnalyzedAt: &semanticAt,
+			ChunkerVersion:     chunkerVersion,
+		})
+	}
+	record("current.go", "2")
+	record("older.go", "1")
+	record("unrecorded.go", "")
+
+	w := New(reg, bus, WithChunkerVersion("2"))
+	if err := w.WalkIncremental(context.Background(), tmpDir); err != nil {
+		t.Fatalf("WalkIncremental failed: %v", err)
+	}
+
+	discovered := make(map[string]bool)
+	for _, e := range bus.Events() {
+		if fe, ok := e.Payload.(*events.FileEvent); ok {
+			discovered[filepath.Base(fe.Path)] = true
+		}
+	}
+
+	if discovered["current.go"] {
+		t.Error("expected same content and chunker version to be skipped")
+	}
+	if !discovered["older.go"] {
+		t.Error("expected older chunker version to be rediscovered for re-chunking")
+	}
+	if !discovered["unrecorded.go"] {
+		t.Error("expected file without a recorded chunker version to be rediscovered for re-chunking")
+	}
+	if stats := w.Stats(); stats.FilesUnchanged != 1 {
+		t.Errorf("expected 1 file unchanged, got %d", stats.FilesUnchanged)
+	}
+}
+
 func TestWalker_WalkAll(t *testing.T) {
 	tmpDir1 := t.TempDir()
 	tmpDir2 := t.TempDir()
@@ -608,6 +771,7 @@ func TestWalker_WalkAllIncremental(t *testing.T) {
 		Size:              aInfo.Size(),
 		ModTime:            aInfo.ModTime(),
 		SemanticAnalyzedAt: &semanticAt,
+		ChunkerVersion:     chunkers.Version,
 	})
 
 	cInfo, _ := os.Stat(filepath.Join(tmpDir2, "c.go"))
@@ -616,6 +780,7 @@ func TestWalker_WalkAllIncremental(t *testing.T) {
 		Size:              cInfo.Size(),
 		ModTime:            cInfo.ModTime(),
 		SemanticAnalyzedAt: &semanticAt,
+		ChunkerVersion:     chunkers.Version,
 	})
 
 	w := New(reg, bus)
@@ -783,9 +948,8 @@ func TestWalker_DiscoveredPaths_Accumulation(t *testing.T) {
 	w := New(reg, bus)
 
 	// Before walk, drain should return nil
-	paths := w.DrainDiscoveredPaths()
-	if paths != nil {
-		t.Error("expected nil paths before walk")
+	if result := w.DrainWalkResult(); result != nil {
+		t.Error("expected nil result before walk")
 	}
 
 	// Walk all paths
@@ -795,10 +959,14 @@ func TestWalker_DiscoveredPaths_Accumulation(t *testing.T) {
 	}
 
 	// Drain should return all discovered paths
-	paths = w.DrainDiscoveredPaths()
-	if paths == nil {
-		t.Fatal("expected non-nil paths after walk")
+	result := w.DrainWalkResult()
+	if result == nil {
+		t.Fatal("expected non-nil result after walk")
 	}
+	if !result.Complete || result.Err != nil {
+		t.Errorf("expected complete walk, got complete=%v err=%v", result.Complete, result.Err)
+	}
+	paths := result.Paths
 
 	// Should have discovered 3 files
 	if len(paths) != 3 {
@@ -837,18 +1005,47 @@ func TestWalker_DiscoveredPaths_Drain(t *testing.T) {
 	}
 
 	// First drain returns paths
-	paths1 := w.DrainDiscoveredPaths()
-	if paths1 == nil {
-		t.Fatal("expected non-nil paths on first drain")
+	result1 := w.DrainWalkResult()
+	if result1 == nil {
+		t.Fatal("expected non-nil result on first drain")
 	}
-	if len(paths1) != 1 {
-		t.Errorf("expected 1 path, got %d", len(paths1))
+	if len(result1.Paths) != 1 {
+		t.Errorf("expected 1 path, got %d", len(result1.Paths))
 	}
 
 	// Second drain returns nil (already drained)
-	paths2 := w.DrainDiscoveredPaths()
-	if paths2 != nil {
-		t.Error("expected nil paths on second drain")
+	if result2 := w.DrainWalkResult(); result2 != nil {
+		t.Error("expected nil result on second drain")
+	}
+}
+
+func TestWalker_WalkResult_IncompleteOnError(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	createTestFiles(t, tmpDir, map[string]string{
+		"main.go": "package main",
+	})
+
+	reg := newMockRegistry()
+	bus := newMockBus()
+	_ = reg.AddPath(context.Background(), tmpDir, &registry.PathConfig{})
+	_ = reg.AddPath(context.Background(), filepath.Join(tmpDir, "missing"), &registry.PathConfig{})
+
+	w := New(reg, bus)
+
+	if err := w.WalkAll(context.Background()); err == nil {
+		t.Fatal("expected WalkAll to fail for missing remembered path")
+	}
+
+	result := w.DrainWalkResult()
+	if result == nil {
+		t.Fatal("expected non-nil result after failed walk")
+	}
+	if result.Complete {
+		t.Error("expected incomplete walk result")
+	}
+	if result.Err == nil {
+		t.Error("expected walk result to carry the walk error")
 	}
 }
 
@@ -873,6 +1070,7 @@ func TestWalker_DiscoveredPaths_IncrementalTracksUnchanged(t *testing.T) {
 		Size:              unchangedInfo.Size(),
 		ModTime:            unchangedInfo.ModTime(),
 		SemanticAnalyzedAt: &semanticAt,
+		ChunkerVersion:     chunkers.Version,
 	})
 
 	w := New(reg, bus)
@@ -884,10 +1082,11 @@ func TestWalker_DiscoveredPaths_IncrementalTracksUnchanged(t *testing.T) {
 	}
 
 	// Both files should be discovered (even unchanged one)
-	paths := w.DrainDiscoveredPaths()
-	if paths == nil {
-		t.Fatal("expected non-nil paths after walk")
+	result := w.DrainWalkResult()
+	if result == nil {
+		t.Fatal("expected non-nil result after walk")
 	}
+	paths := result.Paths
 
 	if len(paths) != 2 {
 		t.Errorf("expected 2 discovered paths, got %d", len(paths))
@@ -910,3 +1109,44 @@ func TestWalker_DiscoveredPaths_IncrementalTracksUnchanged(t *testing.T) {
 		t.Errorf("expected 1 file unchanged, got %d", stats.FilesUnchanged)
 	}
 }
+
+func TestWalker_PriorityHint(t *testing.T) {
+	tmpDir := t.TempDir()
+	createTestFiles(t, tmpDir, map[string]string{"main.go": "package main"})
+
+	walkPriorities := func(t *testing.T, walk func(w Walker) error) []int {
+		t.Helper()
+		reg := newMockRegistry()
+		bus := newMockBus()
+		_ = reg.AddPath(context.Background(), tmpDir, &registry.PathConfig{})
+
+		if err := walk(New(reg, bus)); err != nil {
+			t.Fatalf("walk failed: %v", err)
+		}
+
+		var priorities []int
+		for _, e := range bus.Events() {
+			if fe, ok := e.Payload.(*events.FileEvent); ok {
+				priorities = append(priorities, fe.Priority)
+			}
+		}
+		if len(priorities) != 1 {
+			t.Fatalf("expected 1 file event, got %d", len(priorities))
+		}
+		return priorities
+	}
+
+	t.Run("SinglePathWalkIsExplicit", func(t *testing.T) {
+		got := walkPriorities(t, func(w Walker) error { return w.Walk(context.Background(), tmpDir) })
+		if got[0] != events.FilePriorityExplicit {
+			t.Errorf("Priority = %d, want %d", got[0], events.FilePriorityExplicit)
+		}
+	})
+
+	t.Run("WalkAllIsBackground", func(t *testing.T) {
+		got := walkPriorities(t, func(w Walker) error { return w.WalkAll(context.Background()) })
+		if got[0] != 0 {
+			t.Errorf("Priority = %d, want 0", got[0])
+		}
+	})
+}
@@ -2,6 +2,7 @@ package watcher
 
 import (
 	"context"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -176,7 +177,11 @@ func (r *mockRegistry) UpdateSemanticState(ctx context.Context, path string, ana
 	return nil
 }
 
-func (r *mockRegistry) UpdateEmbeddingsState(ctx context.Context, path string, err error) error {
+func (r *mockRegistry) UpdateEmbeddingsState(ctx context.Context, path string, model string, err error) error {
+	return nil
+}
+
+func (r *mockRegistry) UpdateChunkerState(ctx context.Context, path string, chunkerVersion string) error {
 	return nil
 }
 
@@ -196,6 +201,50 @@ func (r *mockRegistry) ListFilesNeedingEmbeddings(ctx context.Context, parentPat
 	return nil, nil
 }
 
+func (r *mockRegistry) ListFilesWithStaleEmbeddings(ctx context.Context, parentPath string, currentModel string) ([]registry.FileState, error) {
+	return nil, nil
+}
+
+func (r *mockRegistry) CountByAnalysisStatus(ctx context.Context) (registry.AnalysisStatusCounts, error) {
+	return registry.AnalysisStatusCounts{}, nil
+}
+
+func (r *mockRegistry) EnqueueWorkItem(ctx context.Context, item *registry.PendingWorkItem) error {
+	return nil
+}
+
+func (r *mockRegistry) CompleteWorkItem(ctx context.Context, id int64, enqueuedAt time.Time) error {
+	return nil
+}
+
+func (r *mockRegistry) ListPendingWorkItems(ctx context.Context) ([]registry.PendingWorkItem, error) {
+	return nil, nil
+}
+
+func (r *mockRegistry) RecordDeadLetter(ctx context.Context, dl *registry.DeadLetter) error {
+	return nil
+}
+
+func (r *mockRegistry) GetDeadLetter(ctx context.Context, filePath string) (*registry.DeadLetter, error) {
+	return nil, registry.ErrPathNotFound
+}
+
+func (r *mockRegistry) ListDeadLetters(ctx context.Context) ([]registry.DeadLetter, error) {
+	return nil, nil
+}
+
+func (r *mockRegistry) DeleteDeadLetter(ctx context.Context, filePath string) error {
+	return nil
+}
+
+func (r *mockRegistry) Export(ctx context.Context, w io.Writer) error {
+	return nil
+}
+
+func (r *mockRegistry) Import(ctx context.Context, rd io.Reader) error {
+	return nil
+}
+
 func (r *mockRegistry) CheckPathHealth(ctx context.Context) ([]registry.PathStatus, error) {
 	return nil, nil
 }